@@ -127,6 +127,7 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&metav1.Table{},
 		&SearchResults{},
 		&SortableFields{},
+		&ImpactGraph{},
 	)
 	metav1.AddToGroupVersion(scheme, schemeGroupVersion)
 	return nil
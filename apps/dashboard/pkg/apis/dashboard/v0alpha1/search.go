@@ -96,12 +96,32 @@ type DashboardHit struct {
 	Score float64 `json:"score,omitempty"`
 	// Explain the score (if possible)
 	Explain *common.Unstructured `json:"explain,omitzero,omitempty"`
+	// The panels matching the panel filters (only included when requested with panelHits)
+	Panels []PanelHit `json:"panels,omitempty"`
 }
 
 func (DashboardHit) OpenAPIModelName() string {
 	return OpenAPIPrefix + "DashboardHit"
 }
 
+// +k8s:deepcopy-gen=true
+type PanelHit struct {
+	// The panel id (unique within the dashboard)
+	ID int64 `json:"id"`
+	// The panel title
+	Title string `json:"title,omitempty"`
+	// The panel plugin type
+	Type string `json:"type,omitempty"`
+	// The data source UIDs used by the panel
+	DataSources []string `json:"datasource,omitempty"`
+	// The raw query text of the panel targets
+	Queries []string `json:"query,omitempty"`
+}
+
+func (PanelHit) OpenAPIModelName() string {
+	return OpenAPIPrefix + "PanelHit"
+}
+
 type ManagedBy struct {
 	Kind utils.ManagerKind `json:"kind"`
 	ID   string            `json:"id,omitempty"`
@@ -138,3 +158,57 @@ type TermFacet struct {
 func (TermFacet) OpenAPIModelName() string {
 	return OpenAPIPrefix + "TermFacet"
 }
+
+// +k8s:deepcopy-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ImpactGraph struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// The resource the graph was built for
+	Target ImpactNode `json:"target"`
+
+	// Resources that depend on the target, directly or through other resources
+	Dependents []ImpactNode `json:"dependents"`
+
+	// Resources the target depends on
+	Dependencies []ImpactNode `json:"dependencies"`
+
+	// The references between the resources, from the dependent to its dependency
+	Edges []ImpactEdge `json:"edges"`
+}
+
+func (ImpactGraph) OpenAPIModelName() string {
+	return OpenAPIPrefix + "ImpactGraph"
+}
+
+// +k8s:deepcopy-gen=true
+type ImpactNode struct {
+	// The resource kind, eg DataSource, LibraryPanel or Dashboard
+	Kind string `json:"kind"`
+	// The group of the referenced resource (eg, the data source plugin type)
+	Group string `json:"group,omitempty"`
+	// The k8s "name" (eg, grafana UID)
+	Name string `json:"name"`
+	// The display name
+	Title string `json:"title,omitempty"`
+	// The k8s name (eg, grafana UID) for the parent folder
+	Folder string `json:"folder,omitempty"`
+}
+
+func (ImpactNode) OpenAPIModelName() string {
+	return OpenAPIPrefix + "ImpactNode"
+}
+
+// +k8s:deepcopy-gen=true
+type ImpactEdge struct {
+	// The dependent resource in the format {Kind}/{Name}
+	From string `json:"from"`
+	// The referenced resource in the format {Kind}/{Name}
+	To string `json:"to"`
+	// How the resources are related
+	Relation string `json:"relation,omitempty"`
+}
+
+func (ImpactEdge) OpenAPIModelName() string {
+	return OpenAPIPrefix + "ImpactEdge"
+}
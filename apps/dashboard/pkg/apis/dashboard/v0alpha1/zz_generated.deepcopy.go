@@ -89,6 +89,13 @@ func (in *DashboardHit) DeepCopyInto(out *DashboardHit) {
 		in, out := &in.Explain, &out.Explain
 		*out = (*in).DeepCopy()
 	}
+	if in.Panels != nil {
+		in, out := &in.Panels, &out.Panels
+		*out = make([]PanelHit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -165,6 +172,79 @@ func (in *GridPos) DeepCopy() *GridPos {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImpactEdge) DeepCopyInto(out *ImpactEdge) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImpactEdge.
+func (in *ImpactEdge) DeepCopy() *ImpactEdge {
+	if in == nil {
+		return nil
+	}
+	out := new(ImpactEdge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImpactGraph) DeepCopyInto(out *ImpactGraph) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.Target = in.Target
+	if in.Dependents != nil {
+		in, out := &in.Dependents, &out.Dependents
+		*out = make([]ImpactNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]ImpactNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Edges != nil {
+		in, out := &in.Edges, &out.Edges
+		*out = make([]ImpactEdge, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImpactGraph.
+func (in *ImpactGraph) DeepCopy() *ImpactGraph {
+	if in == nil {
+		return nil
+	}
+	out := new(ImpactGraph)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImpactGraph) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImpactNode) DeepCopyInto(out *ImpactNode) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImpactNode.
+func (in *ImpactNode) DeepCopy() *ImpactNode {
+	if in == nil {
+		return nil
+	}
+	out := new(ImpactNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LibraryPanel) DeepCopyInto(out *LibraryPanel) {
 	*out = *in
@@ -290,6 +370,32 @@ func (in *LibraryPanelStatus) DeepCopy() *LibraryPanelStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PanelHit) DeepCopyInto(out *PanelHit) {
+	*out = *in
+	if in.DataSources != nil {
+		in, out := &in.DataSources, &out.DataSources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PanelHit.
+func (in *PanelHit) DeepCopy() *PanelHit {
+	if in == nil {
+		return nil
+	}
+	out := new(PanelHit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SearchResults) DeepCopyInto(out *SearchResults) {
 	*out = *in
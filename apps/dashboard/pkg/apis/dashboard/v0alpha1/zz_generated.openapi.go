@@ -32,11 +32,15 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		DashboardWithAccessInfo{}.OpenAPIModelName():                                               schema_pkg_apis_dashboard_v0alpha1_DashboardWithAccessInfo(ref),
 		FacetResult{}.OpenAPIModelName():                                                           schema_pkg_apis_dashboard_v0alpha1_FacetResult(ref),
 		GridPos{}.OpenAPIModelName():                                                               schema_pkg_apis_dashboard_v0alpha1_GridPos(ref),
+		ImpactEdge{}.OpenAPIModelName():                                                            schema_pkg_apis_dashboard_v0alpha1_ImpactEdge(ref),
+		ImpactGraph{}.OpenAPIModelName():                                                           schema_pkg_apis_dashboard_v0alpha1_ImpactGraph(ref),
+		ImpactNode{}.OpenAPIModelName():                                                            schema_pkg_apis_dashboard_v0alpha1_ImpactNode(ref),
 		LibraryPanel{}.OpenAPIModelName():                                                          schema_pkg_apis_dashboard_v0alpha1_LibraryPanel(ref),
 		LibraryPanelList{}.OpenAPIModelName():                                                      schema_pkg_apis_dashboard_v0alpha1_LibraryPanelList(ref),
 		LibraryPanelSpec{}.OpenAPIModelName():                                                      schema_pkg_apis_dashboard_v0alpha1_LibraryPanelSpec(ref),
 		LibraryPanelStatus{}.OpenAPIModelName():                                                    schema_pkg_apis_dashboard_v0alpha1_LibraryPanelStatus(ref),
 		ManagedBy{}.OpenAPIModelName():                                                             schema_pkg_apis_dashboard_v0alpha1_ManagedBy(ref),
+		PanelHit{}.OpenAPIModelName():                                                              schema_pkg_apis_dashboard_v0alpha1_PanelHit(ref),
 		SearchResults{}.OpenAPIModelName():                                                         schema_pkg_apis_dashboard_v0alpha1_SearchResults(ref),
 		Snapshot{}.OpenAPIModelName():                                                              schema_pkg_apis_dashboard_v0alpha1_Snapshot(ref),
 		"github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard/v0alpha1.SnapshotClient":     schema_pkg_apis_dashboard_v0alpha1_SnapshotClient(ref),
@@ -513,12 +517,26 @@ func schema_pkg_apis_dashboard_v0alpha1_DashboardHit(ref common.ReferenceCallbac
 							Ref:         ref(commonv0alpha1.Unstructured{}.OpenAPIModelName()),
 						},
 					},
+					"panels": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The panels matching the panel filters (only included when requested with panelHits)",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref(PanelHit{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"resource", "name", "title"},
 			},
 		},
 		Dependencies: []string{
-			ManagedBy{}.OpenAPIModelName(), commonv0alpha1.Unstructured{}.OpenAPIModelName()},
+			ManagedBy{}.OpenAPIModelName(), PanelHit{}.OpenAPIModelName(), commonv0alpha1.Unstructured{}.OpenAPIModelName()},
 	}
 }
 
@@ -794,6 +812,170 @@ func schema_pkg_apis_dashboard_v0alpha1_GridPos(ref common.ReferenceCallback) co
 	}
 }
 
+func schema_pkg_apis_dashboard_v0alpha1_ImpactEdge(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"from": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The dependent resource in the format {Kind}/{Name}",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"to": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The referenced resource in the format {Kind}/{Name}",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"relation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How the resources are related",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"from", "to"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_dashboard_v0alpha1_ImpactGraph(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The resource the graph was built for",
+							Default:     map[string]interface{}{},
+							Ref:         ref(ImpactNode{}.OpenAPIModelName()),
+						},
+					},
+					"dependents": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources that depend on the target, directly or through other resources",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref(ImpactNode{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+					"dependencies": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources the target depends on",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref(ImpactNode{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+					"edges": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The references between the resources, from the dependent to its dependency",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref(ImpactEdge{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"target", "dependents", "dependencies", "edges"},
+			},
+		},
+		Dependencies: []string{
+			ImpactEdge{}.OpenAPIModelName(), ImpactNode{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_dashboard_v0alpha1_ImpactNode(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The resource kind, eg DataSource, LibraryPanel or Dashboard",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"group": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The group of the referenced resource (eg, the data source plugin type)",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The k8s \"name\" (eg, grafana UID)",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"title": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The display name",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"folder": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The k8s name (eg, grafana UID) for the parent folder",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"kind", "name"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_dashboard_v0alpha1_LibraryPanel(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -1066,6 +1248,71 @@ func schema_pkg_apis_dashboard_v0alpha1_ManagedBy(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_dashboard_v0alpha1_PanelHit(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"id": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The panel id (unique within the dashboard)",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"title": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The panel title",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The panel plugin type",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"datasource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The data source UIDs used by the panel",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"query": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The raw query text of the panel targets",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"id"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_dashboard_v0alpha1_SearchResults(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -8,10 +8,17 @@ shorturl: {
 		spec: {
 			// The original path to where the short url is linking too e.g. https://localhost:3000/eer8i1kictngga/new-dashboard-with-lib-panel
 			path: string
+			// The unix time after which the short URL stops resolving, unset means it never expires
+			expiresAt?: int64
 		}
 		status: {
 			// The last time the short URL was used, 0 is the initial value
 			lastSeenAt: int64
+			// The total number of redirects through the short URL
+			visitCount?: int64
+			// The number of visits per UTC day, keyed by the unix time of the start of the day.
+			// Only the most recent days are kept.
+			visits?: [string]: int64
 		}
 	}
 	routes: {
@@ -0,0 +1,14 @@
+package v1beta1
+
+import "time"
+
+// IsExpired reports whether a short URL expiry, in unix seconds, has passed.
+// An expiry of 0 means the short URL does not expire.
+func IsExpired(expiresAt int64, now time.Time) bool {
+	return expiresAt > 0 && expiresAt <= now.Unix()
+}
+
+// IsExpired reports whether the short URL has an expiry that has passed.
+func (s ShortURLSpec) IsExpired(now time.Time) bool {
+	return s.ExpiresAt != nil && IsExpired(*s.ExpiresAt, now)
+}
@@ -0,0 +1,36 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsExpired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	past := now.Unix() - 1
+	future := now.Unix() + 1
+	current := now.Unix()
+	zero := int64(0)
+
+	tests := []struct {
+		name      string
+		expiresAt *int64
+		expected  bool
+	}{
+		{name: "no expiry", expiresAt: nil, expected: false},
+		{name: "zero expiry", expiresAt: &zero, expected: false},
+		{name: "expiry in the future", expiresAt: &future, expected: false},
+		{name: "expiry at now", expiresAt: &current, expected: true},
+		{name: "expiry in the past", expiresAt: &past, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, ShortURLSpec{ExpiresAt: tt.expiresAt}.IsExpired(now))
+			if tt.expiresAt != nil {
+				require.Equal(t, tt.expected, IsExpired(*tt.expiresAt, now))
+			}
+		})
+	}
+}
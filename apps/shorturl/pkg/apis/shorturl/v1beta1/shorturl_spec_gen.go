@@ -6,6 +6,8 @@ package v1beta1
 type ShortURLSpec struct {
 	// The original path to where the short url is linking too e.g. https://localhost:3000/eer8i1kictngga/new-dashboard-with-lib-panel
 	Path string `json:"path"`
+	// The unix time after which the short URL stops resolving, unset means it never expires
+	ExpiresAt *int64 `json:"expiresAt,omitempty"`
 }
 
 // NewShortURLSpec creates a new ShortURLSpec object.
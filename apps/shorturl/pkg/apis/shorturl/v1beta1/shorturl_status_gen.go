@@ -29,6 +29,11 @@ func (ShortURLstatusOperatorState) OpenAPIModelName() string {
 type ShortURLStatus struct {
 	// The last time the short URL was used, 0 is the initial value
 	LastSeenAt int64 `json:"lastSeenAt"`
+	// The total number of redirects through the short URL
+	VisitCount *int64 `json:"visitCount,omitempty"`
+	// The number of visits per UTC day, keyed by the unix time of the start of the day.
+	// Only the most recent days are kept.
+	Visits map[string]int64 `json:"visits,omitempty"`
 	// operatorStates is a map of operator ID to operator state evaluations.
 	// Any operator which consumes this kind SHOULD add its state evaluation information to this field.
 	OperatorStates map[string]ShortURLstatusOperatorState `json:"operatorStates,omitempty"`
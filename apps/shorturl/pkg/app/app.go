@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 
@@ -51,6 +52,9 @@ func New(cfg app.Config) (app.App, error) {
 							return fmt.Errorf("expected ShortURL object, got %T", req.Object)
 						}
 
+						if err := validateExpiresAt(shortURL.Spec.ExpiresAt); err != nil {
+							return err
+						}
 						return validateRelativePath(shortURL.Spec.Path)
 					},
 				},
@@ -73,14 +77,25 @@ func New(cfg app.Config) (app.App, error) {
 							return err
 						}
 
+						// Expired short URLs no longer resolve
+						if info.Spec.IsExpired(time.Now()) {
+							w.WriteHeader(http.StatusNotFound)
+							return json.NewEncoder(w).Encode(metav1.Status{
+								Status:  metav1.StatusFailure,
+								Message: "short URL expired",
+								Reason:  metav1.StatusReasonNotFound,
+								Code:    http.StatusNotFound,
+							})
+						}
+
 						// Safety net: validate the stored path before redirecting
 						if err := validateRelativePath(info.Spec.Path); err != nil {
 							return fmt.Errorf("stored short URL has invalid path: %w", err)
 						}
 
-						// Update lastSeenAt in the background
+						// Update lastSeenAt and the visit counts in the background
 						func() { // TODO, this should be async, but keeping sync until we update tests
-							info.Status.LastSeenAt = time.Now().UnixMilli()
+							recordVisit(&info.Status, time.Now())
 							ctx, _, err := identity.WithProvisioningIdentity(context.Background(), req.ResourceIdentifier.Namespace)
 							if err != nil {
 								logging.FromContext(ctx).Warn("unable to create background identity", "err", err)
@@ -9,8 +9,9 @@ import (
 
 // Local error definitions to avoid importing the main shorturls package
 var (
-	ErrShortURLAbsolutePath = fmt.Errorf("path should be relative")
-	ErrShortURLInvalidPath  = fmt.Errorf("invalid short URL path")
+	ErrShortURLAbsolutePath  = fmt.Errorf("path should be relative")
+	ErrShortURLInvalidPath   = fmt.Errorf("invalid short URL path")
+	ErrShortURLInvalidExpiry = fmt.Errorf("expiresAt must be a unix time")
 )
 
 // validateRelativePath checks that a short URL path is a safe relative path
@@ -52,3 +53,12 @@ func validateRelativePath(rawPath string) error {
 
 	return nil
 }
+
+// validateExpiresAt checks that the expiry is unset or a unix time.
+// Expiries in the past are accepted here, so the status of expired short URLs can still be updated.
+func validateExpiresAt(expiresAt *int64) error {
+	if expiresAt != nil && *expiresAt < 0 {
+		return ErrShortURLInvalidExpiry
+	}
+	return nil
+}
@@ -66,3 +66,14 @@ func TestValidateRelativePath(t *testing.T) {
 		require.True(t, errors.Is(err, ErrShortURLInvalidPath))
 	})
 }
+
+func TestValidateExpiresAt(t *testing.T) {
+	negative := int64(-1)
+	zero := int64(0)
+	past := int64(1)
+
+	require.NoError(t, validateExpiresAt(nil))
+	require.NoError(t, validateExpiresAt(&zero))
+	require.NoError(t, validateExpiresAt(&past))
+	require.ErrorIs(t, validateExpiresAt(&negative), ErrShortURLInvalidExpiry)
+}
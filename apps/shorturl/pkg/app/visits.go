@@ -0,0 +1,47 @@
+package app
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	shorturlv1beta1 "github.com/grafana/grafana/apps/shorturl/pkg/apis/shorturl/v1beta1"
+)
+
+// maxVisitDays is the number of daily visit counts kept in the status of a short URL
+const maxVisitDays = 90
+
+// recordVisit counts a visit in the status, in the total and in the bucket of the current UTC day.
+// The buckets older than maxVisitDays are dropped.
+func recordVisit(status *shorturlv1beta1.ShortURLStatus, now time.Time) {
+	status.LastSeenAt = now.UnixMilli()
+
+	count := int64(1)
+	if status.VisitCount != nil {
+		count += *status.VisitCount
+	}
+	status.VisitCount = &count
+
+	if status.Visits == nil {
+		status.Visits = map[string]int64{}
+	}
+	day := now.UTC().Truncate(24 * time.Hour)
+	status.Visits[strconv.FormatInt(day.Unix(), 10)]++
+
+	if len(status.Visits) <= maxVisitDays {
+		return
+	}
+	buckets := make([]int64, 0, len(status.Visits))
+	for k := range status.Visits {
+		bucket, err := strconv.ParseInt(k, 10, 64)
+		if err != nil {
+			delete(status.Visits, k)
+			continue
+		}
+		buckets = append(buckets, bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] > buckets[j] })
+	for _, bucket := range buckets[min(len(buckets), maxVisitDays):] {
+		delete(status.Visits, strconv.FormatInt(bucket, 10))
+	}
+}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	shorturlv1beta1 "github.com/grafana/grafana/apps/shorturl/pkg/apis/shorturl/v1beta1"
+)
+
+func TestRecordVisit(t *testing.T) {
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	bucket := strconv.FormatInt(day.Unix(), 10)
+
+	t.Run("counts the visits per day", func(t *testing.T) {
+		status := &shorturlv1beta1.ShortURLStatus{}
+		recordVisit(status, day.Add(time.Hour))
+		recordVisit(status, day.Add(23*time.Hour))
+		recordVisit(status, day.Add(25*time.Hour))
+
+		require.Equal(t, int64(3), *status.VisitCount)
+		require.Equal(t, map[string]int64{
+			bucket: 2,
+			strconv.FormatInt(day.Add(24*time.Hour).Unix(), 10): 1,
+		}, status.Visits)
+		require.Equal(t, day.Add(25*time.Hour).UnixMilli(), status.LastSeenAt)
+	})
+
+	t.Run("drops the oldest days", func(t *testing.T) {
+		status := &shorturlv1beta1.ShortURLStatus{Visits: map[string]int64{}}
+		for i := 0; i < maxVisitDays; i++ {
+			status.Visits[strconv.FormatInt(day.AddDate(0, 0, -i-1).Unix(), 10)] = 1
+		}
+		recordVisit(status, day)
+
+		require.Len(t, status.Visits, maxVisitDays)
+		require.Equal(t, int64(1), status.Visits[bucket])
+		require.NotContains(t, status.Visits, strconv.FormatInt(day.AddDate(0, 0, -maxVisitDays).Unix(), 10))
+	})
+}
@@ -3,6 +3,8 @@
 export interface Spec {
 	// The original path to where the short url is linking too e.g. https://localhost:3000/eer8i1kictngga/new-dashboard-with-lib-panel
 	path: string;
+	// The unix time after which the short URL stops resolving, unset means it never expires
+	expiresAt?: number;
 }
 
 export const defaultSpec = (): Spec => ({
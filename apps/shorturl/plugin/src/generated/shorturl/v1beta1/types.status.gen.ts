@@ -20,6 +20,11 @@ export const defaultOperatorState = (): OperatorState => ({
 export interface Status {
 	// The last time the short URL was used, 0 is the initial value
 	lastSeenAt: number;
+	// The total number of redirects through the short URL
+	visitCount?: number;
+	// The number of visits per UTC day, keyed by the unix time of the start of the day.
+	// Only the most recent days are kept.
+	visits?: Record<string, number>;
 	// operatorStates is a map of operator ID to operator state evaluations.
 	// Any operator which consumes this kind SHOULD add its state evaluation information to this field.
 	operatorStates?: Record<string, OperatorState>;
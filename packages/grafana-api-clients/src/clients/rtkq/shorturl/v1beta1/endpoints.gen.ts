@@ -489,6 +489,8 @@ export type ObjectMeta = {
   uid?: string;
 };
 export type ShortUrlSpec = {
+  /** The unix time after which the short URL stops resolving, unset means it never expires */
+  expiresAt?: number;
   /** The original path to where the short url is linking too e.g. https://localhost:3000/eer8i1kictngga/new-dashboard-with-lib-panel */
   path: string;
 };
@@ -517,6 +519,13 @@ export type ShortUrlStatus = {
   operatorStates?: {
     [key: string]: ShortUrlOperatorState;
   };
+  /** The total number of redirects through the short URL */
+  visitCount?: number;
+  /** The number of visits per UTC day, keyed by the unix time of the start of the day.
+    Only the most recent days are kept. */
+  visits?: {
+    [key: string]: number;
+  };
 };
 export type ShortUrl = {
   /** APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources */
@@ -920,6 +920,9 @@ export const versionedPages = {
       copyUrlButton: {
         '11.3.0': 'data-testid share snapshot copy url button',
       },
+      refreshSnapshotButton: {
+        '13.0.0': 'data-testid share snapshot refresh button',
+      },
     },
   },
   ExportDashboardDrawer: {
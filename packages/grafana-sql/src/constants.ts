@@ -64,6 +64,8 @@ export const MACRO_NAMES = [
   '$__timeTo',
   '$__timeGroup',
   '$__timeGroupAlias',
+  '$__timeGroupCalendar',
+  '$__timeGroupCalendarAlias',
   '$__unixEpochFilter',
   '$__unixEpochNanoFilter',
   '$__unixEpochNanoFrom',
@@ -1,4 +1,4 @@
-import { lastValueFrom, type Observable, throwError } from 'rxjs';
+import { lastValueFrom, merge, type Observable, throwError } from 'rxjs';
 import { map } from 'rxjs/operators';
 
 import {
@@ -32,9 +32,19 @@ import {
 import { ResponseParser } from '../ResponseParser';
 import { SqlQueryEditorLazy } from '../components/QueryEditorLazy';
 import { MACRO_NAMES } from '../constants';
-import { type DB, type SQLQuery, type SQLOptions, type SqlQueryModel, QueryFormat, type SQLDialect } from '../types';
+import {
+  type DB,
+  type SQLQuery,
+  type SQLOptions,
+  type SQLQueryParameter,
+  type SqlQueryModel,
+  QueryFormat,
+  type SQLDialect,
+} from '../types';
 import migrateAnnotation from '../utils/migration';
 
+import { isStreamingQuery, runStreamingQuery } from './streaming';
+
 export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLOptions> {
   uid: string;
   responseParser: ResponseParser;
@@ -102,7 +112,7 @@ export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLO
         const expandedQuery = {
           ...query,
           datasource: this.getRef(),
-          rawSql: this.templateSrv.replace(query.rawSql, scopedVars, this.interpolateVariable),
+          ...this.interpolateQueryText(query, scopedVars),
           rawQuery: true,
         };
         return expandedQuery;
@@ -119,11 +129,39 @@ export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLO
     return {
       refId: target.refId,
       datasource: this.getRef(),
-      rawSql: this.templateSrv.replace(target.rawSql, scopedVars, this.interpolateVariable),
+      ...this.interpolateQueryText(target, scopedVars),
       format: target.format,
     };
   }
 
+  /**
+   * Parameterized queries are sent as written, with template variables resolved into their
+   * parameters for the backend to bind. Other queries have variables interpolated into the SQL.
+   */
+  private interpolateQueryText(
+    query: SQLQuery,
+    scopedVars: ScopedVars
+  ): Pick<SQLQuery, 'rawSql' | 'parameterized' | 'parameters'> {
+    if (!query.parameterized) {
+      return { rawSql: this.templateSrv.replace(query.rawSql, scopedVars, this.interpolateVariable) };
+    }
+
+    return {
+      rawSql: query.rawSql,
+      parameterized: true,
+      parameters: (query.parameters ?? []).map((p) => this.resolveParameter(p, scopedVars)),
+    };
+  }
+
+  private resolveParameter(parameter: SQLQueryParameter, scopedVars: ScopedVars): SQLQueryParameter {
+    const variable = `$${parameter.variable ?? parameter.name}`;
+    if (!this.templateSrv.containsTemplate(variable)) {
+      return parameter;
+    }
+
+    return { ...parameter, value: JSON.parse(this.templateSrv.replace(variable, scopedVars, 'json')) };
+  }
+
   query(request: DataQueryRequest<SQLQuery>): Observable<DataQueryResponse> {
     // This logic reenables the previous SQL behavior regarding what databases are available for the user to query.
     const databaseIssue = this.checkForDatabaseIssue(request);
@@ -146,7 +184,19 @@ export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLO
       });
     });
 
-    return super.query(request);
+    const streamingTargets = request.targets.filter((t) => !t.hide && isStreamingQuery(t));
+    if (streamingTargets.length === 0) {
+      return super.query(request);
+    }
+
+    const streams = streamingTargets.map((t) =>
+      runStreamingQuery(this.uid, { ...t, ...this.applyTemplateVariables(t, request.scopedVars) }, request)
+    );
+    const targets = request.targets.filter((t) => !isStreamingQuery(t));
+    if (targets.length === 0) {
+      return merge(...streams);
+    }
+    return merge(super.query({ ...request, targets }), ...streams);
   }
 
   private checkForDatabaseIssue(request: DataQueryRequest<SQLQuery>) {
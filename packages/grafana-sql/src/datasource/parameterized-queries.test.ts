@@ -0,0 +1,69 @@
+import { type DataSourceInstanceSettings, type ScopedVars } from '@grafana/data';
+import { type TemplateSrv } from '@grafana/runtime';
+
+import { type DB, type SQLOptions, type SQLQuery, type SqlQueryModel } from '../types';
+
+import { SqlDatasource } from './SqlDatasource';
+
+class TestSqlDatasource extends SqlDatasource {
+  getDB(): DB {
+    return {} as DB;
+  }
+
+  getQueryModel(): SqlQueryModel {
+    return {
+      quoteLiteral: (value: string) => `'${value.replace(/'/g, "''")}'`,
+    } as SqlQueryModel;
+  }
+}
+
+const variables: Record<string, string | string[]> = {
+  host: ['web-1', "o'brien"],
+  limit: '10',
+};
+
+const templateSrv = {
+  containsTemplate: (target?: string) => !!target && target.slice(1) in variables,
+  replace: (target?: string, _scopedVars?: ScopedVars, format?: unknown) => {
+    const name = target?.slice(1) ?? '';
+    if (format === 'json') {
+      return JSON.stringify(variables[name]);
+    }
+    return target?.replace('$host', "'web-1','o''brien'") ?? '';
+  },
+} as unknown as TemplateSrv;
+
+describe('SqlDatasource - parameterized queries', () => {
+  const instanceSettings = { jsonData: {} } as unknown as DataSourceInstanceSettings<SQLOptions>;
+  const ds = new TestSqlDatasource(instanceSettings, templateSrv);
+
+  it('resolves template variables into parameters and leaves the SQL untouched', () => {
+    const query: SQLQuery = {
+      refId: 'A',
+      rawSql: 'SELECT * FROM t WHERE host IN ($__param(host)) LIMIT $__param(n)',
+      parameterized: true,
+      parameters: [
+        { name: 'host', type: 'string' },
+        { name: 'n', type: 'integer', variable: 'limit' },
+        { name: 'fixed', type: 'boolean', value: true },
+      ],
+    };
+
+    const applied = ds.applyTemplateVariables(query, {});
+
+    expect(applied.rawSql).toBe(query.rawSql);
+    expect(applied.parameterized).toBe(true);
+    expect(applied.parameters).toEqual([
+      { name: 'host', type: 'string', value: ['web-1', "o'brien"] },
+      { name: 'n', type: 'integer', variable: 'limit', value: '10' },
+      { name: 'fixed', type: 'boolean', value: true },
+    ]);
+  });
+
+  it('keeps interpolating the SQL of other queries', () => {
+    const applied = ds.applyTemplateVariables({ refId: 'A', rawSql: 'SELECT * FROM t WHERE host IN ($host)' }, {});
+
+    expect(applied.rawSql).toBe("SELECT * FROM t WHERE host IN ('web-1','o''brien')");
+    expect(applied.parameters).toBeUndefined();
+  });
+});
@@ -0,0 +1,95 @@
+import { defer, map, mergeMap, type Observable } from 'rxjs';
+
+import {
+  type DataFrameJSON,
+  type DataQueryRequest,
+  type DataQueryResponse,
+  type LiveChannelEvent,
+  LiveChannelScope,
+  LoadingState,
+  StreamingDataFrame,
+} from '@grafana/data';
+import { getGrafanaLiveSrv } from '@grafana/runtime';
+
+import { type SQLQuery } from '../types';
+
+/**
+ * Streaming queries either poll the query every `pollInterval` seconds, or listen for notifications
+ * on `listenChannel` on data sources that support it.
+ */
+export function isStreamingQuery(query: SQLQuery): boolean {
+  return !!query.listenChannel || (query.pollInterval ?? 0) > 0;
+}
+
+/**
+ * Calculate a unique key for the query. The key picks the channel, so that all the panels running
+ * the same query share a single stream. It is not meant to be secure.
+ */
+export async function getStreamKey(query: SQLQuery): Promise<string> {
+  const str = JSON.stringify({
+    rawSql: query.rawSql,
+    format: query.format,
+    parameters: query.parameters,
+    pollInterval: query.pollInterval,
+    listenChannel: query.listenChannel,
+    timeField: query.timeField,
+  });
+
+  const msgUint8 = new TextEncoder().encode(str);
+  const hashBuffer = await crypto.subtle.digest('SHA-1', msgUint8);
+  const hashArray = Array.from(new Uint8Array(hashBuffer.slice(0, 8)));
+  return hashArray.map((b) => b.toString(16).padStart(2, '0')).join('');
+}
+
+export function runStreamingQuery(
+  uid: string,
+  query: SQLQuery,
+  request: DataQueryRequest<SQLQuery>
+): Observable<DataQueryResponse> {
+  const range = request.range;
+  const maxDelta = range.to.valueOf() - range.from.valueOf() + 1000;
+  const maxLength = Math.max(request.maxDataPoints ?? 1000, 100) * 2;
+  const prefix = query.listenChannel ? 'listen' : 'poll';
+
+  let frame: StreamingDataFrame | undefined = undefined;
+  const updateFrame = (msg: LiveChannelEvent<unknown>) => {
+    if ('message' in msg && msg.message) {
+      const p: DataFrameJSON = msg.message;
+      if (!frame) {
+        frame = StreamingDataFrame.fromDataFrameJSON(p, { maxLength, maxDelta });
+        frame.refId = query.refId;
+      } else {
+        frame.push(p);
+      }
+    }
+    return frame;
+  };
+
+  return defer(() => getStreamKey(query)).pipe(
+    mergeMap((key) =>
+      getGrafanaLiveSrv()
+        .getStream({
+          scope: LiveChannelScope.DataSource,
+          stream: uid,
+          path: `${prefix}/${key}`,
+          data: {
+            ...query,
+            timeRange: {
+              from: range.from.valueOf().toString(),
+              to: range.to.valueOf().toString(),
+            },
+          },
+        })
+        .pipe(
+          map((evt) => {
+            const frame = updateFrame(evt);
+            return {
+              key: query.refId,
+              data: frame ? [frame] : [],
+              state: LoadingState.Streaming,
+            };
+          })
+        )
+    )
+  );
+}
@@ -4,6 +4,8 @@ export type {
   SQLExpression,
   SQLOptions,
   SQLQuery,
+  SQLQueryParameter,
+  SQLQueryParameterType,
   SqlQueryModel,
   SQLSelectableValue,
   SQLDialect,
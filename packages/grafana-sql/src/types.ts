@@ -52,6 +52,20 @@ export enum QueryFormat {
 
 export type SQLQueryMeta = { valueField?: string; textField?: string };
 
+export type SQLQueryParameterType = 'string' | 'integer' | 'number' | 'boolean' | 'time';
+
+/**
+ * A value bound to a parameterized query as a driver parameter. It is referenced in the query
+ * with $__param(name) and takes its value from the template variable with the same name, or from
+ * `variable` when set. Multi-value variables are bound as a list of values.
+ */
+export interface SQLQueryParameter {
+  name: string;
+  type: SQLQueryParameterType;
+  variable?: string;
+  value?: string | number | boolean | null | Array<string | number | boolean>;
+}
+
 export interface SQLQuery extends DataQuery {
   alias?: string;
   format?: QueryFormat;
@@ -62,6 +76,14 @@ export interface SQLQuery extends DataQuery {
   editorMode?: EditorMode;
   rawQuery?: boolean;
   meta?: SQLQueryMeta;
+  parameterized?: boolean;
+  parameters?: SQLQueryParameter[];
+  /** Run the query every `pollInterval` seconds and stream the rows newer than the ones already received */
+  pollInterval?: number;
+  /** Stream the notifications sent to this channel, on data sources supporting LISTEN/NOTIFY */
+  listenChannel?: string;
+  /** Property of the notification payloads holding their time */
+  timeField?: string;
 }
 
 export type SQLVariableQuery = { query: string } & SQLQuery;
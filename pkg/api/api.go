@@ -602,10 +602,13 @@ func (hs *HTTPServer) registerRoutes() {
 
 	// Snapshots
 	r.Get("/api/snapshot/shared-options/", reqSignedIn, hs.GetSharingOptions)
+	r.Get("/api/snapshot/policy", reqOrgAdmin, routing.Wrap(hs.GetSnapshotPolicy))
+	r.Put("/api/snapshot/policy", reqOrgAdmin, routing.Wrap(hs.UpdateSnapshotPolicy))
 
 	r.Post("/api/snapshots/", reqSnapshotPublicModeOrCreate, hs.getCreatedSnapshotHandler())
 	r.Get("/api/snapshots/:key", routing.Wrap(hs.GetDashboardSnapshot))
 	r.Delete("/api/snapshots/:key", authorize(ac.EvalPermission(dashboardsnapshots.ActionSnapshotsDelete)), routing.Wrap(hs.DeleteDashboardSnapshot))
+	r.Post("/api/snapshots/:key/refresh", authorize(ac.EvalPermission(dashboardsnapshots.ActionSnapshotsCreate)), routing.Wrap(hs.RefreshDashboardSnapshot))
 
 	// Snapshots delete for public mode or using the deleteKey
 	r.Get("/api/snapshots-delete/:deleteKey", reqSnapshotPublicModeOrDelete, routing.Wrap(hs.DeleteDashboardSnapshotByDeleteKey))
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/errhttp"
 	"github.com/grafana/grafana/pkg/web"
@@ -219,11 +220,37 @@ func (hs *HTTPServer) DeleteDashboardSnapshot(c *contextmodel.ReqContext) respon
 		return response.Error(http.StatusUnauthorized, "OrgID mismatch", nil)
 	}
 
+	if rsp := hs.checkSnapshotWriteAccess(c, queryResult); rsp != nil {
+		return rsp
+	}
+
+	if queryResult.External {
+		err := dashboardsnapshots.DeleteExternalDashboardSnapshot(queryResult.ExternalDeleteURL)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to delete external dashboard", err)
+		}
+	}
+
+	cmd := &dashboardsnapshots.DeleteDashboardSnapshotCommand{DeleteKey: queryResult.DeleteKey}
+
+	if err := hs.dashboardsnapshotsService.DeleteDashboardSnapshot(c.Req.Context(), cmd); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to delete dashboard snapshot", err)
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"message": "Snapshot deleted. It might take an hour before it's cleared from any CDN caches.",
+		"id":      queryResult.ID,
+	})
+}
+
+// checkSnapshotWriteAccess returns an error response when the signed in user is neither the creator
+// of the snapshot nor allowed to edit the dashboard it was taken from.
+func (hs *HTTPServer) checkSnapshotWriteAccess(c *contextmodel.ReqContext, snap *dashboardsnapshots.DashboardSnapshot) response.Response {
 	// Dashboard can be empty (creation error or external snapshot). This means that the mustInt here returns a 0,
 	// which before RBAC would result in a dashboard which has no ACL. A dashboard without an ACL would fallback
 	// to the user’s org role, which for editors and admins would essentially always be allowed here. With RBAC,
 	// all permissions must be explicit, so the lack of a rule for dashboard 0 means the guardian will reject.
-	dashboardID := queryResult.Dashboard.Get("id").MustInt64()
+	dashboardID := snap.Dashboard.Get("id").MustInt64()
 
 	if dashboardID != 0 {
 		evaluator := ac.EvalPermission(dashboards.ActionDashboardsWrite, dashboards.ScopeDashboardsProvider.GetResourceScope(strconv.FormatInt(dashboardID, 10)))
@@ -233,30 +260,116 @@ func (hs *HTTPServer) DeleteDashboardSnapshot(c *contextmodel.ReqContext) respon
 			return response.Error(http.StatusInternalServerError, "Error while checking permissions for snapshot", err)
 		}
 
-		if !canEdit && queryResult.UserID != c.UserID && !errors.Is(err, dashboards.ErrDashboardNotFound) {
+		if !canEdit && snap.UserID != c.UserID && !errors.Is(err, dashboards.ErrDashboardNotFound) {
 			return response.Error(http.StatusForbidden, "Access denied to this snapshot", nil)
 		}
 	}
 
-	if queryResult.External {
-		err := dashboardsnapshots.DeleteExternalDashboardSnapshot(queryResult.ExternalDeleteURL)
-		if err != nil {
-			return response.Error(http.StatusInternalServerError, "Failed to delete external dashboard", err)
-		}
+	return nil
+}
+
+// swagger:route POST /snapshots/{key}/refresh dashboards snapshots refreshDashboardSnapshot
+//
+// Refresh a snapshot.
+//
+// Replaces the dashboard of a local snapshot with a new version, built by re-running the panel queries from the
+// share snapshot view of the source dashboard.
+// The snapshot keeps its key, delete key and expiry date.
+//
+// Responses:
+// 200: refreshDashboardSnapshotResponse
+// 400: badRequestError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) RefreshDashboardSnapshot(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
 	}
 
-	cmd := &dashboardsnapshots.DeleteDashboardSnapshotCommand{DeleteKey: queryResult.DeleteKey}
+	key := web.Params(c.Req)[":key"]
+	if len(key) == 0 {
+		return response.Error(http.StatusNotFound, "Snapshot not found", nil)
+	}
 
-	if err := hs.dashboardsnapshotsService.DeleteDashboardSnapshot(c.Req.Context(), cmd); err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to delete dashboard snapshot", err)
+	cmd := dashboardsnapshots.RefreshDashboardSnapshotCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if cmd.Dashboard == nil {
+		return response.Error(http.StatusBadRequest, "dashboard data is required", nil)
+	}
+
+	queryResult, err := hs.dashboardsnapshotsService.GetDashboardSnapshot(c.Req.Context(), &dashboardsnapshots.GetDashboardSnapshotQuery{Key: key})
+	if err != nil {
+		return response.Err(err)
+	}
+	if queryResult.OrgID != c.GetOrgID() {
+		return response.Error(http.StatusNotFound, "Snapshot not found", nil)
+	}
+
+	if rsp := hs.checkSnapshotWriteAccess(c, queryResult); rsp != nil {
+		return rsp
+	}
+
+	cmd.Key = key
+	cmd.OrgID = c.GetOrgID()
+
+	result, err := hs.dashboardsnapshotsService.RefreshDashboardSnapshot(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to refresh dashboard snapshot", err)
 	}
 
 	return response.JSON(http.StatusOK, util.DynMap{
-		"message": "Snapshot deleted. It might take an hour before it's cleared from any CDN caches.",
-		"id":      queryResult.ID,
+		"key":     result.Key,
+		"version": result.Version,
+		"url":     setting.ToAbsUrl("dashboard/snapshot/" + result.Key),
 	})
 }
 
+// swagger:route GET /snapshot/policy snapshots getSnapshotPolicy
+//
+// Get the snapshot policy of the current organization.
+//
+// Responses:
+// 200: getSnapshotPolicyResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetSnapshotPolicy(c *contextmodel.ReqContext) response.Response {
+	policy, err := hs.dashboardsnapshotsService.GetSnapshotPolicy(c.Req.Context(), c.GetOrgID())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get snapshot policy", err)
+	}
+
+	return response.JSON(http.StatusOK, policy)
+}
+
+// swagger:route PUT /snapshot/policy snapshots updateSnapshotPolicy
+//
+// Update the snapshot policy of the current organization.
+//
+// Responses:
+// 200: getSnapshotPolicyResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateSnapshotPolicy(c *contextmodel.ReqContext) response.Response {
+	cmd := dashboardsnapshots.SaveSnapshotPolicyCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.GetOrgID()
+
+	policy, err := hs.dashboardsnapshotsService.SaveSnapshotPolicy(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to save snapshot policy", err)
+	}
+
+	return response.JSON(http.StatusOK, policy)
+}
+
 // swagger:route GET /dashboard/snapshots dashboards snapshots searchDashboardSnapshots
 //
 // List snapshots.
@@ -299,6 +412,7 @@ func (hs *HTTPServer) SearchDashboardSnapshots(c *contextmodel.ReqContext) respo
 			UserID:      snapshot.UserID,
 			External:    snapshot.External,
 			ExternalURL: snapshot.ExternalURL,
+			Version:     snapshot.Version,
 			Expires:     snapshot.Expires,
 			Created:     snapshot.Created,
 			Updated:     snapshot.Updated,
@@ -326,6 +440,22 @@ type GetSnapshotsParams struct {
 	Limit int64 `json:"limit"`
 }
 
+// swagger:parameters refreshDashboardSnapshot
+type RefreshDashboardSnapshotParams struct {
+	// in:path
+	Key string `json:"key"`
+	// in:body
+	// required:true
+	Body dashboardsnapshots.RefreshDashboardSnapshotCommand `json:"body"`
+}
+
+// swagger:parameters updateSnapshotPolicy
+type UpdateSnapshotPolicyParams struct {
+	// in:body
+	// required:true
+	Body dashboardsnapshots.SaveSnapshotPolicyCommand `json:"body"`
+}
+
 // swagger:parameters getDashboardSnapshot
 type GetDashboardSnapshotParams struct {
 	// in:path
@@ -365,6 +495,24 @@ type SearchDashboardSnapshotsResponse struct {
 	Body []*dashboardsnapshots.DashboardSnapshotDTO `json:"body"`
 }
 
+// swagger:response refreshDashboardSnapshotResponse
+type RefreshSnapshotResponse struct {
+	// in:body
+	Body struct {
+		// Unique key
+		Key string `json:"key"`
+		// Version of the snapshot after the refresh
+		Version int64  `json:"version"`
+		URL     string `json:"url"`
+	} `json:"body"`
+}
+
+// swagger:response getSnapshotPolicyResponse
+type GetSnapshotPolicyResponse struct {
+	// in:body
+	Body dashboardsnapshots.SnapshotPolicy `json:"body"`
+}
+
 // swagger:response getDashboardSnapshotResponse
 type GetDashboardSnapshotResponse DashboardResponse
 
@@ -124,6 +124,68 @@ func TestHTTPServer_DeleteDashboardSnapshot(t *testing.T) {
 	})
 }
 
+func TestHTTPServer_RefreshDashboardSnapshot(t *testing.T) {
+	setup := func(t *testing.T, svc dashboards.DashboardService, snapSvc dashboardsnapshots.Service) *webtest.Server {
+		t.Helper()
+
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			cfg := setting.NewCfg()
+			cfg.SnapshotEnabled = true
+			hs.Cfg = cfg
+			hs.dashboardsnapshotsService = snapSvc
+
+			hs.DashboardService = svc
+
+			hs.AccessControl = acimpl.ProvideAccessControl(featuremgmt.WithFeatures())
+			hs.AccessControl.RegisterScopeAttributeResolver(dashboards.NewDashboardIDScopeResolver(svc, nil))
+		})
+	}
+
+	newRefreshRequest := func(server *webtest.Server) *http.Request {
+		req := server.NewPostRequest("/api/snapshots/12345/refresh", strings.NewReader(`{"dashboard":{"id":100,"title":"refreshed"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	t.Run("User should not be able to refresh snapshot without permissions to the dashboard", func(t *testing.T) {
+		svc := dashboards.NewFakeDashboardService(t)
+		svc.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "1"}, nil)
+
+		server := setup(t, svc, setUpSnapshotTest(t, 0, ""))
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			newRefreshRequest(server),
+			userWithPermissions(1, []accesscontrol.Permission{{Action: dashboardsnapshots.ActionSnapshotsCreate}}),
+		))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("User should be able to refresh snapshot with correct permissions", func(t *testing.T) {
+		svc := dashboards.NewFakeDashboardService(t)
+		svc.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "1"}, nil)
+
+		snapSvc := setUpSnapshotTest(t, 0, "").(*dashboardsnapshots.MockService)
+		snapSvc.On("RefreshDashboardSnapshot", mock.Anything, mock.MatchedBy(func(cmd *dashboardsnapshots.RefreshDashboardSnapshotCommand) bool {
+			return cmd.Key == "12345" && cmd.OrgID == 1
+		})).Return(&dashboardsnapshots.DashboardSnapshot{Key: "12345", Version: 2}, nil)
+
+		server := setup(t, svc, snapSvc)
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			newRefreshRequest(server),
+			userWithPermissions(1, []accesscontrol.Permission{
+				{Action: dashboards.ActionDashboardsWrite, Scope: "dashboards:uid:1"},
+				{Action: dashboardsnapshots.ActionSnapshotsCreate},
+			}),
+		))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
 func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 	setupRemoteServer := func(fn func(http.ResponseWriter, *http.Request)) *httptest.Server {
 		s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
@@ -7,5 +7,15 @@ type ShortURL struct {
 
 type CreateShortURLCmd struct {
 	Path string `json:"path"`
-	UID  string `json:"uid,omitempty"`
+	// UID is an optional human-readable slug, a random UID is generated when empty
+	UID string `json:"uid,omitempty"`
+	// ExpiresAt is an optional unix time after which the short URL stops resolving
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+}
+
+// UpdateShortURLCmd repoints a short URL while keeping its UID
+type UpdateShortURLCmd struct {
+	Path string `json:"path"`
+	// ExpiresAt replaces the expiry of the short URL, 0 removes it
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
 }
@@ -5,10 +5,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	claims "github.com/grafana/authlib/types"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -17,6 +22,8 @@ import (
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
 	"github.com/grafana/grafana/pkg/middleware"
 	"github.com/grafana/grafana/pkg/registry/apps/shorturl"
 	grafanaapiserver "github.com/grafana/grafana/pkg/services/apiserver"
@@ -37,11 +44,17 @@ func (hs *HTTPServer) registerShortURLAPI(apiRoute routing.RouteRegister) {
 	if hs.Features.IsEnabledGlobally(featuremgmt.FlagKubernetesShortURLs) {
 		handler := newShortURLK8sHandler(hs)
 		apiRoute.Post("/api/short-urls", reqSignedIn, handler.createKubernetesShortURLsHandler)
+		apiRoute.Get("/api/short-urls", reqSignedIn, handler.searchKubernetesShortURLsHandler)
 		apiRoute.Get("/api/short-urls/:uid", reqSignedIn, handler.getKubernetesShortURLsHandler)
+		apiRoute.Patch("/api/short-urls/:uid", reqSignedIn, handler.updateKubernetesShortURLHandler)
+		apiRoute.Get("/api/short-urls/:uid/visits", reqSignedIn, handler.getKubernetesShortURLVisitsHandler)
 		apiRoute.Get("/goto/:uid", reqSignedIn, handler.getKubernetesRedirectFromShortURL, hs.Index)
 	} else {
 		apiRoute.Post("/api/short-urls", reqSignedIn, hs.createShortURL)
+		apiRoute.Get("/api/short-urls", reqSignedIn, hs.searchShortURLs)
 		apiRoute.Get("/api/short-urls/:uid", reqSignedIn, hs.getShortURL)
+		apiRoute.Patch("/api/short-urls/:uid", reqSignedIn, hs.updateShortURL)
+		apiRoute.Get("/api/short-urls/:uid/visits", reqSignedIn, hs.getShortURLVisits)
 		apiRoute.Get("/goto/:uid", reqSignedIn, hs.redirectFromShortURL, hs.Index)
 	}
 }
@@ -86,9 +99,9 @@ func (hs *HTTPServer) redirectFromShortURL(c *contextmodel.ReqContext) {
 		return
 	}
 
-	// Failure to update LastSeenAt should still allow to redirect
-	if err := hs.ShortURLService.UpdateLastSeenAt(c.Req.Context(), shortURL); err != nil {
-		hs.log.Error("Failed to update short URL last seen at", "error", err)
+	// Failure to record the visit should still allow to redirect
+	if err := hs.ShortURLService.RecordVisit(c.Req.Context(), shortURL); err != nil {
+		hs.log.Error("Failed to record short URL visit", "error", err)
 	}
 
 	// Safety net: validate stored path before redirecting to prevent open redirects
@@ -120,6 +133,87 @@ func (hs *HTTPServer) getShortURL(c *contextmodel.ReqContext) response.Response
 	return response.JSON(http.StatusOK, shortURL)
 }
 
+// searchShortURLs handles requests to list the short URLs of the org.
+// Org admins see every short URL, other users only the ones they created.
+func (hs *HTTPServer) searchShortURLs(c *contextmodel.ReqContext) response.Response {
+	query := &shorturls.SearchShortURLsQuery{
+		Query: c.Query("query"),
+		Limit: c.QueryInt("limit"),
+		Page:  c.QueryInt("page"),
+	}
+
+	shortURLs, err := hs.ShortURLService.SearchShortURLs(c.Req.Context(), c.SignedInUser, query)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	results := make([]shorturls.ShortURLSearchResult, 0, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		dto := hs.ShortURLService.ConvertShortURLToDTO(shortURL, hs.Cfg.AppURL)
+		results = append(results, shorturls.ShortURLSearchResult{
+			UID:        shortURL.Uid,
+			URL:        dto.URL,
+			Path:       shortURL.Path,
+			CreatedBy:  shortURL.CreatedBy,
+			CreatedAt:  shortURL.CreatedAt,
+			LastSeenAt: shortURL.LastSeenAt,
+			ExpiresAt:  shortURL.ExpiresAt,
+			VisitCount: shortURL.VisitCount,
+		})
+	}
+
+	return response.JSON(http.StatusOK, results)
+}
+
+// updateShortURL handles requests to repoint a short URL to a new path.
+func (hs *HTTPServer) updateShortURL(c *contextmodel.ReqContext) response.Response {
+	shortURLUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(shortURLUID) {
+		return response.Err(shorturls.ErrShortURLBadRequest.Errorf("invalid uid"))
+	}
+
+	cmd := &dtos.UpdateShortURLCmd{}
+	if err := web.Bind(c.Req, cmd); err != nil {
+		return response.Err(shorturls.ErrShortURLBadRequest.Errorf("bad request data: %w", err))
+	}
+
+	shortURL, err := hs.ShortURLService.UpdateShortURL(c.Req.Context(), c.SignedInUser, shortURLUID, cmd)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, hs.ShortURLService.ConvertShortURLToDTO(shortURL, hs.Cfg.AppURL))
+}
+
+// getShortURLVisits handles requests for the daily visit counts of a short URL.
+// The time range is given in unix seconds by the from and to query parameters and defaults to the last 30 days.
+func (hs *HTTPServer) getShortURLVisits(c *contextmodel.ReqContext) response.Response {
+	shortURLUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(shortURLUID) {
+		return response.Err(shorturls.ErrShortURLBadRequest.Errorf("invalid uid"))
+	}
+
+	shortURL, err := hs.ShortURLService.GetShortURLByUID(c.Req.Context(), c.SignedInUser, shortURLUID)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	now := time.Now()
+	to := time.Unix(c.QueryInt64WithDefault("to", now.Unix()), 0)
+	from := time.Unix(c.QueryInt64WithDefault("from", now.AddDate(0, 0, -30).Unix()), 0)
+
+	visits, err := hs.ShortURLService.GetVisits(c.Req.Context(), shortURL, from, to)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"uid":        shortURL.Uid,
+		"visitCount": shortURL.VisitCount,
+		"visits":     visits,
+	})
+}
+
 type shortURLK8sHandler struct {
 	namespacer           request.NamespaceMapper
 	gvr                  schema.GroupVersionResource
@@ -149,13 +243,176 @@ func (sk8s *shortURLK8sHandler) getKubernetesShortURLsHandler(c *contextmodel.Re
 	}
 
 	c.Logger.Debug("Fetching short URL", "uid", shortURLUID)
-	out, err := client.Get(c.Req.Context(), shortURLUID, v1.GetOptions{})
+	out, ok := sk8s.getShortURL(c, client, shortURLUID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, shorturl.UnstructuredToLegacyShortURL(*out))
+}
+
+// searchKubernetesShortURLsHandler lists the short URLs of the org, with the same filters and paging as searchShortURLs.
+// Org admins see every short URL, other users only the ones they created.
+func (sk8s *shortURLK8sHandler) searchKubernetesShortURLsHandler(c *contextmodel.ReqContext) {
+	client, ok := sk8s.getClient(c)
+	if !ok {
+		return
+	}
+
+	query := strings.ToLower(c.Query("query"))
+	isAdmin := c.SignedInUser.GetOrgRole() == identity.RoleAdmin
+
+	results := make([]shorturls.ShortURLSearchResult, 0)
+	opts := v1.ListOptions{Limit: 500}
+	for {
+		list, err := client.List(c.Req.Context(), opts)
+		if err != nil {
+			sk8s.writeError(c, err)
+			return
+		}
+
+		for _, item := range list.Items {
+			if !isAdmin && !isShortURLOwner(c, item) {
+				continue
+			}
+			shortURL := shorturl.UnstructuredToLegacyShortURL(item)
+			if query != "" && !strings.Contains(strings.ToLower(shortURL.Uid), query) && !strings.Contains(strings.ToLower(shortURL.Path), query) {
+				continue
+			}
+			results = append(results, shorturls.ShortURLSearchResult{
+				UID:        shortURL.Uid,
+				URL:        shorturl.UnstructuredToLegacyShortURLDTO(item, sk8s.cfg.AppURL).URL,
+				Path:       shortURL.Path,
+				CreatedBy:  shortURLCreatorID(item),
+				CreatedAt:  shortURL.CreatedAt,
+				LastSeenAt: shortURL.LastSeenAt,
+				ExpiresAt:  shortURL.ExpiresAt,
+				VisitCount: shortURL.VisitCount,
+			})
+		}
+
+		if list.GetContinue() == "" {
+			break
+		}
+		opts.Continue = list.GetContinue()
+	}
+
+	// Same order and paging as the SQL search
+	sort.SliceStable(results, func(i, j int) bool { return results[i].CreatedAt > results[j].CreatedAt })
+	limit := c.QueryInt("limit")
+	if limit <= 0 {
+		limit = 100
+	}
+	page := c.QueryInt("page")
+	if page < 1 {
+		page = 1
+	}
+	start := min((page-1)*limit, len(results))
+	end := min(start+limit, len(results))
+
+	c.JSON(http.StatusOK, results[start:end])
+}
+
+// updateKubernetesShortURLHandler repoints a short URL to a new path, like updateShortURL.
+// Only the owner of the short URL or an org admin can change it.
+func (sk8s *shortURLK8sHandler) updateKubernetesShortURLHandler(c *contextmodel.ReqContext) {
+	client, ok := sk8s.getClient(c)
+	if !ok {
+		return
+	}
+
+	shortURLUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(shortURLUID) {
+		errhttp.Write(c.Req.Context(), shorturls.ErrShortURLBadRequest.Errorf("invalid uid"), c.Resp)
+		return
+	}
+
+	cmd := dtos.UpdateShortURLCmd{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		errhttp.Write(c.Req.Context(), shorturls.ErrShortURLBadRequest.Errorf("bad request data: %w", err), c.Resp)
+		return
+	}
+	relPath := strings.TrimSpace(cmd.Path)
+	if relPath == "" {
+		errhttp.Write(c.Req.Context(), shorturls.ErrShortURLBadRequest.Errorf("path is required"), c.Resp)
+		return
+	}
+	if err := shorturls.ValidateRelativePath(relPath); err != nil {
+		errhttp.Write(c.Req.Context(), err, c.Resp)
+		return
+	}
+	if err := shorturls.ValidateExpiresAt(cmd.ExpiresAt, time.Now()); err != nil {
+		errhttp.Write(c.Req.Context(), err, c.Resp)
+		return
+	}
+
+	// Expired short URLs can still be repointed, so the expiry is not checked here
+	existing, err := client.Get(c.Req.Context(), shortURLUID, v1.GetOptions{})
 	if err != nil {
 		sk8s.writeError(c, err)
 		return
 	}
+	if c.SignedInUser.GetOrgRole() != identity.RoleAdmin && !isShortURLOwner(c, *existing) {
+		errhttp.Write(c.Req.Context(), shorturls.ErrShortURLForbidden.Errorf("user %s does not own short URL %s", c.SignedInUser.GetID(), shortURLUID), c.Resp)
+		return
+	}
 
-	c.JSON(http.StatusOK, shorturl.UnstructuredToLegacyShortURL(*out))
+	if err := unstructured.SetNestedField(existing.Object, relPath, "spec", "path"); err != nil {
+		c.JsonApiErr(http.StatusInternalServerError, "failed to update short URL", err)
+		return
+	}
+	if cmd.ExpiresAt > 0 {
+		err = unstructured.SetNestedField(existing.Object, cmd.ExpiresAt, "spec", "expiresAt")
+	} else {
+		unstructured.RemoveNestedField(existing.Object, "spec", "expiresAt")
+	}
+	if err != nil {
+		c.JsonApiErr(http.StatusInternalServerError, "failed to update short URL", err)
+		return
+	}
+
+	out, err := client.Update(c.Req.Context(), existing, v1.UpdateOptions{})
+	if err != nil {
+		sk8s.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shorturl.UnstructuredToLegacyShortURLDTO(*out, sk8s.cfg.AppURL))
+}
+
+// getKubernetesShortURLVisitsHandler returns the daily visit counts of a short URL, like getShortURLVisits.
+// The visits are read from the status of the short URL, which keeps the most recent days.
+func (sk8s *shortURLK8sHandler) getKubernetesShortURLVisitsHandler(c *contextmodel.ReqContext) {
+	client, ok := sk8s.getClient(c)
+	if !ok {
+		return
+	}
+
+	shortURLUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(shortURLUID) {
+		errhttp.Write(c.Req.Context(), shorturls.ErrShortURLBadRequest.Errorf("invalid uid"), c.Resp)
+		return
+	}
+
+	out, ok := sk8s.getShortURL(c, client, shortURLUID)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	to := time.Unix(c.QueryInt64WithDefault("to", now.Unix()), 0)
+	from := time.Unix(c.QueryInt64WithDefault("from", now.AddDate(0, 0, -30).Unix()), 0)
+	if to.Before(from) {
+		errhttp.Write(c.Req.Context(), shorturls.ErrShortURLBadRequest.Errorf("from must be before to"), c.Resp)
+		return
+	}
+
+	shortURL := shorturl.UnstructuredToLegacyShortURL(*out)
+	c.JSON(http.StatusOK, util.DynMap{
+		"uid":        shortURL.Uid,
+		"visitCount": shortURL.VisitCount,
+		"visits":     shorturl.UnstructuredToLegacyShortURLVisits(*out, from, to),
+	})
 }
 
 func (sk8s *shortURLK8sHandler) getKubernetesRedirectFromShortURL(c *contextmodel.ReqContext) {
@@ -182,7 +439,14 @@ func (sk8s *shortURLK8sHandler) getKubernetesRedirectFromShortURL(c *contextmode
 		Do(c.Req.Context())
 
 	if err = result.Error(); err != nil {
-		c.JsonApiErr(500, "goto", err)
+		// Like the legacy handler, redirect to the main page instead of looping over the short URL
+		if errors.IsNotFound(err) {
+			c.Logger.Debug("Not redirecting short URL since not found", "uid", uid)
+			c.Redirect(sk8s.cfg.AppURL, http.StatusPermanentRedirect)
+			return
+		}
+		c.Logger.Error("Short URL redirection error", "err", err)
+		c.Redirect(sk8s.cfg.AppURL, http.StatusTemporaryRedirect)
 		return
 	}
 
@@ -241,6 +505,17 @@ func (sk8s *shortURLK8sHandler) createKubernetesShortURLsHandler(c *contextmodel
 		return
 	}
 
+	if err := shorturls.ValidateExpiresAt(cmd.ExpiresAt, time.Now()); err != nil {
+		errhttp.Write(c.Req.Context(), err, c.Resp)
+		return
+	}
+	if cmd.UID != "" {
+		if err := shorturls.ValidateUID(cmd.UID); err != nil {
+			errhttp.Write(c.Req.Context(), err, c.Resp)
+			return
+		}
+	}
+
 	c.Logger.Debug("Creating short URL", "path", cmd.Path)
 	obj := shorturl.LegacyCreateCommandToUnstructured(cmd)
 	obj.SetGenerateName("s") // becomes a prefix
@@ -269,6 +544,40 @@ func (sk8s *shortURLK8sHandler) getClient(c *contextmodel.ReqContext) (dynamic.R
 	return dyn.Resource(sk8s.gvr).Namespace(sk8s.namespacer(c.OrgID)), true
 }
 
+// getShortURL reads a short URL and hides it once expired, like ShortURLService.GetShortURLByUID
+func (sk8s *shortURLK8sHandler) getShortURL(c *contextmodel.ReqContext, client dynamic.ResourceInterface, uid string) (*unstructured.Unstructured, bool) {
+	out, err := client.Get(c.Req.Context(), uid, v1.GetOptions{})
+	if err != nil {
+		sk8s.writeError(c, err)
+		return nil, false
+	}
+	if shorturl.UnstructuredToLegacyShortURL(*out).IsExpired(time.Now()) {
+		errhttp.Write(c.Req.Context(), shorturls.ErrShortURLNotFound.Errorf("short URL expired"), c.Resp)
+		return nil, false
+	}
+	return out, true
+}
+
+// isShortURLOwner reports whether the signed in user created the short URL.
+// Migrated short URLs record the creator by internal ID, new ones by UID.
+func isShortURLOwner(c *contextmodel.ReqContext, item unstructured.Unstructured) bool {
+	createdBy := item.GetAnnotations()[utils.AnnoKeyCreatedBy]
+	return createdBy != "" && (createdBy == c.SignedInUser.GetID() || createdBy == c.SignedInUser.GetUID())
+}
+
+// shortURLCreatorID returns the internal ID of the creator of a short URL, 0 when it is recorded by UID
+func shortURLCreatorID(item unstructured.Unstructured) int64 {
+	_, id, err := claims.ParseTypeID(item.GetAnnotations()[utils.AnnoKeyCreatedBy])
+	if err != nil {
+		return 0
+	}
+	internalID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return internalID
+}
+
 func (sk8s *shortURLK8sHandler) writeError(c *contextmodel.ReqContext, err error) {
 	//nolint:errorlint
 	statusError, ok := err.(*errors.StatusError)
@@ -1,22 +1,37 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/shorturls"
+	"github.com/grafana/grafana/pkg/services/shorturls/shorturlimpl"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/testutil"
+	"github.com/grafana/grafana/pkg/web/webtest"
 )
 
 func TestShortURLAPIEndpoint(t *testing.T) {
@@ -106,6 +121,22 @@ func (s *fakeShortURLService) UpdateLastSeenAt(ctx context.Context, shortURL *sh
 	return nil
 }
 
+func (s *fakeShortURLService) SearchShortURLs(ctx context.Context, user identity.Requester, query *shorturls.SearchShortURLsQuery) ([]*shorturls.ShortUrl, error) {
+	return nil, nil
+}
+
+func (s *fakeShortURLService) UpdateShortURL(ctx context.Context, user identity.Requester, uid string, cmd *dtos.UpdateShortURLCmd) (*shorturls.ShortUrl, error) {
+	return nil, nil
+}
+
+func (s *fakeShortURLService) RecordVisit(ctx context.Context, shortURL *shorturls.ShortUrl) error {
+	return nil
+}
+
+func (s *fakeShortURLService) GetVisits(ctx context.Context, shortURL *shorturls.ShortUrl, from, to time.Time) ([]*shorturls.ShortUrlVisit, error) {
+	return nil, nil
+}
+
 func (s *fakeShortURLService) DeleteStaleShortURLs(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error {
 	return nil
 }
@@ -116,3 +147,297 @@ func (s *fakeShortURLService) ConvertShortURLToDTO(shortURL *shorturls.ShortUrl,
 	}
 	return nil
 }
+
+// shortURLTestData is the short URLs seeded in both the legacy store and the fake apiserver
+var shortURLTestData = []struct {
+	uid     string
+	path    string
+	owner   *user.SignedInUser
+	expired bool
+}{
+	{uid: "mine", path: "d/mine", owner: shortURLOwner},
+	{uid: "gone", path: "d/gone", owner: shortURLOwner, expired: true},
+	{uid: "theirs", path: "d/theirs", owner: shortURLAdmin},
+}
+
+var (
+	shortURLOwner = &user.SignedInUser{UserID: 10, UserUID: "owner-uid", OrgID: 1, OrgRole: org.RoleEditor}
+	shortURLOther = &user.SignedInUser{UserID: 11, UserUID: "other-uid", OrgID: 1, OrgRole: org.RoleEditor}
+	shortURLAdmin = &user.SignedInUser{UserID: 12, UserUID: "admin-uid", OrgID: 1, OrgRole: org.RoleAdmin}
+)
+
+func TestIntegrationShortURLRoutes(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	for _, mode := range []string{"legacy", "kubernetes"} {
+		t.Run(mode, func(t *testing.T) {
+			setup := func(t *testing.T) *webtest.Server {
+				if mode == "kubernetes" {
+					return setupK8sShortURLServer(t)
+				}
+				return setupLegacyShortURLServer(t)
+			}
+
+			t.Run("list returns the short URLs of the user", func(t *testing.T) {
+				server := setup(t)
+				var results []shorturls.ShortURLSearchResult
+				code := sendShortURLRequest(t, server, shortURLOwner, http.MethodGet, "/api/short-urls", nil, &results)
+				require.Equal(t, http.StatusOK, code)
+				require.ElementsMatch(t, []string{"mine", "gone"}, shortURLUIDs(results))
+			})
+
+			t.Run("list returns every short URL to org admins and filters by query", func(t *testing.T) {
+				server := setup(t)
+				var results []shorturls.ShortURLSearchResult
+				code := sendShortURLRequest(t, server, shortURLAdmin, http.MethodGet, "/api/short-urls", nil, &results)
+				require.Equal(t, http.StatusOK, code)
+				require.ElementsMatch(t, []string{"mine", "gone", "theirs"}, shortURLUIDs(results))
+
+				code = sendShortURLRequest(t, server, shortURLAdmin, http.MethodGet, "/api/short-urls?query=THEIRS", nil, &results)
+				require.Equal(t, http.StatusOK, code)
+				require.Equal(t, []string{"theirs"}, shortURLUIDs(results))
+
+				code = sendShortURLRequest(t, server, shortURLAdmin, http.MethodGet, "/api/short-urls?limit=2&page=2", nil, &results)
+				require.Equal(t, http.StatusOK, code)
+				require.Len(t, results, 1)
+			})
+
+			t.Run("get hides expired short URLs", func(t *testing.T) {
+				server := setup(t)
+				var shortURL shorturls.ShortUrl
+				code := sendShortURLRequest(t, server, shortURLOwner, http.MethodGet, "/api/short-urls/mine", nil, &shortURL)
+				require.Equal(t, http.StatusOK, code)
+				require.Equal(t, "d/mine", shortURL.Path)
+
+				code = sendShortURLRequest(t, server, shortURLOwner, http.MethodGet, "/api/short-urls/gone", nil, nil)
+				require.Equal(t, http.StatusNotFound, code)
+			})
+
+			t.Run("only the owner or an org admin can repoint a short URL", func(t *testing.T) {
+				server := setup(t)
+				cmd := dtos.UpdateShortURLCmd{Path: "d/new"}
+				code := sendShortURLRequest(t, server, shortURLOther, http.MethodPatch, "/api/short-urls/mine", cmd, nil)
+				require.Equal(t, http.StatusForbidden, code)
+
+				code = sendShortURLRequest(t, server, shortURLOwner, http.MethodPatch, "/api/short-urls/mine", cmd, nil)
+				require.Equal(t, http.StatusOK, code)
+
+				var shortURL shorturls.ShortUrl
+				code = sendShortURLRequest(t, server, shortURLOwner, http.MethodGet, "/api/short-urls/mine", nil, &shortURL)
+				require.Equal(t, http.StatusOK, code)
+				require.Equal(t, "d/new", shortURL.Path)
+
+				code = sendShortURLRequest(t, server, shortURLAdmin, http.MethodPatch, "/api/short-urls/mine", dtos.UpdateShortURLCmd{Path: "/absolute"}, nil)
+				require.Equal(t, http.StatusBadRequest, code)
+			})
+
+			t.Run("visits", func(t *testing.T) {
+				server := setup(t)
+				var visits struct {
+					UID    string                     `json:"uid"`
+					Visits []*shorturls.ShortUrlVisit `json:"visits"`
+				}
+				code := sendShortURLRequest(t, server, shortURLOwner, http.MethodGet, "/api/short-urls/mine/visits", nil, &visits)
+				require.Equal(t, http.StatusOK, code)
+				require.Equal(t, "mine", visits.UID)
+
+				code = sendShortURLRequest(t, server, shortURLOwner, http.MethodGet, "/api/short-urls/mine/visits?from=20&to=10", nil, nil)
+				require.Equal(t, http.StatusBadRequest, code)
+
+				code = sendShortURLRequest(t, server, shortURLOwner, http.MethodGet, "/api/short-urls/gone/visits", nil, nil)
+				require.Equal(t, http.StatusNotFound, code)
+			})
+
+			t.Run("expired short URLs redirect to the home page", func(t *testing.T) {
+				server := setup(t)
+				server.HttpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+					return http.ErrUseLastResponse
+				}
+
+				req := webtest.RequestWithSignedInUser(server.NewGetRequest("/goto/mine"), shortURLOwner)
+				resp, err := server.Send(req)
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+				require.Equal(t, http.StatusFound, resp.StatusCode)
+				require.True(t, strings.HasSuffix(resp.Header.Get("Location"), "d/mine"), resp.Header.Get("Location"))
+
+				req = webtest.RequestWithSignedInUser(server.NewGetRequest("/goto/gone"), shortURLOwner)
+				resp, err = server.Send(req)
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+				require.Equal(t, http.StatusPermanentRedirect, resp.StatusCode)
+				require.Equal(t, shortURLTestAppURL, resp.Header.Get("Location"))
+			})
+		})
+	}
+}
+
+const shortURLTestAppURL = "http://localhost:3000/"
+
+func sendShortURLRequest(t *testing.T, server *webtest.Server, usr *user.SignedInUser, method, target string, body any, out any) int {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(raw)
+	}
+	req := webtest.RequestWithSignedInUser(server.NewRequest(method, target, reader), usr)
+	resp, err := server.SendJSON(req)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	if out != nil && resp.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+func shortURLUIDs(results []shorturls.ShortURLSearchResult) []string {
+	uids := make([]string, 0, len(results))
+	for _, r := range results {
+		uids = append(uids, r.UID)
+	}
+	return uids
+}
+
+func setupLegacyShortURLServer(t *testing.T) *webtest.Server {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.AppURL = shortURLTestAppURL
+	sqlStore := db.InitTestDB(t, sqlstore.InitTestDBOpt{Cfg: cfg})
+	service := shorturlimpl.ProvideService(sqlStore)
+
+	for _, item := range shortURLTestData {
+		_, err := service.CreateShortURL(context.Background(), item.owner, &dtos.CreateShortURLCmd{Path: item.path, UID: item.uid})
+		require.NoError(t, err)
+		if item.expired {
+			err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+				_, err := sess.Exec("UPDATE short_url SET expires_at = ? WHERE uid = ?", time.Now().Add(-time.Hour).Unix(), item.uid)
+				return err
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	return SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = cfg
+		hs.ShortURLService = service
+		hs.log = log.New("test")
+	})
+}
+
+func setupK8sShortURLServer(t *testing.T) *webtest.Server {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.AppURL = shortURLTestAppURL
+	apiserver := newFakeShortURLAPIServer(t)
+	for _, item := range shortURLTestData {
+		obj := map[string]any{
+			"apiVersion": "shorturl.grafana.app/v1beta1",
+			"kind":       "ShortURL",
+			"metadata": map[string]any{
+				"name":              item.uid,
+				"namespace":         "default",
+				"resourceVersion":   "1",
+				"creationTimestamp": time.Now().UTC().Format(time.RFC3339),
+				// New short URLs record the creator by UID, migrated ones by internal ID
+				"annotations": map[string]any{utils.AnnoKeyCreatedBy: item.owner.GetUID()},
+			},
+			"spec":   map[string]any{"path": item.path},
+			"status": map[string]any{"lastSeenAt": 0},
+		}
+		if item.owner == shortURLAdmin {
+			obj["metadata"].(map[string]any)["annotations"] = map[string]any{utils.AnnoKeyCreatedBy: item.owner.GetID()}
+		}
+		if item.expired {
+			obj["spec"].(map[string]any)["expiresAt"] = time.Now().Add(-time.Hour).Unix()
+		}
+		apiserver.objects[item.uid] = obj
+	}
+
+	return SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = cfg
+		hs.Features = featuremgmt.WithFeatures(featuremgmt.FlagKubernetesShortURLs)
+		hs.clientConfigProvider = &mockDirectRestConfigProvider{host: apiserver.server.URL}
+		hs.log = log.New("test")
+	})
+}
+
+// fakeShortURLAPIServer serves the short URLs of the default namespace like the apiserver does,
+// including the expiry check of the goto subresource
+type fakeShortURLAPIServer struct {
+	server  *httptest.Server
+	objects map[string]map[string]any
+}
+
+func newFakeShortURLAPIServer(t *testing.T) *fakeShortURLAPIServer {
+	f := &fakeShortURLAPIServer{objects: map[string]map[string]any{}}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeShortURLAPIServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	const prefix = "/apis/shorturl.grafana.app/v1beta1/namespaces/default/shorturls"
+	idx := strings.Index(r.URL.Path, prefix)
+	if idx < 0 {
+		http.NotFound(w, r)
+		return
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path[idx+len(prefix):], "/"), "/")
+	w.Header().Set("Content-Type", "application/json")
+
+	if parts[0] == "" {
+		items := make([]map[string]any, 0, len(f.objects))
+		for _, obj := range f.objects {
+			items = append(items, obj)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"apiVersion": "shorturl.grafana.app/v1beta1",
+			"kind":       "ShortURLList",
+			"metadata":   map[string]any{},
+			"items":      items,
+		})
+		return
+	}
+
+	obj, ok := f.objects[parts[0]]
+	if !ok {
+		writeFakeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound)
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && parts[1] == "goto":
+		spec := obj["spec"].(map[string]any)
+		if expiresAt, ok := spec["expiresAt"].(int64); ok && expiresAt <= time.Now().Unix() {
+			writeFakeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"url": strings.TrimSuffix(shortURLTestAppURL, "/") + "/" + spec["path"].(string)})
+	case r.Method == http.MethodPut:
+		updated := map[string]any{}
+		if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+			writeFakeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest)
+			return
+		}
+		f.objects[parts[0]] = updated
+		_ = json.NewEncoder(w).Encode(updated)
+	default:
+		_ = json.NewEncoder(w).Encode(obj)
+	}
+}
+
+func writeFakeStatus(w http.ResponseWriter, code int, reason metav1.StatusReason) {
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Reason:   reason,
+		Code:     int32(code),
+	})
+}
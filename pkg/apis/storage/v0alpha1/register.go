@@ -0,0 +1,134 @@
+package v0alpha1
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+)
+
+const (
+	GROUP      = "storage.grafana.app"
+	VERSION    = "v0alpha1"
+	APIVERSION = GROUP + "/" + VERSION
+)
+
+// The label set on dead letters with the name of their subscription
+const LabelWebhookSubscription = GROUP + "/subscription"
+
+var WebhookSubscriptionResourceInfo = utils.NewResourceInfo(GROUP, VERSION,
+	"webhooksubscriptions", "webhooksubscription", "WebhookSubscription",
+	func() runtime.Object { return &WebhookSubscription{} },
+	func() runtime.Object { return &WebhookSubscriptionList{} },
+	utils.TableColumns{
+		Definition: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Group", Type: "string"},
+			{Name: "Resource", Type: "string"},
+			{Name: "Sink", Type: "string"},
+			{Name: "Created At", Type: "date"},
+		},
+		Reader: func(obj any) ([]interface{}, error) {
+			m, ok := obj.(*WebhookSubscription)
+			if !ok {
+				return nil, fmt.Errorf("expected webhook subscription")
+			}
+			return []interface{}{
+				m.Name,
+				m.Spec.Group,
+				m.Spec.Resource,
+				m.Spec.Sink.URL,
+				m.CreationTimestamp.UTC().Format(time.RFC3339),
+			}, nil
+		},
+	},
+)
+
+var WebhookDeadLetterResourceInfo = utils.NewResourceInfo(GROUP, VERSION,
+	"webhookdeadletters", "webhookdeadletter", "WebhookDeadLetter",
+	func() runtime.Object { return &WebhookDeadLetter{} },
+	func() runtime.Object { return &WebhookDeadLetterList{} },
+	utils.TableColumns{
+		Definition: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Subscription", Type: "string"},
+			{Name: "Attempts", Type: "number"},
+			{Name: "Error", Type: "string"},
+			{Name: "Failed At", Type: "date"},
+		},
+		Reader: func(obj any) ([]interface{}, error) {
+			m, ok := obj.(*WebhookDeadLetter)
+			if !ok {
+				return nil, fmt.Errorf("expected webhook dead letter")
+			}
+			return []interface{}{
+				m.Name,
+				m.Spec.Subscription,
+				m.Spec.Attempts,
+				m.Spec.LastError,
+				m.Spec.FailedAt.UTC().Format(time.RFC3339),
+			}, nil
+		},
+	},
+)
+
+var RetentionPolicyResourceInfo = utils.NewResourceInfo(GROUP, VERSION,
+	"retentionpolicies", "retentionpolicy", "RetentionPolicy",
+	func() runtime.Object { return &RetentionPolicy{} },
+	func() runtime.Object { return &RetentionPolicyList{} },
+	utils.TableColumns{
+		Definition: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Rules", Type: "number"},
+			{Name: "Created At", Type: "date"},
+		},
+		Reader: func(obj any) ([]interface{}, error) {
+			m, ok := obj.(*RetentionPolicy)
+			if !ok {
+				return nil, fmt.Errorf("expected retention policy")
+			}
+			return []interface{}{
+				m.Name,
+				len(m.Spec.Rules),
+				m.CreationTimestamp.UTC().Format(time.RFC3339),
+			}, nil
+		},
+	},
+)
+
+var (
+	// SchemeGroupVersion is group version used to register these objects
+	SchemeGroupVersion = schema.GroupVersion{Group: GROUP, Version: VERSION}
+
+	// SchemeBuilder is used by standard codegen
+	SchemeBuilder      runtime.SchemeBuilder
+	localSchemeBuilder = &SchemeBuilder
+	AddToScheme        = localSchemeBuilder.AddToScheme
+)
+
+func init() {
+	localSchemeBuilder.Register(addKnownTypes)
+}
+
+// Adds the list of known types to the given scheme.
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&WebhookSubscription{},
+		&WebhookSubscriptionList{},
+		&WebhookDeadLetter{},
+		&WebhookDeadLetterList{},
+		&RetentionPolicy{},
+		&RetentionPolicyList{},
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
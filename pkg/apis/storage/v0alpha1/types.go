@@ -0,0 +1,186 @@
+package v0alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+)
+
+const OpenAPIPrefix = "com.github.grafana.grafana.pkg.apis.storage.v0alpha1."
+
+// WebhookSubscription sends the changes of a resource in the namespace to a sink
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WebhookSubscription struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec WebhookSubscriptionSpec `json:"spec,omitempty"`
+}
+
+func (WebhookSubscription) OpenAPIModelName() string {
+	return OpenAPIPrefix + "WebhookSubscription"
+}
+
+type WebhookSubscriptionSpec struct {
+	// The group of the watched resource
+	Group string `json:"group"`
+
+	// The watched resource
+	Resource string `json:"resource"`
+
+	// Only the resources matching the selector are sent (eg, "team=platform,env!=dev")
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// Where the events are sent
+	Sink WebhookSink `json:"sink"`
+}
+
+func (WebhookSubscriptionSpec) OpenAPIModelName() string {
+	return OpenAPIPrefix + "WebhookSubscriptionSpec"
+}
+
+// +enum
+type WebhookSinkType string
+
+const (
+	// The events are posted as JSON
+	WebhookSinkHTTP WebhookSinkType = "webhook"
+	// The events are posted as CloudEvents in structured content mode
+	WebhookSinkCloudEvents WebhookSinkType = "cloudevents"
+)
+
+type WebhookSink struct {
+	Type WebhookSinkType `json:"type"`
+	URL  string          `json:"url"`
+
+	// When set, the payload is signed with HMAC-SHA256.
+	// The secret is write only: it is encrypted when saved and never returned.
+	Secret string `json:"secret,omitempty"`
+
+	// The encrypted secret, set by the server
+	EncryptedSecret string `json:"encryptedSecret,omitempty"`
+}
+
+func (WebhookSink) OpenAPIModelName() string {
+	return OpenAPIPrefix + "WebhookSink"
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WebhookSubscriptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []WebhookSubscription `json:"items"`
+}
+
+func (WebhookSubscriptionList) OpenAPIModelName() string {
+	return OpenAPIPrefix + "WebhookSubscriptionList"
+}
+
+// WebhookDeadLetter is an event that could not be delivered after all the attempts.
+// The dead letters are written by the storage server, deleting one acknowledges it.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WebhookDeadLetter struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec WebhookDeadLetterSpec `json:"spec,omitempty"`
+}
+
+func (WebhookDeadLetter) OpenAPIModelName() string {
+	return OpenAPIPrefix + "WebhookDeadLetter"
+}
+
+type WebhookDeadLetterSpec struct {
+	// The name of the subscription
+	Subscription string `json:"subscription"`
+
+	// The event that was not delivered
+	Event common.Unstructured `json:"event"`
+
+	// The number of delivery attempts
+	Attempts int `json:"attempts"`
+
+	// The error of the last attempt
+	LastError string `json:"lastError"`
+
+	// When the last attempt failed
+	FailedAt metav1.Time `json:"failedAt"`
+}
+
+func (WebhookDeadLetterSpec) OpenAPIModelName() string {
+	return OpenAPIPrefix + "WebhookDeadLetterSpec"
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WebhookDeadLetterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []WebhookDeadLetter `json:"items"`
+}
+
+func (WebhookDeadLetterList) OpenAPIModelName() string {
+	return OpenAPIPrefix + "WebhookDeadLetterList"
+}
+
+// RetentionPolicy configures the history kept by the pruner for the resources of the namespace.
+//
+// The most specific rule matching a resource applies: the rule for its group and resource, then the rule
+// for its group, then the rule without a group. When several policies of the namespace have an equally
+// specific rule, the policy with the smallest name wins. The resources without a matching rule keep the
+// default history limits of the pruner.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type RetentionPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RetentionPolicySpec `json:"spec,omitempty"`
+}
+
+func (RetentionPolicy) OpenAPIModelName() string {
+	return OpenAPIPrefix + "RetentionPolicy"
+}
+
+type RetentionPolicySpec struct {
+	Rules []RetentionRule `json:"rules"`
+}
+
+func (RetentionPolicySpec) OpenAPIModelName() string {
+	return OpenAPIPrefix + "RetentionPolicySpec"
+}
+
+// RetentionRule combines count and age based retention: a version is pruned when it is beyond MaxVersions
+// or older than MaxAge, unless it is one of the MinVersions latest versions. The latest version is always kept.
+type RetentionRule struct {
+	// The group of the resources, empty matches all the groups
+	Group string `json:"group,omitempty"`
+
+	// The resource, empty matches all the resources of the group
+	Resource string `json:"resource,omitempty"`
+
+	// Number of versions kept, zero keeps the default number of versions of the resource
+	MaxVersions int `json:"maxVersions,omitempty"`
+
+	// Age of the versions kept (eg "8760h"), zero keeps versions of any age
+	MaxAge metav1.Duration `json:"maxAge,omitzero"`
+
+	// Number of versions kept whatever their age
+	MinVersions int `json:"minVersions,omitempty"`
+}
+
+func (RetentionRule) OpenAPIModelName() string {
+	return OpenAPIPrefix + "RetentionRule"
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type RetentionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []RetentionPolicy `json:"items"`
+}
+
+func (RetentionPolicyList) OpenAPIModelName() string {
+	return OpenAPIPrefix + "RetentionPolicyList"
+}
@@ -137,6 +137,21 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:   "support-bundle",
+		Usage:  "Creates a support bundle without the Grafana server running",
+		Action: runRunnerCommand(supportBundleCommand),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "Path of the support bundle file, defaults to grafana-support-bundle-<timestamp>.tar.gz in the current directory",
+			},
+			&cli.StringSliceFlag{
+				Name:  "collectors",
+				Usage: "Collectors to include, one of basic, settings, db and unified-storage-index. Defaults to all of them",
+			},
+		},
+	},
 	{
 		Name:  "data-migration",
 		Usage: "Runs a script that migrates or cleanups data in your database",
@@ -224,6 +239,61 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:   "migrate-unified-storage-to-file",
+		Usage:  "Copies the unified storage resources, their history and their blobs from the database into the embedded file storage. Grafana must be stopped. Safe to execute multiple times.",
+		Action: runDbCommand(migrateUnifiedStorageToFile),
+	},
+	{
+		Name:   "export-unified-storage-namespace",
+		Usage:  "Exports the unified storage resources of a namespace, as they were at a resource version or a time, into a parquet snapshot",
+		Action: runDbCommand(exportUnifiedStorageNamespace),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "namespace",
+				Usage: "The namespace to export (eg, default or stacks-123)",
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "Path of the snapshot file, defaults to <namespace>-<timestamp>.parquet in the current directory",
+			},
+			&cli.StringFlag{
+				Name:  "resource-version",
+				Usage: "Export the namespace as it was at this resource version",
+			},
+			&cli.StringFlag{
+				Name:  "timestamp",
+				Usage: "Export the namespace as it was at this time (RFC3339), used when --resource-version is not set. Defaults to now",
+			},
+			&cli.StringSliceFlag{
+				Name:  "resource",
+				Usage: "The group/resource to export (eg, dashboard.grafana.app/dashboards). Defaults to all the resources of the namespace",
+			},
+		},
+	},
+	{
+		Name:   "restore-unified-storage-namespace",
+		Usage:  "Restores the unified storage resources of a namespace from a snapshot written by export-unified-storage-namespace, and prints the changes",
+		Action: runDbCommand(restoreUnifiedStorageNamespace),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "namespace",
+				Usage: "The namespace to restore",
+			},
+			&cli.StringFlag{
+				Name:  "input",
+				Usage: "Path of the snapshot file",
+			},
+			&cli.StringSliceFlag{
+				Name:  "resource",
+				Usage: "The group/resource to restore (eg, dashboard.grafana.app/dashboards). Defaults to the resources in the snapshot",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Only print the changes, nothing is written",
+			},
+		},
+	},
 	{
 		Name:   "flush-rbac-seed-assignment",
 		Usage:  "Clears RBAC seeding to force re-seeding on next startup. Use after running an Enterprise build, then an OSS build, then an Enterprise build again.",
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/sql"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db/dbimpl"
+)
+
+// migrateUnifiedStorageToFile copies the unified storage resources from the database into the embedded file storage
+func migrateUnifiedStorageToFile(c utils.CommandLine, cfg *setting.Cfg, sqlStore db.DB) error {
+	ctx := context.Background()
+
+	eDB, err := dbimpl.ProvideResourceDB(sqlStore, cfg, nil)
+	if err != nil {
+		return err
+	}
+	source, err := sql.NewBackend(sql.BackendOptions{
+		DBProvider:             eDB,
+		DisableStorageServices: true,
+	})
+	if err != nil {
+		return fmt.Errorf("open SQL storage: %w", err)
+	}
+
+	target, err := sql.NewFileBackend(cfg)
+	if err != nil {
+		return fmt.Errorf("open file storage: %w", err)
+	}
+	defer func() {
+		if stopper, ok := target.(resource.ResourceServerStopper); ok {
+			_ = stopper.Stop(ctx)
+		}
+	}()
+	bulk, ok := target.(resource.BulkProcessingBackend)
+	if !ok {
+		return fmt.Errorf("the file storage does not support bulk imports")
+	}
+
+	results, err := sql.MigrateToKV(ctx, source, bulk)
+	for _, result := range results {
+		logger.Infof("%s %s: %d revisions, %d blobs\n", color.GreenString("✔"), result.String(), result.Revisions, result.Blobs)
+		for _, rejected := range result.Rejected {
+			logger.Warnf("Skipped %s: %s\n", rejected.Key.Name, rejected.Error)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Info("\n")
+	logger.Info("Set storage_type = file in the [grafana-apiserver] section to use the migrated storage.\n")
+	return nil
+}
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/server"
+	"github.com/grafana/grafana/pkg/services/supportbundles/supportbundlesimpl"
+)
+
+func supportBundleCommand(c utils.CommandLine, runner server.Runner) error {
+	output := c.String("output")
+	if output == "" {
+		output = fmt.Sprintf("grafana-support-bundle-%s.tar.gz", time.Now().UTC().Format("2006-01-02T15-04-05"))
+		if supportbundlesimpl.IsEncrypted(runner.Cfg) {
+			output += ".age"
+		}
+	}
+
+	bundle, err := supportbundlesimpl.CreateOfflineBundle(context.Background(), runner.Cfg, runner.SettingsProvider, runner.SQLStore, c.StringSlice("collectors"))
+	if err != nil {
+		return fmt.Errorf("failed to create support bundle: %w", err)
+	}
+
+	if err := os.WriteFile(output, bundle, 0o600); err != nil {
+		return fmt.Errorf("failed to write support bundle: %w", err)
+	}
+
+	logger.Infof("Support bundle written to %s\n", output)
+	return nil
+}
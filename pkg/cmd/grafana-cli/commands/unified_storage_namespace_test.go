@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestParseNamespaceCollections(t *testing.T) {
+	collections, err := parseNamespaceCollections([]string{"dashboard.grafana.app/dashboards", "folder.grafana.app/folders"})
+	require.NoError(t, err)
+	require.Equal(t, []*resourcepb.ResourceKey{
+		{Group: "dashboard.grafana.app", Resource: "dashboards"},
+		{Group: "folder.grafana.app", Resource: "folders"},
+	}, collections)
+
+	collections, err = parseNamespaceCollections(nil)
+	require.NoError(t, err)
+	require.Empty(t, collections)
+
+	for _, invalid := range []string{"dashboards", "/dashboards", "dashboard.grafana.app/"} {
+		_, err = parseNamespaceCollections([]string{invalid})
+		require.Error(t, err, invalid)
+	}
+}
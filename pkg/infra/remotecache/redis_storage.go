@@ -83,11 +83,24 @@ func parseRedisConnStr(connStr string) (*redis.Options, error) {
 }
 
 func newRedisStorage(opts *setting.RemoteCacheSettings) (*redisStorage, error) {
+	c, err := NewRedisClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &redisStorage{c: c}, nil
+}
+
+// NewRedisClient creates a client for the Redis server configured in the remote cache settings,
+// for services that need Redis features beyond the CacheStorage interface.
+func NewRedisClient(opts *setting.RemoteCacheSettings) (*redis.Client, error) {
+	if opts == nil || opts.Name != redisCacheType {
+		return nil, errors.New("remote cache is not configured to use redis")
+	}
 	opt, err := parseRedisConnStr(opts.ConnStr)
 	if err != nil {
 		return nil, err
 	}
-	return &redisStorage{c: redis.NewClient(opt)}, nil
+	return redis.NewClient(opt), nil
 }
 
 // Set sets value to a given key
@@ -0,0 +1,86 @@
+package serverlock
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	resultAcquired = "acquired"
+	resultLocked   = "locked"
+	resultError    = "error"
+)
+
+// metrics describe which locks this instance holds. They are always created, but only registered by
+// ProvideServiceWithBackend.
+type metrics struct {
+	acquisitions  *prometheus.CounterVec
+	held          *prometheus.GaugeVec
+	fencingToken  *prometheus.GaugeVec
+	leaseRenewals *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		acquisitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "grafana",
+			Subsystem: "serverlock",
+			Name:      "acquisitions_total",
+			Help:      "Number of attempts to acquire a server lock, by result.",
+		}, []string{"backend", "action_name", "result"}),
+		held: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "grafana",
+			Subsystem: "serverlock",
+			Name:      "held",
+			Help:      "1 while this instance holds the server lock and executes its function, 0 otherwise.",
+		}, []string{"action_name"}),
+		fencingToken: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "grafana",
+			Subsystem: "serverlock",
+			Name:      "fencing_token",
+			Help:      "Fencing token of the last server lock acquired by this instance.",
+		}, []string{"action_name"}),
+		leaseRenewals: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "grafana",
+			Subsystem: "serverlock",
+			Name:      "lease_renewals_total",
+			Help:      "Number of attempts to renew the lease of a server lock held in Redis, by result.",
+		}, []string{"action_name", "result"}),
+	}
+}
+
+func (m *metrics) register(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{m.acquisitions, m.held, m.fencingToken, m.leaseRenewals} {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *metrics) observeAcquire(backend, actionName, result string) {
+	m.acquisitions.WithLabelValues(backend, actionName, result).Inc()
+}
+
+func (m *metrics) observeHeld(actionName string, token int64) {
+	m.held.WithLabelValues(actionName).Set(1)
+	if token != 0 {
+		m.fencingToken.WithLabelValues(actionName).Set(float64(token))
+	}
+}
+
+func (m *metrics) observeReleased(actionName string) {
+	m.held.WithLabelValues(actionName).Set(0)
+}
+
+func (m *metrics) observeRenewal(actionName string, err error) {
+	result := "renewed"
+	switch {
+	case errors.Is(err, errLeaseLost):
+		result = "lost"
+	case err != nil:
+		result = resultError
+	}
+	m.leaseRenewals.WithLabelValues(actionName, result).Inc()
+}
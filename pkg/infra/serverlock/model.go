@@ -1,5 +1,12 @@
 package serverlock
 
+import "context"
+
+const (
+	backendDatabase = "database"
+	backendRedis    = "redis"
+)
+
 type serverLock struct {
 	// nolint:stylecheck
 	Id            int64
@@ -7,3 +14,18 @@ type serverLock struct {
 	LastExecution int64
 	Version       int64
 }
+
+type fencingTokenKey struct{}
+
+func withFencingToken(ctx context.Context, token int64) context.Context {
+	return context.WithValue(ctx, fencingTokenKey{}, token)
+}
+
+// FencingToken returns the fencing token of the lock held while executing the function passed to
+// LockAndExecute, LockExecuteAndRelease or LockExecuteAndReleaseWithRetries. Tokens increase with every
+// acquisition of the same lock, so writes made with a token lower than the last one seen come from
+// a process that lost the lock. ok is false when the backend doesn't provide fencing tokens.
+func FencingToken(ctx context.Context) (token int64, ok bool) {
+	token, ok = ctx.Value(fencingTokenKey{}).(int64)
+	return token, ok && token > 0
+}
@@ -0,0 +1,148 @@
+package serverlock
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var errLeaseLost = errors.New("lock lease is held by another owner")
+
+// acquireScript sets the lock key if it doesn't exist yet, and returns the next fencing token if it was set.
+var acquireScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+// renewScript extends the lease of the lock key if it is still held by the given owner.
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript deletes the lock key if it is still held by the given owner.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// redisLocker stores server locks as Redis keys with a time to live.
+//
+// Every successful acquisition increments a per action counter, which is used as fencing token:
+// storage written by the lock holder can reject writes carrying a token lower than one it has seen.
+type redisLocker struct {
+	client        redis.Cmdable
+	prefix        string
+	instance      string
+	renewInterval time.Duration
+}
+
+func newRedisLocker(client redis.Cmdable, prefix string, renewInterval time.Duration) *redisLocker {
+	instance, err := os.Hostname()
+	if err != nil || instance == "" {
+		instance = "unknown"
+	}
+	return &redisLocker{
+		client:        client,
+		prefix:        prefix,
+		instance:      instance,
+		renewInterval: renewInterval,
+	}
+}
+
+func (r *redisLocker) key(actionName, suffix string) string {
+	return r.prefix + "serverlock:" + actionName + ":" + suffix
+}
+
+func (r *redisLocker) newOwner() string {
+	return r.instance + "/" + util.GenerateShortUID()
+}
+
+func (r *redisLocker) tryAcquire(ctx context.Context, key, actionName, owner string, ttl time.Duration) (int64, error) {
+	return acquireScript.Run(ctx, r.client, []string{key, r.key(actionName, "fence")}, owner, ttl.Milliseconds()).Int64()
+}
+
+// acquireInterval takes the lock of actionName for maxInterval without releasing it, which limits
+// executions to one per maxInterval.
+func (r *redisLocker) acquireInterval(ctx context.Context, actionName string, maxInterval time.Duration) (int64, bool, error) {
+	token, err := r.tryAcquire(ctx, r.key(actionName, "interval"), actionName, r.newOwner(), maxInterval)
+	if err != nil {
+		return 0, false, err
+	}
+	return token, token > 0, nil
+}
+
+// acquireLease takes the lock of actionName until it is released or its lease of ttl expires.
+func (r *redisLocker) acquireLease(ctx context.Context, actionName string, ttl time.Duration) (*heldLock, error) {
+	owner := r.newOwner()
+	token, err := r.tryAcquire(ctx, r.key(actionName, "lease"), actionName, owner, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if token == 0 {
+		return nil, &ServerLockExistsError{actionName: actionName}
+	}
+	return &heldLock{owner: owner, token: token}, nil
+}
+
+func (r *redisLocker) renewLease(ctx context.Context, actionName string, held *heldLock, ttl time.Duration) error {
+	renewed, err := renewScript.Run(ctx, r.client, []string{r.key(actionName, "lease")}, held.owner, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if renewed == 0 {
+		return errLeaseLost
+	}
+	return nil
+}
+
+func (r *redisLocker) releaseLease(ctx context.Context, actionName string, held *heldLock) error {
+	return releaseScript.Run(ctx, r.client, []string{r.key(actionName, "lease")}, held.owner).Err()
+}
+
+// keepAlive renews the lease of the held lock until the returned function is called or the context is done.
+// The result of every renewal is passed to onRenew. Renewals stop once the lease is lost.
+func (r *redisLocker) keepAlive(ctx context.Context, actionName string, held *heldLock, ttl time.Duration, onRenew func(error)) func() {
+	interval := r.renewInterval
+	if interval <= 0 || interval >= ttl {
+		interval = ttl / 3
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := r.renewLease(ctx, actionName, held, ttl)
+				onRenew(err)
+				if errors.Is(err, errLeaseLost) {
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
@@ -0,0 +1,145 @@
+package serverlock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func createRedisServerLock(t *testing.T, renewInterval time.Duration) (*ServerLockService, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return &ServerLockService{
+		tracer:  tracing.InitializeTracerForTest(),
+		log:     log.New("test-logger"),
+		metrics: newMetrics(),
+		redis:   newRedisLocker(client, "test:", renewInterval),
+	}, mr
+}
+
+func TestRedisServerLock_LockAndExecute(t *testing.T) {
+	sl, mr := createRedisServerLock(t, 0)
+	ctx := context.Background()
+
+	var tokens []int64
+	fn := func(ctx context.Context) {
+		token, ok := FencingToken(ctx)
+		require.True(t, ok)
+		tokens = append(tokens, token)
+	}
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, sl.LockAndExecute(ctx, "test-operation", time.Minute, fn))
+	}
+	require.Equal(t, []int64{1}, tokens)
+
+	mr.FastForward(time.Minute)
+	require.NoError(t, sl.LockAndExecute(ctx, "test-operation", time.Minute, fn))
+	require.Equal(t, []int64{1, 2}, tokens)
+}
+
+func TestRedisServerLock_LockExecuteAndRelease(t *testing.T) {
+	sl, mr := createRedisServerLock(t, 0)
+	ctx := context.Background()
+
+	var tokens []int64
+	err := sl.LockExecuteAndRelease(ctx, "test-operation", time.Minute, func(ctx context.Context) {
+		token, _ := FencingToken(ctx)
+		tokens = append(tokens, token)
+
+		// the lock is taken while the function is executing
+		err := sl.LockExecuteAndRelease(ctx, "test-operation", time.Minute, func(context.Context) {
+			t.Fatal("lock should not be acquired twice")
+		})
+		var lockedErr *ServerLockExistsError
+		require.True(t, errors.As(err, &lockedErr))
+	})
+	require.NoError(t, err)
+	assert.False(t, mr.Exists("test:serverlock:test-operation:lease"))
+
+	err = sl.LockExecuteAndRelease(ctx, "test-operation", time.Minute, func(ctx context.Context) {
+		token, _ := FencingToken(ctx)
+		tokens = append(tokens, token)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, tokens)
+}
+
+func TestRedisServerLock_LeaseRenewal(t *testing.T) {
+	sl, mr := createRedisServerLock(t, 20*time.Millisecond)
+	key := "test:serverlock:test-operation:lease"
+
+	t.Run("lease is renewed while the function executes", func(t *testing.T) {
+		err := sl.LockExecuteAndRelease(context.Background(), "test-operation", time.Second, func(ctx context.Context) {
+			mr.FastForward(900 * time.Millisecond)
+			require.Eventually(t, func() bool {
+				return mr.TTL(key) == time.Second
+			}, time.Second, 10*time.Millisecond)
+			require.NoError(t, ctx.Err())
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("context is cancelled when the lease is lost", func(t *testing.T) {
+		err := sl.LockExecuteAndRelease(context.Background(), "test-operation", time.Second, func(ctx context.Context) {
+			mr.Del(key)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+				t.Fatal("context should be cancelled after losing the lease")
+			}
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestRedisServerLock_LockExecuteAndReleaseWithRetries(t *testing.T) {
+	sl, _ := createRedisServerLock(t, 0)
+	ctx := context.Background()
+
+	timeConfig := LockTimeConfig{
+		MaxInterval: time.Second,
+		MinWait:     10 * time.Millisecond,
+		MaxWait:     20 * time.Millisecond,
+	}
+
+	acquired := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_ = sl.LockExecuteAndRelease(ctx, "test-operation", time.Second, func(context.Context) {
+			close(acquired)
+			<-release
+		})
+	}()
+	<-acquired
+
+	retries := 0
+	executed := false
+	err := sl.LockExecuteAndReleaseWithRetries(ctx, "test-operation", timeConfig, func(ctx context.Context) {
+		token, _ := FencingToken(ctx)
+		assert.Equal(t, int64(2), token)
+		executed = true
+	}, func(i int) error {
+		retries = i
+		if i == 3 {
+			close(release)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, executed)
+	assert.GreaterOrEqual(t, retries, 3)
+}
@@ -8,14 +8,17 @@ import (
 	"time"
 
 	"github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 func ProvideService(sqlStore db.DB, tracer tracing.Tracer) *ServerLockService {
@@ -23,9 +26,36 @@ func ProvideService(sqlStore db.DB, tracer tracing.Tracer) *ServerLockService {
 		SQLStore: sqlStore,
 		tracer:   tracer,
 		log:      log.New("infra.lockservice"),
+		metrics:  newMetrics(),
 	}
 }
 
+// ProvideServiceWithBackend creates a ServerLockService using the backend configured in the [server_lock] section.
+// The "redis" backend stores the locks in the Redis server configured as remote cache instead of the database.
+func ProvideServiceWithBackend(cfg *setting.Cfg, sqlStore db.DB, tracer tracing.Tracer, reg prometheus.Registerer) (*ServerLockService, error) {
+	sl := ProvideService(sqlStore, tracer)
+	if reg != nil {
+		if err := sl.metrics.register(reg); err != nil {
+			return nil, err
+		}
+	}
+
+	section := cfg.SectionWithEnvOverrides("server_lock")
+	switch backend := section.Key("backend").MustString(backendDatabase); backend {
+	case backendDatabase:
+	case backendRedis:
+		client, err := remotecache.NewRedisClient(cfg.RemoteCacheOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create redis client for server locks: %w", err)
+		}
+		sl.redis = newRedisLocker(client, cfg.RemoteCacheOptions.Prefix, section.Key("lease_renewal_interval").MustDuration(0))
+	default:
+		return nil, fmt.Errorf("unsupported server lock backend %q", backend)
+	}
+
+	return sl, nil
+}
+
 // ServerLockService allows servers in HA mode to claim a lock and execute a function if the server was granted the lock
 // It exposes 2 services LockAndExecute and LockExecuteAndRelease, which are intended to be used independently, don't mix
 // them up (ie, use the same actionName for both of them).
@@ -33,6 +63,17 @@ type ServerLockService struct {
 	SQLStore db.DB
 	tracer   tracing.Tracer
 	log      log.Logger
+	metrics  *metrics
+
+	// redis is set when locks are stored in Redis instead of the database.
+	redis *redisLocker
+}
+
+func (sl *ServerLockService) backend() string {
+	if sl.redis != nil {
+		return backendRedis
+	}
+	return backendDatabase
 }
 
 // LockAndExecute try to create a lock for this server and only executes the
@@ -47,6 +88,24 @@ func (sl *ServerLockService) LockAndExecute(ctx context.Context, actionName stri
 	ctxLogger := sl.log.FromContext(ctx)
 	ctxLogger.Debug("Start LockAndExecute", "actionName", actionName)
 
+	if sl.redis != nil {
+		token, acquiredLock, err := sl.redis.acquireInterval(ctx, actionName, maxInterval)
+		if err != nil {
+			sl.metrics.observeAcquire(sl.backend(), actionName, resultError)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, fmt.Sprintf("failed to acquire serverlock: %v", err))
+			return err
+		}
+		if acquiredLock {
+			sl.metrics.observeAcquire(sl.backend(), actionName, resultAcquired)
+			sl.executeFunc(withFencingToken(ctx, token), actionName, fn)
+		} else {
+			sl.metrics.observeAcquire(sl.backend(), actionName, resultLocked)
+		}
+		ctxLogger.Debug("LockAndExecute finished", "actionName", actionName, "acquiredLock", acquiredLock, "duration", time.Since(start))
+		return nil
+	}
+
 	// gets or creates a lockable row
 	rowLock, err := sl.getOrCreate(ctx, actionName)
 	if err != nil {
@@ -57,19 +116,25 @@ func (sl *ServerLockService) LockAndExecute(ctx context.Context, actionName stri
 
 	// avoid execution if last lock happened less than `maxInterval` ago
 	if sl.isLockWithinInterval(rowLock, maxInterval) {
+		sl.metrics.observeAcquire(sl.backend(), actionName, resultLocked)
 		return nil
 	}
 
 	// try to get lock based on rowLock version
 	acquiredLock, err := sl.acquireLock(ctx, rowLock)
 	if err != nil {
+		sl.metrics.observeAcquire(sl.backend(), actionName, resultError)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, fmt.Sprintf("failed to acquire serverlock: %v", err))
 		return err
 	}
 
 	if acquiredLock {
-		sl.executeFunc(ctx, actionName, fn)
+		sl.metrics.observeAcquire(sl.backend(), actionName, resultAcquired)
+		// the version we updated the row to is increased by every successful acquisition
+		sl.executeFunc(withFencingToken(ctx, rowLock.Version+1), actionName, fn)
+	} else {
+		sl.metrics.observeAcquire(sl.backend(), actionName, resultLocked)
 	}
 
 	ctxLogger.Debug("LockAndExecute finished", "actionName", actionName, "acquiredLock", acquiredLock, "duration", time.Since(start))
@@ -148,7 +213,7 @@ func (sl *ServerLockService) LockExecuteAndRelease(ctx context.Context, actionNa
 	ctxLogger := sl.log.FromContext(ctx)
 	ctxLogger.Debug("Start LockExecuteAndRelease", "actionName", actionName)
 
-	err := sl.acquireForRelease(ctx, actionName, maxInterval)
+	held, err := sl.acquire(ctx, actionName, maxInterval)
 	// could not get the lock, returning
 	if err != nil {
 		span.RecordError(err)
@@ -156,9 +221,9 @@ func (sl *ServerLockService) LockExecuteAndRelease(ctx context.Context, actionNa
 		return err
 	}
 
-	sl.executeFunc(ctx, actionName, fn)
+	sl.executeHeld(ctx, actionName, maxInterval, held, fn)
 
-	err = sl.releaseLock(ctx, actionName)
+	err = sl.release(ctx, actionName, held)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, fmt.Sprintf("failed to release serverlock: %v", err))
@@ -192,9 +257,11 @@ func (sl *ServerLockService) LockExecuteAndReleaseWithRetries(ctx context.Contex
 
 	lockChecks := 0
 
+	var held *heldLock
 	for {
 		lockChecks++
-		err := sl.acquireForRelease(ctx, actionName, timeConfig.MaxInterval)
+		var err error
+		held, err = sl.acquire(ctx, actionName, timeConfig.MaxInterval)
 		// could not get the lock
 		if err != nil {
 			var lockedErr *ServerLockExistsError
@@ -223,9 +290,9 @@ func (sl *ServerLockService) LockExecuteAndReleaseWithRetries(ctx context.Contex
 		break
 	}
 
-	sl.executeFunc(ctx, actionName, fn)
+	sl.executeHeld(ctx, actionName, timeConfig.MaxInterval, held, fn)
 
-	if err := sl.releaseLock(ctx, actionName); err != nil {
+	if err := sl.release(ctx, actionName, held); err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, fmt.Sprintf("failed to release serverlock: %v", err))
 		ctxLogger.Error("Failed to release the lock", "error", err)
@@ -241,6 +308,73 @@ func lockWait(minWait time.Duration, maxWait time.Duration) time.Duration {
 	return time.Duration(rand.Int63n(int64(maxWait-minWait)) + int64(minWait))
 }
 
+// heldLock is a lock acquired for LockExecuteAndRelease and LockExecuteAndReleaseWithRetries.
+type heldLock struct {
+	// owner identifies the holder of a Redis lease. It is empty for database locks.
+	owner string
+	// token is the fencing token of the lock, or zero if the backend doesn't provide one.
+	token int64
+}
+
+// acquire takes the lock of actionName with the configured backend. It returns a ServerLockExistsError
+// if another process holds the lock.
+func (sl *ServerLockService) acquire(ctx context.Context, actionName string, maxInterval time.Duration) (*heldLock, error) {
+	var held *heldLock
+	var err error
+	if sl.redis != nil {
+		held, err = sl.redis.acquireLease(ctx, actionName, maxInterval)
+	} else {
+		err = sl.acquireForRelease(ctx, actionName, maxInterval)
+		held = &heldLock{}
+	}
+
+	var lockedErr *ServerLockExistsError
+	switch {
+	case err == nil:
+		sl.metrics.observeAcquire(sl.backend(), actionName, resultAcquired)
+	case errors.As(err, &lockedErr):
+		sl.metrics.observeAcquire(sl.backend(), actionName, resultLocked)
+	default:
+		sl.metrics.observeAcquire(sl.backend(), actionName, resultError)
+	}
+	return held, err
+}
+
+// executeHeld executes fn while holding the lock. Redis leases are renewed until fn returns,
+// and the context passed to fn is cancelled if the lease is lost.
+func (sl *ServerLockService) executeHeld(ctx context.Context, actionName string, maxInterval time.Duration, held *heldLock, fn func(ctx context.Context)) {
+	if held.token != 0 {
+		ctx = withFencingToken(ctx, held.token)
+	}
+	if sl.redis == nil {
+		sl.executeFunc(ctx, actionName, fn)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := sl.redis.keepAlive(ctx, actionName, held, maxInterval, func(err error) {
+		sl.metrics.observeRenewal(actionName, err)
+		if err != nil {
+			sl.log.FromContext(ctx).Warn("Failed to renew lock lease", "actionName", actionName, "error", err)
+		}
+		if errors.Is(err, errLeaseLost) {
+			cancel()
+		}
+	})
+	defer stop()
+
+	sl.executeFunc(ctx, actionName, fn)
+}
+
+// release releases a lock taken with acquire.
+func (sl *ServerLockService) release(ctx context.Context, actionName string, held *heldLock) error {
+	if sl.redis != nil {
+		return sl.redis.releaseLease(context.WithoutCancel(ctx), actionName, held)
+	}
+	return sl.releaseLock(ctx, actionName)
+}
+
 // acquireForRelease will check if the lock is already on the database, if it is, will check with maxInterval if it is
 // timeouted. Returns nil error if the lock was acquired correctly
 func (sl *ServerLockService) acquireForRelease(ctx context.Context, actionName string, maxInterval time.Duration) error {
@@ -349,6 +483,10 @@ func (sl *ServerLockService) executeFunc(ctx context.Context, actionName string,
 	ctxLogger := sl.log.FromContext(ctx)
 	ctxLogger.Debug("Start execution", "actionName", actionName)
 
+	token, _ := FencingToken(ctx)
+	sl.metrics.observeHeld(actionName, token)
+	defer sl.metrics.observeReleased(actionName)
+
 	fn(ctx)
 
 	ctxLogger.Debug("Execution finished", "actionName", actionName, "duration", time.Since(start))
@@ -28,6 +28,7 @@ func createTestableServerLock(t *testing.T) *ServerLockService {
 		SQLStore: store,
 		tracer:   tracing.InitializeTracerForTest(),
 		log:      log.New("test-logger"),
+		metrics:  newMetrics(),
 	}
 }
 
@@ -144,3 +145,20 @@ func TestIntegrationLockAndRelease(t *testing.T) {
 		require.NoError(t, err3)
 	})
 }
+
+func TestIntegrationLockAndExecuteFencingToken(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	sl := createTestableServerLock(t)
+
+	var tokens []int64
+	fn := func(ctx context.Context) {
+		token, ok := FencingToken(ctx)
+		require.True(t, ok)
+		tokens = append(tokens, token)
+	}
+
+	require.NoError(t, sl.LockAndExecute(context.Background(), "test-operation-fencing", 0, fn))
+	require.NoError(t, sl.LockAndExecute(context.Background(), "test-operation-fencing", 0, fn))
+	require.Equal(t, []int64{1, 2}, tokens)
+}
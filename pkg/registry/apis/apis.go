@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning"
 	"github.com/grafana/grafana/pkg/registry/apis/query"
 	"github.com/grafana/grafana/pkg/registry/apis/secret"
+	"github.com/grafana/grafana/pkg/registry/apis/unifiedstorage"
 	"github.com/grafana/grafana/pkg/registry/apis/userstorage"
 )
 
@@ -26,6 +27,7 @@ func ProvideRegistryServiceSink(
 	_ *iam.IdentityAccessManagementAPIBuilder,
 	_ *query.QueryAPIBuilder,
 	_ *userstorage.UserStorageAPIBuilder,
+	_ *unifiedstorage.StorageAPIBuilder,
 	_ *preferences.APIBuilder,
 	_ *collections.APIBuilder,
 	_ *provisioning.APIBuilder,
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"maps"
 	"strconv"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -17,6 +18,7 @@ import (
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/apiserver/pkg/registry/rest"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/warning"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/kube-openapi/pkg/common"
 	"k8s.io/kube-openapi/pkg/spec3"
@@ -314,6 +316,9 @@ func (b *DashboardsAPIBuilder) Validate(ctx context.Context, a admission.Attribu
 		}
 
 	case dashv0.LIBRARY_PANEL_RESOURCE:
+		if op == admission.Delete && a.GetName() != "" {
+			b.WarnDependents(ctx, a.GetNamespace(), "LibraryPanel", a.GetName())
+		}
 		return nil // OK for now
 	case dashv0.SNAPSHOT_RESOURCE:
 		return nil // OK for now
@@ -373,6 +378,30 @@ func (b *DashboardsAPIBuilder) validateDelete(ctx context.Context, a admission.A
 	return nil
 }
 
+// WarnDependents adds a warning to the response when other resources still reference the deleted resource.
+// The delete is not blocked: the dependents will show the resource as missing.
+// It is also used by the data source APIs, which do not have access to the search index.
+func (b *DashboardsAPIBuilder) WarnDependents(ctx context.Context, namespace string, kind string, name string) {
+	if b == nil || b.search == nil {
+		return
+	}
+
+	graph, err := b.search.getImpactGraph(ctx, namespace, kind, name)
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to check the dependents of the deleted resource", "kind", kind, "name", name, "error", err)
+		return
+	}
+	if len(graph.Dependents) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(graph.Dependents))
+	for _, dependent := range graph.Dependents {
+		names = append(names, impactNodeID(dependent.Kind, dependent.Name))
+	}
+	warning.AddWarning(ctx, "", fmt.Sprintf("%s %s is referenced by %d resources: %s", kind, name, len(names), strings.Join(names, ", ")))
+}
+
 // validateCreate validates dashboard creation
 func (b *DashboardsAPIBuilder) validateCreate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
 	// Get the dashboard object
@@ -1016,7 +1045,7 @@ func (b *DashboardsAPIBuilder) PostProcessOpenAPI(oas *spec3.OpenAPI) (*spec3.Op
 		defs := b.GetOpenAPIDefinitions()(func(path string) spec.Ref { return spec.Ref{} })
 		refsBase := dashv0.OpenAPIPrefix
 
-		kinds := []string{"SearchResults", "DashboardHit", "ManagedBy", "FacetResult", "TermFacet", "SortBy"}
+		kinds := []string{"SearchResults", "DashboardHit", "ManagedBy", "FacetResult", "TermFacet", "SortBy", "ImpactGraph", "ImpactNode", "ImpactEdge", "PanelHit"}
 
 		// Add any missing definitions
 		//-----------------------------
@@ -1037,10 +1066,25 @@ func (b *DashboardsAPIBuilder) PostProcessOpenAPI(oas *spec3.OpenAPI) (*spec3.Op
 				case "DashboardHit":
 					v.Schema.Properties["managedBy"] = *spec.RefProperty(
 						"#/components/schemas/ManagedBy")
+					v.Schema.Properties["panels"] = *spec.ArrayProperty(
+						spec.RefProperty("#/components/schemas/PanelHit"),
+					)
 				case "FacetResult":
 					v.Schema.Properties["terms"] = *spec.ArrayProperty(
 						spec.RefProperty("#/components/schemas/TermFacet"),
 					)
+				case "ImpactGraph":
+					v.Schema.Properties["target"] = *spec.RefProperty(
+						"#/components/schemas/ImpactNode")
+					v.Schema.Properties["dependents"] = *spec.ArrayProperty(
+						spec.RefProperty("#/components/schemas/ImpactNode"),
+					)
+					v.Schema.Properties["dependencies"] = *spec.ArrayProperty(
+						spec.RefProperty("#/components/schemas/ImpactNode"),
+					)
+					v.Schema.Properties["edges"] = *spec.ArrayProperty(
+						spec.RefProperty("#/components/schemas/ImpactEdge"),
+					)
 				}
 				oas.Components.Schemas[k] = &v.Schema // use the short key (without the full package path)
 			}
@@ -1058,6 +1102,19 @@ func (b *DashboardsAPIBuilder) PostProcessOpenAPI(oas *spec3.OpenAPI) (*spec3.Op
 				},
 			},
 		}
+
+		p = oas.Paths.Paths["/apis/dashboard.grafana.app/v0alpha1/namespaces/{namespace}/search/impact"]
+		p.Get.Responses.StatusCodeResponses[200] = &spec3.Response{
+			ResponseProps: spec3.ResponseProps{
+				Content: map[string]*spec3.MediaType{
+					"application/json": {
+						MediaTypeProps: spec3.MediaTypeProps{
+							Schema: spec.RefSchema("#/components/schemas/ImpactGraph"),
+						},
+					},
+				},
+			},
+		}
 	}
 
 	return oas, nil
@@ -55,6 +55,7 @@ func NewSearchHandler(tracer trace.Tracer, resourceClient resource.ResourceClien
 func (s *SearchHandler) GetAPIRoutes(defs map[string]common.OpenAPIDefinition) *builder.APIRoutes {
 	searchResults := defs[dashboardv0alpha1.SearchResults{}.OpenAPIModelName()].Schema
 	sortableFields := defs[dashboardv0alpha1.SortableFields{}.OpenAPIModelName()].Schema
+	impactGraph := defs[dashboardv0alpha1.ImpactGraph{}.OpenAPIModelName()].Schema
 
 	return &builder.APIRoutes{
 		Namespace: []builder.APIRouteHandler{
@@ -158,6 +159,33 @@ func (s *SearchHandler) GetAPIRoutes(defs map[string]common.OpenAPIDefinition) *
 										Schema:      spec.StringProperty(),
 									},
 								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "panelQuery",
+										In:          "query",
+										Description: "find dashboards with panels querying the given text (eg, a metric name)",
+										Required:    false,
+										Schema:      spec.StringProperty(),
+									},
+								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "panelDataSource",
+										In:          "query",
+										Description: "find dashboards with panels using a given datasource UID",
+										Required:    false,
+										Schema:      spec.StringProperty(),
+									},
+								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "panelHits",
+										In:          "query",
+										Description: "include the panels matching panelType, panelQuery and panelDataSource in each hit",
+										Required:    false,
+										Schema:      spec.BooleanProperty(),
+									},
+								},
 								{
 									ParameterProps: spec3.ParameterProps{
 										Name:        "permission",
@@ -324,6 +352,66 @@ func (s *SearchHandler) GetAPIRoutes(defs map[string]common.OpenAPIDefinition) *
 				},
 				Handler: s.DoSortable,
 			},
+			{
+				Path: "search/impact",
+				Spec: &spec3.PathProps{
+					Get: &spec3.Operation{
+						OperationProps: spec3.OperationProps{
+							Tags:        []string{"Search"},
+							OperationId: "getImpactGraph",
+							Description: "Get the resources that depend on a resource, and the resources it depends on",
+							Parameters: []*spec3.Parameter{
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "namespace",
+										In:          "path",
+										Required:    true,
+										Example:     "default",
+										Description: "workspace",
+										Schema:      spec.StringProperty(),
+									},
+								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "kind",
+										In:          "query",
+										Description: "the kind of the resource",
+										Required:    true,
+										Schema:      spec.StringProperty().WithEnum("DataSource", "LibraryPanel", "Dashboard", "AlertRule", "Correlation"),
+									},
+								},
+								{
+									ParameterProps: spec3.ParameterProps{
+										Name:        "name",
+										In:          "query",
+										Description: "the k8s name (eg, grafana UID) of the resource",
+										Required:    true,
+										Schema:      spec.StringProperty(),
+									},
+								},
+							},
+							Responses: &spec3.Responses{
+								ResponsesProps: spec3.ResponsesProps{
+									StatusCodeResponses: map[int]*spec3.Response{
+										200: {
+											ResponseProps: spec3.ResponseProps{
+												Content: map[string]*spec3.MediaType{
+													"application/json": {
+														MediaTypeProps: spec3.MediaTypeProps{
+															Schema: &impactGraph,
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				Handler: s.DoImpact,
+			},
 		},
 	}
 }
@@ -344,6 +432,9 @@ func (s *SearchHandler) DoSortable(w http.ResponseWriter, r *http.Request) {
 
 const rootFolder = "general"
 
+// The maximum number of dashboards read from the index when the hits are filtered by their panels
+const maxPanelHitsCandidates = 5000
+
 var errEmptyResults = fmt.Errorf("empty results")
 
 func permissionToActions(p dashboardaccess.PermissionType) (dashboardAction string, folderAction string) {
@@ -402,6 +493,15 @@ func (s *SearchHandler) DoSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Hits without matching panels are dropped, so the page is taken after filtering the panels
+	filterPanels := queryParams.Get("panelHits") == "true" && hasPanelFilters(queryParams)
+	offset, limit := searchRequest.Offset, searchRequest.Limit
+	if filterPanels {
+		searchRequest.Offset = 0
+		searchRequest.Page = 1
+		searchRequest.Limit = maxPanelHitsCandidates
+	}
+
 	result, err := s.client.Search(ctx, searchRequest)
 	if err != nil {
 		errhttp.Write(ctx, err, w)
@@ -410,6 +510,9 @@ func (s *SearchHandler) DoSearch(w http.ResponseWriter, r *http.Request) {
 
 	if result != nil {
 		s.log.Debug("search result hits and cost", "total_hits", result.TotalHits, "query_cost", result.QueryCost)
+		if filterPanels && result.TotalHits > maxPanelHitsCandidates {
+			s.log.Warn("too many dashboards match the panel filters, only the first ones are filtered", "total_hits", result.TotalHits, "max", maxPanelHitsCandidates)
+		}
 	}
 
 	parsedResults, err := dashboardsearch.ParseResults(result, searchRequest.Offset)
@@ -418,6 +521,15 @@ func (s *SearchHandler) DoSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if queryParams.Get("panelHits") == "true" {
+		parsedResults.Hits = filterPanelHits(parsedResults.Hits, queryParams)
+	}
+	if filterPanels {
+		parsedResults.TotalHits = int64(len(parsedResults.Hits))
+		parsedResults.Offset = offset
+		parsedResults.Hits = pageHits(parsedResults.Hits, offset, limit)
+	}
+
 	if len(searchRequest.SortBy) == 0 {
 		// default sort by resource descending ( folders then dashboards ) then title
 		sort.Slice(parsedResults.Hits, func(i, j int) bool {
@@ -468,6 +580,9 @@ func convertHttpSearchRequestToResourceSearchRequest(queryParams url.Values, use
 			}
 		}
 	}
+	if queryParams.Get("panelHits") == "true" {
+		fields = append(fields, resource.SEARCH_FIELD_PANELS_INFO)
+	}
 	searchRequest.Fields = fields
 	accessPermission := permissionFromQueryParams(queryParams)
 	if accessPermission > 0 {
@@ -551,6 +666,22 @@ func convertHttpSearchRequestToResourceSearchRequest(queryParams url.Values, use
 		})
 	}
 
+	if v, ok := queryParams["panelQuery"]; ok {
+		searchRequest.Options.Fields = append(searchRequest.Options.Fields, &resourcepb.Requirement{
+			Key:      resource.SEARCH_FIELD_PANEL_QUERY,
+			Operator: "=",
+			Values:   v,
+		})
+	}
+
+	if v, ok := queryParams["panelDataSource"]; ok {
+		searchRequest.Options.Fields = append(searchRequest.Options.Fields, &resourcepb.Requirement{
+			Key:      resource.SEARCH_FIELD_PANEL_DATASOURCE,
+			Operator: "=",
+			Values:   v,
+		})
+	}
+
 	if v, ok := queryParams["libraryPanel"]; ok {
 		searchRequest.Options.Fields = append(searchRequest.Options.Fields, &resourcepb.Requirement{
 			Key:      builders.DASHBOARD_LIBRARY_PANEL_REFERENCE,
@@ -646,6 +777,71 @@ func convertHttpSearchRequestToResourceSearchRequest(queryParams url.Values, use
 	return searchRequest, nil
 }
 
+// filterPanelHits keeps the panels that match all the panel filters. The index flattens the panel
+// values, so a dashboard matches when any of its panels match each filter, not necessarily the same panel.
+// When panel filters are set, the hits without a matching panel are dropped.
+func filterPanelHits(hits []dashboardv0alpha1.DashboardHit, queryParams url.Values) []dashboardv0alpha1.DashboardHit {
+	panelTypes := queryParams["panelType"]
+	dataSources := queryParams["panelDataSource"]
+	queries := queryParams["panelQuery"]
+	filtered := hasPanelFilters(queryParams)
+
+	result := make([]dashboardv0alpha1.DashboardHit, 0, len(hits))
+	for _, hit := range hits {
+		panels := []dashboardv0alpha1.PanelHit{}
+		for _, panel := range hit.Panels {
+			if len(panelTypes) > 0 && !slices.Contains(panelTypes, panel.Type) {
+				continue
+			}
+			if len(dataSources) > 0 && !slices.ContainsFunc(panel.DataSources, func(ds string) bool {
+				return slices.Contains(dataSources, ds)
+			}) {
+				continue
+			}
+			if !matchesPanelQueries(panel.Queries, queries) {
+				continue
+			}
+			panels = append(panels, panel)
+		}
+		if filtered && len(panels) == 0 {
+			continue
+		}
+		hit.Panels = panels
+		result = append(result, hit)
+	}
+	return result
+}
+
+// hasPanelFilters checks if the hits are filtered by their panels
+func hasPanelFilters(queryParams url.Values) bool {
+	return queryParams.Has("panelType") || queryParams.Has("panelDataSource") || queryParams.Has("panelQuery")
+}
+
+// pageHits returns the hits of the requested page
+func pageHits(hits []dashboardv0alpha1.DashboardHit, offset int64, limit int64) []dashboardv0alpha1.DashboardHit {
+	if offset >= int64(len(hits)) {
+		return []dashboardv0alpha1.DashboardHit{}
+	}
+	hits = hits[max(offset, 0):]
+	if limit > 0 && limit < int64(len(hits)) {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// matchesPanelQueries checks that every search term is found (case insensitive) in one of the panel queries
+func matchesPanelQueries(panelQueries []string, terms []string) bool {
+	for _, term := range terms {
+		term = strings.ToLower(term)
+		if !slices.ContainsFunc(panelQueries, func(q string) bool {
+			return strings.Contains(strings.ToLower(q), term)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *SearchHandler) write(w http.ResponseWriter, obj any) {
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(obj)
@@ -0,0 +1,272 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	alertingv0 "github.com/grafana/grafana/apps/alerting/rules/pkg/apis/alerting/v0alpha1"
+	correlationv0 "github.com/grafana/grafana/apps/correlations/pkg/apis/correlation/v0alpha1"
+	dashboardv0alpha1 "github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/util/errhttp"
+)
+
+const (
+	impactKindDataSource = "DataSource"
+
+	// the relation between a resource and the resources it references
+	impactRelation = "depends-on"
+
+	// the maximum number of dependents returned for a single resource
+	impactSearchLimit = 1000
+)
+
+// impactSource is an indexed resource that references other resources
+type impactSource struct {
+	kind     string
+	group    string
+	resource string
+
+	// The kinds referenced by this resource
+	references []string
+}
+
+var impactSources = []impactSource{
+	{
+		kind:       "Dashboard",
+		group:      dashboardv0alpha1.GROUP,
+		resource:   dashboardv0alpha1.DASHBOARD_RESOURCE,
+		references: []string{impactKindDataSource, "LibraryPanel"},
+	},
+	{
+		kind:       "LibraryPanel",
+		group:      dashboardv0alpha1.GROUP,
+		resource:   dashboardv0alpha1.LIBRARY_PANEL_RESOURCE,
+		references: []string{impactKindDataSource},
+	},
+	{
+		kind:       alertingv0.AlertRuleKind().Kind(),
+		group:      alertingv0.AlertRuleKind().Group(),
+		resource:   alertingv0.AlertRuleKind().Plural(),
+		references: []string{impactKindDataSource},
+	},
+	{
+		kind:       correlationv0.CorrelationKind().Kind(),
+		group:      correlationv0.CorrelationKind().Group(),
+		resource:   correlationv0.CorrelationKind().Plural(),
+		references: []string{impactKindDataSource},
+	},
+}
+
+func getImpactSource(kind string) (impactSource, bool) {
+	idx := slices.IndexFunc(impactSources, func(src impactSource) bool {
+		return src.kind == kind
+	})
+	if idx < 0 {
+		return impactSource{}, false
+	}
+	return impactSources[idx], true
+}
+
+func impactNodeID(kind, name string) string {
+	return kind + "/" + name
+}
+
+func (s *SearchHandler) DoImpact(w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.tracer.Start(r.Context(), "dashboard.search.impact")
+	defer span.End()
+
+	user, err := identity.GetRequester(ctx)
+	if err != nil {
+		errhttp.Write(ctx, err, w)
+		return
+	}
+
+	queryParams := r.URL.Query()
+	graph, err := s.getImpactGraph(ctx, user.GetNamespace(), queryParams.Get("kind"), queryParams.Get("name"))
+	if err != nil {
+		errhttp.Write(ctx, err, w)
+		return
+	}
+	s.write(w, graph)
+}
+
+// getImpactGraph walks the references in the search index in both directions: the resources that
+// depend on the target (eg, the dashboards using a data source through a library panel), and the
+// resources the target depends on. Only the resources visible to the user are included.
+func (s *SearchHandler) getImpactGraph(ctx context.Context, namespace string, kind string, name string) (*dashboardv0alpha1.ImpactGraph, error) {
+	if name == "" {
+		return nil, apierrors.NewBadRequest("missing name")
+	}
+	if _, ok := getImpactSource(kind); !ok && kind != impactKindDataSource {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("unsupported kind: %q", kind))
+	}
+
+	graph := &dashboardv0alpha1.ImpactGraph{
+		TypeMeta: v1.TypeMeta{
+			APIVersion: dashboardv0alpha1.APIVERSION,
+			Kind:       "ImpactGraph",
+		},
+		Target:       dashboardv0alpha1.ImpactNode{Kind: kind, Name: name},
+		Dependents:   []dashboardv0alpha1.ImpactNode{},
+		Dependencies: []dashboardv0alpha1.ImpactNode{},
+		Edges:        []dashboardv0alpha1.ImpactEdge{},
+	}
+
+	// Resources that depend on the target
+	visited := map[string]bool{impactNodeID(kind, name): true}
+	queue := []dashboardv0alpha1.ImpactNode{graph.Target}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, src := range impactSources {
+			if !slices.Contains(src.references, node.Kind) {
+				continue
+			}
+			dependents, err := s.searchImpact(ctx, namespace, src, &resourcepb.Requirement{
+				Key:      "reference." + node.Kind,
+				Operator: "=",
+				Values:   []string{node.Name},
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, dependent := range dependents {
+				id := impactNodeID(dependent.node.Kind, dependent.node.Name)
+				graph.Edges = append(graph.Edges, dashboardv0alpha1.ImpactEdge{
+					From:     id,
+					To:       impactNodeID(node.Kind, node.Name),
+					Relation: impactRelation,
+				})
+				if !visited[id] {
+					visited[id] = true
+					graph.Dependents = append(graph.Dependents, dependent.node)
+					queue = append(queue, dependent.node)
+				}
+			}
+		}
+	}
+
+	// Resources the target depends on
+	visited = map[string]bool{impactNodeID(kind, name): true}
+	queue = []dashboardv0alpha1.ImpactNode{graph.Target}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		src, ok := getImpactSource(node.Kind)
+		if !ok {
+			continue // does not reference anything
+		}
+		found, err := s.searchImpact(ctx, namespace, src, &resourcepb.Requirement{
+			Key:      "name",
+			Operator: "=",
+			Values:   []string{node.Name},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			continue
+		}
+		if node.Kind == kind && node.Name == name {
+			graph.Target = found[0].node
+		}
+
+		for _, ref := range found[0].references {
+			id := impactNodeID(ref.Kind, ref.Name)
+			graph.Edges = append(graph.Edges, dashboardv0alpha1.ImpactEdge{
+				From:     impactNodeID(node.Kind, node.Name),
+				To:       id,
+				Relation: impactRelation,
+			})
+			if !visited[id] {
+				visited[id] = true
+				graph.Dependencies = append(graph.Dependencies, ref)
+				queue = append(queue, ref)
+			}
+		}
+	}
+
+	return graph, nil
+}
+
+type impactSearchResult struct {
+	node       dashboardv0alpha1.ImpactNode
+	references []dashboardv0alpha1.ImpactNode
+}
+
+// searchImpact finds the resources of an impact source that match the requirement, along with the resources they reference
+func (s *SearchHandler) searchImpact(ctx context.Context, namespace string, src impactSource, requirement *resourcepb.Requirement) ([]impactSearchResult, error) {
+	fields := []string{resource.SEARCH_FIELD_TITLE, resource.SEARCH_FIELD_FOLDER}
+	for _, ref := range src.references {
+		fields = append(fields, "reference."+ref)
+	}
+
+	rsp, err := s.client.Search(ctx, &resourcepb.ResourceSearchRequest{
+		Options: &resourcepb.ListOptions{
+			Key: &resourcepb.ResourceKey{
+				Namespace: namespace,
+				Group:     src.group,
+				Resource:  src.resource,
+			},
+			Fields: []*resourcepb.Requirement{requirement},
+		},
+		Fields: fields,
+		Limit:  impactSearchLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rsp.GetError() != nil {
+		return nil, resource.GetError(rsp.Error)
+	}
+	if rsp.GetResults() == nil {
+		return nil, nil
+	}
+
+	results := make([]impactSearchResult, 0, len(rsp.Results.Rows))
+	for _, row := range rsp.Results.Rows {
+		result := impactSearchResult{
+			node: dashboardv0alpha1.ImpactNode{
+				Kind: src.kind,
+				Name: row.Key.Name,
+			},
+		}
+		for i, col := range rsp.Results.Columns {
+			if i >= len(row.Cells) || len(row.Cells[i]) == 0 {
+				continue
+			}
+			switch col.Name {
+			case resource.SEARCH_FIELD_TITLE:
+				result.node.Title = string(row.Cells[i])
+			case resource.SEARCH_FIELD_FOLDER:
+				result.node.Folder = string(row.Cells[i])
+			default:
+				refKind, ok := strings.CutPrefix(col.Name, "reference.")
+				if !ok {
+					continue
+				}
+				names := []string{}
+				if err := json.Unmarshal(row.Cells[i], &names); err != nil {
+					return nil, fmt.Errorf("invalid references in %s: %w", col.Name, err)
+				}
+				for _, refName := range names {
+					result.references = append(result.references, dashboardv0alpha1.ImpactNode{Kind: refKind, Name: refName})
+				}
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
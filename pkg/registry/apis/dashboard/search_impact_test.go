@@ -0,0 +1,141 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestSearchHandlerImpact(t *testing.T) {
+	doImpact := func(t *testing.T, mockClient *MockClient, query string) *httptest.ResponseRecorder {
+		searchHandler := SearchHandler{
+			log:    log.New("test", "test"),
+			client: mockClient,
+			tracer: tracing.NewNoopTracerService(),
+		}
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/search/impact?"+query, nil)
+		req = req.WithContext(identity.WithRequester(req.Context(), &user.SignedInUser{Namespace: "default"}))
+		searchHandler.DoImpact(rr, req)
+		return rr
+	}
+
+	t.Run("requires a supported kind", func(t *testing.T) {
+		mockClient := &MockClient{}
+		rr := doImpact(t, mockClient, "kind=Playlist&name=abc")
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.Equal(t, 0, mockClient.CallCount)
+	})
+
+	t.Run("finds the dependents of a data source", func(t *testing.T) {
+		mockClient := &MockClient{
+			MockResponses: []*resourcepb.ResourceSearchResponse{
+				impactResponse(impactRow{name: "d1", title: "Dashboard 1"}),      // dashboards using the data source
+				impactResponse(impactRow{name: "lp1", title: "Library panel 1"}), // library panels using the data source
+				impactResponse(impactRow{name: "r1", title: "Alert rule 1"}),     // alert rules using the data source
+				impactResponse(), // correlations using the data source
+				impactResponse(impactRow{name: "d1"}, impactRow{name: "d2"}), // dashboards using the library panel
+			},
+		}
+		rr := doImpact(t, mockClient, "kind=DataSource&name=ds1")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, 5, mockClient.CallCount)
+
+		require.Equal(t, "reference.DataSource", mockClient.MockCalls[0].Options.Fields[0].Key)
+		require.Equal(t, "dashboards", mockClient.MockCalls[0].Options.Key.Resource)
+		require.Equal(t, "reference.LibraryPanel", mockClient.MockCalls[4].Options.Fields[0].Key)
+		require.Equal(t, []string{"lp1"}, mockClient.MockCalls[4].Options.Fields[0].Values)
+
+		graph := &v0alpha1.ImpactGraph{}
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(graph))
+		require.Equal(t, v0alpha1.ImpactNode{Kind: "DataSource", Name: "ds1"}, graph.Target)
+		require.Equal(t, []v0alpha1.ImpactNode{
+			{Kind: "Dashboard", Name: "d1", Title: "Dashboard 1"},
+			{Kind: "LibraryPanel", Name: "lp1", Title: "Library panel 1"},
+			{Kind: "AlertRule", Name: "r1", Title: "Alert rule 1"},
+			{Kind: "Dashboard", Name: "d2"},
+		}, graph.Dependents)
+		require.Empty(t, graph.Dependencies)
+		require.Equal(t, []v0alpha1.ImpactEdge{
+			{From: "Dashboard/d1", To: "DataSource/ds1", Relation: "depends-on"},
+			{From: "LibraryPanel/lp1", To: "DataSource/ds1", Relation: "depends-on"},
+			{From: "AlertRule/r1", To: "DataSource/ds1", Relation: "depends-on"},
+			{From: "Dashboard/d1", To: "LibraryPanel/lp1", Relation: "depends-on"},
+			{From: "Dashboard/d2", To: "LibraryPanel/lp1", Relation: "depends-on"},
+		}, graph.Edges)
+	})
+
+	t.Run("finds the dependencies of a dashboard", func(t *testing.T) {
+		mockClient := &MockClient{
+			MockResponses: []*resourcepb.ResourceSearchResponse{
+				impactResponse(impactRow{name: "d1", title: "Dashboard 1", folder: "f1", dataSources: []string{"ds1"}, libraryPanels: []string{"lp1"}}),
+				impactResponse(impactRow{name: "lp1", dataSources: []string{"ds2"}}),
+			},
+		}
+		rr := doImpact(t, mockClient, "kind=Dashboard&name=d1")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, 2, mockClient.CallCount)
+
+		graph := &v0alpha1.ImpactGraph{}
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(graph))
+		require.Equal(t, v0alpha1.ImpactNode{Kind: "Dashboard", Name: "d1", Title: "Dashboard 1", Folder: "f1"}, graph.Target)
+		require.Empty(t, graph.Dependents)
+		require.Equal(t, []v0alpha1.ImpactNode{
+			{Kind: "DataSource", Name: "ds1"},
+			{Kind: "LibraryPanel", Name: "lp1"},
+			{Kind: "DataSource", Name: "ds2"},
+		}, graph.Dependencies)
+		require.Equal(t, []v0alpha1.ImpactEdge{
+			{From: "Dashboard/d1", To: "DataSource/ds1", Relation: "depends-on"},
+			{From: "Dashboard/d1", To: "LibraryPanel/lp1", Relation: "depends-on"},
+			{From: "LibraryPanel/lp1", To: "DataSource/ds2", Relation: "depends-on"},
+		}, graph.Edges)
+	})
+}
+
+type impactRow struct {
+	name          string
+	title         string
+	folder        string
+	dataSources   []string
+	libraryPanels []string
+}
+
+func impactResponse(rows ...impactRow) *resourcepb.ResourceSearchResponse {
+	rsp := &resourcepb.ResourceSearchResponse{
+		Results: &resourcepb.ResourceTable{
+			Columns: []*resourcepb.ResourceTableColumnDefinition{
+				{Name: resource.SEARCH_FIELD_TITLE},
+				{Name: resource.SEARCH_FIELD_FOLDER},
+				{Name: "reference.DataSource"},
+				{Name: "reference.LibraryPanel"},
+			},
+		},
+	}
+	for _, row := range rows {
+		cells := [][]byte{[]byte(row.title), []byte(row.folder), nil, nil}
+		if len(row.dataSources) > 0 {
+			cells[2], _ = json.Marshal(row.dataSources)
+		}
+		if len(row.libraryPanels) > 0 {
+			cells[3], _ = json.Marshal(row.libraryPanels)
+		}
+		rsp.Results.Rows = append(rsp.Results.Rows, &resourcepb.ResourceTableRow{
+			Key:   &resourcepb.ResourceKey{Name: row.name},
+			Cells: cells,
+		})
+	}
+	return rsp
+}
@@ -3,8 +3,10 @@ package dashboard
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http/httptest"
 	"net/url"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -921,6 +923,25 @@ func TestConvertHttpSearchRequestToResourceSearchRequest(t *testing.T) {
 				Federated: []*resourcepb.ResourceKey{folderKey},
 			},
 		},
+		"panel query and datasource filter with panel hits": {
+			queryString: "panelQuery=http_requests_total&panelDataSource=prom-uid&panelHits=true",
+			expected: &resourcepb.ResourceSearchRequest{
+				Options: &resourcepb.ListOptions{
+					Key: dashboardKey,
+					Fields: []*resourcepb.Requirement{
+						{Key: "panels.query", Operator: "=", Values: []string{"http_requests_total"}},
+						{Key: "panels.datasource", Operator: "=", Values: []string{"prom-uid"}},
+					},
+				},
+				Query:     "",
+				Limit:     50,
+				Offset:    0,
+				Page:      1,
+				Explain:   false,
+				Fields:    append(slices.Clone(defaultFields), "panels_info"),
+				Federated: []*resourcepb.ResourceKey{folderKey},
+			},
+		},
 		"createdBy filter": {
 			queryString: "createdBy=user:abc123",
 			expected: &resourcepb.ResourceSearchRequest{
@@ -971,6 +992,101 @@ func TestConvertHttpSearchRequestToResourceSearchRequest(t *testing.T) {
 	}
 }
 
+func TestFilterPanelHits(t *testing.T) {
+	hits := []v0alpha1.DashboardHit{{
+		Name: "dash1",
+		Panels: []v0alpha1.PanelHit{
+			{ID: 1, Type: "timeseries", DataSources: []string{"prom-uid"}, Queries: []string{"rate(http_requests_total[5m])"}},
+			{ID: 2, Type: "piechart", DataSources: []string{"prom-uid"}, Queries: []string{"sum(up)"}},
+			{ID: 3, Type: "timeseries", DataSources: []string{"loki-uid"}, Queries: []string{"{job=\"http_requests_total\"}"}},
+		},
+	}, {
+		// matches each filter on a different panel
+		Name: "dash2",
+		Panels: []v0alpha1.PanelHit{
+			{ID: 1, Type: "timeseries", DataSources: []string{"loki-uid"}, Queries: []string{"rate(http_requests_total[5m])"}},
+			{ID: 2, Type: "table", DataSources: []string{"prom-uid"}, Queries: []string{"sum(up)"}},
+		},
+	}}
+
+	t.Run("by query and datasource", func(t *testing.T) {
+		h := filterPanelHits(slices.Clone(hits), url.Values{"panelQuery": {"HTTP_requests_total"}, "panelDataSource": {"prom-uid"}})
+		require.Len(t, h, 1)
+		require.Equal(t, "dash1", h[0].Name)
+		require.Len(t, h[0].Panels, 1)
+		require.Equal(t, int64(1), h[0].Panels[0].ID)
+	})
+
+	t.Run("by type", func(t *testing.T) {
+		h := filterPanelHits(slices.Clone(hits), url.Values{"panelType": {"piechart"}})
+		require.Len(t, h, 1)
+		require.Len(t, h[0].Panels, 1)
+		require.Equal(t, int64(2), h[0].Panels[0].ID)
+	})
+
+	t.Run("without filters", func(t *testing.T) {
+		h := filterPanelHits(slices.Clone(hits), url.Values{})
+		require.Len(t, h, 2)
+		require.Len(t, h[0].Panels, 3)
+		require.Len(t, h[1].Panels, 2)
+	})
+}
+
+func TestPageHits(t *testing.T) {
+	hits := []v0alpha1.DashboardHit{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	names := func(hits []v0alpha1.DashboardHit) []string {
+		result := []string{}
+		for _, hit := range hits {
+			result = append(result, hit.Name)
+		}
+		return result
+	}
+
+	require.Equal(t, []string{"a", "b"}, names(pageHits(hits, 0, 2)))
+	require.Equal(t, []string{"c"}, names(pageHits(hits, 2, 2)))
+	require.Equal(t, []string{}, names(pageHits(hits, 3, 2)))
+	require.Equal(t, []string{"b", "c"}, names(pageHits(hits, 1, 0)))
+}
+
+func TestSearchPanelHitsPaging(t *testing.T) {
+	rows := []*resourcepb.ResourceTableRow{}
+	for _, panelType := range []string{"timeseries", "table", "timeseries", "timeseries"} {
+		panels, err := json.Marshal([]v0alpha1.PanelHit{{ID: 1, Type: panelType}})
+		require.NoError(t, err)
+		rows = append(rows, &resourcepb.ResourceTableRow{
+			Key:   &resourcepb.ResourceKey{Name: fmt.Sprintf("d%d", len(rows)), Resource: "dashboard"},
+			Cells: [][]byte{panels},
+		})
+	}
+
+	mockClient := &MockClient{
+		MockResponses: []*resourcepb.ResourceSearchResponse{{
+			TotalHits: int64(len(rows)),
+			Results: &resourcepb.ResourceTable{
+				Columns: []*resourcepb.ResourceTableColumnDefinition{{Name: resource.SEARCH_FIELD_PANELS_INFO}},
+				Rows:    rows,
+			},
+		}},
+	}
+	searchHandler := NewSearchHandler(tracing.NewNoopTracerService(), mockClient, nil)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/search?panelHits=true&panelType=timeseries&limit=2&page=2", nil)
+	req = req.WithContext(identity.WithRequester(req.Context(), &user.SignedInUser{Namespace: "test"}))
+	searchHandler.DoSearch(rr, req)
+
+	// the candidates are read from the start, the page is taken after filtering
+	require.Equal(t, int64(0), mockClient.LastSearchRequest.Offset)
+	require.Equal(t, int64(maxPanelHitsCandidates), mockClient.LastSearchRequest.Limit)
+
+	result := v0alpha1.SearchResults{}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&result))
+	require.Equal(t, int64(3), result.TotalHits)
+	require.Equal(t, int64(2), result.Offset)
+	require.Len(t, result.Hits, 1)
+	require.Equal(t, "d3", result.Hits[0].Name)
+}
+
 // MockClient implements the ResourceIndexClient interface for testing
 type MockClient struct {
 	resourcepb.ResourceIndexClient
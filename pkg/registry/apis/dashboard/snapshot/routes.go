@@ -170,6 +170,16 @@ func GetRoutes(service dashboardsnapshots.Service, options dashv0.SnapshotSharin
 						return
 					}
 
+					policy, err := service.GetSnapshotPolicy(ctx, user.GetOrgID())
+					if err != nil {
+						wrap.JsonApiErr(http.StatusInternalServerError, "Failed to get snapshot policy", err)
+						return
+					}
+					if err := policy.ApplyToCreate(&cmd, user.GetOrgRole()); err != nil {
+						errhttp.Write(ctx, err, w)
+						return
+					}
+
 					cmd.OrgID = user.GetOrgID()
 					cmd.UserID, _ = identity.UserIdentifier(user.GetID())
 
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -118,6 +119,7 @@ func TestCreateSnapshotDashboardValidation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			snapshotService := dashboardsnapshots.NewMockService(t)
+			snapshotService.On("GetSnapshotPolicy", mock.Anything, orgID).Return(&dashboardsnapshots.SnapshotPolicy{OrgID: orgID}, nil).Maybe()
 			dashboardService := dashboards.NewFakeDashboardService(t)
 			tt.setupDashboardMock(dashboardService)
 
@@ -155,3 +157,50 @@ func TestCreateSnapshotDashboardValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateSnapshotAppliesOrgPolicy(t *testing.T) {
+	const orgID int64 = 1
+	namespace := authlib.OrgNamespaceFormatter(orgID)
+	testUser := &user.SignedInUser{UserID: 1, OrgID: orgID, OrgRole: identity.RoleEditor}
+
+	snapshotService := dashboardsnapshots.NewMockService(t)
+	snapshotService.On("GetSnapshotPolicy", mock.Anything, orgID).Return(&dashboardsnapshots.SnapshotPolicy{OrgID: orgID, MaxAge: 3600}, nil)
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "valid-uid", OrgID: orgID}, nil)
+
+	var created *dashv0.Snapshot
+	mockStorage := grafanarest.NewMockStorage(t)
+	mockStorage.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { created = args.Get(1).(*dashv0.Snapshot) }).
+		Return(&dashv0.Snapshot{}, nil)
+
+	routes := GetRoutes(
+		snapshotService,
+		dashv0.SnapshotSharingOptions{SnapshotsEnabled: true},
+		acmock.New().WithPermissions([]accesscontrol.Permission{{Action: dashboards.ActionSnapshotsCreate}}),
+		map[string]common.OpenAPIDefinition{},
+		func() rest.Storage { return mockStorage },
+		dashboardService,
+	)
+
+	// a snapshot that never expires is capped to the max age of the policy
+	bodyBytes, err := json.Marshal(map[string]any{
+		"dashboard": map[string]any{"uid": "valid-uid", "title": "test"},
+		"name":      "test snapshot",
+		"expires":   0,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/snapshots/create", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(identity.WithRequester(req.Context(), testUser))
+	req = mux.SetURLVars(req, map[string]string{"namespace": namespace})
+
+	recorder := httptest.NewRecorder()
+	routes.Namespace[0].Handler(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotNil(t, created)
+	require.NotNil(t, created.Spec.Expires)
+	require.LessOrEqual(t, *created.Spec.Expires, time.Now().Add(time.Hour).UnixMilli())
+}
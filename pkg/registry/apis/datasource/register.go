@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/registry/rest"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	openapi "k8s.io/kube-openapi/pkg/common"
@@ -33,7 +34,8 @@ import (
 )
 
 var (
-	_ builder.APIGroupBuilder = (*DataSourceAPIBuilder)(nil)
+	_ builder.APIGroupBuilder    = (*DataSourceAPIBuilder)(nil)
+	_ builder.APIGroupValidation = (*DataSourceAPIBuilder)(nil)
 )
 
 type DataSourceAPIBuilderConfig struct {
@@ -54,6 +56,12 @@ type DataSourceAPIBuilder struct {
 	queryTypes             *datasourceV0.QueryTypeDefinitionList
 	cfg                    DataSourceAPIBuilderConfig
 	dataSourceCRUDMetric   *prometheus.HistogramVec
+	dependents             DependentsWarner
+}
+
+// DependentsWarner adds a warning to the response when other resources still reference a deleted resource
+type DependentsWarner interface {
+	WarnDependents(ctx context.Context, namespace string, kind string, name string)
 }
 
 func RegisterAPIService(
@@ -65,6 +73,7 @@ func RegisterAPIService(
 	accessControl accesscontrol.AccessControl,
 	reg prometheus.Registerer,
 	pluginSources sources.Registry,
+	dependents DependentsWarner,
 ) (*DataSourceAPIBuilder, error) {
 	//nolint:staticcheck // not yet migrated to OpenFeature
 	if !features.IsEnabledGlobally(featuremgmt.FlagQueryServiceWithConnections) {
@@ -116,6 +125,7 @@ func RegisterAPIService(
 		}
 
 		builder.SetDataSourceCRUDMetrics(dataSourceCRUDMetric)
+		builder.SetDependentsWarner(dependents)
 
 		apiRegistrar.RegisterAPI(builder)
 	}
@@ -187,6 +197,19 @@ func (b *DataSourceAPIBuilder) SetDataSourceCRUDMetrics(datasourceCRUDMetric *pr
 	b.dataSourceCRUDMetric = datasourceCRUDMetric
 }
 
+func (b *DataSourceAPIBuilder) SetDependentsWarner(dependents DependentsWarner) {
+	b.dependents = dependents
+}
+
+// Validate warns when a data source that is still referenced (eg by dashboards or alert rules) is deleted
+func (b *DataSourceAPIBuilder) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	if a.GetOperation() == admission.Delete && a.GetSubresource() == "" && a.GetName() != "" &&
+		a.GetResource().Resource == b.datasourceResourceInfo.GroupResource().Resource && b.dependents != nil {
+		b.dependents.WarnDependents(ctx, a.GetNamespace(), "DataSource", a.GetName())
+	}
+	return nil
+}
+
 func addKnownTypes(scheme *runtime.Scheme, gv schema.GroupVersion) {
 	scheme.AddKnownTypes(gv,
 		&datasourceV0.DataSource{},
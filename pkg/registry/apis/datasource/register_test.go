@@ -0,0 +1,76 @@
+package datasource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+
+	"github.com/grafana/grafana/pkg/plugins"
+)
+
+func TestValidateWarnsDependents(t *testing.T) {
+	group := "test.datasource.grafana.app"
+	b, err := NewDataSourceAPIBuilder(group, plugins.JSONData{ID: "test"}, nil, nil, nil, nil, DataSourceAPIBuilderConfig{})
+	require.NoError(t, err)
+
+	dependents := &fakeDependentsWarner{}
+	b.SetDependentsWarner(dependents)
+
+	tests := []struct {
+		name        string
+		operation   admission.Operation
+		resource    string
+		subresource string
+		objName     string
+		warned      []string
+	}{
+		{
+			name:      "delete data source",
+			operation: admission.Delete,
+			resource:  "datasources",
+			objName:   "abc",
+			warned:    []string{"DataSource/abc"},
+		},
+		{
+			name:      "delete collection",
+			operation: admission.Delete,
+			resource:  "datasources",
+		},
+		{
+			name:      "update data source",
+			operation: admission.Update,
+			resource:  "datasources",
+			objName:   "abc",
+		},
+		{
+			name:        "subresource",
+			operation:   admission.Delete,
+			resource:    "datasources",
+			subresource: "resource",
+			objName:     "abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dependents.warned = nil
+			a := admission.NewAttributesRecord(nil, nil, schema.GroupVersionKind{}, "default", tt.objName,
+				schema.GroupVersionResource{Group: group, Version: "v0alpha1", Resource: tt.resource}, tt.subresource,
+				tt.operation, nil, false, nil)
+
+			require.NoError(t, b.Validate(context.Background(), a, nil))
+			require.Equal(t, tt.warned, dependents.warned)
+		})
+	}
+}
+
+type fakeDependentsWarner struct {
+	warned []string
+}
+
+func (f *fakeDependentsWarner) WarnDependents(_ context.Context, namespace string, kind string, name string) {
+	f.warned = append(f.warned, kind+"/"+name)
+}
@@ -0,0 +1,119 @@
+package unifiedstorage
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/registry/rest"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/kube-openapi/pkg/common"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	storageapi "github.com/grafana/grafana/pkg/apis/storage/v0alpha1"
+	"github.com/grafana/grafana/pkg/services/apiserver/builder"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var _ builder.APIGroupBuilder = (*StorageAPIBuilder)(nil)
+
+// StorageAPIBuilder exposes the configuration of the unified storage server.
+// The resources are saved in the unified storage and read by the storage server.
+type StorageAPIBuilder struct {
+	// the webhook resources are only served when the storage server delivers the webhooks
+	webhooksEnabled bool
+
+	// encrypts the sink secrets of the webhook subscriptions
+	secretKey string
+}
+
+func RegisterAPIService(cfg *setting.Cfg, apiregistration builder.APIRegistrar) *StorageAPIBuilder {
+	builder := &StorageAPIBuilder{
+		webhooksEnabled: cfg.WebhooksEnabled,
+		secretKey:       cfg.SecretKey,
+	}
+	apiregistration.RegisterAPI(builder)
+	return builder
+}
+
+func (b *StorageAPIBuilder) GetGroupVersion() schema.GroupVersion {
+	return storageapi.SchemeGroupVersion
+}
+
+func (b *StorageAPIBuilder) InstallSchema(scheme *runtime.Scheme) error {
+	gv := storageapi.SchemeGroupVersion
+	if err := storageapi.AddToScheme(scheme); err != nil {
+		return err
+	}
+	metav1.AddToGroupVersion(scheme, gv)
+	return scheme.SetVersionPriority(gv)
+}
+
+func (b *StorageAPIBuilder) AllowedV0Alpha1Resources() []string {
+	return []string{builder.AllResourcesAllowed}
+}
+
+func (b *StorageAPIBuilder) UpdateAPIGroupInfo(apiGroupInfo *genericapiserver.APIGroupInfo, opts builder.APIGroupOptions) error {
+	storage := map[string]rest.Storage{}
+
+	policies, err := newRetentionPolicyStorage(opts.Scheme, opts.OptsGetter)
+	if err != nil {
+		return err
+	}
+	storage[storageapi.RetentionPolicyResourceInfo.StoragePath()] = policies
+
+	if b.webhooksEnabled {
+		subscriptions, err := newWebhookSubscriptionStorage(opts.Scheme, opts.OptsGetter, b.secretKey)
+		if err != nil {
+			return err
+		}
+		storage[storageapi.WebhookSubscriptionResourceInfo.StoragePath()] = subscriptions
+
+		deadLetters, err := newWebhookDeadLetterStorage(opts.Scheme, opts.OptsGetter)
+		if err != nil {
+			return err
+		}
+		storage[storageapi.WebhookDeadLetterResourceInfo.StoragePath()] = deadLetters
+	}
+
+	apiGroupInfo.VersionedResourcesStorageMap[storageapi.VERSION] = storage
+	return nil
+}
+
+func (b *StorageAPIBuilder) GetOpenAPIDefinitions() common.GetOpenAPIDefinitions {
+	return storageapi.GetOpenAPIDefinitions
+}
+
+// GetAuthorizer allows the org admins to manage the resources of their namespace,
+// the namespace itself is checked before by the apiserver.
+func (b *StorageAPIBuilder) GetAuthorizer() authorizer.Authorizer {
+	return authorizer.AuthorizerFunc(
+		func(ctx context.Context, attr authorizer.Attributes) (authorized authorizer.Decision, reason string, err error) {
+			if !attr.IsResourceRequest() {
+				return authorizer.DecisionNoOpinion, "", nil
+			}
+
+			// the dead letters are written by the storage server, they can only be read and deleted
+			if attr.GetResource() == storageapi.WebhookDeadLetterResourceInfo.GroupResource().Resource {
+				switch attr.GetVerb() {
+				case "create", "update", "patch":
+					return authorizer.DecisionDeny, "dead letters are read only", nil
+				}
+			}
+
+			if identity.IsServiceIdentity(ctx) {
+				return authorizer.DecisionAllow, "", nil
+			}
+
+			u, err := identity.GetRequester(ctx)
+			if err != nil {
+				return authorizer.DecisionDeny, "valid user is required", err
+			}
+			if u.GetIsGrafanaAdmin() || u.GetOrgRole().Includes(identity.RoleAdmin) {
+				return authorizer.DecisionAllow, "", nil
+			}
+			return authorizer.DecisionDeny, "admin role is required", nil
+		})
+}
@@ -0,0 +1,119 @@
+package unifiedstorage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+)
+
+func TestAuthorizer(t *testing.T) {
+	tests := []struct {
+		name      string
+		requester *identity.StaticRequester
+		verb      string
+		resource  string
+		decision  authorizer.Decision
+	}{
+		{
+			name:      "org admin",
+			requester: &identity.StaticRequester{Type: "user", OrgRole: identity.RoleAdmin},
+			verb:      "create",
+			resource:  "webhooksubscriptions",
+			decision:  authorizer.DecisionAllow,
+		},
+		{
+			name:      "grafana admin",
+			requester: &identity.StaticRequester{Type: "user", OrgRole: identity.RoleViewer, IsGrafanaAdmin: true},
+			verb:      "list",
+			resource:  "webhooksubscriptions",
+			decision:  authorizer.DecisionAllow,
+		},
+		{
+			name:      "editor",
+			requester: &identity.StaticRequester{Type: "user", OrgRole: identity.RoleEditor},
+			verb:      "list",
+			resource:  "webhooksubscriptions",
+			decision:  authorizer.DecisionDeny,
+		},
+		{
+			name:      "viewer",
+			requester: &identity.StaticRequester{Type: "user", OrgRole: identity.RoleViewer},
+			verb:      "get",
+			resource:  "webhookdeadletters",
+			decision:  authorizer.DecisionDeny,
+		},
+		{
+			name:      "org admin retention policy",
+			requester: &identity.StaticRequester{Type: "user", OrgRole: identity.RoleAdmin},
+			verb:      "update",
+			resource:  "retentionpolicies",
+			decision:  authorizer.DecisionAllow,
+		},
+		{
+			name:      "editor retention policy",
+			requester: &identity.StaticRequester{Type: "user", OrgRole: identity.RoleEditor},
+			verb:      "create",
+			resource:  "retentionpolicies",
+			decision:  authorizer.DecisionDeny,
+		},
+		{
+			name:      "delete dead letter",
+			requester: &identity.StaticRequester{Type: "user", OrgRole: identity.RoleAdmin},
+			verb:      "delete",
+			resource:  "webhookdeadletters",
+			decision:  authorizer.DecisionAllow,
+		},
+		{
+			name:      "create dead letter",
+			requester: &identity.StaticRequester{Type: "user", OrgRole: identity.RoleAdmin, IsGrafanaAdmin: true},
+			verb:      "create",
+			resource:  "webhookdeadletters",
+			decision:  authorizer.DecisionDeny,
+		},
+		{
+			name:      "update dead letter",
+			requester: &identity.StaticRequester{Type: "user", OrgRole: identity.RoleAdmin},
+			verb:      "update",
+			resource:  "webhookdeadletters",
+			decision:  authorizer.DecisionDeny,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := identity.WithRequester(context.Background(), tt.requester)
+			auth := (&StorageAPIBuilder{}).GetAuthorizer()
+			decision, _, err := auth.Authorize(ctx, &fakeAttributes{verb: tt.verb, resource: tt.resource})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.decision, decision)
+		})
+	}
+
+	t.Run("no user", func(t *testing.T) {
+		auth := (&StorageAPIBuilder{}).GetAuthorizer()
+		decision, _, _ := auth.Authorize(context.Background(), &fakeAttributes{verb: "list", resource: "webhooksubscriptions"})
+		assert.Equal(t, authorizer.DecisionDeny, decision)
+	})
+}
+
+type fakeAttributes struct {
+	authorizer.Attributes
+	verb     string
+	resource string
+}
+
+func (a fakeAttributes) GetVerb() string {
+	return a.verb
+}
+
+func (a fakeAttributes) IsResourceRequest() bool {
+	return true
+}
+
+func (a fakeAttributes) GetResource() string {
+	return a.resource
+}
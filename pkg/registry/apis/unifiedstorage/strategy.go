@@ -0,0 +1,184 @@
+package unifiedstorage
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	storageapi "github.com/grafana/grafana/pkg/apis/storage/v0alpha1"
+	grafanaregistry "github.com/grafana/grafana/pkg/apiserver/registry/generic"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+)
+
+type genericStrategy interface {
+	rest.RESTCreateStrategy
+	rest.RESTUpdateStrategy
+}
+
+// webhookSubscriptionStrategy validates the subscriptions and encrypts their sink secret
+type webhookSubscriptionStrategy struct {
+	genericStrategy
+
+	secretKey string
+}
+
+func newWebhookSubscriptionStrategy(typer runtime.ObjectTyper, gv schema.GroupVersion, secretKey string) *webhookSubscriptionStrategy {
+	return &webhookSubscriptionStrategy{grafanaregistry.NewStrategy(typer, gv), secretKey}
+}
+
+func (s *webhookSubscriptionStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	s.genericStrategy.PrepareForCreate(ctx, obj)
+	if sub, ok := obj.(*storageapi.WebhookSubscription); ok {
+		// the encrypted secret is only set by the server
+		sub.Spec.Sink.EncryptedSecret = ""
+	}
+}
+
+func (s *webhookSubscriptionStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	s.genericStrategy.PrepareForUpdate(ctx, obj, old)
+	sub, ok := obj.(*storageapi.WebhookSubscription)
+	if !ok {
+		return
+	}
+	sub.Spec.Sink.EncryptedSecret = ""
+	if oldSub, ok := old.(*storageapi.WebhookSubscription); ok && sub.Spec.Sink.Secret == "" {
+		// the secret is not returned, keep it when it is not changed
+		sub.Spec.Sink.EncryptedSecret = oldSub.Spec.Sink.EncryptedSecret
+	}
+}
+
+// Validate checks the subscription and encrypts the secret once it is valid
+func (s *webhookSubscriptionStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	sub, ok := obj.(*storageapi.WebhookSubscription)
+	if !ok {
+		return field.ErrorList{field.InternalError(nil, fmt.Errorf("expected webhook subscription"))}
+	}
+	if errs := validateWebhookSubscription(sub); len(errs) > 0 {
+		return errs
+	}
+	return s.encryptSecret(sub)
+}
+
+func (s *webhookSubscriptionStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	return s.Validate(ctx, obj)
+}
+
+func (s *webhookSubscriptionStrategy) encryptSecret(sub *storageapi.WebhookSubscription) field.ErrorList {
+	if sub.Spec.Sink.Secret == "" {
+		return nil
+	}
+	encrypted, err := resource.EncryptWebhookSecret(sub.Spec.Sink.Secret, s.secretKey)
+	if err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath("spec", "sink", "secret"), err)}
+	}
+	sub.Spec.Sink.EncryptedSecret = encrypted
+	sub.Spec.Sink.Secret = ""
+	return nil
+}
+
+func validateWebhookSubscription(sub *storageapi.WebhookSubscription) field.ErrorList {
+	specPath := field.NewPath("spec")
+	errs := field.ErrorList{}
+	if sub.Spec.Group == "" {
+		errs = append(errs, field.Required(specPath.Child("group"), "group is required"))
+	} else if sub.Spec.Group == storageapi.GROUP {
+		// the dead letters are written in this group
+		errs = append(errs, field.Invalid(specPath.Child("group"), sub.Spec.Group, "the storage group can not be watched"))
+	}
+	if sub.Spec.Resource == "" {
+		errs = append(errs, field.Required(specPath.Child("resource"), "resource is required"))
+	}
+	if _, err := labels.Parse(sub.Spec.LabelSelector); err != nil {
+		errs = append(errs, field.Invalid(specPath.Child("labelSelector"), sub.Spec.LabelSelector, err.Error()))
+	}
+
+	sinkPath := specPath.Child("sink")
+	switch sub.Spec.Sink.Type {
+	case storageapi.WebhookSinkHTTP, storageapi.WebhookSinkCloudEvents:
+	default:
+		errs = append(errs, field.NotSupported(sinkPath.Child("type"), sub.Spec.Sink.Type,
+			[]storageapi.WebhookSinkType{storageapi.WebhookSinkHTTP, storageapi.WebhookSinkCloudEvents}))
+	}
+	u, err := url.Parse(sub.Spec.Sink.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, field.Invalid(sinkPath.Child("url"), sub.Spec.Sink.URL, "must be an http or https url"))
+	}
+	return errs
+}
+
+// retentionPolicyStrategy validates the retention policies
+type retentionPolicyStrategy struct {
+	genericStrategy
+}
+
+func newRetentionPolicyStrategy(typer runtime.ObjectTyper, gv schema.GroupVersion) *retentionPolicyStrategy {
+	return &retentionPolicyStrategy{grafanaregistry.NewStrategy(typer, gv)}
+}
+
+func (s *retentionPolicyStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	policy, ok := obj.(*storageapi.RetentionPolicy)
+	if !ok {
+		return field.ErrorList{field.InternalError(nil, fmt.Errorf("expected retention policy"))}
+	}
+	return validateRetentionPolicy(policy)
+}
+
+func (s *retentionPolicyStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	return s.Validate(ctx, obj)
+}
+
+func validateRetentionPolicy(policy *storageapi.RetentionPolicy) field.ErrorList {
+	rulesPath := field.NewPath("spec", "rules")
+	errs := field.ErrorList{}
+	if len(policy.Spec.Rules) == 0 {
+		errs = append(errs, field.Required(rulesPath, "at least one rule is required"))
+	}
+	seen := map[string]bool{}
+	for i, rule := range policy.Spec.Rules {
+		rulePath := rulesPath.Index(i)
+		if rule.Resource != "" && rule.Group == "" {
+			errs = append(errs, field.Required(rulePath.Child("group"), "the group is required with a resource"))
+		}
+		target := rule.Group + "/" + rule.Resource
+		if seen[target] {
+			errs = append(errs, field.Duplicate(rulePath, target))
+		}
+		seen[target] = true
+		if rule.MaxVersions < 0 {
+			errs = append(errs, field.Invalid(rulePath.Child("maxVersions"), rule.MaxVersions, "must not be negative"))
+		}
+		if rule.MinVersions < 0 {
+			errs = append(errs, field.Invalid(rulePath.Child("minVersions"), rule.MinVersions, "must not be negative"))
+		}
+		if rule.MaxAge.Duration < 0 {
+			errs = append(errs, field.Invalid(rulePath.Child("maxAge"), rule.MaxAge.String(), "must not be negative"))
+		}
+		if rule.MaxVersions == 0 && rule.MaxAge.Duration == 0 {
+			errs = append(errs, field.Required(rulePath, "maxVersions or maxAge is required"))
+		}
+		if rule.MaxVersions > 0 && rule.MinVersions > rule.MaxVersions {
+			errs = append(errs, field.Invalid(rulePath.Child("minVersions"), rule.MinVersions, "must not be greater than maxVersions"))
+		}
+	}
+	return errs
+}
+
+// hideWebhookSecrets removes the encrypted secrets from the returned subscriptions
+func hideWebhookSecrets(obj runtime.Object) {
+	switch v := obj.(type) {
+	case *storageapi.WebhookSubscription:
+		v.Spec.Sink.Secret = ""
+		v.Spec.Sink.EncryptedSecret = ""
+	case *storageapi.WebhookSubscriptionList:
+		for i := range v.Items {
+			v.Items[i].Spec.Sink.Secret = ""
+			v.Items[i].Spec.Sink.EncryptedSecret = ""
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package unifiedstorage
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	storageapi "github.com/grafana/grafana/pkg/apis/storage/v0alpha1"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func newTestSubscription() *storageapi.WebhookSubscription {
+	return &storageapi.WebhookSubscription{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "audit"},
+		Spec: storageapi.WebhookSubscriptionSpec{
+			Group:    "dashboard.grafana.app",
+			Resource: "dashboards",
+			Sink:     storageapi.WebhookSink{Type: storageapi.WebhookSinkHTTP, URL: "https://example.com/hook"},
+		},
+	}
+}
+
+func TestValidateWebhookSubscription(t *testing.T) {
+	require.Empty(t, validateWebhookSubscription(newTestSubscription()))
+
+	tests := map[string]func(sub *storageapi.WebhookSubscription){
+		"missing resource":   func(sub *storageapi.WebhookSubscription) { sub.Spec.Resource = "" },
+		"missing group":      func(sub *storageapi.WebhookSubscription) { sub.Spec.Group = "" },
+		"storage group":      func(sub *storageapi.WebhookSubscription) { sub.Spec.Group = storageapi.GROUP },
+		"invalid selector":   func(sub *storageapi.WebhookSubscription) { sub.Spec.LabelSelector = "a in (b" },
+		"unsupported sink":   func(sub *storageapi.WebhookSubscription) { sub.Spec.Sink.Type = "kafka" },
+		"unsupported scheme": func(sub *storageapi.WebhookSubscription) { sub.Spec.Sink.URL = "ftp://example.com" },
+	}
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			sub := newTestSubscription()
+			change(sub)
+			require.NotEmpty(t, validateWebhookSubscription(sub))
+		})
+	}
+}
+
+func TestWebhookSubscriptionSecret(t *testing.T) {
+	ctx := context.Background()
+	strategy := newWebhookSubscriptionStrategy(runtime.NewScheme(), storageapi.SchemeGroupVersion, "secret-key")
+
+	sub := newTestSubscription()
+	sub.Spec.Sink.Secret = "s3cr3t"
+	sub.Spec.Sink.EncryptedSecret = "set by the client"
+	strategy.PrepareForCreate(ctx, sub)
+	require.Empty(t, strategy.Validate(ctx, sub))
+	require.Empty(t, sub.Spec.Sink.Secret)
+	require.NotEmpty(t, sub.Spec.Sink.EncryptedSecret)
+	require.Equal(t, "s3cr3t", decryptTestSecret(t, sub.Spec.Sink.EncryptedSecret))
+
+	// the secret is kept when it is not sent again
+	updated := newTestSubscription()
+	updated.Spec.Resource = "folders"
+	strategy.PrepareForUpdate(ctx, updated, sub)
+	require.Empty(t, strategy.ValidateUpdate(ctx, updated, sub))
+	require.Equal(t, sub.Spec.Sink.EncryptedSecret, updated.Spec.Sink.EncryptedSecret)
+
+	// and replaced when it is
+	replaced := newTestSubscription()
+	replaced.Spec.Sink.Secret = "n3w"
+	strategy.PrepareForUpdate(ctx, replaced, sub)
+	require.Empty(t, strategy.ValidateUpdate(ctx, replaced, sub))
+	require.Equal(t, "n3w", decryptTestSecret(t, replaced.Spec.Sink.EncryptedSecret))
+
+	// the secrets are never returned
+	list := &storageapi.WebhookSubscriptionList{Items: []storageapi.WebhookSubscription{*replaced}}
+	hideWebhookSecrets(list)
+	hideWebhookSecrets(replaced)
+	require.Empty(t, list.Items[0].Spec.Sink.EncryptedSecret)
+	require.Empty(t, replaced.Spec.Sink.EncryptedSecret)
+}
+
+func TestValidateRetentionPolicy(t *testing.T) {
+	valid := storageapi.RetentionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "keep"},
+		Spec: storageapi.RetentionPolicySpec{Rules: []storageapi.RetentionRule{
+			{Group: "dashboard.grafana.app", Resource: "dashboards", MaxVersions: 10, MinVersions: 2},
+			{Group: "dashboard.grafana.app", MaxAge: metav1.Duration{Duration: 365 * 24 * time.Hour}},
+		}},
+	}
+	require.Empty(t, validateRetentionPolicy(&valid))
+
+	for name, rules := range map[string][]storageapi.RetentionRule{
+		"no rules":               nil,
+		"resource without group": {{Resource: "dashboards", MaxVersions: 1}},
+		"no limit":               {{Group: "dashboard.grafana.app"}},
+		"negative":               {{MaxVersions: -1}},
+		"negative age":           {{MaxAge: metav1.Duration{Duration: -time.Hour}}},
+		"min above max":          {{MaxVersions: 2, MinVersions: 3}},
+		"duplicate":              {{MaxVersions: 2}, {MaxVersions: 3}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			invalid := valid
+			invalid.Spec.Rules = rules
+			require.NotEmpty(t, validateRetentionPolicy(&invalid))
+		})
+	}
+}
+
+func decryptTestSecret(t *testing.T, encrypted string) string {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	require.NoError(t, err)
+	secret, err := util.Decrypt(data, "secret-key")
+	require.NoError(t, err)
+	return string(secret)
+}
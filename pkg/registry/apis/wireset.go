@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/grafana/pkg/registry/apis/query"
 	"github.com/grafana/grafana/pkg/registry/apis/secret"
 	"github.com/grafana/grafana/pkg/registry/apis/service"
+	"github.com/grafana/grafana/pkg/registry/apis/unifiedstorage"
 	"github.com/grafana/grafana/pkg/registry/apis/userstorage"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
 )
@@ -74,6 +75,7 @@ var WireSet = wire.NewSet(
 
 	// Each must be added here *and* in the ServiceSink above
 	dashboardinternal.RegisterAPIService,
+	wire.Bind(new(datasource.DependentsWarner), new(*dashboardinternal.DashboardsAPIBuilder)),
 	datasource.RegisterAPIService,
 	folders.RegisterAPIService,
 	iam.RegisterAPIService,
@@ -83,6 +85,7 @@ var WireSet = wire.NewSet(
 	preferences.RegisterAPIService,
 	collections.RegisterAPIService,
 	userstorage.RegisterAPIService,
+	unifiedstorage.RegisterAPIService,
 	ofrep.RegisterAPIService,
 	appplugin.RegisterAPIService,
 )
@@ -2,9 +2,12 @@ package shorturl
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	claims "github.com/grafana/authlib/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
@@ -18,8 +21,14 @@ func convertToK8sResource(v *shorturls.ShortUrl, namespacer request.NamespaceMap
 	spec := shorturl.ShortURLSpec{
 		Path: v.Path,
 	}
+	if v.ExpiresAt > 0 {
+		expiresAt := v.ExpiresAt
+		spec.ExpiresAt = &expiresAt
+	}
+	visitCount := v.VisitCount
 	status := shorturl.ShortURLStatus{
 		LastSeenAt: v.LastSeenAt,
+		VisitCount: &visitCount,
 	}
 
 	// resourceVersion can't be 0, since we are using the lastSeenAt value, when it's zero we default to current time
@@ -38,9 +47,21 @@ func convertToK8sResource(v *shorturls.ShortUrl, namespacer request.NamespaceMap
 		Spec:   spec,
 		Status: status,
 	}
+	if v.CreatedBy > 0 {
+		p.SetCreatedBy(claims.NewTypeID(claims.TypeUser, strconv.FormatInt(v.CreatedBy, 10)))
+	}
 	return p
 }
 
+// visitsToStatus converts the daily visits of a legacy short URL to the visits of the k8s status
+func visitsToStatus(visits []*shorturls.ShortUrlVisit) map[string]int64 {
+	out := make(map[string]int64, len(visits))
+	for _, v := range visits {
+		out[strconv.FormatInt(v.Bucket, 10)] = v.Visits
+	}
+	return out
+}
+
 func LegacyCreateCommandToUnstructured(cmd dtos.CreateShortURLCmd) unstructured.Unstructured {
 	obj := unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -52,6 +73,9 @@ func LegacyCreateCommandToUnstructured(cmd dtos.CreateShortURLCmd) unstructured.
 			},
 		},
 	}
+	if cmd.ExpiresAt > 0 {
+		_ = unstructured.SetNestedField(obj.Object, cmd.ExpiresAt, "spec", "expiresAt")
+	}
 	return obj
 }
 
@@ -65,12 +89,37 @@ func UnstructuredToLegacyShortURLDTO(item unstructured.Unstructured, appURL stri
 }
 
 func UnstructuredToLegacyShortURL(item unstructured.Unstructured) *shorturls.ShortUrl {
-	spec := item.Object["spec"].(map[string]interface{})
-	status := item.Object["status"].(map[string]interface{})
+	path, _, _ := unstructured.NestedString(item.Object, "spec", "path")
+	expiresAt, _, _ := unstructured.NestedInt64(item.Object, "spec", "expiresAt")
+	lastSeenAt, _, _ := unstructured.NestedInt64(item.Object, "status", "lastSeenAt")
+	visitCount, _, _ := unstructured.NestedInt64(item.Object, "status", "visitCount")
 
 	return &shorturls.ShortUrl{
 		Uid:        item.GetName(),
-		Path:       spec["path"].(string),
-		LastSeenAt: status["lastSeenAt"].(int64),
+		Path:       path,
+		CreatedAt:  item.GetCreationTimestamp().Unix(),
+		LastSeenAt: lastSeenAt,
+		ExpiresAt:  expiresAt,
+		VisitCount: visitCount,
+	}
+}
+
+// UnstructuredToLegacyShortURLVisits returns the daily visits stored in the status of a short URL,
+// within the from and to unix times and sorted by day.
+func UnstructuredToLegacyShortURLVisits(item unstructured.Unstructured, from, to time.Time) []*shorturls.ShortUrlVisit {
+	raw, _, _ := unstructured.NestedMap(item.Object, "status", "visits")
+	visits := make([]*shorturls.ShortUrlVisit, 0, len(raw))
+	for k, v := range raw {
+		bucket, err := strconv.ParseInt(k, 10, 64)
+		if err != nil || bucket < from.UTC().Truncate(24*time.Hour).Unix() || bucket > to.Unix() {
+			continue
+		}
+		count, ok := v.(int64)
+		if !ok {
+			continue
+		}
+		visits = append(visits, &shorturls.ShortUrlVisit{Bucket: bucket, Visits: count})
 	}
+	sort.Slice(visits, func(i, j int) bool { return visits[i].Bucket < visits[j].Bucket })
+	return visits
 }
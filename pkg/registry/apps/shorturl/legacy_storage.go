@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
@@ -29,6 +30,10 @@ var (
 	_ rest.GracefulDeleter      = (*legacyStorage)(nil)
 )
 
+// maxStatusVisitDays is the number of days of visits returned in the status of a short URL.
+// It matches the number of days kept by the goto route in unified storage.
+const maxStatusVisitDays = 90
+
 type legacyStorage struct {
 	service        shorturls.Service
 	namespacer     request.NamespaceMapper
@@ -93,7 +98,14 @@ func (s *legacyStorage) Get(ctx context.Context, name string, options *metav1.Ge
 		return nil, err
 	}
 
-	return convertToK8sResource(dto, s.namespacer), nil
+	obj := convertToK8sResource(dto, s.namespacer)
+	now := time.Now()
+	visits, err := s.service.GetVisits(ctx, dto, now.AddDate(0, 0, -maxStatusVisitDays), now)
+	if err != nil {
+		return nil, err
+	}
+	obj.Status.Visits = visitsToStatus(visits)
+	return obj, nil
 }
 
 func (s *legacyStorage) Create(ctx context.Context,
@@ -119,6 +131,9 @@ func (s *legacyStorage) Create(ctx context.Context,
 		Path: p.Spec.Path,
 		UID:  p.Name,
 	}
+	if p.Spec.ExpiresAt != nil {
+		cmd.ExpiresAt = *p.Spec.ExpiresAt
+	}
 	out, err := s.service.CreateShortURL(ctx, requester, cmd)
 	if err != nil {
 		return nil, err
@@ -148,17 +163,47 @@ func (s *legacyStorage) Update(ctx context.Context,
 		return nil, false, err
 	}
 
-	err = s.service.UpdateLastSeenAt(ctx, shortURL)
+	existing := convertToK8sResource(shortURL, s.namespacer)
+	obj, err := objInfo.UpdatedObject(ctx, existing)
 	if err != nil {
 		return nil, false, err
 	}
+	updated, ok := obj.(*shorturl.ShortURL)
+	if !ok {
+		return nil, false, fmt.Errorf("expected shorturl?")
+	}
+
+	if updated.Spec.Path != existing.Spec.Path || !equalExpiry(updated.Spec.ExpiresAt, existing.Spec.ExpiresAt) {
+		if updateValidation != nil {
+			if err := updateValidation(ctx, updated, existing); err != nil {
+				return nil, false, err
+			}
+		}
+		// Repoint the short URL, the status is managed by the goto route
+		cmd := &dtos.UpdateShortURLCmd{Path: updated.Spec.Path}
+		if updated.Spec.ExpiresAt != nil {
+			cmd.ExpiresAt = *updated.Spec.ExpiresAt
+		}
+		if _, err := s.service.UpdateShortURL(ctx, requester, name, cmd); err != nil {
+			return nil, false, err
+		}
+	} else if err := s.service.UpdateLastSeenAt(ctx, shortURL); err != nil {
+		return nil, false, err
+	}
+
 	// Fetch the updated short URL to return
-	updatedLegacyShortURL, err := s.service.GetShortURLByUID(ctx, requester, name)
+	out, err := s.Get(ctx, name, nil)
 	if err != nil {
 		return nil, false, err
 	}
+	return out, false, nil
+}
 
-	return convertToK8sResource(updatedLegacyShortURL, s.namespacer), true, nil
+func equalExpiry(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
 
 // GracefulDeleter
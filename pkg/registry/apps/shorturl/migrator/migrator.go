@@ -67,10 +67,12 @@ func (m *shortURLMigrator) MigrateShortURLs(ctx context.Context, orgId int64, op
 		var createdBy int64
 		var createdAt int64
 		var lastSeenAt int64
+		var expiresAt sql.NullInt64
+		var visitCount int64
 
 		rawRows := make([]shortURLRow, 0, limit)
 		for rows.Next() {
-			err = rows.Scan(&id, &orgID, &uid, &path, &createdBy, &createdAt, &lastSeenAt)
+			err = rows.Scan(&id, &orgID, &uid, &path, &createdBy, &createdAt, &lastSeenAt, &expiresAt, &visitCount)
 			if err != nil {
 				_ = rows.Close()
 				return err
@@ -83,6 +85,8 @@ func (m *shortURLMigrator) MigrateShortURLs(ctx context.Context, orgId int64, op
 				createdBy:  createdBy,
 				createdAt:  createdAt,
 				lastSeenAt: lastSeenAt,
+				expiresAt:  expiresAt.Int64,
+				visitCount: visitCount,
 			})
 		}
 
@@ -118,6 +122,12 @@ func (m *shortURLMigrator) MigrateShortURLs(ctx context.Context, orgId int64, op
 					LastSeenAt: row.lastSeenAt,
 				},
 			}
+			if row.expiresAt > 0 {
+				shortURL.Spec.ExpiresAt = &row.expiresAt
+			}
+			if row.visitCount > 0 {
+				shortURL.Status.VisitCount = &row.visitCount
+			}
 
 			if row.createdBy > 0 {
 				shortURL.SetCreatedBy(claims.NewTypeID(claims.TypeUser, strconv.FormatInt(row.createdBy, 10)))
@@ -173,6 +183,8 @@ type shortURLRow struct {
 	createdBy  int64
 	createdAt  int64
 	lastSeenAt int64
+	expiresAt  int64
+	visitCount int64
 }
 
 func (m *shortURLMigrator) listShortURLs(ctx context.Context, orgID int64, lastID int64, limit int64) (*sql.Rows, error) {
@@ -3,6 +3,7 @@ package migrator
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"text/template"
@@ -11,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 
+	shorturlv1beta1 "github.com/grafana/grafana/apps/shorturl/pkg/apis/shorturl/v1beta1"
 	"github.com/grafana/grafana/pkg/storage/legacysql"
 	"github.com/grafana/grafana/pkg/storage/unified/migrations"
 	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
@@ -145,6 +147,49 @@ func TestMigrateShortURLs_ProgressTimingLogs(t *testing.T) {
 	}
 }
 
+func TestMigrateShortURLs_ExpiryAndVisits(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	records := makeShortURLRows(2)
+	records[0].expiresAt = 1720000000
+	records[0].visitCount = 7
+	mock.ExpectQuery("SELECT 0").WillReturnRows(newShortURLSQLRows(records))
+
+	m := &shortURLMigrator{
+		listShortURLsFn: func(_ context.Context, _ int64, lastID int64, _ int64) (*sql.Rows, error) {
+			return db.Query(fmt.Sprintf("SELECT %d", lastID))
+		},
+	}
+
+	stream := &capturingBulkProcessClient{}
+	err = m.MigrateShortURLs(context.Background(), 1, migrations.MigrateOptions{
+		Namespace: "default",
+		Progress:  func(int, string) {},
+	}, stream)
+	require.NoError(t, err)
+	require.Len(t, stream.requests, 2)
+
+	migrated := make([]shorturlv1beta1.ShortURL, 0, 2)
+	for _, req := range stream.requests {
+		obj := shorturlv1beta1.ShortURL{}
+		require.NoError(t, json.Unmarshal(req.Value, &obj))
+		migrated = append(migrated, obj)
+	}
+
+	require.NotNil(t, migrated[0].Spec.ExpiresAt)
+	require.Equal(t, int64(1720000000), *migrated[0].Spec.ExpiresAt)
+	require.NotNil(t, migrated[0].Status.VisitCount)
+	require.Equal(t, int64(7), *migrated[0].Status.VisitCount)
+
+	require.Nil(t, migrated[1].Spec.ExpiresAt)
+	require.Nil(t, migrated[1].Status.VisitCount)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 type progressEvent struct {
 	count int
 	msg   string
@@ -200,9 +245,13 @@ func recordsAfter(rows []testShortURLRow, lastID int64, limit int) []testShortUR
 }
 
 func newShortURLSQLRows(rows []testShortURLRow) *sqlmock.Rows {
-	sqlRows := sqlmock.NewRows([]string{"id", "org_id", "uid", "path", "created_by", "created_at", "last_seen_at"})
+	sqlRows := sqlmock.NewRows([]string{"id", "org_id", "uid", "path", "created_by", "created_at", "last_seen_at", "expires_at", "visit_count"})
 	for _, row := range rows {
-		sqlRows.AddRow(row.id, int64(1), row.uid, row.path, row.createdBy, row.createdAt, row.lastSeenAt)
+		var expiresAt any
+		if row.expiresAt > 0 {
+			expiresAt = row.expiresAt
+		}
+		sqlRows.AddRow(row.id, int64(1), row.uid, row.path, row.createdBy, row.createdAt, row.lastSeenAt, expiresAt, row.visitCount)
 	}
 	return sqlRows
 }
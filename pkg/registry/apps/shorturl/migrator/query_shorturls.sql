@@ -5,7 +5,9 @@ SELECT
     s.path,
     s.created_by,
     s.created_at,
-    s.last_seen_at
+    s.last_seen_at,
+    s.expires_at,
+    s.visit_count
 FROM
     {{ .Ident .ShortURLTable }} as s
 WHERE
@@ -59,8 +59,8 @@ func (s *statusDualWriter) Update(ctx context.Context, name string, objInfo rest
 		return nil, false, err
 	}
 
-	// This ignores the incoming and updates it directly
-	err = s.legacy.service.UpdateLastSeenAt(ctx, shortURL)
+	// This ignores the incoming and counts a visit directly, the status is only updated by the goto route
+	err = s.legacy.service.RecordVisit(ctx, shortURL)
 	if err != nil {
 		return nil, false, err
 	}
@@ -286,6 +286,16 @@ func (srv *CleanUpService) deleteKubernetesExpiredSnapshots(ctx context.Context)
 
 	for _, o := range orgs {
 		ctx, _ := identity.WithServiceIdentity(ctx, o.ID)
+
+		// Snapshots older than the max age of the org policy are removed even when they never expire
+		var createdBefore time.Time
+		policy, err := srv.dashboardSnapshotService.GetSnapshotPolicy(ctx, o.ID)
+		if err != nil {
+			logger.Error("Failed to get snapshot policy for org", "orgID", o.ID, "error", err.Error())
+		} else if policy.MaxAge > 0 {
+			createdBefore = expirationTime.Add(-time.Duration(policy.MaxAge) * time.Second)
+		}
+
 		namespaceMapper := request.GetNamespaceMapper(srv.Cfg)
 		snapshots, err := client.Resource(gvr).Namespace(namespaceMapper(o.ID)).List(ctx, v1.ListOptions{})
 		if err != nil {
@@ -302,8 +312,10 @@ func (srv *CleanUpService) deleteKubernetesExpiredSnapshots(ctx context.Context)
 				continue
 			}
 
-			// Only delete expired snapshots
-			if snapshot.Spec.Expires != nil && *snapshot.Spec.Expires < expirationTimestamp {
+			// Only delete expired snapshots and the snapshots past the max age of the policy
+			expired := snapshot.Spec.Expires != nil && *snapshot.Spec.Expires < expirationTimestamp
+			tooOld := !createdBefore.IsZero() && snapshot.CreationTimestamp.Time.Before(createdBefore)
+			if expired || tooOld {
 				namespace := snapshot.Namespace
 				name := snapshot.Name
 
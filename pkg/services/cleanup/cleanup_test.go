@@ -139,6 +139,30 @@ func TestDeleteExpiredSnapshots_KubernetesMode(t *testing.T) {
 		mockResource.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
+	t.Run("deletes snapshots older than the max age of the org policy", func(t *testing.T) {
+		neverExpires := time.Now().Add(24 * time.Hour).UnixMilli()
+		oldSnapshot := createUnstructuredSnapshot("old-snap", "org-1", neverExpires)
+		oldSnapshot.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-2 * time.Hour)))
+		recentSnapshot := createUnstructuredSnapshot("recent-snap", "org-1", neverExpires)
+		recentSnapshot.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-time.Minute)))
+
+		mockResource := new(mockResourceInterface)
+		mockResource.On("Namespace", mock.Anything).Return(mockResource)
+		mockResource.On("List", mock.Anything, mock.Anything).Return(&unstructured.UnstructuredList{
+			Items: []unstructured.Unstructured{*oldSnapshot, *recentSnapshot},
+		}, nil)
+		mockResource.On("Delete", mock.Anything, "old-snap", mock.Anything, mock.Anything).Return(nil)
+
+		mockDynClient := new(mockDynamicClient)
+		mockDynClient.On("Resource", mock.Anything).Return(mockResource)
+
+		service := createK8sCleanupServiceWithPolicy(t, mockDynClient, &dashboardsnapshots.SnapshotPolicy{MaxAge: 3600})
+		service.deleteExpiredSnapshots(context.Background())
+
+		mockResource.AssertCalled(t, "Delete", mock.Anything, "old-snap", mock.Anything, mock.Anything)
+		mockResource.AssertNotCalled(t, "Delete", mock.Anything, "recent-snap", mock.Anything, mock.Anything)
+	})
+
 	t.Run("handles REST config error", func(t *testing.T) {
 		service := &CleanUpService{
 			log:                  log.New("cleanup"),
@@ -213,12 +237,20 @@ func createUnstructuredSnapshot(name, namespace string, expiresMillis int64) *un
 
 // Helper to create CleanUpService configured for Kubernetes mode with standard two-org setup
 func createK8sCleanupService(t *testing.T, mockDynClient *mockDynamicClient) *CleanUpService {
+	return createK8sCleanupServiceWithPolicy(t, mockDynClient, &dashboardsnapshots.SnapshotPolicy{})
+}
+
+// Helper to create CleanUpService configured for Kubernetes mode where every org has the given snapshot policy
+func createK8sCleanupServiceWithPolicy(t *testing.T, mockDynClient *mockDynamicClient, policy *dashboardsnapshots.SnapshotPolicy) *CleanUpService {
 	mockOrgSvc := orgtest.NewMockService(t)
 	mockOrgSvc.On("Search", mock.Anything, mock.Anything).Return([]*org.OrgDTO{
 		{ID: 1, Name: "org1"},
 		{ID: 2, Name: "org2"},
 	}, nil)
 
+	mockSnapService := dashboardsnapshots.NewMockService(t)
+	mockSnapService.On("GetSnapshotPolicy", mock.Anything, mock.Anything).Return(policy, nil).Maybe()
+
 	return &CleanUpService{
 		log:      log.New("cleanup"),
 		Cfg:      &setting.Cfg{},
@@ -226,7 +258,8 @@ func createK8sCleanupService(t *testing.T, mockDynClient *mockDynamicClient) *Cl
 		clientConfigProvider: apiserver.RestConfigProviderFunc(func(ctx context.Context) (*rest.Config, error) {
 			return &rest.Config{}, nil
 		}),
-		orgService: mockOrgSvc,
+		orgService:               mockOrgSvc,
+		dashboardSnapshotService: mockSnapService,
 		dynamicClientFactory: func(cfg *rest.Config) (dynamic.Interface, error) {
 			return mockDynClient, nil
 		},
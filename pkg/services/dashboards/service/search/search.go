@@ -25,6 +25,7 @@ var (
 		resource.SEARCH_FIELD_MANAGER_ID:       "",
 		resource.SEARCH_FIELD_MANAGER_KIND:     "",
 		resource.SEARCH_FIELD_OWNER_REFERENCES: "",
+		resource.SEARCH_FIELD_PANELS_INFO:      "",
 	}
 
 	IncludeFields = []string{
@@ -113,6 +114,7 @@ func ParseResults(result *resourcepb.ResourceSearchResponse, offset int64) (v0al
 	managerKindIDX := -1
 	managerIdIDX := -1
 	ownerRefsIDX := -1
+	panelsIDX := -1
 
 	for i, v := range result.Results.Columns {
 		switch v.Name {
@@ -134,6 +136,8 @@ func ParseResults(result *resourcepb.ResourceSearchResponse, offset int64) (v0al
 			descriptionIDX = i
 		case resource.SEARCH_FIELD_OWNER_REFERENCES:
 			ownerRefsIDX = i
+		case resource.SEARCH_FIELD_PANELS_INFO:
+			panelsIDX = i
 		}
 	}
 
@@ -204,6 +208,9 @@ func ParseResults(result *resourcepb.ResourceSearchResponse, offset int64) (v0al
 		if ownerRefsIDX >= 0 && row.Cells[ownerRefsIDX] != nil {
 			_ = json.Unmarshal(row.Cells[ownerRefsIDX], &hit.OwnerReferences)
 		}
+		if panelsIDX >= 0 && row.Cells[panelsIDX] != nil {
+			_ = json.Unmarshal(row.Cells[panelsIDX], &hit.Panels)
+		}
 
 		sr.Hits[i] = *hit
 	}
@@ -31,14 +31,32 @@ func NewStore(db db.DB) *DashboardSnapshotStore {
 // DeleteExpiredSnapshots removes snapshots with old expiry dates.
 // SnapShotRemoveExpired is deprecated and should be removed in the future.
 // Snapshot expiry is decided by the user when they share the snapshot.
+// When the command is scoped to an org, the snapshots of the org created before
+// cmd.CreatedBefore are removed instead, which enforces the max age of a snapshot policy.
+// The blob keys are read in the same transaction as the delete so they match the removed rows.
 func (d *DashboardSnapshotStore) DeleteExpiredSnapshots(ctx context.Context, cmd *dashboardsnapshots.DeleteExpiredSnapshotsCommand) error {
-	return d.store.WithDbSession(ctx, func(sess *db.Session) error {
-		deleteExpiredSQL := "DELETE FROM dashboard_snapshot WHERE expires < ?"
-		expiredResponse, err := sess.Exec(deleteExpiredSQL, time.Now())
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		where := "expires < ?"
+		args := []any{time.Now()}
+		if cmd.OrgID > 0 && !cmd.CreatedBefore.IsZero() {
+			where = "org_id = ? AND created < ?"
+			args = []any{cmd.OrgID, cmd.CreatedBefore}
+		}
+
+		storageKeys := make([]string, 0)
+		selectSQL := "SELECT storage_key FROM dashboard_snapshot WHERE " + where + " AND storage_key IS NOT NULL AND storage_key <> ''"
+		err := sess.SQL(selectSQL, args...).Find(&storageKeys)
+		if err != nil {
+			return err
+		}
+
+		deleteExpiredSQL := "DELETE FROM dashboard_snapshot WHERE " + where
+		expiredResponse, err := sess.Exec(append([]any{deleteExpiredSQL}, args...)...)
 		if err != nil {
 			return err
 		}
 		cmd.DeletedRows, _ = expiredResponse.RowsAffected()
+		cmd.StorageKeys = storageKeys
 
 		return nil
 	})
@@ -63,6 +81,8 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 			ExternalDeleteURL:  cmd.ExternalDeleteURL,
 			Dashboard:          simplejson.New(),
 			DashboardEncrypted: cmd.DashboardEncrypted,
+			StorageKey:         cmd.StorageKey,
+			Version:            1,
 			Expires:            expires,
 			Created:            time.Now(),
 			Updated:            time.Now(),
@@ -78,6 +98,26 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 	return result, nil
 }
 
+// UpdateDashboardSnapshot replaces the dashboard of a snapshot with a new version.
+func (d *DashboardSnapshotStore) UpdateDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.UpdateDashboardSnapshotCommand) error {
+	return d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		snapshot := &dashboardsnapshots.DashboardSnapshot{
+			Version:            cmd.Version,
+			DashboardEncrypted: cmd.DashboardEncrypted,
+			StorageKey:         cmd.StorageKey,
+			Updated:            time.Now(),
+		}
+		affected, err := sess.ID(cmd.ID).Cols("version", "dashboard_encrypted", "storage_key", "updated").Update(snapshot)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return dashboardsnapshots.ErrBaseNotFound.Errorf("dashboard snapshot not found")
+		}
+		return nil
+	})
+}
+
 func (d *DashboardSnapshotStore) DeleteDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.DeleteDashboardSnapshotCommand) error {
 	return d.store.WithDbSession(ctx, func(sess *db.Session) error {
 		var rawSQL = "DELETE FROM dashboard_snapshot WHERE delete_key=?"
@@ -151,3 +191,56 @@ func (d *DashboardSnapshotStore) SearchDashboardSnapshots(ctx context.Context, q
 	}
 	return queryResult, nil
 }
+
+// GetSnapshotPolicy returns the snapshot policy of an org.
+// Orgs without a stored policy get an empty policy that allows everything.
+func (d *DashboardSnapshotStore) GetSnapshotPolicy(ctx context.Context, orgID int64) (*dashboardsnapshots.SnapshotPolicy, error) {
+	policy := &dashboardsnapshots.SnapshotPolicy{OrgID: orgID}
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ?", orgID).Get(policy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (d *DashboardSnapshotStore) GetSnapshotPolicies(ctx context.Context) ([]*dashboardsnapshots.SnapshotPolicy, error) {
+	policies := make([]*dashboardsnapshots.SnapshotPolicy, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Find(&policies)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (d *DashboardSnapshotStore) SaveSnapshotPolicy(ctx context.Context, cmd *dashboardsnapshots.SaveSnapshotPolicyCommand) (*dashboardsnapshots.SnapshotPolicy, error) {
+	policy := &dashboardsnapshots.SnapshotPolicy{
+		OrgID:        cmd.OrgID,
+		MaxAge:       cmd.MaxAge,
+		MaxSize:      cmd.MaxSize,
+		ExternalRole: cmd.ExternalRole,
+		Updated:      time.Now(),
+	}
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing := dashboardsnapshots.SnapshotPolicy{}
+		has, err := sess.Where("org_id = ?", cmd.OrgID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !has {
+			_, err = sess.Insert(policy)
+			return err
+		}
+		policy.ID = existing.ID
+		_, err = sess.ID(existing.ID).AllCols().Update(policy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
@@ -198,6 +198,93 @@ func TestIntegrationDeleteExpiredSnapshots(t *testing.T) {
 	})
 }
 
+func TestIntegrationDeleteSnapshotsOlderThanPolicy(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	sqlstore := db.InitTestDB(t)
+	dashStore := NewStore(sqlstore)
+
+	createTestSnapshot(t, dashStore, "key1", 48000)
+	stored := createTestSnapshot(t, dashStore, "key2", 48000)
+	err := dashStore.store.WithDbSession(context.Background(), func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE dashboard_snapshot SET created = ?, storage_key = ? WHERE id = ?", time.Now().Add(-2*time.Hour), "/1/key2/v1", stored.ID)
+		return err
+	})
+	require.NoError(t, err)
+
+	cmd := &dashboardsnapshots.DeleteExpiredSnapshotsCommand{OrgID: 1, CreatedBefore: time.Now().Add(-time.Hour)}
+	err = dashStore.DeleteExpiredSnapshots(context.Background(), cmd)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), cmd.DeletedRows)
+	require.Equal(t, []string{"/1/key2/v1"}, cmd.StorageKeys)
+
+	_, err = dashStore.GetDashboardSnapshot(context.Background(), &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "key1"})
+	require.NoError(t, err)
+	_, err = dashStore.GetDashboardSnapshot(context.Background(), &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "key2"})
+	require.ErrorIs(t, err, dashboardsnapshots.ErrBaseNotFound)
+}
+
+func TestIntegrationSnapshotPolicy(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	sqlstore := db.InitTestDB(t)
+	dashStore := NewStore(sqlstore)
+	ctx := context.Background()
+
+	t.Run("Should return an empty policy when none is saved", func(t *testing.T) {
+		policy, err := dashStore.GetSnapshotPolicy(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), policy.OrgID)
+		require.Zero(t, policy.MaxAge)
+		require.Zero(t, policy.MaxSize)
+		require.Empty(t, policy.ExternalRole)
+	})
+
+	t.Run("Should save and update the policy of an org", func(t *testing.T) {
+		_, err := dashStore.SaveSnapshotPolicy(ctx, &dashboardsnapshots.SaveSnapshotPolicyCommand{OrgID: 1, MaxAge: 3600, ExternalRole: org.RoleAdmin})
+		require.NoError(t, err)
+		_, err = dashStore.SaveSnapshotPolicy(ctx, &dashboardsnapshots.SaveSnapshotPolicyCommand{OrgID: 1, MaxAge: 60, MaxSize: 1024})
+		require.NoError(t, err)
+
+		policy, err := dashStore.GetSnapshotPolicy(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(60), policy.MaxAge)
+		require.Equal(t, int64(1024), policy.MaxSize)
+		require.Empty(t, policy.ExternalRole)
+
+		policies, err := dashStore.GetSnapshotPolicies(ctx)
+		require.NoError(t, err)
+		require.Len(t, policies, 1)
+	})
+}
+
+func TestIntegrationUpdateDashboardSnapshot(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	sqlstore := db.InitTestDB(t)
+	dashStore := NewStore(sqlstore)
+	ctx := context.Background()
+
+	created := createTestSnapshot(t, dashStore, "key1", 48000)
+	require.Equal(t, int64(1), created.Version)
+
+	err := dashStore.UpdateDashboardSnapshot(ctx, &dashboardsnapshots.UpdateDashboardSnapshotCommand{
+		ID:                 created.ID,
+		Version:            2,
+		DashboardEncrypted: []byte("refreshed"),
+	})
+	require.NoError(t, err)
+
+	updated, err := dashStore.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "key1"})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), updated.Version)
+	require.Equal(t, []byte("refreshed"), updated.DashboardEncrypted)
+	require.Equal(t, created.DeleteKey, updated.DeleteKey)
+
+	err = dashStore.UpdateDashboardSnapshot(ctx, &dashboardsnapshots.UpdateDashboardSnapshotCommand{ID: 9999, Version: 2})
+	require.ErrorIs(t, err, dashboardsnapshots.ErrBaseNotFound)
+}
+
 func createTestSnapshot(t *testing.T, dashStore *DashboardSnapshotStore, key string, expires int64) *dashboardsnapshots.DashboardSnapshot {
 	cmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
 		Key:       key,
@@ -5,3 +5,10 @@ import (
 )
 
 var ErrBaseNotFound = errutil.NotFound("dashboardsnapshots.not-found", errutil.WithPublicMessage("Snapshot not found"))
+
+var (
+	ErrSnapshotTooLarge          = errutil.BadRequest("dashboardsnapshots.too-large", errutil.WithPublicMessage("Snapshot exceeds the maximum size allowed by the organization"))
+	ErrExternalSnapshotForbidden = errutil.Forbidden("dashboardsnapshots.external-forbidden", errutil.WithPublicMessage("Your role is not allowed to create external snapshots"))
+	ErrRefreshExternalSnapshot   = errutil.BadRequest("dashboardsnapshots.refresh-external", errutil.WithPublicMessage("External snapshots cannot be refreshed"))
+	ErrInvalidSnapshotPolicy     = errutil.ValidationFailed("dashboardsnapshots.invalid-policy")
+)
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	snapshot "github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard/v0alpha1"
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
@@ -30,8 +31,14 @@ type DashboardSnapshot struct {
 	Created time.Time
 	Updated time.Time
 
+	// Version is incremented every time the snapshot is refreshed
+	Version int64
+
 	Dashboard          *simplejson.Json
 	DashboardEncrypted []byte
+	// StorageKey is the path of the encrypted dashboard in blob storage.
+	// It is empty when the dashboard is stored in DashboardEncrypted.
+	StorageKey string `xorm:"storage_key"`
 }
 
 // DashboardSnapshotDTO without dashboard map
@@ -43,12 +50,32 @@ type DashboardSnapshotDTO struct {
 	UserID      int64  `json:"-" xorm:"user_id"`
 	External    bool   `json:"external"`
 	ExternalURL string `json:"externalUrl" xorm:"external_url"`
+	Version     int64  `json:"version"`
 
 	Expires time.Time `json:"expires"`
 	Created time.Time `json:"created"`
 	Updated time.Time `json:"updated"`
 }
 
+// SnapshotPolicy restricts the snapshots that can be created within an organization
+type SnapshotPolicy struct {
+	ID    int64 `json:"-" xorm:"pk autoincr 'id'"`
+	OrgID int64 `json:"-" xorm:"org_id"`
+	// MaxAge is the longest lifetime of a snapshot in seconds, 0 means no limit
+	MaxAge int64 `json:"maxAge"`
+	// MaxSize is the largest dashboard payload of a snapshot in bytes, 0 means no limit
+	MaxSize int64 `json:"maxSize"`
+	// ExternalRole is the minimum org role required to create external snapshots.
+	// An empty role allows everyone who can create snapshots.
+	ExternalRole identity.RoleType `json:"externalRole"`
+
+	Updated time.Time `json:"updated"`
+}
+
+func (p SnapshotPolicy) TableName() string {
+	return "dashboard_snapshot_policy"
+}
+
 // -----------------
 // COMMANDS
 
@@ -73,6 +100,33 @@ type CreateDashboardSnapshotCommand struct {
 	UserID int64 `json:"-"`
 
 	DashboardEncrypted []byte `json:"-"`
+	StorageKey         string `json:"-"`
+}
+
+// swagger:model
+type RefreshDashboardSnapshotCommand struct {
+	// The dashboard with freshly queried snapshot data. It replaces the stored dashboard.
+	// required:true
+	Dashboard *common.Unstructured `json:"dashboard"`
+
+	Key   string `json:"-"`
+	OrgID int64  `json:"-"`
+}
+
+type UpdateDashboardSnapshotCommand struct {
+	ID                 int64
+	Version            int64
+	DashboardEncrypted []byte
+	StorageKey         string
+}
+
+// swagger:model
+type SaveSnapshotPolicyCommand struct {
+	MaxAge       int64             `json:"maxAge"`
+	MaxSize      int64             `json:"maxSize"`
+	ExternalRole identity.RoleType `json:"externalRole"`
+
+	OrgID int64 `json:"-"`
 }
 
 type DeleteDashboardSnapshotCommand struct {
@@ -80,7 +134,14 @@ type DeleteDashboardSnapshotCommand struct {
 }
 
 type DeleteExpiredSnapshotsCommand struct {
+	// When OrgID and CreatedBefore are set, the snapshots of the org created before
+	// the given time are deleted instead of the ones past their expiry date
+	OrgID         int64
+	CreatedBefore time.Time
+
 	DeletedRows int64
+	// StorageKeys of the deleted snapshots that were kept in blob storage
+	StorageKeys []string
 }
 
 type GetDashboardSnapshotQuery struct {
@@ -0,0 +1,40 @@
+package dashboardsnapshots
+
+import (
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+)
+
+// ApplyToCreate enforces the policy on a snapshot that is about to be created by a user with the given role.
+// A snapshot that would outlive the max age of the policy has its expiry capped to it.
+func (p *SnapshotPolicy) ApplyToCreate(cmd *CreateDashboardSnapshotCommand, role identity.RoleType) error {
+	if cmd.External && p.ExternalRole != "" && !role.Includes(p.ExternalRole) {
+		return ErrExternalSnapshotForbidden.Errorf("role %q is not allowed to create external snapshots", role)
+	}
+
+	if p.MaxAge > 0 && (cmd.Expires <= 0 || cmd.Expires > p.MaxAge) {
+		cmd.Expires = p.MaxAge
+	}
+
+	return nil
+}
+
+// ValidateSize checks the size of a marshalled snapshot dashboard against the policy.
+func (p *SnapshotPolicy) ValidateSize(size int) error {
+	if p.MaxSize > 0 && int64(size) > p.MaxSize {
+		return ErrSnapshotTooLarge.Errorf("snapshot size %d exceeds the limit of %d bytes", size, p.MaxSize)
+	}
+	return nil
+}
+
+func (cmd *SaveSnapshotPolicyCommand) Validate() error {
+	if cmd.MaxAge < 0 {
+		return ErrInvalidSnapshotPolicy.Errorf("maxAge can not be negative")
+	}
+	if cmd.MaxSize < 0 {
+		return ErrInvalidSnapshotPolicy.Errorf("maxSize can not be negative")
+	}
+	if cmd.ExternalRole != "" && !cmd.ExternalRole.IsValid() {
+		return ErrInvalidSnapshotPolicy.Errorf("invalid externalRole %q", cmd.ExternalRole)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package dashboardsnapshots
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	snapshot "github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+)
+
+func TestSnapshotPolicy(t *testing.T) {
+	t.Run("empty policy allows everything", func(t *testing.T) {
+		policy := &SnapshotPolicy{}
+		cmd := &CreateDashboardSnapshotCommand{DashboardCreateCommand: snapshot.DashboardCreateCommand{External: true}}
+
+		require.NoError(t, policy.ApplyToCreate(cmd, identity.RoleViewer))
+		require.Equal(t, int64(0), cmd.Expires)
+		require.NoError(t, policy.ValidateSize(1<<30))
+	})
+
+	t.Run("expiry is capped to the max age", func(t *testing.T) {
+		policy := &SnapshotPolicy{MaxAge: 3600}
+
+		never := &CreateDashboardSnapshotCommand{}
+		require.NoError(t, policy.ApplyToCreate(never, identity.RoleEditor))
+		require.Equal(t, int64(3600), never.Expires)
+
+		tooLong := &CreateDashboardSnapshotCommand{DashboardCreateCommand: snapshot.DashboardCreateCommand{Expires: 7200}}
+		require.NoError(t, policy.ApplyToCreate(tooLong, identity.RoleEditor))
+		require.Equal(t, int64(3600), tooLong.Expires)
+
+		short := &CreateDashboardSnapshotCommand{DashboardCreateCommand: snapshot.DashboardCreateCommand{Expires: 60}}
+		require.NoError(t, policy.ApplyToCreate(short, identity.RoleEditor))
+		require.Equal(t, int64(60), short.Expires)
+	})
+
+	t.Run("external snapshots require the configured role", func(t *testing.T) {
+		policy := &SnapshotPolicy{ExternalRole: identity.RoleAdmin}
+		cmd := &CreateDashboardSnapshotCommand{DashboardCreateCommand: snapshot.DashboardCreateCommand{External: true}}
+
+		err := policy.ApplyToCreate(cmd, identity.RoleEditor)
+		require.ErrorIs(t, err, ErrExternalSnapshotForbidden)
+		require.NoError(t, policy.ApplyToCreate(cmd, identity.RoleAdmin))
+
+		local := &CreateDashboardSnapshotCommand{}
+		require.NoError(t, policy.ApplyToCreate(local, identity.RoleViewer))
+	})
+
+	t.Run("snapshots above the max size are rejected", func(t *testing.T) {
+		policy := &SnapshotPolicy{MaxSize: 100}
+		require.NoError(t, policy.ValidateSize(100))
+		require.ErrorIs(t, policy.ValidateSize(101), ErrSnapshotTooLarge)
+	})
+
+	t.Run("validate save command", func(t *testing.T) {
+		require.NoError(t, (&SaveSnapshotPolicyCommand{MaxAge: 60, ExternalRole: identity.RoleEditor}).Validate())
+		require.ErrorIs(t, (&SaveSnapshotPolicyCommand{MaxAge: -1}).Validate(), ErrInvalidSnapshotPolicy)
+		require.ErrorIs(t, (&SaveSnapshotPolicyCommand{MaxSize: -1}).Validate(), ErrInvalidSnapshotPolicy)
+		require.ErrorIs(t, (&SaveSnapshotPolicyCommand{ExternalRole: "Owner"}).Validate(), ErrInvalidSnapshotPolicy)
+	})
+}
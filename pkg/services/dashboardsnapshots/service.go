@@ -28,6 +28,9 @@ type Service interface {
 	GetDashboardSnapshot(context.Context, *GetDashboardSnapshotQuery) (*DashboardSnapshot, error)
 	SearchDashboardSnapshots(context.Context, *GetDashboardSnapshotsQuery) (DashboardSnapshotsList, error)
 	ValidateDashboardExists(context.Context, int64, string) error
+	RefreshDashboardSnapshot(context.Context, *RefreshDashboardSnapshotCommand) (*DashboardSnapshot, error)
+	GetSnapshotPolicy(context.Context, int64) (*SnapshotPolicy, error)
+	SaveSnapshotPolicy(context.Context, *SaveSnapshotPolicyCommand) (*SnapshotPolicy, error)
 }
 
 var client = &http.Client{
@@ -67,6 +70,16 @@ func CreateDashboardSnapshot(c *contextmodel.ReqContext, cfg snapshot.SnapshotSh
 		return
 	}
 
+	policy, err := svc.GetSnapshotPolicy(c.Req.Context(), user.GetOrgID())
+	if err != nil {
+		c.JsonApiErr(http.StatusInternalServerError, "Failed to get snapshot policy", err)
+		return
+	}
+	if err := policy.ApplyToCreate(&cmd, user.GetOrgRole()); err != nil {
+		c.WriteErr(err)
+		return
+	}
+
 	cmd.ExternalURL = ""
 	cmd.OrgID = user.GetOrgID()
 	cmd.UserID, _ = identity.UserIdentifier(user.GetID())
@@ -132,7 +145,7 @@ func CreateDashboardSnapshotPublic(c *contextmodel.ReqContext, cfg snapshot.Snap
 func saveAndRespond(c *contextmodel.ReqContext, svc Service, cmd CreateDashboardSnapshotCommand, snapshotURL string) {
 	result, err := svc.CreateDashboardSnapshot(c.Req.Context(), &cmd)
 	if err != nil {
-		c.JsonApiErr(http.StatusInternalServerError, "Failed to create snapshot", err)
+		c.WriteErrOrFallback(http.StatusInternalServerError, "Failed to create snapshot", err)
 		return
 	}
 
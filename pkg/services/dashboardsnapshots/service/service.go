@@ -2,30 +2,60 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
 
+	"gocloud.dev/blob"
+
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+
+	// Supported blob storage drivers
+	_ "gocloud.dev/blob/azureblob"
+	_ "gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/memblob"
+	_ "gocloud.dev/blob/s3blob"
 )
 
 type ServiceImpl struct {
 	store            dashboardsnapshots.Store
 	secretsService   secrets.Service
 	dashboardService dashboards.DashboardService
+	// bodies stores the encrypted snapshot dashboards when blob storage is configured
+	bodies filestorage.FileStorage
+	log    log.Logger
 }
 
 // ServiceImpl implements the dashboardsnapshots Service interface
 var _ dashboardsnapshots.Service = (*ServiceImpl)(nil)
 
-func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Service, dashboardService dashboards.DashboardService) *ServiceImpl {
+func ProvideService(cfg *setting.Cfg, store dashboardsnapshots.Store, secretsService secrets.Service, dashboardService dashboards.DashboardService) (*ServiceImpl, error) {
 	s := &ServiceImpl{
 		store:            store,
 		secretsService:   secretsService,
 		dashboardService: dashboardService,
+		log:              log.New("dashboardsnapshots"),
+	}
+
+	if cfg.SnapshotStorageURL != "" {
+		bucket, err := blob.OpenBucket(context.Background(), cfg.SnapshotStorageURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshot storage: %w", err)
+		}
+		s.bodies = filestorage.NewCdkBlobStorage(s.log, bucket, "", nil)
 	}
 
-	return s
+	return s, nil
 }
 
 func (s *ServiceImpl) ValidateDashboardExists(ctx context.Context, orgId int64, dashboardUid string) error {
@@ -34,19 +64,82 @@ func (s *ServiceImpl) ValidateDashboardExists(ctx context.Context, orgId int64,
 }
 
 func (s *ServiceImpl) CreateDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.CreateDashboardSnapshotCommand) (*dashboardsnapshots.DashboardSnapshot, error) {
-	marshalledData, err := cmd.Dashboard.MarshalJSON()
+	encryptedDashboard, err := s.encryptDashboard(ctx, cmd.OrgID, cmd.Dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	// External snapshots only keep an empty dashboard, there is nothing worth moving to blob storage
+	if s.bodies == nil || cmd.External {
+		cmd.DashboardEncrypted = encryptedDashboard
+		return s.store.CreateDashboardSnapshot(ctx, cmd)
+	}
+
+	cmd.StorageKey = storageKey(cmd.OrgID, cmd.Key, 1)
+	if err := s.writeBody(ctx, cmd.StorageKey, encryptedDashboard); err != nil {
+		return nil, err
+	}
+
+	result, err := s.store.CreateDashboardSnapshot(ctx, cmd)
 	if err != nil {
+		s.deleteBody(ctx, cmd.StorageKey)
 		return nil, err
 	}
 
-	encryptedDashboard, err := s.secretsService.Encrypt(ctx, marshalledData, secrets.WithoutScope())
+	return result, nil
+}
+
+// RefreshDashboardSnapshot stores a new version of the snapshot dashboard under the same key.
+// The dashboard is rebuilt by the frontend share snapshot view, which re-runs the panel queries
+// the same way it does when the snapshot is created.
+// The key, delete key and expiry of the snapshot are kept.
+func (s *ServiceImpl) RefreshDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.RefreshDashboardSnapshotCommand) (*dashboardsnapshots.DashboardSnapshot, error) {
+	existing, err := s.store.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: cmd.Key})
 	if err != nil {
 		return nil, err
 	}
+	if existing.OrgID != cmd.OrgID {
+		return nil, dashboardsnapshots.ErrBaseNotFound.Errorf("dashboard snapshot not found")
+	}
+	if existing.External {
+		return nil, dashboardsnapshots.ErrRefreshExternalSnapshot.Errorf("snapshot %s is external", cmd.Key)
+	}
 
-	cmd.DashboardEncrypted = encryptedDashboard
+	encryptedDashboard, err := s.encryptDashboard(ctx, cmd.OrgID, cmd.Dashboard)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.store.CreateDashboardSnapshot(ctx, cmd)
+	update := &dashboardsnapshots.UpdateDashboardSnapshotCommand{
+		ID:      existing.ID,
+		Version: existing.Version + 1,
+	}
+	if s.bodies == nil {
+		update.DashboardEncrypted = encryptedDashboard
+	} else {
+		update.StorageKey = storageKey(existing.OrgID, existing.Key, update.Version)
+		if err := s.writeBody(ctx, update.StorageKey, encryptedDashboard); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.store.UpdateDashboardSnapshot(ctx, update); err != nil {
+		if update.StorageKey != "" {
+			s.deleteBody(ctx, update.StorageKey)
+		}
+		return nil, err
+	}
+
+	if existing.StorageKey != "" {
+		s.deleteBody(ctx, existing.StorageKey)
+	}
+
+	existing.Version = update.Version
+	existing.DashboardEncrypted = update.DashboardEncrypted
+	existing.StorageKey = update.StorageKey
+	existing.Updated = time.Now()
+
+	return existing, nil
 }
 
 func (s *ServiceImpl) GetDashboardSnapshot(ctx context.Context, query *dashboardsnapshots.GetDashboardSnapshotQuery) (*dashboardsnapshots.DashboardSnapshot, error) {
@@ -55,8 +148,16 @@ func (s *ServiceImpl) GetDashboardSnapshot(ctx context.Context, query *dashboard
 		return nil, err
 	}
 
-	if queryResult.DashboardEncrypted != nil {
-		decryptedDashboard, err := s.secretsService.Decrypt(ctx, queryResult.DashboardEncrypted)
+	encrypted := queryResult.DashboardEncrypted
+	if queryResult.StorageKey != "" {
+		encrypted, err = s.readBody(ctx, queryResult.StorageKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if encrypted != nil {
+		decryptedDashboard, err := s.secretsService.Decrypt(ctx, encrypted)
 		if err != nil {
 			return nil, err
 		}
@@ -73,13 +174,130 @@ func (s *ServiceImpl) GetDashboardSnapshot(ctx context.Context, query *dashboard
 }
 
 func (s *ServiceImpl) DeleteDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.DeleteDashboardSnapshotCommand) error {
-	return s.store.DeleteDashboardSnapshot(ctx, cmd)
+	var storageKey string
+	if s.bodies != nil {
+		existing, err := s.store.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{DeleteKey: cmd.DeleteKey})
+		if err != nil {
+			return err
+		}
+		storageKey = existing.StorageKey
+	}
+
+	if err := s.store.DeleteDashboardSnapshot(ctx, cmd); err != nil {
+		return err
+	}
+
+	if storageKey != "" {
+		s.deleteBody(ctx, storageKey)
+	}
+	return nil
 }
 
 func (s *ServiceImpl) SearchDashboardSnapshots(ctx context.Context, query *dashboardsnapshots.GetDashboardSnapshotsQuery) (dashboardsnapshots.DashboardSnapshotsList, error) {
 	return s.store.SearchDashboardSnapshots(ctx, query)
 }
 
+// DeleteExpiredSnapshots removes the snapshots past their expiry date and the snapshots
+// older than the max age of their org snapshot policy.
 func (s *ServiceImpl) DeleteExpiredSnapshots(ctx context.Context, cmd *dashboardsnapshots.DeleteExpiredSnapshotsCommand) error {
-	return s.store.DeleteExpiredSnapshots(ctx, cmd)
+	if err := s.store.DeleteExpiredSnapshots(ctx, cmd); err != nil {
+		return err
+	}
+
+	policies, err := s.store.GetSnapshotPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		if policy.MaxAge <= 0 {
+			continue
+		}
+
+		orgCmd := &dashboardsnapshots.DeleteExpiredSnapshotsCommand{
+			OrgID:         policy.OrgID,
+			CreatedBefore: time.Now().Add(-time.Duration(policy.MaxAge) * time.Second),
+		}
+		if err := s.store.DeleteExpiredSnapshots(ctx, orgCmd); err != nil {
+			return err
+		}
+		cmd.DeletedRows += orgCmd.DeletedRows
+		cmd.StorageKeys = append(cmd.StorageKeys, orgCmd.StorageKeys...)
+	}
+
+	for _, key := range cmd.StorageKeys {
+		s.deleteBody(ctx, key)
+	}
+
+	return nil
+}
+
+func (s *ServiceImpl) GetSnapshotPolicy(ctx context.Context, orgID int64) (*dashboardsnapshots.SnapshotPolicy, error) {
+	return s.store.GetSnapshotPolicy(ctx, orgID)
+}
+
+func (s *ServiceImpl) SaveSnapshotPolicy(ctx context.Context, cmd *dashboardsnapshots.SaveSnapshotPolicyCommand) (*dashboardsnapshots.SnapshotPolicy, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+	return s.store.SaveSnapshotPolicy(ctx, cmd)
+}
+
+// encryptDashboard marshals the dashboard, checks it against the size limit of the org policy and encrypts it.
+func (s *ServiceImpl) encryptDashboard(ctx context.Context, orgID int64, dashboard *common.Unstructured) ([]byte, error) {
+	marshalledData, err := dashboard.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.store.GetSnapshotPolicy(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.ValidateSize(len(marshalledData)); err != nil {
+		return nil, err
+	}
+
+	return s.secretsService.Encrypt(ctx, marshalledData, secrets.WithoutScope())
+}
+
+func (s *ServiceImpl) writeBody(ctx context.Context, path string, contents []byte) error {
+	return s.bodies.Upsert(ctx, &filestorage.UpsertFileCommand{
+		Path:     path,
+		MimeType: "application/octet-stream",
+		Contents: contents,
+	})
+}
+
+func (s *ServiceImpl) readBody(ctx context.Context, path string) ([]byte, error) {
+	if s.bodies == nil {
+		return nil, fmt.Errorf("snapshot is kept in blob storage but [snapshots] storage_url is not configured")
+	}
+
+	file, found, err := s.bodies.Get(ctx, path, &filestorage.GetFileOptions{WithContents: true})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, dashboardsnapshots.ErrBaseNotFound.Errorf("dashboard snapshot body not found")
+	}
+	return file.Contents, nil
+}
+
+// deleteBody removes a snapshot dashboard from blob storage. Failures are only logged
+// since the snapshot row, which is the source of truth, is already gone.
+func (s *ServiceImpl) deleteBody(ctx context.Context, path string) {
+	if s.bodies == nil {
+		return
+	}
+	if err := s.bodies.Delete(ctx, path); err != nil {
+		s.log.Warn("Failed to delete snapshot from blob storage", "path", path, "err", err)
+	}
+}
+
+// storageKey returns the blob storage path of a snapshot version. The snapshot key is
+// hashed since keys are user defined and may contain characters that are not valid in paths.
+func storageKey(orgID int64, key string, version int64) string {
+	hash := sha256.Sum256([]byte(key))
+	return filestorage.Join(strconv.FormatInt(orgID, 10), hex.EncodeToString(hash[:]), "v"+strconv.FormatInt(version, 10))
 }
@@ -32,7 +32,8 @@ func TestIntegrationDashboardSnapshotsService(t *testing.T) {
 	dsStore := dashsnapdb.ProvideStore(sqlStore, cfg)
 	fakeDashboardService := &dashboards.FakeDashboardService{}
 	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
-	s := ProvideService(dsStore, secretsService, fakeDashboardService)
+	s, err := ProvideService(cfg, dsStore, secretsService, fakeDashboardService)
+	require.NoError(t, err)
 
 	origSecret := cfg.SecretKey
 	cfg.SecretKey = "dashboard_snapshot_service_test"
@@ -44,7 +45,7 @@ func TestIntegrationDashboardSnapshotsService(t *testing.T) {
 
 	dashboard := &common.Unstructured{}
 	rawDashboard := []byte(`{"id":123}`)
-	err := json.Unmarshal(rawDashboard, dashboard)
+	err = json.Unmarshal(rawDashboard, dashboard)
 	require.NoError(t, err)
 
 	t.Run("create dashboard snapshot should encrypt the dashboard", func(t *testing.T) {
@@ -82,4 +83,112 @@ func TestIntegrationDashboardSnapshotsService(t *testing.T) {
 
 		require.Equal(t, rawDashboard, decrypted)
 	})
+
+	t.Run("refresh dashboard snapshot should store a new version under the same key", func(t *testing.T) {
+		ctx := context.Background()
+
+		refreshed := &common.Unstructured{}
+		rawRefreshed := []byte(`{"id":123,"title":"refreshed"}`)
+		require.NoError(t, json.Unmarshal(rawRefreshed, refreshed))
+
+		result, err := s.RefreshDashboardSnapshot(ctx, &dashboardsnapshots.RefreshDashboardSnapshotCommand{
+			Key:       dashboardKey,
+			Dashboard: refreshed,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.Version)
+
+		queryResult, err := s.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: dashboardKey})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), queryResult.Version)
+
+		decrypted, err := queryResult.Dashboard.Encode()
+		require.NoError(t, err)
+		require.Equal(t, rawRefreshed, decrypted)
+	})
+
+	t.Run("create dashboard snapshot should fail when it exceeds the org policy size", func(t *testing.T) {
+		ctx := context.Background()
+
+		_, err := s.SaveSnapshotPolicy(ctx, &dashboardsnapshots.SaveSnapshotPolicyCommand{OrgID: 2, MaxSize: 5})
+		require.NoError(t, err)
+
+		_, err = s.CreateDashboardSnapshot(ctx, &dashboardsnapshots.CreateDashboardSnapshotCommand{
+			Key:       "too-large",
+			DeleteKey: "too-large",
+			OrgID:     2,
+			DashboardCreateCommand: snapshot.DashboardCreateCommand{
+				Dashboard: dashboard,
+			},
+		})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrSnapshotTooLarge)
+	})
+}
+
+func TestIntegrationDashboardSnapshotsServiceBlobStorage(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.SnapshotStorageURL = "mem://"
+	dsStore := dashsnapdb.ProvideStore(sqlStore, cfg)
+	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	s, err := ProvideService(cfg, dsStore, secretsService, &dashboards.FakeDashboardService{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	dashboard := &common.Unstructured{}
+	rawDashboard := []byte(`{"id":123}`)
+	require.NoError(t, json.Unmarshal(rawDashboard, dashboard))
+
+	result, err := s.CreateDashboardSnapshot(ctx, &dashboardsnapshots.CreateDashboardSnapshotCommand{
+		Key:       "blob-key",
+		DeleteKey: "blob-delete-key",
+		OrgID:     1,
+		DashboardCreateCommand: snapshot.DashboardCreateCommand{
+			Dashboard: dashboard,
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.DashboardEncrypted)
+	require.NotEmpty(t, result.StorageKey)
+
+	t.Run("get dashboard snapshot should read the dashboard from blob storage", func(t *testing.T) {
+		queryResult, err := s.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "blob-key"})
+		require.NoError(t, err)
+
+		decrypted, err := queryResult.Dashboard.Encode()
+		require.NoError(t, err)
+		require.Equal(t, rawDashboard, decrypted)
+	})
+
+	t.Run("refresh should replace the blob of the previous version", func(t *testing.T) {
+		refreshed, err := s.RefreshDashboardSnapshot(ctx, &dashboardsnapshots.RefreshDashboardSnapshotCommand{
+			Key:       "blob-key",
+			OrgID:     1,
+			Dashboard: dashboard,
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, result.StorageKey, refreshed.StorageKey)
+
+		_, found, err := s.bodies.Get(ctx, result.StorageKey, nil)
+		require.NoError(t, err)
+		require.False(t, found)
+
+		_, found, err = s.bodies.Get(ctx, refreshed.StorageKey, nil)
+		require.NoError(t, err)
+		require.True(t, found)
+	})
+
+	t.Run("delete should remove the blob", func(t *testing.T) {
+		existing, err := s.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "blob-key"})
+		require.NoError(t, err)
+
+		err = s.DeleteDashboardSnapshot(ctx, &dashboardsnapshots.DeleteDashboardSnapshotCommand{DeleteKey: "blob-delete-key"})
+		require.NoError(t, err)
+
+		_, found, err := s.bodies.Get(ctx, existing.StorageKey, nil)
+		require.NoError(t, err)
+		require.False(t, found)
+	})
 }
@@ -109,6 +109,96 @@ func (_m *MockService) GetDashboardSnapshot(_a0 context.Context, _a1 *GetDashboa
 	return r0, r1
 }
 
+// GetSnapshotPolicy provides a mock function with given fields: _a0, _a1
+func (_m *MockService) GetSnapshotPolicy(_a0 context.Context, _a1 int64) (*SnapshotPolicy, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSnapshotPolicy")
+	}
+
+	var r0 *SnapshotPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*SnapshotPolicy, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *SnapshotPolicy); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*SnapshotPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshDashboardSnapshot provides a mock function with given fields: _a0, _a1
+func (_m *MockService) RefreshDashboardSnapshot(_a0 context.Context, _a1 *RefreshDashboardSnapshotCommand) (*DashboardSnapshot, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshDashboardSnapshot")
+	}
+
+	var r0 *DashboardSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *RefreshDashboardSnapshotCommand) (*DashboardSnapshot, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *RefreshDashboardSnapshotCommand) *DashboardSnapshot); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *RefreshDashboardSnapshotCommand) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveSnapshotPolicy provides a mock function with given fields: _a0, _a1
+func (_m *MockService) SaveSnapshotPolicy(_a0 context.Context, _a1 *SaveSnapshotPolicyCommand) (*SnapshotPolicy, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSnapshotPolicy")
+	}
+
+	var r0 *SnapshotPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *SaveSnapshotPolicyCommand) (*SnapshotPolicy, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *SaveSnapshotPolicyCommand) *SnapshotPolicy); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*SnapshotPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *SaveSnapshotPolicyCommand) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SearchDashboardSnapshots provides a mock function with given fields: _a0, _a1
 func (_m *MockService) SearchDashboardSnapshots(_a0 context.Context, _a1 *GetDashboardSnapshotsQuery) (DashboardSnapshotsList, error) {
 	ret := _m.Called(_a0, _a1)
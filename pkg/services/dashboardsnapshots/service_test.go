@@ -127,6 +127,8 @@ func TestCreateDashboardSnapshot(t *testing.T) {
 
 		mockService.On("ValidateDashboardExists", mock.Anything, int64(1), "test-dashboard-uid").
 			Return(nil)
+		mockService.On("GetSnapshotPolicy", mock.Anything, int64(1)).
+			Return(&SnapshotPolicy{OrgID: 1}, nil)
 		mockService.On("CreateDashboardSnapshot", mock.Anything, mock.Anything).
 			Return(&DashboardSnapshot{
 				Key:       "external-key",
@@ -181,6 +183,39 @@ func TestCreateDashboardSnapshot(t *testing.T) {
 		assert.Equal(t, "External dashboard creation is disabled", response["message"])
 	})
 
+	t.Run("should return forbidden when the snapshot policy does not allow external snapshots", func(t *testing.T) {
+		mockService := NewMockService(t)
+		cfg := snapshot.SnapshotSharingOptions{
+			SnapshotsEnabled: true,
+			ExternalEnabled:  true,
+		}
+		testUser := createTestUser()
+		testUser.OrgRole = identity.RoleEditor
+		dashboard := createTestDashboard(t)
+
+		cmd := CreateDashboardSnapshotCommand{
+			DashboardCreateCommand: snapshot.DashboardCreateCommand{
+				Dashboard: dashboard,
+				Name:      "Test External Snapshot",
+				External:  true,
+			},
+		}
+
+		mockService.On("ValidateDashboardExists", mock.Anything, int64(1), "test-dashboard-uid").
+			Return(nil)
+		mockService.On("GetSnapshotPolicy", mock.Anything, int64(1)).
+			Return(&SnapshotPolicy{OrgID: 1, ExternalRole: identity.RoleAdmin}, nil)
+
+		req, _ := http.NewRequest("POST", "/api/snapshots", nil)
+		req = req.WithContext(identity.WithRequester(req.Context(), testUser))
+		ctx, recorder := createReqContext(t, req, testUser)
+
+		CreateDashboardSnapshot(ctx, cfg, cmd, mockService)
+
+		mockService.AssertExpectations(t)
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+
 	t.Run("should create local snapshot", func(t *testing.T) {
 		mockService := NewMockService(t)
 		cfg := snapshot.SnapshotSharingOptions{
@@ -200,6 +235,8 @@ func TestCreateDashboardSnapshot(t *testing.T) {
 
 		mockService.On("ValidateDashboardExists", mock.Anything, int64(1), "test-dashboard-uid").
 			Return(nil)
+		mockService.On("GetSnapshotPolicy", mock.Anything, int64(1)).
+			Return(&SnapshotPolicy{OrgID: 1}, nil)
 		mockService.On("CreateDashboardSnapshot", mock.Anything, mock.Anything).
 			Return(&DashboardSnapshot{
 				Key:       "local-key",
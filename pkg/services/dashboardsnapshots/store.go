@@ -6,8 +6,13 @@ import (
 
 type Store interface {
 	CreateDashboardSnapshot(context.Context, *CreateDashboardSnapshotCommand) (*DashboardSnapshot, error)
+	UpdateDashboardSnapshot(context.Context, *UpdateDashboardSnapshotCommand) error
 	DeleteDashboardSnapshot(context.Context, *DeleteDashboardSnapshotCommand) error
 	DeleteExpiredSnapshots(context.Context, *DeleteExpiredSnapshotsCommand) error
 	GetDashboardSnapshot(context.Context, *GetDashboardSnapshotQuery) (*DashboardSnapshot, error)
 	SearchDashboardSnapshots(context.Context, *GetDashboardSnapshotsQuery) (DashboardSnapshotsList, error)
+
+	GetSnapshotPolicy(ctx context.Context, orgID int64) (*SnapshotPolicy, error)
+	GetSnapshotPolicies(context.Context) ([]*SnapshotPolicy, error)
+	SaveSnapshotPolicy(context.Context, *SaveSnapshotPolicyCommand) (*SnapshotPolicy, error)
 }
@@ -0,0 +1,77 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/supportbundles"
+)
+
+type liveSupportInfo struct {
+	HAEngine     string                            `json:"ha_engine"`
+	Nodes        int                               `json:"nodes"`
+	Clients      int                               `json:"clients"`
+	Users        int                               `json:"users"`
+	Channels     int                               `json:"channels"`
+	Pipeline     bool                              `json:"pipeline"`
+	ChannelRules map[string][]pipeline.ChannelRule `json:"channel_rules,omitempty"`
+	Error        string                            `json:"error,omitempty"`
+}
+
+// SupportBundleCollector returns a collector that adds the state of the Live node and the
+// pipeline channel rules of every organization returned by orgIDs to support bundles.
+func (g *GrafanaLive) SupportBundleCollector(orgIDs func(ctx context.Context) ([]int64, error)) supportbundles.Collector {
+	return supportbundles.Collector{
+		UID:               "live",
+		DisplayName:       "Grafana Live",
+		Description:       "Connections, channels and pipeline channel rules of Grafana Live",
+		IncludedByDefault: false,
+		Default:           false,
+		Fn: func(ctx context.Context) (*supportbundles.SupportItem, error) {
+			info := liveSupportInfo{
+				HAEngine: g.Cfg.LiveHAEngine,
+				Pipeline: g.Pipeline != nil,
+			}
+
+			if g.node != nil {
+				info.Clients = g.node.Hub().NumClients()
+				info.Users = g.node.Hub().NumUsers()
+				info.Channels = g.node.Hub().NumChannels()
+				if nodeInfo, err := g.node.Info(); err == nil {
+					info.Nodes = len(nodeInfo.Nodes)
+				}
+			}
+
+			if g.pipelineStorage != nil {
+				ids, err := orgIDs(ctx)
+				if err != nil {
+					info.Error = err.Error()
+				}
+
+				info.ChannelRules = map[string][]pipeline.ChannelRule{}
+				mapper := request.GetNamespaceMapper(g.Cfg)
+				for _, orgID := range ids {
+					ns := mapper(orgID)
+					rules, err := g.pipelineStorage.ListChannelRules(ctx, ns)
+					if err != nil {
+						info.Error = err.Error()
+						continue
+					}
+					info.ChannelRules[ns] = rules
+				}
+			}
+
+			data, err := json.MarshalIndent(info, "", " ")
+			if err != nil {
+				return nil, err
+			}
+
+			return &supportbundles.SupportItem{
+				Filename:  "live.json",
+				FileBytes: data,
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,80 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeChannelRuleStorage struct {
+	pipeline.Storage
+	rules map[string][]pipeline.ChannelRule
+}
+
+func (s *fakeChannelRuleStorage) ListChannelRules(_ context.Context, ns string) ([]pipeline.ChannelRule, error) {
+	rules, ok := s.rules[ns]
+	if !ok {
+		return nil, errors.New("storage unavailable")
+	}
+	return rules, nil
+}
+
+func TestSupportBundleCollector(t *testing.T) {
+	collect := func(t *testing.T, g *GrafanaLive, orgIDs func(ctx context.Context) ([]int64, error)) liveSupportInfo {
+		t.Helper()
+		collector := g.SupportBundleCollector(orgIDs)
+		require.Equal(t, "live", collector.UID)
+
+		item, err := collector.Fn(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "live.json", item.Filename)
+
+		var info liveSupportInfo
+		require.NoError(t, json.Unmarshal(item.FileBytes, &info))
+		return info
+	}
+
+	t.Run("without a node or a pipeline", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.LiveHAEngine = "redis"
+
+		info := collect(t, &GrafanaLive{Cfg: cfg}, func(ctx context.Context) ([]int64, error) {
+			t.Fatal("the organizations are only listed with a pipeline storage")
+			return nil, nil
+		})
+		require.Equal(t, liveSupportInfo{HAEngine: "redis"}, info)
+	})
+
+	t.Run("lists the channel rules of every organization", func(t *testing.T) {
+		storage := &fakeChannelRuleStorage{rules: map[string][]pipeline.ChannelRule{
+			"default": {{Pattern: "stream/telegraf/cpu"}},
+			"org-2":   {},
+		}}
+		g := &GrafanaLive{Cfg: setting.NewCfg(), pipelineStorage: storage}
+
+		info := collect(t, g, func(ctx context.Context) ([]int64, error) {
+			return []int64{1, 2, 3}, nil
+		})
+		require.Len(t, info.ChannelRules, 2)
+		require.Equal(t, "stream/telegraf/cpu", info.ChannelRules["default"][0].Pattern)
+		require.Empty(t, info.ChannelRules["org-2"])
+		// the organizations that fail are reported without failing the collector
+		require.Equal(t, "storage unavailable", info.Error)
+	})
+
+	t.Run("reports the errors listing the organizations", func(t *testing.T) {
+		g := &GrafanaLive{Cfg: setting.NewCfg(), pipelineStorage: &fakeChannelRuleStorage{}}
+
+		info := collect(t, g, func(ctx context.Context) ([]int64, error) {
+			return nil, errors.New("database unavailable")
+		})
+		require.Empty(t, info.ChannelRules)
+		require.Equal(t, "database unavailable", info.Error)
+	})
+}
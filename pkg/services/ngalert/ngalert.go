@@ -158,6 +158,7 @@ type AlertNG struct {
 	RecordingWriter       schedule.RecordingWriter
 	schedule              schedule.ScheduleService
 	stateManager          *state.Manager
+	historian             Historian
 	folderService         folder.Service
 	dashboardService      dashboards.DashboardService
 	Api                   *api.API
@@ -382,6 +383,7 @@ func (ng *AlertNG) init() error {
 	if err != nil {
 		return err
 	}
+	ng.historian = history
 
 	ng.InstanceStore, ng.StartupInstanceReader = initInstanceStore(ng.store.SQLStore, ng.Log, ng.FeatureToggles)
 
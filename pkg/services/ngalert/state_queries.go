@@ -0,0 +1,118 @@
+package ngalert
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	ac "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+var errStateHistoryUnavailable = errors.New("alert state history is not available")
+
+// QueryStateHistory returns the state transitions of alert rules recorded by the configured
+// state history backend. Access to the rules is checked by the backend using query.SignedInUser.
+func (ng *AlertNG) QueryStateHistory(ctx context.Context, query ngmodels.HistoryQuery) (*data.Frame, error) {
+	if ng.historian == nil {
+		return nil, errStateHistoryUnavailable
+	}
+	return ng.historian.Query(ctx, query)
+}
+
+// QueryAlertInstances returns the current state of the alert instances of the organization of the user
+// as a single frame with one row per instance. Instances of rules the user cannot read are left out.
+// When ruleUIDs or states are not empty, only the instances of these rules and in these states are returned.
+func (ng *AlertNG) QueryAlertInstances(ctx context.Context, user identity.Requester, ruleUIDs []string, states ...eval.State) (*data.Frame, error) {
+	orgID := user.GetOrgID()
+	rules, err := ng.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:    orgID,
+		RuleUIDs: ruleUIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byUID, err := readableRules(ctx, user, ac.NewRuleService(ng.accesscontrol), rules)
+	if err != nil {
+		return nil, err
+	}
+	return alertInstancesFrame(byUID, ng.stateManager.GetAll(ctx, orgID), states), nil
+}
+
+type folderAccessChecker interface {
+	HasAccessInFolder(ctx context.Context, user identity.Requester, rule ngmodels.Namespaced) (bool, error)
+}
+
+// readableRules returns the rules the user can read by their UID. Access is checked once per folder.
+func readableRules(ctx context.Context, user identity.Requester, checker folderAccessChecker, rules []*ngmodels.AlertRule) (map[string]*ngmodels.AlertRule, error) {
+	byUID := make(map[string]*ngmodels.AlertRule, len(rules))
+	canRead := make(map[string]bool)
+	for _, rule := range rules {
+		ok, found := canRead[rule.NamespaceUID]
+		if !found {
+			var err error
+			ok, err = checker.HasAccessInFolder(ctx, user, rule)
+			if err != nil {
+				return nil, err
+			}
+			canRead[rule.NamespaceUID] = ok
+		}
+		if ok {
+			byUID[rule.UID] = rule
+		}
+	}
+	return byUID, nil
+}
+
+// alertInstancesFrame returns a frame with one row for each instance of the given rules.
+// When states is not empty, only the instances in these states are returned.
+func alertInstancesFrame(rules map[string]*ngmodels.AlertRule, instances []*state.State, states []eval.State) *data.Frame {
+	var (
+		ruleUIDField   []string
+		titleField     []string
+		folderField    []string
+		groupField     []string
+		stateField     []string
+		reasonField    []string
+		labelsField    []string
+		startsAtField  []time.Time
+		lastEvalField  []time.Time
+		lastValueField []string
+	)
+	for _, st := range instances {
+		rule, ok := rules[st.AlertRuleUID]
+		if !ok || (len(states) > 0 && !slices.Contains(states, st.State)) {
+			continue
+		}
+		ruleUIDField = append(ruleUIDField, rule.UID)
+		titleField = append(titleField, rule.Title)
+		folderField = append(folderField, rule.NamespaceUID)
+		groupField = append(groupField, rule.RuleGroup)
+		stateField = append(stateField, st.State.String())
+		reasonField = append(reasonField, st.StateReason)
+		labelsField = append(labelsField, st.Labels.String())
+		startsAtField = append(startsAtField, st.StartsAt)
+		lastEvalField = append(lastEvalField, st.LastEvaluationTime)
+		lastValueField = append(lastValueField, st.LastEvaluationString)
+	}
+
+	return data.NewFrame("alert_instances",
+		data.NewField("ruleUID", nil, ruleUIDField),
+		data.NewField("title", nil, titleField),
+		data.NewField("folderUID", nil, folderField),
+		data.NewField("group", nil, groupField),
+		data.NewField("state", nil, stateField),
+		data.NewField("reason", nil, reasonField),
+		data.NewField("labels", nil, labelsField),
+		data.NewField("startsAt", nil, startsAtField),
+		data.NewField("lastEvaluation", nil, lastEvalField),
+		data.NewField("lastValue", nil, lastValueField),
+	)
+}
@@ -0,0 +1,163 @@
+package ngalert
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	acfakes "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestQueryStateHistory(t *testing.T) {
+	t.Run("fails without state history backend", func(t *testing.T) {
+		ng := &AlertNG{}
+		_, err := ng.QueryStateHistory(context.Background(), ngmodels.HistoryQuery{})
+		require.ErrorIs(t, err, errStateHistoryUnavailable)
+	})
+
+	t.Run("queries the state history backend", func(t *testing.T) {
+		historian := &fakeQueryHistorian{frame: data.NewFrame("states")}
+		ng := &AlertNG{historian: historian}
+
+		query := ngmodels.HistoryQuery{OrgID: 1, RuleUID: "rule"}
+		frame, err := ng.QueryStateHistory(context.Background(), query)
+		require.NoError(t, err)
+		require.Equal(t, historian.frame, frame)
+		require.Equal(t, query, historian.query)
+	})
+}
+
+func TestReadableRules(t *testing.T) {
+	rules := []*ngmodels.AlertRule{
+		{UID: "a", NamespaceUID: "allowed"},
+		{UID: "b", NamespaceUID: "denied"},
+		{UID: "c", NamespaceUID: "allowed"},
+	}
+
+	tests := []struct {
+		name     string
+		allowed  map[string]bool
+		err      error
+		expected []string
+	}{
+		{
+			name:     "rules in readable folders",
+			allowed:  map[string]bool{"allowed": true},
+			expected: []string{"a", "c"},
+		},
+		{
+			name:     "no readable folder",
+			allowed:  map[string]bool{},
+			expected: []string{},
+		},
+		{
+			name: "access check fails",
+			err:  errors.New("boom"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &acfakes.FakeRuleService{
+				HasAccessInFolderFunc: func(_ context.Context, _ identity.Requester, rule ngmodels.Namespaced) (bool, error) {
+					if tt.err != nil {
+						return false, tt.err
+					}
+					return tt.allowed[rule.GetNamespaceUID()], nil
+				},
+			}
+
+			byUID, err := readableRules(context.Background(), &user.SignedInUser{OrgID: 1}, checker, rules)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			uids := make([]string, 0, len(byUID))
+			for uid := range byUID {
+				uids = append(uids, uid)
+			}
+			require.ElementsMatch(t, tt.expected, uids)
+			// access is checked once per folder
+			require.Len(t, checker.Calls, 2)
+		})
+	}
+}
+
+func TestAlertInstancesFrame(t *testing.T) {
+	startsAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rules := map[string]*ngmodels.AlertRule{
+		"a": {UID: "a", Title: "CPU", NamespaceUID: "folder", RuleGroup: "group"},
+	}
+	instances := []*state.State{
+		{
+			AlertRuleUID: "a", State: eval.Alerting, StateReason: "", Labels: data.Labels{"host": "1"},
+			StartsAt: startsAt, LastEvaluationTime: startsAt.Add(time.Minute), LastEvaluationString: "[ var='A' value=90 ]",
+		},
+		{AlertRuleUID: "a", State: eval.Normal, Labels: data.Labels{"host": "2"}, StartsAt: startsAt, LastEvaluationTime: startsAt},
+		{AlertRuleUID: "unreadable", State: eval.Alerting},
+	}
+
+	tests := []struct {
+		name   string
+		states []eval.State
+		hosts  []string
+	}{
+		{
+			name:  "all states",
+			hosts: []string{"host=1", "host=2"},
+		},
+		{
+			name:   "filtered by state",
+			states: []eval.State{eval.Alerting},
+			hosts:  []string{"host=1"},
+		},
+		{
+			name:   "no matching state",
+			states: []eval.State{eval.Pending},
+			hosts:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame := alertInstancesFrame(rules, instances, tt.states)
+			require.Equal(t, "alert_instances", frame.Name)
+
+			labels, _ := frame.FieldByName("labels")
+			hosts := make([]string, 0, labels.Len())
+			for i := 0; i < labels.Len(); i++ {
+				hosts = append(hosts, labels.At(i).(string))
+			}
+			require.Equal(t, tt.hosts, hosts)
+		})
+	}
+
+	t.Run("row values", func(t *testing.T) {
+		frame := alertInstancesFrame(rules, instances[:1], nil)
+		require.Equal(t, []any{
+			"a", "CPU", "folder", "group", "Alerting", "", "host=1", startsAt, startsAt.Add(time.Minute), "[ var='A' value=90 ]",
+		}, frame.RowCopy(0))
+	})
+}
+
+type fakeQueryHistorian struct {
+	Historian
+	query ngmodels.HistoryQuery
+	frame *data.Frame
+}
+
+func (h *fakeQueryHistorian) Query(_ context.Context, query ngmodels.HistoryQuery) (*data.Frame, error) {
+	h.query = query
+	return h.frame, nil
+}
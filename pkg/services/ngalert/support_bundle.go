@@ -0,0 +1,158 @@
+package ngalert
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/supportbundles"
+)
+
+// SupportBundleCollectors returns the collectors that add the state of the alert rule scheduler
+// and of the Alertmanagers to support bundles.
+func (ng *AlertNG) SupportBundleCollectors() []supportbundles.Collector {
+	return []supportbundles.Collector{
+		{
+			UID:               "alerting-scheduler",
+			DisplayName:       "Alerting scheduler",
+			Description:       "Alert rules known to the scheduler with their last evaluation and the state of their alerts",
+			IncludedByDefault: false,
+			Default:           false,
+			Fn:                ng.collectSchedulerState,
+		},
+		{
+			UID:               "alerting-alertmanager",
+			DisplayName:       "Alertmanager",
+			Description:       "Alertmanager configuration, silences and readiness per organization",
+			IncludedByDefault: false,
+			Default:           false,
+			Fn:                ng.collectAlertmanagerState,
+		},
+	}
+}
+
+type schedulerRuleState struct {
+	OrgID               int64          `json:"org_id"`
+	UID                 string         `json:"uid"`
+	Title               string         `json:"title"`
+	NamespaceUID        string         `json:"namespace_uid"`
+	RuleGroup           string         `json:"rule_group"`
+	IntervalSeconds     int64          `json:"interval_seconds"`
+	IsPaused            bool           `json:"is_paused"`
+	Scheduled           bool           `json:"scheduled"`
+	Health              string         `json:"health,omitempty"`
+	LastError           string         `json:"last_error,omitempty"`
+	EvaluationTimestamp *time.Time     `json:"evaluation_timestamp,omitempty"`
+	EvaluationDuration  string         `json:"evaluation_duration,omitempty"`
+	States              map[string]int `json:"states"`
+}
+
+func (ng *AlertNG) collectSchedulerState(ctx context.Context) (*supportbundles.SupportItem, error) {
+	type ruleLister interface {
+		Rules() ([]*ngmodels.AlertRule, map[ngmodels.FolderKey]string)
+	}
+
+	result := make([]schedulerRuleState, 0)
+	if lister, ok := ng.schedule.(ruleLister); ok {
+		rules, _ := lister.Rules()
+		for _, rule := range rules {
+			rs := schedulerRuleState{
+				OrgID:           rule.OrgID,
+				UID:             rule.UID,
+				Title:           rule.Title,
+				NamespaceUID:    rule.NamespaceUID,
+				RuleGroup:       rule.RuleGroup,
+				IntervalSeconds: rule.IntervalSeconds,
+				IsPaused:        rule.IsPaused,
+				States:          map[string]int{},
+			}
+
+			if status, ok := ng.schedule.Status(ctx, rule.GetKey()); ok {
+				rs.Scheduled = true
+				rs.Health = status.Health
+				if status.LastError != nil {
+					rs.LastError = status.LastError.Error()
+				}
+				if !status.EvaluationTimestamp.IsZero() {
+					ts := status.EvaluationTimestamp
+					rs.EvaluationTimestamp = &ts
+				}
+				rs.EvaluationDuration = status.EvaluationDuration.String()
+			}
+
+			for _, s := range ng.stateManager.GetStatesForRuleUID(ctx, rule.OrgID, rule.UID) {
+				rs.States[s.State.String()]++
+			}
+
+			result = append(result, rs)
+		}
+	}
+
+	data, err := json.MarshalIndent(result, "", " ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &supportbundles.SupportItem{
+		Filename:  "alerting-scheduler.json",
+		FileBytes: data,
+	}, nil
+}
+
+type alertmanagerState struct {
+	OrgID             int64                      `json:"org_id"`
+	Ready             bool                       `json:"ready"`
+	ConfigurationHash string                     `json:"configuration_hash"`
+	Configuration     json.RawMessage            `json:"configuration"`
+	CreatedAt         time.Time                  `json:"created_at"`
+	Default           bool                       `json:"default"`
+	Silences          apimodels.GettableSilences `json:"silences,omitempty"`
+	Error             string                     `json:"error,omitempty"`
+}
+
+func (ng *AlertNG) collectAlertmanagerState(ctx context.Context) (*supportbundles.SupportItem, error) {
+	configs, err := ng.store.GetAllLatestAlertmanagerConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]alertmanagerState, 0, len(configs))
+	for _, cfg := range configs {
+		state := alertmanagerState{
+			OrgID:             cfg.OrgID,
+			ConfigurationHash: cfg.ConfigurationHash,
+			CreatedAt:         time.Unix(cfg.CreatedAt, 0).UTC(),
+			Default:           cfg.Default,
+		}
+		if json.Valid([]byte(cfg.AlertmanagerConfiguration)) {
+			state.Configuration = json.RawMessage(cfg.AlertmanagerConfiguration)
+		}
+
+		am, err := ng.MultiOrgAlertmanager.AlertmanagerFor(cfg.OrgID)
+		if am == nil {
+			state.Error = err.Error()
+			result = append(result, state)
+			continue
+		}
+
+		state.Ready = am.Ready()
+		silences, err := am.ListSilences(ctx, nil)
+		if err != nil {
+			state.Error = err.Error()
+		}
+		state.Silences = silences
+		result = append(result, state)
+	}
+
+	data, err := json.MarshalIndent(result, "", " ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &supportbundles.SupportItem{
+		Filename:  "alerting-alertmanager.json",
+		FileBytes: data,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package ngalert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+// supportBundleSchedule is a scheduler that knows a fixed set of rules
+type supportBundleSchedule struct {
+	testSchedule
+	rules    []*models.AlertRule
+	statuses map[models.AlertRuleKey]models.RuleStatus
+}
+
+func (s *supportBundleSchedule) Rules() ([]*models.AlertRule, map[models.FolderKey]string) {
+	return s.rules, nil
+}
+
+func (s *supportBundleSchedule) Status(_ context.Context, key models.AlertRuleKey) (models.RuleStatus, bool) {
+	status, ok := s.statuses[key]
+	return status, ok
+}
+
+func TestSupportBundleCollectors(t *testing.T) {
+	ng := &AlertNG{}
+	collectors := ng.SupportBundleCollectors()
+	uids := make([]string, 0, len(collectors))
+	for _, c := range collectors {
+		require.False(t, c.IncludedByDefault, c.UID)
+		uids = append(uids, c.UID)
+	}
+	require.Equal(t, []string{"alerting-scheduler", "alerting-alertmanager"}, uids)
+}
+
+func TestCollectSchedulerState(t *testing.T) {
+	evaluated := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	healthy := &models.AlertRule{OrgID: 1, UID: "healthy", Title: "Healthy", NamespaceUID: "folder", RuleGroup: "group", IntervalSeconds: 60}
+	failing := &models.AlertRule{OrgID: 1, UID: "failing", Title: "Failing", NamespaceUID: "folder", RuleGroup: "group", IntervalSeconds: 60}
+	unscheduled := &models.AlertRule{OrgID: 2, UID: "paused", Title: "Paused", NamespaceUID: "folder", RuleGroup: "group", IntervalSeconds: 60, IsPaused: true}
+
+	sched := &supportBundleSchedule{
+		rules: []*models.AlertRule{healthy, failing, unscheduled},
+		statuses: map[models.AlertRuleKey]models.RuleStatus{
+			healthy.GetKey(): {Health: "ok", EvaluationTimestamp: evaluated, EvaluationDuration: time.Second},
+			failing.GetKey(): {Health: "error", LastError: errors.New("query failed"), EvaluationTimestamp: evaluated},
+		},
+	}
+	ng := &AlertNG{
+		schedule:     sched,
+		stateManager: state.NewManager(state.ManagerCfg{Log: log.NewNopLogger(), StatePeriodicSaveInterval: time.Minute}, state.NewNoopPersister()),
+	}
+
+	item, err := ng.collectSchedulerState(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "alerting-scheduler.json", item.Filename)
+
+	var result []schedulerRuleState
+	require.NoError(t, json.Unmarshal(item.FileBytes, &result))
+	require.Len(t, result, 3)
+
+	require.Equal(t, "healthy", result[0].UID)
+	require.True(t, result[0].Scheduled)
+	require.Equal(t, "ok", result[0].Health)
+	require.Equal(t, "1s", result[0].EvaluationDuration)
+	require.NotNil(t, result[0].EvaluationTimestamp)
+	require.True(t, evaluated.Equal(*result[0].EvaluationTimestamp))
+	require.Empty(t, result[0].States)
+
+	require.Equal(t, "failing", result[1].UID)
+	require.Equal(t, "query failed", result[1].LastError)
+
+	require.Equal(t, "paused", result[2].UID)
+	require.Equal(t, int64(2), result[2].OrgID)
+	require.True(t, result[2].IsPaused)
+	require.False(t, result[2].Scheduled)
+	require.Nil(t, result[2].EvaluationTimestamp)
+
+	t.Run("schedulers that do not list their rules", func(t *testing.T) {
+		ng := &AlertNG{schedule: &testSchedule{}}
+		item, err := ng.collectSchedulerState(context.Background())
+		require.NoError(t, err)
+		require.JSONEq(t, `[]`, string(item.FileBytes))
+	})
+}
+
+func TestIntegrationCollectAlertmanagerState(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	sqlStore := db.InitTestDB(t)
+	ruleStore := &store.DBstore{SQLStore: sqlStore, Logger: log.NewNopLogger()}
+	require.NoError(t, ruleStore.SaveAlertmanagerConfiguration(context.Background(), &models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: `{"alertmanager_config":{"route":{"receiver":"email"}}}`,
+		ConfigurationVersion:      "v1",
+		Default:                   true,
+		OrgID:                     1,
+	}))
+
+	// no Alertmanager is running for the org
+	ng := &AlertNG{store: ruleStore, MultiOrgAlertmanager: &notifier.MultiOrgAlertmanager{}}
+
+	item, err := ng.collectAlertmanagerState(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "alerting-alertmanager.json", item.Filename)
+
+	var result []alertmanagerState
+	require.NoError(t, json.Unmarshal(item.FileBytes, &result))
+	require.Len(t, result, 1)
+	require.Equal(t, int64(1), result[0].OrgID)
+	require.True(t, result[0].Default)
+	require.False(t, result[0].Ready)
+	require.NotEmpty(t, result[0].ConfigurationHash)
+	require.JSONEq(t, `{"alertmanager_config":{"route":{"receiver":"email"}}}`, string(result[0].Configuration))
+	require.Equal(t, notifier.ErrNoAlertmanagerForOrg.Error(), result[0].Error)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/grafana/grafana/pkg/tsdb/opentsdb"
 	"github.com/grafana/grafana/pkg/tsdb/parca"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus"
+	"github.com/grafana/grafana/pkg/tsdb/sqlite"
 	"github.com/grafana/grafana/pkg/tsdb/tempo"
 	"github.com/grafana/grafana/pkg/tsdb/zipkin"
 )
@@ -52,6 +53,7 @@ const (
 	PostgreSQL      = "grafana-postgresql-datasource"
 	MySQL           = "mysql"
 	MSSQL           = "mssql"
+	SQLite          = "sqlite"
 	Grafana         = "grafana"
 	Pyroscope       = "grafana-pyroscope-datasource"
 	Parca           = "parca"
@@ -99,7 +101,8 @@ func ProvideCoreProvider(coreRegistry *Registry) plugins.BackendFactoryProvider
 func ProvideCoreRegistry(tracer trace.Tracer, am *azuremonitor.Service, cw *cloudwatch.Service, cm *cloudmonitoring.Service,
 	grap *graphite.Service, idb *influxdb.Service, lk *loki.Service, otsdb *opentsdb.Service,
 	pr *prometheus.Service, t *tempo.Service, td *testdatasource.Service, pg *postgres.Service, my *mysql.Service,
-	ms *mssql.Service, graf *grafanads.Service, pyroscope *pyroscope.Service, parca *parca.Service, zipkin *zipkin.Service, jaeger *jaeger.Service) *Registry {
+	ms *mssql.Service, graf *grafanads.Service, pyroscope *pyroscope.Service, parca *parca.Service, zipkin *zipkin.Service, jaeger *jaeger.Service,
+	sl *sqlite.Service) *Registry {
 	// Non-optimal global solution to replace plugin SDK default tracer for core plugins.
 	sdktracing.InitDefaultTracer(tracer)
 
@@ -117,6 +120,7 @@ func ProvideCoreRegistry(tracer trace.Tracer, am *azuremonitor.Service, cw *clou
 		PostgreSQL:      asBackendPlugin(pg),
 		MySQL:           asBackendPlugin(my),
 		MSSQL:           asBackendPlugin(ms),
+		SQLite:          asBackendPlugin(sl),
 		Grafana:         asBackendPlugin(graf),
 		Pyroscope:       asBackendPlugin(pyroscope),
 		Parca:           asBackendPlugin(parca),
@@ -245,6 +249,8 @@ func NewPlugin(pluginID string, httpClientProvider *httpclient.Provider, tracer
 		svc = mysql.ProvideService()
 	case MSSQL:
 		svc = mssql.ProvideService()
+	case SQLite:
+		svc = sqlite.ProvideService()
 	case Pyroscope:
 		svc = pyroscope.ProvideService(httpClientProvider)
 	case Parca:
@@ -21,6 +21,12 @@ import (
 
 var _ PluginRequestConfigProvider = (*RequestConfigProvider)(nil)
 
+// SQLiteAllowedDirectories is the request config key holding the comma separated directories
+// the core SQLite data source is allowed to open database files from.
+const SQLiteAllowedDirectories = "GF_SQL_SQLITE_ALLOWED_DIRECTORIES"
+
+const sqlitePluginID = "sqlite"
+
 type PluginRequestConfigProvider interface {
 	PluginRequestConfig(ctx context.Context, pluginID string, externalService *auth.ExternalService) map[string]string
 }
@@ -188,6 +194,10 @@ func (s *RequestConfigProvider) PluginRequestConfig(ctx context.Context, pluginI
 	m[backend.SQLMaxIdleConnsDefault] = strconv.Itoa(s.cfg.SQLDatasourceMaxIdleConnsDefault)
 	m[backend.SQLMaxConnLifetimeSecondsDefault] = strconv.Itoa(s.cfg.SQLDatasourceMaxConnLifetimeDefault)
 
+	if pluginID == sqlitePluginID && len(s.cfg.SQLiteDatasourceAllowedDirectories) > 0 {
+		m[SQLiteAllowedDirectories] = strings.Join(s.cfg.SQLiteDatasourceAllowedDirectories, ",")
+	}
+
 	if s.cfg.ResponseLimit > 0 {
 		m[backend.ResponseLimit] = strconv.FormatInt(s.cfg.ResponseLimit, 10)
 	}
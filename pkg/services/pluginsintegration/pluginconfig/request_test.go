@@ -226,6 +226,20 @@ func TestRequestConfigProvider_PluginRequestConfig_SQL(t *testing.T) {
 			"GF_SQL_MAX_CONN_LIFETIME_SECONDS_DEFAULT": "0",
 		}, p.PluginRequestConfig(context.Background(), "", nil))
 	})
+
+	t.Run("Forwards the SQLite allowed directories only to the SQLite data source", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.SqliteDatasourceAllowedDirectories = []string{"/var/lib/grafana/sqlite", "/data"}
+
+		pCfg, err := ProvidePluginInstanceConfig(cfg, setting.ProvideProvider(cfg), featuremgmt.WithFeatures())
+		require.NoError(t, err)
+
+		p := NewRequestConfigProvider(pCfg, &fakeSSOSettingsProvider{})
+		require.Subset(t, p.PluginRequestConfig(context.Background(), "sqlite", nil), map[string]string{
+			"GF_SQL_SQLITE_ALLOWED_DIRECTORIES": "/var/lib/grafana/sqlite,/data",
+		})
+		require.NotContains(t, p.PluginRequestConfig(context.Background(), "mysql", nil), "GF_SQL_SQLITE_ALLOWED_DIRECTORIES")
+	})
 }
 
 func TestRequestConfigProvider_PluginRequestConfig_concurrentQueryCount(t *testing.T) {
@@ -1,6 +1,7 @@
 package queryhistory
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
@@ -22,6 +23,16 @@ func (s *QueryHistoryService) registerAPIEndpoints() {
 		entities.Post("/star/:uid", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.starHandler, "Failed to star query history")))
 		entities.Delete("/star/:uid", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.unstarHandler, "Failed to unstar query history")))
 		entities.Patch("/:uid", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.patchCommentHandler, "Failed to update comment of query in query history")))
+		entities.Post("/:uid/library", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.libraryWriterMiddleware(s.promoteToLibraryHandler, "Failed to add query to query library"), "Failed to add query to query library")))
+	})
+
+	s.RouteRegister.Group("/api/query-library", func(entities routing.RouteRegister) {
+		entities.Post("/", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.libraryWriterMiddleware(s.createLibraryQueryHandler, "Failed to add query to query library"), "Failed to add query to query library")))
+		entities.Get("/", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.searchLibraryHandler, "Failed to search query library")))
+		entities.Get("/:uid", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.getLibraryQueryHandler, "Failed to get query from query library")))
+		entities.Put("/:uid", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.libraryWriterMiddleware(s.updateLibraryQueryHandler, "Failed to update query in query library"), "Failed to update query in query library")))
+		entities.Delete("/:uid", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.libraryWriterMiddleware(s.deleteLibraryQueryHandler, "Failed to delete query from query library"), "Failed to delete query from query library")))
+		entities.Get("/:uid/versions", middleware.ReqSignedIn, routing.Wrap(s.permissionsMiddleware(s.getLibraryQueryVersionsHandler, "Failed to get versions of query in query library")))
 	})
 }
 
@@ -37,6 +48,16 @@ func (s *QueryHistoryService) permissionsMiddleware(handler CallbackHandler, err
 	}
 }
 
+// libraryWriterMiddleware restricts changes to the query library, which is shared with the org or a team, to editors and admins.
+func (s *QueryHistoryService) libraryWriterMiddleware(handler CallbackHandler, errorMessage string) CallbackHandler {
+	return func(c *contextmodel.ReqContext) response.Response {
+		if !c.SignedInUser.HasRole(org.RoleEditor) {
+			return response.Error(http.StatusForbidden, errorMessage, nil)
+		}
+		return handler(c)
+	}
+}
+
 // swagger:route POST /query-history query_history createQuery
 //
 // Add query to query history.
@@ -200,7 +221,223 @@ func (s *QueryHistoryService) unstarHandler(c *contextmodel.ReqContext) response
 	return response.JSON(http.StatusOK, QueryHistoryResponse{Result: query})
 }
 
-// swagger:parameters starQuery patchQueryComment deleteQuery unstarQuery
+// swagger:route POST /query-history/{query_history_uid}/library query_history promoteQueryToLibrary
+//
+// Add query from query history to query library.
+//
+// Shares a query from the query history of the user with their org or one of their teams.
+//
+// Responses:
+// 200: getQueryLibraryResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) promoteToLibraryHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if len(queryUID) > 0 && !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Query in query history not found", nil)
+	}
+
+	cmd := PromoteQueryToQueryLibraryCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	item, err := s.PromoteQueryToQueryLibrary(c.Req.Context(), c.SignedInUser, queryUID, cmd)
+	if err != nil {
+		if errors.Is(err, ErrQueryNotFound) {
+			return response.Error(http.StatusNotFound, "Query in query history not found", err)
+		}
+		return libraryErrorResponse(err, "Failed to add query to query library")
+	}
+
+	return response.JSON(http.StatusOK, QueryLibraryResponse{Result: item})
+}
+
+// swagger:route POST /query-library query_library createLibraryQuery
+//
+// Add query to query library.
+//
+// Adds a new query to the query library of the org, or of a team when teamId is set.
+//
+// Responses:
+// 200: getQueryLibraryResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *QueryHistoryService) createLibraryQueryHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := CreateQueryInQueryLibraryCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	item, err := s.CreateQueryInQueryLibrary(c.Req.Context(), c.SignedInUser, cmd)
+	if err != nil {
+		return libraryErrorResponse(err, "Failed to add query to query library")
+	}
+
+	return response.JSON(http.StatusOK, QueryLibraryResponse{Result: item})
+}
+
+// swagger:route GET /query-library query_library searchLibraryQueries
+//
+// Query library search.
+//
+// Returns the queries shared with the org and with the teams of the user that match the search criteria.
+// Org admins see the queries of all teams. The default limit is 100.
+//
+// Responses:
+// 200: getQueryLibrarySearchResponse
+// 401: unauthorisedError
+// 500: internalServerError
+func (s *QueryHistoryService) searchLibraryHandler(c *contextmodel.ReqContext) response.Response {
+	query := SearchInQueryLibraryQuery{
+		SearchString:   c.Query("searchString"),
+		Tags:           c.QueryStrings("tag"),
+		DatasourceType: c.Query("datasourceType"),
+		TeamID:         c.QueryInt64("teamId"),
+		Page:           c.QueryInt("page"),
+		Limit:          c.QueryInt("limit"),
+	}
+
+	result, err := s.SearchInQueryLibrary(c.Req.Context(), c.SignedInUser, query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to search query library", err)
+	}
+
+	return response.JSON(http.StatusOK, QueryLibrarySearchResponse{Result: result})
+}
+
+// swagger:route GET /query-library/{query_library_uid} query_library getLibraryQuery
+//
+// Get query from query library.
+//
+// Responses:
+// 200: getQueryLibraryResponse
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) getLibraryQueryHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Query in query library not found", nil)
+	}
+
+	item, err := s.GetQueryFromQueryLibrary(c.Req.Context(), c.SignedInUser, queryUID)
+	if err != nil {
+		return libraryErrorResponse(err, "Failed to get query from query library")
+	}
+
+	return response.JSON(http.StatusOK, QueryLibraryResponse{Result: item})
+}
+
+// swagger:route PUT /query-library/{query_library_uid} query_library updateLibraryQuery
+//
+// Update query in query library.
+//
+// Saves a new version of a query in the query library. The version of the query the changes are based on
+// must be provided and the update fails with 409 when a newer version has been saved in the meantime.
+// Queries can be changed by their creator, org admins and, for team queries, team admins.
+//
+// Responses:
+// 200: getQueryLibraryResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *QueryHistoryService) updateLibraryQueryHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Query in query library not found", nil)
+	}
+
+	cmd := UpdateQueryInQueryLibraryCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	item, err := s.UpdateQueryInQueryLibrary(c.Req.Context(), c.SignedInUser, queryUID, cmd)
+	if err != nil {
+		return libraryErrorResponse(err, "Failed to update query in query library")
+	}
+
+	return response.JSON(http.StatusOK, QueryLibraryResponse{Result: item})
+}
+
+// swagger:route DELETE /query-library/{query_library_uid} query_library deleteLibraryQuery
+//
+// Delete query in query library.
+//
+// Deletes a query and all its versions from the query library. This operation cannot be reverted.
+//
+// Responses:
+// 200: getQueryHistoryDeleteQueryResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) deleteLibraryQueryHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Query in query library not found", nil)
+	}
+
+	id, err := s.DeleteQueryFromQueryLibrary(c.Req.Context(), c.SignedInUser, queryUID)
+	if err != nil {
+		return libraryErrorResponse(err, "Failed to delete query from query library")
+	}
+
+	return response.JSON(http.StatusOK, QueryHistoryDeleteQueryResponse{
+		Message: "Query deleted",
+		ID:      id,
+	})
+}
+
+// swagger:route GET /query-library/{query_library_uid}/versions query_library getLibraryQueryVersions
+//
+// Get versions of query in query library.
+//
+// Returns all saved versions of a query in the query library, newest first.
+//
+// Responses:
+// 200: getQueryLibraryVersionsResponse
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) getLibraryQueryVersionsHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Query in query library not found", nil)
+	}
+
+	versions, err := s.GetQueryVersionsFromQueryLibrary(c.Req.Context(), c.SignedInUser, queryUID)
+	if err != nil {
+		return libraryErrorResponse(err, "Failed to get versions of query in query library")
+	}
+
+	return response.JSON(http.StatusOK, QueryLibraryVersionsResponse{Result: versions})
+}
+
+func libraryErrorResponse(err error, message string) response.Response {
+	switch {
+	case errors.Is(err, ErrLibraryQueryNotFound):
+		return response.Error(http.StatusNotFound, "Query in query library not found", err)
+	case errors.Is(err, ErrLibraryQueryInvalid):
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	case errors.Is(err, ErrLibraryQueryForbidden):
+		return response.Error(http.StatusForbidden, err.Error(), err)
+	case errors.Is(err, ErrLibraryQueryVersionMismatch):
+		return response.Error(http.StatusConflict, err.Error(), err)
+	}
+	return response.Error(http.StatusInternalServerError, message, err)
+}
+
+// swagger:parameters starQuery patchQueryComment deleteQuery unstarQuery promoteQueryToLibrary
 type QueryHistoryByUID struct {
 	// in:path
 	// required:true
@@ -278,3 +515,77 @@ type GetQueryHistoryDeleteQueryResponse struct {
 	// in: body
 	Body QueryHistoryDeleteQueryResponse `json:"body"`
 }
+
+// swagger:parameters getLibraryQuery updateLibraryQuery deleteLibraryQuery getLibraryQueryVersions
+type QueryLibraryByUID struct {
+	// in:path
+	// required:true
+	UID string `json:"query_library_uid"`
+}
+
+// swagger:parameters searchLibraryQueries
+type SearchLibraryQueriesParams struct {
+	// Text inside the title, description or queries that is searched for
+	// in:query
+	// required: false
+	SearchString string `json:"searchString"`
+	// Only return queries with all of these tags
+	// in:query
+	// required: false
+	// type: array
+	// collectionFormat: multi
+	Tag []string `json:"tag"`
+	// Type of the data source the queries are written for
+	// in:query
+	// required: false
+	DatasourceType string `json:"datasourceType"`
+	// Only return queries of this team, -1 only returns the queries shared with the whole org
+	// in:query
+	// required: false
+	TeamID int64 `json:"teamId"`
+	// in:query
+	// required: false
+	Page int `json:"page"`
+	// in:query
+	// required: false
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters createLibraryQuery
+type CreateLibraryQueryParams struct {
+	// in:body
+	// required:true
+	Body CreateQueryInQueryLibraryCommand `json:"body"`
+}
+
+// swagger:parameters updateLibraryQuery
+type UpdateLibraryQueryParams struct {
+	// in:body
+	// required:true
+	Body UpdateQueryInQueryLibraryCommand `json:"body"`
+}
+
+// swagger:parameters promoteQueryToLibrary
+type PromoteQueryToLibraryParams struct {
+	// in:body
+	// required:true
+	Body PromoteQueryToQueryLibraryCommand `json:"body"`
+}
+
+// swagger:response getQueryLibraryResponse
+type GetQueryLibraryResponse struct {
+	// in: body
+	Body QueryLibraryResponse `json:"body"`
+}
+
+// swagger:response getQueryLibrarySearchResponse
+type GetQueryLibrarySearchResponse struct {
+	// in: body
+	Body QueryLibrarySearchResponse `json:"body"`
+}
+
+// swagger:response getQueryLibraryVersionsResponse
+type GetQueryLibraryVersionsResponse struct {
+	// in: body
+	Body QueryLibraryVersionsResponse `json:"body"`
+}
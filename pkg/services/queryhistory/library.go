@@ -0,0 +1,486 @@
+package queryhistory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	maxLibraryTitleLength = 190
+	maxLibraryTagLength   = 50
+)
+
+// createLibraryQuery adds a query to the query library of the org or of one of the teams of the user
+func (s QueryHistoryService) createLibraryQuery(ctx context.Context, user *user.SignedInUser, cmd CreateQueryInQueryLibraryCommand) (QueryLibraryItemDTO, error) {
+	if err := validateLibraryQuery(cmd.Title, cmd.Queries, cmd.Tags); err != nil {
+		return QueryLibraryItemDTO{}, err
+	}
+	if cmd.TeamID < 0 {
+		return QueryLibraryItemDTO{}, fmt.Errorf("%w: invalid team id %d", ErrLibraryQueryInvalid, cmd.TeamID)
+	}
+
+	now := s.now().Unix()
+	item := QueryLibraryItem{
+		UID:            util.GenerateShortUID(),
+		OrgID:          user.OrgID,
+		TeamID:         cmd.TeamID,
+		Title:          strings.TrimSpace(cmd.Title),
+		Description:    cmd.Description,
+		DatasourceType: cmd.DatasourceType,
+		DatasourceUID:  cmd.DatasourceUID,
+		Queries:        cmd.Queries,
+		Variables:      variablesOrEmpty(cmd.Variables),
+		Version:        1,
+		CreatedBy:      user.UserID,
+		CreatedAt:      now,
+		UpdatedBy:      user.UserID,
+		UpdatedAt:      now,
+	}
+	tags := normalizeLibraryTags(cmd.Tags)
+
+	err := s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		if item.TeamID > 0 && !user.HasRole(org.RoleAdmin) {
+			member, err := isTeamMember(session, user, item.TeamID, false)
+			if err != nil {
+				return err
+			}
+			if !member {
+				return fmt.Errorf("%w: user is not a member of team %d", ErrLibraryQueryForbidden, item.TeamID)
+			}
+		}
+
+		if _, err := session.Insert(&item); err != nil {
+			return err
+		}
+		if err := saveLibraryTags(session, item.ID, tags); err != nil {
+			return err
+		}
+		return insertLibraryVersion(session, item)
+	})
+	if err != nil {
+		return QueryLibraryItemDTO{}, err
+	}
+
+	return libraryItemToDTO(item, tags), nil
+}
+
+// promoteQueryToLibrary adds a query from the query history of the user to the query library
+func (s QueryHistoryService) promoteQueryToLibrary(ctx context.Context, user *user.SignedInUser, UID string, cmd PromoteQueryToQueryLibraryCommand) (QueryLibraryItemDTO, error) {
+	var queryHistory QueryHistory
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		exists, err := session.Where("org_id = ? AND created_by = ? AND uid = ?", user.OrgID, user.UserID, UID).Get(&queryHistory)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrQueryNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return QueryLibraryItemDTO{}, err
+	}
+
+	// The comment is the closest thing query history has to a title
+	title := cmd.Title
+	if strings.TrimSpace(title) == "" {
+		title = queryHistory.Comment
+	}
+	description := cmd.Description
+	if description == "" {
+		description = queryHistory.Comment
+	}
+
+	return s.createLibraryQuery(ctx, user, CreateQueryInQueryLibraryCommand{
+		TeamID:         cmd.TeamID,
+		Title:          title,
+		Description:    description,
+		DatasourceType: cmd.DatasourceType,
+		DatasourceUID:  queryHistory.DatasourceUID,
+		Queries:        queryHistory.Queries,
+		Variables:      cmd.Variables,
+		Tags:           cmd.Tags,
+	})
+}
+
+// getLibraryQuery returns a query from the query library if the user can see it
+func (s QueryHistoryService) getLibraryQuery(ctx context.Context, user *user.SignedInUser, UID string) (QueryLibraryItemDTO, error) {
+	var dto QueryLibraryItemDTO
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		item, err := getVisibleLibraryItem(session, user, UID)
+		if err != nil {
+			return err
+		}
+		tags, err := getLibraryTags(session, []int64{item.ID})
+		if err != nil {
+			return err
+		}
+		dto = libraryItemToDTO(item, tags[item.ID])
+		return nil
+	})
+	return dto, err
+}
+
+// searchLibraryQueries searches the query library items the user can see
+func (s QueryHistoryService) searchLibraryQueries(ctx context.Context, user *user.SignedInUser, query SearchInQueryLibraryQuery) (QueryLibrarySearchResult, error) {
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = 100
+	}
+
+	var items []QueryLibraryItem
+	var totalCount int
+
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		itemsBuilder := db.SQLBuilder{}
+		itemsBuilder.Write(`SELECT query_library_item.* FROM query_library_item`)
+		writeLibraryFiltersSQL(query, user, s.store, &itemsBuilder)
+		itemsBuilder.Write(` ORDER BY query_library_item.title ASC, query_library_item.id ASC`)
+		itemsBuilder.Write(` LIMIT ? OFFSET ?`, query.Limit, query.Limit*(query.Page-1))
+
+		if err := session.SQL(itemsBuilder.GetSQLString(), itemsBuilder.GetParams()...).Find(&items); err != nil {
+			return err
+		}
+
+		countBuilder := db.SQLBuilder{}
+		countBuilder.Write(`SELECT COUNT(*) FROM query_library_item`)
+		writeLibraryFiltersSQL(query, user, s.store, &countBuilder)
+		_, err := session.SQL(countBuilder.GetSQLString(), countBuilder.GetParams()...).Get(&totalCount)
+		return err
+	})
+	if err != nil {
+		return QueryLibrarySearchResult{}, err
+	}
+
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	var tags map[int64][]string
+	err = s.store.WithDbSession(ctx, func(session *db.Session) error {
+		var err error
+		tags, err = getLibraryTags(session, ids)
+		return err
+	})
+	if err != nil {
+		return QueryLibrarySearchResult{}, err
+	}
+
+	dtos := make([]QueryLibraryItemDTO, 0, len(items))
+	for _, item := range items {
+		dtos = append(dtos, libraryItemToDTO(item, tags[item.ID]))
+	}
+
+	return QueryLibrarySearchResult{
+		TotalCount: totalCount,
+		Items:      dtos,
+		Page:       query.Page,
+		PerPage:    query.Limit,
+	}, nil
+}
+
+// updateLibraryQuery saves a new version of a query in the query library. The update is rejected when the
+// version in the command is not the latest one, so that concurrent edits do not silently overwrite each other.
+func (s QueryHistoryService) updateLibraryQuery(ctx context.Context, user *user.SignedInUser, UID string, cmd UpdateQueryInQueryLibraryCommand) (QueryLibraryItemDTO, error) {
+	if err := validateLibraryQuery(cmd.Title, cmd.Queries, cmd.Tags); err != nil {
+		return QueryLibraryItemDTO{}, err
+	}
+
+	var item QueryLibraryItem
+	tags := normalizeLibraryTags(cmd.Tags)
+
+	err := s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		var err error
+		item, err = getEditableLibraryItem(session, user, UID)
+		if err != nil {
+			return err
+		}
+		if item.Version != cmd.Version {
+			return ErrLibraryQueryVersionMismatch
+		}
+
+		item.Title = strings.TrimSpace(cmd.Title)
+		item.Description = cmd.Description
+		item.DatasourceType = cmd.DatasourceType
+		item.DatasourceUID = cmd.DatasourceUID
+		item.Queries = cmd.Queries
+		item.Variables = variablesOrEmpty(cmd.Variables)
+		item.Version = cmd.Version + 1
+		item.UpdatedBy = user.UserID
+		item.UpdatedAt = s.now().Unix()
+
+		// Only update the row if nobody saved a new version in the meantime
+		affected, err := session.Where("id = ? AND version = ?", item.ID, cmd.Version).AllCols().Update(&item)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrLibraryQueryVersionMismatch
+		}
+
+		if _, err := session.Where("item_id = ?", item.ID).Delete(&QueryLibraryTag{}); err != nil {
+			return err
+		}
+		if err := saveLibraryTags(session, item.ID, tags); err != nil {
+			return err
+		}
+		return insertLibraryVersion(session, item)
+	})
+	if err != nil {
+		return QueryLibraryItemDTO{}, err
+	}
+
+	return libraryItemToDTO(item, tags), nil
+}
+
+// deleteLibraryQuery removes a query and its versions from the query library
+func (s QueryHistoryService) deleteLibraryQuery(ctx context.Context, user *user.SignedInUser, UID string) (int64, error) {
+	var itemID int64
+	err := s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		item, err := getEditableLibraryItem(session, user, UID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := session.Where("item_id = ?", item.ID).Delete(&QueryLibraryTag{}); err != nil {
+			return err
+		}
+		if _, err := session.Where("item_id = ?", item.ID).Delete(&QueryLibraryVersion{}); err != nil {
+			return err
+		}
+		if _, err := session.ID(item.ID).Delete(&QueryLibraryItem{}); err != nil {
+			return err
+		}
+
+		itemID = item.ID
+		return nil
+	})
+
+	return itemID, err
+}
+
+// getLibraryQueryVersions returns the saved versions of a query in the query library, newest first
+func (s QueryHistoryService) getLibraryQueryVersions(ctx context.Context, user *user.SignedInUser, UID string) ([]QueryLibraryVersion, error) {
+	versions := make([]QueryLibraryVersion, 0)
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		item, err := getVisibleLibraryItem(session, user, UID)
+		if err != nil {
+			return err
+		}
+		return session.Where("item_id = ?", item.ID).Desc("version").Find(&versions)
+	})
+	return versions, err
+}
+
+func writeLibraryFiltersSQL(query SearchInQueryLibraryQuery, user *user.SignedInUser, sqlStore db.DB, builder *db.SQLBuilder) {
+	params := []any{user.OrgID}
+	var sql bytes.Buffer
+	sql.WriteString(" WHERE query_library_item.org_id = ?")
+
+	if !user.HasRole(org.RoleAdmin) {
+		sql.WriteString(" AND (query_library_item.team_id = 0 OR query_library_item.team_id IN (SELECT team_member.team_id FROM team_member WHERE team_member.org_id = ? AND team_member.user_id = ?))")
+		params = append(params, user.OrgID, user.UserID)
+	}
+
+	if query.SearchString != "" {
+		titleSQL, titleParam := sqlStore.GetDialect().LikeOperator("query_library_item.title", true, query.SearchString, true)
+		descriptionSQL, descriptionParam := sqlStore.GetDialect().LikeOperator("query_library_item.description", true, query.SearchString, true)
+		queriesSQL, queriesParam := sqlStore.GetDialect().LikeOperator("query_library_item.queries", true, query.SearchString, true)
+		sql.WriteString(" AND (" + titleSQL + " OR " + descriptionSQL + " OR " + queriesSQL + ")")
+		params = append(params, titleParam, descriptionParam, queriesParam)
+	}
+
+	if query.DatasourceType != "" {
+		sql.WriteString(" AND query_library_item.datasource_type = ?")
+		params = append(params, query.DatasourceType)
+	}
+
+	if query.TeamID > 0 {
+		sql.WriteString(" AND query_library_item.team_id = ?")
+		params = append(params, query.TeamID)
+	} else if query.TeamID < 0 {
+		sql.WriteString(" AND query_library_item.team_id = 0")
+	}
+
+	for _, tag := range normalizeLibraryTags(query.Tags) {
+		sql.WriteString(" AND EXISTS (SELECT 1 FROM query_library_tag WHERE query_library_tag.item_id = query_library_item.id AND query_library_tag.term = ?)")
+		params = append(params, tag)
+	}
+
+	builder.Write(sql.String(), params...)
+}
+
+// getVisibleLibraryItem returns the item if it is shared with the org, or with a team the user is a member of.
+// Org admins can see all items of the org.
+func getVisibleLibraryItem(session *db.Session, user *user.SignedInUser, UID string) (QueryLibraryItem, error) {
+	var item QueryLibraryItem
+	exists, err := session.Where("org_id = ? AND uid = ?", user.OrgID, UID).Get(&item)
+	if err != nil {
+		return item, err
+	}
+	if !exists {
+		return item, ErrLibraryQueryNotFound
+	}
+
+	if item.TeamID > 0 && !user.HasRole(org.RoleAdmin) {
+		member, err := isTeamMember(session, user, item.TeamID, false)
+		if err != nil {
+			return item, err
+		}
+		if !member {
+			return item, ErrLibraryQueryNotFound
+		}
+	}
+
+	return item, nil
+}
+
+// getEditableLibraryItem returns the item if the user is allowed to change it, which are its creator,
+// org admins and for team items the admins of the team.
+func getEditableLibraryItem(session *db.Session, user *user.SignedInUser, UID string) (QueryLibraryItem, error) {
+	item, err := getVisibleLibraryItem(session, user, UID)
+	if err != nil {
+		return item, err
+	}
+
+	if item.CreatedBy == user.UserID || user.HasRole(org.RoleAdmin) {
+		return item, nil
+	}
+
+	if item.TeamID > 0 {
+		admin, err := isTeamMember(session, user, item.TeamID, true)
+		if err != nil {
+			return item, err
+		}
+		if admin {
+			return item, nil
+		}
+	}
+
+	return item, ErrLibraryQueryForbidden
+}
+
+func isTeamMember(session *db.Session, user *user.SignedInUser, teamID int64, adminOnly bool) (bool, error) {
+	sess := session.Table("team_member").Where("org_id = ? AND team_id = ? AND user_id = ?", user.OrgID, teamID, user.UserID)
+	if adminOnly {
+		sess = sess.And("permission = ?", team.PermissionTypeAdmin)
+	}
+	return sess.Exist()
+}
+
+func getLibraryTags(session *db.Session, itemIDs []int64) (map[int64][]string, error) {
+	tags := make(map[int64][]string)
+	if len(itemIDs) == 0 {
+		return tags, nil
+	}
+
+	var rows []QueryLibraryTag
+	if err := session.In("item_id", itemIDs).Asc("term").Find(&rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		tags[row.ItemID] = append(tags[row.ItemID], row.Term)
+	}
+	return tags, nil
+}
+
+func saveLibraryTags(session *db.Session, itemID int64, tags []string) error {
+	for _, tag := range tags {
+		if _, err := session.Insert(&QueryLibraryTag{ItemID: itemID, Term: tag}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertLibraryVersion(session *db.Session, item QueryLibraryItem) error {
+	_, err := session.Insert(&QueryLibraryVersion{
+		ItemID:         item.ID,
+		Version:        item.Version,
+		Title:          item.Title,
+		Description:    item.Description,
+		DatasourceType: item.DatasourceType,
+		DatasourceUID:  item.DatasourceUID,
+		Queries:        item.Queries,
+		Variables:      item.Variables,
+		CreatedBy:      item.UpdatedBy,
+		CreatedAt:      item.UpdatedAt,
+	})
+	return err
+}
+
+func validateLibraryQuery(title string, queries *simplejson.Json, tags []string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return fmt.Errorf("%w: title is required", ErrLibraryQueryInvalid)
+	}
+	if len(title) > maxLibraryTitleLength {
+		return fmt.Errorf("%w: title is longer than %d characters", ErrLibraryQueryInvalid, maxLibraryTitleLength)
+	}
+	if queries == nil || queries.Interface() == nil {
+		return fmt.Errorf("%w: queries are required", ErrLibraryQueryInvalid)
+	}
+	for _, tag := range tags {
+		if len(strings.TrimSpace(tag)) > maxLibraryTagLength {
+			return fmt.Errorf("%w: tag %q is longer than %d characters", ErrLibraryQueryInvalid, tag, maxLibraryTagLength)
+		}
+	}
+	return nil
+}
+
+// normalizeLibraryTags trims and deduplicates tags and drops the empty ones
+func normalizeLibraryTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || slices.Contains(normalized, tag) {
+			continue
+		}
+		normalized = append(normalized, tag)
+	}
+	slices.Sort(normalized)
+	return normalized
+}
+
+func variablesOrEmpty(variables *simplejson.Json) *simplejson.Json {
+	if variables == nil {
+		return simplejson.NewFromAny([]any{})
+	}
+	return variables
+}
+
+func libraryItemToDTO(item QueryLibraryItem, tags []string) QueryLibraryItemDTO {
+	if tags == nil {
+		tags = []string{}
+	}
+	return QueryLibraryItemDTO{
+		UID:            item.UID,
+		TeamID:         item.TeamID,
+		Title:          item.Title,
+		Description:    item.Description,
+		DatasourceType: item.DatasourceType,
+		DatasourceUID:  item.DatasourceUID,
+		Queries:        item.Queries,
+		Variables:      item.Variables,
+		Tags:           tags,
+		Version:        item.Version,
+		CreatedBy:      item.CreatedBy,
+		CreatedAt:      item.CreatedAt,
+		UpdatedBy:      item.UpdatedBy,
+		UpdatedAt:      item.UpdatedAt,
+	}
+}
@@ -10,6 +10,11 @@ var (
 	ErrQueryNotFound        = errors.New("query in query history not found")
 	ErrStarredQueryNotFound = errors.New("starred query not found")
 	ErrQueryAlreadyStarred  = errors.New("query was already starred")
+
+	ErrLibraryQueryNotFound        = errors.New("query in query library not found")
+	ErrLibraryQueryInvalid         = errors.New("query in query library is invalid")
+	ErrLibraryQueryForbidden       = errors.New("not allowed to change query in query library")
+	ErrLibraryQueryVersionMismatch = errors.New("query in query library has been changed by someone else")
 )
 
 // QueryHistory is the model for query history definitions
@@ -91,3 +96,140 @@ type PatchQueryCommentInQueryHistoryCommand struct {
 	// Updated comment
 	Comment string `json:"comment"`
 }
+
+// QueryLibraryItem is the model for queries shared in the query library.
+// Items with a TeamID of 0 are shared with the whole org.
+type QueryLibraryItem struct {
+	ID             int64  `xorm:"pk autoincr 'id'"`
+	UID            string `xorm:"uid"`
+	OrgID          int64  `xorm:"org_id"`
+	TeamID         int64  `xorm:"team_id"`
+	Title          string
+	Description    string
+	DatasourceType string `xorm:"datasource_type"`
+	DatasourceUID  string `xorm:"datasource_uid"`
+	Queries        *simplejson.Json
+	Variables      *simplejson.Json
+	Version        int64
+	CreatedBy      int64
+	CreatedAt      int64
+	UpdatedBy      int64
+	UpdatedAt      int64
+}
+
+// QueryLibraryTag is the model for the tags of a query library item
+type QueryLibraryTag struct {
+	ID     int64 `xorm:"pk autoincr 'id'"`
+	ItemID int64 `xorm:"item_id"`
+	Term   string
+}
+
+// QueryLibraryVersion keeps the content of every saved version of a query library item
+type QueryLibraryVersion struct {
+	ID             int64            `xorm:"pk autoincr 'id'"`
+	ItemID         int64            `xorm:"item_id"`
+	Version        int64            `json:"version"`
+	Title          string           `json:"title"`
+	Description    string           `json:"description"`
+	DatasourceType string           `xorm:"datasource_type" json:"datasourceType"`
+	DatasourceUID  string           `xorm:"datasource_uid" json:"datasourceUid"`
+	Queries        *simplejson.Json `json:"queries"`
+	Variables      *simplejson.Json `json:"variables"`
+	CreatedBy      int64            `json:"createdBy"`
+	CreatedAt      int64            `json:"createdAt"`
+}
+
+type QueryLibraryItemDTO struct {
+	UID            string           `json:"uid"`
+	TeamID         int64            `json:"teamId"`
+	Title          string           `json:"title"`
+	Description    string           `json:"description"`
+	DatasourceType string           `json:"datasourceType"`
+	DatasourceUID  string           `json:"datasourceUid"`
+	Queries        *simplejson.Json `json:"queries"`
+	Variables      *simplejson.Json `json:"variables"`
+	Tags           []string         `json:"tags"`
+	Version        int64            `json:"version"`
+	CreatedBy      int64            `json:"createdBy"`
+	CreatedAt      int64            `json:"createdAt"`
+	UpdatedBy      int64            `json:"updatedBy"`
+	UpdatedAt      int64            `json:"updatedAt"`
+}
+
+// QueryLibraryResponse is a response struct for QueryLibraryItemDTO
+type QueryLibraryResponse struct {
+	Result QueryLibraryItemDTO `json:"result"`
+}
+
+type SearchInQueryLibraryQuery struct {
+	SearchString   string   `json:"searchString"`
+	Tags           []string `json:"tags"`
+	DatasourceType string   `json:"datasourceType"`
+	// TeamID limits the search to the items of a team, -1 limits it to the items shared with the org
+	TeamID int64 `json:"teamId"`
+	Page   int   `json:"page"`
+	Limit  int   `json:"limit"`
+}
+
+type QueryLibrarySearchResult struct {
+	TotalCount int                   `json:"totalCount"`
+	Items      []QueryLibraryItemDTO `json:"items"`
+	Page       int                   `json:"page"`
+	PerPage    int                   `json:"perPage"`
+}
+
+type QueryLibrarySearchResponse struct {
+	Result QueryLibrarySearchResult `json:"result"`
+}
+
+type QueryLibraryVersionsResponse struct {
+	Result []QueryLibraryVersion `json:"result"`
+}
+
+// CreateQueryInQueryLibraryCommand is the command for adding a query to the query library
+// swagger:model
+type CreateQueryInQueryLibraryCommand struct {
+	// ID of the team the query is shared with. The query is shared with the whole org when empty.
+	TeamID int64 `json:"teamId"`
+	// required: true
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Type of the data source the queries are written for.
+	// example: prometheus
+	DatasourceType string `json:"datasourceType"`
+	// UID of the data source the queries were saved from.
+	DatasourceUID string `json:"datasourceUid"`
+	// The JSON model of queries.
+	// required: true
+	Queries *simplejson.Json `json:"queries"`
+	// The JSON model of the template variables used by the queries.
+	Variables *simplejson.Json `json:"variables"`
+	Tags      []string         `json:"tags"`
+}
+
+// UpdateQueryInQueryLibraryCommand is the command for saving a new version of a query in the query library
+// swagger:model
+type UpdateQueryInQueryLibraryCommand struct {
+	// required: true
+	Title          string           `json:"title"`
+	Description    string           `json:"description"`
+	DatasourceType string           `json:"datasourceType"`
+	DatasourceUID  string           `json:"datasourceUid"`
+	Queries        *simplejson.Json `json:"queries"`
+	Variables      *simplejson.Json `json:"variables"`
+	Tags           []string         `json:"tags"`
+	// Version of the query the changes are based on.
+	// required: true
+	Version int64 `json:"version"`
+}
+
+// PromoteQueryToQueryLibraryCommand is the command for adding a query from query history to the query library
+// swagger:model
+type PromoteQueryToQueryLibraryCommand struct {
+	TeamID         int64            `json:"teamId"`
+	Title          string           `json:"title"`
+	Description    string           `json:"description"`
+	DatasourceType string           `json:"datasourceType"`
+	Variables      *simplejson.Json `json:"variables"`
+	Tags           []string         `json:"tags"`
+}
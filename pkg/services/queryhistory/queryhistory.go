@@ -47,6 +47,13 @@ type Service interface {
 	UnstarQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string) (QueryHistoryDTO, error)
 	DeleteStaleQueriesInQueryHistory(ctx context.Context, olderThan int64) (int, error)
 	EnforceRowLimitInQueryHistory(ctx context.Context, limit int, starredQueries bool) (int, error)
+	CreateQueryInQueryLibrary(ctx context.Context, user *user.SignedInUser, cmd CreateQueryInQueryLibraryCommand) (QueryLibraryItemDTO, error)
+	PromoteQueryToQueryLibrary(ctx context.Context, user *user.SignedInUser, UID string, cmd PromoteQueryToQueryLibraryCommand) (QueryLibraryItemDTO, error)
+	GetQueryFromQueryLibrary(ctx context.Context, user *user.SignedInUser, UID string) (QueryLibraryItemDTO, error)
+	SearchInQueryLibrary(ctx context.Context, user *user.SignedInUser, query SearchInQueryLibraryQuery) (QueryLibrarySearchResult, error)
+	UpdateQueryInQueryLibrary(ctx context.Context, user *user.SignedInUser, UID string, cmd UpdateQueryInQueryLibraryCommand) (QueryLibraryItemDTO, error)
+	DeleteQueryFromQueryLibrary(ctx context.Context, user *user.SignedInUser, UID string) (int64, error)
+	GetQueryVersionsFromQueryLibrary(ctx context.Context, user *user.SignedInUser, UID string) ([]QueryLibraryVersion, error)
 }
 
 type QueryHistoryService struct {
@@ -89,3 +96,31 @@ func (s QueryHistoryService) DeleteStaleQueriesInQueryHistory(ctx context.Contex
 func (s QueryHistoryService) EnforceRowLimitInQueryHistory(ctx context.Context, limit int, starredQueries bool) (int, error) {
 	return s.enforceQueryHistoryRowLimit(ctx, limit, starredQueries)
 }
+
+func (s QueryHistoryService) CreateQueryInQueryLibrary(ctx context.Context, user *user.SignedInUser, cmd CreateQueryInQueryLibraryCommand) (QueryLibraryItemDTO, error) {
+	return s.createLibraryQuery(ctx, user, cmd)
+}
+
+func (s QueryHistoryService) PromoteQueryToQueryLibrary(ctx context.Context, user *user.SignedInUser, UID string, cmd PromoteQueryToQueryLibraryCommand) (QueryLibraryItemDTO, error) {
+	return s.promoteQueryToLibrary(ctx, user, UID, cmd)
+}
+
+func (s QueryHistoryService) GetQueryFromQueryLibrary(ctx context.Context, user *user.SignedInUser, UID string) (QueryLibraryItemDTO, error) {
+	return s.getLibraryQuery(ctx, user, UID)
+}
+
+func (s QueryHistoryService) SearchInQueryLibrary(ctx context.Context, user *user.SignedInUser, query SearchInQueryLibraryQuery) (QueryLibrarySearchResult, error) {
+	return s.searchLibraryQueries(ctx, user, query)
+}
+
+func (s QueryHistoryService) UpdateQueryInQueryLibrary(ctx context.Context, user *user.SignedInUser, UID string, cmd UpdateQueryInQueryLibraryCommand) (QueryLibraryItemDTO, error) {
+	return s.updateLibraryQuery(ctx, user, UID, cmd)
+}
+
+func (s QueryHistoryService) DeleteQueryFromQueryLibrary(ctx context.Context, user *user.SignedInUser, UID string) (int64, error) {
+	return s.deleteLibraryQuery(ctx, user, UID)
+}
+
+func (s QueryHistoryService) GetQueryVersionsFromQueryLibrary(ctx context.Context, user *user.SignedInUser, UID string) ([]QueryLibraryVersion, error) {
+	return s.getLibraryQueryVersions(ctx, user, UID)
+}
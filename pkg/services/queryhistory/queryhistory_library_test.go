@@ -0,0 +1,250 @@
+package queryhistory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/util/testutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestIntegrationQueryLibrary(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	testScenario(t, "When user adds a query to the query library, it can be found by text and tags", false, false,
+		func(t *testing.T, sc scenarioContext) {
+			created := createLibraryQuery(t, sc, CreateQueryInQueryLibraryCommand{
+				Title:          "Error rate",
+				DatasourceType: "prometheus",
+				Queries:        simplejson.NewFromAny([]any{map[string]any{"expr": "rate(errors[5m])"}}),
+				Tags:           []string{" slo ", "errors", "slo"},
+			})
+			require.Equal(t, int64(1), created.Version)
+			require.Equal(t, []string{"errors", "slo"}, created.Tags)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": created.UID})
+			resp := sc.service.getLibraryQueryHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			result := searchLibrary(t, sc, url.Values{"searchString": {"errors[5m]"}})
+			require.Equal(t, 1, result.TotalCount)
+
+			result = searchLibrary(t, sc, url.Values{"tag": {"slo", "errors"}})
+			require.Equal(t, 1, result.TotalCount)
+
+			result = searchLibrary(t, sc, url.Values{"tag": {"latency"}})
+			require.Equal(t, 0, result.TotalCount)
+
+			result = searchLibrary(t, sc, url.Values{"datasourceType": {"loki"}})
+			require.Equal(t, 0, result.TotalCount)
+		})
+
+	testScenario(t, "When user adds a query without title to the query library, it should fail", false, false,
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Body = mockRequestBody(CreateQueryInQueryLibraryCommand{
+				Queries: simplejson.NewFromAny([]any{map[string]any{"expr": "up"}}),
+			})
+			resp := sc.service.createLibraryQueryHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When user promotes a query from query history, it is added to the query library",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(PromoteQueryToQueryLibraryCommand{Title: "Promoted", Tags: []string{"shared"}})
+			resp := sc.service.promoteToLibraryHandler(sc.reqContext)
+			item := validateAndUnMarshalLibraryResponse(t, resp)
+			require.Equal(t, "Promoted", item.Title)
+			require.Equal(t, testDsUID1, item.DatasourceUID)
+			require.Equal(t, sc.initialResult.Result.Queries, item.Queries)
+		})
+
+	testScenario(t, "When user updates a query in the query library, a new version is saved", false, false,
+		func(t *testing.T, sc scenarioContext) {
+			created := createLibraryQuery(t, sc, CreateQueryInQueryLibraryCommand{
+				Title:   "Up",
+				Queries: simplejson.NewFromAny([]any{map[string]any{"expr": "up"}}),
+			})
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": created.UID})
+			sc.reqContext.Req.Body = mockRequestBody(UpdateQueryInQueryLibraryCommand{
+				Title:   "Up by job",
+				Queries: simplejson.NewFromAny([]any{map[string]any{"expr": "sum by (job) (up)"}}),
+				Version: created.Version,
+			})
+			updated := validateAndUnMarshalLibraryResponse(t, sc.service.updateLibraryQueryHandler(sc.reqContext))
+			require.Equal(t, int64(2), updated.Version)
+			require.Equal(t, "Up by job", updated.Title)
+
+			// Saving again based on the first version conflicts with the update above
+			sc.reqContext.Req.Body = mockRequestBody(UpdateQueryInQueryLibraryCommand{
+				Title:   "Stale",
+				Queries: simplejson.NewFromAny([]any{map[string]any{"expr": "up"}}),
+				Version: created.Version,
+			})
+			resp := sc.service.updateLibraryQueryHandler(sc.reqContext)
+			require.Equal(t, 409, resp.Status())
+
+			resp = sc.service.getLibraryQueryVersionsHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var versions QueryLibraryVersionsResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &versions))
+			require.Len(t, versions.Result, 2)
+			require.Equal(t, int64(2), versions.Result[0].Version)
+			require.Equal(t, "Up", versions.Result[1].Title)
+		})
+
+	testScenario(t, "When user is not the creator of a query in the query library, they cannot change it", false, false,
+		func(t *testing.T, sc scenarioContext) {
+			created := createLibraryQuery(t, sc, CreateQueryInQueryLibraryCommand{
+				Title:   "Up",
+				Queries: simplejson.NewFromAny([]any{map[string]any{"expr": "up"}}),
+			})
+
+			sc.reqContext.UserID = testUserID + 1
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": created.UID})
+			resp := sc.service.deleteLibraryQueryHandler(sc.reqContext)
+			require.Equal(t, 403, resp.Status())
+
+			// It is still visible since it is shared with the whole org
+			resp = sc.service.getLibraryQueryHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			sc.reqContext.UserID = testUserID
+			resp = sc.service.deleteLibraryQueryHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			resp = sc.service.getLibraryQueryHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	testScenario(t, "When viewer with explore permission changes the query library, it should fail", true, true,
+		func(t *testing.T, sc scenarioContext) {
+			result := searchLibrary(t, sc, url.Values{})
+			require.Equal(t, 0, result.TotalCount)
+
+			sc.reqContext.Req.Body = mockRequestBody(CreateQueryInQueryLibraryCommand{
+				Title:   "Up",
+				Queries: simplejson.NewFromAny([]any{map[string]any{"expr": "up"}}),
+			})
+			resp := sc.service.libraryWriterMiddleware(sc.service.createLibraryQueryHandler, "Failed to add query to query library")(sc.reqContext)
+			require.Equal(t, 403, resp.Status())
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": "abc"})
+			sc.reqContext.Req.Body = mockRequestBody(PromoteQueryToQueryLibraryCommand{Title: "Promoted"})
+			resp = sc.service.libraryWriterMiddleware(sc.service.promoteToLibraryHandler, "Failed to add query to query library")(sc.reqContext)
+			require.Equal(t, 403, resp.Status())
+
+			sc.reqContext.Req.Body = mockRequestBody(UpdateQueryInQueryLibraryCommand{
+				Title:   "Up by job",
+				Queries: simplejson.NewFromAny([]any{map[string]any{"expr": "sum by (job) (up)"}}),
+				Version: 1,
+			})
+			resp = sc.service.libraryWriterMiddleware(sc.service.updateLibraryQueryHandler, "Failed to update query in query library")(sc.reqContext)
+			require.Equal(t, 403, resp.Status())
+
+			resp = sc.service.libraryWriterMiddleware(sc.service.deleteLibraryQueryHandler, "Failed to delete query from query library")(sc.reqContext)
+			require.Equal(t, 403, resp.Status())
+		})
+
+	testScenario(t, "When a query is shared with a team, only team members can see it", false, false,
+		func(t *testing.T, sc scenarioContext) {
+			teamID := int64(5)
+
+			sc.reqContext.Req.Body = mockRequestBody(CreateQueryInQueryLibraryCommand{
+				TeamID:  teamID,
+				Title:   "Team query",
+				Queries: simplejson.NewFromAny([]any{map[string]any{"expr": "up"}}),
+			})
+			resp := sc.service.createLibraryQueryHandler(sc.reqContext)
+			require.Equal(t, 403, resp.Status())
+
+			addTeamMember(t, sc, teamID, sc.reqContext.UserID, team.PermissionTypeMember)
+			created := createLibraryQuery(t, sc, CreateQueryInQueryLibraryCommand{
+				TeamID:  teamID,
+				Title:   "Team query",
+				Queries: simplejson.NewFromAny([]any{map[string]any{"expr": "up"}}),
+			})
+
+			result := searchLibrary(t, sc, url.Values{"teamId": {"5"}})
+			require.Equal(t, 1, result.TotalCount)
+
+			result = searchLibrary(t, sc, url.Values{"teamId": {"-1"}})
+			require.Equal(t, 0, result.TotalCount)
+
+			sc.reqContext.UserID = testUserID + 1
+			result = searchLibrary(t, sc, url.Values{})
+			require.Equal(t, 0, result.TotalCount)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": created.UID})
+			resp = sc.service.getLibraryQueryHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+
+			// Team admins can change the queries of their team
+			addTeamMember(t, sc, teamID, sc.reqContext.UserID, team.PermissionTypeAdmin)
+			sc.reqContext.Req.Body = mockRequestBody(UpdateQueryInQueryLibraryCommand{
+				Title:   "Team query v2",
+				Queries: simplejson.NewFromAny([]any{map[string]any{"expr": "up == 0"}}),
+				Version: created.Version,
+			})
+			resp = sc.service.updateLibraryQueryHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+		})
+}
+
+func createLibraryQuery(t *testing.T, sc scenarioContext, cmd CreateQueryInQueryLibraryCommand) QueryLibraryItemDTO {
+	t.Helper()
+
+	sc.reqContext.Req.Body = mockRequestBody(cmd)
+	return validateAndUnMarshalLibraryResponse(t, sc.service.createLibraryQueryHandler(sc.reqContext))
+}
+
+func searchLibrary(t *testing.T, sc scenarioContext, params url.Values) QueryLibrarySearchResult {
+	t.Helper()
+
+	sc.reqContext.Req.Form = params
+	resp := sc.service.searchLibraryHandler(sc.reqContext)
+	require.Equal(t, 200, resp.Status())
+
+	var result QueryLibrarySearchResponse
+	require.NoError(t, json.Unmarshal(resp.Body(), &result))
+	return result.Result
+}
+
+func addTeamMember(t *testing.T, sc scenarioContext, teamID, userID int64, permission team.PermissionType) {
+	t.Helper()
+
+	err := sc.sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+		_, err := sess.Insert(&team.TeamMember{
+			UID:        fmt.Sprintf("member-%d-%d", teamID, userID),
+			OrgID:      testOrgID,
+			TeamID:     teamID,
+			UserID:     userID,
+			Permission: permission,
+			Created:    time.Now(),
+			Updated:    time.Now(),
+		})
+		return err
+	})
+	require.NoError(t, err)
+}
+
+func validateAndUnMarshalLibraryResponse(t *testing.T, resp response.Response) QueryLibraryItemDTO {
+	t.Helper()
+
+	require.Equal(t, 200, resp.Status())
+
+	var result QueryLibraryResponse
+	require.NoError(t, json.Unmarshal(resp.Body(), &result))
+	return result.Result
+}
@@ -6,7 +6,9 @@ import (
 	"strings"
 	"time"
 
+	shorturlv1beta1 "github.com/grafana/grafana/apps/shorturl/pkg/apis/shorturl/v1beta1"
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 var (
@@ -16,6 +18,7 @@ var (
 	ErrShortURLInvalidPath  = errutil.ValidationFailed("shorturl.invalid-path", errutil.WithPublicMessage("Invalid short URL path"))
 	ErrShortURLInternal     = errutil.Internal("shorturl.internal")
 	ErrShortURLConflict     = errutil.Conflict("shorturl.conflict")
+	ErrShortURLForbidden    = errutil.Forbidden("shorturl.forbidden", errutil.WithPublicMessage("Only the owner of a short URL or an org admin can change it"))
 )
 
 type ShortUrl struct {
@@ -26,6 +29,37 @@ type ShortUrl struct {
 	CreatedBy  int64  `json:"-"`
 	CreatedAt  int64  `json:"-"`
 	LastSeenAt int64  `json:"lastSeenAt"`
+	// ExpiresAt is the unix time after which the short URL stops resolving, 0 means it follows the stale link cleanup
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+	// VisitCount is the total number of redirects through the short URL
+	VisitCount int64 `json:"visitCount"`
+	UpdatedAt  int64 `json:"-"`
+}
+
+// IsExpired reports whether the short URL has an expiry that has passed.
+func (s *ShortUrl) IsExpired(now time.Time) bool {
+	return shorturlv1beta1.IsExpired(s.ExpiresAt, now)
+}
+
+// ShortUrlVisit is the number of visits of a short URL within one day
+type ShortUrlVisit struct {
+	Id         int64 `json:"-"`
+	ShortUrlId int64 `json:"-"`
+	// Bucket is the unix time of the start of the day (UTC)
+	Bucket int64 `json:"time"`
+	Visits int64 `json:"visits"`
+}
+
+// ShortURLSearchResult is a short URL as listed by the search API
+type ShortURLSearchResult struct {
+	UID        string `json:"uid"`
+	URL        string `json:"url"`
+	Path       string `json:"path"`
+	CreatedBy  int64  `json:"createdBy"`
+	CreatedAt  int64  `json:"createdAt"`
+	LastSeenAt int64  `json:"lastSeenAt"`
+	ExpiresAt  int64  `json:"expiresAt,omitempty"`
+	VisitCount int64  `json:"visitCount"`
 }
 
 // ValidateRelativePath checks that a short URL path is a safe relative path
@@ -69,6 +103,35 @@ func ValidateRelativePath(rawPath string) error {
 	return nil
 }
 
+// ValidateUID checks a user provided UID, which is used as the slug of the short URL.
+func ValidateUID(uid string) error {
+	if !util.IsValidShortUID(uid) {
+		return ErrShortURLBadRequest.Errorf("invalid UID: %s", uid)
+	}
+	if util.IsShortUIDTooLong(uid) {
+		return ErrShortURLBadRequest.Errorf("UID is longer than %d characters: %s", util.MaxUIDLength, uid)
+	}
+	return nil
+}
+
+// ValidateExpiresAt checks that an expiry is either unset or in the future.
+func ValidateExpiresAt(expiresAt int64, now time.Time) error {
+	if expiresAt < 0 || (expiresAt > 0 && expiresAt <= now.Unix()) {
+		return ErrShortURLBadRequest.Errorf("expiresAt must be in the future")
+	}
+	return nil
+}
+
+type SearchShortURLsQuery struct {
+	OrgID int64
+	// Query matches the UID or the path of the short URL
+	Query string
+	// CreatedBy restricts the results to the short URLs of one user, 0 returns everyone's
+	CreatedBy int64
+	Limit     int
+	Page      int
+}
+
 type DeleteShortUrlCommand struct {
 	Uid       string
 	OlderThan time.Time
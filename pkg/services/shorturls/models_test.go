@@ -1,7 +1,9 @@
 package shorturls
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -107,3 +109,22 @@ func TestValidateRelativePath(t *testing.T) {
 		require.True(t, ErrShortURLInvalidPath.Is(err))
 	})
 }
+
+func TestValidateUID(t *testing.T) {
+	require.NoError(t, ValidateUID("team-dashboard_1"))
+
+	err := ValidateUID("has spaces")
+	require.True(t, ErrShortURLBadRequest.Is(err))
+
+	err = ValidateUID(strings.Repeat("a", 41))
+	require.True(t, ErrShortURLBadRequest.Is(err))
+}
+
+func TestValidateExpiresAt(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	require.NoError(t, ValidateExpiresAt(0, now))
+	require.NoError(t, ValidateExpiresAt(1001, now))
+	require.True(t, ErrShortURLBadRequest.Is(ValidateExpiresAt(1000, now)))
+	require.True(t, ErrShortURLBadRequest.Is(ValidateExpiresAt(-1, now)))
+}
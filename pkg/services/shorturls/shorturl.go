@@ -2,6 +2,7 @@ package shorturls
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
@@ -14,4 +15,9 @@ type Service interface {
 	DeleteStaleShortURLs(ctx context.Context, cmd *DeleteShortUrlCommand) error
 	ConvertShortURLToDTO(shortURL *ShortUrl, appURL string) *dtos.ShortURL
 	List(ctx context.Context, orgID int64) ([]*ShortUrl, error)
+	SearchShortURLs(ctx context.Context, user identity.Requester, query *SearchShortURLsQuery) ([]*ShortUrl, error)
+	UpdateShortURL(ctx context.Context, user identity.Requester, uid string, cmd *dtos.UpdateShortURLCmd) (*ShortUrl, error)
+	// RecordVisit updates LastSeenAt and counts a visit of the short URL
+	RecordVisit(ctx context.Context, shortURL *ShortUrl) error
+	GetVisits(ctx context.Context, shortURL *ShortUrl, from, to time.Time) ([]*ShortUrlVisit, error)
 }
@@ -28,7 +28,16 @@ func ProvideService(db db.DB) *ShortURLService {
 }
 
 func (s ShortURLService) GetShortURLByUID(ctx context.Context, user identity.Requester, uid string) (*shorturls.ShortUrl, error) {
-	return s.SQLStore.Get(ctx, user, uid)
+	shortURL, err := s.SQLStore.Get(ctx, user, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if shortURL.IsExpired(getTime()) {
+		return nil, shorturls.ErrShortURLNotFound.Errorf("short URL expired")
+	}
+
+	return shortURL, nil
 }
 
 func (s ShortURLService) UpdateLastSeenAt(ctx context.Context, shortURL *shorturls.ShortUrl) error {
@@ -46,13 +55,17 @@ func (s ShortURLService) CreateShortURL(ctx context.Context, user identity.Reque
 		return nil, err
 	}
 
+	if err := shorturls.ValidateExpiresAt(cmd.ExpiresAt, getTime()); err != nil {
+		return nil, err
+	}
+
 	uid := cmd.UID
 	if uid == "" {
 		uid = util.GenerateShortUID()
 	} else {
 		// Ensure the UID is valid
-		if !util.IsValidShortUID(uid) {
-			return nil, shorturls.ErrShortURLBadRequest.Errorf("invalid UID: %s", uid)
+		if err := shorturls.ValidateUID(uid); err != nil {
+			return nil, err
 		}
 
 		// Check if the UID already exists
@@ -74,6 +87,7 @@ func (s ShortURLService) CreateShortURL(ctx context.Context, user identity.Reque
 		Uid:       uid,
 		Path:      relPath,
 		CreatedAt: now,
+		ExpiresAt: cmd.ExpiresAt,
 	}
 	shortURL.CreatedBy, _ = user.GetInternalID()
 
@@ -84,6 +98,63 @@ func (s ShortURLService) CreateShortURL(ctx context.Context, user identity.Reque
 	return &shortURL, nil
 }
 
+// SearchShortURLs lists the short URLs of the org of the user. Users that are not org admins only see their own.
+func (s ShortURLService) SearchShortURLs(ctx context.Context, user identity.Requester, query *shorturls.SearchShortURLsQuery) ([]*shorturls.ShortUrl, error) {
+	query.OrgID = user.GetOrgID()
+	if user.GetOrgRole() != identity.RoleAdmin {
+		userID, err := user.GetInternalID()
+		if err != nil {
+			return nil, shorturls.ErrShortURLInternal.Errorf("failed to get user id: %w", err)
+		}
+		query.CreatedBy = userID
+	}
+
+	return s.SQLStore.Search(ctx, query)
+}
+
+// UpdateShortURL repoints a short URL to a new path while keeping its UID.
+func (s ShortURLService) UpdateShortURL(ctx context.Context, user identity.Requester, uid string, cmd *dtos.UpdateShortURLCmd) (*shorturls.ShortUrl, error) {
+	relPath := strings.TrimSpace(cmd.Path)
+	if relPath == "" {
+		return nil, shorturls.ErrShortURLBadRequest.Errorf("path is required")
+	}
+	if err := shorturls.ValidateRelativePath(relPath); err != nil {
+		return nil, err
+	}
+	if err := shorturls.ValidateExpiresAt(cmd.ExpiresAt, getTime()); err != nil {
+		return nil, err
+	}
+
+	shortURL, err := s.SQLStore.Get(ctx, user, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, _ := user.GetInternalID()
+	if shortURL.CreatedBy != userID && user.GetOrgRole() != identity.RoleAdmin {
+		return nil, shorturls.ErrShortURLForbidden.Errorf("user %d does not own short URL %s", userID, uid)
+	}
+
+	shortURL.Path = relPath
+	shortURL.ExpiresAt = cmd.ExpiresAt
+	if err := s.SQLStore.UpdateTarget(ctx, shortURL); err != nil {
+		return nil, shorturls.ErrShortURLInternal.Errorf("failed to update shorturl: %w", err)
+	}
+
+	return shortURL, nil
+}
+
+func (s ShortURLService) RecordVisit(ctx context.Context, shortURL *shorturls.ShortUrl) error {
+	return s.SQLStore.RecordVisit(ctx, shortURL)
+}
+
+func (s ShortURLService) GetVisits(ctx context.Context, shortURL *shorturls.ShortUrl, from, to time.Time) ([]*shorturls.ShortUrlVisit, error) {
+	if to.Before(from) {
+		return nil, shorturls.ErrShortURLBadRequest.Errorf("from must be before to")
+	}
+	return s.SQLStore.GetVisits(ctx, shortURL.Id, from, to)
+}
+
 func (s ShortURLService) DeleteStaleShortURLs(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error {
 	return s.SQLStore.Delete(ctx, cmd)
 }
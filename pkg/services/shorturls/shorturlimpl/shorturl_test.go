@@ -9,6 +9,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/tests/testsuite"
@@ -213,4 +214,153 @@ func TestIntegrationShortURLService(t *testing.T) {
 		require.ErrorIs(t, err, shorturls.ErrShortURLConflict)
 		require.Nil(t, newShortURL2)
 	})
+
+	t.Run("Create URL with an invalid slug should fail", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+
+		cmd := &dtos.CreateShortURLCmd{
+			Path: "mock/path?test=true",
+			UID:  "not a slug!",
+		}
+		newShortURL, err := service.CreateShortURL(context.Background(), user, cmd)
+		require.ErrorIs(t, err, shorturls.ErrShortURLBadRequest)
+		require.Nil(t, newShortURL)
+	})
+
+	t.Run("Expired short URLs cannot be looked up", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+
+		ctx := context.Background()
+
+		cmd := &dtos.CreateShortURLCmd{
+			Path:      "mock/path?test=true",
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		}
+		newShortURL, err := service.CreateShortURL(ctx, user, cmd)
+		require.NoError(t, err)
+		require.Equal(t, cmd.ExpiresAt, newShortURL.ExpiresAt)
+
+		_, err = service.GetShortURLByUID(ctx, user, newShortURL.Uid)
+		require.NoError(t, err)
+
+		origGetTime := getTime
+		t.Cleanup(func() {
+			getTime = origGetTime
+		})
+		getTime = func() time.Time {
+			return time.Now().Add(2 * time.Hour)
+		}
+
+		_, err = service.GetShortURLByUID(ctx, user, newShortURL.Uid)
+		require.ErrorIs(t, err, shorturls.ErrShortURLNotFound)
+	})
+
+	t.Run("Create URL with an expiry in the past should fail", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+
+		cmd := &dtos.CreateShortURLCmd{
+			Path:      "mock/path?test=true",
+			ExpiresAt: time.Now().Add(-time.Hour).Unix(),
+		}
+		newShortURL, err := service.CreateShortURL(context.Background(), user, cmd)
+		require.ErrorIs(t, err, shorturls.ErrShortURLBadRequest)
+		require.Nil(t, newShortURL)
+	})
+
+	t.Run("Visits are counted per day", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+
+		ctx := context.Background()
+
+		newShortURL, err := service.CreateShortURL(ctx, user, &dtos.CreateShortURLCmd{Path: "mock/path?test=true"})
+		require.NoError(t, err)
+
+		origGetTime := getTime
+		t.Cleanup(func() {
+			getTime = origGetTime
+		})
+
+		day1 := time.Date(2020, time.November, 27, 6, 5, 1, 0, time.UTC)
+		day2 := day1.AddDate(0, 0, 1)
+		for _, visit := range []time.Time{day1, day1.Add(time.Hour), day2} {
+			getTime = func() time.Time {
+				return visit
+			}
+			require.NoError(t, service.RecordVisit(ctx, newShortURL))
+		}
+
+		updatedShortURL, err := service.SQLStore.Get(ctx, user, newShortURL.Uid)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), updatedShortURL.VisitCount)
+		require.Equal(t, day2.Unix(), updatedShortURL.LastSeenAt)
+
+		visits, err := service.GetVisits(ctx, updatedShortURL, day1.AddDate(0, 0, -1), day2)
+		require.NoError(t, err)
+		require.Len(t, visits, 2)
+		require.Equal(t, int64(2), visits[0].Visits)
+		require.Equal(t, int64(1), visits[1].Visits)
+
+		_, err = service.GetVisits(ctx, updatedShortURL, day2, day1)
+		require.ErrorIs(t, err, shorturls.ErrShortURLBadRequest)
+	})
+
+	t.Run("Search only returns the short URLs of the user unless they are an org admin", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+
+		ctx := context.Background()
+
+		owner := &user.SignedInUser{UserID: 10, OrgID: 7, OrgRole: org.RoleEditor}
+		other := &user.SignedInUser{UserID: 11, OrgID: 7, OrgRole: org.RoleEditor}
+		admin := &user.SignedInUser{UserID: 12, OrgID: 7, OrgRole: org.RoleAdmin}
+
+		_, err := service.CreateShortURL(ctx, owner, &dtos.CreateShortURLCmd{Path: "d/owner/dashboard"})
+		require.NoError(t, err)
+		_, err = service.CreateShortURL(ctx, other, &dtos.CreateShortURLCmd{Path: "d/other/dashboard"})
+		require.NoError(t, err)
+
+		results, err := service.SearchShortURLs(ctx, owner, &shorturls.SearchShortURLsQuery{})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, "d/owner/dashboard", results[0].Path)
+
+		results, err = service.SearchShortURLs(ctx, admin, &shorturls.SearchShortURLsQuery{})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		results, err = service.SearchShortURLs(ctx, admin, &shorturls.SearchShortURLsQuery{Query: "other"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, "d/other/dashboard", results[0].Path)
+	})
+
+	t.Run("Short URLs can only be repointed by their owner or an org admin", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+
+		ctx := context.Background()
+
+		owner := &user.SignedInUser{UserID: 20, OrgID: 8, OrgRole: org.RoleEditor}
+		other := &user.SignedInUser{UserID: 21, OrgID: 8, OrgRole: org.RoleEditor}
+		admin := &user.SignedInUser{UserID: 22, OrgID: 8, OrgRole: org.RoleAdmin}
+
+		newShortURL, err := service.CreateShortURL(ctx, owner, &dtos.CreateShortURLCmd{Path: "d/old/dashboard", UID: "repoint-me"})
+		require.NoError(t, err)
+
+		_, err = service.UpdateShortURL(ctx, other, newShortURL.Uid, &dtos.UpdateShortURLCmd{Path: "d/other/dashboard"})
+		require.ErrorIs(t, err, shorturls.ErrShortURLForbidden)
+
+		_, err = service.UpdateShortURL(ctx, owner, newShortURL.Uid, &dtos.UpdateShortURLCmd{Path: "//evil.com"})
+		require.ErrorIs(t, err, shorturls.ErrShortURLInvalidPath)
+
+		updated, err := service.UpdateShortURL(ctx, owner, newShortURL.Uid, &dtos.UpdateShortURLCmd{Path: "d/new/dashboard"})
+		require.NoError(t, err)
+		require.Equal(t, "d/new/dashboard", updated.Path)
+
+		_, err = service.UpdateShortURL(ctx, admin, newShortURL.Uid, &dtos.UpdateShortURLCmd{Path: "d/admin/dashboard"})
+		require.NoError(t, err)
+
+		existing, err := service.GetShortURLByUID(ctx, owner, newShortURL.Uid)
+		require.NoError(t, err)
+		require.Equal(t, "d/admin/dashboard", existing.Path)
+		require.Equal(t, newShortURL.Id, existing.Id)
+	})
 }
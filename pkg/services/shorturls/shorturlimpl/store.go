@@ -2,10 +2,14 @@ package shorturlimpl
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/shorturls"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 )
 
 type store interface {
@@ -14,6 +18,10 @@ type store interface {
 	Insert(ctx context.Context, shortURL *shorturls.ShortUrl) error
 	Delete(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error
 	List(ctx context.Context, orgID int64) ([]*shorturls.ShortUrl, error)
+	Search(ctx context.Context, query *shorturls.SearchShortURLsQuery) ([]*shorturls.ShortUrl, error)
+	UpdateTarget(ctx context.Context, shortURL *shorturls.ShortUrl) error
+	RecordVisit(ctx context.Context, shortURL *shorturls.ShortUrl) error
+	GetVisits(ctx context.Context, shortURLID int64, from, to time.Time) ([]*shorturls.ShortUrlVisit, error)
 }
 
 type sqlStore struct {
@@ -73,16 +81,19 @@ func (s sqlStore) Delete(ctx context.Context, cmd *shorturls.DeleteShortUrlComma
 		})
 	}
 
-	// Otherwise, delete all stale short URLs older than the specified time
+	// Otherwise, delete all stale short URLs older than the specified time and the ones past their expiry.
+	// Short URLs with an explicit expiry are not considered stale.
 	return s.db.WithTransactionalDbSession(ctx, func(session *db.Session) error {
-		var rawSql = "DELETE FROM short_url WHERE created_at <= ? AND (last_seen_at IS NULL OR last_seen_at = 0)"
+		var rawSql = "DELETE FROM short_url WHERE (created_at <= ? AND (last_seen_at IS NULL OR last_seen_at = 0) AND (expires_at IS NULL OR expires_at = 0)) OR (expires_at > 0 AND expires_at <= ?)"
 
-		if result, err := session.Exec(rawSql, cmd.OlderThan.Unix()); err != nil {
+		if result, err := session.Exec(rawSql, cmd.OlderThan.Unix(), getTime().Unix()); err != nil {
 			return err
 		} else if cmd.NumDeleted, err = result.RowsAffected(); err != nil {
 			return err
 		}
-		return nil
+
+		_, err := session.Exec("DELETE FROM short_url_visit WHERE short_url_id NOT IN (SELECT id FROM short_url)")
+		return err
 	})
 }
 
@@ -101,3 +112,99 @@ func (s sqlStore) List(ctx context.Context, orgID int64) ([]*shorturls.ShortUrl,
 
 	return shortURLs, nil
 }
+
+func (s sqlStore) Search(ctx context.Context, query *shorturls.SearchShortURLsQuery) ([]*shorturls.ShortUrl, error) {
+	shortURLs := make([]*shorturls.ShortUrl, 0)
+	err := s.db.WithDbSession(ctx, func(dbSession *db.Session) error {
+		sess := dbSession.Where("org_id = ?", query.OrgID)
+		if query.CreatedBy > 0 {
+			sess.And("created_by = ?", query.CreatedBy)
+		}
+		if query.Query != "" {
+			like := "%" + strings.ToLower(query.Query) + "%"
+			sess.And("(LOWER(uid) LIKE ? OR LOWER(path) LIKE ?)", like, like)
+		}
+
+		limit := query.Limit
+		if limit <= 0 {
+			limit = 100
+		}
+		page := query.Page
+		if page < 1 {
+			page = 1
+		}
+		sess.Desc("created_at").Limit(limit, (page-1)*limit)
+
+		return sess.Find(&shortURLs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shortURLs, nil
+}
+
+func (s sqlStore) UpdateTarget(ctx context.Context, shortURL *shorturls.ShortUrl) error {
+	shortURL.UpdatedAt = getTime().Unix()
+	return s.db.WithTransactionalDbSession(ctx, func(dbSession *db.Session) error {
+		_, err := dbSession.ID(shortURL.Id).Cols("path", "expires_at", "updated_at").Update(shortURL)
+		return err
+	})
+}
+
+// RecordVisit updates the last seen time and the visit counters of a short URL.
+// Visits are counted per UTC day.
+func (s sqlStore) RecordVisit(ctx context.Context, shortURL *shorturls.ShortUrl) error {
+	now := getTime()
+	bucket := now.UTC().Truncate(24 * time.Hour).Unix()
+
+	// The first visits of a day race on the unique (short_url_id, bucket) index, so the
+	// daily counter is upserted in a single statement
+	var upsert string
+	switch s.db.GetDBType() {
+	case migrator.Postgres:
+		upsert = `INSERT INTO short_url_visit (short_url_id, bucket, visits)
+					VALUES ($1, $2, 1)
+					ON CONFLICT (short_url_id, bucket) DO UPDATE SET
+					visits = short_url_visit.visits + 1`
+	case migrator.MySQL:
+		upsert = `INSERT INTO short_url_visit (short_url_id, bucket, visits)
+					VALUES (?, ?, 1)
+					ON DUPLICATE KEY UPDATE
+					visits = visits + 1`
+	case migrator.SQLite:
+		upsert = `INSERT INTO short_url_visit (short_url_id, bucket, visits)
+					VALUES (?, ?, 1)
+					ON CONFLICT (short_url_id, bucket) DO UPDATE SET
+					visits = visits + 1`
+	default:
+		return fmt.Errorf("unsupported database driver: %s", s.db.GetDBType())
+	}
+
+	return s.db.WithTransactionalDbSession(ctx, func(dbSession *db.Session) error {
+		if _, err := dbSession.Exec("UPDATE short_url SET last_seen_at = ?, visit_count = visit_count + 1 WHERE id = ?", now.Unix(), shortURL.Id); err != nil {
+			return err
+		}
+		if _, err := dbSession.Exec(upsert, shortURL.Id, bucket); err != nil {
+			return err
+		}
+
+		shortURL.LastSeenAt = now.Unix()
+		shortURL.VisitCount++
+		return nil
+	})
+}
+
+func (s sqlStore) GetVisits(ctx context.Context, shortURLID int64, from, to time.Time) ([]*shorturls.ShortUrlVisit, error) {
+	visits := make([]*shorturls.ShortUrlVisit, 0)
+	err := s.db.WithDbSession(ctx, func(dbSession *db.Session) error {
+		return dbSession.Where("short_url_id = ? AND bucket >= ? AND bucket <= ?", shortURLID, from.UTC().Truncate(24*time.Hour).Unix(), to.Unix()).
+			Asc("bucket").
+			Find(&visits)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return visits, nil
+}
@@ -71,4 +71,30 @@ func addDashboardSnapshotMigrations(mg *Migrator) {
 
 	mg.AddMigration("Change dashboard_encrypted column to MEDIUMBLOB", NewRawSQLMigration("").
 		Mysql("ALTER TABLE dashboard_snapshot MODIFY dashboard_encrypted MEDIUMBLOB;"))
+
+	mg.AddMigration("Add version column to dashboard_snapshot table", NewAddColumnMigration(snapshotV5, &Column{
+		Name: "version", Type: DB_BigInt, Nullable: false, Default: "1",
+	}))
+
+	mg.AddMigration("Add storage_key column to dashboard_snapshot table", NewAddColumnMigration(snapshotV5, &Column{
+		Name: "storage_key", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+
+	snapshotPolicyV1 := Table{
+		Name: "dashboard_snapshot_policy",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "max_age", Type: DB_BigInt, Nullable: false},
+			{Name: "max_size", Type: DB_BigInt, Nullable: false},
+			{Name: "external_role", Type: DB_NVarchar, Length: 20, Nullable: true},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create dashboard_snapshot_policy table v1", NewAddTableMigration(snapshotPolicyV1))
+	addTableIndicesMigrations(mg, "v1", snapshotPolicyV1)
 }
@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addQueryLibraryMigrations(mg *Migrator) {
+	queryLibraryItemV1 := Table{
+		Name: "query_library_item",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "team_id", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "title", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "description", Type: DB_Text, Nullable: false},
+			{Name: "datasource_type", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "datasource_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "queries", Type: DB_Text, Nullable: false},
+			{Name: "variables", Type: DB_Text, Nullable: false},
+			{Name: "version", Type: DB_BigInt, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created_at", Type: DB_BigInt, Nullable: false},
+			{Name: "updated_by", Type: DB_BigInt, Nullable: false},
+			{Name: "updated_at", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "team_id"}},
+		},
+	}
+
+	mg.AddMigration("create query_library_item table v1", NewAddTableMigration(queryLibraryItemV1))
+	addTableIndicesMigrations(mg, "v1", queryLibraryItemV1)
+
+	queryLibraryTagV1 := Table{
+		Name: "query_library_tag",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "item_id", Type: DB_BigInt, Nullable: false},
+			{Name: "term", Type: DB_NVarchar, Length: 50, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"item_id", "term"}, Type: UniqueIndex},
+			{Cols: []string{"term"}},
+		},
+	}
+
+	mg.AddMigration("create query_library_tag table v1", NewAddTableMigration(queryLibraryTagV1))
+	addTableIndicesMigrations(mg, "v1", queryLibraryTagV1)
+
+	queryLibraryVersionV1 := Table{
+		Name: "query_library_version",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "item_id", Type: DB_BigInt, Nullable: false},
+			{Name: "version", Type: DB_BigInt, Nullable: false},
+			{Name: "title", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "description", Type: DB_Text, Nullable: false},
+			{Name: "datasource_type", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "datasource_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "queries", Type: DB_Text, Nullable: false},
+			{Name: "variables", Type: DB_Text, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created_at", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"item_id", "version"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create query_library_version table v1", NewAddTableMigration(queryLibraryVersionV1))
+	addTableIndicesMigrations(mg, "v1", queryLibraryVersionV1)
+}
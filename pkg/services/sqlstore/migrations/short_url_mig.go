@@ -28,4 +28,36 @@ func addShortURLMigrations(mg *Migrator) {
 	mg.AddMigration("alter table short_url alter column created_by type to bigint", NewRawSQLMigration("").
 		Mysql("ALTER TABLE short_url MODIFY created_by BIGINT;").
 		Postgres("ALTER TABLE short_url ALTER COLUMN created_by TYPE BIGINT;"))
+
+	mg.AddMigration("add expires_at column to short_url table", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "expires_at", Type: DB_BigInt, Nullable: true,
+	}))
+
+	mg.AddMigration("add visit_count column to short_url table", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "visit_count", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add updated_at column to short_url table", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "updated_at", Type: DB_BigInt, Nullable: true,
+	}))
+
+	mg.AddMigration("add index short_url.org_id-created_by", NewAddIndexMigration(shortURLV1, &Index{
+		Cols: []string{"org_id", "created_by"},
+	}))
+
+	shortURLVisitV1 := Table{
+		Name: "short_url_visit",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "short_url_id", Type: DB_BigInt, Nullable: false},
+			{Name: "bucket", Type: DB_BigInt, Nullable: false},
+			{Name: "visits", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"short_url_id", "bucket"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create short_url_visit table v1", NewAddTableMigration(shortURLVisitV1))
+	mg.AddMigration("add unique index short_url_visit.short_url_id-bucket", NewAddIndexMigration(shortURLVisitV1, shortURLVisitV1.Indices[0]))
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -677,6 +678,7 @@ func readpanelInfo(iter *jsoniter.Iterator, lookup DatasourceLookup, jsonPath st
 	}
 
 	panel.Datasource = targets.GetDatasourceInfo()
+	panel.Queries = targets.queries
 
 	return panel, true
 }
@@ -777,6 +779,11 @@ func readV2PanelSpec(iter *jsoniter.Iterator, lookup DatasourceLookup, jsonPath
 										panel.Datasource = append(panel.Datasource, DataSourceRef{UID: uid, Type: typ})
 									}
 								}
+								if spec, _ := m["spec"].(map[string]any); spec != nil {
+									if query, _ := spec["query"].(map[string]any); query != nil {
+										panel.Queries = append(panel.Queries, readV2QueryText(query)...)
+									}
+								}
 							}
 						}
 					}
@@ -792,6 +799,19 @@ func readV2PanelSpec(iter *jsoniter.Iterator, lookup DatasourceLookup, jsonPath
 	return panel
 }
 
+// readV2QueryText returns the query text of a v2 data query, eg {"kind": "prometheus", "spec": {"expr": "up"}}
+func readV2QueryText(query map[string]any) []string {
+	spec, _ := query["spec"].(map[string]any)
+	queries := []string{}
+	for k, v := range spec {
+		if text, ok := v.(string); ok && text != "" && queryTextFields[k] {
+			queries = append(queries, text)
+		}
+	}
+	sort.Strings(queries) // map iteration order is random
+	return queries
+}
+
 func readV2LibraryPanelSpec(iter *jsoniter.Iterator, jsonPath string, lc map[string]any) PanelSummaryInfo {
 	panel := PanelSummaryInfo{}
 	for field := iter.ReadObject(); field != ""; field = iter.ReadObject() {
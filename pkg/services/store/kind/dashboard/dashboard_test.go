@@ -72,6 +72,7 @@ func TestReadDashboard(t *testing.T) {
 		"special-datasource-types",
 		"panels-without-datasources",
 		"panel-with-library-panel-field",
+		"panel-queries",
 		"k8s-wrapper",
 		"k8s-wrapper-editable-string",
 		"k8s-wrapper-tags-string",
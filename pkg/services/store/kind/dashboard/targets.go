@@ -4,9 +4,20 @@ import (
 	jsoniter "github.com/json-iterator/go"
 )
 
+// queryTextFields are the target properties holding the query text in the common data sources
+var queryTextFields = map[string]bool{
+	"expr":       true, // prometheus, loki
+	"expression": true, // server side expressions, cloudwatch
+	"query":      true, // influxdb (flux), elasticsearch, tempo
+	"queryText":  true,
+	"rawSql":     true, // sql data sources
+	"target":     true, // graphite
+}
+
 type targetInfo struct {
-	lookup DatasourceLookup
-	uids   map[string]*DataSourceRef
+	lookup  DatasourceLookup
+	uids    map[string]*DataSourceRef
+	queries []string
 }
 
 func newTargetInfo(lookup DatasourceLookup) targetInfo {
@@ -83,11 +94,21 @@ func (s *targetInfo) addTarget(iter *jsoniter.Iterator, jsonPath string, lc map[
 			iter.Skip()
 
 		default:
+			if queryTextFields[f] && iter.WhatIsNext() == jsoniter.StringValue {
+				s.addQuery(iter.ReadString())
+				continue
+			}
 			iter.Skip()
 		}
 	}
 }
 
+func (s *targetInfo) addQuery(query string) {
+	if query != "" {
+		s.queries = append(s.queries, query)
+	}
+}
+
 func (s *targetInfo) addPanel(panel PanelSummaryInfo) {
 	for idx, v := range panel.Datasource {
 		if v.UID != "" {
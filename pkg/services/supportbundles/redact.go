@@ -0,0 +1,201 @@
+package supportbundles
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces the values removed by the Redactor.
+const RedactedValue = "*********"
+
+// DefaultRedactKeys are the keys whose values are always redacted. A key matches when it contains
+// one of these, ignoring case, so "password" also covers "basicAuthPassword".
+var DefaultRedactKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"apikey",
+	"api_key",
+	"private_key",
+	"privatekey",
+	"authorization",
+	"cookie",
+	"credential",
+}
+
+// DefaultRedactPatterns are the patterns whose matches are always redacted, wherever they appear.
+// When a pattern has a group named "secret", only that group is redacted.
+var DefaultRedactPatterns = []string{
+	// Grafana service account tokens
+	`glsa_[A-Za-z0-9_]{32,}`,
+	// Grafana Cloud access policy tokens
+	`glc_[A-Za-z0-9+/=]{32,}`,
+	// JSON Web Tokens
+	`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`,
+	// Bearer tokens in headers
+	`(?i)bearer\s+[A-Za-z0-9._~+/-]+=*`,
+	// Credentials in URLs
+	`://[^/\s:@"]+:(?P<secret>[^/\s@"]+)@`,
+}
+
+// Redactor removes secrets from support items before they are archived.
+//
+// JSON items are redacted by key: the values of all keys matching one of the configured keys are replaced.
+// Other items are redacted line by line, replacing the value of "key: value" and "key = value" pairs.
+// The configured patterns are applied to every item afterwards.
+type Redactor struct {
+	keys     []string
+	keyRegex *regexp.Regexp
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor creates a Redactor for the default keys and patterns and the given additional ones.
+func NewRedactor(keys []string, patterns []string) (*Redactor, error) {
+	r := &Redactor{}
+
+	allKeys := append(append([]string{}, DefaultRedactKeys...), keys...)
+	quoted := make([]string, 0, len(allKeys))
+	for _, key := range allKeys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		r.keys = append(r.keys, key)
+		quoted = append(quoted, regexp.QuoteMeta(key))
+	}
+	r.keyRegex = regexp.MustCompile(`(?i)([\w.-]*(?:` + strings.Join(quoted, "|") + `)[\w.-]*)(["']?\s*[:=]\s*)("[^"]*"|'[^']*'|[^\s,]+)`)
+
+	for _, pattern := range append(append([]string{}, DefaultRedactPatterns...), patterns...) {
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid support bundle redaction pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	return r, nil
+}
+
+// Redact returns a copy of the item with secrets removed.
+func (r *Redactor) Redact(item *SupportItem) *SupportItem {
+	if item == nil {
+		return nil
+	}
+	return &SupportItem{
+		Filename:  item.Filename,
+		FileBytes: r.RedactBytes(item.FileBytes),
+	}
+}
+
+// RedactBytes removes secrets from the contents of a support item.
+func (r *Redactor) RedactBytes(data []byte) []byte {
+	if redacted, ok := r.redactJSON(data); ok {
+		data = redacted
+	} else {
+		data = r.redactKeyValues(data)
+	}
+
+	for _, re := range r.patterns {
+		data = redactPattern(re, data)
+	}
+	return data
+}
+
+// sourceLineRegex matches the file and line references of stack traces, eg "token.go:123".
+var sourceLineRegex = regexp.MustCompile(`^[\w.-]+\.[A-Za-z]+:\d+$`)
+
+// redactKeyValues replaces the values of the "key: value" and "key = value" pairs with a matching key.
+// File and line references such as the frames of goroutine dumps are not key value pairs and are kept.
+func (r *Redactor) redactKeyValues(data []byte) []byte {
+	return r.keyRegex.ReplaceAllFunc(data, func(match []byte) []byte {
+		loc := r.keyRegex.FindSubmatchIndex(match)
+		if loc == nil {
+			return match
+		}
+		if sourceLineRegex.Match(match) {
+			return match
+		}
+		redacted := make([]byte, 0, loc[5]+len(RedactedValue))
+		redacted = append(redacted, match[:loc[5]]...)
+		return append(redacted, RedactedValue...)
+	})
+}
+
+func redactPattern(re *regexp.Regexp, data []byte) []byte {
+	secret := re.SubexpIndex("secret")
+	if secret < 0 {
+		return re.ReplaceAll(data, []byte(RedactedValue))
+	}
+
+	return re.ReplaceAllFunc(data, func(match []byte) []byte {
+		loc := re.FindSubmatchIndex(match)
+		if loc == nil || loc[2*secret] < 0 {
+			return match
+		}
+		start, end := loc[2*secret], loc[2*secret+1]
+		redacted := make([]byte, 0, len(match))
+		redacted = append(redacted, match[:start]...)
+		redacted = append(redacted, RedactedValue...)
+		return append(redacted, match[end:]...)
+	})
+}
+
+func (r *Redactor) redactJSON(data []byte) ([]byte, bool) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return nil, false
+	}
+
+	redacted, err := json.MarshalIndent(r.redactValue(value, false), "", " ")
+	if err != nil {
+		return nil, false
+	}
+	return redacted, true
+}
+
+// redactValue walks a decoded JSON value. All scalar values below a matching key are replaced.
+func (r *Redactor) redactValue(value any, sensitive bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			v[key] = r.redactValue(child, sensitive || r.isSensitiveKey(key))
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = r.redactValue(child, sensitive)
+		}
+		return v
+	case nil, bool:
+		return v
+	default:
+		if sensitive && fmt.Sprint(v) != "" {
+			return RedactedValue
+		}
+		return v
+	}
+}
+
+func (r *Redactor) isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range r.keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package supportbundlesimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/supportbundles/bundleregistry"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// OfflineCollectors are the collectors available when creating a bundle without a running server.
+var OfflineCollectors = []string{"basic", "settings", "db", "unified-storage-index"}
+
+// CreateOfflineBundle creates a support bundle from the configuration and database of an instance
+// that is not running. Only the collectors that don't depend on running services are available.
+// The bundle is redacted and encrypted according to the [support_bundles] settings.
+func CreateOfflineBundle(ctx context.Context, cfg *setting.Cfg, settings setting.Provider, sql db.DB, collectors []string) ([]byte, error) {
+	redactor, err := newRedactor(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Service{
+		bundleRegistry:       bundleregistry.ProvideService(),
+		cfg:                  cfg,
+		encryptionPublicKeys: cfg.SectionWithEnvOverrides("support_bundles").Key("public_keys").Strings(" "),
+		log:                  log.New("supportbundle.offline"),
+		redactor:             redactor,
+		tracer:               tracing.NewNoopTracerService(),
+	}
+
+	s.bundleRegistry.RegisterSupportItemCollector(basicCollector(cfg))
+	s.bundleRegistry.RegisterSupportItemCollector(settingsCollector(settings))
+	s.bundleRegistry.RegisterSupportItemCollector(dbCollector(sql))
+	s.bundleRegistry.RegisterSupportItemCollector(unifiedStorageIndexCollector(cfg))
+
+	if len(collectors) == 0 {
+		collectors = OfflineCollectors
+	}
+
+	return s.bundle(ctx, collectors, "offline")
+}
+
+// IsEncrypted returns true when bundles are encrypted with the configured public keys.
+func IsEncrypted(cfg *setting.Cfg) bool {
+	return len(cfg.SectionWithEnvOverrides("support_bundles").Key("public_keys").Strings(" ")) > 0
+}
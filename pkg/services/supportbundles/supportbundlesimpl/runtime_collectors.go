@@ -0,0 +1,149 @@
+package supportbundlesimpl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/supportbundles"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func goroutineCollector() supportbundles.Collector {
+	return supportbundles.Collector{
+		UID:               "goroutines",
+		DisplayName:       "Goroutine dump",
+		Description:       "Stack traces of all current goroutines",
+		IncludedByDefault: false,
+		Default:           false,
+		Fn: func(ctx context.Context) (*supportbundles.SupportItem, error) {
+			var buf bytes.Buffer
+			if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+				return nil, err
+			}
+
+			return &supportbundles.SupportItem{
+				Filename:  "goroutines.txt",
+				FileBytes: buf.Bytes(),
+			}, nil
+		},
+	}
+}
+
+type indexStats struct {
+	Namespace    string    `json:"namespace"`
+	Resource     string    `json:"resource"`
+	Files        int       `json:"files"`
+	SizeBytes    int64     `json:"size_bytes"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+func unifiedStorageIndexCollector(cfg *setting.Cfg) supportbundles.Collector {
+	return supportbundles.Collector{
+		UID:               "unified-storage-index",
+		DisplayName:       "Unified storage index",
+		Description:       "Size and age of the unified storage search indexes on disk",
+		IncludedByDefault: false,
+		Default:           true,
+		Fn: func(ctx context.Context) (*supportbundles.SupportItem, error) {
+			root := cfg.IndexPath
+			if root == "" {
+				root = filepath.Join(cfg.DataPath, "unified-search", "bleve")
+			}
+
+			stats, err := collectIndexStats(root)
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := json.MarshalIndent(struct {
+				Path    string       `json:"path"`
+				Indexes []indexStats `json:"indexes"`
+			}{Path: root, Indexes: stats}, "", " ")
+			if err != nil {
+				return nil, err
+			}
+
+			return &supportbundles.SupportItem{
+				Filename:  "unified-storage-index.json",
+				FileBytes: data,
+			}, nil
+		},
+	}
+}
+
+// collectIndexStats aggregates the files below root, which is laid out as <namespace>/<resource>/...
+func collectIndexStats(root string) ([]indexStats, error) {
+	byIndex := map[string]*indexStats{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) < 3 {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		key := parts[0] + "/" + parts[1]
+		s, ok := byIndex[key]
+		if !ok {
+			s = &indexStats{Namespace: parts[0], Resource: parts[1]}
+			byIndex[key] = s
+		}
+		s.Files++
+		s.SizeBytes += info.Size()
+		if info.ModTime().After(s.LastModified) {
+			s.LastModified = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	stats := make([]indexStats, 0, len(byIndex))
+	for _, s := range byIndex {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Namespace != stats[j].Namespace {
+			return stats[i].Namespace < stats[j].Namespace
+		}
+		return stats[i].Resource < stats[j].Resource
+	})
+	return stats, nil
+}
+
+// orgIDsFn lists the IDs of all organizations, for collectors that report per organization.
+func orgIDsFn(sql db.DB) func(ctx context.Context) ([]int64, error) {
+	return func(ctx context.Context) ([]int64, error) {
+		ids := make([]int64, 0)
+		err := sql.WithDbSession(ctx, func(sess *db.Session) error {
+			return sess.Table("org").Cols("id").Find(&ids)
+		})
+		return ids, err
+	}
+}
@@ -0,0 +1,39 @@
+package supportbundlesimpl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectIndexStats(t *testing.T) {
+	root := t.TempDir()
+
+	write := func(path string, size int) {
+		full := filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o750))
+		require.NoError(t, os.WriteFile(full, make([]byte, size), 0o600))
+	}
+	write("default/dashboards.dashboard.grafana.app/1234/store/root.bolt", 10)
+	write("default/dashboards.dashboard.grafana.app/1234/index_meta.json", 5)
+	write("default/folders.folder.grafana.app/5678/store/root.bolt", 7)
+	write("stray.txt", 1)
+
+	stats, err := collectIndexStats(root)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, "default", stats[0].Namespace)
+	assert.Equal(t, "dashboards.dashboard.grafana.app", stats[0].Resource)
+	assert.Equal(t, 2, stats[0].Files)
+	assert.Equal(t, int64(15), stats[0].SizeBytes)
+	assert.False(t, stats[0].LastModified.IsZero())
+	assert.Equal(t, "folders.folder.grafana.app", stats[1].Resource)
+
+	stats, err = collectIndexStats(filepath.Join(root, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+}
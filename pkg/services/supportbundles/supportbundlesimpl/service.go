@@ -34,6 +34,7 @@ type Service struct {
 	features       featuremgmt.FeatureToggles
 	pluginSettings pluginsettings.Service
 	pluginStore    pluginstore.Store
+	redactor       *supportbundles.Redactor
 	store          bundleStore
 	tracer         tracing.Tracer
 
@@ -60,6 +61,11 @@ func ProvideService(
 	usageStats usagestats.Service,
 	tracer tracing.Tracer) (*Service, error) {
 	section := cfg.SectionWithEnvOverrides("support_bundles")
+	redactor, err := newRedactor(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	s := &Service{
 		accessControl:        accessControl,
 		bundleRegistry:       bundleRegistry,
@@ -70,6 +76,7 @@ func ProvideService(
 		log:                  log.New("supportbundle.service"),
 		pluginSettings:       pluginSettings,
 		pluginStore:          pluginStore,
+		redactor:             redactor,
 		serverAdminOnly:      section.Key("server_admin_only").MustBool(true),
 		store:                newStore(kvStore),
 		tracer:               tracer,
@@ -92,10 +99,27 @@ func ProvideService(
 	s.bundleRegistry.RegisterSupportItemCollector(settingsCollector(settings))
 	s.bundleRegistry.RegisterSupportItemCollector(dbCollector(sql))
 	s.bundleRegistry.RegisterSupportItemCollector(pluginInfoCollector(pluginStore, pluginSettings, s.log))
+	s.bundleRegistry.RegisterSupportItemCollector(goroutineCollector())
+	s.bundleRegistry.RegisterSupportItemCollector(unifiedStorageIndexCollector(cfg))
+	if httpServer.AlertNG != nil {
+		for _, collector := range httpServer.AlertNG.SupportBundleCollectors() {
+			s.bundleRegistry.RegisterSupportItemCollector(collector)
+		}
+	}
+	if httpServer.Live != nil {
+		s.bundleRegistry.RegisterSupportItemCollector(httpServer.Live.SupportBundleCollector(orgIDsFn(sql)))
+	}
 
 	return s, nil
 }
 
+// newRedactor creates the redactor applied to all support bundle items from the
+// redact_keys and redact_patterns settings.
+func newRedactor(cfg *setting.Cfg) (*supportbundles.Redactor, error) {
+	section := cfg.SectionWithEnvOverrides("support_bundles")
+	return supportbundles.NewRedactor(section.Key("redact_keys").Strings(" "), section.Key("redact_patterns").Strings(" "))
+}
+
 func (s *Service) Run(ctx context.Context) error {
 	if !s.enabled {
 		return nil
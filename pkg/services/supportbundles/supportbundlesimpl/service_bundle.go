@@ -99,6 +99,9 @@ func (s *Service) bundle(ctx context.Context, collectors []string, uid string) (
 
 		// write item to file
 		if item != nil {
+			if s.redactor != nil {
+				item = s.redactor.Redact(item)
+			}
 			files[item.Filename] = item.FileBytes
 		}
 
@@ -133,6 +133,45 @@ func TestService_bundleEncryptDecryptMultipleRecipients(t *testing.T) {
 	confirmFilesInTar(t, tarBytes2)
 }
 
+func TestService_bundleRedact(t *testing.T) {
+	redactor, err := supportbundles.NewRedactor(nil, nil)
+	require.NoError(t, err)
+
+	s := &Service{
+		log:            log.New("test"),
+		bundleRegistry: bundleregistry.ProvideService(),
+		redactor:       redactor,
+		store:          newStore(kvstore.NewFakeKVStore()),
+		tracer:         tracing.InitializeTracerForTest(),
+	}
+
+	s.bundleRegistry.RegisterSupportItemCollector(supportbundles.Collector{
+		UID:               "secrets",
+		IncludedByDefault: true,
+		Fn: func(ctx context.Context) (*supportbundles.SupportItem, error) {
+			return &supportbundles.SupportItem{
+				Filename:  "secrets.json",
+				FileBytes: []byte(`{"admin_password": "hunter2", "url": "postgres://grafana:hunter3@db:5432"}`),
+			}, nil
+		},
+	})
+
+	tarBytes, err := s.bundle(context.Background(), nil, "test")
+	require.NoError(t, err)
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(tarBytes))
+	require.NoError(t, err)
+	tr := tar.NewReader(gzipReader)
+	_, err = tr.Next()
+	require.NoError(t, err)
+	content, err := io.ReadAll(tr)
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(content), "hunter2")
+	assert.NotContains(t, string(content), "hunter3")
+	assert.Contains(t, string(content), supportbundles.RedactedValue)
+}
+
 func decryptTar(t *testing.T, tarBytes []byte, privateKey string) []byte {
 	reader := bytes.NewReader(tarBytes)
 	t.Helper()
@@ -401,12 +401,16 @@ type Cfg struct {
 	SqlDatasourceMaxOpenConnsDefault    int
 	SqlDatasourceMaxIdleConnsDefault    int
 	SqlDatasourceMaxConnLifetimeDefault int
+	// SqliteDatasourceAllowedDirectories are the directories the SQLite data source can open files from
+	SqliteDatasourceAllowedDirectories []string
 
 	// Snapshots
 	SnapshotEnabled      bool
 	ExternalSnapshotUrl  string
 	ExternalSnapshotName string
 	ExternalEnabled      bool
+	// Go CDK bucket URL where snapshot dashboards are stored instead of the database
+	SnapshotStorageURL string
 
 	// Only used in https://snapshots.raintank.io/
 	SnapshotPublicMode bool
@@ -653,6 +657,7 @@ type Cfg struct {
 	IndexCacheTTL                              time.Duration
 	IndexMinUpdateInterval                     time.Duration // Don't update index if it was updated less than this interval ago.
 	IndexModificationCacheTTL                  time.Duration // TTL for dedup cache used in ListModifiedSince. 0 disables the cache.
+	IndexSearchFuzziness                       int           // Max typos (edit distance, 0-2) allowed in text search terms. 0 disables fuzzy matching and suggestions.
 	MaxFileIndexAge                            time.Duration // Max age of file-based indexes. Index older than this will be rebuilt asynchronously.
 	MinFileIndexBuildVersion                   string        // Minimum version of Grafana that built the file-based index. If index was built with older Grafana, it will be rebuilt asynchronously.
 	IndexSnapshotEnabled                       bool          // Enable remote index snapshots
@@ -692,6 +697,17 @@ type Cfg struct {
 	GarbageCollectionBatchWait                 time.Duration
 	GarbageCollectionMaxAge                    time.Duration
 	DashboardsGarbageCollectionMaxAge          time.Duration
+	HistoryArchiveBucketURL                    string
+	HistoryArchiveMaxAge                       time.Duration
+	HistoryArchiveInterval                     time.Duration
+	HistoryArchiveBatchSize                    int
+	HistoryArchiveCompactionThreshold          int
+	WebhooksEnabled                            bool
+	WebhooksMaxAttempts                        int
+	WebhooksInitialBackoff                     time.Duration
+	WebhooksMaxBackoff                         time.Duration
+	WebhooksTimeout                            time.Duration
+	WebhooksAllowPrivateNetworks               bool
 	// StorageModeCacheTTL is the TTL for caching statusReader results in the dynamic dualwrite service.
 	// Default: 5 seconds, 0 or negative means no expiration.
 	StorageModeCacheTTL time.Duration
@@ -2158,6 +2174,7 @@ func readSnapshotsSettings(cfg *Cfg, iniFile *ini.File) error {
 
 	cfg.ExternalEnabled = snapshots.Key("external_enabled").MustBool(true)
 	cfg.SnapshotPublicMode = snapshots.Key("public_mode").MustBool(false)
+	cfg.SnapshotStorageURL = valueAsString(snapshots, "storage_url", "")
 
 	return nil
 }
@@ -2298,6 +2315,7 @@ func (cfg *Cfg) readSqlDataSourceSettings() {
 	cfg.SqlDatasourceMaxOpenConnsDefault = sqlDatasources.Key("max_open_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxIdleConnsDefault = sqlDatasources.Key("max_idle_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxConnLifetimeDefault = sqlDatasources.Key("max_conn_lifetime_default").MustInt(14400)
+	cfg.SqliteDatasourceAllowedDirectories = util.SplitString(sqlDatasources.Key("sqlite_allowed_directories").MustString(""))
 }
 
 func GetAllowedOriginGlobs(originPatterns []string) ([]glob.Glob, error) {
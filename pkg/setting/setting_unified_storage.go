@@ -179,6 +179,7 @@ func (cfg *Cfg) setUnifiedStorageConfig() {
 	cfg.IndexCacheTTL = section.Key("index_cache_ttl").MustDuration(10 * time.Minute)
 	cfg.IndexMinUpdateInterval = section.Key("index_min_update_interval").MustDuration(0)
 	cfg.IndexModificationCacheTTL = section.Key("index_modification_cache_ttl").MustDuration(0)
+	cfg.IndexSearchFuzziness = section.Key("index_search_fuzziness").MustInt(1)
 	cfg.SprinklesApiServer = section.Key("sprinkles_api_server").String()
 	cfg.SprinklesApiServerPageLimit = section.Key("sprinkles_api_server_page_limit").MustInt(10000)
 	cfg.CACertPath = section.Key("ca_cert_path").String()
@@ -210,6 +211,21 @@ func (cfg *Cfg) setUnifiedStorageConfig() {
 	cfg.GarbageCollectionMaxAge = section.Key("garbage_collection_max_age").MustDuration(24 * time.Hour)
 	cfg.DashboardsGarbageCollectionMaxAge = section.Key("dashboards_garbage_collection_max_age").MustDuration(365 * 24 * time.Hour)
 
+	// history archive, disabled when no bucket is configured
+	cfg.HistoryArchiveBucketURL = section.Key("history_archive_bucket_url").String()
+	cfg.HistoryArchiveMaxAge = section.Key("history_archive_max_age").MustDuration(30 * 24 * time.Hour)
+	cfg.HistoryArchiveInterval = section.Key("history_archive_interval").MustDuration(1 * time.Hour)
+	cfg.HistoryArchiveBatchSize = section.Key("history_archive_batch_size").MustInt(10000)
+	cfg.HistoryArchiveCompactionThreshold = section.Key("history_archive_compaction_threshold").MustInt(10)
+
+	// webhook subscriptions to the write events (requires the sqlkv backend)
+	cfg.WebhooksEnabled = section.Key("webhooks_enabled").MustBool(false)
+	cfg.WebhooksMaxAttempts = section.Key("webhooks_max_attempts").MustInt(5)
+	cfg.WebhooksInitialBackoff = section.Key("webhooks_initial_backoff").MustDuration(time.Second)
+	cfg.WebhooksMaxBackoff = section.Key("webhooks_max_backoff").MustDuration(5 * time.Minute)
+	cfg.WebhooksTimeout = section.Key("webhooks_timeout").MustDuration(10 * time.Second)
+	cfg.WebhooksAllowPrivateNetworks = section.Key("webhooks_allow_private_networks").MustBool(false)
+
 	cfg.EventRetentionPeriod = section.Key("event_retention_period").MustDuration(1 * time.Hour)
 	cfg.EventPruningInterval = section.Key("event_pruning_interval").MustDuration(5 * time.Minute)
 	cfg.SearchLookback = section.Key("search_lookback").MustDuration(1 * time.Second)
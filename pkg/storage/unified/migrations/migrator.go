@@ -120,7 +120,7 @@ func (m *unifiedMigration) Migrate(ctx context.Context, opts MigrateOptions) (*r
 			"alertmanager", "azuremonitor", "cloud-monitoring", "cloudwatch", "dashboard", "elasticsearch",
 			"grafana-postgresql-datasource", "grafana-pyroscope-datasource", "grafana-testdata-datasource",
 			"graphite", "influxdb", "jaeger", "loki", "mixed", "mssql", "mysql", "opentsdb", "parca", "prometheus",
-			"tempo", "zipkin",
+			"sqlite", "tempo", "zipkin",
 		}
 		opts.Resources = make([]schema.GroupResource, 0, len(plugins))
 		for _, p := range plugins {
@@ -0,0 +1,426 @@
+package parquet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/grafana/grafana-app-sdk/logging"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+var (
+	_ resource.StorageBackend        = (*ArchiveBackend)(nil)
+	_ resource.BulkProcessingBackend = (*ArchiveBackend)(nil)
+	_ resource.BlobSupport           = (*ArchiveBackend)(nil)
+	_ resource.ResourceServerStopper = (*ArchiveBackend)(nil)
+	_ resource.StatsGetter           = (*ArchiveBackend)(nil)
+	_ resource.HistoryQuotaSupport   = (*ArchiveBackend)(nil)
+	_ resourcepb.DiagnosticsServer   = (*ArchiveBackend)(nil) //nolint:staticcheck
+)
+
+const (
+	defaultArchiveMaxAge              = 30 * 24 * time.Hour
+	defaultArchiveBatchSize           = 10000
+	defaultArchiveCompactionThreshold = 10
+)
+
+// HistoryRevision is a revision of a resource in the history of the hot tier
+type HistoryRevision struct {
+	GUID            string
+	Key             *resourcepb.ResourceKey
+	Folder          string
+	Action          resourcepb.WatchEvent_Type
+	ResourceVersion int64
+	Value           []byte
+}
+
+// HistorySource is implemented by the storage backends whose history can be archived
+type HistorySource interface {
+	// ListArchivableHistory returns up to limit revisions of a namespace and resource older than the
+	// cutoff resource version, oldest first. The latest revision of a resource and deletions are never
+	// archived, so the trash and the latest deletion of a resource are always read from the hot tier.
+	ListArchivableHistory(ctx context.Context, key resource.NamespacedResource, cutoffRV int64, limit int) ([]*HistoryRevision, error)
+
+	// DeleteArchivedHistory removes the archived revisions from the history
+	DeleteArchivedHistory(ctx context.Context, key resource.NamespacedResource, guids []string) (int64, error)
+
+	// LatestDeletedRV returns the resource version of the last time the resource was deleted, or zero
+	LatestDeletedRV(ctx context.Context, key *resourcepb.ResourceKey) (int64, error)
+
+	// ResourceVersionAt returns the resource version of a write made at the given time, in the format
+	// used by the backend
+	ResourceVersionAt(t time.Time) int64
+}
+
+type ArchiveBackendOptions struct {
+	// The hot tier, it must implement HistorySource
+	Backend resource.StorageBackend
+
+	// Where the archived history is saved
+	Bucket     resource.CDKBucket
+	RootFolder string
+
+	MaxAge              time.Duration // revisions older than this are archived
+	Interval            time.Duration // how often the history is archived, zero disables archiving in the background
+	BatchSize           int           // max number of revisions in an archive file
+	CompactionThreshold int           // number of archive files of a namespace and resource before they are merged
+}
+
+// ArchiveBackend is a storage backend that moves the old history of the hot tier into parquet
+// files in a bucket. Reading a resource at a version, and listing history, read both tiers.
+// Everything else is handled by the hot tier.
+type ArchiveBackend struct {
+	services.Service
+
+	hot    resource.StorageBackend
+	source HistorySource
+	store  *archiveStore
+	log    logging.Logger
+
+	maxAge              time.Duration
+	interval            time.Duration
+	batchSize           int
+	compactionThreshold int
+}
+
+func NewArchiveBackend(opts ArchiveBackendOptions) (*ArchiveBackend, error) {
+	if opts.Backend == nil {
+		return nil, fmt.Errorf("missing backend")
+	}
+	source, ok := opts.Backend.(HistorySource)
+	if !ok {
+		return nil, fmt.Errorf("the backend does not support archiving history")
+	}
+	if opts.Bucket == nil {
+		return nil, fmt.Errorf("missing bucket")
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = defaultArchiveMaxAge
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultArchiveBatchSize
+	}
+	if opts.CompactionThreshold <= 0 {
+		opts.CompactionThreshold = defaultArchiveCompactionThreshold
+	}
+
+	b := &ArchiveBackend{
+		hot:    opts.Backend,
+		source: source,
+		store: &archiveStore{
+			bucket: opts.Bucket,
+			root:   opts.RootFolder,
+		},
+		log:                 logging.DefaultLogger.With("logger", "parquet.archive"),
+		maxAge:              opts.MaxAge,
+		interval:            opts.Interval,
+		batchSize:           opts.BatchSize,
+		compactionThreshold: opts.CompactionThreshold,
+	}
+	b.Service = services.NewBasicService(b.starting, b.running, b.stopping)
+	return b, nil
+}
+
+func (b *ArchiveBackend) starting(ctx context.Context) error {
+	if svc, ok := b.hot.(services.Service); ok {
+		return services.StartAndAwaitRunning(ctx, svc)
+	}
+	return nil
+}
+
+func (b *ArchiveBackend) running(ctx context.Context) error {
+	if b.interval <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := b.Archive(ctx); err != nil {
+				b.log.Error("failed to archive history", "error", err)
+			}
+		}
+	}
+}
+
+func (b *ArchiveBackend) stopping(_ error) error {
+	if svc, ok := b.hot.(services.Service); ok {
+		return services.StopAndAwaitTerminated(context.Background(), svc)
+	}
+	return nil
+}
+
+// Archive moves the history older than the max age of every namespace and resource into the
+// archive, and compacts the archive files.
+func (b *ArchiveBackend) Archive(ctx context.Context) error {
+	stats, err := b.hot.GetResourceStats(ctx, resource.NamespacedResource{}, 0)
+	if err != nil {
+		return fmt.Errorf("list resources: %w", err)
+	}
+
+	cutoffRV := b.source.ResourceVersionAt(time.Now().Add(-b.maxAge))
+	var errs []error
+	for _, stat := range stats {
+		if err := b.archive(ctx, stat.NamespacedResource, cutoffRV); err != nil {
+			errs = append(errs, fmt.Errorf("archive %s: %w", stat.String(), err))
+			continue
+		}
+		if err := b.Compact(ctx, stat.NamespacedResource); err != nil {
+			errs = append(errs, fmt.Errorf("compact %s: %w", stat.String(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *ArchiveBackend) archive(ctx context.Context, key resource.NamespacedResource, cutoffRV int64) error {
+	total := 0
+	for {
+		revisions, err := b.source.ListArchivableHistory(ctx, key, cutoffRV, b.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(revisions) == 0 {
+			break
+		}
+
+		// The revisions are only deleted from the hot tier once they are archived
+		file, err := b.store.write(ctx, key, revisions)
+		if err != nil {
+			return err
+		}
+		guids := make([]string, 0, len(revisions))
+		for _, rev := range revisions {
+			guids = append(guids, rev.GUID)
+		}
+		if _, err := b.source.DeleteArchivedHistory(ctx, key, guids); err != nil {
+			return err
+		}
+
+		total += len(revisions)
+		b.log.Debug("archived history", "namespace", key.Namespace, "group", key.Group, "resource", key.Resource, "file", file.path, "revisions", len(revisions))
+		if len(revisions) < b.batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		b.log.Info("archived history", "namespace", key.Namespace, "group", key.Group, "resource", key.Resource, "revisions", total)
+	}
+	return nil
+}
+
+// Compact merges the archive files of a namespace and resource once there are enough of them
+func (b *ArchiveBackend) Compact(ctx context.Context, key resource.NamespacedResource) error {
+	merged, err := b.store.compact(ctx, key, b.compactionThreshold)
+	if err != nil {
+		return err
+	}
+	if merged > 0 {
+		b.log.Info("compacted archive files", "namespace", key.Namespace, "group", key.Group, "resource", key.Resource, "files", merged)
+	}
+	return nil
+}
+
+// WriteEvent implements resource.StorageBackend.
+func (b *ArchiveBackend) WriteEvent(ctx context.Context, event resource.WriteEvent) (int64, error) {
+	return b.hot.WriteEvent(ctx, event)
+}
+
+// ReadResource implements resource.StorageBackend.
+// A resource at a version that is no longer in the hot tier is read from the archive.
+func (b *ArchiveBackend) ReadResource(ctx context.Context, req *resourcepb.ReadRequest) *resource.BackendReadResponse {
+	rsp := b.hot.ReadResource(ctx, req)
+	if req.ResourceVersion <= 0 || rsp.Error == nil || rsp.Error.Code != http.StatusNotFound {
+		return rsp
+	}
+
+	rev, err := b.store.readAt(ctx, req.Key, req.ResourceVersion)
+	if err != nil {
+		return &resource.BackendReadResponse{Error: resource.AsErrorResult(err)}
+	}
+	if rev == nil {
+		return rsp
+	}
+	return &resource.BackendReadResponse{
+		Key:             rev.Key,
+		Folder:          rev.Folder,
+		ResourceVersion: rev.ResourceVersion,
+		Value:           rev.Value,
+	}
+}
+
+// ListIterator implements resource.StorageBackend.
+func (b *ArchiveBackend) ListIterator(ctx context.Context, req *resourcepb.ListRequest, cb func(resource.ListIterator) error) (int64, error) {
+	return b.hot.ListIterator(ctx, req, cb)
+}
+
+// ListHistory implements resource.StorageBackend.
+// The history of the hot tier is merged with the archived revisions. The trash is only in the
+// hot tier, since deletions are never archived.
+func (b *ArchiveBackend) ListHistory(ctx context.Context, req *resourcepb.ListRequest, cb func(resource.ListIterator) error) (int64, error) {
+	if req.Source == resourcepb.ListRequest_TRASH || req.Options == nil || req.Options.Key == nil {
+		return b.hot.ListHistory(ctx, req, cb)
+	}
+	key := req.Options.Key
+
+	// Same order as the hot tier: ascending when using NotOlderThan matching, descending otherwise
+	token := &archiveContinueToken{
+		SortAscending: req.GetVersionMatchV2() == resourcepb.ResourceVersionMatchV2_NotOlderThan,
+	}
+	if req.NextPageToken != "" {
+		var err error
+		token, err = getArchiveContinueToken(req.NextPageToken)
+		if err != nil {
+			return 0, fmt.Errorf("get continue token (%q): %w", req.NextPageToken, err)
+		}
+	}
+
+	filter := historyFilter{name: key.Name}
+	switch {
+	case req.VersionMatchV2 == resourcepb.ResourceVersionMatchV2_Exact:
+		filter.minRV = req.ResourceVersion
+		filter.maxRV = req.ResourceVersion
+	case req.ResourceVersion > 0 && req.VersionMatchV2 == resourcepb.ResourceVersionMatchV2_NotOlderThan:
+		filter.minRV = req.ResourceVersion
+	case key.Name != "":
+		// The history before the last deletion is hidden, like in the hot tier
+		deletedRV, err := b.source.LatestDeletedRV(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		filter.minRV = deletedRV + 1
+	}
+	if token.ResourceVersion > 0 {
+		if token.SortAscending {
+			filter.minRV = max(filter.minRV, token.ResourceVersion+1)
+		} else if filter.maxRV <= 0 || token.ResourceVersion-1 < filter.maxRV {
+			filter.maxRV = token.ResourceVersion - 1
+		}
+	}
+
+	archived, err := b.store.history(ctx, resource.NamespacedResource{
+		Namespace: key.Namespace,
+		Group:     key.Group,
+		Resource:  key.Resource,
+	}, filter, token.SortAscending)
+	if err != nil {
+		return 0, fmt.Errorf("read archived history: %w", err)
+	}
+
+	hotReq := proto.Clone(req).(*resourcepb.ListRequest)
+	hotReq.NextPageToken = token.Hot
+	return b.hot.ListHistory(ctx, hotReq, func(hot resource.ListIterator) error {
+		return cb(&historyIterator{
+			hot:           hot,
+			archived:      archived,
+			sortAscending: token.SortAscending,
+			hotToken:      token.Hot,
+		})
+	})
+}
+
+// ListModifiedSince implements resource.StorageBackend.
+func (b *ArchiveBackend) ListModifiedSince(ctx context.Context, key resource.NamespacedResource, sinceRv int64, lastCalledWithSinceRv *time.Time) (int64, iter.Seq2[*resource.ModifiedResource, error]) {
+	return b.hot.ListModifiedSince(ctx, key, sinceRv, lastCalledWithSinceRv)
+}
+
+// WatchWriteEvents implements resource.StorageBackend.
+func (b *ArchiveBackend) WatchWriteEvents(ctx context.Context) (<-chan *resource.WrittenEvent, error) {
+	return b.hot.WatchWriteEvents(ctx)
+}
+
+// GetResourceStats implements resource.StorageBackend.
+func (b *ArchiveBackend) GetResourceStats(ctx context.Context, nsr resource.NamespacedResource, minCount int) ([]resource.ResourceStats, error) {
+	return b.hot.GetResourceStats(ctx, nsr, minCount)
+}
+
+// GetResourceLastImportTimes implements resource.StorageBackend.
+func (b *ArchiveBackend) GetResourceLastImportTimes(ctx context.Context) iter.Seq2[resource.ResourceLastImportTime, error] {
+	return b.hot.GetResourceLastImportTimes(ctx)
+}
+
+// ProcessBulk implements resource.BulkProcessingBackend.
+func (b *ArchiveBackend) ProcessBulk(ctx context.Context, setting resource.BulkSettings, iter resource.BulkRequestIterator) *resourcepb.BulkResponse {
+	bulk, ok := b.hot.(resource.BulkProcessingBackend)
+	if !ok {
+		return &resourcepb.BulkResponse{
+			Error: resource.AsErrorResult(fmt.Errorf("the backend does not support bulk processing")),
+		}
+	}
+	return bulk.ProcessBulk(ctx, setting, iter)
+}
+
+// IsHealthy implements resourcepb.DiagnosticsServer.
+func (b *ArchiveBackend) IsHealthy(ctx context.Context, req *resourcepb.HealthCheckRequest) (*resourcepb.HealthCheckResponse, error) {
+	if diagnostics, ok := b.hot.(resourcepb.DiagnosticsServer); ok { //nolint:staticcheck
+		return diagnostics.IsHealthy(ctx, req) //nolint:staticcheck
+	}
+	return &resourcepb.HealthCheckResponse{Status: resourcepb.HealthCheckResponse_SERVING}, nil
+}
+
+// GetStats implements resource.StatsGetter.
+func (b *ArchiveBackend) GetStats(ctx context.Context, req *resourcepb.ResourceStatsRequest) (*resourcepb.ResourceStatsResponse, error) {
+	if stats, ok := b.hot.(resource.StatsGetter); ok {
+		return stats.GetStats(ctx, req)
+	}
+	return nil, fmt.Errorf("the backend does not support stats")
+}
+
+// SetHistoryQuotas implements resource.HistoryQuotaSupport.
+// The quotas are enforced by the pruner of the hot tier.
+func (b *ArchiveBackend) SetHistoryQuotas(quotas resource.HistoryQuotas) {
+	if q, ok := b.hot.(resource.HistoryQuotaSupport); ok {
+		q.SetHistoryQuotas(quotas)
+	}
+}
+
+// Stop implements resource.ResourceServerStopper.
+func (b *ArchiveBackend) Stop(ctx context.Context) error {
+	if stopper, ok := b.hot.(resource.ResourceServerStopper); ok {
+		return stopper.Stop(ctx)
+	}
+	return nil
+}
+
+// SupportsSignedURLs implements resource.BlobSupport.
+func (b *ArchiveBackend) SupportsSignedURLs() bool {
+	if blobs, ok := b.hot.(resource.BlobSupport); ok {
+		return blobs.SupportsSignedURLs()
+	}
+	return false
+}
+
+// PutResourceBlob implements resource.BlobSupport.
+func (b *ArchiveBackend) PutResourceBlob(ctx context.Context, req *resourcepb.PutBlobRequest) (*resourcepb.PutBlobResponse, error) {
+	if blobs, ok := b.hot.(resource.BlobSupport); ok {
+		return blobs.PutResourceBlob(ctx, req)
+	}
+	return &resourcepb.PutBlobResponse{
+		Error: resource.NewBadRequestError("the backend does not support blobs"),
+	}, nil
+}
+
+// GetResourceBlob implements resource.BlobSupport.
+func (b *ArchiveBackend) GetResourceBlob(ctx context.Context, key *resourcepb.ResourceKey, info *utils.BlobInfo, mustProxy bool) (*resourcepb.GetBlobResponse, error) {
+	if blobs, ok := b.hot.(resource.BlobSupport); ok {
+		return blobs.GetResourceBlob(ctx, key, info, mustProxy)
+	}
+	return &resourcepb.GetBlobResponse{
+		Error: resource.NewBadRequestError("the backend does not support blobs"),
+	}, nil
+}
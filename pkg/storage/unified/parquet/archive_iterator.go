@@ -0,0 +1,160 @@
+package parquet
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+)
+
+var (
+	_ resource.ListIterator = (*historyIterator)(nil)
+)
+
+// archiveContinueToken resumes listing history across both tiers. The hot tier is resumed
+// with its own token, the archive after the resource version of the last item.
+type archiveContinueToken struct {
+	ResourceVersion int64  `json:"v"`
+	SortAscending   bool   `json:"s"`
+	Hot             string `json:"h,omitempty"`
+}
+
+func (c archiveContinueToken) String() string {
+	b, _ := json.Marshal(c)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func getArchiveContinueToken(token string) (*archiveContinueToken, error) {
+	continueVal, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding continue token")
+	}
+
+	t := &archiveContinueToken{}
+	if err := json.Unmarshal(continueVal, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// historyIterator merges the history of the hot tier with the archived revisions, both sorted
+// by resource version in the same order. A revision that is in both tiers, because it was
+// archived but not yet deleted from the hot tier, is returned once.
+type historyIterator struct {
+	hot           resource.ListIterator
+	archived      []*HistoryRevision
+	sortAscending bool
+
+	// the next item of the hot tier, read ahead to compare it with the next archived revision
+	hotPending bool
+	hotDone    bool
+	hotToken   string
+
+	index   int // of the next archived revision
+	current historyItem
+	err     error
+}
+
+type historyItem struct {
+	rv        int64
+	namespace string
+	name      string
+	folder    string
+	value     []byte
+}
+
+// Next implements resource.ListIterator.
+func (i *historyIterator) Next() bool {
+	if i.err != nil {
+		return false
+	}
+	if !i.hotPending && !i.hotDone {
+		if i.hot.Next() {
+			if i.err = i.hot.Error(); i.err != nil {
+				return false
+			}
+			i.hotPending = true
+		} else {
+			i.err = i.hot.Error()
+			i.hotDone = true
+		}
+	}
+
+	var next *HistoryRevision
+	if i.index < len(i.archived) {
+		next = i.archived[i.index]
+	}
+
+	if i.hotPending {
+		rv := i.hot.ResourceVersion()
+		if next != nil && next.ResourceVersion == rv {
+			i.index++ // the revision is still in the hot tier
+			next = nil
+		}
+		if next == nil || (rv < next.ResourceVersion) == i.sortAscending {
+			i.current = historyItem{
+				rv:        rv,
+				namespace: i.hot.Namespace(),
+				name:      i.hot.Name(),
+				folder:    i.hot.Folder(),
+				value:     i.hot.Value(),
+			}
+			i.hotToken = i.hot.ContinueToken()
+			i.hotPending = false
+			return true
+		}
+	}
+
+	if next == nil {
+		return false
+	}
+	i.index++
+	i.current = historyItem{
+		rv:        next.ResourceVersion,
+		namespace: next.Key.Namespace,
+		name:      next.Key.Name,
+		folder:    next.Folder,
+		value:     next.Value,
+	}
+	return i.err == nil
+}
+
+// Error implements resource.ListIterator.
+func (i *historyIterator) Error() error {
+	return i.err
+}
+
+// ContinueToken implements resource.ListIterator.
+func (i *historyIterator) ContinueToken() string {
+	return archiveContinueToken{
+		ResourceVersion: i.current.rv,
+		SortAscending:   i.sortAscending,
+		Hot:             i.hotToken,
+	}.String()
+}
+
+// ResourceVersion implements resource.ListIterator.
+func (i *historyIterator) ResourceVersion() int64 {
+	return i.current.rv
+}
+
+// Namespace implements resource.ListIterator.
+func (i *historyIterator) Namespace() string {
+	return i.current.namespace
+}
+
+// Name implements resource.ListIterator.
+func (i *historyIterator) Name() string {
+	return i.current.name
+}
+
+// Folder implements resource.ListIterator.
+func (i *historyIterator) Folder() string {
+	return i.current.folder
+}
+
+// Value implements resource.ListIterator.
+func (i *historyIterator) Value() []byte {
+	return i.current.value
+}
@@ -0,0 +1,311 @@
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"gocloud.dev/blob"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+const (
+	archiveFileExtension   = ".parquet"
+	archiveContentType     = "application/vnd.apache.parquet"
+	archiveReadBatchSize   = 1024
+	archiveFileNameRVWidth = 19 // digits of the largest int64, so the names sort by resource version
+)
+
+// archiveStore keeps the archived history of each namespace and resource in parquet files.
+// The files are named after the range of resource versions they contain:
+//
+//	{root}{namespace}/{group}/{resource}/{minRV}-{maxRV}.parquet
+type archiveStore struct {
+	bucket resource.CDKBucket
+	root   string
+}
+
+type archiveFile struct {
+	path  string
+	minRV int64
+	maxRV int64
+}
+
+// historyFilter selects archived revisions, the zero value selects all of them
+type historyFilter struct {
+	name  string
+	minRV int64 // inclusive, ignored when zero
+	maxRV int64 // inclusive, ignored when zero
+}
+
+func (f historyFilter) matches(name string, rv int64) bool {
+	if f.name != "" && f.name != name {
+		return false
+	}
+	if f.minRV > 0 && rv < f.minRV {
+		return false
+	}
+	return f.maxRV <= 0 || rv <= f.maxRV
+}
+
+func (f historyFilter) overlaps(file archiveFile) bool {
+	if f.minRV > 0 && file.maxRV < f.minRV {
+		return false
+	}
+	return f.maxRV <= 0 || file.minRV <= f.maxRV
+}
+
+func (s *archiveStore) prefix(key resource.NamespacedResource) string {
+	var buffer strings.Builder
+	buffer.WriteString(s.root)
+	if key.Namespace != "" {
+		buffer.WriteString(key.Namespace)
+		buffer.WriteString("/")
+	}
+	buffer.WriteString(key.Group)
+	buffer.WriteString("/")
+	buffer.WriteString(key.Resource)
+	buffer.WriteString("/")
+	return buffer.String()
+}
+
+func (s *archiveStore) filePath(key resource.NamespacedResource, minRV, maxRV int64) string {
+	return fmt.Sprintf("%s%0*d-%0*d%s", s.prefix(key), archiveFileNameRVWidth, minRV, archiveFileNameRVWidth, maxRV, archiveFileExtension)
+}
+
+// files lists the archive files of a namespace and resource, oldest first
+func (s *archiveStore) files(ctx context.Context, key resource.NamespacedResource) ([]archiveFile, error) {
+	var files []archiveFile
+	iter := s.bucket.List(&blob.ListOptions{
+		Prefix:    s.prefix(key),
+		Delimiter: "/",
+	})
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if obj.IsDir {
+			continue
+		}
+
+		var minRV, maxRV int64
+		name := strings.TrimSuffix(path.Base(obj.Key), archiveFileExtension)
+		if _, err := fmt.Sscanf(name, "%d-%d", &minRV, &maxRV); err != nil {
+			continue // not an archive file
+		}
+		files = append(files, archiveFile{path: obj.Key, minRV: minRV, maxRV: maxRV})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].minRV < files[j].minRV
+	})
+	return files, nil
+}
+
+// write saves the revisions into a new archive file, the revisions must be sorted by resource version
+func (s *archiveStore) write(ctx context.Context, key resource.NamespacedResource, revisions []*HistoryRevision) (archiveFile, error) {
+	if len(revisions) == 0 {
+		return archiveFile{}, fmt.Errorf("no revisions to archive")
+	}
+
+	var buffer bytes.Buffer
+	writer, err := NewParquetWriter(&buffer)
+	if err != nil {
+		return archiveFile{}, err
+	}
+	for _, rev := range revisions {
+		if _, err := writer.writeRevision(rev.Key, rev.Folder, rev.ResourceVersion, rev.Action, rev.Value); err != nil {
+			_ = writer.Close()
+			return archiveFile{}, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return archiveFile{}, err
+	}
+
+	file := archiveFile{
+		minRV: revisions[0].ResourceVersion,
+		maxRV: revisions[len(revisions)-1].ResourceVersion,
+	}
+	file.path = s.filePath(key, file.minRV, file.maxRV)
+	err = s.bucket.WriteAll(ctx, file.path, buffer.Bytes(), &blob.WriterOptions{
+		ContentType: archiveContentType,
+	})
+	return file, err
+}
+
+// read returns the revisions of an archive file selected by the filter
+func (s *archiveStore) read(ctx context.Context, file archiveFile, filter historyFilter) ([]*HistoryRevision, error) {
+	contents, err := s.bucket.ReadAll(ctx, file.path)
+	if err != nil {
+		return nil, fmt.Errorf("read archive file %s: %w", file.path, err)
+	}
+
+	rdr, err := NewRecordReader(ctx, bytes.NewReader(contents), nil, archiveReadBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("open archive file %s: %w", file.path, err)
+	}
+	defer rdr.Release()
+
+	var revisions []*HistoryRevision
+	for rdr.Next() {
+		rows, err := readRevisions(rdr.RecordBatch(), filter)
+		if err != nil {
+			return nil, fmt.Errorf("read archive file %s: %w", file.path, err)
+		}
+		revisions = append(revisions, rows...)
+	}
+	if err := rdr.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("read archive file %s: %w", file.path, err)
+	}
+	return revisions, nil
+}
+
+// history returns the archived revisions of a namespace and resource selected by the filter,
+// sorted by resource version. Revisions found in more than one file, like while the files are
+// compacted, are only returned once.
+func (s *archiveStore) history(ctx context.Context, key resource.NamespacedResource, filter historyFilter, sortAscending bool) ([]*HistoryRevision, error) {
+	files, err := s.files(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []*HistoryRevision
+	for _, file := range files {
+		if !filter.overlaps(file) {
+			continue
+		}
+		rows, err := s.read(ctx, file, filter)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rows...)
+	}
+
+	sort.SliceStable(revisions, func(i, j int) bool {
+		if sortAscending {
+			return revisions[i].ResourceVersion < revisions[j].ResourceVersion
+		}
+		return revisions[i].ResourceVersion > revisions[j].ResourceVersion
+	})
+
+	unique := make([]*HistoryRevision, 0, len(revisions))
+	for _, rev := range revisions {
+		if len(unique) > 0 && unique[len(unique)-1].ResourceVersion == rev.ResourceVersion {
+			continue
+		}
+		unique = append(unique, rev)
+	}
+	return unique, nil
+}
+
+// readAt returns the latest archived revision of a resource at the resource version, or nil
+func (s *archiveStore) readAt(ctx context.Context, key *resourcepb.ResourceKey, rv int64) (*HistoryRevision, error) {
+	revisions, err := s.history(ctx, resource.NamespacedResource{
+		Namespace: key.Namespace,
+		Group:     key.Group,
+		Resource:  key.Resource,
+	}, historyFilter{name: key.Name, maxRV: rv}, false)
+	if err != nil || len(revisions) == 0 {
+		return nil, err
+	}
+	return revisions[0], nil
+}
+
+// compact merges the archive files of a namespace and resource into a single file once there
+// are at least threshold files. The new file is written before the merged files are deleted,
+// so the revisions can always be read.
+func (s *archiveStore) compact(ctx context.Context, key resource.NamespacedResource, threshold int) (int, error) {
+	files, err := s.files(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if len(files) < 2 || len(files) < threshold {
+		return 0, nil
+	}
+
+	revisions, err := s.history(ctx, key, historyFilter{}, true)
+	if err != nil {
+		return 0, err
+	}
+	merged, err := s.write(ctx, key, revisions)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, file := range files {
+		if file.path == merged.path {
+			continue
+		}
+		if err := s.bucket.Delete(ctx, file.path); err != nil {
+			return 0, fmt.Errorf("delete compacted archive file %s: %w", file.path, err)
+		}
+	}
+	return len(files), nil
+}
+
+// readRevisions returns the rows of a record selected by the filter. Values are copied, since
+// the record memory is released once the next record is read.
+func readRevisions(rec arrow.RecordBatch, filter historyFilter) ([]*HistoryRevision, error) {
+	rvs, err := recordColumn[*array.Int64](rec, "resource_version")
+	if err != nil {
+		return nil, err
+	}
+	actions, err := recordColumn[*array.Int8](rec, "action")
+	if err != nil {
+		return nil, err
+	}
+	strs := make(map[string]*array.String)
+	for _, name := range []string{"namespace", "group", "resource", "name", "folder", "value"} {
+		if strs[name], err = recordColumn[*array.String](rec, name); err != nil {
+			return nil, err
+		}
+	}
+
+	var revisions []*HistoryRevision
+	for i := 0; i < int(rec.NumRows()); i++ {
+		rv := rvs.Value(i)
+		if !filter.matches(strs["name"].Value(i), rv) {
+			continue
+		}
+		revisions = append(revisions, &HistoryRevision{
+			Key: &resourcepb.ResourceKey{
+				Namespace: strings.Clone(strs["namespace"].Value(i)),
+				Group:     strings.Clone(strs["group"].Value(i)),
+				Resource:  strings.Clone(strs["resource"].Value(i)),
+				Name:      strings.Clone(strs["name"].Value(i)),
+			},
+			Folder:          strings.Clone(strs["folder"].Value(i)),
+			Action:          resourcepb.WatchEvent_Type(actions.Value(i)),
+			ResourceVersion: rv,
+			Value:           []byte(strs["value"].Value(i)),
+		})
+	}
+	return revisions, nil
+}
+
+func recordColumn[T arrow.Array](rec arrow.RecordBatch, name string) (T, error) {
+	var col T
+	indices := rec.Schema().FieldIndices(name)
+	if len(indices) == 0 {
+		return col, fmt.Errorf("missing column: %s", name)
+	}
+	col, ok := rec.Column(indices[0]).(T)
+	if !ok {
+		return col, fmt.Errorf("unexpected type of column %s: %s", name, rec.Column(indices[0]).DataType())
+	}
+	return col, nil
+}
@@ -0,0 +1,332 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestArchiveBackend(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T, batchSize, compactionThreshold int) (*ArchiveBackend, *fakeHistoryBackend) {
+		hot := &fakeHistoryBackend{}
+		hot.add("a", 100, resourcepb.WatchEvent_ADDED)
+		hot.add("a", 200, resourcepb.WatchEvent_MODIFIED)
+		hot.add("a", 300, resourcepb.WatchEvent_MODIFIED)
+		hot.add("b", 110, resourcepb.WatchEvent_ADDED)
+		hot.add("b", 120, resourcepb.WatchEvent_DELETED)
+
+		backend, err := NewArchiveBackend(ArchiveBackendOptions{
+			Backend:             hot,
+			Bucket:              memblob.OpenBucket(nil),
+			RootFolder:          "history/",
+			BatchSize:           batchSize,
+			CompactionThreshold: compactionThreshold,
+		})
+		require.NoError(t, err)
+		require.NoError(t, backend.Archive(ctx))
+		return backend, hot
+	}
+
+	key := &resourcepb.ResourceKey{Namespace: "ns", Group: "group", Resource: "res", Name: "a"}
+
+	t.Run("requires a backend with history that can be archived", func(t *testing.T) {
+		_, err := NewArchiveBackend(ArchiveBackendOptions{
+			Backend: struct{ resource.StorageBackend }{},
+			Bucket:  memblob.OpenBucket(nil),
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("moves the old history into the archive", func(t *testing.T) {
+		backend, hot := setup(t, 0, 0)
+
+		// the latest revision and the deletion are kept in the hot tier
+		require.Equal(t, []int64{120, 300}, hot.resourceVersions())
+
+		files, err := backend.store.files(ctx, resource.NamespacedResource{Namespace: "ns", Group: "group", Resource: "res"})
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		require.Equal(t, "history/ns/group/res/0000000000000000100-0000000000000000200.parquet", files[0].path)
+	})
+
+	t.Run("compacts the archive files", func(t *testing.T) {
+		backend, _ := setup(t, 1, 2)
+
+		files, err := backend.store.files(ctx, resource.NamespacedResource{Namespace: "ns", Group: "group", Resource: "res"})
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		require.Equal(t, int64(100), files[0].minRV)
+		require.Equal(t, int64(200), files[0].maxRV)
+	})
+
+	t.Run("reads archived revisions", func(t *testing.T) {
+		backend, _ := setup(t, 0, 0)
+
+		rsp := backend.ReadResource(ctx, &resourcepb.ReadRequest{Key: key, ResourceVersion: 250})
+		require.Nil(t, rsp.Error)
+		require.Equal(t, int64(200), rsp.ResourceVersion)
+		require.Equal(t, "a-200", string(rsp.Value))
+
+		rsp = backend.ReadResource(ctx, &resourcepb.ReadRequest{Key: key})
+		require.Nil(t, rsp.Error)
+		require.Equal(t, int64(300), rsp.ResourceVersion)
+
+		rsp = backend.ReadResource(ctx, &resourcepb.ReadRequest{Key: key, ResourceVersion: 50})
+		require.NotNil(t, rsp.Error)
+		require.Equal(t, int32(http.StatusNotFound), rsp.Error.Code)
+
+		// the history of deleted resources is archived too
+		deleted := &resourcepb.ResourceKey{Namespace: "ns", Group: "group", Resource: "res", Name: "b"}
+		rsp = backend.ReadResource(ctx, &resourcepb.ReadRequest{Key: deleted, ResourceVersion: 115})
+		require.Nil(t, rsp.Error)
+		require.Equal(t, int64(110), rsp.ResourceVersion)
+		require.Equal(t, "b-110", string(rsp.Value))
+
+		rsp = backend.ReadResource(ctx, &resourcepb.ReadRequest{Key: deleted})
+		require.NotNil(t, rsp.Error)
+		require.Equal(t, int32(http.StatusNotFound), rsp.Error.Code)
+	})
+
+	t.Run("uses the resource versions of the backend for the cutoff", func(t *testing.T) {
+		hot := &fakeHistoryBackend{}
+		now := time.Now()
+		hot.add("a", now.Add(-48*time.Hour).UnixMicro(), resourcepb.WatchEvent_ADDED)
+		hot.add("a", now.Add(-36*time.Hour).UnixMicro(), resourcepb.WatchEvent_MODIFIED)
+		hot.add("a", now.Add(-12*time.Hour).UnixMicro(), resourcepb.WatchEvent_MODIFIED)
+		hot.add("a", now.Add(-6*time.Hour).UnixMicro(), resourcepb.WatchEvent_MODIFIED)
+
+		backend, err := NewArchiveBackend(ArchiveBackendOptions{
+			Backend: hot,
+			Bucket:  memblob.OpenBucket(nil),
+			MaxAge:  24 * time.Hour,
+		})
+		require.NoError(t, err)
+		require.NoError(t, backend.Archive(ctx))
+
+		// only the revisions older than a day are archived
+		require.Len(t, hot.history, 2)
+		require.Equal(t, now.Add(-12*time.Hour).UnixMicro(), hot.history[0].ResourceVersion)
+	})
+
+	t.Run("lists the history of both tiers", func(t *testing.T) {
+		backend, _ := setup(t, 0, 0)
+
+		req := &resourcepb.ListRequest{
+			Source:  resourcepb.ListRequest_HISTORY,
+			Options: &resourcepb.ListOptions{Key: key},
+		}
+		rvs, token := listHistory(t, backend, req, 2)
+		require.Equal(t, []int64{300, 200}, rvs)
+
+		req.NextPageToken = token
+		rvs, _ = listHistory(t, backend, req, 10)
+		require.Equal(t, []int64{100}, rvs)
+
+		rvs, _ = listHistory(t, backend, &resourcepb.ListRequest{
+			Source:          resourcepb.ListRequest_HISTORY,
+			Options:         &resourcepb.ListOptions{Key: key},
+			ResourceVersion: 150,
+			VersionMatchV2:  resourcepb.ResourceVersionMatchV2_NotOlderThan,
+		}, 10)
+		require.Equal(t, []int64{200, 300}, rvs)
+	})
+
+	t.Run("forwards the history quotas to the hot tier", func(t *testing.T) {
+		backend, hot := setup(t, 0, 0)
+
+		// the resource server sets the quotas on the backends supporting them
+		quotas, ok := resource.StorageBackend(backend).(resource.HistoryQuotaSupport)
+		require.True(t, ok)
+		quotas.SetHistoryQuotas(fixedHistoryQuota(5))
+		require.Equal(t, fixedHistoryQuota(5), hot.quotas)
+	})
+}
+
+func listHistory(t *testing.T, backend *ArchiveBackend, req *resourcepb.ListRequest, limit int) ([]int64, string) {
+	t.Helper()
+
+	var rvs []int64
+	var token string
+	_, err := backend.ListHistory(context.Background(), req, func(iter resource.ListIterator) error {
+		for len(rvs) < limit && iter.Next() {
+			if err := iter.Error(); err != nil {
+				return err
+			}
+			rvs = append(rvs, iter.ResourceVersion())
+			token = iter.ContinueToken()
+		}
+		return iter.Error()
+	})
+	require.NoError(t, err)
+	return rvs, token
+}
+
+// fakeHistoryBackend keeps the history of a single namespace and resource in memory
+type fakeHistoryBackend struct {
+	resource.StorageBackend
+
+	history []*HistoryRevision // sorted by resource version
+	quotas  resource.HistoryQuotas
+}
+
+func (f *fakeHistoryBackend) SetHistoryQuotas(quotas resource.HistoryQuotas) {
+	f.quotas = quotas
+}
+
+// fixedHistoryQuota is the history quota of every resource
+type fixedHistoryQuota int
+
+func (q fixedHistoryQuota) GetHistoryLimit(_ context.Context, _ resource.NamespacedResource) int {
+	return int(q)
+}
+
+func (f *fakeHistoryBackend) add(name string, rv int64, action resourcepb.WatchEvent_Type) {
+	f.history = append(f.history, &HistoryRevision{
+		GUID:            fmt.Sprintf("%s-%d", name, rv),
+		Key:             &resourcepb.ResourceKey{Namespace: "ns", Group: "group", Resource: "res", Name: name},
+		Action:          action,
+		ResourceVersion: rv,
+		Value:           []byte(fmt.Sprintf("%s-%d", name, rv)),
+	})
+	slices.SortFunc(f.history, func(a, b *HistoryRevision) int {
+		return int(a.ResourceVersion - b.ResourceVersion)
+	})
+}
+
+func (f *fakeHistoryBackend) resourceVersions() []int64 {
+	var rvs []int64
+	for _, rev := range f.history {
+		rvs = append(rvs, rev.ResourceVersion)
+	}
+	return rvs
+}
+
+func (f *fakeHistoryBackend) ReadResource(_ context.Context, req *resourcepb.ReadRequest) *resource.BackendReadResponse {
+	var latest *HistoryRevision
+	for _, rev := range f.history {
+		if rev.Key.Name == req.Key.Name && (req.ResourceVersion <= 0 || rev.ResourceVersion <= req.ResourceVersion) {
+			latest = rev
+		}
+	}
+	if latest == nil || latest.Action == resourcepb.WatchEvent_DELETED {
+		return &resource.BackendReadResponse{Error: resource.NewNotFoundError(req.Key)}
+	}
+	return &resource.BackendReadResponse{
+		Key:             latest.Key,
+		ResourceVersion: latest.ResourceVersion,
+		Value:           latest.Value,
+	}
+}
+
+func (f *fakeHistoryBackend) ListHistory(_ context.Context, req *resourcepb.ListRequest, cb func(resource.ListIterator) error) (int64, error) {
+	ascending := req.GetVersionMatchV2() == resourcepb.ResourceVersionMatchV2_NotOlderThan
+	var after int64
+	if req.NextPageToken != "" {
+		var err error
+		if after, err = strconv.ParseInt(req.NextPageToken, 10, 64); err != nil {
+			return 0, err
+		}
+	}
+
+	var items []*HistoryRevision
+	for _, rev := range f.history {
+		if rev.Key.Name != req.Options.Key.Name {
+			continue
+		}
+		if ascending && rev.ResourceVersion < req.ResourceVersion {
+			continue
+		}
+		if after > 0 && ((ascending && rev.ResourceVersion <= after) || (!ascending && rev.ResourceVersion >= after)) {
+			continue
+		}
+		items = append(items, rev)
+	}
+	if !ascending {
+		slices.Reverse(items)
+	}
+	return 0, cb(&fakeHistoryIterator{items: items, index: -1})
+}
+
+func (f *fakeHistoryBackend) GetResourceStats(_ context.Context, _ resource.NamespacedResource, _ int) ([]resource.ResourceStats, error) {
+	return []resource.ResourceStats{{
+		NamespacedResource: resource.NamespacedResource{Namespace: "ns", Group: "group", Resource: "res"},
+		Count:              int64(len(f.history)),
+	}}, nil
+}
+
+func (f *fakeHistoryBackend) ListArchivableHistory(_ context.Context, _ resource.NamespacedResource, cutoffRV int64, limit int) ([]*HistoryRevision, error) {
+	var revisions []*HistoryRevision
+	for i, rev := range f.history {
+		if len(revisions) == limit {
+			break
+		}
+		if rev.ResourceVersion >= cutoffRV || rev.Action == resourcepb.WatchEvent_DELETED {
+			continue
+		}
+		newer := false
+		for _, next := range f.history[i+1:] {
+			if next.Key.Name == rev.Key.Name {
+				newer = true
+				break
+			}
+		}
+		if newer {
+			revisions = append(revisions, rev)
+		}
+	}
+	return revisions, nil
+}
+
+func (f *fakeHistoryBackend) DeleteArchivedHistory(_ context.Context, _ resource.NamespacedResource, guids []string) (int64, error) {
+	before := len(f.history)
+	f.history = slices.DeleteFunc(f.history, func(rev *HistoryRevision) bool {
+		return slices.Contains(guids, rev.GUID)
+	})
+	return int64(before - len(f.history)), nil
+}
+
+func (f *fakeHistoryBackend) LatestDeletedRV(_ context.Context, key *resourcepb.ResourceKey) (int64, error) {
+	var rv int64
+	for _, rev := range f.history {
+		if rev.Key.Name == key.Name && rev.Action == resourcepb.WatchEvent_DELETED {
+			rv = rev.ResourceVersion
+		}
+	}
+	return rv, nil
+}
+
+func (f *fakeHistoryBackend) ResourceVersionAt(t time.Time) int64 {
+	return t.UnixMicro()
+}
+
+type fakeHistoryIterator struct {
+	items []*HistoryRevision
+	index int
+}
+
+func (i *fakeHistoryIterator) Next() bool {
+	i.index++
+	return i.index < len(i.items)
+}
+
+func (i *fakeHistoryIterator) Error() error { return nil }
+func (i *fakeHistoryIterator) ContinueToken() string {
+	return strconv.FormatInt(i.ResourceVersion(), 10)
+}
+func (i *fakeHistoryIterator) ResourceVersion() int64 { return i.items[i.index].ResourceVersion }
+func (i *fakeHistoryIterator) Namespace() string      { return i.items[i.index].Key.Namespace }
+func (i *fakeHistoryIterator) Name() string           { return i.items[i.index].Key.Name }
+func (i *fakeHistoryIterator) Folder() string         { return i.items[i.index].Folder }
+func (i *fakeHistoryIterator) Value() []byte          { return i.items[i.index].Value }
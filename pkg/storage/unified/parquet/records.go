@@ -0,0 +1,36 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// NewRecordReader reads the rows of any parquet file as arrow record batches of up to batchSize rows.
+// Only the named columns are read, or all of them when no columns are given.
+func NewRecordReader(ctx context.Context, r parquet.ReaderAtSeeker, columns []string, batchSize int64) (pqarrow.RecordReader, error) {
+	rdr, err := file.NewParquetReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var indices []int
+	schema := rdr.MetaData().Schema
+	for _, name := range columns {
+		index := schema.ColumnIndexByName(name)
+		if index < 0 {
+			return nil, fmt.Errorf("missing column: %s", name)
+		}
+		indices = append(indices, index)
+	}
+
+	fr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{BatchSize: batchSize}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, err
+	}
+	return fr.GetRecordReader(ctx, indices, nil)
+}
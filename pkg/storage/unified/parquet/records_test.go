@@ -0,0 +1,115 @@
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRecordReader(t *testing.T) {
+	contents := writeTestRecords(t, 5)
+
+	tests := []struct {
+		name      string
+		columns   []string
+		batchSize int64
+		fields    []string
+		batches   []int64
+		err       string
+	}{
+		{
+			name:      "all columns",
+			batchSize: 10,
+			fields:    []string{"id", "name"},
+			batches:   []int64{5},
+		},
+		{
+			name:      "projection",
+			columns:   []string{"name"},
+			batchSize: 10,
+			fields:    []string{"name"},
+			batches:   []int64{5},
+		},
+		{
+			name:      "batches",
+			batchSize: 2,
+			fields:    []string{"id", "name"},
+			batches:   []int64{2, 2, 1},
+		},
+		{
+			name:      "missing column",
+			columns:   []string{"other"},
+			batchSize: 10,
+			err:       "missing column: other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdr, err := NewRecordReader(context.Background(), bytes.NewReader(contents), tt.columns, tt.batchSize)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			defer rdr.Release()
+
+			var fields []string
+			for _, f := range rdr.Schema().Fields() {
+				fields = append(fields, f.Name)
+			}
+			require.Equal(t, tt.fields, fields)
+
+			var batches []int64
+			for rdr.Next() {
+				batches = append(batches, rdr.RecordBatch().NumRows())
+			}
+			if err := rdr.Err(); err != nil && !errors.Is(err, io.EOF) {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.batches, batches)
+		})
+	}
+
+	t.Run("invalid file", func(t *testing.T) {
+		_, err := NewRecordReader(context.Background(), bytes.NewReader([]byte("not parquet")), nil, 10)
+		require.Error(t, err)
+	})
+}
+
+func writeTestRecords(t *testing.T, rows int) []byte {
+	t.Helper()
+
+	mem := memory.DefaultAllocator
+	id := array.NewInt64Builder(mem)
+	defer id.Release()
+	name := array.NewStringBuilder(mem)
+	defer name.Release()
+	for i := 0; i < rows; i++ {
+		id.Append(int64(i))
+		name.Append(string(rune('a' + i)))
+	}
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+	}, nil)
+	rec := array.NewRecordBatch(schema, []arrow.Array{id.NewArray(), name.NewArray()}, int64(rows))
+	defer rec.Release()
+
+	var buf bytes.Buffer
+	w, err := pqarrow.NewFileWriter(schema, &buf, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps())
+	require.NoError(t, err)
+	require.NoError(t, w.Write(rec))
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
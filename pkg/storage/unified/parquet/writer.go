@@ -163,14 +163,6 @@ func (w *parquetWriter) Write(ctx context.Context, key *resourcepb.ResourceKey,
 	}
 	rv, _ := meta.GetResourceVersionInt64() // it can be empty
 
-	w.rv.Append(rv)
-	w.namespace.Append(key.Namespace)
-	w.group.Append(key.Group)
-	w.resource.Append(key.Resource)
-	w.name.Append(key.Name)
-	w.folder.Append(meta.GetFolder())
-	w.value.Append(string(value))
-
 	var action resourcepb.WatchEvent_Type
 	switch meta.GetGeneration() {
 	case 0, 1:
@@ -180,12 +172,10 @@ func (w *parquetWriter) Write(ctx context.Context, key *resourcepb.ResourceKey,
 	default:
 		action = resourcepb.WatchEvent_MODIFIED
 	}
-	w.action.Append(int8(action))
 
-	w.wrote = w.wrote + len(value)
-	if w.wrote > w.buffer {
-		w.logger.Info("buffer full", "buffer", w.wrote, "max", w.buffer)
-		return w.flush()
+	flushed, err := w.writeRevision(key, meta.GetFolder(), rv, action, value)
+	if err != nil || flushed {
+		return err
 	}
 
 	summary := w.summary[resource.NSGR(key)]
@@ -202,6 +192,26 @@ func (w *parquetWriter) Write(ctx context.Context, key *resourcepb.ResourceKey,
 	return nil
 }
 
+// writeRevision appends a revision with an explicit resource version and action, and flushes
+// the buffer when it is full
+func (w *parquetWriter) writeRevision(key *resourcepb.ResourceKey, folder string, rv int64, action resourcepb.WatchEvent_Type, value []byte) (bool, error) {
+	w.rv.Append(rv)
+	w.namespace.Append(key.Namespace)
+	w.group.Append(key.Group)
+	w.resource.Append(key.Resource)
+	w.name.Append(key.Name)
+	w.folder.Append(folder)
+	w.action.Append(int8(action))
+	w.value.Append(string(value))
+
+	w.wrote = w.wrote + len(value)
+	if w.wrote > w.buffer {
+		w.logger.Info("buffer full", "buffer", w.wrote, "max", w.buffer)
+		return true, w.flush()
+	}
+	return false, nil
+}
+
 func newSchema(metadata *arrow.Metadata) *arrow.Schema {
 	return arrow.NewSchema([]arrow.Field{
 		{Name: "resource_version", Type: &arrow.Int64Type{}, Nullable: false},
@@ -0,0 +1,217 @@
+package resource
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	kvpkg "github.com/grafana/grafana/pkg/storage/unified/resource/kv"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+const blobsSection = kvpkg.BlobsSection
+
+var (
+	_ BlobSupport  = &kvStorageBackend{}
+	_ BlobImporter = &kvStorageBackend{}
+)
+
+// BlobImporter saves a blob under the uid it already has, so the objects referencing it
+// can be copied from another backend
+type BlobImporter interface {
+	ImportResourceBlob(ctx context.Context, key *resourcepb.ResourceKey, uid string, contentType string, value []byte) error
+}
+
+// blobStore saves the resource blobs in the KV store, so the backends built on the KV store
+// support blobs without an external bucket.
+//
+// Keys:
+//
+//	<namespace>/<group>/<resource>/<name>/<uid>
+type blobStore struct {
+	kv KV
+}
+
+type storedBlob struct {
+	UID         string `json:"uid"`
+	Created     int64  `json:"created"` // unix nanoseconds
+	ContentType string `json:"contentType,omitempty"`
+	Hash        string `json:"hash"`
+	Value       []byte `json:"value"`
+}
+
+func newBlobStore(kv KV) *blobStore {
+	return &blobStore{kv: kv}
+}
+
+func blobKeyPrefix(key *resourcepb.ResourceKey) string {
+	return fmt.Sprintf("%s/%s/%s/%s/", key.Namespace, key.Group, key.Resource, key.Name)
+}
+
+func (s *blobStore) save(ctx context.Context, key *resourcepb.ResourceKey, blob storedBlob) error {
+	writer, err := s.kv.Save(ctx, blobsSection, blobKeyPrefix(key)+blob.UID)
+	if err != nil {
+		return fmt.Errorf("opening writer: %w", err)
+	}
+	if err := json.NewEncoder(writer).Encode(blob); err != nil {
+		_ = writer.Close()
+		return fmt.Errorf("encoding blob: %w", err)
+	}
+	return writer.Close()
+}
+
+// get returns the blob with the uid, or the most recent blob of the resource when the uid is empty
+func (s *blobStore) get(ctx context.Context, key *resourcepb.ResourceKey, uid string) (*storedBlob, error) {
+	if uid != "" {
+		return s.read(ctx, blobKeyPrefix(key)+uid)
+	}
+
+	var latest *storedBlob
+	prefix := blobKeyPrefix(key)
+	for k, err := range s.kv.Keys(ctx, blobsSection, ListOptions{StartKey: prefix, EndKey: PrefixRangeEnd(prefix)}) {
+		if err != nil {
+			return nil, err
+		}
+		blob, err := s.read(ctx, k)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if latest == nil || blob.Created > latest.Created {
+			latest = blob
+		}
+	}
+	if latest == nil {
+		return nil, ErrNotFound
+	}
+	return latest, nil
+}
+
+func (s *blobStore) read(ctx context.Context, key string) (*storedBlob, error) {
+	reader, err := s.kv.Get(ctx, blobsSection, key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := readAndClose(reader)
+	if err != nil {
+		return nil, fmt.Errorf("reading blob: %w", err)
+	}
+	blob := &storedBlob{}
+	if err := json.Unmarshal(data, blob); err != nil {
+		return nil, fmt.Errorf("unmarshaling blob: %w", err)
+	}
+	return blob, nil
+}
+
+// SupportsSignedURLs implements BlobSupport.
+func (k *kvStorageBackend) SupportsSignedURLs() bool {
+	return false
+}
+
+// PutResourceBlob implements BlobSupport.
+func (k *kvStorageBackend) PutResourceBlob(ctx context.Context, req *resourcepb.PutBlobRequest) (*resourcepb.PutBlobResponse, error) {
+	if req.Method == resourcepb.PutBlobRequest_HTTP {
+		return &resourcepb.PutBlobResponse{
+			Error: NewBadRequestError("signed url upload not supported"),
+		}, nil
+	}
+	if err := verifyRequestKey(req.Resource); err != nil {
+		return &resourcepb.PutBlobResponse{Error: err}, nil
+	}
+
+	hasher := md5.New() // same as s3
+	_, err := hasher.Write(req.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &utils.BlobInfo{
+		UID:  uuid.New().String(),
+		Size: int64(len(req.Value)),
+		Hash: hex.EncodeToString(hasher.Sum(nil)),
+	}
+	info.SetContentType(req.ContentType)
+
+	if info.Size < 1 {
+		return &resourcepb.PutBlobResponse{
+			Error: NewBadRequestError("empty content"),
+		}, nil
+	}
+
+	err = k.blobs.save(ctx, req.Resource, storedBlob{
+		UID:         info.UID,
+		Created:     time.Now().UnixNano(),
+		ContentType: req.ContentType,
+		Hash:        info.Hash,
+		Value:       req.Value,
+	})
+	if err != nil {
+		return &resourcepb.PutBlobResponse{
+			Error: AsErrorResult(err),
+		}, nil
+	}
+	return &resourcepb.PutBlobResponse{
+		Uid:      info.UID,
+		Size:     info.Size,
+		MimeType: info.MimeType,
+		Charset:  info.Charset,
+		Hash:     info.Hash,
+	}, nil
+}
+
+// GetResourceBlob implements BlobSupport.
+func (k *kvStorageBackend) GetResourceBlob(ctx context.Context, key *resourcepb.ResourceKey, info *utils.BlobInfo, mustProxy bool) (*resourcepb.GetBlobResponse, error) {
+	if info == nil {
+		return &resourcepb.GetBlobResponse{
+			Error: NewBadRequestError("missing blob info"),
+		}, nil
+	}
+
+	blob, err := k.blobs.get(ctx, key, info.UID)
+	if errors.Is(err, ErrNotFound) {
+		return &resourcepb.GetBlobResponse{
+			Error: &resourcepb.ErrorResult{Code: http.StatusNotFound},
+		}, nil
+	}
+	if err != nil {
+		return &resourcepb.GetBlobResponse{
+			Error: AsErrorResult(err),
+		}, nil
+	}
+	return &resourcepb.GetBlobResponse{
+		ContentType: blob.ContentType,
+		Value:       blob.Value,
+	}, nil
+}
+
+// ImportResourceBlob implements BlobImporter.
+func (k *kvStorageBackend) ImportResourceBlob(ctx context.Context, key *resourcepb.ResourceKey, uid string, contentType string, value []byte) error {
+	if err := verifyRequestKey(key); err != nil {
+		return errors.New(err.Message)
+	}
+	if uid == "" {
+		return errors.New("missing blob uid")
+	}
+
+	hasher := md5.New() // same as s3
+	if _, err := hasher.Write(value); err != nil {
+		return err
+	}
+	return k.blobs.save(ctx, key, storedBlob{
+		UID:         uid,
+		Created:     time.Now().UnixNano(),
+		ContentType: contentType,
+		Hash:        hex.EncodeToString(hasher.Sum(nil)),
+		Value:       value,
+	})
+}
@@ -0,0 +1,71 @@
+package resource
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestKVBackendBlobs(t *testing.T) {
+	ctx := t.Context()
+	backend := setupTestStorageBackend(t)
+	key := &resourcepb.ResourceKey{Namespace: "default", Group: "playlist.grafana.app", Resource: "playlists", Name: "fdgsv37qslr0ga"}
+
+	put := func(value string) *resourcepb.PutBlobResponse {
+		rsp, err := backend.PutResourceBlob(ctx, &resourcepb.PutBlobRequest{
+			Resource:    key,
+			Method:      resourcepb.PutBlobRequest_GRPC,
+			ContentType: "text/plain",
+			Value:       []byte(value),
+		})
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		return rsp
+	}
+
+	first := put("hello")
+	require.Equal(t, "5d41402abc4b2a76b9719d911017c592", first.Hash)
+	require.Equal(t, int64(5), first.Size)
+	second := put("hello world")
+	require.NotEqual(t, first.Uid, second.Uid)
+
+	rsp, err := backend.GetResourceBlob(ctx, key, &utils.BlobInfo{UID: first.Uid}, false)
+	require.NoError(t, err)
+	require.Nil(t, rsp.Error)
+	require.Equal(t, "hello", string(rsp.Value))
+	require.Equal(t, "text/plain", rsp.ContentType)
+
+	rsp, err = backend.GetResourceBlob(ctx, key, &utils.BlobInfo{}, false)
+	require.NoError(t, err)
+	require.Nil(t, rsp.Error)
+	require.Equal(t, "hello world", string(rsp.Value))
+
+	rsp, err = backend.GetResourceBlob(ctx, key, &utils.BlobInfo{UID: "missing"}, false)
+	require.NoError(t, err)
+	require.Equal(t, int32(http.StatusNotFound), rsp.Error.Code)
+
+	prsp, err := backend.PutResourceBlob(ctx, &resourcepb.PutBlobRequest{Resource: key, Method: resourcepb.PutBlobRequest_HTTP})
+	require.NoError(t, err)
+	require.NotNil(t, prsp.Error)
+}
+
+func TestKVBackendImportBlob(t *testing.T) {
+	ctx := t.Context()
+	backend := setupTestStorageBackend(t)
+	key := &resourcepb.ResourceKey{Namespace: "default", Group: "playlist.grafana.app", Resource: "playlists", Name: "fdgsv37qslr0ga"}
+
+	require.NoError(t, backend.ImportResourceBlob(ctx, key, "imported-uid", "text/plain", []byte("hello")))
+
+	rsp, err := backend.GetResourceBlob(ctx, key, &utils.BlobInfo{UID: "imported-uid"}, false)
+	require.NoError(t, err)
+	require.Nil(t, rsp.Error)
+	require.Equal(t, "hello", string(rsp.Value))
+	require.Equal(t, "text/plain", rsp.ContentType)
+
+	require.Error(t, backend.ImportResourceBlob(ctx, key, "", "text/plain", []byte("hello")))
+	require.Error(t, backend.ImportResourceBlob(ctx, &resourcepb.ResourceKey{Namespace: "default", Name: "missing-group"}, "uid", "text/plain", []byte("hello")))
+}
@@ -2,6 +2,7 @@ package resource
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -30,6 +31,15 @@ type SearchableDocumentFields interface {
 // Some kinds will require special processing for their namespace
 type NamespacedDocumentSupplier = func(ctx context.Context, namespace string, blob BlobSupport) (DocumentBuilder, error)
 
+// Builders that need the folder tree of the namespace (eg to index the folder path).
+// The parents are read from the folder resources when the builder is created for a namespace.
+type FolderTreeDocumentBuilder interface {
+	DocumentBuilder
+
+	// Returns a builder using the parent of each folder (by name), folders at the root are not included
+	WithFolderParents(parents map[string]string) DocumentBuilder
+}
+
 // Register how documents can be built for a resource
 type DocumentBuilderInfo struct {
 	// The target resource (empty will be used to match anything)
@@ -127,6 +137,31 @@ type IndexableDocument struct {
 
 	// When the manager knows about file paths
 	Source *utils.SourceProperties `json:"source,omitempty"`
+
+	// Nested panel documents (for dashboards)
+	Panels []IndexablePanel `json:"panels,omitempty"`
+
+	// internal stored field with the JSON encoded panels ( don't set this directly )
+	PanelsInfo string `json:"panels_info,omitempty"`
+}
+
+// IndexablePanel is indexed as a nested document of its dashboard, so we can find
+// the panels using a plugin, a data source or matching a query
+type IndexablePanel struct {
+	// The panel id (unique within the dashboard)
+	ID int64 `json:"id"`
+
+	// The panel title
+	Title string `json:"title,omitempty"`
+
+	// The panel plugin type
+	Type string `json:"type,omitempty"`
+
+	// The data sources (uids) queried by the panel
+	DataSources []string `json:"datasource,omitempty"`
+
+	// The raw query text of each target
+	Queries []string `json:"query,omitempty"`
 }
 
 func (m *IndexableDocument) UpdateCopyFields() *IndexableDocument {
@@ -141,6 +176,13 @@ func (m *IndexableDocument) UpdateCopyFields() *IndexableDocument {
 		// Group and Version are ignored for now. This could be revisited.
 		m.Reference[ref.Kind] = append(m.Reference[ref.Kind], ref.Name)
 	}
+
+	m.PanelsInfo = ""
+	if len(m.Panels) > 0 {
+		if info, err := json.Marshal(m.Panels); err == nil {
+			m.PanelsInfo = string(info)
+		}
+	}
 	return m
 }
 
@@ -342,7 +384,8 @@ const (
 	SEARCH_FIELD_NAME               = "name"
 	SEARCH_FIELD_RV                 = "rv"
 	SEARCH_FIELD_TITLE              = "title"
-	SEARCH_FIELD_TITLE_PHRASE       = "title_phrase" // filtering/sorting on title by full phrase
+	SEARCH_FIELD_TITLE_PHRASE       = "title_phrase"  // filtering/sorting on title by full phrase
+	SEARCH_FIELD_TITLE_SUGGEST      = "title_suggest" // words of the title, used for "did you mean" suggestions
 	SEARCH_FIELD_DESCRIPTION        = "description"
 	SEARCH_FIELD_TAGS               = "tags"
 	SEARCH_FIELD_LABELS             = "labels" // All labels, not a specific one
@@ -360,7 +403,14 @@ const (
 	SEARCH_FIELD_SOURCE_TIME        = "source.timestampMillis"
 	SEARCH_FIELD_SCORE              = "_score"            // the match score
 	SEARCH_FIELD_EXPLAIN            = "_explain"          // score explanation as JSON object
+	SEARCH_FIELD_HIGHLIGHT          = "_highlight"        // matched fragments by field as JSON object
+	SEARCH_FIELD_SUGGESTIONS        = "_suggestions"      // facet with the "did you mean" queries
 	SEARCH_SELECTABLE_FIELDS_PREFIX = "selectableFields." // Prefix for searching selectable fields.
+	SEARCH_FIELD_PANEL_TITLE        = "panels.title"
+	SEARCH_FIELD_PANEL_TYPE         = "panels.type"
+	SEARCH_FIELD_PANEL_DATASOURCE   = "panels.datasource"
+	SEARCH_FIELD_PANEL_QUERY        = "panels.query"
+	SEARCH_FIELD_PANELS_INFO        = "panels_info" // JSON encoded panels, only returned from search
 )
 
 var standardSearchFieldsInit sync.Once
@@ -449,6 +499,11 @@ func StandardSearchFields() SearchableDocumentFields {
 				Type:        resourcepb.ResourceTableColumnDefinition_OBJECT,
 				Description: "Explain why this result matches (depends on the engine)",
 			},
+			{
+				Name:        SEARCH_FIELD_HIGHLIGHT,
+				Type:        resourcepb.ResourceTableColumnDefinition_OBJECT,
+				Description: "The fragments of the text fields matching the query, by field",
+			},
 			{
 				Name:        SEARCH_FIELD_SCORE,
 				Type:        resourcepb.ResourceTableColumnDefinition_DOUBLE,
@@ -487,6 +542,11 @@ func StandardSearchFields() SearchableDocumentFields {
 				IsArray:     true,
 				Description: "Owner references in format {Group}/{Kind}/{Name}",
 			},
+			{
+				Name:        SEARCH_FIELD_PANELS_INFO,
+				Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+				Description: "The nested panels, as a JSON array",
+			},
 		})
 
 		if err != nil {
@@ -121,8 +121,8 @@ type KV interface {
 
 var _ KV = &badgerKV{}
 
-// Reference implementation of the KV interface using BadgerDB
-// This is only used for testing purposes, and will not work HA
+// Implementation of the KV interface using BadgerDB
+// It is used for tests and by single node deployments with embedded storage, it will not work HA
 type badgerKV struct {
 	db *badger.DB
 }
@@ -133,6 +133,26 @@ func NewBadgerKV(db *badger.DB) *badgerKV {
 	}
 }
 
+// CollectGarbage rewrites the value log files that hold mostly stale data.
+// Badger never reclaims this space by itself, so long-running databases should call it regularly.
+func (k *badgerKV) CollectGarbage(ctx context.Context) error {
+	for ctx.Err() == nil {
+		err := k.db.RunValueLogGC(0.5)
+		if errors.Is(err, badger.ErrNoRewrite) || errors.Is(err, badger.ErrRejected) || errors.Is(err, badger.ErrGCInMemoryMode) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// Close closes the database
+func (k *badgerKV) Close() error {
+	return k.db.Close()
+}
+
 func (k *badgerKV) Get(ctx context.Context, section string, key string) (io.ReadCloser, error) {
 	if k.db.IsClosed() {
 		return nil, fmt.Errorf("database is closed")
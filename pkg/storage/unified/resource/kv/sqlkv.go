@@ -21,6 +21,8 @@ const (
 	EventsSection         = "unified/events"
 	LastImportTimeSection = "unified/lastimport"
 	PendingDeleteSection  = "unified/pendingdelete"
+	WebhooksSection       = "unified/webhooks"
+	BlobsSection          = "unified/blobs"
 )
 
 var _ KV = &SqlKV{}
@@ -96,6 +98,10 @@ func (k *SqlKV) getQueryBuilder(section string) (*queryBuilder, error) {
 		tableName = "resource_history"
 	case PendingDeleteSection:
 		tableName = "pending_tenant_deletions"
+	case WebhooksSection:
+		tableName = "resource_webhooks"
+	case BlobsSection:
+		tableName = "resource_kv_blobs"
 	default:
 		return nil, fmt.Errorf("invalid section: %s", section)
 	}
@@ -374,7 +380,7 @@ func (k *SqlKV) Save(ctx context.Context, section string, key string) (io.WriteC
 	if key == "" {
 		return nil, fmt.Errorf("key is required")
 	}
-	if section != DataSection && section != EventsSection && section != PendingDeleteSection && section != LastImportTimeSection {
+	if section != DataSection && section != EventsSection && section != PendingDeleteSection && section != LastImportTimeSection && section != WebhooksSection && section != BlobsSection {
 		return nil, fmt.Errorf("invalid section: %s", section)
 	}
 
@@ -428,8 +434,8 @@ func (w *sqlWriteCloser) Close() error {
 	keyPath := getKeyPath(w.section, w.key)
 
 	// do regular kv save: simple key_path + value insert with conflict check.
-	// can only do this on resource_events and pending_tenant_deletions for now, until we drop the columns in resource_history
-	if w.section == EventsSection || w.section == PendingDeleteSection {
+	// can only do this on resource_events, pending_tenant_deletions, resource_webhooks and resource_kv_blobs for now, until we drop the columns in resource_history
+	if w.section == EventsSection || w.section == PendingDeleteSection || w.section == WebhooksSection || w.section == BlobsSection {
 		query, args := qb.buildUpsertQuery(keyPath, value)
 		_, err := w.kv.conn(w.ctx).ExecContext(w.ctx, query, args...)
 		if err != nil {
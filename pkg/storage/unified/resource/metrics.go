@@ -12,6 +12,7 @@ type StorageMetrics struct {
 	WatchEventLatency      *prometheus.HistogramVec
 	PollerLatency          prometheus.Histogram
 	ListWithFieldSelectors *prometheus.CounterVec
+	QuotaWarnings          *prometheus.CounterVec
 	Broadcaster            *BroadcasterMetrics
 }
 
@@ -39,6 +40,10 @@ func ProvideStorageMetrics(reg prometheus.Registerer) *StorageMetrics {
 			Name: "storage_server_field_selector_search_count",
 			Help: "number of times List was served by field selector search",
 		}, []string{"resource", "served_by"}),
+		QuotaWarnings: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "storage_server_quota_warnings_total",
+			Help: "number of creates past the soft limit (warning) or rejected by the limit (exceeded) of their resource",
+		}, []string{"resource", "state"}),
 		Broadcaster: newBroadcasterMetrics(reg),
 	}
 }
@@ -0,0 +1,480 @@
+package resource
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// number of items read from the backend for each list call
+const namespaceListPageSize = 500
+
+// NamespaceExportOptions selects what is exported by ExportNamespace
+type NamespaceExportOptions struct {
+	Namespace string
+
+	// Export the namespace as it was at this resource version.
+	// When zero, Timestamp is used, and when both are empty the latest state is exported
+	ResourceVersion int64
+
+	// Export the namespace as it was at this time
+	Timestamp time.Time
+
+	// The group/resources to export. When empty, all the resources that currently hold
+	// objects in the namespace are exported
+	Collections []*resourcepb.ResourceKey
+}
+
+// ExportNamespace writes every object of the namespace, as it was at the requested resource version,
+// to the bulk writer. The exported values hold the resource version they were read at.
+// The writer is closed when the export completes.
+func ExportNamespace(ctx context.Context, backend StorageBackend, opts NamespaceExportOptions, writer BulkResourceWriter) (*resourcepb.BulkResponse, error) {
+	if opts.Namespace == "" {
+		_ = writer.Close()
+		return nil, fmt.Errorf("missing namespace")
+	}
+
+	collections, err := namespaceCollections(ctx, backend, opts.Namespace, opts.Collections)
+	if err != nil {
+		_ = writer.Close()
+		return nil, err
+	}
+
+	rv := opts.ResourceVersion
+	if rv == 0 && !opts.Timestamp.IsZero() && len(collections) > 0 {
+		rv, err = ResourceVersionAtTime(ctx, backend, collections[0], opts.Timestamp)
+		if err != nil {
+			_ = writer.Close()
+			return nil, fmt.Errorf("reading the resource version at %s: %w", opts.Timestamp.Format(time.RFC3339), err)
+		}
+	}
+
+	for _, key := range collections {
+		// Pin the remaining collections to the resource version of the first list,
+		// so the export is consistent when the latest state is requested
+		rv, err = listNamespaceCollection(ctx, backend, key, rv, func(iter ListIterator) error {
+			value, err := withResourceVersion(iter.Value(), iter.ResourceVersion())
+			if err != nil {
+				return fmt.Errorf("reading %s/%s/%s: %w", key.Group, key.Resource, iter.Name(), err)
+			}
+			return writer.Write(ctx, &resourcepb.ResourceKey{
+				Namespace: key.Namespace,
+				Group:     key.Group,
+				Resource:  key.Resource,
+				Name:      iter.Name(),
+			}, value)
+		})
+		if err != nil {
+			_ = writer.Close()
+			return nil, fmt.Errorf("exporting %s/%s: %w", key.Group, key.Resource, err)
+		}
+	}
+	return writer.CloseWithResults()
+}
+
+// ResourceVersionAtTime returns the resource version that includes every write made before the given time.
+// The resource versions are snowflake IDs in the KV backend and microsecond timestamps in the SQL backend
+// (see resourceVersionTime), the format is the one of the current resource version of the collection.
+func ResourceVersionAtTime(ctx context.Context, backend StorageBackend, key *resourcepb.ResourceKey, t time.Time) (int64, error) {
+	current, err := backend.ListIterator(ctx, &resourcepb.ListRequest{
+		Limit: 1,
+		Options: &resourcepb.ListOptions{
+			Key: &resourcepb.ResourceKey{Namespace: key.Namespace, Group: key.Group, Resource: key.Resource},
+		},
+	}, func(ListIterator) error { return nil })
+	if err != nil {
+		return 0, err
+	}
+	return resourceVersionAtTime(current, t), nil
+}
+
+func resourceVersionAtTime(current int64, t time.Time) int64 {
+	if isSnowflake(current) {
+		return snowflakeFromTime(t)
+	}
+	return t.UnixMicro()
+}
+
+// NamespaceRestoreOptions controls RestoreNamespace
+type NamespaceRestoreOptions struct {
+	Namespace string
+
+	// The group/resources to restore. When empty, the group/resources found in the snapshot are restored.
+	// Objects in a group/resource that is not restored are left untouched, so a group/resource that was
+	// empty when the snapshot was taken must be listed explicitly to remove the objects created since.
+	Collections []*resourcepb.ResourceKey
+
+	// Only report the changes, nothing is written
+	DryRun bool
+}
+
+// NamespaceRestoreAction is the write needed to bring an object back to its state in the snapshot
+type NamespaceRestoreAction string
+
+const (
+	NamespaceRestoreCreate NamespaceRestoreAction = "create"
+	NamespaceRestoreUpdate NamespaceRestoreAction = "update"
+	NamespaceRestoreDelete NamespaceRestoreAction = "delete"
+)
+
+// NamespaceRestoreChange is a single write of the restore
+type NamespaceRestoreChange struct {
+	Action   NamespaceRestoreAction `json:"action"`
+	Group    string                 `json:"group"`
+	Resource string                 `json:"resource"`
+	Name     string                 `json:"name"`
+
+	// The resource version of the current object (empty for creates)
+	CurrentRV int64 `json:"currentResourceVersion,omitempty"`
+	// The resource version of the object in the snapshot (empty for deletes)
+	SnapshotRV int64 `json:"snapshotResourceVersion,omitempty"`
+	// The resource version written by the restore
+	ResourceVersion int64 `json:"resourceVersion,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// NamespaceRestoreReport lists the changes made (or that would be made with a dry run) by RestoreNamespace
+type NamespaceRestoreReport struct {
+	Namespace string `json:"namespace"`
+	DryRun    bool   `json:"dryRun"`
+
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Deleted   int `json:"deleted"`
+	Unchanged int `json:"unchanged"`
+	Failed    int `json:"failed"`
+
+	Changes []NamespaceRestoreChange `json:"changes"`
+}
+
+type restoreSnapshotItem struct {
+	value []byte
+	rv    int64
+}
+
+type restoreWrite struct {
+	change NamespaceRestoreChange
+	value  []byte
+}
+
+// RestoreNamespace compares the current state of the namespace with a snapshot written by ExportNamespace,
+// and replays the creates, updates and deletes needed to match the snapshot through the resource server.
+// The writes are checked and authorized like any other write, so ctx must hold the identity making the restore.
+// A failed write is recorded in the report and does not stop the restore.
+func RestoreNamespace(ctx context.Context, backend StorageBackend, srv resourcepb.ResourceStoreServer, opts NamespaceRestoreOptions, snapshot BulkRequestIterator) (*NamespaceRestoreReport, error) {
+	if opts.Namespace == "" {
+		return nil, fmt.Errorf("missing namespace")
+	}
+
+	// Read the snapshot, grouped by group/resource
+	items := map[NamespacedResource]map[string]restoreSnapshotItem{}
+	for snapshot.Next() {
+		if snapshot.RollbackRequested() {
+			return nil, fmt.Errorf("reading snapshot failed")
+		}
+		req := snapshot.Request()
+		if req.Key == nil {
+			return nil, fmt.Errorf("snapshot item is missing a key")
+		}
+		if req.Key.Namespace != opts.Namespace {
+			return nil, fmt.Errorf("snapshot item %s/%s/%s is not in namespace %s", req.Key.Group, req.Key.Resource, req.Key.Name, opts.Namespace)
+		}
+		if req.Action == resourcepb.BulkRequest_DELETED {
+			continue
+		}
+		nsr := NamespacedResource{Namespace: req.Key.Namespace, Group: req.Key.Group, Resource: req.Key.Resource}
+		if items[nsr] == nil {
+			items[nsr] = map[string]restoreSnapshotItem{}
+		}
+		rv, err := resourceVersionFromValue(req.Value)
+		if err != nil {
+			return nil, fmt.Errorf("reading snapshot item %s/%s/%s: %w", req.Key.Group, req.Key.Resource, req.Key.Name, err)
+		}
+		items[nsr][req.Key.Name] = restoreSnapshotItem{value: req.Value, rv: rv}
+	}
+
+	collections := make([]*resourcepb.ResourceKey, 0, len(items))
+	if len(opts.Collections) > 0 {
+		for _, c := range opts.Collections {
+			collections = append(collections, &resourcepb.ResourceKey{Namespace: opts.Namespace, Group: c.Group, Resource: c.Resource})
+		}
+	} else {
+		for nsr := range items {
+			collections = append(collections, &resourcepb.ResourceKey{Namespace: nsr.Namespace, Group: nsr.Group, Resource: nsr.Resource})
+		}
+	}
+	sortCollections(collections)
+
+	var writes, deletes []restoreWrite
+	unchanged := 0
+	for _, key := range collections {
+		wanted := items[NamespacedResource{Namespace: key.Namespace, Group: key.Group, Resource: key.Resource}]
+		seen := map[string]bool{}
+		_, err := listNamespaceCollection(ctx, backend, key, 0, func(iter ListIterator) error {
+			name := iter.Name()
+			seen[name] = true
+			change := NamespaceRestoreChange{Group: key.Group, Resource: key.Resource, Name: name, CurrentRV: iter.ResourceVersion()}
+			item, ok := wanted[name]
+			if !ok {
+				change.Action = NamespaceRestoreDelete
+				deletes = append(deletes, restoreWrite{change: change})
+				return nil
+			}
+			value, changed, err := restoreValue(item.value, iter.Value())
+			if err != nil {
+				return fmt.Errorf("comparing %s: %w", name, err)
+			}
+			if !changed {
+				unchanged++
+				return nil
+			}
+			change.Action = NamespaceRestoreUpdate
+			change.SnapshotRV = item.rv
+			writes = append(writes, restoreWrite{change: change, value: value})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing %s/%s: %w", key.Group, key.Resource, err)
+		}
+
+		names := make([]string, 0, len(wanted))
+		for name := range wanted {
+			if !seen[name] {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			value, _, err := restoreValue(wanted[name].value, nil)
+			if err != nil {
+				return nil, fmt.Errorf("reading snapshot item %s/%s/%s: %w", key.Group, key.Resource, name, err)
+			}
+			writes = append(writes, restoreWrite{
+				change: NamespaceRestoreChange{Action: NamespaceRestoreCreate, Group: key.Group, Resource: key.Resource, Name: name, SnapshotRV: wanted[name].rv},
+				value:  value,
+			})
+		}
+	}
+	// The deletes run last, so the objects restored into a folder created since the snapshot
+	// are moved out of it before the folder is removed
+	writes = append(writes, deletes...)
+
+	report := &NamespaceRestoreReport{
+		Namespace: opts.Namespace,
+		DryRun:    opts.DryRun,
+		Unchanged: unchanged,
+		Changes:   make([]NamespaceRestoreChange, 0, len(writes)),
+	}
+	for _, w := range writes {
+		change := w.change
+		switch change.Action {
+		case NamespaceRestoreCreate:
+			report.Created++
+		case NamespaceRestoreUpdate:
+			report.Updated++
+		case NamespaceRestoreDelete:
+			report.Deleted++
+		}
+		if !opts.DryRun {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			rv, errResult, err := applyRestoreChange(ctx, srv, opts.Namespace, change, w.value)
+			if err == nil && errResult != nil {
+				err = fmt.Errorf("%s (%d)", errResult.Message, errResult.Code)
+			}
+			if err != nil {
+				change.Error = err.Error()
+				report.Failed++
+			} else {
+				change.ResourceVersion = rv
+			}
+		}
+		report.Changes = append(report.Changes, change)
+	}
+	return report, nil
+}
+
+func applyRestoreChange(ctx context.Context, srv resourcepb.ResourceStoreServer, namespace string, change NamespaceRestoreChange, value []byte) (int64, *resourcepb.ErrorResult, error) {
+	key := &resourcepb.ResourceKey{Namespace: namespace, Group: change.Group, Resource: change.Resource, Name: change.Name}
+	switch change.Action {
+	case NamespaceRestoreCreate:
+		rsp, err := srv.Create(ctx, &resourcepb.CreateRequest{Key: key, Value: value})
+		if err != nil {
+			return 0, nil, err
+		}
+		return rsp.ResourceVersion, rsp.Error, nil
+	case NamespaceRestoreUpdate:
+		rsp, err := srv.Update(ctx, &resourcepb.UpdateRequest{Key: key, ResourceVersion: change.CurrentRV, Value: value})
+		if err != nil {
+			return 0, nil, err
+		}
+		return rsp.ResourceVersion, rsp.Error, nil
+	case NamespaceRestoreDelete:
+		rsp, err := srv.Delete(ctx, &resourcepb.DeleteRequest{Key: key, ResourceVersion: change.CurrentRV})
+		if err != nil {
+			return 0, nil, err
+		}
+		return rsp.ResourceVersion, rsp.Error, nil
+	}
+	return 0, nil, fmt.Errorf("unknown restore action: %s", change.Action)
+}
+
+// namespaceCollections returns the requested group/resources, or all the ones with objects in the namespace
+func namespaceCollections(ctx context.Context, backend StorageBackend, namespace string, requested []*resourcepb.ResourceKey) ([]*resourcepb.ResourceKey, error) {
+	collections := make([]*resourcepb.ResourceKey, 0, len(requested))
+	for _, c := range requested {
+		collections = append(collections, &resourcepb.ResourceKey{Namespace: namespace, Group: c.Group, Resource: c.Resource})
+	}
+	if len(collections) == 0 {
+		stats, err := backend.GetResourceStats(ctx, NamespacedResource{Namespace: namespace}, 0)
+		if err != nil {
+			return nil, fmt.Errorf("listing resources in namespace: %w", err)
+		}
+		for _, s := range stats {
+			if s.Namespace != namespace {
+				continue
+			}
+			collections = append(collections, &resourcepb.ResourceKey{Namespace: namespace, Group: s.Group, Resource: s.Resource})
+		}
+	}
+	sortCollections(collections)
+	return collections, nil
+}
+
+func sortCollections(collections []*resourcepb.ResourceKey) {
+	slices.SortFunc(collections, func(a, b *resourcepb.ResourceKey) int {
+		return cmp.Or(cmp.Compare(a.Group, b.Group), cmp.Compare(a.Resource, b.Resource))
+	})
+}
+
+// listNamespaceCollection calls fn for every object of the collection at the resource version (zero for the latest),
+// and returns the resource version the objects were listed at
+func listNamespaceCollection(ctx context.Context, backend StorageBackend, key *resourcepb.ResourceKey, rv int64, fn func(ListIterator) error) (int64, error) {
+	req := &resourcepb.ListRequest{
+		ResourceVersion: rv,
+		Limit:           namespaceListPageSize,
+		Options:         &resourcepb.ListOptions{Key: key},
+	}
+	if rv > 0 {
+		req.VersionMatchV2 = resourcepb.ResourceVersionMatchV2_Exact
+	}
+
+	for {
+		var next string
+		listRV, err := backend.ListIterator(ctx, req, func(iter ListIterator) error {
+			count := 0
+			var lastToken string
+			for iter.Next() {
+				if err := iter.Error(); err != nil {
+					return err
+				}
+				// The extra item confirms there is another page
+				if count >= int(req.Limit) {
+					next = lastToken
+					break
+				}
+				if err := fn(iter); err != nil {
+					return err
+				}
+				count++
+				lastToken = iter.ContinueToken()
+			}
+			return iter.Error()
+		})
+		if err != nil {
+			return 0, err
+		}
+		if rv == 0 {
+			rv = listRV
+		}
+		if next == "" {
+			return rv, nil
+		}
+		req.NextPageToken = next
+	}
+}
+
+// restoreValue returns the value to write to bring the current object back to the snapshot,
+// and whether it differs from the current object.
+// Fields that are set by the storage on every write are not compared.
+func restoreValue(snapshot, current []byte) ([]byte, bool, error) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(snapshot); err != nil {
+		return nil, false, err
+	}
+	obj.SetResourceVersion("")
+
+	if current != nil {
+		cur := &unstructured.Unstructured{}
+		if err := cur.UnmarshalJSON(current); err != nil {
+			return nil, false, err
+		}
+		// keep the identity of the current object
+		obj.SetUID(cur.GetUID())
+		obj.SetCreationTimestamp(cur.GetCreationTimestamp())
+		obj.SetGeneration(cur.GetGeneration())
+
+		a, err := restoreComparable(obj)
+		if err != nil {
+			return nil, false, err
+		}
+		b, err := restoreComparable(cur)
+		if err != nil {
+			return nil, false, err
+		}
+		if bytes.Equal(a, b) {
+			return nil, false, nil
+		}
+		obj.SetGeneration(cur.GetGeneration() + 1)
+	} else {
+		obj.SetGeneration(1)
+	}
+
+	value, err := obj.MarshalJSON()
+	return value, true, err
+}
+
+func restoreComparable(obj *unstructured.Unstructured) ([]byte, error) {
+	c := obj.DeepCopy()
+	c.SetResourceVersion("")
+	c.SetGeneration(0)
+	c.SetManagedFields(nil)
+	annotations := c.GetAnnotations()
+	delete(annotations, utils.AnnoKeyUpdatedTimestamp)
+	delete(annotations, utils.AnnoKeyUpdatedBy)
+	c.SetAnnotations(annotations)
+	// json.Marshal sorts the map keys
+	return json.Marshal(c.Object)
+}
+
+func withResourceVersion(value []byte, rv int64) ([]byte, error) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(value); err != nil {
+		return nil, err
+	}
+	obj.SetResourceVersion(strconv.FormatInt(rv, 10))
+	return obj.MarshalJSON()
+}
+
+func resourceVersionFromValue(value []byte) (int64, error) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(value); err != nil {
+		return 0, err
+	}
+	if obj.GetResourceVersion() == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(obj.GetResourceVersion(), 10, 64)
+}
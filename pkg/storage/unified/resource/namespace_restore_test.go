@@ -0,0 +1,195 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	authlib "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+type memoryBulkWriter struct {
+	requests []*resourcepb.BulkRequest
+	closed   bool
+}
+
+func (w *memoryBulkWriter) Write(_ context.Context, key *resourcepb.ResourceKey, value []byte) error {
+	w.requests = append(w.requests, &resourcepb.BulkRequest{Key: key, Value: value, Action: resourcepb.BulkRequest_ADDED})
+	return nil
+}
+
+func (w *memoryBulkWriter) CloseWithResults() (*resourcepb.BulkResponse, error) {
+	w.closed = true
+	return &resourcepb.BulkResponse{Processed: int64(len(w.requests))}, nil
+}
+
+func (w *memoryBulkWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestNamespaceExportAndRestore(t *testing.T) {
+	ctx := authlib.WithAuthInfo(t.Context(), &identity.StaticRequester{
+		Type:           authlib.TypeUser,
+		Login:          "testuser",
+		UserID:         123,
+		UserUID:        "u123",
+		OrgRole:        identity.RoleAdmin,
+		IsGrafanaAdmin: true,
+	})
+
+	backend := setupTestStorageBackend(t)
+	srv, err := NewResourceServer(ResourceServerOptions{Backend: backend})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = srv.Stop(ctx)
+	})
+
+	key := func(namespace, name string) *resourcepb.ResourceKey {
+		return &resourcepb.ResourceKey{Namespace: namespace, Group: "playlist.grafana.app", Resource: "playlists", Name: name}
+	}
+	value := func(namespace, name, title string) []byte {
+		return fmt.Appendf(nil, `{"apiVersion":"playlist.grafana.app/v0alpha1","kind":"Playlist","metadata":{"name":%q,"namespace":%q,"uid":"uid-%s"},"spec":{"title":%q}}`, name, namespace, name, title)
+	}
+	create := func(namespace, name, title string) int64 {
+		rsp, err := srv.Create(ctx, &resourcepb.CreateRequest{Key: key(namespace, name), Value: value(namespace, name, title)})
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		return rsp.ResourceVersion
+	}
+	read := func(name string) *resourcepb.ReadResponse {
+		rsp, err := srv.Read(ctx, &resourcepb.ReadRequest{Key: key("default", name)})
+		require.NoError(t, err)
+		return rsp
+	}
+	title := func(name string) string {
+		rsp := read(name)
+		require.Nil(t, rsp.Error)
+		obj := &unstructured.Unstructured{}
+		require.NoError(t, obj.UnmarshalJSON(rsp.Value))
+		v, _, _ := unstructured.NestedString(obj.Object, "spec", "title")
+		return v
+	}
+
+	create("default", "unchanged", "unchanged")
+	updatedRV := create("default", "updated", "before")
+	create("default", "deleted", "deleted")
+	create("other", "ignored", "ignored")
+	snapshotRV := create("default", "unchanged-2", "unchanged")
+
+	// the snowflake resource versions have a millisecond precision
+	time.Sleep(5 * time.Millisecond)
+	snapshotTime := time.Now()
+	time.Sleep(5 * time.Millisecond)
+
+	// The bad bulk change
+	rsp, err := srv.Update(ctx, &resourcepb.UpdateRequest{Key: key("default", "updated"), ResourceVersion: updatedRV, Value: value("default", "updated", "after")})
+	require.NoError(t, err)
+	require.Nil(t, rsp.Error)
+	deleted := read("deleted")
+	drsp, err := srv.Delete(ctx, &resourcepb.DeleteRequest{Key: key("default", "deleted"), ResourceVersion: deleted.ResourceVersion})
+	require.NoError(t, err)
+	require.Nil(t, drsp.Error)
+	create("default", "created", "created")
+
+	writer := &memoryBulkWriter{}
+	res, err := ExportNamespace(ctx, backend, NamespaceExportOptions{Namespace: "default", ResourceVersion: snapshotRV}, writer)
+	require.NoError(t, err)
+	require.True(t, writer.closed)
+	require.Equal(t, int64(4), res.Processed)
+	names := []string{}
+	for _, req := range writer.requests {
+		require.Equal(t, "default", req.Key.Namespace)
+		names = append(names, req.Key.Name)
+	}
+	require.ElementsMatch(t, []string{"unchanged", "updated", "deleted", "unchanged-2"}, names)
+
+	t.Run("exports the namespace at a timestamp", func(t *testing.T) {
+		atTime := &memoryBulkWriter{}
+		res, err := ExportNamespace(ctx, backend, NamespaceExportOptions{Namespace: "default", Timestamp: snapshotTime}, atTime)
+		require.NoError(t, err)
+		require.Equal(t, int64(4), res.Processed)
+		require.Equal(t, writer.requests, atTime.requests)
+	})
+
+	t.Run("dry run reports the changes", func(t *testing.T) {
+		report, err := RestoreNamespace(ctx, backend, srv, NamespaceRestoreOptions{Namespace: "default", DryRun: true}, newSliceBulkIterator(writer.requests...))
+		require.NoError(t, err)
+		require.True(t, report.DryRun)
+		require.Equal(t, 1, report.Created)
+		require.Equal(t, 1, report.Updated)
+		require.Equal(t, 1, report.Deleted)
+		require.Equal(t, 2, report.Unchanged)
+		require.Equal(t, 0, report.Failed)
+
+		actions := map[string]NamespaceRestoreAction{}
+		for _, c := range report.Changes {
+			actions[c.Name] = c.Action
+			require.Zero(t, c.ResourceVersion)
+		}
+		require.Equal(t, map[string]NamespaceRestoreAction{
+			"deleted": NamespaceRestoreCreate,
+			"updated": NamespaceRestoreUpdate,
+			"created": NamespaceRestoreDelete,
+		}, actions)
+
+		// nothing was written
+		require.Equal(t, "after", title("updated"))
+		require.NotNil(t, read("deleted").Error)
+	})
+
+	t.Run("restore replays the changes", func(t *testing.T) {
+		report, err := RestoreNamespace(ctx, backend, srv, NamespaceRestoreOptions{Namespace: "default"}, newSliceBulkIterator(writer.requests...))
+		require.NoError(t, err)
+		require.Equal(t, 0, report.Failed, report.Changes)
+		require.Len(t, report.Changes, 3)
+		for _, c := range report.Changes {
+			require.Positive(t, c.ResourceVersion)
+		}
+
+		require.Equal(t, "before", title("updated"))
+		require.Equal(t, "deleted", title("deleted"))
+		require.NotNil(t, read("created").Error)
+
+		// restoring again is a no-op
+		report, err = RestoreNamespace(ctx, backend, srv, NamespaceRestoreOptions{Namespace: "default"}, newSliceBulkIterator(writer.requests...))
+		require.NoError(t, err)
+		require.Empty(t, report.Changes)
+		require.Equal(t, 4, report.Unchanged)
+	})
+
+	t.Run("rejects snapshots from another namespace", func(t *testing.T) {
+		_, err := RestoreNamespace(ctx, backend, srv, NamespaceRestoreOptions{Namespace: "other"}, newSliceBulkIterator(writer.requests...))
+		require.Error(t, err)
+	})
+}
+
+func TestResourceVersionAtTime(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+
+	t.Run("snowflake resource versions", func(t *testing.T) {
+		rv := resourceVersionAtTime(snowflakeFromTime(time.Now()), at)
+		require.True(t, isSnowflake(rv))
+		require.Equal(t, at, resourceVersionTime(rv).UTC())
+	})
+
+	t.Run("microsecond resource versions", func(t *testing.T) {
+		rv := resourceVersionAtTime(time.Now().UnixMicro(), at)
+		require.False(t, isSnowflake(rv))
+		require.Equal(t, at.UnixMicro(), rv)
+		require.Equal(t, at, resourceVersionTime(rv).UTC())
+	})
+
+	t.Run("empty backend", func(t *testing.T) {
+		require.Equal(t, at.UnixMicro(), resourceVersionAtTime(0, at))
+	})
+}
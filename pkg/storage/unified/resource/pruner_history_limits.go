@@ -1,5 +1,7 @@
 package resource
 
+import "context"
+
 // defaultPrunerHistoryLimit is the default number of history entries to keep per resource.
 const defaultPrunerHistoryLimit = 20
 
@@ -20,3 +22,33 @@ func LookupPrunerHistoryLimit(group, resource string, dashboardVersionsToKeep in
 	}
 	return defaultPrunerHistoryLimit
 }
+
+// HistoryQuotas provides the history quotas of the namespaces, such as the OverridesService.
+type HistoryQuotas interface {
+	// GetHistoryLimit returns the max number of history entries to keep for each resource, or 0 when no quota is set.
+	GetHistoryLimit(ctx context.Context, nsr NamespacedResource) int
+}
+
+// HistoryQuotaSupport is implemented by the backends enforcing the history quotas when pruning.
+// The quotas must be set before the backend serves writes.
+type HistoryQuotaSupport interface {
+	SetHistoryQuotas(quotas HistoryQuotas)
+}
+
+// PrunerHistoryLimit returns the history limit for the pruning key,
+// lowered to the history quota of its namespace when one is set.
+func PrunerHistoryLimit(ctx context.Context, quotas HistoryQuotas, key PruningKey, dashboardVersionsToKeep int) int {
+	limit := LookupPrunerHistoryLimit(key.Group, key.Resource, dashboardVersionsToKeep)
+	if quotas == nil {
+		return limit
+	}
+	quota := quotas.GetHistoryLimit(ctx, NamespacedResource{
+		Namespace: key.Namespace,
+		Group:     key.Group,
+		Resource:  key.Resource,
+	})
+	if quota > 0 && quota < limit {
+		return quota
+	}
+	return limit
+}
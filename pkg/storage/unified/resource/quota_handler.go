@@ -0,0 +1,124 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	claims "github.com/grafana/authlib/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+)
+
+// NewQuotaHandler exposes the quota usage of the namespaces over HTTP:
+//
+//	GET /namespaces/{namespace}
+//	GET /namespaces/{namespace}/{group}/{resource}
+//
+// The requests must be authenticated, the caller must belong to the namespace and only
+// gets the usage of the resources it can list.
+// Measuring the size of the resources lists them, so the requests are as expensive as a full list.
+func NewQuotaHandler(r *QuotaReporter, access claims.AccessClient) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /namespaces/{namespace}", func(w http.ResponseWriter, req *http.Request) {
+		namespace := req.PathValue("namespace")
+		user, status := authorizeQuotaNamespace(req.Context(), namespace)
+		if status != http.StatusOK {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+
+		rsp, err := r.NamespaceStatus(req.Context(), namespace)
+		if err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		allowed := make([]QuotaStatus, 0, len(rsp.Resources))
+		for _, res := range rsp.Resources {
+			ok, err := canListQuotaResource(req.Context(), access, user, NamespacedResource{Namespace: namespace, Group: res.Group, Resource: res.Resource})
+			if err != nil {
+				writeAPIError(w, err)
+				return
+			}
+			if ok {
+				allowed = append(allowed, res)
+			}
+		}
+		rsp.Resources = allowed
+		writeJSON(w, http.StatusOK, rsp)
+	})
+
+	mux.HandleFunc("GET /namespaces/{namespace}/{group}/{resource}", func(w http.ResponseWriter, req *http.Request) {
+		nsr := NamespacedResource{
+			Namespace: req.PathValue("namespace"),
+			Group:     req.PathValue("group"),
+			Resource:  req.PathValue("resource"),
+		}
+		user, status := authorizeQuotaNamespace(req.Context(), nsr.Namespace)
+		if status != http.StatusOK {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		ok, err := canListQuotaResource(req.Context(), access, user, nsr)
+		if err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		rsp, err := r.ResourceStatus(req.Context(), nsr)
+		if err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, rsp)
+	})
+
+	return mux
+}
+
+// authorizeQuotaNamespace returns the caller when it belongs to the namespace
+func authorizeQuotaNamespace(ctx context.Context, namespace string) (claims.AuthInfo, int) {
+	user, ok := claims.AuthInfoFrom(ctx)
+	if !ok || user == nil {
+		return nil, http.StatusUnauthorized
+	}
+	if !claims.NamespaceMatches(user.GetNamespace(), namespace) {
+		return nil, http.StatusForbidden
+	}
+	return user, http.StatusOK
+}
+
+func canListQuotaResource(ctx context.Context, access claims.AccessClient, user claims.AuthInfo, nsr NamespacedResource) (bool, error) {
+	rsp, err := access.Check(ctx, user, claims.CheckRequest{
+		Verb:      utils.VerbList,
+		Group:     nsr.Group,
+		Resource:  nsr.Resource,
+		Namespace: nsr.Namespace,
+	}, "")
+	if err != nil {
+		return false, err
+	}
+	return rsp.Allowed, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, err error) {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		writeJSON(w, int(status.Status().Code), status.Status())
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
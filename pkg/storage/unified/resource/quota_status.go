@@ -0,0 +1,161 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// QuotaState is the state of a resource relative to its quota
+type QuotaState string
+
+const (
+	QuotaStateOK       QuotaState = "ok"
+	QuotaStateWarning  QuotaState = "warning"
+	QuotaStateExceeded QuotaState = "exceeded"
+)
+
+// QuotaStatus is the usage of a resource in a namespace, compared to its quota
+type QuotaStatus struct {
+	Group    string `json:"group"`
+	Resource string `json:"resource"`
+
+	// Number of resources
+	Count int64 `json:"count"`
+	// Total size of the latest version of the resources
+	Bytes int64 `json:"bytes"`
+
+	Limit        int     `json:"limit"`
+	SoftLimit    int     `json:"softLimit"`
+	HistoryLimit int     `json:"historyLimit,omitempty"`
+	PercentUsed  float64 `json:"percentUsed"`
+	// Creates are rejected past the limit when enforced, and succeed with a warning otherwise
+	Enforced bool       `json:"enforced"`
+	State    QuotaState `json:"state"`
+}
+
+// NamespaceQuotaStatus is the usage of all the resources of a namespace
+type NamespaceQuotaStatus struct {
+	Namespace string        `json:"namespace"`
+	Resources []QuotaStatus `json:"resources"`
+}
+
+// QuotaReporter reports the usage of the namespaces against the quotas of the overrides
+type QuotaReporter struct {
+	backend   StorageBackend
+	overrides *OverridesService
+	config    QuotasConfig
+}
+
+func NewQuotaReporter(backend StorageBackend, overrides *OverridesService, config QuotasConfig) *QuotaReporter {
+	return &QuotaReporter{
+		backend:   backend,
+		overrides: overrides,
+		config:    config,
+	}
+}
+
+// NamespaceStatus returns the status of every resource of the namespace, with the resources
+// that have a quota override but no instance yet.
+func (r *QuotaReporter) NamespaceStatus(ctx context.Context, namespace string) (*NamespaceQuotaStatus, error) {
+	if namespace == "" {
+		return nil, fmt.Errorf("missing namespace")
+	}
+
+	stats, err := r.backend.GetResourceStats(ctx, NamespacedResource{Namespace: namespace}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("get resource stats: %w", err)
+	}
+	counts := make(map[string]int64, len(stats))
+	for _, stat := range stats {
+		counts[stat.Group+"/"+stat.Resource] += stat.Count
+	}
+	overridden, err := r.overrides.getNamespaceQuotas(namespace)
+	if err != nil {
+		return nil, err
+	}
+	for groupResource := range overridden {
+		if _, ok := counts[groupResource]; !ok {
+			counts[groupResource] = 0
+		}
+	}
+
+	rsp := &NamespaceQuotaStatus{Namespace: namespace, Resources: make([]QuotaStatus, 0, len(counts))}
+	for groupResource, count := range counts {
+		group, resource, ok := strings.Cut(groupResource, "/")
+		if !ok {
+			continue
+		}
+		status, err := r.status(ctx, NamespacedResource{Namespace: namespace, Group: group, Resource: resource}, count)
+		if err != nil {
+			return nil, err
+		}
+		rsp.Resources = append(rsp.Resources, *status)
+	}
+	slices.SortFunc(rsp.Resources, func(a, b QuotaStatus) int {
+		return strings.Compare(a.Group+"/"+a.Resource, b.Group+"/"+b.Resource)
+	})
+	return rsp, nil
+}
+
+// ResourceStatus returns the status of a resource in a namespace
+func (r *QuotaReporter) ResourceStatus(ctx context.Context, nsr NamespacedResource) (*QuotaStatus, error) {
+	stats, err := r.backend.GetResourceStats(ctx, nsr, 0)
+	if err != nil {
+		return nil, fmt.Errorf("get resource stats: %w", err)
+	}
+	var count int64
+	for _, stat := range stats {
+		count += stat.Count
+	}
+	return r.status(ctx, nsr, count)
+}
+
+func (r *QuotaReporter) status(ctx context.Context, nsr NamespacedResource, count int64) (*QuotaStatus, error) {
+	quota, err := r.overrides.GetQuota(ctx, nsr)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &QuotaStatus{
+		Group:        nsr.Group,
+		Resource:     nsr.Resource,
+		Count:        count,
+		Limit:        quota.Limit,
+		SoftLimit:    quota.GetSoftLimit(),
+		HistoryLimit: quota.HistoryLimit,
+		Enforced:     r.config.ShouldEnforce(nsr.Group, nsr.Resource),
+		State:        QuotaStateOK,
+	}
+	if quota.Limit > 0 {
+		status.PercentUsed = float64(count) * 100 / float64(quota.Limit)
+	}
+	switch {
+	case count >= int64(quota.Limit):
+		status.State = QuotaStateExceeded
+	case count >= int64(status.SoftLimit):
+		status.State = QuotaStateWarning
+	}
+
+	if count > 0 {
+		status.Bytes, err = r.collectionBytes(ctx, nsr)
+		if err != nil {
+			return nil, fmt.Errorf("measure %s: %w", nsr.String(), err)
+		}
+	}
+	return status, nil
+}
+
+// collectionBytes sums the size of the latest version of the resources
+func (r *QuotaReporter) collectionBytes(ctx context.Context, nsr NamespacedResource) (int64, error) {
+	var size int64
+	key := &resourcepb.ResourceKey{Namespace: nsr.Namespace, Group: nsr.Group, Resource: nsr.Resource}
+	_, err := listNamespaceCollection(ctx, r.backend, key, 0, func(iter ListIterator) error {
+		size += int64(len(iter.Value()))
+		return nil
+	})
+	return size, err
+}
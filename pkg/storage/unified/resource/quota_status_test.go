@@ -0,0 +1,211 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	authlib "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func setupOverridesService(t *testing.T, content string) *OverridesService {
+	t.Helper()
+	overridesFile := filepath.Join(t.TempDir(), "overrides.yaml")
+	require.NoError(t, os.WriteFile(overridesFile, []byte(content), 0644))
+
+	tcr := tracing.NewNoopTracerService()
+	overridesService, err := NewOverridesService(t.Context(), log.NewNopLogger(), prometheus.NewRegistry(), tcr.Tracer, ReloadOptions{
+		FilePath: overridesFile,
+	})
+	require.NoError(t, err)
+	require.NoError(t, overridesService.init(t.Context()))
+	t.Cleanup(func() {
+		_ = overridesService.stop(context.Background())
+	})
+	return overridesService
+}
+
+func TestResourceQuotaGetSoftLimit(t *testing.T) {
+	require.Equal(t, 800, ResourceQuota{Limit: 1000}.GetSoftLimit())
+	require.Equal(t, 900, ResourceQuota{Limit: 1000, SoftLimit: 900}.GetSoftLimit())
+	require.Equal(t, 1000, ResourceQuota{Limit: 1000, SoftLimit: 2000}.GetSoftLimit())
+}
+
+type fakeHistoryQuotas map[string]int
+
+func (f fakeHistoryQuotas) GetHistoryLimit(_ context.Context, nsr NamespacedResource) int {
+	return f[nsr.Namespace]
+}
+
+func TestPrunerHistoryLimit(t *testing.T) {
+	quotas := fakeHistoryQuotas{"lower": 5, "higher": 100}
+	key := func(namespace string) PruningKey {
+		return PruningKey{Namespace: namespace, Group: "playlist.grafana.app", Resource: "playlists", Name: "a"}
+	}
+
+	require.Equal(t, defaultPrunerHistoryLimit, PrunerHistoryLimit(t.Context(), nil, key("lower"), 20))
+	require.Equal(t, 5, PrunerHistoryLimit(t.Context(), quotas, key("lower"), 20))
+	require.Equal(t, defaultPrunerHistoryLimit, PrunerHistoryLimit(t.Context(), quotas, key("higher"), 20))
+	require.Equal(t, defaultPrunerHistoryLimit, PrunerHistoryLimit(t.Context(), quotas, key("none"), 20))
+}
+
+func TestCheckQuotaSoftLimit(t *testing.T) {
+	overridesService := setupOverridesService(t, `overrides:
+  "123":
+    quotas:
+      dashboard.grafana.app/dashboards:
+        limit: 10
+        soft_limit: 5
+`)
+	nsr := NamespacedResource{Namespace: "stacks-123", Group: "dashboard.grafana.app", Resource: "dashboards"}
+
+	for _, tt := range []struct {
+		count    int64
+		warnings float64
+	}{
+		{count: 4, warnings: 0},
+		{count: 5, warnings: 1},
+		{count: 12, warnings: 1},
+	} {
+		t.Run(fmt.Sprintf("count %d", tt.count), func(t *testing.T) {
+			metrics := ProvideStorageMetrics(prometheus.NewRegistry())
+			s := &server{
+				backend:          &mockStorageBackend{resourceStats: []ResourceStats{{NamespacedResource: nsr, Count: tt.count}}},
+				overridesService: overridesService,
+				storageMetrics:   metrics,
+				log:              log.NewNopLogger(),
+			}
+
+			require.NoError(t, s.checkQuota(t.Context(), nsr))
+			require.Equal(t, tt.warnings, promtestutil.ToFloat64(metrics.QuotaWarnings.WithLabelValues("dashboard.grafana.app/dashboards", string(QuotaStateWarning))))
+		})
+	}
+}
+
+func TestQuotaReporter(t *testing.T) {
+	ctx := authlib.WithAuthInfo(t.Context(), &identity.StaticRequester{
+		Type:           authlib.TypeUser,
+		Login:          "testuser",
+		UserID:         123,
+		UserUID:        "u123",
+		OrgRole:        identity.RoleAdmin,
+		IsGrafanaAdmin: true,
+	})
+
+	backend := setupTestStorageBackend(t)
+	srv, err := NewResourceServer(ResourceServerOptions{Backend: backend})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = srv.Stop(ctx)
+	})
+
+	overridesService := setupOverridesService(t, `overrides:
+  "123":
+    quotas:
+      playlist.grafana.app/playlists:
+        limit: 4
+        history_limit: 3
+      folder.grafana.app/folders:
+        limit: 10
+`)
+
+	for i := range 3 {
+		value := fmt.Appendf(nil, `{"apiVersion":"playlist.grafana.app/v0alpha1","kind":"Playlist","metadata":{"name":"p%d","namespace":"stacks-123"},"spec":{"title":"Playlist %d"}}`, i, i)
+		rsp, err := srv.Create(ctx, &resourcepb.CreateRequest{
+			Key:   &resourcepb.ResourceKey{Namespace: "stacks-123", Group: "playlist.grafana.app", Resource: "playlists", Name: fmt.Sprintf("p%d", i)},
+			Value: value,
+		})
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+	}
+
+	reporter := NewQuotaReporter(backend, overridesService, QuotasConfig{
+		EnforcedResources: map[string]bool{"playlist.grafana.app/playlists": true},
+	})
+	playlists := QuotaStatus{
+		Group:        "playlist.grafana.app",
+		Resource:     "playlists",
+		Count:        3,
+		Limit:        4,
+		SoftLimit:    3,
+		HistoryLimit: 3,
+		PercentUsed:  75,
+		Enforced:     true,
+		State:        QuotaStateWarning,
+	}
+
+	status, err := reporter.ResourceStatus(ctx, NamespacedResource{Namespace: "stacks-123", Group: "playlist.grafana.app", Resource: "playlists"})
+	require.NoError(t, err)
+	require.Positive(t, status.Bytes)
+	playlists.Bytes = status.Bytes
+	require.Equal(t, playlists, *status)
+
+	t.Run("namespace status includes the overridden resources", func(t *testing.T) {
+		rsp, err := reporter.NamespaceStatus(ctx, "stacks-123")
+		require.NoError(t, err)
+		require.Equal(t, &NamespaceQuotaStatus{
+			Namespace: "stacks-123",
+			Resources: []QuotaStatus{{
+				Group:     "folder.grafana.app",
+				Resource:  "folders",
+				Limit:     10,
+				SoftLimit: 8,
+				State:     QuotaStateOK,
+			}, playlists},
+		}, rsp)
+	})
+
+	t.Run("http handler", func(t *testing.T) {
+		member := authlib.WithAuthInfo(t.Context(), &identity.StaticRequester{
+			Type:      authlib.TypeUser,
+			Login:     "member",
+			UserID:    124,
+			UserUID:   "u124",
+			Namespace: "stacks-123",
+			OrgRole:   identity.RoleViewer,
+		})
+		get := func(ctx context.Context, access authlib.AccessClient, path string) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			NewQuotaHandler(reporter, access).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx))
+			return rec
+		}
+
+		rec := get(member, authlib.FixedAccessClient(true), "/namespaces/stacks-123/playlist.grafana.app/playlists")
+		require.Equal(t, http.StatusOK, rec.Code)
+		got := QuotaStatus{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		require.Equal(t, playlists, got)
+
+		rec = get(member, authlib.FixedAccessClient(false), "/namespaces/stacks-123")
+		require.Equal(t, http.StatusOK, rec.Code)
+		ns := NamespaceQuotaStatus{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &ns))
+		require.Empty(t, ns.Resources, "only the resources the caller can list are reported")
+
+		rec = get(member, authlib.FixedAccessClient(false), "/namespaces/stacks-123/playlist.grafana.app/playlists")
+		require.Equal(t, http.StatusForbidden, rec.Code)
+
+		rec = get(member, authlib.FixedAccessClient(true), "/namespaces/stacks-456")
+		require.Equal(t, http.StatusForbidden, rec.Code, "the caller is not in the namespace")
+
+		rec = get(t.Context(), authlib.FixedAccessClient(true), "/namespaces/stacks-123")
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
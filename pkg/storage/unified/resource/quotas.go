@@ -18,6 +18,12 @@ import (
 
 const DEFAULT_RESOURCE_LIMIT = 1000
 
+// DEFAULT_SOFT_LIMIT_PERCENT is the share of the limit from which writes get a warning, when no soft limit is set
+const DEFAULT_SOFT_LIMIT_PERCENT = 80
+
+// QuotaWarningHeader is the gRPC response header holding the quota warnings of a write
+const QuotaWarningHeader = "grafana-quota-warning"
+
 type QuotasConfig struct {
 	EnforcedResources map[string]bool // group/resource keys to enforce, e.g. {"dashboard.grafana.app/dashboards": true}
 	SupportMessage    string
@@ -44,6 +50,18 @@ func (e QuotaExceededError) Message() string {
 	return fmt.Sprintf("Limit reached for resource %s: %d/%d %s", e.Resource, e.Used, e.Limit, e.SupportMessage)
 }
 
+// quotaWarning is the message returned to the clients writing past the soft limit
+func quotaWarning(resource string, used int64, limit int, supportMessage string) string {
+	msg := fmt.Sprintf("Resource %s is close to its limit: %d/%d", resource, used, limit)
+	if used >= int64(limit) {
+		msg = fmt.Sprintf("Limit reached for resource %s: %d/%d", resource, used, limit)
+	}
+	if supportMessage != "" {
+		msg += " " + supportMessage
+	}
+	return msg
+}
+
 type OverridesService struct {
 	manager *runtimeconfig.Manager
 	logger  log.Logger
@@ -58,6 +76,20 @@ type ReloadOptions struct {
 // ResourceQuota represents quota limits for a specific resource
 type ResourceQuota struct {
 	Limit int `yaml:"limit"`
+	// Number of resources from which writes get a warning.
+	// Defaults to DEFAULT_SOFT_LIMIT_PERCENT of the limit.
+	SoftLimit int `yaml:"soft_limit"`
+	// Max number of history entries kept for each resource, enforced by the history pruner.
+	// The pruner defaults apply when empty.
+	HistoryLimit int `yaml:"history_limit"`
+}
+
+// GetSoftLimit returns the soft limit, or its default
+func (q ResourceQuota) GetSoftLimit() int {
+	if q.SoftLimit > 0 {
+		return min(q.SoftLimit, q.Limit)
+	}
+	return q.Limit * DEFAULT_SOFT_LIMIT_PERCENT / 100
 }
 
 // NamespaceOverrides represents all overrides for a tenant
@@ -79,6 +111,8 @@ overrides:
 	  quotas:
 	    dashboard.grafana.app/dashboards:
 	      limit: 1500
+	      soft_limit: 1200
+	      history_limit: 10
 	    folder.grafana.app/folders:
 	      limit: 1500
 */
@@ -139,18 +173,38 @@ func (q *OverridesService) GetQuota(_ context.Context, nsr NamespacedResource) (
 		return ResourceQuota{}, fmt.Errorf("invalid namespaced resource: %+v", nsr)
 	}
 
-	overrides, ok := q.manager.GetConfig().(*Overrides)
-	if !ok {
-		return ResourceQuota{}, fmt.Errorf("failed to get quota overrides from config manager")
+	quotas, err := q.getNamespaceQuotas(nsr.Namespace)
+	if err != nil {
+		return ResourceQuota{}, err
 	}
-
-	tenantId := strings.TrimPrefix(nsr.Namespace, "stacks-")
-	groupResource := nsr.Group + "/" + nsr.Resource
-	if tenantOverrides, ok := overrides.Namespaces[tenantId]; ok {
-		if resourceQuota, ok := tenantOverrides.Quotas[groupResource]; ok {
-			return resourceQuota, nil
+	if resourceQuota, ok := quotas[nsr.Group+"/"+nsr.Resource]; ok {
+		if resourceQuota.Limit == 0 {
+			// only the soft or history limits are overridden
+			resourceQuota.Limit = DEFAULT_RESOURCE_LIMIT
 		}
+		return resourceQuota, nil
 	}
 
 	return ResourceQuota{Limit: DEFAULT_RESOURCE_LIMIT}, nil
 }
+
+// GetHistoryLimit implements HistoryQuotas.
+func (q *OverridesService) GetHistoryLimit(ctx context.Context, nsr NamespacedResource) int {
+	quota, err := q.GetQuota(ctx, nsr)
+	if err != nil {
+		q.logger.Debug("failed to get history quota", "namespace", nsr.Namespace, "group", nsr.Group, "resource", nsr.Resource, "error", err)
+		return 0
+	}
+	return quota.HistoryLimit
+}
+
+// getNamespaceQuotas returns the overridden quotas of a namespace, by group/resource
+func (q *OverridesService) getNamespaceQuotas(namespace string) (map[string]ResourceQuota, error) {
+	overrides, ok := q.manager.GetConfig().(*Overrides)
+	if !ok {
+		return nil, fmt.Errorf("failed to get quota overrides from config manager")
+	}
+
+	tenantId := strings.TrimPrefix(namespace, "stacks-")
+	return overrides.Namespaces[tenantId].Quotas, nil
+}
@@ -0,0 +1,358 @@
+package resource
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	storageapi "github.com/grafana/grafana/pkg/apis/storage/v0alpha1"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/util/debouncer"
+)
+
+const (
+	defaultRetentionReloadInterval = time.Minute
+	// how often the resources with an age based rule are queued for pruning,
+	// so the old versions of the resources that are not written anymore are pruned too
+	retentionSweepInterval = time.Hour
+
+	// the reasons of the pruned revisions metric
+	PruneReasonCount = "count"
+	PruneReasonAge   = "age"
+)
+
+var retentionPolicyResource = storageapi.RetentionPolicyResourceInfo.GroupResource()
+
+// retentionRule is a rule of a storage.grafana.app/retentionpolicies resource
+type retentionRule storageapi.RetentionRule
+
+func (r retentionRule) matches(group, resource string) bool {
+	return (r.Group == "" || r.Group == group) && (r.Resource == "" || r.Resource == resource)
+}
+
+func (r retentionRule) specificity() int {
+	switch {
+	case r.Resource != "":
+		return 2
+	case r.Group != "":
+		return 1
+	}
+	return 0
+}
+
+// retentionPolicySource is the part of the storage backend used to read the retention policies
+type retentionPolicySource interface {
+	ListIterator(ctx context.Context, req *resourcepb.ListRequest, cb func(ListIterator) error) (int64, error)
+}
+
+// RetentionPolicies are the retention policies applied by the history pruners of the KV and SQL backends.
+//
+// The policies are storage.grafana.app/retentionpolicies resources, validated and authorized by the apiserver.
+// They are listed from the storage backend and cached in memory: the cache is reloaded regularly, so the
+// changes apply after the reload interval.
+type RetentionPolicies struct {
+	source         retentionPolicySource
+	log            log.Logger
+	reloadInterval time.Duration
+
+	mu     sync.RWMutex
+	rules  map[string][]retentionRule // by namespace, in the order of the policy names
+	loaded time.Time
+}
+
+func NewRetentionPolicies(source retentionPolicySource, logger log.Logger, reloadInterval time.Duration) *RetentionPolicies {
+	if reloadInterval <= 0 {
+		reloadInterval = defaultRetentionReloadInterval
+	}
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+	return &RetentionPolicies{
+		source:         source,
+		log:            logger,
+		reloadInterval: reloadInterval,
+		rules:          map[string][]retentionRule{},
+	}
+}
+
+// Run reloads the policies until the context is canceled
+func (p *RetentionPolicies) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.reload(ctx); err != nil {
+			p.log.Error("Failed to reload retention policies", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh reloads the policies when they were loaded more than the reload interval ago,
+// for the pruners that read the policies without running the reload loop
+func (p *RetentionPolicies) Refresh(ctx context.Context) error {
+	p.mu.RLock()
+	fresh := time.Since(p.loaded) < p.reloadInterval
+	p.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+	return p.reload(ctx)
+}
+
+func (p *RetentionPolicies) reload(ctx context.Context) error {
+	policies := []*storageapi.RetentionPolicy{}
+	_, err := p.source.ListIterator(ctx, &resourcepb.ListRequest{
+		Options: &resourcepb.ListOptions{
+			Key: &resourcepb.ResourceKey{
+				Group:    retentionPolicyResource.Group,
+				Resource: retentionPolicyResource.Resource,
+			},
+		},
+	}, func(iter ListIterator) error {
+		for iter.Next() {
+			if err := iter.Error(); err != nil {
+				return err
+			}
+			policy := &storageapi.RetentionPolicy{}
+			if err := json.Unmarshal(iter.Value(), policy); err != nil {
+				p.log.Error("invalid retention policy", "namespace", iter.Namespace(), "name", iter.Name(), "error", err)
+				continue
+			}
+			policies = append(policies, policy)
+		}
+		return iter.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	// the rule of the policy with the smallest name wins
+	slices.SortFunc(policies, func(a, b *storageapi.RetentionPolicy) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	rules := make(map[string][]retentionRule)
+	for _, policy := range policies {
+		for _, rule := range policy.Spec.Rules {
+			rules[policy.Namespace] = append(rules[policy.Namespace], retentionRule(rule))
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rules = rules
+	p.loaded = time.Now()
+	return nil
+}
+
+// rule returns the rule applying to a resource
+func (p *RetentionPolicies) rule(nsr NamespacedResource) (retentionRule, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var found retentionRule
+	ok := false
+	for _, rule := range p.rules[nsr.Namespace] {
+		if rule.matches(nsr.Group, nsr.Resource) && (!ok || rule.specificity() > found.specificity()) {
+			found = rule
+			ok = true
+		}
+	}
+	return found, ok
+}
+
+// HistoryRetention returns the retention of a resource: its retention policy rule, capped by the
+// history quota of its namespace, or the default history limits when no rule applies. The default
+// count limit applies to the rules that only set an age.
+func (p *RetentionPolicies) HistoryRetention(ctx context.Context, quotas HistoryQuotas, key PruningKey, dashboardVersionsToKeep int) HistoryRetention {
+	nsr := NamespacedResource{Namespace: key.Namespace, Group: key.Group, Resource: key.Resource}
+	rule, ok := p.rule(nsr)
+	if !ok {
+		return HistoryRetention{MaxVersions: PrunerHistoryLimit(ctx, quotas, key, dashboardVersionsToKeep)}
+	}
+
+	retention := HistoryRetention{MaxVersions: rule.MaxVersions, MinVersions: rule.MinVersions}
+	if retention.MaxVersions == 0 {
+		// a rule without a count limit keeps the default one
+		retention.MaxVersions = LookupPrunerHistoryLimit(key.Group, key.Resource, dashboardVersionsToKeep)
+	}
+	if rule.MaxAge.Duration > 0 {
+		retention.Cutoff = time.Now().Add(-rule.MaxAge.Duration)
+	}
+	// the history quotas are set by the operators and apply over the policies
+	if quotas != nil {
+		if quota := quotas.GetHistoryLimit(ctx, nsr); quota > 0 && quota < retention.MaxVersions {
+			retention.MaxVersions = quota
+			retention.MinVersions = min(retention.MinVersions, quota)
+		}
+	}
+	return retention
+}
+
+// SweepNamespaces returns the namespaces with an age based rule. The old versions of their resources
+// must be pruned regularly, as the resources that are not written anymore are not pruned on write.
+func (p *RetentionPolicies) SweepNamespaces() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	namespaces := []string{}
+	for namespace, rules := range p.rules {
+		if slices.ContainsFunc(rules, func(rule retentionRule) bool { return rule.MaxAge.Duration > 0 }) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	slices.Sort(namespaces)
+	return namespaces
+}
+
+// sweepTargets returns the namespaced resources with an age based rule
+func (p *RetentionPolicies) sweepTargets() []retentionSweepTarget {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	targets := []retentionSweepTarget{}
+	for namespace, rules := range p.rules {
+		for _, rule := range rules {
+			if rule.MaxAge.Duration > 0 {
+				targets = append(targets, retentionSweepTarget{namespace: namespace, group: rule.Group, resource: rule.Resource})
+			}
+		}
+	}
+	return targets
+}
+
+type retentionSweepTarget struct {
+	namespace string
+	group     string // empty for all the groups
+	resource  string // empty for all the resources of the group
+}
+
+// HistoryRetention is the retention applied by the pruner to the history of a resource
+type HistoryRetention struct {
+	MaxVersions int       // zero keeps any number of versions
+	MinVersions int       // kept whatever their age
+	Cutoff      time.Time // zero keeps versions of any age
+}
+
+// pruneReason returns why the n-th latest version (the latest being 0) is pruned,
+// or an empty string when it is kept
+func (r HistoryRetention) pruneReason(n int, created time.Time) string {
+	if n == 0 || n < r.MinVersions {
+		return ""
+	}
+	if r.MaxVersions > 0 && n >= r.MaxVersions {
+		return PruneReasonCount
+	}
+	if !r.Cutoff.IsZero() && created.Before(r.Cutoff) {
+		return PruneReasonAge
+	}
+	return ""
+}
+
+// runRetentionSweeps regularly queues the resources with an age based rule for pruning
+func (k *kvStorageBackend) runRetentionSweeps(ctx context.Context) {
+	ticker := time.NewTicker(retentionSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.sweepRetention(ctx); err != nil {
+				k.log.Error("Failed to sweep retention policies", "error", err)
+			}
+		}
+	}
+}
+
+func (k *kvStorageBackend) sweepRetention(ctx context.Context) error {
+	targets := k.retention.sweepTargets()
+	if len(targets) == 0 {
+		return nil
+	}
+	groupResources, err := k.dataStore.getGroupResources(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get group resources: %w", err)
+	}
+
+	queued := 0
+	for _, target := range targets {
+		for _, gr := range groupResources {
+			if !(retentionRule{Group: target.group, Resource: target.resource}).matches(gr.Group, gr.Resource) {
+				continue
+			}
+			for dataKey, err := range k.dataStore.ListLatestResourceKeys(ctx, ListRequestKey{
+				Namespace: target.namespace,
+				Group:     gr.Group,
+				Resource:  gr.Resource,
+			}) {
+				if err != nil {
+					return err
+				}
+				if err := k.queuePruning(ctx, PruningKey{
+					Namespace: dataKey.Namespace,
+					Group:     dataKey.Group,
+					Resource:  dataKey.Resource,
+					Name:      dataKey.Name,
+				}); err != nil {
+					return err
+				}
+				queued++
+			}
+		}
+	}
+	k.log.Debug("queued resources for retention pruning", "count", queued)
+	return nil
+}
+
+// queuePruning adds the key to the pruner, waiting while its buffer is full
+func (k *kvStorageBackend) queuePruning(ctx context.Context, key PruningKey) error {
+	for {
+		err := k.historyPruner.Add(key)
+		if !errors.Is(err, debouncer.ErrBufferFull) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// HistoryPrunerMetrics counts the revisions removed by the history pruners
+type HistoryPrunerMetrics struct {
+	prunedRevisions *prometheus.CounterVec
+}
+
+func NewHistoryPrunerMetrics(reg prometheus.Registerer) *HistoryPrunerMetrics {
+	return &HistoryPrunerMetrics{
+		prunedRevisions: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "storage_server",
+			Name:      "history_pruned_revisions_total",
+			Help:      "Number of revisions removed from the history by the pruner, by the limit (count or age) that pruned them",
+		}, []string{"resource", "reason"}),
+	}
+}
+
+// Pruned records the revisions of a resource removed by the pruner
+func (m *HistoryPrunerMetrics) Pruned(key PruningKey, reason string, count int) {
+	if count <= 0 {
+		return
+	}
+	m.prunedRevisions.WithLabelValues(key.Group+"/"+key.Resource, reason).Add(float64(count))
+}
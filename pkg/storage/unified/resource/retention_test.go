@@ -0,0 +1,190 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	storageapi "github.com/grafana/grafana/pkg/apis/storage/v0alpha1"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// testRetentionSource returns a source with the given retention policies
+func testRetentionSource(t *testing.T, policies ...*storageapi.RetentionPolicy) *fakeWebhookSource {
+	source := &fakeWebhookSource{}
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	for i, policy := range policies {
+		policy.TypeMeta = storageapi.RetentionPolicyResourceInfo.TypeMeta()
+		value, err := json.Marshal(policy)
+		require.NoError(t, err)
+		source.put(&resourcepb.ResourceKey{
+			Namespace: policy.Namespace,
+			Group:     retentionPolicyResource.Group,
+			Resource:  retentionPolicyResource.Resource,
+			Name:      policy.Name,
+		}, int64(i+1), value)
+	}
+	return source
+}
+
+func testRetentionPolicy(namespace, name string, rules ...storageapi.RetentionRule) *storageapi.RetentionPolicy {
+	return &storageapi.RetentionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       storageapi.RetentionPolicySpec{Rules: rules},
+	}
+}
+
+func TestRetentionPolicies(t *testing.T) {
+	ctx := t.Context()
+	prod := testRetentionPolicy("prod", "regulated",
+		storageapi.RetentionRule{Group: "dashboard.grafana.app", Resource: "dashboards", MaxAge: metav1.Duration{Duration: 365 * 24 * time.Hour}},
+		storageapi.RetentionRule{Group: "dashboard.grafana.app", MaxVersions: 50},
+		storageapi.RetentionRule{MaxVersions: 10},
+	)
+	source := testRetentionSource(t,
+		prod,
+		// the policy with the smallest name wins
+		testRetentionPolicy("prod", "z-other", storageapi.RetentionRule{MaxVersions: 5}),
+		testRetentionPolicy("sandbox", "short", storageapi.RetentionRule{MaxVersions: 20}),
+	)
+	p := NewRetentionPolicies(source, nil, time.Hour)
+
+	// nothing is loaded before the first reload
+	_, ok := p.rule(NamespacedResource{Namespace: "prod", Group: "folder.grafana.app", Resource: "folders"})
+	require.False(t, ok)
+	require.NoError(t, p.Refresh(ctx))
+
+	rule, ok := p.rule(NamespacedResource{Namespace: "prod", Group: "dashboard.grafana.app", Resource: "dashboards"})
+	require.True(t, ok)
+	require.Equal(t, retentionRule(prod.Spec.Rules[0]), rule)
+	rule, ok = p.rule(NamespacedResource{Namespace: "prod", Group: "dashboard.grafana.app", Resource: "librarypanels"})
+	require.True(t, ok)
+	require.Equal(t, retentionRule(prod.Spec.Rules[1]), rule)
+	rule, ok = p.rule(NamespacedResource{Namespace: "prod", Group: "folder.grafana.app", Resource: "folders"})
+	require.True(t, ok)
+	require.Equal(t, retentionRule(prod.Spec.Rules[2]), rule)
+	rule, ok = p.rule(NamespacedResource{Namespace: "sandbox", Group: "folder.grafana.app", Resource: "folders"})
+	require.True(t, ok)
+	require.Equal(t, 20, rule.MaxVersions)
+	_, ok = p.rule(NamespacedResource{Namespace: "other", Group: "folder.grafana.app", Resource: "folders"})
+	require.False(t, ok)
+
+	require.Equal(t, []string{"prod"}, p.SweepNamespaces())
+	require.Len(t, p.sweepTargets(), 1)
+
+	t.Run("history retention", func(t *testing.T) {
+		// the age only rule keeps the default count limit
+		retention := p.HistoryRetention(ctx, nil, PruningKey{Namespace: "prod", Group: "dashboard.grafana.app", Resource: "dashboards", Name: "a"}, 30)
+		require.Equal(t, 30, retention.MaxVersions)
+		retention = p.HistoryRetention(ctx, fakeHistoryQuotas{"prod": 5}, PruningKey{Namespace: "prod", Group: "dashboard.grafana.app", Resource: "dashboards", Name: "a"}, 30)
+		require.Equal(t, 5, retention.MaxVersions)
+		require.WithinDuration(t, time.Now().Add(-365*24*time.Hour), retention.Cutoff, time.Minute)
+
+		retention = p.HistoryRetention(ctx, fakeHistoryQuotas{"sandbox": 5}, PruningKey{Namespace: "sandbox", Group: "folder.grafana.app", Resource: "folders", Name: "a"}, 0)
+		require.Equal(t, HistoryRetention{MaxVersions: 5}, retention)
+
+		// the default limits apply without a rule
+		retention = p.HistoryRetention(ctx, nil, PruningKey{Namespace: "other", Group: "folder.grafana.app", Resource: "folders", Name: "a"}, 0)
+		require.Equal(t, HistoryRetention{MaxVersions: PrunerHistoryLimit(ctx, nil, PruningKey{Group: "folder.grafana.app", Resource: "folders"}, 0)}, retention)
+	})
+
+	t.Run("the deleted policies are removed on reload", func(t *testing.T) {
+		source.mu.Lock()
+		source.objects = nil
+		source.mu.Unlock()
+
+		// still fresh
+		require.NoError(t, p.Refresh(ctx))
+		_, ok := p.rule(NamespacedResource{Namespace: "sandbox", Group: "folder.grafana.app", Resource: "folders"})
+		require.True(t, ok)
+
+		require.NoError(t, p.reload(ctx))
+		_, ok = p.rule(NamespacedResource{Namespace: "sandbox", Group: "folder.grafana.app", Resource: "folders"})
+		require.False(t, ok)
+		require.Empty(t, p.SweepNamespaces())
+	})
+}
+
+func TestHistoryRetentionPruneReason(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+	retention := HistoryRetention{MaxVersions: 5, MinVersions: 2, Cutoff: now.Add(-time.Hour)}
+
+	require.Empty(t, retention.pruneReason(0, old))
+	require.Empty(t, retention.pruneReason(1, old))
+	require.Equal(t, PruneReasonAge, retention.pruneReason(2, old))
+	require.Empty(t, retention.pruneReason(2, now))
+	require.Equal(t, PruneReasonCount, retention.pruneReason(5, now))
+
+	// age only
+	retention = HistoryRetention{Cutoff: now.Add(-time.Hour)}
+	require.Empty(t, retention.pruneReason(0, old))
+	require.Empty(t, retention.pruneReason(100, now))
+	require.Equal(t, PruneReasonAge, retention.pruneReason(1, old))
+}
+
+func TestKvStorageBackend_PruneEventsWithRetentionPolicy(t *testing.T) {
+	ctx := t.Context()
+	backend := setupTestStorageBackend(t)
+	ns := NamespacedResource{Namespace: "default", Group: "apps", Resource: "resources"}
+
+	writeVersions := func(name string, count int) {
+		testObj, err := createTestObjectWithName(name, ns, "test-data")
+		require.NoError(t, err)
+		metaAccessor, err := utils.MetaAccessor(testObj)
+		require.NoError(t, err)
+		writeEvent := WriteEvent{
+			Type:   resourcepb.WatchEvent_ADDED,
+			Key:    &resourcepb.ResourceKey{Namespace: ns.Namespace, Group: ns.Group, Resource: ns.Resource, Name: name},
+			Value:  objectToJSONBytes(t, testObj),
+			Object: metaAccessor,
+		}
+		for i := range count {
+			if i > 0 {
+				testObj.Object["spec"].(map[string]any)["value"] = fmt.Sprintf("update-%d", i)
+				writeEvent.Type = resourcepb.WatchEvent_MODIFIED
+				writeEvent.Value = objectToJSONBytes(t, testObj)
+			}
+			rv, err := backend.WriteEvent(ctx, writeEvent)
+			require.NoError(t, err)
+			writeEvent.PreviousRV = rv
+		}
+	}
+	countVersions := func(name string) int {
+		count := 0
+		for _, err := range backend.dataStore.Keys(ctx, ListRequestKey{Namespace: ns.Namespace, Group: ns.Group, Resource: ns.Resource, Name: name}, SortOrderDesc) {
+			require.NoError(t, err)
+			count++
+		}
+		return count
+	}
+	prune := func(name string) {
+		require.NoError(t, backend.pruneEvents(ctx, PruningKey{Namespace: ns.Namespace, Group: ns.Group, Resource: ns.Resource, Name: name}))
+	}
+
+	backend.retention = NewRetentionPolicies(testRetentionSource(t,
+		testRetentionPolicy("default", "short", storageapi.RetentionRule{Group: "apps", Resource: "resources", MaxVersions: 3}),
+	), nil, time.Hour)
+	require.NoError(t, backend.retention.Refresh(ctx))
+
+	writeVersions("a", 5)
+	prune("a")
+	require.Equal(t, 3, countVersions("a"))
+	require.Equal(t, float64(2), promtestutil.ToFloat64(backend.prunerMetrics.prunedRevisions.WithLabelValues("apps/resources", PruneReasonCount)))
+
+	t.Run("the history quota caps the policy", func(t *testing.T) {
+		backend.SetHistoryQuotas(fakeHistoryQuotas{"default": 2})
+		t.Cleanup(func() { backend.SetHistoryQuotas(nil) })
+
+		writeVersions("b", 5)
+		prune("b")
+		require.Equal(t, 2, countVersions("b"))
+	})
+}
@@ -233,6 +233,7 @@ func newSearchServer(opts SearchOptions, storage StorageBackend, access types.Ac
 	s.builders, err = newBuilderCache(info, 100, time.Minute*2) // TODO? opts
 	if s.builders != nil {
 		s.builders.blob = blob
+		s.builders.storage = storage
 	}
 
 	return s, err
@@ -1362,6 +1363,9 @@ type builderCache struct {
 	// Possible blob support
 	blob BlobSupport
 
+	// Used to read the folder tree for builders that need it
+	storage StorageBackend
+
 	// searchable fields initialized once on startup
 	fields map[schema.GroupResource]SearchableDocumentFields
 
@@ -1407,12 +1411,30 @@ func newBuilderCache(cfg []DocumentBuilderInfo, nsCacheSize int, ttl time.Durati
 }
 
 func (s *builderCache) GetFields(key NamespacedResource) SearchableDocumentFields {
-	return s.fields[schema.GroupResource{Group: key.Group, Resource: key.Resource}]
+	if g, ok := s.groupBuilders(key.Group); ok {
+		if r, ok := g[key.Resource]; ok {
+			return s.fields[r.GroupResource]
+		}
+	}
+	return nil
+}
+
+// groupBuilders returns the builders for a group. Groups without builders fall back to a
+// wildcard group, eg "*.datasource.grafana.app" matches the group of every data source plugin.
+func (s *builderCache) groupBuilders(group string) (map[string]DocumentBuilderInfo, bool) {
+	g, ok := s.lookup[group]
+	if ok {
+		return g, true
+	}
+	if idx := strings.Index(group, "."); idx > 0 {
+		g, ok = s.lookup["*"+group[idx:]]
+	}
+	return g, ok
 }
 
 // context is typically background.  Holds an LRU cache for a
 func (s *builderCache) get(ctx context.Context, key NamespacedResource) (DocumentBuilder, error) {
-	g, ok := s.lookup[key.Group]
+	g, ok := s.groupBuilders(key.Group)
 	if ok {
 		r, ok := g[key.Resource]
 		if ok {
@@ -1430,6 +1452,14 @@ func (s *builderCache) get(ctx context.Context, key NamespacedResource) (Documen
 				defer s.mu.Unlock()
 
 				b, err := r.Namespaced(ctx, key.Namespace, s.blob)
+				if f, ok := b.(FolderTreeDocumentBuilder); ok && err == nil {
+					var parents map[string]string
+					parents, err = s.folderParents(ctx, key.Namespace)
+					if err != nil {
+						return nil, err
+					}
+					b = f.WithFolderParents(parents)
+				}
 				if err == nil {
 					_ = s.ns.Add(key, b)
 				}
@@ -1440,6 +1470,35 @@ func (s *builderCache) get(ctx context.Context, key NamespacedResource) (Documen
 	return s.defaultBuilder, nil
 }
 
+// folderParents reads the parent of each folder in the namespace from the folder resources
+func (s *builderCache) folderParents(ctx context.Context, namespace string) (map[string]string, error) {
+	parents := map[string]string{}
+	if s.storage == nil {
+		return parents, nil
+	}
+	_, err := s.storage.ListIterator(ctx, &resourcepb.ListRequest{
+		Limit: 1000000000000, // big number
+		Options: &resourcepb.ListOptions{
+			Key: &resourcepb.ResourceKey{
+				Group:     folders.GROUP,
+				Resource:  folders.RESOURCE,
+				Namespace: namespace,
+			},
+		},
+	}, func(iter ListIterator) error {
+		for iter.Next() {
+			if err := iter.Error(); err != nil {
+				return err
+			}
+			if parent := iter.Folder(); parent != "" {
+				parents[iter.Name()] = parent
+			}
+		}
+		return iter.Error()
+	})
+	return parents, err
+}
+
 // AsResourceKey converts the given namespace and type to a search key
 func AsResourceKey(ns string, t string) (*resourcepb.ResourceKey, error) {
 	if ns == "" {
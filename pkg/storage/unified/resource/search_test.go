@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -16,8 +17,10 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/grafana/authlib/types"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	dashboardv1 "github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard/v1"
+	folders "github.com/grafana/grafana/apps/folder/pkg/apis/folder/v1"
 	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
 )
 
@@ -73,6 +76,60 @@ func (f *fakeDocumentBuilder) BuildDocument(_ context.Context, _ *resourcepb.Res
 	return nil, fmt.Errorf("not expected")
 }
 
+// fakeFolderTreeBuilder records the folder parents it was created with
+type fakeFolderTreeBuilder struct {
+	fakeDocumentBuilder
+	parents map[string]string
+}
+
+func (f *fakeFolderTreeBuilder) WithFolderParents(parents map[string]string) DocumentBuilder {
+	return &fakeFolderTreeBuilder{parents: parents}
+}
+
+// folderStorageBackend lists a fixed set of folders
+type folderStorageBackend struct {
+	mockStorageBackend
+	folders []testListItem
+	req     *resourcepb.ListRequest
+}
+
+func (m *folderStorageBackend) ListIterator(ctx context.Context, req *resourcepb.ListRequest, callback func(ListIterator) error) (int64, error) {
+	m.req = req
+	return 1, callback(&testListIterator{items: m.folders, idx: -1})
+}
+
+type testListItem struct {
+	namespace string
+	name      string
+	folder    string
+	rv        int64
+	value     []byte
+}
+
+type testListIterator struct {
+	items []testListItem
+	idx   int
+}
+
+func (i *testListIterator) Next() bool {
+	i.idx++
+	return i.idx < len(i.items)
+}
+
+func (i *testListIterator) Error() error          { return nil }
+func (i *testListIterator) ContinueToken() string { return "" }
+func (i *testListIterator) Name() string          { return i.items[i.idx].name }
+func (i *testListIterator) Folder() string        { return i.items[i.idx].folder }
+func (i *testListIterator) Value() []byte         { return i.items[i.idx].value }
+
+func (i *testListIterator) ResourceVersion() int64 {
+	return cmp.Or(i.items[i.idx].rv, 1)
+}
+
+func (i *testListIterator) Namespace() string {
+	return cmp.Or(i.items[i.idx].namespace, "default")
+}
+
 // mockStorageBackend implements StorageBackend for testing
 type mockStorageBackend struct {
 	resourceStats   []ResourceStats
@@ -439,6 +496,63 @@ func TestCombineBuildRequests(t *testing.T) {
 	}
 }
 
+func TestBuilderCacheWildcardGroup(t *testing.T) {
+	fields, err := NewSearchableDocumentFields([]*resourcepb.ResourceTableColumnDefinition{{
+		Name: "type",
+		Type: resourcepb.ResourceTableColumnDefinition_STRING,
+	}})
+	require.NoError(t, err)
+
+	defaultBuilder := StandardDocumentBuilder(nil)
+	dsBuilder := StandardDocumentBuilder(nil)
+	cache, err := newBuilderCache([]DocumentBuilderInfo{
+		{Builder: defaultBuilder},
+		{
+			GroupResource: schema.GroupResource{Group: "*.datasource.grafana.app", Resource: "datasources"},
+			Fields:        fields,
+			Builder:       dsBuilder,
+		},
+	}, 10, time.Minute)
+	require.NoError(t, err)
+
+	key := NamespacedResource{Namespace: "default", Group: "prometheus.datasource.grafana.app", Resource: "datasources"}
+	builder, err := cache.get(context.Background(), key)
+	require.NoError(t, err)
+	require.Same(t, dsBuilder, builder)
+	require.Equal(t, fields, cache.GetFields(key))
+
+	key.Resource = "querytypes"
+	builder, err = cache.get(context.Background(), key)
+	require.NoError(t, err)
+	require.Same(t, defaultBuilder, builder)
+	require.Nil(t, cache.GetFields(key))
+}
+
+func TestBuilderCacheFolderParents(t *testing.T) {
+	storage := &folderStorageBackend{folders: []testListItem{
+		{name: "root"},
+		{name: "parent", folder: "root"},
+		{name: "child", folder: "parent"},
+	}}
+	cache, err := newBuilderCache([]DocumentBuilderInfo{
+		{Builder: StandardDocumentBuilder(nil)},
+		{
+			GroupResource: schema.GroupResource{Group: folders.GROUP, Resource: folders.RESOURCE},
+			Namespaced: func(ctx context.Context, namespace string, blob BlobSupport) (DocumentBuilder, error) {
+				return &fakeFolderTreeBuilder{}, nil
+			},
+		},
+	}, 10, time.Minute)
+	require.NoError(t, err)
+	cache.storage = storage
+
+	builder, err := cache.get(context.Background(), NamespacedResource{Namespace: "default", Group: folders.GROUP, Resource: folders.RESOURCE})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"parent": "root", "child": "parent"}, builder.(*fakeFolderTreeBuilder).parents)
+	require.Equal(t, "default", storage.req.Options.Key.Namespace)
+	require.Equal(t, folders.RESOURCE, storage.req.Options.Key.Resource)
+}
+
 func TestShouldRebuildIndex(t *testing.T) {
 	type testcase struct {
 		buildInfo        IndexBuildInfo
@@ -17,7 +17,9 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -455,6 +457,13 @@ func NewUninitializedResourceServer(opts ResourceServerOptions) (*server, error)
 		artificialSuccessfulWriteDelay: opts.Search.IndexMinUpdateInterval,
 	}
 
+	// The history pruners enforce the history quotas of the overrides
+	if opts.OverridesService != nil {
+		if b, ok := opts.Backend.(HistoryQuotaSupport); ok {
+			b.SetHistoryQuotas(opts.OverridesService)
+		}
+	}
+
 	if opts.Search.Resources != nil {
 		var err error
 		s.search, err = newSearchServer(opts.Search, s.backend, s.access, s.blob, opts.IndexMetrics, opts.OwnsIndexFn)
@@ -1918,6 +1927,7 @@ func (s *server) checkQuota(ctx context.Context, nsr NamespacedResource) error {
 	if len(stats) > 0 && stats[0].Count >= int64(quota.Limit) {
 		s.log.FromContext(ctx).Info("Quota exceeded on create", "namespace", nsr.Namespace, "group", nsr.Group, "resource", nsr.Resource, "quota", quota.Limit, "count", stats[0].Count, "stats_resource", stats[0].Resource)
 		if s.quotasConfig.ShouldEnforce(nsr.Group, nsr.Resource) {
+			s.recordQuotaState(nsr, QuotaStateExceeded)
 			return QuotaExceededError{
 				Resource:       nsr.Resource,
 				Used:           stats[0].Count,
@@ -1927,9 +1937,30 @@ func (s *server) checkQuota(ctx context.Context, nsr NamespacedResource) error {
 		}
 	}
 
+	// Writes past the soft limit, or past a limit that is not enforced, succeed with a warning
+	if len(stats) > 0 && stats[0].Count >= int64(quota.GetSoftLimit()) {
+		s.recordQuotaState(nsr, QuotaStateWarning)
+		s.sendQuotaWarning(ctx, quotaWarning(nsr.Resource, stats[0].Count, quota.Limit, s.quotasConfig.SupportMessage))
+	}
+
 	return nil
 }
 
+func (s *server) recordQuotaState(nsr NamespacedResource, state QuotaState) {
+	if s.storageMetrics != nil {
+		s.storageMetrics.QuotaWarnings.WithLabelValues(nsr.Group+"/"+nsr.Resource, string(state)).Inc()
+	}
+}
+
+// sendQuotaWarning adds the warning to the headers of the gRPC response.
+// The apistore turns them into warnings of the API response.
+func (s *server) sendQuotaWarning(ctx context.Context, msg string) {
+	if err := grpc.SetHeader(ctx, metadata.Pairs(QuotaWarningHeader, msg)); err != nil {
+		// the server is called directly, without gRPC
+		s.log.FromContext(ctx).Debug("failed to send quota warning", "warning", msg, "error", err)
+	}
+}
+
 // resourceVersionTime extracts the timestamp embedded in a resource version.
 // Resource versions can be either snowflake IDs (KV backend) or microsecond
 // Unix timestamps (SQL backend).
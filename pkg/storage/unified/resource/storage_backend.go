@@ -64,12 +64,17 @@ type kvStorageBackend struct {
 	log                     log.Logger
 	disablePruner           bool
 	dashboardVersionsToKeep int
+	historyQuotas           HistoryQuotas
+	retention               *RetentionPolicies
+	prunerMetrics           *HistoryPrunerMetrics
 	eventRetentionPeriod    time.Duration
 	eventPruningInterval    time.Duration
 	historyPruner           Pruner
 	garbageCollection       GarbageCollectionConfig
 	lastImportStore         *lastImportStore
 	lastImportTimeMaxAge    time.Duration
+	blobs                   *blobStore
+	closeKV                 bool
 	//tracer        trace.Tracer
 	//reg           prometheus.Registerer
 
@@ -88,6 +93,10 @@ type kvStorageBackend struct {
 	// nil if tenant deletion is not configured.
 	tenantDeleter *TenantDeleter
 
+	// webhooks delivers the write events to the webhook subscriptions.
+	// nil if webhooks are not configured.
+	webhooks *WebhookDispatcher
+
 	searchLookback time.Duration
 
 	// cancel stops all background goroutines owned by the backend.
@@ -95,6 +104,7 @@ type kvStorageBackend struct {
 }
 
 var _ KVBackend = &kvStorageBackend{}
+var _ HistoryQuotaSupport = &kvStorageBackend{}
 
 type KVBackend interface {
 	StorageBackend
@@ -131,11 +141,20 @@ type KVBackendOptions struct {
 	// TenantDeleterConfig, if set, enables periodic deletion of expired pending-delete tenant data.
 	TenantDeleterConfig *TenantDeleterConfig
 
+	// WebhookConfig, if set, enables delivering the write events to webhook subscriptions.
+	WebhookConfig *WebhookConfig
+
 	// SearchLookback is the duration subtracted from sinceRv in calls to ListModifiedSince.
 	// This guards against concurrent writes that commit slightly out-of-order. 0 means no lookback.
 	SearchLookback time.Duration
 
 	DashboardVersionsToKeep int
+
+	// RetentionReloadInterval is how often the retention policies are reloaded from the storage (default: 1 minute)
+	RetentionReloadInterval time.Duration
+
+	// CloseKV closes the KV store when the backend is stopped, for embedded stores that are owned by the backend.
+	CloseKV bool
 }
 
 var (
@@ -202,12 +221,16 @@ func NewKVStorageBackend(opts KVBackendOptions) (KVBackend, error) {
 		dbKeepAlive:             opts.DBKeepAlive,
 		lastImportStore:         newLastImportStore(kv),
 		lastImportTimeMaxAge:    opts.LastImportTimeMaxAge,
+		blobs:                   newBlobStore(kv),
+		closeKV:                 opts.CloseKV,
 		garbageCollection:       garbageCollection,
 		searchLookback:          opts.SearchLookback,
 		disablePruner:           opts.DisablePruner,
 		dashboardVersionsToKeep: opts.DashboardVersionsToKeep,
+		prunerMetrics:           NewHistoryPrunerMetrics(opts.Reg),
 		cancel:                  cancel,
 	}
+	backend.retention = NewRetentionPolicies(backend, logger, opts.RetentionReloadInterval)
 	err = backend.initPruner(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize pruner: %w", err)
@@ -236,9 +259,25 @@ func NewKVStorageBackend(opts KVBackendOptions) (KVBackend, error) {
 		backend.tenantDeleter = td
 	}
 
+	// Optionally start the webhook dispatcher.
+	if opts.WebhookConfig != nil {
+		backend.webhooks = NewWebhookDispatcher(kv, backend, *opts.WebhookConfig)
+		go func() {
+			if err := backend.webhooks.Run(ctx); err != nil {
+				logger.Error("webhook dispatcher stopped", "error", err)
+			}
+		}()
+	}
+
 	// Start the cleanup background job.
 	go backend.runCleanups(ctx)
 
+	// Keep the retention policies up to date, and prune the resources with an age based retention
+	go backend.retention.Run(ctx)
+	if !backend.disablePruner {
+		go backend.runRetentionSweeps(ctx)
+	}
+
 	logger.Info("backend initialized", "kv", fmt.Sprintf("%T", kv))
 
 	return backend, nil
@@ -256,6 +295,11 @@ func (k *kvStorageBackend) IsHealthy(ctx context.Context, _ *resourcepb.HealthCh
 	return &resourcepb.HealthCheckResponse{Status: resourcepb.HealthCheckResponse_SERVING}, nil
 }
 
+// SetHistoryQuotas implements HistoryQuotaSupport.
+func (k *kvStorageBackend) SetHistoryQuotas(quotas HistoryQuotas) {
+	k.historyQuotas = quotas
+}
+
 // Stop shuts down services owned by the backend.
 func (k *kvStorageBackend) Stop(_ context.Context) error {
 	if k.historyPruner != nil {
@@ -269,6 +313,9 @@ func (k *kvStorageBackend) Stop(_ context.Context) error {
 	}
 	// Cancel the background context to stop runCleanups, GC, and other goroutines.
 	k.cancel()
+	if closer, ok := k.kv.(io.Closer); ok && k.closeKV {
+		return closer.Close()
+	}
 	return nil
 }
 
@@ -286,6 +333,19 @@ func (k *kvStorageBackend) runCleanups(ctx context.Context) {
 		case <-ticker.C:
 			k.cleanupOldEvents(ctx)
 			k.cleanupOldLastImportTimes(ctx)
+			k.collectKVGarbage(ctx)
+		}
+	}
+}
+
+// collectKVGarbage reclaims the space used by stale data, for the KV stores that do not do it by themselves (badger)
+func (k *kvStorageBackend) collectKVGarbage(ctx context.Context) {
+	type garbageCollector interface {
+		CollectGarbage(context.Context) error
+	}
+	if gc, ok := k.kv.(garbageCollector); ok {
+		if err := gc.CollectGarbage(ctx); err != nil {
+			k.log.Error("Failed to collect KV garbage", "error", err)
 		}
 	}
 }
@@ -322,10 +382,10 @@ func (k *kvStorageBackend) pruneEvents(ctx context.Context, key PruningKey) erro
 		return fmt.Errorf("invalid pruning key: group, resource, and name must be set: %+v", key)
 	}
 
-	prunerMaxLimit := LookupPrunerHistoryLimit(key.Group, key.Resource, k.dashboardVersionsToKeep)
+	retention := k.retention.HistoryRetention(ctx, k.historyQuotas, key, k.dashboardVersionsToKeep)
 	counter := 0
 	deleted := 0
-	// iterate over all keys for the resource and delete versions beyond the configured limit
+	// iterate over all keys for the resource and delete versions beyond the configured retention
 	for datakey, err := range k.dataStore.Keys(ctx, ListRequestKey{
 		Namespace: key.Namespace,
 		Group:     key.Group,
@@ -337,19 +397,23 @@ func (k *kvStorageBackend) pruneEvents(ctx context.Context, key PruningKey) erro
 		}
 
 		// Pruner needs to exclude deleted events
-		if counter < prunerMaxLimit && datakey.Action != DataActionDeleted {
-			counter++
+		if datakey.Action == DataActionDeleted {
 			continue
 		}
 
-		// If we already have the configured number of versions, delete any more create or update events
-		if datakey.Action != DataActionDeleted {
-			err := k.dataStore.Delete(ctx, datakey)
-			if err != nil {
-				return err
-			}
-			deleted += 1
+		reason := retention.pruneReason(counter, resourceVersionTime(datakey.ResourceVersion))
+		counter++
+		if reason == "" {
+			continue
+		}
+
+		// The version is beyond the configured retention, delete the create or update event
+		err := k.dataStore.Delete(ctx, datakey)
+		if err != nil {
+			return err
 		}
+		deleted += 1
+		k.prunerMetrics.Pruned(key, reason, 1)
 	}
 
 	k.log.Debug("pruned history successfully",
@@ -0,0 +1,231 @@
+package resource
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+
+	storageapi "github.com/grafana/grafana/pkg/apis/storage/v0alpha1"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// WebhookSignatureHeader holds the hex encoded HMAC-SHA256 of the request body, signed with the subscription secret
+	WebhookSignatureHeader = "X-Grafana-Webhook-Signature"
+	// WebhookEventIDHeader holds the event id, receivers should use it to discard the events delivered more than once
+	WebhookEventIDHeader = "X-Grafana-Webhook-Event-Id"
+
+	cloudEventsContentType = "application/cloudevents+json"
+	cloudEventsSpecVersion = "1.0"
+	cloudEventsTypePrefix  = "com.grafana.storage.resource."
+)
+
+// WebhookEvent is the payload delivered to the subscriptions
+type WebhookEvent struct {
+	// Unique for a resource version of a resource, it is the same when the event is delivered again
+	ID string `json:"id"`
+	// ADDED, MODIFIED or DELETED
+	Type string `json:"type"`
+
+	Namespace string `json:"namespace"`
+	Group     string `json:"group"`
+	Resource  string `json:"resource"`
+	Name      string `json:"name"`
+	Folder    string `json:"folder,omitempty"`
+
+	ResourceVersion int64 `json:"resourceVersion"`
+	PreviousRV      int64 `json:"previousResourceVersion,omitempty"`
+
+	// Unix seconds when the event was written, only set for live events
+	Timestamp int64 `json:"timestamp,omitempty"`
+
+	// The resource (for deletes, the last version of the resource)
+	Object json.RawMessage `json:"object,omitempty"`
+}
+
+func newWebhookEvent(evt *WrittenEvent) WebhookEvent {
+	return WebhookEvent{
+		ID:              fmt.Sprintf("%s/%s/%s/%s/%d", evt.Key.Namespace, evt.Key.Group, evt.Key.Resource, evt.Key.Name, evt.ResourceVersion),
+		Type:            evt.Type.String(),
+		Namespace:       evt.Key.Namespace,
+		Group:           evt.Key.Group,
+		Resource:        evt.Key.Resource,
+		Name:            evt.Key.Name,
+		Folder:          evt.Folder,
+		ResourceVersion: evt.ResourceVersion,
+		PreviousRV:      evt.PreviousRV,
+		Timestamp:       evt.Timestamp,
+		Object:          evt.Value,
+	}
+}
+
+// cloudEvent is the structured content mode of a CloudEvent
+// See: https://github.com/cloudevents/spec/blob/v1.0.2/cloudevents/formats/json-format.md
+type cloudEvent struct {
+	SpecVersion     string        `json:"specversion"`
+	ID              string        `json:"id"`
+	Source          string        `json:"source"`
+	Type            string        `json:"type"`
+	Subject         string        `json:"subject"`
+	Time            string        `json:"time,omitempty"`
+	DataContentType string        `json:"datacontenttype"`
+	Data            *WebhookEvent `json:"data"`
+}
+
+func newCloudEvent(evt *WebhookEvent) cloudEvent {
+	ce := cloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              evt.ID,
+		Source:          fmt.Sprintf("/apis/%s/namespaces/%s/%s", evt.Group, evt.Namespace, evt.Resource),
+		Type:            cloudEventsTypePrefix + cloudEventAction(evt.Type),
+		Subject:         evt.Name,
+		DataContentType: "application/json",
+		Data:            evt,
+	}
+	if evt.Timestamp > 0 {
+		ce.Time = time.Unix(evt.Timestamp, 0).UTC().Format(time.RFC3339)
+	}
+	return ce
+}
+
+func cloudEventAction(eventType string) string {
+	switch eventType {
+	case resourcepb.WatchEvent_ADDED.String():
+		return "created"
+	case resourcepb.WatchEvent_MODIFIED.String():
+		return "updated"
+	case resourcepb.WatchEvent_DELETED.String():
+		return "deleted"
+	}
+	return strings.ToLower(eventType)
+}
+
+// signWebhookPayload returns the signature sent in the WebhookSignatureHeader
+func signWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks the signature of a delivered payload, it can be used by the receivers written in go
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(signWebhookPayload(secret, body)), []byte(signature))
+}
+
+// EncryptWebhookSecret encrypts the secret of a sink with the Grafana secret key, the result is saved in
+// the EncryptedSecret of the subscription
+func EncryptWebhookSecret(secret string, secretKey string) (string, error) {
+	encrypted, err := util.Encrypt([]byte(secret), secretKey)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+func decryptWebhookSecret(encrypted string, secretKey string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	secret, err := util.Decrypt(data, secretKey)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// errWebhookSinkNotAllowed is returned when a sink resolves to an address on a private network
+var errWebhookSinkNotAllowed = errors.New("webhook sinks on private networks are not allowed")
+
+// newWebhookClient returns the client delivering the events. Unless private networks are allowed, the
+// destination address is checked when connecting, so neither a DNS record nor a redirect can point a
+// sink to an internal service.
+func newWebhookClient(timeout time.Duration, allowPrivateNetworks bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if !allowPrivateNetworks {
+		dialer.Control = denyPrivateNetworks
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// denyPrivateNetworks refuses the loopback, private, link-local and unspecified addresses
+func denyPrivateNetworks(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", errWebhookSinkNotAllowed, ip)
+	}
+	return nil
+}
+
+// deliverWebhookEvent sends a single event to the sink of the subscription
+func deliverWebhookEvent(ctx context.Context, client *http.Client, sink storageapi.WebhookSink, evt *WebhookEvent) error {
+	var (
+		body        []byte
+		err         error
+		contentType = "application/json"
+	)
+	switch sink.Type {
+	case storageapi.WebhookSinkCloudEvents:
+		contentType = cloudEventsContentType
+		body, err = json.Marshal(newCloudEvent(evt))
+	default:
+		body, err = json.Marshal(evt)
+	}
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(WebhookEventIDHeader, evt.ID)
+	if sink.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, signWebhookPayload(sink.Secret, body))
+	}
+
+	rsp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rsp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(rsp.Body, 1024*64))
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status: %s", rsp.Status)
+	}
+	return nil
+}
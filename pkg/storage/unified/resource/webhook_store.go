@@ -0,0 +1,72 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	kvpkg "github.com/grafana/grafana/pkg/storage/unified/resource/kv"
+)
+
+const (
+	webhooksSection = kvpkg.WebhooksSection
+
+	webhookCursorPrefix = "cursors/"
+)
+
+// webhookStore persists the cursors of the webhook subscriptions in the KV store.
+// The subscriptions and their dead letters are resources of the storage.grafana.app group.
+//
+// Keys:
+//
+//	cursors/<namespace>/<name>
+type webhookStore struct {
+	kv KV
+}
+
+func newWebhookStore(kv KV) *webhookStore {
+	return &webhookStore{kv: kv}
+}
+
+func webhookCursorKey(namespace, name string) string {
+	return webhookCursorPrefix + namespace + "/" + name
+}
+
+// getCursor returns the resource version of the last event handled by the subscription, or zero
+func (s *webhookStore) getCursor(ctx context.Context, namespace, name string) (int64, error) {
+	reader, err := s.kv.Get(ctx, webhooksSection, webhookCursorKey(namespace, name))
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	data, err := readAndClose(reader)
+	if err != nil {
+		return 0, fmt.Errorf("reading webhook cursor: %w", err)
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
+
+func (s *webhookStore) saveCursor(ctx context.Context, namespace, name string, rv int64) error {
+	writer, err := s.kv.Save(ctx, webhooksSection, webhookCursorKey(namespace, name))
+	if err != nil {
+		return fmt.Errorf("opening writer: %w", err)
+	}
+	if _, err := io.WriteString(writer, strconv.FormatInt(rv, 10)); err != nil {
+		_ = writer.Close()
+		return fmt.Errorf("writing webhook cursor: %w", err)
+	}
+	return writer.Close()
+}
+
+func (s *webhookStore) deleteCursor(ctx context.Context, namespace, name string) error {
+	err := s.kv.Delete(ctx, webhooksSection, webhookCursorKey(namespace, name))
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
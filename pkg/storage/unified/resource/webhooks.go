@@ -0,0 +1,639 @@
+package resource
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	storageapi "github.com/grafana/grafana/pkg/apis/storage/v0alpha1"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+const (
+	defaultWebhookMaxAttempts    = 5
+	defaultWebhookInitialBackoff = time.Second
+	defaultWebhookMaxBackoff     = 5 * time.Minute
+	defaultWebhookTimeout        = 10 * time.Second
+	defaultWebhookQueueSize      = 1000
+)
+
+var (
+	webhookSubscriptionResource = storageapi.WebhookSubscriptionResourceInfo.GroupResource()
+	webhookDeadLetterResource   = storageapi.WebhookDeadLetterResourceInfo.GroupResource()
+)
+
+// webhookSubscription is a subscription read from the storage, with the sink secret decrypted
+type webhookSubscription struct {
+	Namespace string
+	Name      string
+
+	Group         string
+	Resource      string
+	LabelSelector string
+
+	Sink storageapi.WebhookSink
+}
+
+// WebhookConfig configures the delivery of the webhook subscriptions.
+type WebhookConfig struct {
+	// MaxAttempts is the number of times an event is sent before it is dead lettered.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, it doubles on every attempt up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Timeout of a single delivery.
+	Timeout time.Duration
+	// AllowPrivateNetworks allows the sinks on loopback, private and link-local addresses.
+	AllowPrivateNetworks bool
+	// QueueSize is the number of pending events per subscription, when the queue is full
+	// the subscription catches up from its cursor.
+	QueueSize int
+	// SecretKey decrypts the sink secrets of the subscriptions.
+	SecretKey string
+	Log       log.Logger
+}
+
+// NewWebhookConfig creates WebhookConfig from Grafana settings and returns nil when webhooks are disabled.
+func NewWebhookConfig(cfg *setting.Cfg) *WebhookConfig {
+	if cfg == nil || !cfg.WebhooksEnabled {
+		return nil
+	}
+	return &WebhookConfig{
+		MaxAttempts:          cfg.WebhooksMaxAttempts,
+		InitialBackoff:       cfg.WebhooksInitialBackoff,
+		MaxBackoff:           cfg.WebhooksMaxBackoff,
+		Timeout:              cfg.WebhooksTimeout,
+		AllowPrivateNetworks: cfg.WebhooksAllowPrivateNetworks,
+		SecretKey:            cfg.SecretKey,
+		Log:                  log.New("storage-webhooks"),
+	}
+}
+
+// webhookEventSource is the part of the storage backend used to read the changes and the subscriptions,
+// and to write the dead letters
+type webhookEventSource interface {
+	WatchWriteEvents(ctx context.Context) (<-chan *WrittenEvent, error)
+	ListModifiedSince(ctx context.Context, key NamespacedResource, sinceRv int64, lastCalledWithSinceRv *time.Time) (int64, iter.Seq2[*ModifiedResource, error])
+	ListIterator(ctx context.Context, req *resourcepb.ListRequest, cb func(ListIterator) error) (int64, error)
+	WriteEvent(ctx context.Context, event WriteEvent) (int64, error)
+}
+
+// WebhookDispatcher delivers the write events of the storage backend to the webhook subscriptions.
+//
+// The subscriptions are storageapi.grafana.app/webhooksubscriptions resources, the dispatcher lists them
+// when it starts and follows their write events. The events that could not be delivered are saved as
+// storageapi.grafana.app/webhookdeadletters resources.
+//
+// Delivery is at-least-once: the resource version of the last event handled by a subscription is
+// persisted after the event is delivered (or dead lettered), and the subscription catches up from
+// it when the dispatcher starts or when it falls behind. While catching up only the latest change
+// of each resource is sent. In HA setups every replica delivers the events, receivers should use
+// the event id to discard duplicates.
+type WebhookDispatcher struct {
+	store  *webhookStore
+	source webhookEventSource
+	client *http.Client
+	log    log.Logger
+
+	secretKey string
+
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	queueSize      int
+
+	mu      sync.Mutex
+	ctx     context.Context // set when running
+	workers map[string]*webhookWorker
+}
+
+func NewWebhookDispatcher(kv KV, source webhookEventSource, cfg WebhookConfig) *WebhookDispatcher {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultWebhookInitialBackoff
+	}
+	if cfg.MaxBackoff < cfg.InitialBackoff {
+		cfg.MaxBackoff = max(defaultWebhookMaxBackoff, cfg.InitialBackoff)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultWebhookTimeout
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultWebhookQueueSize
+	}
+	if cfg.Log == nil {
+		cfg.Log = log.NewNopLogger()
+	}
+
+	return &WebhookDispatcher{
+		store:          newWebhookStore(kv),
+		source:         source,
+		client:         newWebhookClient(cfg.Timeout, cfg.AllowPrivateNetworks),
+		log:            cfg.Log,
+		secretKey:      cfg.SecretKey,
+		maxAttempts:    cfg.MaxAttempts,
+		initialBackoff: cfg.InitialBackoff,
+		maxBackoff:     cfg.MaxBackoff,
+		queueSize:      cfg.QueueSize,
+		workers:        make(map[string]*webhookWorker),
+	}
+}
+
+// Run delivers the events until the context is canceled
+func (d *WebhookDispatcher) Run(ctx context.Context) error {
+	events, err := d.source.WatchWriteEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("watching write events: %w", err)
+	}
+
+	// the subscriptions saved while starting are either listed here or started by their write event
+	d.mu.Lock()
+	subscriptions, err := d.listSubscriptions(ctx)
+	if err != nil {
+		d.mu.Unlock()
+		return fmt.Errorf("listing webhook subscriptions: %w", err)
+	}
+	d.ctx = ctx
+	for _, sub := range subscriptions {
+		d.startWorker(sub)
+	}
+	d.mu.Unlock()
+	d.log.Info("webhook dispatcher started", "subscriptions", len(subscriptions))
+
+	defer func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		for key, w := range d.workers {
+			w.stop()
+			delete(d.workers, key)
+		}
+		d.ctx = nil
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt, ok := <-events:
+			if !ok {
+				return nil
+			}
+			// Skip events during batch updates
+			if evt == nil || evt.Key == nil || evt.PreviousRV < 0 {
+				continue
+			}
+			if evt.Key.Group == webhookSubscriptionResource.Group && evt.Key.Resource == webhookSubscriptionResource.Resource {
+				d.onSubscriptionEvent(ctx, evt)
+				continue
+			}
+			d.mu.Lock()
+			for _, w := range d.workers {
+				if w.accepts(evt.Key) {
+					w.enqueue(evt)
+				}
+			}
+			d.mu.Unlock()
+		}
+	}
+}
+
+// onSubscriptionEvent restarts the worker of a saved subscription, a new subscription receives the events
+// written after it is created and an updated subscription continues from its cursor. The cursor and the
+// dead letters of a deleted subscription are removed.
+func (d *WebhookDispatcher) onSubscriptionEvent(ctx context.Context, evt *WrittenEvent) {
+	namespace, name := evt.Key.Namespace, evt.Key.Name
+
+	d.mu.Lock()
+	d.stopWorker(namespace, name)
+	if evt.Type == resourcepb.WatchEvent_DELETED {
+		d.mu.Unlock()
+		d.cleanupSubscription(ctx, namespace, name)
+		return
+	}
+	defer d.mu.Unlock()
+
+	sub, err := d.parseSubscription(evt.Value)
+	if err != nil {
+		d.log.Error("invalid webhook subscription", "namespace", namespace, "name", name, "error", err)
+		return
+	}
+	d.startWorker(sub)
+}
+
+func (d *WebhookDispatcher) cleanupSubscription(ctx context.Context, namespace, name string) {
+	if err := d.store.deleteCursor(ctx, namespace, name); err != nil {
+		d.log.Warn("failed to delete webhook cursor", "namespace", namespace, "name", name, "error", err)
+	}
+	if err := d.deleteDeadLetters(ctx, namespace, name); err != nil {
+		d.log.Warn("failed to delete webhook dead letters", "namespace", namespace, "name", name, "error", err)
+	}
+}
+
+// must be called with the lock held
+func (d *WebhookDispatcher) startWorker(sub webhookSubscription) {
+	if d.ctx == nil {
+		return // not running, the worker starts with the dispatcher
+	}
+	if sub.Group == webhookSubscriptionResource.Group {
+		// the dead letters are written in this group
+		d.log.Error("webhook subscriptions to the storage group are not supported", "namespace", sub.Namespace, "name", sub.Name)
+		return
+	}
+	selector, err := labels.Parse(sub.LabelSelector)
+	if err != nil {
+		d.log.Error("invalid webhook subscription selector", "namespace", sub.Namespace, "name", sub.Name, "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(d.ctx)
+	w := &webhookWorker{
+		d:        d,
+		sub:      sub,
+		selector: selector,
+		queue:    make(chan *WrittenEvent, d.queueSize),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	d.workers[webhookWorkerKey(sub.Namespace, sub.Name)] = w
+	go w.run(ctx)
+}
+
+// must be called with the lock held
+func (d *WebhookDispatcher) stopWorker(namespace, name string) {
+	key := webhookWorkerKey(namespace, name)
+	if w, ok := d.workers[key]; ok {
+		w.stop()
+		delete(d.workers, key)
+	}
+}
+
+func webhookWorkerKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// listSubscriptions returns the subscriptions of all the namespaces
+func (d *WebhookDispatcher) listSubscriptions(ctx context.Context) ([]webhookSubscription, error) {
+	subscriptions := []webhookSubscription{}
+	_, err := d.source.ListIterator(ctx, &resourcepb.ListRequest{
+		Options: &resourcepb.ListOptions{
+			Key: &resourcepb.ResourceKey{
+				Group:    webhookSubscriptionResource.Group,
+				Resource: webhookSubscriptionResource.Resource,
+			},
+		},
+	}, func(iter ListIterator) error {
+		for iter.Next() {
+			if err := iter.Error(); err != nil {
+				return err
+			}
+			sub, err := d.parseSubscription(iter.Value())
+			if err != nil {
+				d.log.Error("invalid webhook subscription", "namespace", iter.Namespace(), "name", iter.Name(), "error", err)
+				continue
+			}
+			subscriptions = append(subscriptions, sub)
+		}
+		return iter.Error()
+	})
+	return subscriptions, err
+}
+
+// parseSubscription reads a saved subscription and decrypts its sink secret
+func (d *WebhookDispatcher) parseSubscription(value []byte) (webhookSubscription, error) {
+	obj := &storageapi.WebhookSubscription{}
+	if err := json.Unmarshal(value, obj); err != nil {
+		return webhookSubscription{}, err
+	}
+	sub := webhookSubscription{
+		Namespace:     obj.Namespace,
+		Name:          obj.Name,
+		Group:         obj.Spec.Group,
+		Resource:      obj.Spec.Resource,
+		LabelSelector: obj.Spec.LabelSelector,
+		Sink:          obj.Spec.Sink,
+	}
+	sub.Sink.Secret = ""
+	if sub.Sink.EncryptedSecret != "" {
+		secret, err := decryptWebhookSecret(sub.Sink.EncryptedSecret, d.secretKey)
+		if err != nil {
+			return sub, fmt.Errorf("decrypting the sink secret: %w", err)
+		}
+		sub.Sink.Secret = secret
+	}
+	return sub, nil
+}
+
+// saveDeadLetter writes an event that could not be delivered as a dead letter resource
+func (d *WebhookDispatcher) saveDeadLetter(ctx context.Context, sub webhookSubscription, evt *WebhookEvent, lastErr error) error {
+	event := map[string]any{}
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	now := metav1.NewTime(time.Now().UTC())
+	letter := &storageapi.WebhookDeadLetter{
+		TypeMeta: storageapi.WebhookDeadLetterResourceInfo.TypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("%s-%d", sub.Name, evt.ResourceVersion),
+			Namespace:         sub.Namespace,
+			UID:               types.UID(uuid.New().String()),
+			CreationTimestamp: now,
+			Labels:            map[string]string{storageapi.LabelWebhookSubscription: sub.Name},
+		},
+		Spec: storageapi.WebhookDeadLetterSpec{
+			Subscription: sub.Name,
+			Event:        common.Unstructured{Object: event},
+			Attempts:     d.maxAttempts,
+			LastError:    lastErr.Error(),
+			FailedAt:     now,
+		},
+	}
+	value, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+	obj, err := utils.MetaAccessor(letter)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.source.WriteEvent(ctx, WriteEvent{
+		Type: resourcepb.WatchEvent_ADDED,
+		Key: &resourcepb.ResourceKey{
+			Namespace: letter.Namespace,
+			Group:     webhookDeadLetterResource.Group,
+			Resource:  webhookDeadLetterResource.Resource,
+			Name:      letter.Name,
+		},
+		GUID:   string(letter.UID),
+		Value:  value,
+		Object: obj,
+	})
+	if errors.Is(err, ErrResourceAlreadyExists) {
+		return nil // the event was delivered again after a restart, or by another replica
+	}
+	return err
+}
+
+// deleteDeadLetters removes the dead letters of a deleted subscription
+func (d *WebhookDispatcher) deleteDeadLetters(ctx context.Context, namespace, name string) error {
+	type deadLetter struct {
+		name  string
+		rv    int64
+		value []byte
+	}
+	letters := []deadLetter{}
+	_, err := d.source.ListIterator(ctx, &resourcepb.ListRequest{
+		Options: &resourcepb.ListOptions{
+			Key: &resourcepb.ResourceKey{
+				Namespace: namespace,
+				Group:     webhookDeadLetterResource.Group,
+				Resource:  webhookDeadLetterResource.Resource,
+			},
+		},
+	}, func(iter ListIterator) error {
+		for iter.Next() {
+			if err := iter.Error(); err != nil {
+				return err
+			}
+			obj := &metav1.PartialObjectMetadata{}
+			if err := json.Unmarshal(iter.Value(), obj); err != nil {
+				continue
+			}
+			if obj.Labels[storageapi.LabelWebhookSubscription] == name {
+				letters = append(letters, deadLetter{name: iter.Name(), rv: iter.ResourceVersion(), value: iter.Value()})
+			}
+		}
+		return iter.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, letter := range letters {
+		marker := &unstructured.Unstructured{}
+		if err := json.Unmarshal(letter.value, marker); err != nil {
+			return err
+		}
+		obj, err := utils.MetaAccessor(marker)
+		if err != nil {
+			return err
+		}
+		_, err = d.source.WriteEvent(ctx, WriteEvent{
+			Type: resourcepb.WatchEvent_DELETED,
+			Key: &resourcepb.ResourceKey{
+				Namespace: namespace,
+				Group:     webhookDeadLetterResource.Group,
+				Resource:  webhookDeadLetterResource.Resource,
+				Name:      letter.name,
+			},
+			PreviousRV: letter.rv,
+			GUID:       uuid.New().String(),
+			Value:      letter.value,
+			Object:     obj,
+			ObjectOld:  obj,
+		})
+		if err != nil && !apierrors.IsConflict(err) && !apierrors.IsNotFound(err) {
+			return err // conflicts happen when another replica deletes it first
+		}
+	}
+	return nil
+}
+
+// webhookWorker delivers the events of a single subscription, in order
+type webhookWorker struct {
+	d        *WebhookDispatcher
+	sub      webhookSubscription
+	selector labels.Selector
+	queue    chan *WrittenEvent
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	// set when events were dropped because the queue was full
+	lagging atomic.Bool
+
+	// the resource version of the last handled event
+	cursor int64
+}
+
+func (w *webhookWorker) accepts(key *resourcepb.ResourceKey) bool {
+	return key.Namespace == w.sub.Namespace && key.Group == w.sub.Group && key.Resource == w.sub.Resource
+}
+
+func (w *webhookWorker) enqueue(evt *WrittenEvent) {
+	select {
+	case w.queue <- evt:
+	default:
+		// the dropped events are read again from the storage
+		w.lagging.Store(true)
+	}
+}
+
+func (w *webhookWorker) stop() {
+	w.cancel()
+	<-w.done
+}
+
+func (w *webhookWorker) run(ctx context.Context) {
+	defer close(w.done)
+	logger := w.d.log.New("namespace", w.sub.Namespace, "subscription", w.sub.Name)
+
+	cursor, err := w.d.store.getCursor(ctx, w.sub.Namespace, w.sub.Name)
+	if err != nil {
+		logger.Error("failed to read webhook cursor", "error", err)
+		return
+	}
+	w.cursor = cursor
+	w.lagging.Store(cursor > 0) // catch up with the events written while the dispatcher was not running
+
+	for {
+		if w.lagging.Swap(false) {
+			if err := w.catchUp(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				logger.Error("failed to catch up webhook subscription", "error", err)
+				w.lagging.Store(true)
+			}
+		}
+
+		var retry <-chan time.Time
+		if w.lagging.Load() {
+			retry = time.After(w.d.initialBackoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-retry:
+		case evt := <-w.queue:
+			if evt.ResourceVersion <= w.cursor {
+				continue // already handled while catching up
+			}
+			if err := w.handle(ctx, evt); err != nil {
+				if ctx.Err() != nil {
+					return // delivered again on restart
+				}
+				logger.Error("failed to handle webhook event", "rv", evt.ResourceVersion, "error", err)
+				w.lagging.Store(true) // read it again from the cursor
+			}
+		}
+	}
+}
+
+// catchUp sends the latest changes of the resources modified after the cursor
+func (w *webhookWorker) catchUp(ctx context.Context) error {
+	if w.cursor <= 0 {
+		return nil
+	}
+	key := NamespacedResource{Namespace: w.sub.Namespace, Group: w.sub.Group, Resource: w.sub.Resource}
+	_, seq := w.d.source.ListModifiedSince(ctx, key, w.cursor, nil)
+
+	modified := []*WrittenEvent{}
+	for m, err := range seq {
+		if err != nil {
+			return err
+		}
+		if m.ResourceVersion <= w.cursor {
+			continue
+		}
+		k := m.Key
+		modified = append(modified, &WrittenEvent{
+			Type:            m.Action,
+			Key:             &k,
+			Value:           m.Value,
+			ResourceVersion: m.ResourceVersion,
+		})
+	}
+	slices.SortFunc(modified, func(a, b *WrittenEvent) int {
+		return cmp.Compare(a.ResourceVersion, b.ResourceVersion)
+	})
+
+	for _, evt := range modified {
+		if err := w.handle(ctx, evt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handle delivers an event matching the selector, and moves the cursor once it is delivered or dead lettered
+func (w *webhookWorker) handle(ctx context.Context, evt *WrittenEvent) error {
+	if w.matches(evt) {
+		if err := w.deliver(ctx, evt); err != nil {
+			return err
+		}
+	}
+	if err := w.d.store.saveCursor(ctx, w.sub.Namespace, w.sub.Name, evt.ResourceVersion); err != nil {
+		return fmt.Errorf("saving webhook cursor: %w", err)
+	}
+	w.cursor = evt.ResourceVersion
+	return nil
+}
+
+func (w *webhookWorker) matches(evt *WrittenEvent) bool {
+	if w.selector.Empty() {
+		return true
+	}
+	obj := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(evt.Value, obj); err != nil {
+		return false
+	}
+	return w.selector.Matches(labels.Set(obj.Labels))
+}
+
+// deliver retries the event with an exponential backoff and saves it as a dead letter after the last attempt
+func (w *webhookWorker) deliver(ctx context.Context, evt *WrittenEvent) error {
+	payload := newWebhookEvent(evt)
+	backoff := w.d.initialBackoff
+
+	var err error
+	for attempt := 1; attempt <= w.d.maxAttempts; attempt++ {
+		if err = deliverWebhookEvent(ctx, w.d.client, w.sub.Sink, &payload); err == nil {
+			return nil
+		}
+		if attempt == w.d.maxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, w.d.maxBackoff)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	w.d.log.Warn("webhook event dead lettered", "namespace", w.sub.Namespace, "subscription", w.sub.Name, "rv", evt.ResourceVersion, "error", err)
+	if err := w.d.saveDeadLetter(ctx, w.sub, &payload, err); err != nil {
+		return fmt.Errorf("saving webhook dead letter: %w", err)
+	}
+	return nil
+}
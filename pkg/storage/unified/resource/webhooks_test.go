@@ -0,0 +1,414 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	storageapi "github.com/grafana/grafana/pkg/apis/storage/v0alpha1"
+	"github.com/grafana/grafana/pkg/storage/unified/resource/kv"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+type fakeWebhookSource struct {
+	events   chan *WrittenEvent
+	modified []*ModifiedResource
+
+	mu      sync.Mutex
+	objects map[string]testListItem
+	rv      int64
+}
+
+func (f *fakeWebhookSource) WatchWriteEvents(ctx context.Context) (<-chan *WrittenEvent, error) {
+	return f.events, nil
+}
+
+func (f *fakeWebhookSource) ListModifiedSince(ctx context.Context, key NamespacedResource, sinceRv int64, _ *time.Time) (int64, iter.Seq2[*ModifiedResource, error]) {
+	return 0, func(yield func(*ModifiedResource, error) bool) {
+		for _, m := range f.modified {
+			if m.Key.Namespace == key.Namespace && m.Key.Group == key.Group && m.Key.Resource == key.Resource && m.ResourceVersion > sinceRv {
+				if !yield(m, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (f *fakeWebhookSource) ListIterator(ctx context.Context, req *resourcepb.ListRequest, cb func(ListIterator) error) (int64, error) {
+	key := req.Options.Key
+	prefix := key.Group + "/" + key.Resource + "/"
+	if key.Namespace != "" {
+		prefix += key.Namespace + "/"
+	}
+
+	f.mu.Lock()
+	keys := slices.Sorted(maps.Keys(f.objects))
+	items := []testListItem{}
+	for _, k := range keys {
+		if strings.HasPrefix(k, prefix) {
+			items = append(items, f.objects[k])
+		}
+	}
+	f.mu.Unlock()
+	return f.rv, cb(&testListIterator{items: items, idx: -1})
+}
+
+func (f *fakeWebhookSource) WriteEvent(ctx context.Context, event WriteEvent) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	k := event.Key
+	key := k.Group + "/" + k.Resource + "/" + k.Namespace + "/" + k.Name
+	_, exists := f.objects[key]
+	switch event.Type {
+	case resourcepb.WatchEvent_ADDED:
+		if exists {
+			return 0, ErrResourceAlreadyExists
+		}
+	case resourcepb.WatchEvent_DELETED:
+		if !exists {
+			return 0, conflictError(event, "resource not found")
+		}
+		delete(f.objects, key)
+		return f.rv, nil
+	}
+	f.rv++
+	f.put(k, f.rv, event.Value)
+	return f.rv, nil
+}
+
+// must be called with the lock held
+func (f *fakeWebhookSource) put(k *resourcepb.ResourceKey, rv int64, value []byte) {
+	if f.objects == nil {
+		f.objects = map[string]testListItem{}
+	}
+	f.objects[k.Group+"/"+k.Resource+"/"+k.Namespace+"/"+k.Name] = testListItem{namespace: k.Namespace, name: k.Name, rv: rv, value: value}
+}
+
+func (f *fakeWebhookSource) deadLetters(t *testing.T) []storageapi.WebhookDeadLetter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	letters := []storageapi.WebhookDeadLetter{}
+	for _, k := range slices.Sorted(maps.Keys(f.objects)) {
+		if strings.HasPrefix(k, webhookDeadLetterResource.Group+"/"+webhookDeadLetterResource.Resource+"/") {
+			letter := storageapi.WebhookDeadLetter{}
+			require.NoError(t, json.Unmarshal(f.objects[k].value, &letter))
+			letters = append(letters, letter)
+		}
+	}
+	return letters
+}
+
+const testWebhookSecretKey = "test-secret-key"
+
+func testWebhookSubscription(t *testing.T, name, url, secret, labelSelector string) *storageapi.WebhookSubscription {
+	sub := &storageapi.WebhookSubscription{
+		TypeMeta:   storageapi.WebhookSubscriptionResourceInfo.TypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: storageapi.WebhookSubscriptionSpec{
+			Group:         "dashboard.grafana.app",
+			Resource:      "dashboards",
+			LabelSelector: labelSelector,
+			Sink:          storageapi.WebhookSink{Type: storageapi.WebhookSinkHTTP, URL: url},
+		},
+	}
+	if secret != "" {
+		encrypted, err := EncryptWebhookSecret(secret, testWebhookSecretKey)
+		require.NoError(t, err)
+		sub.Spec.Sink.EncryptedSecret = encrypted
+	}
+	return sub
+}
+
+// testSubscriptionEvent is the write event of a saved (or deleted) subscription
+func testSubscriptionEvent(t *testing.T, sub *storageapi.WebhookSubscription, eventType resourcepb.WatchEvent_Type, rv int64) *WrittenEvent {
+	value, err := json.Marshal(sub)
+	require.NoError(t, err)
+	return &WrittenEvent{
+		Type:            eventType,
+		Key:             &resourcepb.ResourceKey{Namespace: sub.Namespace, Group: webhookSubscriptionResource.Group, Resource: webhookSubscriptionResource.Resource, Name: sub.Name},
+		Value:           value,
+		ResourceVersion: rv,
+	}
+}
+
+func waitForWebhookWorkers(t *testing.T, d *WebhookDispatcher, count int) {
+	require.Eventually(t, func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return len(d.workers) == count
+	}, time.Second, 10*time.Millisecond)
+}
+
+type webhookReceiver struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	status   int
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	if r.status > 0 {
+		w.WriteHeader(r.status)
+	}
+}
+
+func (r *webhookReceiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+func testWebhookEvent(name string, rv int64, labels string) *WrittenEvent {
+	return &WrittenEvent{
+		Type:            resourcepb.WatchEvent_ADDED,
+		Key:             &resourcepb.ResourceKey{Namespace: "default", Group: "dashboard.grafana.app", Resource: "dashboards", Name: name},
+		Value:           []byte(`{"metadata":{"name":"` + name + `","labels":{` + labels + `}}}`),
+		ResourceVersion: rv,
+		Timestamp:       1700000000,
+	}
+}
+
+func TestWebhookStore(t *testing.T) {
+	testWebhookStore(t, setupBadgerKV(t))
+}
+
+func TestIntegrationWebhookStore(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+
+	testWebhookStore(t, setupSqlKV(t))
+}
+
+func testWebhookStore(t *testing.T, kv kv.KV) {
+	ctx := t.Context()
+	store := newWebhookStore(kv)
+
+	cursor, err := store.getCursor(ctx, "default", "audit")
+	require.NoError(t, err)
+	require.Zero(t, cursor)
+	require.NoError(t, store.saveCursor(ctx, "default", "audit", 1234))
+	require.NoError(t, store.saveCursor(ctx, "other", "audit", 10))
+	cursor, err = store.getCursor(ctx, "default", "audit")
+	require.NoError(t, err)
+	require.Equal(t, int64(1234), cursor)
+
+	require.NoError(t, store.deleteCursor(ctx, "default", "audit"))
+	require.NoError(t, store.deleteCursor(ctx, "default", "missing"))
+	cursor, err = store.getCursor(ctx, "default", "audit")
+	require.NoError(t, err)
+	require.Zero(t, cursor)
+	cursor, err = store.getCursor(ctx, "other", "audit")
+	require.NoError(t, err)
+	require.Equal(t, int64(10), cursor)
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	t.Run("delivers matching events and saves the cursor", func(t *testing.T) {
+		receiver := &webhookReceiver{}
+		srv := httptest.NewServer(receiver)
+		defer srv.Close()
+
+		source := &fakeWebhookSource{events: make(chan *WrittenEvent, 10)}
+		d := NewWebhookDispatcher(setupBadgerKV(t), source, WebhookConfig{SecretKey: testWebhookSecretKey, AllowPrivateNetworks: true})
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+		go func() { _ = d.Run(ctx) }()
+
+		source.events <- testSubscriptionEvent(t, testWebhookSubscription(t, "platform", srv.URL, "s3cr3t", "team=platform"), resourcepb.WatchEvent_ADDED, 1)
+		waitForWebhookWorkers(t, d, 1)
+
+		source.events <- testWebhookEvent("a", 10, `"team":"platform"`)
+		source.events <- testWebhookEvent("b", 11, `"team":"other"`)
+		source.events <- testWebhookEvent("c", 12, `"team":"platform"`)
+
+		require.Eventually(t, func() bool {
+			cursor, _ := d.store.getCursor(ctx, "default", "platform")
+			return cursor == 12
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, 2, receiver.count())
+
+		evt := WebhookEvent{}
+		require.NoError(t, json.Unmarshal(receiver.bodies[0], &evt))
+		require.Equal(t, "a", evt.Name)
+		require.Equal(t, "ADDED", evt.Type)
+		require.Equal(t, int64(10), evt.ResourceVersion)
+		require.Equal(t, evt.ID, receiver.requests[0].Header.Get(WebhookEventIDHeader))
+		require.True(t, VerifyWebhookSignature("s3cr3t", receiver.bodies[0], receiver.requests[0].Header.Get(WebhookSignatureHeader)))
+	})
+
+	t.Run("dead letters events after the last attempt", func(t *testing.T) {
+		receiver := &webhookReceiver{status: http.StatusInternalServerError}
+		srv := httptest.NewServer(receiver)
+		defer srv.Close()
+
+		source := &fakeWebhookSource{events: make(chan *WrittenEvent, 10)}
+		d := NewWebhookDispatcher(setupBadgerKV(t), source, WebhookConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, AllowPrivateNetworks: true})
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+		go func() { _ = d.Run(ctx) }()
+
+		sub := testWebhookSubscription(t, "failing", srv.URL, "", "")
+		sub.Spec.Sink.Type = storageapi.WebhookSinkCloudEvents
+		source.events <- testSubscriptionEvent(t, sub, resourcepb.WatchEvent_ADDED, 1)
+		waitForWebhookWorkers(t, d, 1)
+
+		source.events <- testWebhookEvent("a", 10, "")
+
+		var letters []storageapi.WebhookDeadLetter
+		require.Eventually(t, func() bool {
+			letters = source.deadLetters(t)
+			return len(letters) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, 3, receiver.count())
+		require.Equal(t, "failing-10", letters[0].Name)
+		require.Equal(t, "default", letters[0].Namespace)
+		require.Equal(t, "failing", letters[0].Labels[storageapi.LabelWebhookSubscription])
+		require.Equal(t, "failing", letters[0].Spec.Subscription)
+		require.Equal(t, 3, letters[0].Spec.Attempts)
+		require.Equal(t, "a", letters[0].Spec.Event.Object["name"])
+		require.Contains(t, letters[0].Spec.LastError, "500")
+
+		require.Equal(t, cloudEventsContentType, receiver.requests[0].Header.Get("Content-Type"))
+		ce := map[string]any{}
+		require.NoError(t, json.Unmarshal(receiver.bodies[0], &ce))
+		require.Equal(t, "1.0", ce["specversion"])
+		require.Equal(t, "com.grafana.storage.resource.created", ce["type"])
+		require.Equal(t, "/apis/dashboard.grafana.app/namespaces/default/dashboards", ce["source"])
+		require.Equal(t, "a", ce["subject"])
+
+		// deleting the subscription removes its cursor and its dead letters
+		source.events <- testSubscriptionEvent(t, sub, resourcepb.WatchEvent_DELETED, 2)
+		waitForWebhookWorkers(t, d, 0)
+		require.Eventually(t, func() bool {
+			return len(source.deadLetters(t)) == 0
+		}, 5*time.Second, 10*time.Millisecond)
+		cursor, err := d.store.getCursor(ctx, "default", "failing")
+		require.NoError(t, err)
+		require.Zero(t, cursor)
+	})
+
+	t.Run("catches up from the cursor on start", func(t *testing.T) {
+		receiver := &webhookReceiver{}
+		srv := httptest.NewServer(receiver)
+		defer srv.Close()
+
+		kv := setupBadgerKV(t)
+		store := newWebhookStore(kv)
+		require.NoError(t, store.saveCursor(t.Context(), "default", "gitops", 10))
+
+		key := resourcepb.ResourceKey{Namespace: "default", Group: "dashboard.grafana.app", Resource: "dashboards"}
+		modified := func(name string, rv int64) *ModifiedResource {
+			k := key
+			k.Name = name
+			return &ModifiedResource{Action: resourcepb.WatchEvent_MODIFIED, Key: k, Value: []byte(`{}`), ResourceVersion: rv}
+		}
+		source := &fakeWebhookSource{
+			events:   make(chan *WrittenEvent, 10),
+			modified: []*ModifiedResource{modified("old", 5), modified("b", 30), modified("a", 20)},
+		}
+		saved := testSubscriptionEvent(t, testWebhookSubscription(t, "gitops", srv.URL, "", ""), resourcepb.WatchEvent_ADDED, 1)
+		source.put(saved.Key, saved.ResourceVersion, saved.Value)
+
+		d := NewWebhookDispatcher(kv, source, WebhookConfig{AllowPrivateNetworks: true})
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+		go func() { _ = d.Run(ctx) }()
+
+		require.Eventually(t, func() bool {
+			cursor, _ := store.getCursor(ctx, "default", "gitops")
+			return cursor == 30
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, 2, receiver.count())
+
+		names := []string{}
+		for _, body := range receiver.bodies {
+			evt := WebhookEvent{}
+			require.NoError(t, json.Unmarshal(body, &evt))
+			names = append(names, evt.Name)
+		}
+		require.Equal(t, []string{"a", "b"}, names)
+	})
+
+	t.Run("ignores subscriptions that cannot be decrypted", func(t *testing.T) {
+		source := &fakeWebhookSource{events: make(chan *WrittenEvent, 10)}
+		d := NewWebhookDispatcher(setupBadgerKV(t), source, WebhookConfig{SecretKey: testWebhookSecretKey, AllowPrivateNetworks: true})
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+		go func() { _ = d.Run(ctx) }()
+
+		invalid := testWebhookSubscription(t, "secret", "http://localhost/hook", "", "")
+		invalid.Spec.Sink.EncryptedSecret = "not encrypted"
+		source.events <- testSubscriptionEvent(t, invalid, resourcepb.WatchEvent_ADDED, 1)
+		source.events <- testSubscriptionEvent(t, testWebhookSubscription(t, "plain", "http://localhost/hook", "", ""), resourcepb.WatchEvent_ADDED, 2)
+		waitForWebhookWorkers(t, d, 1)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		require.Contains(t, d.workers, webhookWorkerKey("default", "plain"))
+	})
+}
+
+func TestWebhookSecretEncryption(t *testing.T) {
+	encrypted, err := EncryptWebhookSecret("s3cr3t", testWebhookSecretKey)
+	require.NoError(t, err)
+	require.NotContains(t, encrypted, "s3cr3t")
+
+	secret, err := decryptWebhookSecret(encrypted, testWebhookSecretKey)
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t", secret)
+
+	secret, err = decryptWebhookSecret(encrypted, "another-key")
+	require.NoError(t, err)
+	require.NotEqual(t, "s3cr3t", secret)
+
+	_, err = decryptWebhookSecret("not encrypted", testWebhookSecretKey)
+	require.Error(t, err)
+}
+
+func TestWebhookClientPrivateNetworks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	evt := &WebhookEvent{ID: "1"}
+
+	t.Run("refuses sinks on private networks", func(t *testing.T) {
+		client := newWebhookClient(time.Second, false)
+		for _, url := range []string{srv.URL, "http://169.254.169.254/latest/meta-data", "http://[::1]:8080", "http://10.0.0.1"} {
+			err := deliverWebhookEvent(context.Background(), client, storageapi.WebhookSink{Type: storageapi.WebhookSinkHTTP, URL: url}, evt)
+			require.ErrorIs(t, err, errWebhookSinkNotAllowed, url)
+		}
+	})
+
+	t.Run("delivers to private networks when allowed", func(t *testing.T) {
+		client := newWebhookClient(time.Second, true)
+		err := deliverWebhookEvent(context.Background(), client, storageapi.WebhookSink{Type: storageapi.WebhookSinkHTTP, URL: srv.URL}, evt)
+		require.NoError(t, err)
+	})
+
+	t.Run("allows public addresses", func(t *testing.T) {
+		for _, address := range []string{"93.184.216.34:443", "[2606:2800:220:1:248:1893:25c8:1946]:80"} {
+			require.NoError(t, denyPrivateNetworks("tcp", address, nil), address)
+		}
+		require.ErrorIs(t, denyPrivateNetworks("tcp", "[::ffff:127.0.0.1]:80", nil), errWebhookSinkNotAllowed)
+	})
+}
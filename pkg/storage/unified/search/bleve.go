@@ -86,6 +86,10 @@ type BleveOptions struct {
 	// Map "group/kind" -> list of selectable fields. Keys must be lower-case.
 	// Only given fields are indexed (have mapping).
 	SelectableFieldsForKinds map[string][]string
+
+	// Maximum number of typos (edit distance) allowed in the words of text queries, between 0 and 2.
+	// Short words always match exactly. 0 disables fuzzy matching and "did you mean" suggestions.
+	SearchFuzziness int
 }
 
 type bleveBackend struct {
@@ -124,6 +128,10 @@ func NewBleveBackend(opts BleveOptions, indexMetrics *resource.BleveIndexMetrics
 		return nil, fmt.Errorf("bleve root is configured against a file (not folder)")
 	}
 
+	if opts.SearchFuzziness < 0 || opts.SearchFuzziness > maxSearchFuzziness {
+		return nil, fmt.Errorf("search fuzziness must be between 0 and %d", maxSearchFuzziness)
+	}
+
 	if opts.BuildVersion != "" {
 		// Don't allow storing invalid versions to the index.
 		_, err := semver.NewVersion(opts.BuildVersion)
@@ -760,6 +768,9 @@ type bleveIndex struct {
 	updaterFn         resource.UpdateFn
 	minUpdateInterval time.Duration
 
+	// Maximum edit distance of the fuzzy matches, 0 when disabled
+	fuzziness int
+
 	updaterMu       sync.Mutex
 	updaterCond     *sync.Cond         // Used to signal the updater goroutine that there is work to do, or updater is no longer enabled and should stop. Also used by updater itself to stop early if there's no work to be done.
 	updaterShutdown bool               // When set to true, index is getting closed and updater is no longer going to update index.
@@ -794,6 +805,7 @@ func (b *bleveBackend) newBleveIndex(
 		logger:            logger,
 		updaterFn:         updaterFn,
 		minUpdateInterval: b.opts.IndexMinUpdateInterval,
+		fuzziness:         b.opts.SearchFuzziness,
 	}
 	bi.updaterCond = sync.NewCond(&bi.updaterMu)
 	if b.indexMetrics != nil {
@@ -1084,7 +1096,7 @@ func (b *bleveIndex) Search(
 
 	conversionStarts := time.Now()
 	// convert protobuf request to bleve request
-	searchrequest, e := b.toBleveSearchRequest(ctx, req, access)
+	searchrequest, e := b.toBleveSearchRequest(ctx, req, access, queryTextAnalyzer(b, federate))
 	if e != nil {
 		response.Error = e
 		return response, nil
@@ -1135,6 +1147,20 @@ func (b *bleveIndex) Search(
 		}
 		response.Facet[k] = f
 	}
+
+	// the searches finding enough documents don't scan the title words for suggestions
+	if b.fuzziness > 0 && res.Total <= suggestMaxHits && isTextQuery(req.Query) {
+		suggestions, err := b.suggest(req.Query)
+		if err != nil {
+			return nil, err
+		}
+		if suggestions != nil {
+			if response.Facet == nil {
+				response.Facet = make(map[string]*resourcepb.ResourceSearchResponse_Facet)
+			}
+			response.Facet[resource.SEARCH_FIELD_SUGGESTIONS] = suggestions
+		}
+	}
 	stats.AddResultsConversionTime(time.Since(resultsConversionStart))
 	return response, nil
 }
@@ -1213,7 +1239,7 @@ func (b *bleveIndex) getIndex(
 }
 
 // nolint:gocyclo
-func (b *bleveIndex) toBleveSearchRequest(ctx context.Context, req *resourcepb.ResourceSearchRequest, access authlib.AccessClient) (*bleve.SearchRequest, *resourcepb.ErrorResult) {
+func (b *bleveIndex) toBleveSearchRequest(ctx context.Context, req *resourcepb.ResourceSearchRequest, access authlib.AccessClient, textAnalyzer string) (*bleve.SearchRequest, *resourcepb.ErrorResult) {
 	ctx, span := tracer.Start(ctx, "search.bleveIndex.toBleveSearchRequest") //nolint:staticcheck,ineffassign // SA4006: ctx intentionally kept so future code added to this function inherits the traced span
 	defer span.End()
 
@@ -1281,6 +1307,15 @@ func (b *bleveIndex) toBleveSearchRequest(ctx context.Context, req *resourcepb.R
 		}
 	}
 
+	if len(req.Query) > 1 && slices.Contains(req.Fields, resource.SEARCH_FIELD_HIGHLIGHT) {
+		searchrequest.Highlight = bleve.NewHighlight()
+		searchrequest.Highlight.Fields = []string{
+			resource.SEARCH_FIELD_TITLE,
+			resource.SEARCH_FIELD_TAGS,
+			resource.SEARCH_FIELD_DESCRIPTION,
+		}
+	}
+
 	if len(req.Query) > 1 {
 		if strings.Contains(req.Query, "*") {
 			// wildcard query is expensive - should be used with caution
@@ -1302,11 +1337,23 @@ func (b *bleveIndex) toBleveSearchRequest(ctx context.Context, req *resourcepb.R
 					}, {
 						Name:  resource.SEARCH_FIELD_TITLE,
 						Type:  resourcepb.QueryFieldType_TEXT,
-						Boost: 2, // standard analyzer (with ngrams!)
+						Boost: 2, // text analyzer (with ngrams!)
 					}, {
 						Name:  resource.SEARCH_FIELD_TITLE_PHRASE,
 						Type:  resourcepb.QueryFieldType_TEXT,
-						Boost: 5, // standard analyzer
+						Boost: 5, // text analyzer
+					}, {
+						Name:  resource.SEARCH_FIELD_TAGS,
+						Type:  resourcepb.QueryFieldType_KEYWORD,
+						Boost: 1.5, // exact tag
+					}, {
+						Name:  resource.SEARCH_FIELD_DESCRIPTION,
+						Type:  resourcepb.QueryFieldType_TEXT,
+						Boost: 1,
+					}, {
+						Name:  resource.SEARCH_FIELD_PANEL_TITLE,
+						Type:  resourcepb.QueryFieldType_TEXT,
+						Boost: 0.5,
 					},
 				}
 			}
@@ -1317,10 +1364,19 @@ func (b *bleveIndex) toBleveSearchRequest(ctx context.Context, req *resourcepb.R
 					q := bleve.NewMatchQuery(removeSmallTerms(req.Query)) // removeSmallTerms should be part of the analyzer
 					q.SetBoost(float64(field.Boost))
 					q.SetField(field.Name)
-					q.Analyzer = standard.Name               // analyze the text
+					q.Analyzer = textAnalyzer                // analyze the text
 					q.Operator = query.MatchQueryOperatorAnd // all terms must match
 					disjoin.AddQuery(q)
 
+					// the same terms with typos, scored lower than the exact matches
+					if b.fuzziness > 0 {
+						fuzzy := newFuzzyQuery(b.index.Mapping().AnalyzerNamed(textAnalyzer), removeSmallTerms(req.Query), field.Name, b.fuzziness)
+						if fuzzy != nil {
+							fuzzy.SetBoost(float64(field.Boost) * fuzzyBoost)
+							disjoin.AddQuery(fuzzy)
+						}
+					}
+
 				case resourcepb.QueryFieldType_KEYWORD:
 					q := bleve.NewMatchQuery(req.Query)
 					q.SetBoost(float64(field.Boost))
@@ -1797,6 +1853,15 @@ func (b *bleveIndex) hitsToTable(ctx context.Context, selectFields []string, hit
 				}
 			}
 
+			// The names of the referenced resources of a kind
+			if strings.HasPrefix(name, "reference.") {
+				f = &resourcepb.ResourceTableColumnDefinition{
+					Name:    name,
+					Type:    resourcepb.ResourceTableColumnDefinition_STRING,
+					IsArray: true,
+				}
+			}
+
 			// return nil, fmt.Errorf("unknown response field: " + name)
 			if f == nil {
 				continue // OK for now
@@ -1844,6 +1909,11 @@ func (b *bleveIndex) hitsToTable(ctx context.Context, selectFields []string, hit
 				if match.Expl != nil {
 					row.Cells[i], err = json.Marshal(match.Expl)
 				}
+
+			case resource.SEARCH_FIELD_HIGHLIGHT:
+				if len(match.Fragments) > 0 {
+					row.Cells[i], err = json.Marshal(match.Fragments)
+				}
 			case resource.SEARCH_FIELD_LEGACY_ID:
 				v := match.Fields[resource.SEARCH_FIELD_LABELS+"."+resource.SEARCH_FIELD_LEGACY_ID]
 				if v != nil {
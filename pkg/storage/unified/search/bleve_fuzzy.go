@@ -0,0 +1,233 @@
+package search
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/standard"
+	"github.com/blevesearch/bleve/v2/search/query"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+const (
+	// maxSearchFuzziness is the largest edit distance supported by bleve fuzzy queries
+	maxSearchFuzziness = 2
+
+	// fuzzyBoost lowers the score of the fuzzy matches, so the exact matches are listed first
+	fuzzyBoost = 0.5
+
+	// suggestMaxHits is the largest number of hits of a search returning suggestions,
+	// the searches finding more documents don't need them
+	suggestMaxHits = 3
+
+	// suggestMaxTerms caps the number of title words compared to the misspelled words of a query
+	suggestMaxTerms = 10000
+)
+
+// isTextQuery is true when the query is matched against the analyzed text fields
+func isTextQuery(q string) bool {
+	return len(q) > 1 && !strings.Contains(q, "*")
+}
+
+// queryTextAnalyzer returns the analyzer of the text queries.
+// Indexes built before the text analyzer was registered don't know it, so the queries keep
+// using the standard analyzer until all the searched indexes are rebuilt.
+func queryTextAnalyzer(b *bleveIndex, federate []resource.ResourceIndex) string {
+	indexes := []*bleveIndex{b}
+	for _, f := range federate {
+		if typed, ok := f.(*bleveIndex); ok {
+			indexes = append(indexes, typed)
+		}
+	}
+	for _, idx := range indexes {
+		if idx.index.Mapping().AnalyzerNamed(TEXT_ANALYZER) == nil {
+			return standard.Name
+		}
+	}
+	return TEXT_ANALYZER
+}
+
+// termFuzziness returns the number of typos allowed in a term: short terms must match exactly,
+// longer terms allow more typos, up to the configured fuzziness.
+func termFuzziness(term string, fuzziness int) int {
+	switch n := utf8.RuneCountInString(term); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return min(1, fuzziness)
+	default:
+		return min(2, fuzziness)
+	}
+}
+
+// newFuzzyQuery returns a query matching the documents where the field contains all the terms of the text,
+// allowing typos in the longer terms. Returns nil when no term is long enough to allow typos.
+func newFuzzyQuery(analyzer analysis.Analyzer, text string, field string, fuzziness int) *query.ConjunctionQuery {
+	if analyzer == nil {
+		return nil
+	}
+
+	fuzzy := false
+	queries := []query.Query{}
+	for _, token := range analyzer.Analyze([]byte(text)) {
+		term := string(token.Term)
+		distance := termFuzziness(term, fuzziness)
+		if distance == 0 {
+			q := bleve.NewTermQuery(term)
+			q.SetField(field)
+			queries = append(queries, q)
+			continue
+		}
+
+		fuzzy = true
+		q := bleve.NewFuzzyQuery(term)
+		q.SetFuzziness(distance)
+		q.SetField(field)
+		queries = append(queries, q)
+	}
+	if !fuzzy {
+		return nil
+	}
+	return bleve.NewConjunctionQuery(queries...)
+}
+
+type suggestedTerm struct {
+	term     string
+	distance int
+	count    uint64
+}
+
+// suggest returns the "did you mean" facet of a query. The words of the query that are not found in any title,
+// even as part of a word, are replaced by the most frequent title word within the allowed number of typos.
+// Returns nil when all the words exist, or no replacement is found.
+// Only the words of this index are suggested, not the ones of the federated indexes.
+func (b *bleveIndex) suggest(q string) (*resourcepb.ResourceSearchResponse_Facet, error) {
+	analyzer := b.index.Mapping().AnalyzerNamed(SUGGEST_ANALYZER)
+	if analyzer == nil {
+		return nil, nil // built before suggestions were supported
+	}
+
+	words := []string{}
+	misspelled := map[string]*suggestedTerm{}
+	for _, token := range analyzer.Analyze([]byte(q)) {
+		if token.Type == analysis.Ideographic {
+			return nil, nil // CJK text has no separator, so the query can't be rebuilt from its words
+		}
+		word := string(token.Term)
+		words = append(words, word)
+		if termFuzziness(word, b.fuzziness) == 0 {
+			continue
+		}
+		found, err := b.hasSuggestPrefix(word)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			misspelled[word] = nil
+		}
+	}
+	if len(misspelled) == 0 {
+		return nil, nil
+	}
+
+	dict, err := b.index.FieldDict(resource.SEARCH_FIELD_TITLE_SUGGEST)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = dict.Close() }()
+	// the dictionary is sorted, so the words past the cap are never suggested in large indexes
+	for scanned := 0; scanned < suggestMaxTerms && len(misspelled) > 0; scanned++ {
+		entry, err := dict.Next()
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			break
+		}
+		for word, best := range misspelled {
+			if strings.Contains(entry.Term, word) {
+				delete(misspelled, word) // matches the middle of a word, like the title ngrams
+				continue
+			}
+			limit := termFuzziness(word, b.fuzziness)
+			distance := editDistance(word, entry.Term, limit)
+			if distance > limit {
+				continue
+			}
+			if best == nil || distance < best.distance || (distance == best.distance && entry.Count > best.count) {
+				misspelled[word] = &suggestedTerm{term: entry.Term, distance: distance, count: entry.Count}
+			}
+		}
+	}
+
+	replaced := false
+	count := uint64(math.MaxInt64)
+	for i, word := range words {
+		if best := misspelled[word]; best != nil {
+			words[i] = best.term
+			count = min(count, best.count)
+			replaced = true
+		}
+	}
+	if !replaced {
+		return nil, nil
+	}
+	return &resourcepb.ResourceSearchResponse_Facet{
+		Field: resource.SEARCH_FIELD_TITLE_SUGGEST,
+		Total: 1,
+		Terms: []*resourcepb.ResourceSearchResponse_TermFacet{{
+			Term:  strings.Join(words, " "),
+			Count: int64(count), // the number of titles with the least frequent suggested word
+		}},
+	}, nil
+}
+
+// hasSuggestPrefix is true when a title word starts with the prefix
+func (b *bleveIndex) hasSuggestPrefix(prefix string) (bool, error) {
+	dict, err := b.index.FieldDictPrefix(resource.SEARCH_FIELD_TITLE_SUGGEST, []byte(prefix))
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = dict.Close() }()
+	entry, err := dict.Next()
+	if err != nil {
+		return false, err
+	}
+	return entry != nil, nil
+}
+
+// editDistance returns the Levenshtein distance between a and b, or limit+1 when it exceeds the limit
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra)-len(rb) > limit || len(rb)-len(ra) > limit {
+		return limit + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, cur = cur, prev
+	}
+	return min(prev[len(rb)], limit+1)
+}
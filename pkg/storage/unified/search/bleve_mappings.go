@@ -48,17 +48,30 @@ func getBleveDocMappings(fields resource.SearchableDocumentFields, selectableFie
 
 	// mapping for title to search on words/tokens larger than the ngram size
 	titleWordMapping := bleve.NewTextFieldMapping()
-	titleWordMapping.Analyzer = standard.Name
+	titleWordMapping.Analyzer = TEXT_ANALYZER
 	titleWordMapping.Store = true
+
+	// the words of the title in their own field, without ngrams, to look up "did you mean" suggestions
+	titleSuggestMapping := &mapping.FieldMapping{
+		Name:               resource.SEARCH_FIELD_TITLE_SUGGEST,
+		Type:               "text",
+		Analyzer:           SUGGEST_ANALYZER,
+		Store:              false,
+		Index:              true,
+		IncludeTermVectors: false,
+		IncludeInAll:       false,
+		DocValues:          false,
+	}
 	// NOTE: this causes 3 title fields in the response
-	mapper.AddFieldMappingsAt(resource.SEARCH_FIELD_TITLE, titleWordMapping, titleSearchMapping, titlePhraseMapping)
+	mapper.AddFieldMappingsAt(resource.SEARCH_FIELD_TITLE, titleWordMapping, titleSearchMapping, titlePhraseMapping, titleSuggestMapping)
 
 	descriptionMapping := &mapping.FieldMapping{
 		Name:               resource.SEARCH_FIELD_DESCRIPTION,
 		Type:               "text",
+		Analyzer:           TEXT_ANALYZER,
 		Store:              true,
 		Index:              true,
-		IncludeTermVectors: false,
+		IncludeTermVectors: true, // for highlighting
 		IncludeInAll:       false,
 		DocValues:          false,
 	}
@@ -70,7 +83,7 @@ func getBleveDocMappings(fields resource.SearchableDocumentFields, selectableFie
 		Analyzer:           keyword.Name,
 		Store:              true,
 		Index:              true,
-		IncludeTermVectors: false,
+		IncludeTermVectors: true, // for highlighting
 		IncludeInAll:       true,
 		DocValues:          false,
 	})
@@ -167,6 +180,45 @@ func getBleveDocMappings(fields resource.SearchableDocumentFields, selectableFie
 	referenceMapper.DefaultAnalyzer = keyword.Name
 	mapper.AddSubDocumentMapping("reference", referenceMapper)
 
+	// Nested panels (values are flattened, eg panels.type holds the types of all panels)
+	// The panels are returned from the JSON stored in panels_info
+	panels := bleve.NewDocumentStaticMapping()
+	panels.AddFieldMappingsAt("title", &mapping.FieldMapping{
+		Name:     "title",
+		Type:     "text",
+		Analyzer: TEXT_ANALYZER,
+		Store:    false,
+		Index:    true,
+	})
+	panels.AddFieldMappingsAt("type", &mapping.FieldMapping{
+		Name:     "type",
+		Type:     "text",
+		Analyzer: keyword.Name,
+		Store:    false,
+		Index:    true,
+	})
+	panels.AddFieldMappingsAt("datasource", &mapping.FieldMapping{
+		Name:     "datasource",
+		Type:     "text",
+		Analyzer: keyword.Name,
+		Store:    false,
+		Index:    true,
+	})
+	panels.AddFieldMappingsAt("query", &mapping.FieldMapping{
+		Name:     "query",
+		Type:     "text",
+		Analyzer: standard.Name, // metric names like http_requests_total are kept as a single token
+		Store:    false,
+		Index:    true,
+	})
+	mapper.AddSubDocumentMapping("panels", panels)
+	mapper.AddFieldMappingsAt(resource.SEARCH_FIELD_PANELS_INFO, &mapping.FieldMapping{
+		Name:  resource.SEARCH_FIELD_PANELS_INFO,
+		Type:  "text",
+		Store: true,
+		Index: false,
+	})
+
 	labelMapper := bleve.NewDocumentMapping()
 	mapper.AddSubDocumentMapping(resource.SEARCH_FIELD_LABELS, labelMapper)
 
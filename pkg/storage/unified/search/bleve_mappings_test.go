@@ -50,5 +50,40 @@ func TestDocumentMapping(t *testing.T) {
 
 	fmt.Printf("DOC: fields %d\n", len(doc.Fields))
 	fmt.Printf("DOC: size %d\n", doc.Size())
-	require.Equal(t, 20, len(doc.Fields))
+	require.Equal(t, 21, len(doc.Fields))
+}
+
+func TestPanelDocumentMapping(t *testing.T) {
+	mappings, err := search.GetBleveMappings(nil, nil)
+	require.NoError(t, err)
+	data := resource.IndexableDocument{
+		Title: "title",
+		Panels: []resource.IndexablePanel{{
+			ID:          1,
+			Title:       "Requests",
+			Type:        "timeseries",
+			DataSources: []string{"prom-uid"},
+			Queries:     []string{"rate(http_requests_total[5m])"},
+		}},
+	}
+	data.UpdateCopyFields()
+	require.JSONEq(t, `[{"id":1,"title":"Requests","type":"timeseries","datasource":["prom-uid"],"query":["rate(http_requests_total[5m])"]}]`, data.PanelsInfo)
+
+	doc := document.NewDocument("id")
+	err = mappings.MapDocument(doc, data)
+	require.NoError(t, err)
+
+	names := map[string]bool{}
+	for _, f := range doc.Fields {
+		names[f.Name()] = true
+	}
+	for _, name := range []string{
+		resource.SEARCH_FIELD_PANEL_TITLE,
+		resource.SEARCH_FIELD_PANEL_TYPE,
+		resource.SEARCH_FIELD_PANEL_DATASOURCE,
+		resource.SEARCH_FIELD_PANEL_QUERY,
+		resource.SEARCH_FIELD_PANELS_INFO,
+	} {
+		require.True(t, names[name], "missing field %s", name)
+	}
 }
@@ -2,6 +2,7 @@ package search_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -223,6 +224,142 @@ func TestCanSearchByTitle(t *testing.T) {
 	})
 }
 
+func TestFullTextSearch(t *testing.T) {
+	key := resource.NamespacedResource{
+		Namespace: "default",
+		Group:     "dashboard.grafana.app",
+		Resource:  "dashboards",
+	}
+	newIndex := func(t *testing.T, fuzziness int) resource.ResourceIndex {
+		return newTestDashboardsIndexWithOptions(t, search.BleveOptions{
+			Root:            t.TempDir(),
+			FileThreshold:   threshold, // use in-memory for tests
+			SearchFuzziness: fuzziness,
+		}, 2, noop)
+	}
+
+	t.Run("fuzzy search will match terms with typos", func(t *testing.T) {
+		index := newIndex(t, 2)
+		indexDocumentsWithTitles(t, index, key, map[string]string{
+			"name1": "Kubernetes cluster",
+			"name2": "Node exporter",
+		})
+
+		checkSearchQuery(t, index, newTestQuery("kubernetse"), []string{"name1"})
+		checkSearchQuery(t, index, newTestQuery("clustr"), []string{"name1"})
+		checkSearchQuery(t, index, newTestQuery("kubernetse clustr"), []string{"name1"})
+	})
+
+	t.Run("fuzzy search will rank exact matches first", func(t *testing.T) {
+		index := newIndex(t, 1)
+		indexDocumentsWithTitles(t, index, key, map[string]string{
+			"name1": "Alertz",
+			"name2": "Alerts",
+		})
+
+		checkSearchQuery(t, index, newTestQuery("alerts"), []string{"name2", "name1"})
+	})
+
+	t.Run("fuzzy search is disabled without fuzziness", func(t *testing.T) {
+		index := newIndex(t, 0)
+		indexDocumentsWithTitles(t, index, key, map[string]string{
+			"name1": "Kubernetes cluster",
+		})
+
+		checkSearchQuery(t, index, newTestQuery("kubernetse"), nil)
+	})
+
+	t.Run("will boost title over tags over description over panel titles", func(t *testing.T) {
+		index := newIndex(t, 0)
+		docs := map[string]*resource.IndexableDocument{
+			"panel": {Title: "Service overview", Panels: []resource.IndexablePanel{{ID: 1, Title: "Latency"}}},
+			"desc":  {Title: "Service health", Description: "latency and errors"},
+			"tags":  {Title: "Service status", Tags: []string{"latency"}},
+			"title": {Title: "Latency"},
+		}
+		items := make([]*resource.BulkIndexItem, 0, len(docs))
+		for name, doc := range docs {
+			doc.RV = 1
+			doc.Name = name
+			doc.Key = &resourcepb.ResourceKey{Name: name, Namespace: key.Namespace, Group: key.Group, Resource: key.Resource}
+			items = append(items, &resource.BulkIndexItem{Action: resource.ActionIndex, Doc: doc})
+		}
+		require.NoError(t, index.BulkIndex(&resource.BulkIndexRequest{Items: items}))
+
+		checkSearchQuery(t, index, newTestQuery("latency"), []string{"title", "tags", "desc", "panel"})
+	})
+
+	t.Run("will match accented and CJK text", func(t *testing.T) {
+		index := newIndex(t, 0)
+		indexDocumentsWithTitles(t, index, key, map[string]string{
+			"name1": "Café métriques",
+			"name2": "监控仪表板",
+		})
+
+		checkSearchQuery(t, index, newTestQuery("cafe"), []string{"name1"})
+		checkSearchQuery(t, index, newTestQuery("métriques"), []string{"name1"})
+		checkSearchQuery(t, index, newTestQuery("metriques"), []string{"name1"})
+		checkSearchQuery(t, index, newTestQuery("仪表"), []string{"name2"})
+	})
+
+	t.Run("will return highlights when requested", func(t *testing.T) {
+		index := newIndex(t, 0)
+		indexDocumentsWithTitles(t, index, key, map[string]string{
+			"name1": "Kubernetes cluster",
+		})
+
+		query := newTestQuery("cluster")
+		query.Fields = []string{resource.SEARCH_FIELD_TITLE, resource.SEARCH_FIELD_HIGHLIGHT}
+		res, err := index.Search(context.Background(), nil, query, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, res.Results.Rows, 1)
+		require.Equal(t, resource.SEARCH_FIELD_HIGHLIGHT, res.Results.Columns[1].Name)
+
+		highlights := map[string][]string{}
+		require.NoError(t, json.Unmarshal(res.Results.Rows[0].Cells[1], &highlights))
+		require.Len(t, highlights[resource.SEARCH_FIELD_TITLE], 1)
+		require.Contains(t, highlights[resource.SEARCH_FIELD_TITLE][0], "<mark>cluster</mark>")
+	})
+
+	t.Run("will suggest corrections of misspelled words", func(t *testing.T) {
+		index := newIndex(t, 2)
+		indexDocumentsWithTitles(t, index, key, map[string]string{
+			"name1": "Kubernetes cluster",
+			"name2": "Kubernetes nodes",
+			"name3": "Node exporter",
+		})
+
+		res, err := index.Search(context.Background(), nil, newTestQuery("kubernetse clustr"), nil, nil)
+		require.NoError(t, err)
+		suggestions := res.Facet[resource.SEARCH_FIELD_SUGGESTIONS]
+		require.NotNil(t, suggestions)
+		require.Len(t, suggestions.Terms, 1)
+		require.Equal(t, "kubernetes cluster", suggestions.Terms[0].Term)
+		require.Equal(t, int64(1), suggestions.Terms[0].Count)
+
+		// no suggestion when all the words exist, or are the start of a word
+		for _, q := range []string{"kubernetes cluster", "kube", "export"} {
+			res, err = index.Search(context.Background(), nil, newTestQuery(q), nil, nil)
+			require.NoError(t, err)
+			require.Nil(t, res.Facet[resource.SEARCH_FIELD_SUGGESTIONS], q)
+		}
+	})
+
+	t.Run("will only suggest corrections when few documents are found", func(t *testing.T) {
+		index := newIndex(t, 2)
+		titles := map[string]string{}
+		for i := range 5 {
+			titles[fmt.Sprintf("name%d", i)] = fmt.Sprintf("Kubernetes cluster %d", i)
+		}
+		indexDocumentsWithTitles(t, index, key, titles)
+
+		res, err := index.Search(context.Background(), nil, newTestQuery("kubernetse"), nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(5), res.TotalHits)
+		require.Nil(t, res.Facet[resource.SEARCH_FIELD_SUGGESTIONS])
+	})
+}
+
 func newTestQuery(query string) *resourcepb.ResourceSearchRequest {
 	return &resourcepb.ResourceSearchRequest{
 		Options: &resourcepb.ListOptions{
@@ -252,15 +389,19 @@ func newQueryByTitle(query string) *resourcepb.ResourceSearchRequest {
 }
 
 func newTestDashboardsIndex(t testing.TB, threshold int64, size int64, writer resource.BuildFn) resource.ResourceIndex {
+	return newTestDashboardsIndexWithOptions(t, search.BleveOptions{
+		Root:          t.TempDir(),
+		FileThreshold: threshold, // use in-memory for tests
+	}, size, writer)
+}
+
+func newTestDashboardsIndexWithOptions(t testing.TB, opts search.BleveOptions, size int64, writer resource.BuildFn) resource.ResourceIndex {
 	key := &resourcepb.ResourceKey{
 		Namespace: "default",
 		Group:     "dashboard.grafana.app",
 		Resource:  "dashboards",
 	}
-	backend, err := search.NewBleveBackend(search.BleveOptions{
-		Root:          t.TempDir(),
-		FileThreshold: threshold, // use in-memory for tests
-	}, nil)
+	backend, err := search.NewBleveBackend(opts, nil)
 	require.NoError(t, err)
 
 	t.Cleanup(backend.Stop)
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/standard"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
@@ -1743,3 +1744,105 @@ func TestBuildIndexReturnsErrorWhenIndexLocked(t *testing.T) {
 	// Clean up: close first backend to release the file lock
 	backend1.Stop()
 }
+
+func TestEditDistance(t *testing.T) {
+	require.Equal(t, 0, editDistance("cluster", "cluster", 2))
+	require.Equal(t, 1, editDistance("clustr", "cluster", 2))
+	require.Equal(t, 2, editDistance("kubernetse", "kubernetes", 2))
+	require.Equal(t, 1, editDistance("café", "cafe", 2))
+	require.Equal(t, 3, editDistance("node", "exporter", 2)) // over the limit
+}
+
+func TestTermFuzziness(t *testing.T) {
+	require.Equal(t, 0, termFuzziness("new", 2))
+	require.Equal(t, 1, termFuzziness("alerts", 2))
+	require.Equal(t, 2, termFuzziness("kubernetes", 2))
+	require.Equal(t, 1, termFuzziness("kubernetes", 1))
+	require.Equal(t, 0, termFuzziness("kubernetes", 0))
+}
+
+// buildTitlesIndex returns an in-memory index of documents named after their position in titles
+func buildTitlesIndex(t *testing.T, titles ...string) *bleveIndex {
+	backend, _ := setupBleveBackend(t)
+	ns := resource.NamespacedResource{Namespace: "default", Group: "dashboard.grafana.app", Resource: "dashboards"}
+
+	index, err := backend.BuildIndex(t.Context(), ns, 1 /* memory based */, nil, "test", func(index resource.ResourceIndex) (int64, error) {
+		items := make([]*resource.BulkIndexItem, 0, len(titles))
+		for i, title := range titles {
+			name := fmt.Sprintf("doc%d", i)
+			items = append(items, &resource.BulkIndexItem{
+				Action: resource.ActionIndex,
+				Doc: &resource.IndexableDocument{
+					RV:    1,
+					Name:  name,
+					Key:   &resourcepb.ResourceKey{Namespace: ns.Namespace, Group: ns.Group, Resource: ns.Resource, Name: name},
+					Title: title,
+				},
+			})
+		}
+		return 1, index.BulkIndex(&resource.BulkIndexRequest{Items: items})
+	}, nil, false, time.Time{})
+	require.NoError(t, err)
+	return index.(*bleveIndex)
+}
+
+func searchNames(t *testing.T, index *bleveIndex, query string) []string {
+	t.Helper()
+
+	rsp, err := index.Search(t.Context(), nil, &resourcepb.ResourceSearchRequest{
+		Options: &resourcepb.ListOptions{
+			Key: &resourcepb.ResourceKey{Namespace: index.key.Namespace, Group: index.key.Group, Resource: index.key.Resource},
+		},
+		Query: query,
+		Limit: 100,
+	}, nil, nil)
+	require.NoError(t, err)
+	require.Nil(t, rsp.Error)
+
+	names := []string{}
+	for _, row := range rsp.Results.Rows {
+		names = append(names, row.Key.Name)
+	}
+	return names
+}
+
+func TestTextAnalyzerCJKBigrams(t *testing.T) {
+	index := buildTitlesIndex(t, "监控仪表板", "仪表")
+
+	tokens := index.index.Mapping().AnalyzerNamed(TEXT_ANALYZER).Analyze([]byte("监控仪表板"))
+	terms := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		terms = append(terms, string(token.Term))
+	}
+	require.Equal(t, []string{"监控", "控仪", "仪表", "表板"}, terms)
+
+	require.Equal(t, []string{"doc0"}, searchNames(t, index, "表板"))
+	require.ElementsMatch(t, []string{"doc0", "doc1"}, searchNames(t, index, "仪表"))
+	require.Empty(t, searchNames(t, index, "板监"))
+}
+
+func TestTextAnalyzerAccentFolding(t *testing.T) {
+	index := buildTitlesIndex(t, "Über Straße", "Uber Strasse", "Crème brûlée")
+
+	require.ElementsMatch(t, []string{"doc0", "doc1"}, searchNames(t, index, "über"))
+	require.ElementsMatch(t, []string{"doc0", "doc1"}, searchNames(t, index, "uber"))
+	require.Equal(t, []string{"doc2"}, searchNames(t, index, "creme brulee"))
+	require.Equal(t, []string{"doc2"}, searchNames(t, index, "CRÈME"))
+}
+
+func TestQueryTextAnalyzer(t *testing.T) {
+	current := buildTitlesIndex(t, "Dashboard")
+
+	// indexes built before the text analyzer was registered only know the standard analyzer
+	oldMapping := bleve.NewIndexMapping()
+	old, err := bleve.NewMemOnly(oldMapping)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = old.Close() })
+	previous := &bleveIndex{index: old}
+
+	require.Equal(t, TEXT_ANALYZER, queryTextAnalyzer(current, nil))
+	require.Equal(t, TEXT_ANALYZER, queryTextAnalyzer(current, []resource.ResourceIndex{buildTitlesIndex(t, "Folder")}))
+	require.Equal(t, standard.Name, queryTextAnalyzer(previous, nil))
+	// the federated indexes use the same analyzer
+	require.Equal(t, standard.Name, queryTextAnalyzer(current, []resource.ResourceIndex{previous}))
+}
@@ -0,0 +1,121 @@
+package builders
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	alertingv0 "github.com/grafana/grafana/apps/alerting/rules/pkg/apis/alerting/v0alpha1"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+const (
+	ALERT_RULE_LABELS   = "rule_labels"
+	ALERT_RULE_DS_UIDS  = "ds_uids"
+	ALERT_RULE_PAUSED   = "paused"
+	ALERT_RULE_RECEIVER = "receiver"
+)
+
+// expressions without a data source, or using this one, are server side expressions
+const alertRuleExpressionDatasourceUID = "__expr__"
+
+var AlertRuleTableColumnDefinitions = map[string]*resourcepb.ResourceTableColumnDefinition{
+	ALERT_RULE_LABELS: {
+		Name:        ALERT_RULE_LABELS,
+		Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+		IsArray:     true,
+		Description: "The labels of the alert rule, in the form key=value",
+		Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+			Filterable: true,
+		},
+	},
+	ALERT_RULE_DS_UIDS: {
+		Name:        ALERT_RULE_DS_UIDS,
+		Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+		IsArray:     true,
+		Description: "The data sources queried by the alert rule",
+		Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+			Filterable: true,
+		},
+	},
+	ALERT_RULE_PAUSED: {
+		Name:        ALERT_RULE_PAUSED,
+		Type:        resourcepb.ResourceTableColumnDefinition_BOOLEAN,
+		Description: "Whether the evaluation of the alert rule is paused",
+		Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+			Filterable: true,
+		},
+	},
+	ALERT_RULE_RECEIVER: {
+		Name:        ALERT_RULE_RECEIVER,
+		Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+		Description: "The contact point notified by the alert rule",
+		Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+			Filterable: true,
+		},
+	},
+}
+
+func GetAlertRuleBuilder() (resource.DocumentBuilderInfo, error) {
+	values := make([]*resourcepb.ResourceTableColumnDefinition, 0, len(AlertRuleTableColumnDefinitions))
+	for _, v := range AlertRuleTableColumnDefinitions {
+		values = append(values, v)
+	}
+	fields, err := resource.NewSearchableDocumentFields(values)
+	return resource.DocumentBuilderInfo{
+		GroupResource: alertingv0.AlertRuleKind().GroupVersionResource().GroupResource(),
+		Fields:        fields,
+		Builder:       new(alertRuleDocumentBuilder),
+	}, err
+}
+
+var _ resource.DocumentBuilder = new(alertRuleDocumentBuilder)
+
+type alertRuleDocumentBuilder struct{}
+
+func (a *alertRuleDocumentBuilder) BuildDocument(ctx context.Context, key *resourcepb.ResourceKey, rv int64, value []byte) (*resource.IndexableDocument, error) {
+	rule := &alertingv0.AlertRule{}
+	doc, err := NewIndexableDocumentFromValue(key, rv, value, rule, alertingv0.AlertRuleKind())
+	if err != nil {
+		return nil, err
+	}
+	doc.Title = rule.Spec.Title
+
+	if len(rule.Spec.Labels) > 0 {
+		labels := make([]string, 0, len(rule.Spec.Labels))
+		for k, v := range rule.Spec.Labels {
+			labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+		}
+		sort.Strings(labels)
+		doc.Fields[ALERT_RULE_LABELS] = labels
+	}
+
+	dsUIDs := []string{}
+	for _, expr := range rule.Spec.Expressions {
+		if expr.DatasourceUID == nil || *expr.DatasourceUID == "" || *expr.DatasourceUID == alertRuleExpressionDatasourceUID {
+			continue
+		}
+		dsUIDs = append(dsUIDs, string(*expr.DatasourceUID))
+	}
+	if len(dsUIDs) > 0 {
+		sort.Strings(dsUIDs)
+		dsUIDs = slices.Compact(dsUIDs) // distinct values
+		doc.Fields[ALERT_RULE_DS_UIDS] = dsUIDs
+		for _, uid := range dsUIDs {
+			doc.References = append(doc.References, resource.ResourceReference{
+				Kind:     "DataSource",
+				Name:     uid,
+				Relation: "depends-on",
+			})
+		}
+	}
+
+	doc.Fields[ALERT_RULE_PAUSED] = rule.Spec.Paused != nil && *rule.Spec.Paused
+	if rule.Spec.NotificationSettings != nil && rule.Spec.NotificationSettings.Receiver != "" {
+		doc.Fields[ALERT_RULE_RECEIVER] = rule.Spec.NotificationSettings.Receiver
+	}
+
+	return doc.UpdateCopyFields(), nil
+}
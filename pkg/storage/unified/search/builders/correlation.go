@@ -0,0 +1,66 @@
+package builders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	correlationv0 "github.com/grafana/grafana/apps/correlations/pkg/apis/correlation/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// GetCorrelationBuilder returns the builder for correlations. Correlations are only indexed
+// to keep track of the data sources they link together.
+func GetCorrelationBuilder() (resource.DocumentBuilderInfo, error) {
+	fields, err := resource.NewSearchableDocumentFields(nil)
+	return resource.DocumentBuilderInfo{
+		GroupResource: schema.GroupResource{
+			Group:    correlationv0.APIGroup,
+			Resource: correlationv0.CorrelationKind().Plural(),
+		},
+		Fields:  fields,
+		Builder: new(correlationDocumentBuilder),
+	}, err
+}
+
+var _ resource.DocumentBuilder = new(correlationDocumentBuilder)
+
+type correlationDocumentBuilder struct{}
+
+func (c *correlationDocumentBuilder) BuildDocument(ctx context.Context, key *resourcepb.ResourceKey, rv int64, value []byte) (*resource.IndexableDocument, error) {
+	correlation := &correlationv0.Correlation{}
+	if err := json.NewDecoder(bytes.NewReader(value)).Decode(correlation); err != nil {
+		return nil, err
+	}
+	obj, err := utils.MetaAccessor(correlation)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := resource.NewIndexableDocument(key, rv, obj, correlation.Spec.Label)
+	if correlation.Spec.Description != nil {
+		doc.Description = *correlation.Spec.Description
+	}
+
+	refs := []correlationv0.CorrelationDataSourceRef{correlation.Spec.Source}
+	if correlation.Spec.Target != nil && correlation.Spec.Target.Name != correlation.Spec.Source.Name {
+		refs = append(refs, *correlation.Spec.Target)
+	}
+	for _, ref := range refs {
+		if ref.Name == "" {
+			continue
+		}
+		doc.References = append(doc.References, resource.ResourceReference{
+			Group:    ref.Group,
+			Kind:     "DataSource",
+			Name:     ref.Name,
+			Relation: "depends-on",
+		})
+	}
+
+	return doc.UpdateCopyFields(), nil
+}
@@ -309,6 +309,18 @@ func (s *DashboardDocumentBuilder) BuildDocument(ctx context.Context, key *resou
 				Relation: "depends-on",
 			})
 		}
+		if p.Type != "row" {
+			panel := resource.IndexablePanel{
+				ID:      p.ID,
+				Title:   p.Title,
+				Type:    p.Type,
+				Queries: p.Queries,
+			}
+			for _, ds := range p.Datasource {
+				panel.DataSources = append(panel.DataSources, ds.UID)
+			}
+			doc.Panels = append(doc.Panels, panel)
+		}
 	}
 
 	for _, ds := range summary.Datasource {
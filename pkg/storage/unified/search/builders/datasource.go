@@ -0,0 +1,82 @@
+package builders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	datasourceV0 "github.com/grafana/grafana/pkg/apis/datasource/v0alpha1"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+const (
+	DATASOURCE_TYPE       = "type"
+	DATASOURCE_IS_DEFAULT = "is_default"
+)
+
+var DataSourceTableColumnDefinitions = map[string]*resourcepb.ResourceTableColumnDefinition{
+	DATASOURCE_TYPE: {
+		Name:        DATASOURCE_TYPE,
+		Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+		Description: "The data source plugin type",
+		Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+			Filterable: true,
+		},
+	},
+	DATASOURCE_IS_DEFAULT: {
+		Name:        DATASOURCE_IS_DEFAULT,
+		Type:        resourcepb.ResourceTableColumnDefinition_BOOLEAN,
+		Description: "Whether this is the default data source",
+		Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+			Filterable: true,
+		},
+	},
+}
+
+// GetDataSourceBuilder returns the builder for the data sources of every plugin.
+// Each plugin has its own group, eg prometheus.datasource.grafana.app
+func GetDataSourceBuilder() (resource.DocumentBuilderInfo, error) {
+	values := make([]*resourcepb.ResourceTableColumnDefinition, 0, len(DataSourceTableColumnDefinitions))
+	for _, v := range DataSourceTableColumnDefinitions {
+		values = append(values, v)
+	}
+	fields, err := resource.NewSearchableDocumentFields(values)
+	return resource.DocumentBuilderInfo{
+		GroupResource: schema.GroupResource{
+			Group:    "*." + datasourceV0.GROUP,
+			Resource: datasourceV0.DataSourceResourceInfo.GroupResource().Resource,
+		},
+		Fields:  fields,
+		Builder: new(dataSourceDocumentBuilder),
+	}, err
+}
+
+var _ resource.DocumentBuilder = new(dataSourceDocumentBuilder)
+
+type dataSourceDocumentBuilder struct{}
+
+func (d *dataSourceDocumentBuilder) BuildDocument(ctx context.Context, key *resourcepb.ResourceKey, rv int64, value []byte) (*resource.IndexableDocument, error) {
+	ds := &datasourceV0.DataSource{}
+	if err := json.NewDecoder(bytes.NewReader(value)).Decode(ds); err != nil {
+		return nil, err
+	}
+	obj, err := utils.MetaAccessor(ds)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := resource.NewIndexableDocument(key, rv, obj, ds.Spec.Title())
+	doc.Fields = map[string]any{
+		DATASOURCE_IS_DEFAULT: ds.Spec.IsDefault(),
+	}
+	if pluginType, ok := strings.CutSuffix(key.Group, "."+datasourceV0.GROUP); ok && pluginType != "" {
+		doc.Fields[DATASOURCE_TYPE] = pluginType
+	}
+
+	return doc, nil
+}
@@ -17,7 +17,7 @@ import (
 )
 
 // All returns all document builders from this package.
-// These builders have dependencies on Grafana apps (dashboard and user).
+// These builders have dependencies on Grafana apps (dashboard, user, alerting, data source and folder).
 func All(sql db.DB, sprinkles DashboardStats) ([]resource.DocumentBuilderInfo, error) {
 	dashboards, err := DashboardBuilder(func(ctx context.Context, namespace string, blob resource.BlobSupport) (resource.DocumentBuilder, error) {
 		logger := log.New("dashboard_builder", "namespace", namespace)
@@ -83,7 +83,66 @@ func All(sql db.DB, sprinkles DashboardStats) ([]resource.DocumentBuilderInfo, e
 		return nil, err
 	}
 
-	return []resource.DocumentBuilderInfo{dashboards, users, extGroupMappings, teams, teamBindings}, nil
+	alertRules, err := GetAlertRuleBuilder()
+	if err != nil {
+		return nil, err
+	}
+
+	dataSources, err := GetDataSourceBuilder()
+	if err != nil {
+		return nil, err
+	}
+
+	libraryPanels, err := LibraryPanelBuilder(func(ctx context.Context, namespace string, blob resource.BlobSupport) (resource.DocumentBuilder, error) {
+		usage := map[string]int64{}
+		ns, err := claims.ParseNamespace(namespace)
+		if err == nil && sql != nil {
+			rows, err := sql.GetSqlxSession().Query(ctx, `SELECT le.uid, COUNT(lec.id) FROM library_element AS le
+				INNER JOIN library_element_connection AS lec ON lec.element_id = le.id
+				WHERE le.org_id=? GROUP BY le.uid`, ns.OrgID)
+			if err != nil {
+				return nil, err
+			}
+
+			defer func() {
+				_ = rows.Close()
+			}()
+
+			for rows.Next() {
+				var uid string
+				var count int64
+				if err = rows.Scan(&uid, &count); err != nil {
+					return nil, err
+				}
+				usage[uid] = count
+			}
+		}
+
+		return &LibraryPanelDocumentBuilder{
+			Namespace:  namespace,
+			UsageCount: usage,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The parents are read from the folder resources, see resource.FolderTreeDocumentBuilder
+	folders, err := FolderBuilder(func(ctx context.Context, namespace string, blob resource.BlobSupport) (resource.DocumentBuilder, error) {
+		return &FolderDocumentBuilder{
+			Namespace: namespace,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	correlations, err := GetCorrelationBuilder()
+	if err != nil {
+		return nil, err
+	}
+
+	return []resource.DocumentBuilderInfo{dashboards, users, extGroupMappings, teams, teamBindings, alertRules, dataSources, libraryPanels, folders, correlations}, nil
 }
 
 // NewIndexableDocumentFromValue parses provided bytes value into object, and initializes IndexableDocument from it.
@@ -96,6 +96,85 @@ func TestTeamBindingSearchBuilder(t *testing.T) {
 	})
 }
 
+func TestAlertRuleDocumentBuilder(t *testing.T) {
+	info, err := GetAlertRuleBuilder()
+	require.NoError(t, err)
+	doSnapshotTests(t, info.Builder, "alert_rule", &resourcepb.ResourceKey{
+		Namespace: "default",
+		Group:     "rules.alerting.grafana.app",
+		Resource:  "alertrules",
+	}, []string{
+		"with-labels-and-datasources",
+	})
+}
+
+func TestLibraryPanelDocumentBuilder(t *testing.T) {
+	info, err := LibraryPanelBuilder(func(ctx context.Context, namespace string, blob resource.BlobSupport) (resource.DocumentBuilder, error) {
+		return &LibraryPanelDocumentBuilder{
+			Namespace:  namespace,
+			UsageCount: map[string]int64{"timeseries": 3},
+		}, nil
+	})
+	require.NoError(t, err)
+
+	builder, err := info.Namespaced(context.Background(), "default", nil)
+	require.NoError(t, err)
+	doSnapshotTests(t, builder, "library_panel", &resourcepb.ResourceKey{
+		Namespace: "default",
+		Group:     "dashboard.grafana.app",
+		Resource:  "librarypanels",
+	}, []string{
+		"timeseries",
+	})
+}
+
+func TestDataSourceDocumentBuilder(t *testing.T) {
+	info, err := GetDataSourceBuilder()
+	require.NoError(t, err)
+	require.Equal(t, "*.datasource.grafana.app", info.GroupResource.Group)
+	doSnapshotTests(t, info.Builder, "datasource", &resourcepb.ResourceKey{
+		Namespace: "default",
+		Group:     "prometheus.datasource.grafana.app",
+		Resource:  "datasources",
+	}, []string{
+		"prom-uid",
+	})
+}
+
+func TestCorrelationDocumentBuilder(t *testing.T) {
+	info, err := GetCorrelationBuilder()
+	require.NoError(t, err)
+	doSnapshotTests(t, info.Builder, "correlation", &resourcepb.ResourceKey{
+		Namespace: "default",
+		Group:     "correlations.grafana.app",
+		Resource:  "correlations",
+	}, []string{
+		"prom-to-loki",
+	})
+}
+
+func TestFolderDocumentBuilder(t *testing.T) {
+	info, err := FolderBuilder(func(ctx context.Context, namespace string, blob resource.BlobSupport) (resource.DocumentBuilder, error) {
+		return &FolderDocumentBuilder{
+			Namespace: namespace,
+		}, nil
+	})
+	require.NoError(t, err)
+
+	builder, err := info.Namespaced(context.Background(), "default", nil)
+	require.NoError(t, err)
+	tree, ok := builder.(resource.FolderTreeDocumentBuilder)
+	require.True(t, ok)
+	builder = tree.WithFolderParents(map[string]string{"parent": "root"})
+	doSnapshotTests(t, builder, "folder", &resourcepb.ResourceKey{
+		Namespace: "default",
+		Group:     "folder.grafana.app",
+		Resource:  "folders",
+	}, []string{
+		"nested",
+	})
+}
+
 func TestDashboardDocumentBuilder(t *testing.T) {
 	key := &resourcepb.ResourceKey{
 		Namespace: "default",
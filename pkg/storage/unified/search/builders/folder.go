@@ -0,0 +1,97 @@
+package builders
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	folderv1 "github.com/grafana/grafana/apps/folder/pkg/apis/folder/v1"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+const (
+	FOLDER_PATH  = "path"
+	FOLDER_DEPTH = "depth"
+)
+
+var FolderTableColumnDefinitions = map[string]*resourcepb.ResourceTableColumnDefinition{
+	FOLDER_PATH: {
+		Name:        FOLDER_PATH,
+		Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+		IsArray:     true,
+		Description: "The parent folders, starting from the root",
+		Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+			Filterable: true,
+		},
+	},
+	FOLDER_DEPTH: {
+		Name:        FOLDER_DEPTH,
+		Type:        resourcepb.ResourceTableColumnDefinition_INT32,
+		Description: "How many folders contain this folder",
+		Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+			Filterable: true,
+		},
+	},
+}
+
+// FolderBuilder creates the folder builder. The parents of the folders are read from the
+// folder resources of the namespace to build the path of each folder.
+func FolderBuilder(namespaced resource.NamespacedDocumentSupplier) (resource.DocumentBuilderInfo, error) {
+	values := make([]*resourcepb.ResourceTableColumnDefinition, 0, len(FolderTableColumnDefinitions))
+	for _, v := range FolderTableColumnDefinitions {
+		values = append(values, v)
+	}
+	fields, err := resource.NewSearchableDocumentFields(values)
+	return resource.DocumentBuilderInfo{
+		GroupResource: folderv1.FolderResourceInfo.GroupResource(),
+		Fields:        fields,
+		Namespaced:    namespaced,
+	}, err
+}
+
+var _ resource.FolderTreeDocumentBuilder = new(FolderDocumentBuilder)
+
+type FolderDocumentBuilder struct {
+	// Scoped to a single tenant
+	Namespace string
+
+	// The parent of each folder (by uid), folders at the root are not included
+	Parents map[string]string
+}
+
+func (f *FolderDocumentBuilder) WithFolderParents(parents map[string]string) resource.DocumentBuilder {
+	return &FolderDocumentBuilder{
+		Namespace: f.Namespace,
+		Parents:   parents,
+	}
+}
+
+func (f *FolderDocumentBuilder) BuildDocument(ctx context.Context, key *resourcepb.ResourceKey, rv int64, value []byte) (*resource.IndexableDocument, error) {
+	if f.Namespace != "" && f.Namespace != key.Namespace {
+		return nil, fmt.Errorf("invalid namespace")
+	}
+
+	folder := &folderv1.Folder{}
+	doc, err := NewIndexableDocumentFromValue(key, rv, value, folder, folderv1.FolderKind())
+	if err != nil {
+		return nil, err
+	}
+	doc.Title = folder.Spec.Title
+	if folder.Spec.Description != nil {
+		doc.Description = *folder.Spec.Description
+	}
+
+	// The parent is taken from the folder itself, the lookup may not have seen the latest move
+	path := []string{}
+	for parent := doc.Folder; parent != "" && !slices.Contains(path, parent); parent = f.Parents[parent] {
+		path = append(path, parent)
+	}
+	slices.Reverse(path)
+
+	doc.Fields[FOLDER_DEPTH] = len(path)
+	if len(path) > 0 {
+		doc.Fields[FOLDER_PATH] = path
+	}
+	return doc.UpdateCopyFields(), nil
+}
@@ -0,0 +1,96 @@
+package builders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	dashboardv0 "github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+const (
+	LIBRARY_PANEL_TYPE        = "panel_type"
+	LIBRARY_PANEL_USAGE_COUNT = "usage_count"
+)
+
+var LibraryPanelTableColumnDefinitions = map[string]*resourcepb.ResourceTableColumnDefinition{
+	LIBRARY_PANEL_TYPE: {
+		Name:        LIBRARY_PANEL_TYPE,
+		Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+		Description: "The panel type",
+		Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+			Filterable: true,
+		},
+	},
+	LIBRARY_PANEL_USAGE_COUNT: {
+		Name:        LIBRARY_PANEL_USAGE_COUNT,
+		Type:        resourcepb.ResourceTableColumnDefinition_INT64,
+		Description: "How many dashboards use the library panel",
+		Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+			Filterable: true,
+		},
+	},
+}
+
+// LibraryPanelBuilder creates the library panel builder. The usage count of the panels is
+// looked up per namespace, like the dashboard stats.
+func LibraryPanelBuilder(namespaced resource.NamespacedDocumentSupplier) (resource.DocumentBuilderInfo, error) {
+	values := make([]*resourcepb.ResourceTableColumnDefinition, 0, len(LibraryPanelTableColumnDefinitions))
+	for _, v := range LibraryPanelTableColumnDefinitions {
+		values = append(values, v)
+	}
+	fields, err := resource.NewSearchableDocumentFields(values)
+	return resource.DocumentBuilderInfo{
+		GroupResource: dashboardv0.LibraryPanelResourceInfo.GroupResource(),
+		Fields:        fields,
+		Namespaced:    namespaced,
+	}, err
+}
+
+var _ resource.DocumentBuilder = new(LibraryPanelDocumentBuilder)
+
+type LibraryPanelDocumentBuilder struct {
+	// Scoped to a single tenant
+	Namespace string
+
+	// The number of dashboards using each library panel (by uid)
+	UsageCount map[string]int64
+}
+
+func (l *LibraryPanelDocumentBuilder) BuildDocument(ctx context.Context, key *resourcepb.ResourceKey, rv int64, value []byte) (*resource.IndexableDocument, error) {
+	if l.Namespace != "" && l.Namespace != key.Namespace {
+		return nil, fmt.Errorf("invalid namespace")
+	}
+
+	panel := &dashboardv0.LibraryPanel{}
+	if err := json.NewDecoder(bytes.NewReader(value)).Decode(panel); err != nil {
+		return nil, err
+	}
+	obj, err := utils.MetaAccessor(panel)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := resource.NewIndexableDocument(key, rv, obj, panel.Spec.Title)
+	doc.Description = panel.Spec.Description
+	doc.Fields = map[string]any{
+		LIBRARY_PANEL_USAGE_COUNT: l.UsageCount[panel.Name],
+	}
+	if panel.Spec.Type != "" {
+		doc.Fields[LIBRARY_PANEL_TYPE] = panel.Spec.Type
+	}
+	if panel.Spec.Datasource != nil && panel.Spec.Datasource.UID != "" {
+		doc.References = append(doc.References, resource.ResourceReference{
+			Group:    panel.Spec.Datasource.Type,
+			Kind:     "DataSource",
+			Name:     panel.Spec.Datasource.UID,
+			Relation: "depends-on",
+		})
+	}
+
+	return doc.UpdateCopyFields(), nil
+}
@@ -2,19 +2,31 @@ package search
 
 import (
 	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/char/asciifolding"
+	"github.com/blevesearch/bleve/v2/analysis/lang/cjk"
+	"github.com/blevesearch/bleve/v2/analysis/lang/en"
 	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
 	"github.com/blevesearch/bleve/v2/analysis/token/ngram"
 	"github.com/blevesearch/bleve/v2/analysis/token/unique"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
 	"github.com/blevesearch/bleve/v2/analysis/tokenizer/whitespace"
 	"github.com/blevesearch/bleve/v2/mapping"
 )
 
 const TITLE_ANALYZER = "title_analyzer"
+const TEXT_ANALYZER = "text_analyzer"
+const SUGGEST_ANALYZER = "suggest_analyzer"
 const EDGE_NGRAM_MIN_TOKEN = 3.0
 const tokenFilterName = "ngram_filter"
 
 func RegisterCustomAnalyzers(mapper *mapping.IndexMappingImpl) error {
-	return registerTitleAnalyzer(mapper)
+	if err := registerTitleAnalyzer(mapper); err != nil {
+		return err
+	}
+	if err := registerTextAnalyzer(mapper); err != nil {
+		return err
+	}
+	return registerSuggestAnalyzer(mapper)
 }
 
 // The registerTitleAnalyzer function defines a custom analyzer using edge n-gram or full n-gram
@@ -34,9 +46,9 @@ func registerTitleAnalyzer(mapper *mapping.IndexMappingImpl) error {
 	//Create a custom analyzer using the N-Gram tokenizer
 	ngramAnalyzer := map[string]interface{}{
 		"type":          custom.Name,
+		"char_filters":  []string{asciifolding.Name}, // "Café" is indexed as "cafe"
 		"tokenizer":     whitespace.Name,
 		"token_filters": []string{tokenFilterName, lowercase.Name, unique.Name},
-		//"char_filters":  //TODO IF NEEDED
 	}
 
 	err = mapper.AddCustomAnalyzer(TITLE_ANALYZER, ngramAnalyzer)
@@ -46,3 +58,26 @@ func registerTitleAnalyzer(mapper *mapping.IndexMappingImpl) error {
 
 	return nil
 }
+
+// The registerTextAnalyzer function defines the analyzer used for words in free text (titles, descriptions, panel titles).
+// It behaves like the standard analyzer, but also folds accents and splits CJK text, which has no whitespace
+// between words, in overlapping bigrams.
+func registerTextAnalyzer(mapper *mapping.IndexMappingImpl) error {
+	return mapper.AddCustomAnalyzer(TEXT_ANALYZER, map[string]interface{}{
+		"type":          custom.Name,
+		"char_filters":  []string{asciifolding.Name},
+		"tokenizer":     unicode.Name,
+		"token_filters": []string{cjk.WidthName, lowercase.Name, cjk.BigramName, en.StopName},
+	})
+}
+
+// The registerSuggestAnalyzer function defines the analyzer of the terms used for "did you mean" suggestions.
+// It keeps every word as is (no stop words, no ngrams), so a query can be rebuilt from the suggested terms.
+func registerSuggestAnalyzer(mapper *mapping.IndexMappingImpl) error {
+	return mapper.AddCustomAnalyzer(SUGGEST_ANALYZER, map[string]interface{}{
+		"type":          custom.Name,
+		"char_filters":  []string{asciifolding.Name},
+		"tokenizer":     unicode.Name,
+		"token_filters": []string{lowercase.Name},
+	})
+}
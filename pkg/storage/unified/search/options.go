@@ -64,6 +64,7 @@ func NewSearchOptions(
 			OwnsIndex:                ownsIndexFn,
 			IndexMinUpdateInterval:   cfg.IndexMinUpdateInterval,
 			SelectableFieldsForKinds: resource.SelectableFields(),
+			SearchFuzziness:          cfg.IndexSearchFuzziness,
 		}, indexMetrics)
 
 		if err != nil {
@@ -65,6 +65,9 @@ func newTenantDeleterGcomClient(cfg *setting.Cfg) gcom.Service {
 const defaultWatchBufferSize = 100 // number of events to buffer in the watch stream
 const defaultGarbageCollectionBatchWait = 1 * time.Second
 
+// how often the collections with an age based retention are pruned
+const retentionSweepInterval = time.Hour
+
 type GarbageCollectionConfig struct {
 	Enabled          bool
 	Interval         time.Duration // how often the process runs
@@ -127,7 +130,7 @@ func NewStorageBackend(
 		cfg.SectionWithEnvOverrides("resource_api"))
 
 	if !cfg.EnableSQLKVBackend {
-		return NewBackend(BackendOptions{
+		backend, err := NewBackend(BackendOptions{
 			DBProvider:           eDB,
 			Reg:                  reg,
 			IsHA:                 isHA,
@@ -148,6 +151,10 @@ func NewStorageBackend(
 			DisablePruner:           cfg.DisablePruner,
 			DashboardVersionsToKeep: cfg.DashboardVersionsToKeep,
 		})
+		if err != nil || cfg.HistoryArchiveBucketURL == "" {
+			return backend, err
+		}
+		return newArchiveBackend(cfg, backend, disableStorageServices)
 	}
 
 	ctx := context.Background()
@@ -182,6 +189,7 @@ func NewStorageBackend(
 		LastImportTimeMaxAge: cfg.MaxFileIndexAge,
 		TenantWatcherConfig:  resource.NewTenantWatcherConfig(cfg),
 		TenantDeleterConfig:  tenantDeleterCfg,
+		WebhookConfig:        resource.NewWebhookConfig(cfg),
 		GarbageCollection: resource.GarbageCollectionConfig{
 			Enabled:          cfg.EnableGarbageCollection,
 			DryRun:           cfg.GarbageCollectionDryRun,
@@ -213,22 +221,47 @@ func NewStorageBackend(
 	return resource.NewKVStorageBackend(kvBackendOpts)
 }
 
+// NewFileBackend creates a KV backend on an embedded BadgerDB, so single node installations can run
+// unified storage without an external database.
 func NewFileBackend(cfg *setting.Cfg) (resource.StorageBackend, error) {
 	apiserverCfg := cfg.SectionWithEnvOverrides("grafana-apiserver")
 	dataPath := apiserverCfg.Key("storage_path").
 		MustString(filepath.Join(cfg.DataPath, "grafana-apiserver"))
 	db, err := badger.Open(badger.DefaultOptions(filepath.Join(dataPath, "badger")).
+		WithSyncWrites(apiserverCfg.Key("storage_sync_writes").MustBool(true)).
 		WithLogger(nil))
 	if err != nil {
 		return nil, err
 	}
 
 	kvStore := resource.NewBadgerKV(db)
-	return resource.NewKVStorageBackend(resource.KVBackendOptions{
-		KvStore:                 kvStore,
-		Log:                     log.New("storage-backend"),
+	backend, err := resource.NewKVStorageBackend(resource.KVBackendOptions{
+		KvStore:              kvStore,
+		CloseKV:              true,
+		UseChannelNotifier:   true, // a single process owns the database
+		Log:                  log.New("storage-backend"),
+		LastImportTimeMaxAge: cfg.MaxFileIndexAge,
+		WebhookConfig:        resource.NewWebhookConfig(cfg),
+		GarbageCollection: resource.GarbageCollectionConfig{
+			Enabled:          cfg.EnableGarbageCollection,
+			DryRun:           cfg.GarbageCollectionDryRun,
+			Interval:         cfg.GarbageCollectionInterval,
+			BatchSize:        cfg.GarbageCollectionBatchSize,
+			BatchWait:        cfg.GarbageCollectionBatchWait,
+			MaxAge:           cfg.GarbageCollectionMaxAge,
+			DashboardsMaxAge: cfg.DashboardsGarbageCollectionMaxAge,
+		},
+		EventRetentionPeriod:    cfg.EventRetentionPeriod,
+		EventPruningInterval:    cfg.EventPruningInterval,
+		SearchLookback:          cfg.SearchLookback,
+		WatchOptions:            resource.WatchOptions{SettleDelay: cfg.NotifierSettleDelay},
 		DashboardVersionsToKeep: cfg.DashboardVersionsToKeep,
 	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return backend, nil
 }
 
 type BackendOptions struct {
@@ -346,7 +379,10 @@ type backend struct {
 
 	disablePruner           bool
 	dashboardVersionsToKeep int
+	historyQuotas           resource.HistoryQuotas
 	historyPruner           resource.Pruner
+	retention               *resource.RetentionPolicies
+	prunerMetrics           *resource.HistoryPrunerMetrics
 
 	garbageCollection GarbageCollectionConfig
 
@@ -361,6 +397,13 @@ type backend struct {
 	lastImportTimeDeletionTime atomic.Time
 }
 
+var _ resource.HistoryQuotaSupport = (*backend)(nil)
+
+// SetHistoryQuotas implements resource.HistoryQuotaSupport.
+func (b *backend) SetHistoryQuotas(quotas resource.HistoryQuotas) {
+	b.historyQuotas = quotas
+}
+
 func (b *backend) Init(ctx context.Context) error {
 	b.initOnce.Do(func() {
 		b.initErr = b.initLocked(ctx)
@@ -437,41 +480,18 @@ func (b *backend) initPruner(ctx context.Context) error {
 		return nil
 	}
 
+	// The retention policies are loaded when the pruner runs, nothing is read from the database now
+	b.retention = resource.NewRetentionPolicies(b, log.New("sql-retention-policies"), 0)
+	b.prunerMetrics = resource.NewHistoryPrunerMetrics(b.reg)
+
 	b.log.Debug("using debounced history pruner")
 	// Initialize history pruner.
 	pruner, err := debouncer.NewGroup(debouncer.DebouncerOpts[resource.PruningKey]{
-		Name:       "history_pruner",
-		BufferSize: 1000,
-		MinWait:    time.Second * 30,
-		MaxWait:    time.Minute * 5,
-		ProcessHandler: func(ctx context.Context, key resource.PruningKey) error {
-			return b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
-				res, err := dbutil.Exec(ctx, tx, sqlResourceHistoryPrune, &sqlPruneHistoryRequest{
-					SQLTemplate:  sqltemplate.New(b.dialect),
-					HistoryLimit: int64(resource.LookupPrunerHistoryLimit(key.Group, key.Resource, b.dashboardVersionsToKeep)),
-					Key: &resourcepb.ResourceKey{
-						Namespace: key.Namespace,
-						Group:     key.Group,
-						Resource:  key.Resource,
-						Name:      key.Name,
-					},
-				})
-				if err != nil {
-					return fmt.Errorf("failed to prune history: %w", err)
-				}
-				rows, err := res.RowsAffected()
-				if err != nil {
-					return fmt.Errorf("failed to get rows affected: %w", err)
-				}
-				b.log.Debug("pruned history successfully",
-					"namespace", key.Namespace,
-					"group", key.Group,
-					"resource", key.Resource,
-					"name", key.Name,
-					"rows", rows)
-				return nil
-			})
-		},
+		Name:           "history_pruner",
+		BufferSize:     1000,
+		MinWait:        time.Second * 30,
+		MaxWait:        time.Minute * 5,
+		ProcessHandler: b.pruneHistory,
 		ErrorHandler: func(key resource.PruningKey, err error) {
 			b.log.Error("failed to prune history",
 				"namespace", key.Namespace,
@@ -488,6 +508,132 @@ func (b *backend) initPruner(ctx context.Context) error {
 
 	b.historyPruner = pruner
 	b.historyPruner.Start(ctx)
+	go b.runRetentionSweeps(ctx)
+	return nil
+}
+
+// pruneHistory removes the versions of a resource beyond its retention
+func (b *backend) pruneHistory(ctx context.Context, key resource.PruningKey) error {
+	if err := b.retention.Refresh(ctx); err != nil {
+		// the policies loaded before still apply
+		b.log.Warn("failed to reload retention policies", "error", err)
+	}
+	retention := b.retention.HistoryRetention(ctx, b.historyQuotas, key, b.dashboardVersionsToKeep)
+	resourceKey := &resourcepb.ResourceKey{
+		Namespace: key.Namespace,
+		Group:     key.Group,
+		Resource:  key.Resource,
+		Name:      key.Name,
+	}
+
+	var prunedByCount, prunedByAge int64
+	err := b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+		if retention.MaxVersions > 0 {
+			res, err := dbutil.Exec(ctx, tx, sqlResourceHistoryPrune, &sqlPruneHistoryRequest{
+				SQLTemplate:  sqltemplate.New(b.dialect),
+				HistoryLimit: int64(retention.MaxVersions),
+				Key:          resourceKey,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to prune history: %w", err)
+			}
+			if prunedByCount, err = res.RowsAffected(); err != nil {
+				return fmt.Errorf("failed to get rows affected: %w", err)
+			}
+		}
+		if !retention.Cutoff.IsZero() {
+			var err error
+			if prunedByAge, err = b.pruneHistoryByAge(ctx, tx, resourceKey, retention); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	b.prunerMetrics.Pruned(key, resource.PruneReasonCount, int(prunedByCount))
+	b.prunerMetrics.Pruned(key, resource.PruneReasonAge, int(prunedByAge))
+	b.log.Debug("pruned history successfully",
+		"namespace", key.Namespace,
+		"group", key.Group,
+		"resource", key.Resource,
+		"name", key.Name,
+		"rows", prunedByCount+prunedByAge)
+	return nil
+}
+
+// pruneHistoryByAge removes the versions older than the retention cutoff, of a resource or of
+// all the resources of a collection when the key has no name
+func (b *backend) pruneHistoryByAge(ctx context.Context, x db.ContextExecer, key *resourcepb.ResourceKey, retention resource.HistoryRetention) (int64, error) {
+	res, err := dbutil.Exec(ctx, x, sqlResourceHistoryPruneAge, &sqlPruneHistoryAgeRequest{
+		SQLTemplate:  sqltemplate.New(b.dialect),
+		Key:          key,
+		KeepVersions: int64(max(retention.MinVersions, 1)),
+		// the resource versions of the SQL backend are timestamps in microseconds
+		CutoffRV: retention.Cutoff.UnixMicro(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune history by age: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows, nil
+}
+
+// runRetentionSweeps regularly prunes the collections with an age based retention,
+// so the old versions of the resources that are not written anymore are pruned too
+func (b *backend) runRetentionSweeps(ctx context.Context) {
+	ticker := time.NewTicker(retentionSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.sweepRetention(ctx); err != nil {
+				b.log.Error("failed to sweep retention policies", "error", err)
+			}
+		}
+	}
+}
+
+func (b *backend) sweepRetention(ctx context.Context) error {
+	if err := b.retention.Refresh(ctx); err != nil {
+		return fmt.Errorf("failed to reload retention policies: %w", err)
+	}
+	for _, namespace := range b.retention.SweepNamespaces() {
+		stats, err := b.GetResourceStats(ctx, resource.NamespacedResource{Namespace: namespace}, 0)
+		if err != nil {
+			return err
+		}
+		for _, stat := range stats {
+			key := resource.PruningKey{Namespace: stat.Namespace, Group: stat.Group, Resource: stat.Resource}
+			// the most specific rule of the collection applies, it may have no age limit
+			retention := b.retention.HistoryRetention(ctx, b.historyQuotas, key, b.dashboardVersionsToKeep)
+			if retention.Cutoff.IsZero() {
+				continue
+			}
+			var pruned int64
+			err := b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+				var err error
+				pruned, err = b.pruneHistoryByAge(ctx, tx, &resourcepb.ResourceKey{
+					Namespace: key.Namespace,
+					Group:     key.Group,
+					Resource:  key.Resource,
+				}, retention)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			b.prunerMetrics.Pruned(key, resource.PruneReasonAge, int(pruned))
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,129 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/parquet"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/dbutil"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
+)
+
+// The history of the SQL backend can be archived into parquet files
+var _ parquet.HistorySource = (*backend)(nil)
+
+// newArchiveBackend moves the old history of the backend into parquet files in the configured bucket
+func newArchiveBackend(cfg *setting.Cfg, backend Backend, disableStorageServices bool) (resource.StorageBackend, error) {
+	bucket, err := resource.OpenBlobBucket(context.Background(), cfg.HistoryArchiveBucketURL)
+	if err != nil {
+		return nil, fmt.Errorf("open history archive bucket: %w", err)
+	}
+
+	interval := cfg.HistoryArchiveInterval
+	if disableStorageServices {
+		interval = 0
+	}
+	archive, err := parquet.NewArchiveBackend(parquet.ArchiveBackendOptions{
+		Backend:             backend,
+		Bucket:              bucket,
+		MaxAge:              cfg.HistoryArchiveMaxAge,
+		Interval:            interval,
+		BatchSize:           cfg.HistoryArchiveBatchSize,
+		CompactionThreshold: cfg.HistoryArchiveCompactionThreshold,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// ListArchivableHistory implements parquet.HistorySource.
+func (b *backend) ListArchivableHistory(ctx context.Context, key resource.NamespacedResource, cutoffRV int64, limit int) ([]*parquet.HistoryRevision, error) {
+	ctx, span := tracer.Start(ctx, "sql.backend.ListArchivableHistory")
+	span.SetAttributes(attribute.String("namespace", key.Namespace), attribute.String("group", key.Group), attribute.String("resource", key.Resource))
+	defer span.End()
+
+	var candidates []archiveCandidate
+	err := b.db.WithTx(ctx, ReadCommittedRO, func(ctx context.Context, tx db.Tx) error {
+		var err error
+		candidates, err = dbutil.Query(ctx, tx, sqlResourceHistoryArchiveCandidates, &sqlArchiveCandidatesRequest{
+			SQLTemplate: sqltemplate.New(b.dialect),
+			Namespace:   key.Namespace,
+			Group:       key.Group,
+			Resource:    key.Resource,
+			CutoffRV:    cutoffRV,
+			BatchSize:   limit,
+			Response:    new(archiveCandidate),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*parquet.HistoryRevision, 0, len(candidates))
+	for _, c := range candidates {
+		revisions = append(revisions, &parquet.HistoryRevision{
+			GUID: c.GUID,
+			Key: &resourcepb.ResourceKey{
+				Namespace: key.Namespace,
+				Group:     key.Group,
+				Resource:  key.Resource,
+				Name:      c.Name,
+			},
+			Folder:          c.Folder,
+			Action:          resourcepb.WatchEvent_Type(c.Action),
+			ResourceVersion: c.ResourceVersion,
+			Value:           c.Value,
+		})
+	}
+	return revisions, nil
+}
+
+// DeleteArchivedHistory implements parquet.HistorySource.
+func (b *backend) DeleteArchivedHistory(ctx context.Context, key resource.NamespacedResource, guids []string) (int64, error) {
+	ctx, span := tracer.Start(ctx, "sql.backend.DeleteArchivedHistory")
+	span.SetAttributes(attribute.String("namespace", key.Namespace), attribute.String("group", key.Group), attribute.String("resource", key.Resource))
+	defer span.End()
+
+	var rowsAffected int64
+	err := b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+		res, err := dbutil.Exec(ctx, tx, sqlResourceHistoryArchiveDelete, &sqlArchiveDeleteRequest{
+			SQLTemplate: sqltemplate.New(b.dialect),
+			Namespace:   key.Namespace,
+			Group:       key.Group,
+			Resource:    key.Resource,
+			GUIDs:       guids,
+		})
+		if err != nil {
+			return err
+		}
+		rowsAffected, err = res.RowsAffected()
+		return err
+	})
+	return rowsAffected, err
+}
+
+// LatestDeletedRV implements parquet.HistorySource.
+func (b *backend) LatestDeletedRV(ctx context.Context, key *resourcepb.ResourceKey) (int64, error) {
+	var rv int64
+	err := b.db.WithTx(ctx, ReadCommittedRO, func(ctx context.Context, tx db.Tx) error {
+		var err error
+		rv, err = b.fetchLatestHistoryRV(ctx, tx, b.dialect, key, resourcepb.WatchEvent_DELETED)
+		return err
+	})
+	return rv, err
+}
+
+// ResourceVersionAt implements parquet.HistorySource.
+// The resource versions of the SQL backend are microsecond timestamps.
+func (b *backend) ResourceVersionAt(t time.Time) int64 {
+	return t.UnixMicro()
+}
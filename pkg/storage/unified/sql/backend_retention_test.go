@@ -0,0 +1,106 @@
+package sql
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	storageapi "github.com/grafana/grafana/pkg/apis/storage/v0alpha1"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	test "github.com/grafana/grafana/pkg/storage/unified/testing"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestIntegrationRetentionPolicies(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+	t.Cleanup(db.CleanupTestDB)
+
+	ctx := testutil.NewTestContext(t, time.Now().Add(30*time.Second))
+	storageBackend, _ := newTestBackend(t, GarbageCollectionConfig{})
+	b := storageBackend.(*backend)
+	// the pruner is disabled with SQLite, it is called directly
+	b.retention = resource.NewRetentionPolicies(b, nil, time.Hour)
+	b.prunerMetrics = resource.NewHistoryPrunerMetrics(nil)
+
+	server, err := resource.NewResourceServer(resource.ResourceServerOptions{
+		Backend: storageBackend,
+	})
+	require.NoError(t, err)
+
+	policy, err := json.Marshal(&storageapi.RetentionPolicy{
+		TypeMeta:   storageapi.RetentionPolicyResourceInfo.TypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "policy"},
+		Spec: storageapi.RetentionPolicySpec{Rules: []storageapi.RetentionRule{
+			{Group: "group", Resource: "resource", MaxVersions: 3},
+			{Group: "group", Resource: "aged", MaxAge: metav1.Duration{Duration: time.Millisecond}, MinVersions: 2},
+			{Group: "group", Resource: "trashed", MaxAge: metav1.Duration{Duration: time.Millisecond}},
+		}},
+	})
+	require.NoError(t, err)
+	_, err = test.WriteEvent(ctx, storageBackend, "policy", resourcepb.WatchEvent_ADDED,
+		test.WithGroup(storageapi.GROUP),
+		test.WithResource(storageapi.RetentionPolicyResourceInfo.GroupResource().Resource),
+		func(o *test.WriteEventOptions) { o.Value = policy })
+	require.NoError(t, err)
+
+	writeVersions := func(name, res string, count int) {
+		rv, err := test.WriteEvent(ctx, storageBackend, name, resourcepb.WatchEvent_ADDED, test.WithResource(res))
+		require.NoError(t, err)
+		for range count - 1 {
+			rv, err = test.WriteEvent(ctx, storageBackend, name, resourcepb.WatchEvent_MODIFIED, test.WithResource(res), test.WithNamespaceAndRV("namespace", rv))
+			require.NoError(t, err)
+		}
+	}
+	countVersions := func(name, res string) int {
+		rsp, err := server.List(ctx, &resourcepb.ListRequest{
+			Source: resourcepb.ListRequest_HISTORY,
+			Options: &resourcepb.ListOptions{
+				Key: &resourcepb.ResourceKey{Namespace: "namespace", Group: "group", Resource: res, Name: name},
+			},
+		})
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		return len(rsp.Items)
+	}
+
+	writeVersions("counted", "resource", 5)
+	writeVersions("old", "aged", 4)
+	require.NoError(t, b.pruneHistory(ctx, resource.PruningKey{Namespace: "namespace", Group: "group", Resource: "resource", Name: "counted"}))
+	require.Equal(t, 3, countVersions("counted", "resource"))
+
+	t.Run("the sweep prunes the old versions", func(t *testing.T) {
+		time.Sleep(10 * time.Millisecond)
+		require.Equal(t, 4, countVersions("old", "aged"))
+		require.NoError(t, b.sweepRetention(ctx))
+		require.Equal(t, 2, countVersions("old", "aged"))
+		// the rule of the other collection has no age limit
+		require.Equal(t, 3, countVersions("counted", "resource"))
+	})
+
+	t.Run("the sweep keeps the versions of trashed resources", func(t *testing.T) {
+		rv, err := test.WriteEvent(ctx, storageBackend, "deleted", resourcepb.WatchEvent_ADDED, test.WithResource("trashed"))
+		require.NoError(t, err)
+		for range 2 {
+			rv, err = test.WriteEvent(ctx, storageBackend, "deleted", resourcepb.WatchEvent_MODIFIED, test.WithResource("trashed"), test.WithNamespaceAndRV("namespace", rv))
+			require.NoError(t, err)
+		}
+		_, err = test.WriteEvent(ctx, storageBackend, "deleted", resourcepb.WatchEvent_DELETED, test.WithResource("trashed"), test.WithNamespaceAndRV("namespace", rv))
+		require.NoError(t, err)
+
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, b.sweepRetention(ctx))
+
+		// the version before the deletion is kept so the resource can be restored
+		rsp := b.ReadResource(ctx, &resourcepb.ReadRequest{
+			Key:             &resourcepb.ResourceKey{Namespace: "namespace", Group: "group", Resource: "trashed", Name: "deleted"},
+			ResourceVersion: rv,
+		})
+		require.Nil(t, rsp.Error)
+		require.Equal(t, rv, rsp.ResourceVersion)
+	})
+}
@@ -0,0 +1,24 @@
+{{/* Find the revisions older than the cutoff that are neither the latest revision of a resource, nor a deletion. */}}
+SELECT
+    {{ .Ident "guid" | .Into .Response.GUID }},
+    {{ .Ident "name" | .Into .Response.Name }},
+    {{ .Ident "folder" | .Into .Response.Folder }},
+    {{ .Ident "action" | .Into .Response.Action }},
+    {{ .Ident "resource_version" | .Into .Response.ResourceVersion }},
+    {{ .Ident "value" | .Into .Response.Value }}
+FROM {{ .Ident "resource_history" }} h
+WHERE h.{{ .Ident "namespace" }} = {{ .Arg .Namespace }}
+  AND h.{{ .Ident "group" }} = {{ .Arg .Group }}
+  AND h.{{ .Ident "resource" }} = {{ .Arg .Resource }}
+  AND h.{{ .Ident "resource_version" }} < {{ .Arg .CutoffRV }}
+  AND h.{{ .Ident "action" }} <> 3
+  AND EXISTS (
+    SELECT 1 FROM {{ .Ident "resource_history" }} n
+    WHERE n.{{ .Ident "namespace" }} = h.{{ .Ident "namespace" }}
+      AND n.{{ .Ident "group" }} = h.{{ .Ident "group" }}
+      AND n.{{ .Ident "resource" }} = h.{{ .Ident "resource" }}
+      AND n.{{ .Ident "name" }} = h.{{ .Ident "name" }}
+      AND n.{{ .Ident "resource_version" }} > h.{{ .Ident "resource_version" }}
+  )
+ORDER BY h.{{ .Ident "resource_version" }} ASC
+LIMIT {{ .Arg .BatchSize }};
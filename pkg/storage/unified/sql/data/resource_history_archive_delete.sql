@@ -0,0 +1,5 @@
+DELETE FROM {{ .Ident "resource_history" }}
+WHERE {{ .Ident "namespace" }} = {{ .Arg .Namespace }}
+  AND {{ .Ident "group" }} = {{ .Arg .Group }}
+  AND {{ .Ident "resource" }} = {{ .Arg .Resource }}
+  AND {{ .Ident "guid" }} IN ({{ .ArgList .GUIDs }});
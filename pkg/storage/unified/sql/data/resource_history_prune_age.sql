@@ -0,0 +1,33 @@
+DELETE FROM {{ .Ident "resource_history" }}
+WHERE {{ .Ident "guid" }} IN (
+  SELECT {{ .Ident "guid" }}
+  FROM (
+  SELECT
+    {{ .Ident "guid" }},
+    {{ .Ident "resource_version" }},
+    ROW_NUMBER() OVER (
+      PARTITION BY {{ .Ident "namespace" }}
+        , {{ .Ident "group" }}
+        , {{ .Ident "resource" }}
+        , {{ .Ident "name" }}
+      ORDER BY {{ .Ident "resource_version" }} DESC
+    ) AS {{ .Ident "rn" }},
+    FIRST_VALUE({{ .Ident "action" }}) OVER (
+      PARTITION BY {{ .Ident "namespace" }}
+        , {{ .Ident "group" }}
+        , {{ .Ident "resource" }}
+        , {{ .Ident "name" }}
+      ORDER BY {{ .Ident "resource_version" }} DESC
+    ) AS {{ .Ident "latest_action" }}
+  FROM {{ .Ident "resource_history" }}
+  WHERE {{ .Ident "namespace" }} = {{ .Arg .Key.Namespace }}
+    AND {{ .Ident "group" }} = {{ .Arg .Key.Group }}
+    AND {{ .Ident "resource" }} = {{ .Arg .Key.Resource }}
+    {{ if .Key.Name }}
+    AND {{ .Ident "name" }} = {{ .Arg .Key.Name }}
+    {{ end }}
+  ) AS {{ .Ident "ranked" }}
+  WHERE {{ .Ident "rn" }} > {{ .Arg .KeepVersions }}
+    AND {{ .Ident "resource_version" }} < {{ .Arg .CutoffRV }}
+    AND {{ .Ident "latest_action" }} <> 3
+);
@@ -243,6 +243,26 @@ func initResourceTables(mg *migrator.Migrator) string {
 	mg.AddMigration("create table "+pending_tenant_deletions_table.Name, migrator.NewAddTableMigration(pending_tenant_deletions_table))
 	mg.AddMigration("Change key_path collation of pending_tenant_deletions in postgres", migrator.NewRawSQLMigration("").Postgres(`ALTER TABLE pending_tenant_deletions ALTER COLUMN key_path TYPE VARCHAR(2048) COLLATE "C";`))
 
+	resource_webhooks_table := migrator.Table{
+		Name: "resource_webhooks",
+		Columns: []*migrator.Column{
+			{Name: "key_path", Type: migrator.DB_NVarchar, Length: 2048, Nullable: false, IsPrimaryKey: true, IsLatin: true},
+			{Name: "value", Type: migrator.DB_MediumText, Nullable: false},
+		},
+	}
+	mg.AddMigration("create table "+resource_webhooks_table.Name, migrator.NewAddTableMigration(resource_webhooks_table))
+	mg.AddMigration("Change key_path collation of resource_webhooks in postgres", migrator.NewRawSQLMigration("").Postgres(`ALTER TABLE resource_webhooks ALTER COLUMN key_path TYPE VARCHAR(2048) COLLATE "C";`))
+
+	resource_kv_blobs_table := migrator.Table{
+		Name: "resource_kv_blobs",
+		Columns: []*migrator.Column{
+			{Name: "key_path", Type: migrator.DB_NVarchar, Length: 2048, Nullable: false, IsPrimaryKey: true, IsLatin: true},
+			{Name: "value", Type: migrator.DB_LongBlob, Nullable: false},
+		},
+	}
+	mg.AddMigration("create table "+resource_kv_blobs_table.Name, migrator.NewAddTableMigration(resource_kv_blobs_table))
+	mg.AddMigration("Change key_path collation of resource_kv_blobs in postgres", migrator.NewRawSQLMigration("").Postgres(`ALTER TABLE resource_kv_blobs ALTER COLUMN key_path TYPE VARCHAR(2048) COLLATE "C";`))
+
 	return marker
 }
 
@@ -0,0 +1,264 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// number of history rows read for each list call during a migration
+const kvMigrationPageSize = 1000
+
+// KVMigrationResult is the outcome of the migration of a namespace and resource
+type KVMigrationResult struct {
+	resource.NamespacedResource
+
+	// Number of revisions imported
+	Revisions int64
+	// Number of blobs copied
+	Blobs int64
+	// Revisions, and blobs referenced by a revision, that could not be imported
+	Rejected []*resourcepb.BulkResponse_Rejected
+}
+
+// kvMigrationTarget is the backend receiving the migration, it keeps the uid of the copied blobs
+// so the migrated objects still reference them
+type kvMigrationTarget interface {
+	resource.BulkProcessingBackend
+	resource.BlobImporter
+}
+
+// blobRef is a blob referenced by a revision of an object
+type blobRef struct {
+	key *resourcepb.ResourceKey
+	uid string
+}
+
+// MigrateToKV copies every resource of the SQL backend, with its history, into a KV backend such as the file backend.
+// Each namespace and resource is replaced in the target by a bulk import, so the migration can safely be run again.
+// The history keeps its order, but gets new resource versions. The blobs referenced by the history are copied
+// with their uid, the migration is refused when the target can not import them.
+func MigrateToKV(ctx context.Context, source Backend, target resource.BulkProcessingBackend) ([]KVMigrationResult, error) {
+	blobSource, ok := source.(resource.BlobSupport)
+	if !ok {
+		return nil, fmt.Errorf("the source storage does not support blobs")
+	}
+	blobTarget, ok := target.(kvMigrationTarget)
+	if !ok {
+		return nil, fmt.Errorf("the target storage can not import blobs")
+	}
+
+	stats, err := source.GetResourceStats(ctx, resource.NamespacedResource{}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("list resources: %w", err)
+	}
+
+	results := make([]KVMigrationResult, 0, len(stats))
+	for _, stat := range stats {
+		key := &resourcepb.ResourceKey{Namespace: stat.Namespace, Group: stat.Group, Resource: stat.Resource}
+		rsp, blobs, err := migrateCollectionToKV(ctx, source, blobTarget, key)
+		if err != nil {
+			return results, fmt.Errorf("migrate %s: %w", stat.String(), err)
+		}
+		result := KVMigrationResult{
+			NamespacedResource: stat.NamespacedResource,
+			Revisions:          rsp.Processed - int64(len(rsp.Rejected)),
+			Rejected:           rsp.Rejected,
+		}
+		for _, blob := range blobs {
+			rejected, err := copyBlobToKV(ctx, blobSource, blobTarget, blob)
+			if err != nil {
+				return results, fmt.Errorf("migrate %s: copy blob %s of %s: %w", stat.String(), blob.uid, blob.key.Name, err)
+			}
+			if rejected != nil {
+				result.Rejected = append(result.Rejected, rejected)
+				continue
+			}
+			result.Blobs++
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// copyBlobToKV copies a blob with its uid. A blob that no longer exists in the source is rejected.
+func copyBlobToKV(ctx context.Context, source resource.BlobSupport, target resource.BlobImporter, blob blobRef) (*resourcepb.BulkResponse_Rejected, error) {
+	rsp, err := source.GetResourceBlob(ctx, blob.key, &utils.BlobInfo{UID: blob.uid}, true)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.Error != nil {
+		if rsp.Error.Code == http.StatusNotFound {
+			return &resourcepb.BulkResponse_Rejected{
+				Key:   blob.key,
+				Error: fmt.Sprintf("blob %s not found", blob.uid),
+			}, nil
+		}
+		return nil, fmt.Errorf("%s (%d)", rsp.Error.Message, rsp.Error.Code)
+	}
+	return nil, target.ImportResourceBlob(ctx, blob.key, blob.uid, rsp.ContentType, rsp.Value)
+}
+
+// migrateCollectionToKV imports the history of the collection, and returns the blobs it references
+func migrateCollectionToKV(ctx context.Context, source Backend, target resource.BulkProcessingBackend, key *resourcepb.ResourceKey) (*resourcepb.BulkResponse, []blobRef, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The history is streamed into the bulk import, which needs to see the whole collection in a single call
+	items := make(chan historyBulkItem, 100)
+	var readErr error
+	var blobs []blobRef
+	go func() {
+		defer close(items)
+		seen := map[string]bool{}
+		readErr = readCollectionHistory(ctx, source, key, func(req *resourcepb.BulkRequest, blob *utils.BlobInfo) error {
+			if blob != nil && !seen[req.Key.Name+"/"+blob.UID] {
+				seen[req.Key.Name+"/"+blob.UID] = true
+				blobs = append(blobs, blobRef{key: req.Key, uid: blob.UID})
+			}
+			select {
+			case items <- historyBulkItem{req: req}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if readErr != nil {
+			// makes the import roll back
+			select {
+			case items <- historyBulkItem{err: readErr}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	rsp := target.ProcessBulk(ctx, resource.BulkSettings{
+		Collection: []*resourcepb.ResourceKey{key},
+	}, &historyBulkIterator{items: items})
+
+	cancel()
+	for range items {
+		// wait for the reader to stop
+	}
+
+	if rsp.Error != nil {
+		return rsp, nil, fmt.Errorf("bulk import: %s", rsp.Error.Message)
+	}
+	if readErr != nil {
+		return rsp, nil, fmt.Errorf("read history: %w", readErr)
+	}
+	return rsp, blobs, nil
+}
+
+// readCollectionHistory calls fn with every revision of the collection, oldest first, and the blob it references
+func readCollectionHistory(ctx context.Context, source Backend, key *resourcepb.ResourceKey, fn func(*resourcepb.BulkRequest, *utils.BlobInfo) error) error {
+	req := &resourcepb.ListRequest{
+		Source: resourcepb.ListRequest_HISTORY,
+		// NotOlderThan lists the history in ascending order, and a resource version
+		// includes the revisions before the last deletion
+		ResourceVersion: 1,
+		VersionMatchV2:  resourcepb.ResourceVersionMatchV2_NotOlderThan,
+		Limit:           kvMigrationPageSize,
+		Options:         &resourcepb.ListOptions{Key: key},
+	}
+
+	live := map[string]bool{}
+	for {
+		var next string
+		_, err := source.ListHistory(ctx, req, func(iter resource.ListIterator) error {
+			count := 0
+			var lastToken string
+			for iter.Next() {
+				if err := iter.Error(); err != nil {
+					return err
+				}
+				if count >= int(req.Limit) {
+					next = lastToken
+					break
+				}
+
+				obj := &unstructured.Unstructured{}
+				if err := obj.UnmarshalJSON(iter.Value()); err != nil {
+					return fmt.Errorf("read %s at %d: %w", iter.Name(), iter.ResourceVersion(), err)
+				}
+				meta, err := utils.MetaAccessor(obj)
+				if err != nil {
+					return fmt.Errorf("read %s at %d: %w", iter.Name(), iter.ResourceVersion(), err)
+				}
+				blob := meta.GetBlob()
+				if blob != nil && blob.UID == "" {
+					blob = nil
+				}
+				action := resourcepb.BulkRequest_ADDED
+				switch {
+				case obj.GetGeneration() == utils.DeletedGeneration:
+					action = resourcepb.BulkRequest_DELETED
+					delete(live, iter.Name())
+				case live[iter.Name()]:
+					action = resourcepb.BulkRequest_MODIFIED
+				default:
+					live[iter.Name()] = true
+				}
+
+				err := fn(&resourcepb.BulkRequest{
+					Key: &resourcepb.ResourceKey{
+						Namespace: key.Namespace,
+						Group:     key.Group,
+						Resource:  key.Resource,
+						Name:      iter.Name(),
+					},
+					Action: action,
+					Folder: iter.Folder(),
+					Value:  iter.Value(),
+				}, blob)
+				if err != nil {
+					return err
+				}
+				count++
+				lastToken = iter.ContinueToken()
+			}
+			return iter.Error()
+		})
+		if err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		req.NextPageToken = next
+	}
+}
+
+type historyBulkItem struct {
+	req *resourcepb.BulkRequest
+	err error
+}
+
+// historyBulkIterator adapts the history read from the SQL backend to resource.BulkRequestIterator
+type historyBulkIterator struct {
+	items   <-chan historyBulkItem
+	current historyBulkItem
+}
+
+func (i *historyBulkIterator) Next() bool {
+	item, ok := <-i.items
+	if !ok {
+		return false
+	}
+	i.current = item
+	return true
+}
+
+func (i *historyBulkIterator) Request() *resourcepb.BulkRequest {
+	return i.current.req
+}
+
+func (i *historyBulkIterator) RollbackRequested() bool {
+	return i.current.err != nil
+}
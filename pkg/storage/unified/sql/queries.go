@@ -46,8 +46,11 @@ var (
 	sqlResourceHistoryGet                  = mustTemplate("resource_history_get.sql")
 	sqlResourceHistoryDelete               = mustTemplate("resource_history_delete.sql")
 	sqlResourceHistoryPrune                = mustTemplate("resource_history_prune.sql")
+	sqlResourceHistoryPruneAge             = mustTemplate("resource_history_prune_age.sql")
 	sqlResourceHistoryGarbageGetCandidates = mustTemplate("resource_history_gc_get_candidates.sql")
 	sqlResourceHistoryGCDeleteByNames      = mustTemplate("resource_history_gc_delete_by_names.sql")
+	sqlResourceHistoryArchiveCandidates    = mustTemplate("resource_history_archive_candidates.sql")
+	sqlResourceHistoryArchiveDelete        = mustTemplate("resource_history_archive_delete.sql")
 	sqlResourceTrash                       = mustTemplate("resource_trash.sql")
 	sqlResourceInsertFromHistory           = mustTemplate("resource_insert_from_history.sql")
 
@@ -354,6 +357,34 @@ func (r *sqlPruneHistoryRequest) Validate() error {
 	return nil
 }
 
+// sqlPruneHistoryAgeRequest removes the versions older than the cutoff, of a resource
+// or of all the resources of a collection when the key has no name
+type sqlPruneHistoryAgeRequest struct {
+	sqltemplate.SQLTemplate
+	Key          *resourcepb.ResourceKey
+	KeepVersions int64 // the latest versions kept whatever their age
+	CutoffRV     int64
+}
+
+func (r *sqlPruneHistoryAgeRequest) Validate() error {
+	if r.KeepVersions <= 0 {
+		return fmt.Errorf("the latest version must be kept")
+	}
+	if r.CutoffRV <= 0 {
+		return fmt.Errorf("missing cutoff")
+	}
+	if r.Key == nil {
+		return fmt.Errorf("missing key")
+	}
+	if r.Key.Group == "" {
+		return fmt.Errorf("missing group")
+	}
+	if r.Key.Resource == "" {
+		return fmt.Errorf("missing resource")
+	}
+	return nil
+}
+
 type gcCandidateName struct {
 	Namespace string
 	Name      string
@@ -409,6 +440,73 @@ func (r *sqlGarbageCollectDeleteByNamesRequest) Validate() error {
 	return nil
 }
 
+type archiveCandidate struct {
+	GUID            string
+	Name            string
+	Folder          string
+	Action          int
+	ResourceVersion int64
+	Value           []byte
+}
+
+type sqlArchiveCandidatesRequest struct {
+	sqltemplate.SQLTemplate
+	Namespace string
+	Group     string
+	Resource  string
+	CutoffRV  int64
+	BatchSize int
+	Response  *archiveCandidate
+}
+
+func (r *sqlArchiveCandidatesRequest) Validate() error {
+	if r.Namespace == "" {
+		return fmt.Errorf("missing namespace")
+	}
+	if r.Group == "" {
+		return fmt.Errorf("missing group")
+	}
+	if r.Resource == "" {
+		return fmt.Errorf("missing resource")
+	}
+	if r.CutoffRV <= 0 {
+		return fmt.Errorf("invalid cutoff resource version")
+	}
+	if r.BatchSize <= 0 {
+		return fmt.Errorf("invalid batch size")
+	}
+	return nil
+}
+
+func (r *sqlArchiveCandidatesRequest) Results() (archiveCandidate, error) {
+	x := *r.Response
+	return x, nil
+}
+
+type sqlArchiveDeleteRequest struct {
+	sqltemplate.SQLTemplate
+	Namespace string
+	Group     string
+	Resource  string
+	GUIDs     []string
+}
+
+func (r *sqlArchiveDeleteRequest) Validate() error {
+	if r.Namespace == "" {
+		return fmt.Errorf("missing namespace")
+	}
+	if r.Group == "" {
+		return fmt.Errorf("missing group")
+	}
+	if r.Resource == "" {
+		return fmt.Errorf("missing resource")
+	}
+	if len(r.GUIDs) == 0 {
+		return fmt.Errorf("missing guids")
+	}
+	return nil
+}
+
 type sqlResourceBlobInsertRequest struct {
 	sqltemplate.SQLTemplate
 	Now         time.Time
@@ -176,6 +176,32 @@ func TestUnifiedStorageQueries(t *testing.T) {
 					},
 				},
 			},
+			sqlResourceHistoryArchiveCandidates: {
+				{
+					Name: "single path",
+					Data: &sqlArchiveCandidatesRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Namespace:   "ns",
+						Group:       "group",
+						Resource:    "res",
+						CutoffRV:    123456,
+						BatchSize:   100,
+						Response:    new(archiveCandidate),
+					},
+				},
+			},
+			sqlResourceHistoryArchiveDelete: {
+				{
+					Name: "single path",
+					Data: &sqlArchiveDeleteRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Namespace:   "ns",
+						Group:       "group",
+						Resource:    "res",
+						GUIDs:       []string{"guid1", "guid2"},
+					},
+				},
+			},
 			sqlResourceHistoryPoll: {
 				{
 					Name: "single path",
@@ -412,6 +438,36 @@ func TestUnifiedStorageQueries(t *testing.T) {
 				},
 			},
 
+			sqlResourceHistoryPruneAge: {
+				{
+					Name: "single-resource",
+					Data: &sqlPruneHistoryAgeRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Key: &resourcepb.ResourceKey{
+							Namespace: "default",
+							Group:     "dashboard.grafana.app",
+							Resource:  "dashboards",
+							Name:      "dash-xyz",
+						},
+						KeepVersions: 2,
+						CutoffRV:     1700000000000000,
+					},
+				},
+				{
+					Name: "collection",
+					Data: &sqlPruneHistoryAgeRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Key: &resourcepb.ResourceKey{
+							Namespace: "default",
+							Group:     "dashboard.grafana.app",
+							Resource:  "dashboards",
+						},
+						KeepVersions: 2,
+						CutoffRV:     1700000000000000,
+					},
+				},
+			},
+
 			rvmanager.SqlResourceVersionGet: {
 				{
 					Name: "single path",
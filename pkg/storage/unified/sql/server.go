@@ -204,15 +204,19 @@ func withOverridesService(opts *ServerOptions, resourceOpts *resource.ResourceSe
 }
 
 func withQuotaConfig(opts *ServerOptions, resourceOpts *resource.ResourceServerOptions) error {
-	enforced := make(map[string]bool, len(opts.Cfg.EnforcedQuotaResources))
-	for _, r := range opts.Cfg.EnforcedQuotaResources {
+	resourceOpts.QuotasConfig = newQuotasConfig(opts.Cfg)
+	return nil
+}
+
+func newQuotasConfig(cfg *setting.Cfg) resource.QuotasConfig {
+	enforced := make(map[string]bool, len(cfg.EnforcedQuotaResources))
+	for _, r := range cfg.EnforcedQuotaResources {
 		enforced[r] = true
 	}
-	resourceOpts.QuotasConfig = resource.QuotasConfig{
+	return resource.QuotasConfig{
 		EnforcedResources: enforced,
-		SupportMessage:    opts.Cfg.QuotasErrorMessageSupportInfo,
+		SupportMessage:    cfg.QuotasErrorMessageSupportInfo,
 	}
-	return nil
 }
 
 func withStorageMetrics(opts *ServerOptions, resourceOpts *resource.ResourceServerOptions) error {
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -15,8 +16,10 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/grafana/authlib/grpcutils"
+	authlib "github.com/grafana/authlib/types"
 	"github.com/grafana/dskit/kv"
 	"github.com/grafana/dskit/netutil"
 	"github.com/grafana/dskit/ring"
@@ -61,6 +64,7 @@ type service struct {
 	storageMetrics *resource.StorageMetrics
 	scheduler      *scheduler.Scheduler
 	searchClient   resourcepb.ResourceIndexClient
+	quotaHandler   atomic.Pointer[http.Handler]
 
 	// -- Search Services
 	docBuilders      resource.DocumentBuilderSupplier
@@ -152,6 +156,10 @@ func ProvideUnifiedStorageGrpcService(cfg *setting.Cfg,
 		}
 	}
 
+	if cfg.OverridesFilePath != "" && httpServerRouter != nil {
+		httpServerRouter.PathPrefix("/quotas/").Handler(http.StripPrefix("/quotas", http.HandlerFunc(s.serveQuotas)))
+	}
+
 	if cfg.QOSEnabled {
 		qosReg := prometheus.WrapRegistererWithPrefix("resource_server_qos_", reg)
 		queue := scheduler.NewQueue(&scheduler.QueueOptions{
@@ -386,11 +394,44 @@ func (s *service) registerServer(provider grpcserver.Provider) error {
 			return err
 		}
 		serverOptions.OverridesService = overridesSvc
+
+		// the quotas are authorized like the resource server requests, and denied without an authz client
+		var quotaAccess authlib.AccessClient = authlib.FixedAccessClient(false)
+		if authzClient != nil {
+			quotaAccess = resource.NewAuthzLimitedClient(authzClient, resource.AuthzOptions{Registry: s.reg})
+		}
+		var quotaHandler http.Handler = resource.NewQuotaHandler(resource.NewQuotaReporter(s.backend, overridesSvc, newQuotasConfig(s.cfg)), quotaAccess)
+		s.quotaHandler.Store(&quotaHandler)
 	}
 
 	return s.createAndRegisterServer(provider, serverOptions)
 }
 
+// serveQuotas serves the quota usage once the overrides are loaded.
+// The requests are authenticated like the gRPC requests, with the credentials sent as headers.
+func (s *service) serveQuotas(w http.ResponseWriter, r *http.Request) {
+	handler := s.quotaHandler.Load()
+	if handler == nil {
+		http.Error(w, "quota overrides are not loaded", http.StatusServiceUnavailable)
+		return
+	}
+	if s.authenticator == nil {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	md := metadata.MD{}
+	for key, values := range r.Header {
+		md.Append(key, values...)
+	}
+	ctx, err := s.authenticator(metadata.NewIncomingContext(r.Context(), md))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	(*handler).ServeHTTP(w, r.WithContext(ctx))
+}
+
 func (s *service) running(ctx context.Context) error {
 	select {
 	case err := <-s.subservicesWatcher.Chan():
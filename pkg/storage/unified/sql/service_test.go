@@ -3,6 +3,8 @@ package sql
 import (
 	"context"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"sync/atomic"
 	"testing"
 
@@ -13,8 +15,12 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	authlib "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/grpcserver"
 	"github.com/grafana/grafana/pkg/services/grpcserver/interceptors"
@@ -202,3 +208,39 @@ func TestRegisterUnifiedResourceServerWithAuth(t *testing.T) {
 		require.Greater(t, authCalled.Load(), int32(0))
 	})
 }
+
+func TestServeQuotasAuthentication(t *testing.T) {
+	var quotaHandler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := authlib.AuthInfoFrom(r.Context())
+		require.True(t, ok)
+		_, _ = w.Write([]byte(user.GetName()))
+	})
+	s := &service{
+		authenticator: func(ctx context.Context) (context.Context, error) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			if len(md.Get("x-access-token")) == 0 {
+				return nil, status.Error(codes.Unauthenticated, "missing token")
+			}
+			return identity.WithRequester(ctx, &identity.StaticRequester{Name: md.Get("x-access-token")[0]}), nil
+		},
+	}
+
+	serve := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/namespaces/default", nil)
+		if token != "" {
+			req.Header.Set("X-Access-Token", token)
+		}
+		rec := httptest.NewRecorder()
+		s.serveQuotas(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusServiceUnavailable, serve("token").Code, "the overrides are not loaded")
+
+	s.quotaHandler.Store(&quotaHandler)
+	require.Equal(t, http.StatusUnauthorized, serve("").Code)
+
+	rec := serve("token")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "token", rec.Body.String())
+}
@@ -0,0 +1,210 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/storage/unified/sql"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestIntegrationMigrateToKV(t *testing.T) {
+	testutil.SkipIntegrationTestInShortMode(t)
+	t.Cleanup(db.CleanupTestDB)
+
+	ctx := identity.WithRequester(testutil.NewTestContext(t, time.Now().Add(2*time.Minute)), &identity.StaticRequester{
+		Type:           types.TypeUser,
+		UserID:         1,
+		UserUID:        "user-uid-1",
+		OrgID:          1,
+		Login:          "testuser",
+		OrgRole:        identity.RoleAdmin,
+		IsGrafanaAdmin: true,
+	})
+
+	source, ok := newTestBackend(t, false, 0, 0).(sql.Backend)
+	require.True(t, ok)
+	server, err := resource.NewResourceServer(resource.ResourceServerOptions{Backend: source})
+	require.NoError(t, err)
+
+	key := func(name string) *resourcepb.ResourceKey {
+		return &resourcepb.ResourceKey{Namespace: "default", Group: "migrate.grafana.app", Resource: "items", Name: name}
+	}
+	value := func(name, title string, blob *utils.BlobInfo) []byte {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "migrate.grafana.app/v0alpha1",
+			"kind":       "Item",
+			"metadata":   map[string]any{"name": name, "namespace": "default"},
+			"spec":       map[string]any{"title": title},
+		}}
+		if blob != nil {
+			meta, err := utils.MetaAccessor(obj)
+			require.NoError(t, err)
+			meta.SetBlob(blob)
+		}
+		raw, err := obj.MarshalJSON()
+		require.NoError(t, err)
+		return raw
+	}
+
+	// More history rows than a migration page: every item is created and updated,
+	// and one item out of ten is deleted
+	const items = 520
+	revisions := int64(0)
+	for i := range items {
+		name := fmt.Sprintf("item-%03d", i)
+		created, err := server.Create(ctx, &resourcepb.CreateRequest{Key: key(name), Value: value(name, "created", nil)})
+		require.NoError(t, err)
+		require.Nil(t, created.Error)
+		updated, err := server.Update(ctx, &resourcepb.UpdateRequest{Key: key(name), ResourceVersion: created.ResourceVersion, Value: value(name, "updated", nil)})
+		require.NoError(t, err)
+		require.Nil(t, updated.Error)
+		revisions += 2
+		if i%10 == 0 {
+			deleted, err := server.Delete(ctx, &resourcepb.DeleteRequest{Key: key(name), ResourceVersion: updated.ResourceVersion})
+			require.NoError(t, err)
+			require.Nil(t, deleted.Error)
+			revisions++
+		}
+	}
+
+	// An item with a blob, and one referencing a blob that no longer exists
+	blobStore, ok := source.(resource.BlobSupport)
+	require.True(t, ok)
+	blob, err := blobStore.PutResourceBlob(ctx, &resourcepb.PutBlobRequest{
+		Resource:    key("with-blob"),
+		Method:      resourcepb.PutBlobRequest_GRPC,
+		ContentType: "text/plain",
+		Value:       []byte("hello blob"),
+	})
+	require.NoError(t, err)
+	require.Nil(t, blob.Error)
+	created, err := server.Create(ctx, &resourcepb.CreateRequest{Key: key("with-blob"), Value: value("with-blob", "blob", &utils.BlobInfo{UID: blob.Uid})})
+	require.NoError(t, err)
+	require.Nil(t, created.Error)
+	created, err = server.Create(ctx, &resourcepb.CreateRequest{Key: key("missing-blob"), Value: value("missing-blob", "blob", &utils.BlobInfo{UID: "missing"})})
+	require.NoError(t, err)
+	require.Nil(t, created.Error)
+	revisions += 2
+
+	kvDB, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = kvDB.Close() })
+	target, err := resource.NewKVStorageBackend(resource.KVBackendOptions{KvStore: resource.NewBadgerKV(kvDB)})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = target.Stop(context.Background()) })
+	bulk, ok := target.(resource.BulkProcessingBackend)
+	require.True(t, ok)
+
+	results, err := sql.MigrateToKV(ctx, source, bulk)
+	require.NoError(t, err)
+	idx := slices.IndexFunc(results, func(r sql.KVMigrationResult) bool {
+		return r.Namespace == "default" && r.Group == "migrate.grafana.app" && r.Resource == "items"
+	})
+	require.GreaterOrEqual(t, idx, 0)
+	result := results[idx]
+	require.Equal(t, revisions, result.Revisions)
+	require.Equal(t, int64(1), result.Blobs)
+	require.Len(t, result.Rejected, 1)
+	require.Equal(t, "missing-blob", result.Rejected[0].Key.Name)
+
+	t.Run("the latest objects match", func(t *testing.T) {
+		expected := listTitles(t, ctx, source, key(""))
+		require.Len(t, expected, items-items/10+2)
+		require.Equal(t, expected, listTitles(t, ctx, target, key("")))
+	})
+
+	t.Run("the history matches", func(t *testing.T) {
+		for _, name := range []string{"item-001", "item-259", "item-519", "with-blob"} {
+			expected := historyTitles(t, ctx, source, key(name))
+			require.NotEmpty(t, expected)
+			require.Equal(t, expected, historyTitles(t, ctx, target, key(name)), name)
+		}
+	})
+
+	t.Run("the deleted objects are not migrated as live objects", func(t *testing.T) {
+		rsp := target.ReadResource(ctx, &resourcepb.ReadRequest{Key: key("item-010")})
+		require.NotNil(t, rsp.Error)
+		require.Equal(t, int32(http.StatusNotFound), rsp.Error.Code)
+	})
+
+	t.Run("the blobs keep their uid", func(t *testing.T) {
+		targetBlobs, ok := target.(resource.BlobSupport)
+		require.True(t, ok)
+		rsp, err := targetBlobs.GetResourceBlob(ctx, key("with-blob"), &utils.BlobInfo{UID: blob.Uid}, true)
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		require.Equal(t, "hello blob", string(rsp.Value))
+		require.Equal(t, "text/plain", rsp.ContentType)
+	})
+}
+
+// listTitles returns the title of every object of the collection
+func listTitles(t *testing.T, ctx context.Context, backend resource.StorageBackend, key *resourcepb.ResourceKey) map[string]string {
+	t.Helper()
+	titles := map[string]string{}
+	req := &resourcepb.ListRequest{Limit: 100, Options: &resourcepb.ListOptions{Key: key}}
+	for {
+		var next string
+		_, err := backend.ListIterator(ctx, req, func(iter resource.ListIterator) error {
+			count := 0
+			var lastToken string
+			for iter.Next() {
+				if count >= int(req.Limit) {
+					next = lastToken
+					break
+				}
+				titles[iter.Name()] = objectTitle(t, iter.Value())
+				count++
+				lastToken = iter.ContinueToken()
+			}
+			return iter.Error()
+		})
+		require.NoError(t, err)
+		if next == "" {
+			return titles
+		}
+		req.NextPageToken = next
+	}
+}
+
+// historyTitles returns the titles of the revisions of an object
+func historyTitles(t *testing.T, ctx context.Context, backend resource.StorageBackend, key *resourcepb.ResourceKey) []string {
+	t.Helper()
+	var titles []string
+	_, err := backend.ListHistory(ctx, &resourcepb.ListRequest{
+		Source:  resourcepb.ListRequest_HISTORY,
+		Limit:   100,
+		Options: &resourcepb.ListOptions{Key: key},
+	}, func(iter resource.ListIterator) error {
+		for iter.Next() {
+			titles = append(titles, objectTitle(t, iter.Value()))
+		}
+		return iter.Error()
+	})
+	require.NoError(t, err)
+	return titles
+}
+
+func objectTitle(t *testing.T, value []byte) string {
+	t.Helper()
+	obj := &unstructured.Unstructured{}
+	require.NoError(t, obj.UnmarshalJSON(value))
+	title, _, _ := unstructured.NestedString(obj.Object, "spec", "title")
+	return title
+}
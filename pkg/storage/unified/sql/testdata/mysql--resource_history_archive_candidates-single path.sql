@@ -0,0 +1,23 @@
+SELECT
+    `guid`,
+    `name`,
+    `folder`,
+    `action`,
+    `resource_version`,
+    `value`
+FROM `resource_history` h
+WHERE h.`namespace` = 'ns'
+  AND h.`group` = 'group'
+  AND h.`resource` = 'res'
+  AND h.`resource_version` < 123456
+  AND h.`action` <> 3
+  AND EXISTS (
+    SELECT 1 FROM `resource_history` n
+    WHERE n.`namespace` = h.`namespace`
+      AND n.`group` = h.`group`
+      AND n.`resource` = h.`resource`
+      AND n.`name` = h.`name`
+      AND n.`resource_version` > h.`resource_version`
+  )
+ORDER BY h.`resource_version` ASC
+LIMIT 100;
@@ -0,0 +1,5 @@
+DELETE FROM `resource_history`
+WHERE `namespace` = 'ns'
+  AND `group` = 'group'
+  AND `resource` = 'res'
+  AND `guid` IN ('guid1', 'guid2');
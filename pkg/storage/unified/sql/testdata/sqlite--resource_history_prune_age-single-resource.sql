@@ -0,0 +1,31 @@
+DELETE FROM "resource_history"
+WHERE "guid" IN (
+  SELECT "guid"
+  FROM (
+  SELECT
+    "guid",
+    "resource_version",
+    ROW_NUMBER() OVER (
+      PARTITION BY "namespace"
+        , "group"
+        , "resource"
+        , "name"
+      ORDER BY "resource_version" DESC
+    ) AS "rn",
+    FIRST_VALUE("action") OVER (
+      PARTITION BY "namespace"
+        , "group"
+        , "resource"
+        , "name"
+      ORDER BY "resource_version" DESC
+    ) AS "latest_action"
+  FROM "resource_history"
+  WHERE "namespace" = 'default'
+    AND "group" = 'dashboard.grafana.app'
+    AND "resource" = 'dashboards'
+    AND "name" = 'dash-xyz'
+  ) AS "ranked"
+  WHERE "rn" > 2
+    AND "resource_version" < 1700000000000000
+    AND "latest_action" <> 3
+);
@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/tsdb/grafana-postgresql-datasource/sqleng"
+	"github.com/grafana/grafana/pkg/tsdb/sqlcalendar"
 )
 
 const rsIdentifier = `([_a-zA-Z0-9]+)`
@@ -235,6 +236,27 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 			return tg + " AS \"time\"", nil
 		}
 		return "", err
+	case "__timeGroupCalendar":
+		if len(args) < 3 {
+			return "", fmt.Errorf("macro %v needs time column, interval, time zone and optional fill value", name)
+		}
+		interval, err := sqlcalendar.Parse(strings.Trim(args[1], `'`), strings.Trim(args[2], `'`))
+		if err != nil {
+			return "", err
+		}
+		if len(args) == 4 {
+			err := sqleng.SetupCalendarFillmode(query, interval, args[3])
+			if err != nil {
+				return "", err
+			}
+		}
+		return timeGroupCalendar(args[0], interval), nil
+	case "__timeGroupCalendarAlias":
+		tg, err := m.evaluateMacro(timeRange, query, "__timeGroupCalendar", args)
+		if err == nil {
+			return tg + " AS \"time\"", nil
+		}
+		return "", err
 	case "__unixEpochFilter":
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
@@ -274,3 +296,26 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 		return "", fmt.Errorf("unknown macro %q", name)
 	}
 }
+
+// timeGroupCalendar returns the epoch seconds of the start of the calendar interval column is in.
+// Like __timeGroup, timestamps without time zone are taken to be UTC.
+func timeGroupCalendar(column string, interval sqlcalendar.Interval) string {
+	timezone := interval.Location.String()
+	local := fmt.Sprintf("(to_timestamp(extract(epoch from %s)) AT TIME ZONE '%s')", column, timezone)
+
+	var start string
+	switch {
+	case interval.Unit == sqlcalendar.Week:
+		start = fmt.Sprintf("date_trunc('week', %s)", local)
+	case interval.Unit == sqlcalendar.Month && interval.Count == 1:
+		start = fmt.Sprintf("date_trunc('month', %s)", local)
+	case interval.Unit == sqlcalendar.Month:
+		start = fmt.Sprintf("date_trunc('year', %s) + floor((extract(month from %s) - 1) / %d) * %d * interval '1 month'", local, local, interval.Count, interval.Count)
+	case interval.Unit == sqlcalendar.Year:
+		start = fmt.Sprintf("date_trunc('year', %s)", local)
+	default:
+		start = fmt.Sprintf("date_trunc('day', %s)", local)
+	}
+
+	return fmt.Sprintf("extract(epoch from (%s) AT TIME ZONE '%s')", start, timezone)
+}
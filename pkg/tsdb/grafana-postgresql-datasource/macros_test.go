@@ -88,6 +88,41 @@ func TestMacroEngine(t *testing.T) {
 			require.Equal(t, sql2, sql+" AS \"time\"")
 		})
 
+		t.Run("interpolate __timeGroupCalendar function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "SELECT $__timeGroupCalendar(time_column, '1w', 'Europe/Berlin')")
+			require.NoError(t, err)
+			sql2, err := engine.Interpolate(query, timeRange, "SELECT $__timeGroupCalendarAlias(time_column, '1w', 'Europe/Berlin')")
+			require.NoError(t, err)
+
+			require.Equal(t, "SELECT extract(epoch from (date_trunc('week', (to_timestamp(extract(epoch from time_column)) AT TIME ZONE 'Europe/Berlin'))) AT TIME ZONE 'Europe/Berlin')", sql)
+			require.Equal(t, sql2, sql+" AS \"time\"")
+		})
+
+		t.Run("interpolate __timeGroupCalendar function with months", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "SELECT $__timeGroupCalendar(time_column, '1M', 'America/New_York')")
+			require.NoError(t, err)
+			require.Equal(t, "SELECT extract(epoch from (date_trunc('month', (to_timestamp(extract(epoch from time_column)) AT TIME ZONE 'America/New_York'))) AT TIME ZONE 'America/New_York')", sql)
+
+			sql, err = engine.Interpolate(query, timeRange, "SELECT $__timeGroupCalendar(time_column, '3M', 'UTC')")
+			require.NoError(t, err)
+			require.Equal(t, "SELECT extract(epoch from (date_trunc('year', (to_timestamp(extract(epoch from time_column)) AT TIME ZONE 'UTC')) + floor((extract(month from (to_timestamp(extract(epoch from time_column)) AT TIME ZONE 'UTC')) - 1) / 3) * 3 * interval '1 month') AT TIME ZONE 'UTC')", sql)
+		})
+
+		t.Run("interpolate __timeGroupCalendar function with fill", func(t *testing.T) {
+			query := &backend.DataQuery{JSON: []byte(`{}`)}
+			_, err := engine.Interpolate(query, timeRange, "SELECT $__timeGroupCalendar(time_column, '1y', 'Europe/Berlin', 0)")
+			require.NoError(t, err)
+
+			require.JSONEq(t, `{"fill": true, "fillInterval": 0, "fillMode": "value", "fillValue": 0, "fillCalendarInterval": "1y", "fillTimezone": "Europe/Berlin"}`, string(query.JSON))
+		})
+
+		t.Run("interpolate __timeGroupCalendar function with invalid arguments", func(t *testing.T) {
+			_, err := engine.Interpolate(query, timeRange, "SELECT $__timeGroupCalendar(time_column, '1d')")
+			require.Error(t, err)
+			_, err = engine.Interpolate(query, timeRange, "SELECT $__timeGroupCalendar(time_column, '1d', 'Europe/Nowhere')")
+			require.Error(t, err)
+		})
+
 		t.Run("interpolate __timeGroup function with TimescaleDB enabled", func(t *testing.T) {
 			sql, err := engineTS.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'5m')")
 			require.NoError(t, err)
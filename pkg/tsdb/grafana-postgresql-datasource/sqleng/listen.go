@@ -0,0 +1,204 @@
+package sqleng
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// maxChannelNameLength is the maximum length of a postgres identifier.
+const maxChannelNameLength = 63
+
+// payloadFieldName is the name of the field holding notification payloads that are not JSON objects.
+const payloadFieldName = "payload"
+
+// listenQuery is the query model of a listen stream.
+type listenQuery struct {
+	// ListenChannel is the channel to LISTEN on
+	ListenChannel string `json:"listenChannel"`
+	// TimeField is the payload property holding the time of the notification, as epoch or
+	// RFC 3339 string. Notifications without it use the time they were received at.
+	TimeField string `json:"timeField"`
+}
+
+func (q listenQuery) validate() error {
+	if q.ListenChannel == "" {
+		return errors.New("missing listenChannel in listen query")
+	}
+	if len(q.ListenChannel) > maxChannelNameLength {
+		return fmt.Errorf("channel name is longer than %d characters", maxChannelNameLength)
+	}
+	return nil
+}
+
+func (q listenQuery) timeField() string {
+	if q.TimeField == "" {
+		return "time"
+	}
+	return q.TimeField
+}
+
+// runListenStream LISTENs on a channel and sends every notification as a single row frame.
+func (e *DataSourceHandler) runListenStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	var query listenQuery
+	if err := json.Unmarshal(req.Data, &query); err != nil {
+		return fmt.Errorf("error unmarshal query json: %w", err)
+	}
+	if err := query.validate(); err != nil {
+		return err
+	}
+
+	logger := e.log.FromContext(ctx)
+	var prev data.FrameJSONCache
+	err := e.listen(ctx, query.ListenChannel, func(n *pgconn.Notification) error {
+		frame, err := notificationToFrame(n, query.timeField(), time.Now())
+		if err != nil {
+			logger.Warn("Failed to decode notification", "channel", n.Channel, "error", err)
+			return nil
+		}
+
+		next, err := data.FrameToJSONCache(frame)
+		if err != nil {
+			return err
+		}
+		if next.SameSchema(&prev) {
+			err = sender.SendBytes(next.Bytes(data.IncludeDataOnly))
+		} else {
+			err = sender.SendFrame(frame, data.IncludeAll)
+		}
+		prev = next
+		return err
+	})
+	if ctx.Err() != nil {
+		logger.Debug("Stop listening (context canceled)", "channel", query.ListenChannel)
+		return nil
+	}
+	return err
+}
+
+// listen takes a connection out of the pool to LISTEN on channel and calls onNotification for
+// every notification until ctx is done. The connection is closed afterwards rather than returned
+// to the pool, so no other query runs on a connection that is still listening.
+func (e *DataSourceHandler) listen(ctx context.Context, channel string, onNotification func(*pgconn.Notification) error) error {
+	c, err := e.pool.Acquire(ctx)
+	if err != nil {
+		return backend.DownstreamErrorf("failed to acquire connection: %w", err)
+	}
+	conn := c.Hijack()
+	defer func() {
+		if err := conn.Close(context.Background()); err != nil {
+			e.log.Warn("Failed to close listen connection", "err", err)
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return backend.DownstreamError(err)
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		if err := onNotification(n); err != nil {
+			return err
+		}
+	}
+}
+
+// notificationToFrame decodes the payload of a notification into a single row frame. JSON
+// object payloads get a field for each property, sorted by name, after the time field. Other
+// payloads are kept as a string.
+func notificationToFrame(n *pgconn.Notification, timeField string, received time.Time) (*data.Frame, error) {
+	ts := received
+	fields := data.Fields{}
+
+	var payload map[string]any
+	decoder := json.NewDecoder(bytes.NewReader([]byte(n.Payload)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil || payload == nil {
+		fields = append(fields, data.NewField(payloadFieldName, nil, []*string{&n.Payload}))
+	} else {
+		if v, ok := payload[timeField]; ok {
+			t, err := parseNotificationTime(v)
+			if err != nil {
+				return nil, err
+			}
+			ts = t
+			delete(payload, timeField)
+		}
+
+		names := make([]string, 0, len(payload))
+		for name := range payload {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			field, err := notificationField(name, payload[name])
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, field)
+		}
+	}
+
+	timeValues := []time.Time{ts}
+	fields = append(data.Fields{data.NewField(data.TimeSeriesTimeFieldName, nil, timeValues)}, fields...)
+	frame := data.NewFrame(n.Channel, fields...)
+	frame.SetMeta(&data.FrameMeta{ExecutedQueryString: "LISTEN " + pgx.Identifier{n.Channel}.Sanitize()})
+	return frame, nil
+}
+
+func notificationField(name string, value any) (*data.Field, error) {
+	switch v := value.(type) {
+	case nil:
+		return data.NewField(name, nil, []*string{nil}), nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("property %q: %w", name, err)
+		}
+		return data.NewField(name, nil, []*float64{&f}), nil
+	case string:
+		return data.NewField(name, nil, []*string{&v}), nil
+	case bool:
+		return data.NewField(name, nil, []*bool{&v}), nil
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("property %q: %w", name, err)
+		}
+		msg := json.RawMessage(raw)
+		return data.NewField(name, nil, []*json.RawMessage{&msg}), nil
+	}
+}
+
+// parseNotificationTime parses epoch timestamps in seconds, milliseconds or nanoseconds, and RFC 3339 strings.
+func parseNotificationTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(0, int64(epochPrecisionToMS(f))*int64(time.Millisecond)).UTC(), nil
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return time.Unix(0, int64(epochPrecisionToMS(f))*int64(time.Millisecond)).UTC(), nil
+		}
+		return time.Parse(time.RFC3339Nano, v)
+	default:
+		return time.Time{}, fmt.Errorf("unsupported time value %v", value)
+	}
+}
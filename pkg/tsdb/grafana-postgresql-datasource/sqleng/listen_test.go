@@ -0,0 +1,88 @@
+package sqleng
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationToFrame(t *testing.T) {
+	received := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("JSON object payload", func(t *testing.T) {
+		n := &pgconn.Notification{
+			Channel: "sensors",
+			Payload: `{"time": 1709287200, "temperature": 21.5, "device": "d1", "ok": true, "tags": ["a"]}`,
+		}
+		frame, err := notificationToFrame(n, "time", received)
+		require.NoError(t, err)
+
+		require.Equal(t, "sensors", frame.Name)
+		require.Equal(t, 1, frame.Rows())
+		names := make([]string, 0, len(frame.Fields))
+		for _, f := range frame.Fields {
+			names = append(names, f.Name)
+		}
+		require.Equal(t, []string{data.TimeSeriesTimeFieldName, "device", "ok", "tags", "temperature"}, names)
+
+		require.Equal(t, time.Unix(1709287200, 0).UTC(), frame.Fields[0].At(0))
+		require.Equal(t, data.FieldTypeNullableString, frame.Fields[1].Type())
+		require.Equal(t, data.FieldTypeNullableBool, frame.Fields[2].Type())
+		require.Equal(t, data.FieldTypeNullableJSON, frame.Fields[3].Type())
+		temperature, err := frame.Fields[4].FloatAt(0)
+		require.NoError(t, err)
+		require.Equal(t, 21.5, temperature)
+	})
+
+	t.Run("custom time field as RFC 3339 string", func(t *testing.T) {
+		n := &pgconn.Notification{Channel: "events", Payload: `{"at": "2024-03-01T09:00:00Z", "value": 1}`}
+		frame, err := notificationToFrame(n, "at", received)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), frame.Fields[0].At(0))
+		require.Len(t, frame.Fields, 2)
+	})
+
+	t.Run("payload without a time uses the time it was received at", func(t *testing.T) {
+		n := &pgconn.Notification{Channel: "events", Payload: `{"value": 1}`}
+		frame, err := notificationToFrame(n, "time", received)
+		require.NoError(t, err)
+		require.Equal(t, received, frame.Fields[0].At(0))
+	})
+
+	t.Run("plain text payload", func(t *testing.T) {
+		n := &pgconn.Notification{Channel: "events", Payload: "refresh"}
+		frame, err := notificationToFrame(n, "time", received)
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, payloadFieldName, frame.Fields[1].Name)
+		payload, ok := frame.Fields[1].ConcreteAt(0)
+		require.True(t, ok)
+		require.Equal(t, "refresh", payload)
+	})
+
+	t.Run("invalid time", func(t *testing.T) {
+		n := &pgconn.Notification{Channel: "events", Payload: `{"time": "yesterday"}`}
+		_, err := notificationToFrame(n, "time", received)
+		require.Error(t, err)
+	})
+
+	t.Run("nested values are kept as JSON", func(t *testing.T) {
+		n := &pgconn.Notification{Channel: "events", Payload: `{"meta": {"a": 1}}`}
+		frame, err := notificationToFrame(n, "time", received)
+		require.NoError(t, err)
+		meta, ok := frame.Fields[1].ConcreteAt(0)
+		require.True(t, ok)
+		require.JSONEq(t, `{"a": 1}`, string(meta.(json.RawMessage)))
+	})
+}
+
+func TestListenQueryValidate(t *testing.T) {
+	require.NoError(t, listenQuery{ListenChannel: "sensors"}.validate())
+	require.Error(t, listenQuery{}.validate())
+	require.Error(t, listenQuery{ListenChannel: strings.Repeat("a", maxChannelNameLength+1)}.validate())
+}
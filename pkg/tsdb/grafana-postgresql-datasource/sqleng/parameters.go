@@ -0,0 +1,255 @@
+package sqleng
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Parameter types that can be declared for a query parameter.
+const (
+	ParameterTypeString  = "string"
+	ParameterTypeInteger = "integer"
+	ParameterTypeNumber  = "number"
+	ParameterTypeBoolean = "boolean"
+	ParameterTypeTime    = "time"
+)
+
+// paramRegExp matches the references to query parameters in a parameterized query.
+var paramRegExp = regexp.MustCompile(`\$__param\(\s*([_a-zA-Z0-9]+)\s*\)`)
+
+// QueryParameter is a value bound to a parameterized query as a driver parameter instead of
+// being interpolated into the SQL text. It is referenced in the query with $__param(name).
+// A value holding an array binds every element, which is what multi-value template variables
+// resolve to.
+type QueryParameter struct {
+	Name  string          `json:"name"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// boundParameter is a parameter value along with the type it is sent to the server as.
+type boundParameter struct {
+	oid   uint32
+	value any
+}
+
+// bindParameters replaces the $__param(name) references in sql with numbered $N placeholders and
+// returns the parameters to execute the query with, in placeholder order. Array values are bound
+// as a single array parameter, so they are meant to be used with = ANY(...).
+func bindParameters(sql string, params []QueryParameter) (string, []boundParameter, error) {
+	values := make(map[string]boundParameter, len(params))
+	for _, p := range params {
+		if p.Name == "" {
+			return "", nil, fmt.Errorf("query parameter name is required")
+		}
+		value, err := decodeParameter(p)
+		if err != nil {
+			return "", nil, err
+		}
+		bound, err := newBoundParameter(p, value)
+		if err != nil {
+			return "", nil, err
+		}
+		values[p.Name] = bound
+	}
+
+	var args []boundParameter
+	var bindErr error
+	sql = paramRegExp.ReplaceAllStringFunc(sql, func(ref string) string {
+		name := paramRegExp.FindStringSubmatch(ref)[1]
+		value, ok := values[name]
+		if !ok {
+			if bindErr == nil {
+				bindErr = fmt.Errorf("query parameter %q is not declared", name)
+			}
+			return ref
+		}
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	})
+	if bindErr != nil {
+		return "", nil, bindErr
+	}
+
+	return sql, args, nil
+}
+
+// newBoundParameter picks the postgres type of a parameter from its declared type, so that null
+// values and empty arrays are still sent with a type. Arrays are converted to typed slices.
+func newBoundParameter(p QueryParameter, value any) (boundParameter, error) {
+	list, isArray := value.([]any)
+
+	var oid, arrayOID uint32
+	switch p.Type {
+	case ParameterTypeString, "":
+		oid, arrayOID = pgtype.TextOID, pgtype.TextArrayOID
+	case ParameterTypeInteger:
+		oid, arrayOID = pgtype.Int8OID, pgtype.Int8ArrayOID
+	case ParameterTypeNumber:
+		oid, arrayOID = pgtype.Float8OID, pgtype.Float8ArrayOID
+	case ParameterTypeBoolean:
+		oid, arrayOID = pgtype.BoolOID, pgtype.BoolArrayOID
+	case ParameterTypeTime:
+		oid, arrayOID = pgtype.TimestamptzOID, pgtype.TimestamptzArrayOID
+	default:
+		return boundParameter{}, fmt.Errorf("query parameter %q: unsupported parameter type %q", p.Name, p.Type)
+	}
+
+	if !isArray {
+		return boundParameter{oid: oid, value: value}, nil
+	}
+	for _, v := range list {
+		if v == nil {
+			return boundParameter{}, fmt.Errorf("query parameter %q contains a null value", p.Name)
+		}
+	}
+
+	var typed any
+	switch arrayOID {
+	case pgtype.TextArrayOID:
+		typed = typedSlice[string](list)
+	case pgtype.Int8ArrayOID:
+		typed = typedSlice[int64](list)
+	case pgtype.Float8ArrayOID:
+		typed = typedSlice[float64](list)
+	case pgtype.BoolArrayOID:
+		typed = typedSlice[bool](list)
+	case pgtype.TimestamptzArrayOID:
+		typed = typedSlice[time.Time](list)
+	}
+	return boundParameter{oid: arrayOID, value: typed}, nil
+}
+
+func typedSlice[T any](list []any) []T {
+	typed := make([]T, 0, len(list))
+	for _, v := range list {
+		typed = append(typed, v.(T))
+	}
+	return typed
+}
+
+// encodeParameters encodes the parameters in the text format, along with their type OIDs.
+func encodeParameters(m *pgtype.Map, params []boundParameter) ([][]byte, []uint32, error) {
+	values := make([][]byte, len(params))
+	oids := make([]uint32, len(params))
+	for i, p := range params {
+		oids[i] = p.oid
+		if p.value == nil {
+			continue
+		}
+		buf, err := m.Encode(p.oid, pgtype.TextFormatCode, p.value, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode parameter $%d: %w", i+1, err)
+		}
+		values[i] = buf
+	}
+	return values, oids, nil
+}
+
+// decodeParameter converts the JSON value of a parameter to its declared type. Template
+// variables resolve to strings, so strings are parsed for non-string types.
+func decodeParameter(p QueryParameter) (any, error) {
+	value := bytes.TrimSpace(p.Value)
+	if len(value) == 0 || bytes.Equal(value, []byte("null")) {
+		return nil, nil
+	}
+
+	if value[0] == '[' {
+		var raw []any
+		if err := json.Unmarshal(value, &raw); err != nil {
+			return nil, fmt.Errorf("query parameter %q: %w", p.Name, err)
+		}
+		list := make([]any, 0, len(raw))
+		for _, v := range raw {
+			converted, err := convertParameterValue(p.Type, v)
+			if err != nil {
+				return nil, fmt.Errorf("query parameter %q: %w", p.Name, err)
+			}
+			list = append(list, converted)
+		}
+		return list, nil
+	}
+
+	var raw any
+	if err := json.Unmarshal(value, &raw); err != nil {
+		return nil, fmt.Errorf("query parameter %q: %w", p.Name, err)
+	}
+	converted, err := convertParameterValue(p.Type, raw)
+	if err != nil {
+		return nil, fmt.Errorf("query parameter %q: %w", p.Name, err)
+	}
+	return converted, nil
+}
+
+func convertParameterValue(typ string, v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch typ {
+	case ParameterTypeString, "":
+		switch v := v.(type) {
+		case string:
+			return v, nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		}
+	case ParameterTypeInteger:
+		switch v := v.(type) {
+		case float64:
+			if v != float64(int64(v)) {
+				return nil, fmt.Errorf("%v is not an integer", v)
+			}
+			return int64(v), nil
+		case string:
+			return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		}
+	case ParameterTypeNumber:
+		switch v := v.(type) {
+		case float64:
+			return v, nil
+		case string:
+			return strconv.ParseFloat(strings.TrimSpace(v), 64)
+		}
+	case ParameterTypeBoolean:
+		switch v := v.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(strings.TrimSpace(v))
+		}
+	case ParameterTypeTime:
+		switch v := v.(type) {
+		case float64:
+			return time.UnixMilli(int64(v)).UTC(), nil
+		case string:
+			return parseParameterTime(strings.TrimSpace(v))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported parameter type %q", typ)
+	}
+
+	return nil, fmt.Errorf("cannot convert %v to %s", v, typ)
+}
+
+// parseParameterTime parses RFC 3339 timestamps and unix timestamps in milliseconds, which is
+// what the time range variables resolve to.
+func parseParameterTime(s string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot parse %q as a time", s)
+	}
+	return t.UTC(), nil
+}
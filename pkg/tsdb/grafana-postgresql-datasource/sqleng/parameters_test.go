@@ -0,0 +1,87 @@
+package sqleng
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindParameters(t *testing.T) {
+	param := func(name, typ string, value any) QueryParameter {
+		raw, err := json.Marshal(value)
+		require.NoError(t, err)
+		return QueryParameter{Name: name, Type: typ, Value: raw}
+	}
+
+	t.Run("binds typed values in placeholder order", func(t *testing.T) {
+		sql, params, err := bindParameters(
+			"SELECT * FROM t WHERE a = $__param(a) AND b > $__param( b ) AND a <> $__param(a)",
+			[]QueryParameter{
+				param("a", ParameterTypeString, "it's"),
+				param("b", ParameterTypeInteger, "42"),
+			})
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM t WHERE a = $1 AND b > $2 AND a <> $3", sql)
+		require.Equal(t, []boundParameter{
+			{oid: pgtype.TextOID, value: "it's"},
+			{oid: pgtype.Int8OID, value: int64(42)},
+			{oid: pgtype.TextOID, value: "it's"},
+		}, params)
+	})
+
+	t.Run("binds multi-value parameters as arrays", func(t *testing.T) {
+		sql, params, err := bindParameters("SELECT * FROM t WHERE host = ANY($__param(hosts)) AND v = ANY($__param(v))", []QueryParameter{
+			param("hosts", ParameterTypeString, []string{"a", "b"}),
+			param("v", ParameterTypeNumber, []string{"1", "2.5"}),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM t WHERE host = ANY($1) AND v = ANY($2)", sql)
+		require.Equal(t, []boundParameter{
+			{oid: pgtype.TextArrayOID, value: []string{"a", "b"}},
+			{oid: pgtype.Float8ArrayOID, value: []float64{1, 2.5}},
+		}, params)
+	})
+
+	t.Run("binds typed null values", func(t *testing.T) {
+		_, params, err := bindParameters("$__param(a)", []QueryParameter{{Name: "a", Type: ParameterTypeTime}})
+		require.NoError(t, err)
+		require.Equal(t, []boundParameter{{oid: pgtype.TimestamptzOID}}, params)
+	})
+
+	t.Run("fails on undeclared parameters", func(t *testing.T) {
+		_, _, err := bindParameters("SELECT $__param(missing)", nil)
+		require.ErrorContains(t, err, `"missing" is not declared`)
+	})
+
+	t.Run("fails on null values in arrays", func(t *testing.T) {
+		_, _, err := bindParameters("$__param(a)", []QueryParameter{{Name: "a", Value: json.RawMessage(`["a", null]`)}})
+		require.ErrorContains(t, err, "contains a null value")
+	})
+
+	t.Run("fails on values not matching the declared type", func(t *testing.T) {
+		_, _, err := bindParameters("$__param(a)", []QueryParameter{param("a", ParameterTypeBoolean, "yes please")})
+		require.Error(t, err)
+
+		_, _, err = bindParameters("$__param(a)", []QueryParameter{param("a", "uuid", "x")})
+		require.ErrorContains(t, err, "unsupported parameter type")
+	})
+}
+
+func TestEncodeParameters(t *testing.T) {
+	ts := time.Date(2018, 3, 14, 21, 20, 6, 0, time.UTC)
+	values, oids, err := encodeParameters(pgtype.NewMap(), []boundParameter{
+		{oid: pgtype.TextOID, value: "it's"},
+		{oid: pgtype.Int8ArrayOID, value: []int64{1, 2}},
+		{oid: pgtype.TimestamptzOID, value: ts},
+		{oid: pgtype.BoolOID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uint32{pgtype.TextOID, pgtype.Int8ArrayOID, pgtype.TimestamptzOID, pgtype.BoolOID}, oids)
+	require.Equal(t, "it's", string(values[0]))
+	require.Equal(t, "{1,2}", string(values[1]))
+	require.Contains(t, string(values[2]), "2018-03-14 21:20:06")
+	require.Nil(t, values[3])
+}
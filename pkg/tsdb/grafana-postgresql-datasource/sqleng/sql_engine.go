@@ -21,6 +21,8 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/grafana/grafana/pkg/tsdb/sqlcalendar"
 )
 
 // MetaKeyExecutedQueryString is the key where the executed query should get stored
@@ -99,6 +101,14 @@ type QueryJson struct {
 	FillMode     string  `json:"fillMode"`
 	FillValue    float64 `json:"fillValue"`
 	Format       string  `json:"format"`
+	// FillCalendarInterval and FillTimezone are set instead of FillInterval by calendar aware
+	// macros, whose buckets vary in length.
+	FillCalendarInterval string `json:"fillCalendarInterval"`
+	FillTimezone         string `json:"fillTimezone"`
+	// Parameterized queries reference Parameters with $__param(name) and have them bound as
+	// driver parameters instead of interpolated into the query.
+	Parameterized bool             `json:"parameterized"`
+	Parameters    []QueryParameter `json:"parameters"`
 }
 
 func (e *DataSourceHandler) TransformQueryError(logger log.Logger, err error) error {
@@ -174,7 +184,8 @@ func (e *DataSourceHandler) QueryData(ctx context.Context, req *backend.QueryDat
 
 		// the fill-params are only stored inside this function, during query-interpolation. we do not support
 		// sending them in "from the outside"
-		if queryjson.Fill || queryjson.FillInterval != 0.0 || queryjson.FillMode != "" || queryjson.FillValue != 0.0 {
+		if queryjson.Fill || queryjson.FillInterval != 0.0 || queryjson.FillMode != "" || queryjson.FillValue != 0.0 ||
+			queryjson.FillCalendarInterval != "" || queryjson.FillTimezone != "" {
 			return nil, backend.DownstreamErrorf("query fill-parameters not supported")
 		}
 
@@ -198,13 +209,27 @@ func (e *DataSourceHandler) QueryData(ctx context.Context, req *backend.QueryDat
 	return result, nil
 }
 
-func (e *DataSourceHandler) execQuery(ctx context.Context, query string) ([]*pgconn.Result, error) {
+func (e *DataSourceHandler) execQuery(ctx context.Context, query string, params []boundParameter) ([]*pgconn.Result, error) {
 	c, err := e.pool.Acquire(ctx)
 	if err != nil {
 		return nil, backend.DownstreamErrorf("failed to acquire connection: %w", err)
 	}
 	defer c.Release()
 
+	// The extended protocol used to send parameters supports a single statement only, so the
+	// simple protocol is kept for queries without parameters.
+	if len(params) > 0 {
+		values, oids, err := encodeParameters(c.Conn().TypeMap(), params)
+		if err != nil {
+			return nil, backend.DownstreamError(err)
+		}
+		result := c.Conn().PgConn().ExecParams(ctx, query, values, oids, nil, nil).Read()
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return []*pgconn.Result{result}, nil
+	}
+
 	mrr := c.Conn().PgConn().Exec(ctx, query)
 	// Close returns the first error that occurred during the MultiResultReader's use. We will log that later.
 	defer mrr.Close() //nolint:errcheck
@@ -229,6 +254,18 @@ func (e *DataSourceHandler) executeQuery(queryContext context.Context, query bac
 	// global substitutions
 	interpolatedQuery := Interpolate(query, query.TimeRange, e.dsInfo.JsonData.TimeInterval, queryJSON.RawSql)
 
+	// parameters are bound before the data source specific substitutions, which would reject
+	// $__param as an unknown macro
+	var params []boundParameter
+	if queryJSON.Parameterized {
+		var err error
+		interpolatedQuery, params, err = bindParameters(interpolatedQuery, queryJSON.Parameters)
+		if err != nil {
+			e.handleQueryError("binding parameters failed", backend.DownstreamError(err), queryJSON.RawSql, backend.ErrorSourceDownstream, ch, queryResult)
+			return
+		}
+	}
+
 	// data source specific substitutions
 	interpolatedQuery, err := e.macroEngine.Interpolate(&query, query.TimeRange, interpolatedQuery)
 	if err != nil {
@@ -236,7 +273,7 @@ func (e *DataSourceHandler) executeQuery(queryContext context.Context, query bac
 		return
 	}
 
-	results, err := e.execQuery(queryContext, interpolatedQuery)
+	results, err := e.execQuery(queryContext, interpolatedQuery, params)
 	if err != nil {
 		e.handleQueryError("db query error", e.TransformQueryError(logger, err), interpolatedQuery, backend.ErrorSourceDownstream, ch, queryResult)
 		return
@@ -433,6 +470,13 @@ func (e *DataSourceHandler) newProcessCfg(queryContext context.Context, query ba
 	if queryJSON.Fill {
 		qm.FillMissing = &data.FillMissing{}
 		qm.Interval = time.Duration(queryJSON.FillInterval * float64(time.Second))
+		if queryJSON.FillCalendarInterval != "" {
+			calendarInterval, err := sqlcalendar.Parse(queryJSON.FillCalendarInterval, queryJSON.FillTimezone)
+			if err != nil {
+				return nil, err
+			}
+			qm.CalendarInterval = &calendarInterval
+		}
 		switch strings.ToLower(queryJSON.FillMode) {
 		case "null":
 			qm.FillMissing.Mode = data.FillModeNull
@@ -506,6 +550,7 @@ type dataQueryModel struct {
 	TimeRange         backend.TimeRange
 	FillMissing       *data.FillMissing // property not set until after Interpolate()
 	Interval          time.Duration
+	CalendarInterval  *sqlcalendar.Interval // set instead of Interval by calendar aware macros
 	columnNames       []string
 	columnTypes       []string
 	timeIndex         int
@@ -812,6 +857,10 @@ func convertSQLValueColumnToFloat(frame *data.Frame, Index int) (*data.Frame, er
 // of fill points would exceed the row limit the fill is skipped and a warning
 // notice is appended to the frame instead.
 func (e *DataSourceHandler) applyFill(frame *data.Frame, qm *dataQueryModel) *data.Frame {
+	if qm.CalendarInterval != nil {
+		return e.applyCalendarFill(frame, qm)
+	}
+
 	startUnixTime := qm.TimeRange.From.Unix() / int64(qm.Interval.Seconds()) * int64(qm.Interval.Seconds())
 	alignedTimeRange := backend.TimeRange{
 		From: time.Unix(startUnixTime, 0),
@@ -840,6 +889,27 @@ func (e *DataSourceHandler) applyFill(frame *data.Frame, qm *dataQueryModel) *da
 	return frame
 }
 
+// applyCalendarFill is applyFill for calendar intervals, whose buckets vary in length.
+func (e *DataSourceHandler) applyCalendarFill(frame *data.Frame, qm *dataQueryModel) *data.Frame {
+	buckets, ok := qm.CalendarInterval.Buckets(qm.TimeRange, e.rowLimit)
+	if !ok {
+		e.log.Warn("Skipping fill: number of fill points exceeds row limit", "rowLimit", e.rowLimit)
+		frame.AppendNotices(data.Notice{
+			Text:     "Fill operation skipped: time range and interval would require more points than the configured row limit",
+			Severity: data.NoticeSeverityWarning,
+		})
+		return frame
+	}
+
+	resampled, err := sqlcalendar.Resample(frame, qm.FillMissing, buckets)
+	if err != nil {
+		e.log.Error("Failed to resample dataframe", "err", err)
+		frame.AppendNotices(data.Notice{Text: "Failed to resample dataframe", Severity: data.NoticeSeverityWarning})
+		return frame
+	}
+	return resampled
+}
+
 func SetupFillmode(query *backend.DataQuery, interval time.Duration, fillmode string) error {
 	rawQueryProp := make(map[string]any)
 	queryBytes, err := query.JSON.MarshalJSON()
@@ -873,6 +943,14 @@ func SetupFillmode(query *backend.DataQuery, interval time.Duration, fillmode st
 	return nil
 }
 
+// SetupCalendarFillmode is SetupFillmode for calendar intervals.
+func SetupCalendarFillmode(query *backend.DataQuery, interval sqlcalendar.Interval, fillmode string) error {
+	if err := SetupFillmode(query, 0, fillmode); err != nil {
+		return err
+	}
+	return sqlcalendar.SetupFill(query, interval)
+}
+
 type SQLMacroEngineBase struct{}
 
 func NewSQLMacroEngineBase() *SQLMacroEngineBase {
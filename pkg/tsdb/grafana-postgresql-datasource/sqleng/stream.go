@@ -0,0 +1,56 @@
+package sqleng
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/tsdb/sqlstream"
+)
+
+// StreamPathListen is the prefix of the stream paths listening to notifications on a channel.
+const StreamPathListen = "listen/"
+
+func (e *DataSourceHandler) SubscribeStream(_ context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	if !strings.HasPrefix(req.Path, StreamPathListen) {
+		return sqlstream.Subscribe(req)
+	}
+
+	if err := sqlstream.CheckAccess(req.PluginContext); err != nil {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusPermissionDenied,
+		}, err
+	}
+
+	var query listenQuery
+	err := json.Unmarshal(req.Data, &query)
+	if err == nil {
+		err = query.validate()
+	}
+	if err != nil {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusNotFound,
+		}, err
+	}
+
+	return &backend.SubscribeStreamResponse{
+		Status: backend.SubscribeStreamStatusOK,
+	}, nil
+}
+
+func (e *DataSourceHandler) PublishStream(_ context.Context, _ *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return &backend.PublishStreamResponse{
+		Status: backend.PublishStreamStatusPermissionDenied,
+	}, nil
+}
+
+// RunStream runs the query of a poll stream every poll interval, or listens to the notifications
+// of a channel.
+func (e *DataSourceHandler) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	if strings.HasPrefix(req.Path, StreamPathListen) {
+		return e.runListenStream(ctx, req, sender)
+	}
+	return sqlstream.RunPoll(ctx, e.log.FromContext(ctx), req, e.QueryData, sender)
+}
@@ -0,0 +1,90 @@
+package sqleng
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeStream(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		data string
+		// anonymous subscriptions are not made on behalf of a user
+		anonymous bool
+		status    backend.SubscribeStreamStatus
+	}{
+		{
+			name:   "poll query",
+			path:   "poll/abc",
+			data:   `{"rawSql": "SELECT 1"}`,
+			status: backend.SubscribeStreamStatusOK,
+		},
+		{
+			name:   "poll query without sql",
+			path:   "poll/abc",
+			data:   `{}`,
+			status: backend.SubscribeStreamStatusNotFound,
+		},
+		{
+			name:   "invalid query",
+			path:   "poll/abc",
+			data:   `{`,
+			status: backend.SubscribeStreamStatusNotFound,
+		},
+		{
+			name:   "listen query",
+			path:   "listen/abc",
+			data:   `{"listenChannel": "sensors"}`,
+			status: backend.SubscribeStreamStatusOK,
+		},
+		{
+			name:   "listen query without channel",
+			path:   "listen/abc",
+			data:   `{}`,
+			status: backend.SubscribeStreamStatusNotFound,
+		},
+		{
+			name:   "unsupported path",
+			path:   "other/abc",
+			data:   `{"rawSql": "SELECT 1"}`,
+			status: backend.SubscribeStreamStatusNotFound,
+		},
+		{
+			name:      "poll query without data source access",
+			path:      "poll/abc",
+			data:      `{"rawSql": "SELECT 1"}`,
+			anonymous: true,
+			status:    backend.SubscribeStreamStatusPermissionDenied,
+		},
+		{
+			name:      "listen query without data source access",
+			path:      "listen/abc",
+			data:      `{"listenChannel": "sensors"}`,
+			anonymous: true,
+			status:    backend.SubscribeStreamStatusPermissionDenied,
+		},
+	}
+
+	handler := &DataSourceHandler{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &backend.SubscribeStreamRequest{Path: tt.path, Data: []byte(tt.data)}
+			req.PluginContext.DataSourceInstanceSettings = &backend.DataSourceInstanceSettings{UID: "sql"}
+			if !tt.anonymous {
+				req.PluginContext.User = &backend.User{Login: "viewer", Role: "Viewer"}
+			}
+
+			rsp, err := handler.SubscribeStream(context.Background(), req)
+			require.Equal(t, tt.status, rsp.Status)
+			if tt.status == backend.SubscribeStreamStatusOK {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
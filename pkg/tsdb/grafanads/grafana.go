@@ -1,11 +1,10 @@
 package grafanads
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"path/filepath"
+	"sync"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -57,6 +56,33 @@ type Service struct {
 	store    store.StorageService
 	log      log.Logger
 	features featuremgmt.FeatureToggles
+
+	handlersMu sync.RWMutex
+	handlers   map[string]QueryTypeHandler
+}
+
+// QueryTypeHandler executes a single query of a registered query type.
+type QueryTypeHandler func(ctx context.Context, pCtx backend.PluginContext, query backend.DataQuery) backend.DataResponse
+
+// RegisterQueryType adds a query type handled outside of this package. This lets services that
+// depend on the plugin infrastructure themselves (alerting, search, ...) expose their data through
+// the built-in datasource without creating a dependency cycle.
+func (s *Service) RegisterQueryType(queryType string, handler QueryTypeHandler) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+
+	if s.handlers == nil {
+		s.handlers = make(map[string]QueryTypeHandler)
+	}
+	s.handlers[queryType] = handler
+}
+
+func (s *Service) queryTypeHandler(queryType string) (QueryTypeHandler, bool) {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+
+	handler, ok := s.handlers[queryType]
+	return handler, ok
 }
 
 func DataSourceModel(orgId int64) *datasources.DataSource {
@@ -83,6 +109,10 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		case queryTypeRead:
 			response.Responses[q.RefID] = s.doReadQuery(ctx, q)
 		default:
+			if handler, ok := s.queryTypeHandler(q.QueryType); ok {
+				response.Responses[q.RefID] = handler(ctx, req.PluginContext, q)
+				continue
+			}
 			response.Responses[q.RefID] = backend.DataResponse{
 				Error: fmt.Errorf("unknown query type"),
 			}
@@ -118,36 +148,6 @@ func (s *Service) doListQuery(ctx context.Context, query backend.DataQuery) back
 	return response
 }
 
-func (s *Service) doReadQuery(ctx context.Context, query backend.DataQuery) backend.DataResponse {
-	q := &readQueryModel{}
-	response := backend.DataResponse{}
-	err := json.Unmarshal(query.JSON, &q)
-	if err != nil {
-		response.Error = err
-		return response
-	}
-
-	if filepath.Ext(q.Path) != ".csv" {
-		response.Error = fmt.Errorf("unsupported file type")
-		return response
-	}
-
-	path := store.RootPublicStatic + "/" + q.Path
-	file, err := s.store.Read(ctx, nil, path)
-	if err != nil {
-		response.Error = err
-		return response
-	}
-
-	frame, err := testdatasource.LoadCsvContent(bytes.NewReader(file.Contents), filepath.Base(path))
-	if err != nil {
-		response.Error = err
-		return response
-	}
-	response.Frames = data.Frames{frame}
-	return response
-}
-
 func (s *Service) doRandomWalk(query backend.DataQuery) backend.DataResponse {
 	response := backend.DataResponse{}
 
@@ -0,0 +1,79 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// alertingQuerier is implemented by ngalert.AlertNG.
+type alertingQuerier interface {
+	QueryStateHistory(ctx context.Context, query ngmodels.HistoryQuery) (*data.Frame, error)
+	QueryAlertInstances(ctx context.Context, user identity.Requester, ruleUIDs []string, states ...eval.State) (*data.Frame, error)
+}
+
+func (q *queries) alertStateHistoryQuery(ctx context.Context, user identity.Requester, query backend.DataQuery, model queryModel) backend.DataResponse {
+	for _, s := range []string{model.Previous, model.Current} {
+		if s == "" {
+			continue
+		}
+		if _, err := eval.ParseStateString(s); err != nil {
+			return backend.DataResponse{Error: fmt.Errorf("invalid state filter: %w", err)}
+		}
+	}
+
+	var matchers labels.Matchers
+	if model.Matchers != "" {
+		parsed, err := labels.ParseMatchers(model.Matchers)
+		if err != nil {
+			return backend.DataResponse{Error: fmt.Errorf("invalid matchers: %w", err)}
+		}
+		matchers = parsed
+	}
+
+	frame, err := q.alerting.QueryStateHistory(ctx, ngmodels.HistoryQuery{
+		RuleUID:      model.RuleUID,
+		OrgID:        user.GetOrgID(),
+		DashboardUID: model.DashboardUID,
+		Labels:       matchers,
+		Previous:     model.Previous,
+		Current:      model.Current,
+		From:         query.TimeRange.From,
+		To:           query.TimeRange.To,
+		Limit:        int(model.Limit),
+		SignedInUser: user,
+	})
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+func (q *queries) alertInstancesQuery(ctx context.Context, user identity.Requester, _ backend.DataQuery, model queryModel) backend.DataResponse {
+	var ruleUIDs []string
+	if model.RuleUID != "" {
+		ruleUIDs = []string{model.RuleUID}
+	}
+
+	var states []eval.State
+	if model.State != "" {
+		state, err := eval.ParseStateString(model.State)
+		if err != nil {
+			return backend.DataResponse{Error: fmt.Errorf("invalid state filter: %w", err)}
+		}
+		states = append(states, state)
+	}
+
+	frame, err := q.alerting.QueryAlertInstances(ctx, user, ruleUIDs, states...)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
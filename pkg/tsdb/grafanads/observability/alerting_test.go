@@ -0,0 +1,148 @@
+package observability
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestAlertStateHistoryQuery(t *testing.T) {
+	timeRange := backend.TimeRange{From: time.UnixMilli(1000), To: time.UnixMilli(5000)}
+	signedInUser := &user.SignedInUser{OrgID: 2}
+
+	tests := []struct {
+		name     string
+		model    queryModel
+		expected *ngmodels.HistoryQuery
+		err      string
+	}{
+		{
+			name:  "no filters",
+			model: queryModel{},
+			expected: &ngmodels.HistoryQuery{
+				OrgID: 2, From: timeRange.From, To: timeRange.To, SignedInUser: signedInUser,
+			},
+		},
+		{
+			name: "filters",
+			model: queryModel{
+				RuleUID: "rule", DashboardUID: "dash", Previous: "Normal", Current: "Alerting", Matchers: `{team="a"}`, Limit: 10,
+			},
+			expected: &ngmodels.HistoryQuery{
+				RuleUID: "rule", OrgID: 2, DashboardUID: "dash", Previous: "Normal", Current: "Alerting",
+				Labels: labels.Matchers{{Type: labels.MatchEqual, Name: "team", Value: "a"}},
+				From:   timeRange.From, To: timeRange.To, Limit: 10, SignedInUser: signedInUser,
+			},
+		},
+		{
+			name:  "invalid previous state",
+			model: queryModel{Previous: "Broken"},
+			err:   "invalid state filter",
+		},
+		{
+			name:  "invalid current state",
+			model: queryModel{Current: "Broken"},
+			err:   "invalid state filter",
+		},
+		{
+			name:  "invalid matchers",
+			model: queryModel{Matchers: `{team=~"(}`},
+			err:   "invalid matchers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerting := &fakeAlertingQuerier{}
+			q := &queries{alerting: alerting}
+
+			rsp := q.alertStateHistoryQuery(context.Background(), signedInUser, backend.DataQuery{TimeRange: timeRange}, tt.model)
+			if tt.err != "" {
+				require.ErrorContains(t, rsp.Error, tt.err)
+				require.Nil(t, alerting.historyQuery)
+				return
+			}
+			require.NoError(t, rsp.Error)
+			require.Len(t, rsp.Frames, 1)
+
+			expected := *tt.expected
+			actual := *alerting.historyQuery
+			require.Equal(t, len(expected.Labels), len(actual.Labels))
+			for i := range expected.Labels {
+				require.Equal(t, expected.Labels[i].String(), actual.Labels[i].String())
+			}
+			expected.Labels, actual.Labels = nil, nil
+			require.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestAlertInstancesQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		model    queryModel
+		ruleUIDs []string
+		states   []eval.State
+		err      string
+	}{
+		{
+			name:  "no filters",
+			model: queryModel{},
+		},
+		{
+			name:     "rule and state",
+			model:    queryModel{RuleUID: "rule", State: "Alerting"},
+			ruleUIDs: []string{"rule"},
+			states:   []eval.State{eval.Alerting},
+		},
+		{
+			name:  "invalid state",
+			model: queryModel{State: "Broken"},
+			err:   "invalid state filter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerting := &fakeAlertingQuerier{}
+			q := &queries{alerting: alerting}
+
+			rsp := q.alertInstancesQuery(context.Background(), &user.SignedInUser{OrgID: 2}, backend.DataQuery{}, tt.model)
+			if tt.err != "" {
+				require.ErrorContains(t, rsp.Error, tt.err)
+				return
+			}
+			require.NoError(t, rsp.Error)
+			require.Len(t, rsp.Frames, 1)
+			require.Equal(t, tt.ruleUIDs, alerting.ruleUIDs)
+			require.Equal(t, tt.states, alerting.states)
+		})
+	}
+}
+
+type fakeAlertingQuerier struct {
+	historyQuery *ngmodels.HistoryQuery
+	ruleUIDs     []string
+	states       []eval.State
+}
+
+func (f *fakeAlertingQuerier) QueryStateHistory(_ context.Context, query ngmodels.HistoryQuery) (*data.Frame, error) {
+	f.historyQuery = &query
+	return data.NewFrame("states"), nil
+}
+
+func (f *fakeAlertingQuerier) QueryAlertInstances(_ context.Context, _ identity.Requester, ruleUIDs []string, states ...eval.State) (*data.Frame, error) {
+	f.ruleUIDs = ruleUIDs
+	f.states = states
+	return data.NewFrame("alert_instances"), nil
+}
@@ -0,0 +1,134 @@
+package observability
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/annotations"
+)
+
+const (
+	formatTimeSeries = "timeseries"
+
+	defaultAnnotationsLimit = 1000
+	// maxAnnotationsLimit bounds the number of annotations read to count them over time
+	maxAnnotationsLimit = 100000
+	// maxCountBuckets bounds the number of points returned when counting annotations over time
+	maxCountBuckets = 10000
+)
+
+func (q *queries) annotationsQuery(ctx context.Context, user identity.Requester, query backend.DataQuery, model queryModel) backend.DataResponse {
+	limit := model.Limit
+	if limit <= 0 {
+		limit = defaultAnnotationsLimit
+		if model.Format == formatTimeSeries {
+			limit = maxAnnotationsLimit
+		}
+	}
+	if limit > maxAnnotationsLimit {
+		limit = maxAnnotationsLimit
+	}
+
+	items, err := q.annotations.Find(ctx, &annotations.ItemQuery{
+		OrgID:        user.GetOrgID(),
+		From:         query.TimeRange.From.UnixMilli(),
+		To:           query.TimeRange.To.UnixMilli(),
+		DashboardUID: model.DashboardUID,
+		Tags:         model.Tags,
+		MatchAny:     model.MatchAny,
+		Type:         model.AnnotationType,
+		SignedInUser: user,
+		Limit:        limit,
+	})
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	if model.Format == formatTimeSeries {
+		return backend.DataResponse{Frames: data.Frames{annotationCounts(query, items)}}
+	}
+	return backend.DataResponse{Frames: data.Frames{annotationsTable(items)}}
+}
+
+func annotationsTable(items []*annotations.ItemDTO) *data.Frame {
+	timeField := make([]time.Time, 0, len(items))
+	timeEndField := make([]time.Time, 0, len(items))
+	textField := make([]string, 0, len(items))
+	tagsField := make([]string, 0, len(items))
+	dashboardField := make([]string, 0, len(items))
+	panelField := make([]int64, 0, len(items))
+	alertField := make([]string, 0, len(items))
+	newStateField := make([]string, 0, len(items))
+	prevStateField := make([]string, 0, len(items))
+	loginField := make([]string, 0, len(items))
+
+	for _, item := range items {
+		timeField = append(timeField, time.UnixMilli(item.Time))
+		timeEndField = append(timeEndField, time.UnixMilli(item.TimeEnd))
+		textField = append(textField, item.Text)
+		tagsField = append(tagsField, strings.Join(item.Tags, ","))
+		dashboardUID := ""
+		if item.DashboardUID != nil {
+			dashboardUID = *item.DashboardUID
+		}
+		dashboardField = append(dashboardField, dashboardUID)
+		panelField = append(panelField, item.PanelID)
+		alertField = append(alertField, item.AlertName)
+		newStateField = append(newStateField, item.NewState)
+		prevStateField = append(prevStateField, item.PrevState)
+		loginField = append(loginField, item.Login)
+	}
+
+	return data.NewFrame("annotations",
+		data.NewField("time", nil, timeField),
+		data.NewField("timeEnd", nil, timeEndField),
+		data.NewField("text", nil, textField),
+		data.NewField("tags", nil, tagsField),
+		data.NewField("dashboardUID", nil, dashboardField),
+		data.NewField("panelId", nil, panelField),
+		data.NewField("alertName", nil, alertField),
+		data.NewField("newState", nil, newStateField),
+		data.NewField("prevState", nil, prevStateField),
+		data.NewField("login", nil, loginField),
+	)
+}
+
+// annotationCounts returns the number of annotations starting in each interval of the query time range.
+func annotationCounts(query backend.DataQuery, items []*annotations.ItemDTO) *data.Frame {
+	from, to := query.TimeRange.From, query.TimeRange.To
+	interval := query.Interval
+	if interval <= 0 && query.MaxDataPoints > 0 {
+		interval = to.Sub(from) / time.Duration(query.MaxDataPoints)
+	}
+	if minInterval := to.Sub(from) / maxCountBuckets; interval < minInterval {
+		interval = minInterval
+	}
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	start := from.Truncate(interval)
+	buckets := int(to.Sub(start)/interval) + 1
+	times := make([]time.Time, buckets)
+	counts := make([]int64, buckets)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i) * interval)
+	}
+
+	for _, item := range items {
+		i := int(time.UnixMilli(item.Time).Sub(start) / interval)
+		if i >= 0 && i < buckets {
+			counts[i]++
+		}
+	}
+
+	return data.NewFrame("annotations",
+		data.NewField(data.TimeSeriesTimeFieldName, nil, times),
+		data.NewField("count", nil, counts),
+	)
+}
@@ -0,0 +1,176 @@
+package observability
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestAnnotationsQuery(t *testing.T) {
+	timeRange := backend.TimeRange{From: time.UnixMilli(1000), To: time.UnixMilli(5000)}
+	signedInUser := &user.SignedInUser{OrgID: 2}
+
+	tests := []struct {
+		name     string
+		model    queryModel
+		expected annotations.ItemQuery
+		frame    string
+	}{
+		{
+			name:  "table with default limit",
+			model: queryModel{},
+			expected: annotations.ItemQuery{
+				OrgID: 2, From: 1000, To: 5000, SignedInUser: signedInUser, Limit: defaultAnnotationsLimit,
+			},
+			frame: "time",
+		},
+		{
+			name:  "time series reads all annotations",
+			model: queryModel{Format: formatTimeSeries},
+			expected: annotations.ItemQuery{
+				OrgID: 2, From: 1000, To: 5000, SignedInUser: signedInUser, Limit: maxAnnotationsLimit,
+			},
+			frame: data.TimeSeriesTimeFieldName,
+		},
+		{
+			name:  "limit is bounded",
+			model: queryModel{Limit: maxAnnotationsLimit + 1},
+			expected: annotations.ItemQuery{
+				OrgID: 2, From: 1000, To: 5000, SignedInUser: signedInUser, Limit: maxAnnotationsLimit,
+			},
+			frame: "time",
+		},
+		{
+			name: "filters",
+			model: queryModel{
+				Limit: 10, Tags: []string{"deploy"}, MatchAny: true, DashboardUID: "dash", AnnotationType: "alert",
+			},
+			expected: annotations.ItemQuery{
+				OrgID: 2, From: 1000, To: 5000, SignedInUser: signedInUser, Limit: 10,
+				Tags: []string{"deploy"}, MatchAny: true, DashboardUID: "dash", Type: "alert",
+			},
+			frame: "time",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAnnotationsRepo{}
+			q := &queries{annotations: repo}
+
+			rsp := q.annotationsQuery(context.Background(), signedInUser, backend.DataQuery{TimeRange: timeRange, Interval: time.Second}, tt.model)
+			require.NoError(t, rsp.Error)
+			require.Len(t, rsp.Frames, 1)
+			require.Equal(t, tt.frame, rsp.Frames[0].Fields[0].Name)
+			require.Equal(t, tt.expected, *repo.query)
+		})
+	}
+}
+
+func TestAnnotationsTable(t *testing.T) {
+	dashboardUID := "dash"
+	frame := annotationsTable([]*annotations.ItemDTO{
+		{Time: 1000, TimeEnd: 2000, Text: "deploy", Tags: []string{"a", "b"}, DashboardUID: &dashboardUID, PanelID: 3, Login: "admin"},
+		{Time: 3000, TimeEnd: 3000, AlertName: "cpu", NewState: "Alerting", PrevState: "Normal"},
+	})
+
+	rows, err := frame.RowLen()
+	require.NoError(t, err)
+	require.Equal(t, 2, rows)
+	require.Equal(t, []any{
+		time.UnixMilli(1000), time.UnixMilli(2000), "deploy", "a,b", "dash", int64(3), "", "", "", "admin",
+	}, frame.RowCopy(0))
+	require.Equal(t, []any{
+		time.UnixMilli(3000), time.UnixMilli(3000), "", "", "", int64(0), "cpu", "Alerting", "Normal", "",
+	}, frame.RowCopy(1))
+}
+
+func TestAnnotationCounts(t *testing.T) {
+	items := []*annotations.ItemDTO{{Time: 101000}, {Time: 104000}, {Time: 106000}, {Time: 120000}}
+
+	tests := []struct {
+		name   string
+		query  backend.DataQuery
+		times  []int64
+		counts []int64
+	}{
+		{
+			name: "query interval",
+			query: backend.DataQuery{
+				TimeRange: backend.TimeRange{From: time.Unix(100, 0), To: time.Unix(110, 0)},
+				Interval:  5 * time.Second,
+			},
+			times:  []int64{100, 105, 110},
+			counts: []int64{2, 1, 0},
+		},
+		{
+			name: "interval from max data points",
+			query: backend.DataQuery{
+				TimeRange:     backend.TimeRange{From: time.Unix(100, 0), To: time.Unix(110, 0)},
+				MaxDataPoints: 2,
+			},
+			times:  []int64{100, 105, 110},
+			counts: []int64{2, 1, 0},
+		},
+		{
+			name: "start is aligned to the interval",
+			query: backend.DataQuery{
+				TimeRange: backend.TimeRange{From: time.Unix(102, 0), To: time.Unix(108, 0)},
+				Interval:  5 * time.Second,
+			},
+			times:  []int64{100, 105},
+			counts: []int64{2, 1},
+		},
+		{
+			name: "interval of at least a second",
+			query: backend.DataQuery{
+				TimeRange: backend.TimeRange{From: time.Unix(100, 0), To: time.Unix(103, 0)},
+				Interval:  time.Millisecond,
+			},
+			times:  []int64{100, 101, 102, 103},
+			counts: []int64{0, 1, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame := annotationCounts(tt.query, items)
+			require.Equal(t, len(tt.times), frame.Fields[0].Len())
+
+			times := make([]int64, 0, len(tt.times))
+			counts := make([]int64, 0, len(tt.counts))
+			for i := 0; i < frame.Fields[0].Len(); i++ {
+				times = append(times, frame.Fields[0].At(i).(time.Time).Unix())
+				counts = append(counts, frame.Fields[1].At(i).(int64))
+			}
+			require.Equal(t, tt.times, times)
+			require.Equal(t, tt.counts, counts)
+		})
+	}
+
+	t.Run("number of buckets is bounded", func(t *testing.T) {
+		frame := annotationCounts(backend.DataQuery{
+			TimeRange: backend.TimeRange{From: time.Unix(0, 0), To: time.Unix(0, 0).Add(time.Duration(maxCountBuckets) * time.Minute)},
+			Interval:  time.Second,
+		}, nil)
+		require.LessOrEqual(t, frame.Fields[0].Len(), maxCountBuckets+1)
+	})
+}
+
+type fakeAnnotationsRepo struct {
+	annotations.Repository
+	query *annotations.ItemQuery
+	items []*annotations.ItemDTO
+}
+
+func (r *fakeAnnotationsRepo) Find(_ context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	r.query = query
+	return r.items, nil
+}
@@ -0,0 +1,124 @@
+// Package observability adds query types to the built-in "-- Grafana --" datasource that
+// return data about Grafana itself: annotations, alerting state, search results and usage stats.
+//
+// The query types live outside the grafanads package because the services they read from depend
+// on the plugin infrastructure, which in turn depends on grafanads.
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/usagestats"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/ngalert"
+	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+)
+
+const (
+	// queryTypeAnnotations returns annotations as a table, or the number of annotations
+	// over time when the format is time series
+	queryTypeAnnotations = "grafanaAnnotations"
+
+	// queryTypeAlertStateHistory returns the state transitions of alert rules
+	queryTypeAlertStateHistory = "alertStateHistory"
+
+	// queryTypeAlertInstances returns the current state of alert instances
+	queryTypeAlertInstances = "alertInstances"
+
+	// queryTypeDashboardSearch returns dashboards and folders matching a search
+	queryTypeDashboardSearch = "dashboardSearch"
+
+	// queryTypeUsageStats returns the metrics reported in the anonymous usage stats
+	queryTypeUsageStats = "usageStats"
+)
+
+var errNoUser = errors.New("no signed in user found in the request")
+
+// Registration is used to make sure the query types are registered with the built-in datasource.
+type Registration struct{}
+
+func ProvideRegistration(
+	ds *grafanads.Service,
+	annotationsRepo annotations.Repository,
+	alertNG *ngalert.AlertNG,
+	searchService *search.SearchService,
+	usageStats usagestats.Service,
+) Registration {
+	q := &queries{
+		annotations: annotationsRepo,
+		search:      searchService,
+		usageStats:  usageStats,
+	}
+
+	ds.RegisterQueryType(queryTypeAnnotations, q.handle(q.annotationsQuery))
+	ds.RegisterQueryType(queryTypeDashboardSearch, q.handle(q.searchQuery))
+	ds.RegisterQueryType(queryTypeUsageStats, q.handle(q.usageStatsQuery))
+	if alertNG != nil && !alertNG.IsDisabled() {
+		q.alerting = alertNG
+		ds.RegisterQueryType(queryTypeAlertStateHistory, q.handle(q.alertStateHistoryQuery))
+		ds.RegisterQueryType(queryTypeAlertInstances, q.handle(q.alertInstancesQuery))
+	}
+
+	return Registration{}
+}
+
+type queries struct {
+	annotations annotations.Repository
+	alerting    alertingQuerier
+	search      search.Service
+	usageStats  usagestats.Service
+}
+
+// queryModel holds the properties used by the query types of this package.
+type queryModel struct {
+	// Format is either "table" (default) or "timeseries" for annotations
+	Format string `json:"format,omitempty"`
+	// Limit is the maximum number of rows to return
+	Limit int64 `json:"limit,omitempty"`
+	// Tags filters annotations and search results
+	Tags []string `json:"tags,omitempty"`
+	// MatchAny returns annotations matching any of the tags instead of all of them
+	MatchAny bool `json:"matchAny,omitempty"`
+	// DashboardUID filters annotations and alert state history
+	DashboardUID string `json:"dashboardUID,omitempty"`
+	// AnnotationType is either "annotation" or "alert"
+	AnnotationType string `json:"annotationType,omitempty"`
+	// RuleUID filters alert state history and alert instances
+	RuleUID string `json:"ruleUID,omitempty"`
+	// Previous and Current filter alert state history transitions
+	Previous string `json:"previous,omitempty"`
+	Current  string `json:"current,omitempty"`
+	// Matchers filter alert state history by labels, e.g. {team="a",env=~"prod.*"}
+	Matchers string `json:"matchers,omitempty"`
+	// State filters alert instances by their current state
+	State string `json:"state,omitempty"`
+	// Title, FolderUIDs and SearchType filter search results
+	Title      string   `json:"title,omitempty"`
+	FolderUIDs []string `json:"folderUIDs,omitempty"`
+	SearchType string   `json:"searchType,omitempty"`
+}
+
+type queryFunc func(ctx context.Context, user identity.Requester, query backend.DataQuery, model queryModel) backend.DataResponse
+
+func (q *queries) handle(fn queryFunc) grafanads.QueryTypeHandler {
+	return func(ctx context.Context, _ backend.PluginContext, query backend.DataQuery) backend.DataResponse {
+		user, err := identity.GetRequester(ctx)
+		if err != nil || user == nil {
+			return backend.DataResponse{Error: errNoUser}
+		}
+
+		model := queryModel{}
+		if len(query.JSON) > 0 {
+			if err := json.Unmarshal(query.JSON, &model); err != nil {
+				return backend.DataResponse{Error: err}
+			}
+		}
+		return fn(ctx, user, query, model)
+	}
+}
@@ -0,0 +1,69 @@
+package observability
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestHandle(t *testing.T) {
+	signedInUser := &user.SignedInUser{OrgID: 2}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		json     string
+		expected queryModel
+		err      string
+	}{
+		{
+			name:     "query model",
+			ctx:      identity.WithRequester(context.Background(), signedInUser),
+			json:     `{"format": "timeseries", "limit": 10, "tags": ["a"]}`,
+			expected: queryModel{Format: formatTimeSeries, Limit: 10, Tags: []string{"a"}},
+		},
+		{
+			name: "empty query",
+			ctx:  identity.WithRequester(context.Background(), signedInUser),
+		},
+		{
+			name: "no user",
+			ctx:  context.Background(),
+			json: `{}`,
+			err:  errNoUser.Error(),
+		},
+		{
+			name: "invalid json",
+			ctx:  identity.WithRequester(context.Background(), signedInUser),
+			json: `{"limit": "ten"}`,
+			err:  "cannot unmarshal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			q := &queries{}
+			handler := q.handle(func(_ context.Context, user identity.Requester, _ backend.DataQuery, model queryModel) backend.DataResponse {
+				called = true
+				require.Equal(t, signedInUser, user)
+				require.Equal(t, tt.expected, model)
+				return backend.DataResponse{}
+			})
+
+			rsp := handler(tt.ctx, backend.PluginContext{}, backend.DataQuery{JSON: []byte(tt.json)})
+			if tt.err != "" {
+				require.ErrorContains(t, rsp.Error, tt.err)
+				require.False(t, called)
+				return
+			}
+			require.NoError(t, rsp.Error)
+			require.True(t, called)
+		})
+	}
+}
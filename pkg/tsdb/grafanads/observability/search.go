@@ -0,0 +1,77 @@
+package observability
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const defaultSearchLimit = 1000
+
+func (q *queries) searchQuery(ctx context.Context, requester identity.Requester, _ backend.DataQuery, qm queryModel) backend.DataResponse {
+	signedInUser, ok := requester.(*user.SignedInUser)
+	if !ok {
+		return backend.DataResponse{Error: errNoUser}
+	}
+
+	limit := qm.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	searchType := qm.SearchType
+	if searchType == "" {
+		searchType = string(model.DashHitDB)
+	}
+
+	hits, err := q.search.SearchHandler(ctx, &search.Query{
+		Title:        qm.Title,
+		Tags:         qm.Tags,
+		OrgId:        signedInUser.GetOrgID(),
+		SignedInUser: signedInUser,
+		Limit:        limit,
+		Page:         1,
+		Type:         searchType,
+		FolderUIDs:   qm.FolderUIDs,
+	})
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	uidField := make([]string, 0, len(hits))
+	titleField := make([]string, 0, len(hits))
+	typeField := make([]string, 0, len(hits))
+	urlField := make([]string, 0, len(hits))
+	tagsField := make([]string, 0, len(hits))
+	folderUIDField := make([]string, 0, len(hits))
+	folderTitleField := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		tags := append([]string(nil), hit.Tags...)
+		sort.Strings(tags)
+		uidField = append(uidField, hit.UID)
+		titleField = append(titleField, hit.Title)
+		typeField = append(typeField, string(hit.Type))
+		urlField = append(urlField, hit.URL)
+		tagsField = append(tagsField, strings.Join(tags, ","))
+		folderUIDField = append(folderUIDField, hit.FolderUID)
+		folderTitleField = append(folderTitleField, hit.FolderTitle)
+	}
+
+	frame := data.NewFrame("search",
+		data.NewField("uid", nil, uidField),
+		data.NewField("title", nil, titleField),
+		data.NewField("type", nil, typeField),
+		data.NewField("url", nil, urlField),
+		data.NewField("tags", nil, tagsField),
+		data.NewField("folderUID", nil, folderUIDField),
+		data.NewField("folderTitle", nil, folderTitleField),
+	)
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
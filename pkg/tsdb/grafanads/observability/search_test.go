@@ -0,0 +1,86 @@
+package observability
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestSearchQuery(t *testing.T) {
+	signedInUser := &user.SignedInUser{OrgID: 2}
+
+	tests := []struct {
+		name      string
+		requester identity.Requester
+		model     queryModel
+		expected  *search.Query
+		err       error
+	}{
+		{
+			name:      "defaults",
+			requester: signedInUser,
+			model:     queryModel{},
+			expected: &search.Query{
+				OrgId: 2, SignedInUser: signedInUser, Limit: defaultSearchLimit, Page: 1, Type: string(model.DashHitDB),
+			},
+		},
+		{
+			name:      "filters",
+			requester: signedInUser,
+			model: queryModel{
+				Title: "cpu", Tags: []string{"infra"}, FolderUIDs: []string{"folder"}, SearchType: string(model.DashHitFolder), Limit: 5,
+			},
+			expected: &search.Query{
+				Title: "cpu", Tags: []string{"infra"}, OrgId: 2, SignedInUser: signedInUser, Limit: 5, Page: 1,
+				Type: string(model.DashHitFolder), FolderUIDs: []string{"folder"},
+			},
+		},
+		{
+			name:      "not a signed in user",
+			requester: &identity.StaticRequester{OrgID: 2},
+			err:       errNoUser,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher := &fakeSearchService{hits: model.HitList{
+				{UID: "a", Title: "A", Type: model.DashHitDB, URL: "/d/a", Tags: []string{"z", "b"}, FolderUID: "folder", FolderTitle: "Folder"},
+			}}
+			q := &queries{search: searcher}
+
+			rsp := q.searchQuery(context.Background(), tt.requester, backend.DataQuery{}, tt.model)
+			if tt.err != nil {
+				require.ErrorIs(t, rsp.Error, tt.err)
+				require.Nil(t, searcher.query)
+				return
+			}
+			require.NoError(t, rsp.Error)
+			require.Equal(t, tt.expected, searcher.query)
+
+			require.Len(t, rsp.Frames, 1)
+			require.Equal(t, []any{"a", "A", string(model.DashHitDB), "/d/a", "b,z", "folder", "Folder"}, rsp.Frames[0].RowCopy(0))
+		})
+	}
+}
+
+type fakeSearchService struct {
+	query *search.Query
+	hits  model.HitList
+}
+
+func (f *fakeSearchService) SearchHandler(_ context.Context, query *search.Query) (model.HitList, error) {
+	f.query = query
+	return f.hits, nil
+}
+
+func (f *fakeSearchService) SortOptions() []model.SortOption {
+	return nil
+}
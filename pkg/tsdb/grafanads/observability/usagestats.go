@@ -0,0 +1,76 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+)
+
+var errUsageStatsForbidden = errors.New("usage stats can only be queried by server admins")
+
+// usageStatsQuery returns the numeric metrics of the usage report as a table of name and value.
+// The report covers the whole instance, so only server admins can query it.
+func (q *queries) usageStatsQuery(ctx context.Context, user identity.Requester, _ backend.DataQuery, _ queryModel) backend.DataResponse {
+	if !user.GetIsGrafanaAdmin() {
+		return backend.DataResponse{Error: errUsageStatsForbidden}
+	}
+
+	report, err := q.usageStats.GetUsageReport(ctx)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	names := make([]string, 0, len(report.Metrics))
+	for name := range report.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nameField := make([]string, 0, len(names))
+	valueField := make([]float64, 0, len(names))
+	for _, name := range names {
+		value, ok := toFloat(report.Metrics[name])
+		if !ok {
+			continue
+		}
+		nameField = append(nameField, name)
+		valueField = append(valueField, value)
+	}
+
+	frame := data.NewFrame("usage_stats",
+		data.NewField("metric", nil, nameField),
+		data.NewField("value", nil, valueField),
+	)
+	frame.SetMeta(&data.FrameMeta{Custom: map[string]any{
+		"version": report.Version,
+		"edition": report.Edition,
+	}})
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case bool:
+		if n {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,70 @@
+package observability
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/usagestats"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestUsageStatsQuery(t *testing.T) {
+	usageStats := &usagestats.UsageStatsMock{T: t}
+	usageStats.RegisterMetricsFunc(func(context.Context) (map[string]any, error) {
+		return map[string]any{
+			"stats.dashboards.count": 3,
+			"stats.users.count":      int64(2),
+			"stats.ratio":            0.5,
+			"stats.enabled":          true,
+			"stats.edition":          "oss",
+		}, nil
+	})
+	q := &queries{usageStats: usageStats}
+
+	t.Run("server admins get the numeric metrics", func(t *testing.T) {
+		rsp := q.usageStatsQuery(context.Background(), &user.SignedInUser{IsGrafanaAdmin: true}, backend.DataQuery{}, queryModel{})
+		require.NoError(t, rsp.Error)
+		require.Len(t, rsp.Frames, 1)
+
+		frame := rsp.Frames[0]
+		rows, err := frame.RowLen()
+		require.NoError(t, err)
+		require.Equal(t, 4, rows)
+		require.Equal(t, []any{"stats.dashboards.count", float64(3)}, frame.RowCopy(0))
+		require.Equal(t, []any{"stats.enabled", float64(1)}, frame.RowCopy(1))
+		require.Equal(t, []any{"stats.ratio", 0.5}, frame.RowCopy(2))
+		require.Equal(t, []any{"stats.users.count", float64(2)}, frame.RowCopy(3))
+	})
+
+	t.Run("other users are rejected", func(t *testing.T) {
+		rsp := q.usageStatsQuery(context.Background(), &user.SignedInUser{}, backend.DataQuery{}, queryModel{})
+		require.ErrorIs(t, rsp.Error, errUsageStatsForbidden)
+	})
+}
+
+func TestToFloat(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected float64
+		ok       bool
+	}{
+		{value: 1, expected: 1, ok: true},
+		{value: int32(2), expected: 2, ok: true},
+		{value: int64(3), expected: 3, ok: true},
+		{value: float32(0.5), expected: 0.5, ok: true},
+		{value: 1.5, expected: 1.5, ok: true},
+		{value: true, expected: 1, ok: true},
+		{value: false, expected: 0, ok: true},
+		{value: "1", ok: false},
+		{value: nil, ok: false},
+	}
+
+	for _, tt := range tests {
+		value, ok := toFloat(tt.value)
+		require.Equal(t, tt.ok, ok, "%v", tt.value)
+		require.Equal(t, tt.expected, value, "%v", tt.value)
+	}
+}
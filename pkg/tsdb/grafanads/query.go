@@ -8,8 +8,8 @@ const (
 	queryTypeList = "list"
 
 	// QueryTypeRead will read a file and return it as data frames
-	// currently only .csv files are supported,
-	// other file types will eventually be supported (parquet, etc)
+	// .csv, .parquet, .json (an array of objects), .ndjson/.jsonl and
+	// Arrow IPC (.arrow/.feather files or .arrows streams) files are supported
 	queryTypeRead = "read"
 )
 
@@ -18,4 +18,11 @@ type listQueryModel struct {
 }
 type readQueryModel struct {
 	Path string `json:"path"`
+	// Columns are the columns to return, all columns are returned when empty
+	Columns []string `json:"columns,omitempty"`
+	// Limit is the maximum number of rows to return
+	Limit int64 `json:"limit,omitempty"`
+	// TimeColumn is the column holding the time of each row. When set, only the rows
+	// within the query time range are returned
+	TimeColumn string `json:"timeColumn,omitempty"`
 }
@@ -0,0 +1,528 @@
+package grafanads
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/store"
+	"github.com/grafana/grafana/pkg/storage/unified/parquet"
+	testdatasource "github.com/grafana/grafana/pkg/tsdb/grafana-testdata-datasource"
+)
+
+// maxReadRows is the maximum number of rows returned by a read query
+const maxReadRows = 100000
+
+// readBatchSize is the number of rows decoded at once from parquet files
+const readBatchSize = 1024
+
+func (s *Service) doReadQuery(ctx context.Context, query backend.DataQuery) backend.DataResponse {
+	q := &readQueryModel{}
+	response := backend.DataResponse{}
+	err := json.Unmarshal(query.JSON, &q)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
+	path := store.RootPublicStatic + "/" + q.Path
+	name := filepath.Base(path)
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".csv", ".parquet", ".json", ".ndjson", ".jsonl", ".arrow", ".arrows", ".feather":
+	default:
+		response.Error = fmt.Errorf("unsupported file type")
+		return response
+	}
+
+	file, err := s.store.Read(ctx, nil, path)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
+	frame, err := readFrame(ctx, file.Contents, name, q, query.TimeRange)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+	response.Frames = data.Frames{frame}
+	return response
+}
+
+// readFrame decodes the contents of a file based on its extension and applies the options of the query.
+func readFrame(ctx context.Context, contents []byte, name string, q *readQueryModel, timeRange backend.TimeRange) (*data.Frame, error) {
+	limit := q.Limit
+	if limit <= 0 || limit > maxReadRows {
+		limit = maxReadRows
+	}
+	// stop decoding early when the rows are not filtered afterwards
+	decodeLimit := limit
+	if q.TimeColumn != "" {
+		decodeLimit = 0
+	}
+	columns := projectedColumns(q)
+
+	var frame *data.Frame
+	var err error
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		frame, err = testdatasource.LoadCsvContent(bytes.NewReader(contents), name)
+	case ".parquet":
+		frame, err = readParquet(ctx, contents, name, columns, decodeLimit)
+	case ".json":
+		frame, err = readJSON(contents, name, decodeLimit)
+	case ".ndjson", ".jsonl":
+		frame, err = readNDJSON(contents, name, decodeLimit)
+	case ".arrow", ".arrows", ".feather":
+		frame, err = readArrow(contents, name, decodeLimit)
+	default:
+		err = fmt.Errorf("unsupported file type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return applyReadOptions(frame, columns, q.TimeColumn, timeRange, limit)
+}
+
+// projectedColumns returns the columns to read. The time column is always read, even when it is
+// not one of the requested columns, since the rows are filtered on it.
+func projectedColumns(q *readQueryModel) []string {
+	if len(q.Columns) == 0 || q.TimeColumn == "" {
+		return q.Columns
+	}
+	for _, name := range q.Columns {
+		if name == q.TimeColumn {
+			return q.Columns
+		}
+	}
+	return append([]string{q.TimeColumn}, q.Columns...)
+}
+
+// applyReadOptions projects the columns, filters the rows to the time range and limits the number of rows.
+func applyReadOptions(frame *data.Frame, columns []string, timeColumn string, timeRange backend.TimeRange, limit int64) (*data.Frame, error) {
+	if len(columns) > 0 {
+		fields := make([]*data.Field, 0, len(columns))
+		for _, name := range columns {
+			field, idx := frame.FieldByName(name)
+			if idx < 0 {
+				return nil, fmt.Errorf("missing column: %s", name)
+			}
+			fields = append(fields, field)
+		}
+		frame = data.NewFrame(frame.Name, fields...).SetMeta(frame.Meta)
+	}
+
+	timeIdx := -1
+	if timeColumn != "" {
+		field, idx := frame.FieldByName(timeColumn)
+		if idx < 0 {
+			return nil, fmt.Errorf("missing time column: %s", timeColumn)
+		}
+		timeField, err := toTimeField(field)
+		if err != nil {
+			return nil, err
+		}
+		frame.Fields[idx] = timeField
+		timeIdx = idx
+	}
+
+	rows, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+	if timeIdx < 0 && int64(rows) <= limit {
+		return frame, nil
+	}
+
+	filtered := frame.EmptyCopy()
+	for i := 0; i < rows && int64(filtered.Rows()) < limit; i++ {
+		if timeIdx >= 0 {
+			t, ok := frame.Fields[timeIdx].ConcreteAt(i)
+			if !ok || t.(time.Time).Before(timeRange.From) || t.(time.Time).After(timeRange.To) {
+				continue
+			}
+		}
+		filtered.AppendRow(frame.RowCopy(i)...)
+	}
+	return filtered, nil
+}
+
+// toTimeField converts a field to a nullable time field. Numbers are read as epoch milliseconds
+// and strings as RFC 3339 timestamps, values that can't be converted become null.
+func toTimeField(field *data.Field) (*data.Field, error) {
+	if field.Type() == data.FieldTypeNullableTime {
+		return field, nil
+	}
+
+	values := make([]*time.Time, field.Len())
+	for i := range values {
+		v, ok := field.ConcreteAt(i)
+		if !ok {
+			continue
+		}
+		var t time.Time
+		switch v := v.(type) {
+		case time.Time:
+			t = v
+		case string:
+			parsed, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				continue
+			}
+			t = parsed
+		default:
+			ms, err := field.FloatAt(i)
+			if err != nil {
+				return nil, fmt.Errorf("column %s can't be used as time column: %w", field.Name, err)
+			}
+			t = time.UnixMilli(int64(ms))
+		}
+		values[i] = &t
+	}
+
+	timeField := data.NewField(field.Name, field.Labels, values)
+	timeField.Config = field.Config
+	return timeField, nil
+}
+
+func readParquet(ctx context.Context, contents []byte, name string, columns []string, limit int64) (*data.Frame, error) {
+	rdr, err := parquet.NewRecordReader(ctx, bytes.NewReader(contents), columns, readBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Release()
+
+	b := newRecordFrameBuilder(name, limit)
+	for !b.full() && rdr.Next() {
+		if err := b.append(rdr.RecordBatch()); err != nil {
+			return nil, err
+		}
+	}
+	if err := rdr.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return b.frame(), nil
+}
+
+// readArrow reads files in the Arrow IPC file format, or the Arrow IPC streaming format if they don't
+// start with the file format magic bytes. Feather v1 files predate the Arrow IPC format and are rejected.
+func readArrow(contents []byte, name string, limit int64) (*data.Frame, error) {
+	if bytes.HasPrefix(contents, []byte("FEA1")) {
+		return nil, fmt.Errorf("feather v1 files are not supported, convert them to feather v2")
+	}
+
+	b := newRecordFrameBuilder(name, limit)
+
+	if bytes.HasPrefix(contents, []byte("ARROW1")) {
+		rdr, err := ipc.NewFileReader(bytes.NewReader(contents))
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = rdr.Close() }()
+
+		for i := 0; i < rdr.NumRecords() && !b.full(); i++ {
+			rec, err := rdr.RecordBatch(i)
+			if err != nil {
+				return nil, err
+			}
+			if err := b.append(rec); err != nil {
+				return nil, err
+			}
+		}
+		return b.frame(), nil
+	}
+
+	rdr, err := ipc.NewReader(bytes.NewReader(contents))
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Release()
+
+	for !b.full() && rdr.Next() {
+		if err := b.append(rdr.RecordBatch()); err != nil {
+			return nil, err
+		}
+	}
+	if err := rdr.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return b.frame(), nil
+}
+
+// recordFrameBuilder appends the rows of arrow record batches to a data frame.
+type recordFrameBuilder struct {
+	name   string
+	limit  int64
+	rows   int64
+	fields []*data.Field
+}
+
+func newRecordFrameBuilder(name string, limit int64) *recordFrameBuilder {
+	return &recordFrameBuilder{name: name, limit: limit}
+}
+
+func (b *recordFrameBuilder) full() bool {
+	return b.limit > 0 && b.rows >= b.limit
+}
+
+func (b *recordFrameBuilder) append(rec arrow.RecordBatch) error {
+	if b.fields == nil {
+		for _, f := range rec.Schema().Fields() {
+			field := data.NewFieldFromFieldType(arrowFieldType(f.Type), 0)
+			field.Name = f.Name
+			b.fields = append(b.fields, field)
+		}
+	}
+	if int(rec.NumCols()) != len(b.fields) {
+		return fmt.Errorf("record has %d columns, expected %d", rec.NumCols(), len(b.fields))
+	}
+
+	rows := rec.NumRows()
+	if b.limit > 0 && b.rows+rows > b.limit {
+		rows = b.limit - b.rows
+	}
+	for c, col := range rec.Columns() {
+		for i := 0; i < int(rows); i++ {
+			b.fields[c].Append(arrowValue(col, i))
+		}
+	}
+	b.rows += rows
+	return nil
+}
+
+func (b *recordFrameBuilder) frame() *data.Frame {
+	return data.NewFrame(b.name, b.fields...)
+}
+
+func arrowFieldType(t arrow.DataType) data.FieldType {
+	switch t.ID() {
+	case arrow.BOOL:
+		return data.FieldTypeNullableBool
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64:
+		return data.FieldTypeNullableInt64
+	case arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+		return data.FieldTypeNullableUint64
+	case arrow.FLOAT16, arrow.FLOAT32, arrow.FLOAT64:
+		return data.FieldTypeNullableFloat64
+	case arrow.TIMESTAMP, arrow.DATE32, arrow.DATE64:
+		return data.FieldTypeNullableTime
+	default:
+		return data.FieldTypeNullableString
+	}
+}
+
+// arrowValue returns the value of row i of a column as pointer to the type of arrowFieldType.
+func arrowValue(col arrow.Array, i int) any {
+	if col.IsNull(i) {
+		return nil
+	}
+
+	switch c := col.(type) {
+	case *array.Boolean:
+		v := c.Value(i)
+		return &v
+	case *array.Int8:
+		v := int64(c.Value(i))
+		return &v
+	case *array.Int16:
+		v := int64(c.Value(i))
+		return &v
+	case *array.Int32:
+		v := int64(c.Value(i))
+		return &v
+	case *array.Int64:
+		v := c.Value(i)
+		return &v
+	case *array.Uint8:
+		v := uint64(c.Value(i))
+		return &v
+	case *array.Uint16:
+		v := uint64(c.Value(i))
+		return &v
+	case *array.Uint32:
+		v := uint64(c.Value(i))
+		return &v
+	case *array.Uint64:
+		v := c.Value(i)
+		return &v
+	case *array.Float16:
+		v := float64(c.Value(i).Float32())
+		return &v
+	case *array.Float32:
+		v := float64(c.Value(i))
+		return &v
+	case *array.Float64:
+		v := c.Value(i)
+		return &v
+	case *array.Timestamp:
+		v := c.Value(i).ToTime(c.DataType().(*arrow.TimestampType).Unit)
+		return &v
+	case *array.Date32:
+		v := c.Value(i).ToTime()
+		return &v
+	case *array.Date64:
+		v := c.Value(i).ToTime()
+		return &v
+	case *array.String:
+		v := c.Value(i)
+		return &v
+	case *array.LargeString:
+		v := c.Value(i)
+		return &v
+	default:
+		v := col.ValueStr(i)
+		return &v
+	}
+}
+
+func readJSON(contents []byte, name string, limit int64) (*data.Frame, error) {
+	var rows []map[string]json.RawMessage
+	if err := json.Unmarshal(contents, &rows); err != nil {
+		return nil, fmt.Errorf("expected an array of objects: %w", err)
+	}
+	if limit > 0 && int64(len(rows)) > limit {
+		rows = rows[:limit]
+	}
+	return jsonRowsToFrame(rows, name)
+}
+
+func readNDJSON(contents []byte, name string, limit int64) (*data.Frame, error) {
+	var rows []map[string]json.RawMessage
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() && (limit <= 0 || int64(len(rows)) < limit) {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		row := map[string]json.RawMessage{}
+		if err := json.Unmarshal(line, &row); err != nil {
+			return nil, fmt.Errorf("line %d: expected an object: %w", len(rows)+1, err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return jsonRowsToFrame(rows, name)
+}
+
+// jsonRowsToFrame creates a frame with a column for every key of the rows. Columns only holding numbers,
+// booleans or strings get that type, other columns hold the JSON encoded values.
+func jsonRowsToFrame(rows []map[string]json.RawMessage, name string) (*data.Frame, error) {
+	var columns []string
+	seen := map[string]bool{}
+	for _, row := range rows {
+		keys := make([]string, 0, len(row))
+		for key := range row {
+			if !seen[key] {
+				keys = append(keys, key)
+			}
+		}
+		// the key order of objects is lost when decoding them, new keys are added alphabetically
+		sort.Strings(keys)
+		for _, key := range keys {
+			seen[key] = true
+			columns = append(columns, key)
+		}
+	}
+
+	frame := data.NewFrame(name)
+	for _, column := range columns {
+		values := make([]any, len(rows))
+		kind := ""
+		for i, row := range rows {
+			raw, ok := row[column]
+			if !ok {
+				continue
+			}
+			var v any
+			decoder := json.NewDecoder(bytes.NewReader(raw))
+			decoder.UseNumber()
+			if err := decoder.Decode(&v); err != nil {
+				return nil, err
+			}
+			if v == nil {
+				continue
+			}
+
+			k := "json"
+			switch v.(type) {
+			case json.Number:
+				k = "number"
+			case bool:
+				k = "bool"
+			case string:
+				k = "string"
+			default:
+				v = string(raw)
+			}
+			if kind == "" {
+				kind = k
+			} else if kind != k {
+				kind = "json"
+			}
+			values[i] = v
+		}
+		frame.Fields = append(frame.Fields, jsonColumnField(column, kind, values))
+	}
+	return frame, nil
+}
+
+func jsonColumnField(name, kind string, values []any) *data.Field {
+	switch kind {
+	case "number":
+		floats := make([]*float64, len(values))
+		for i, v := range values {
+			if n, ok := v.(json.Number); ok {
+				if f, err := n.Float64(); err == nil {
+					floats[i] = &f
+				}
+			}
+		}
+		return data.NewField(name, nil, floats)
+	case "bool":
+		bools := make([]*bool, len(values))
+		for i, v := range values {
+			if b, ok := v.(bool); ok {
+				bools[i] = &b
+			}
+		}
+		return data.NewField(name, nil, bools)
+	default:
+		strs := make([]*string, len(values))
+		for i, v := range values {
+			var s string
+			switch v := v.(type) {
+			case nil:
+				continue
+			case string:
+				s = v
+			case json.Number:
+				s = v.String()
+			case bool:
+				s = strconv.FormatBool(v)
+			default:
+				s = fmt.Sprint(v)
+			}
+			strs[i] = &s
+		}
+		return data.NewField(name, nil, strs)
+	}
+}
@@ -0,0 +1,340 @@
+package grafanads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+const testCSV = `ts,value,name
+1000,1,a
+2000,2,b
+3000,3,c
+`
+
+const testJSON = `[
+	{"ts": 1000, "value": 1, "name": "a"},
+	{"ts": 2000, "value": 2, "name": "b"},
+	{"ts": 3000, "value": 3, "name": "c"}
+]`
+
+const testNDJSON = `{"ts": 1000, "value": 1, "name": "a"}
+{"ts": 2000, "value": 2, "name": "b"}
+
+{"ts": 3000, "value": 3, "name": "c"}
+`
+
+func TestReadFrame(t *testing.T) {
+	files := map[string][]byte{
+		"test.csv":     []byte(testCSV),
+		"test.json":    []byte(testJSON),
+		"test.ndjson":  []byte(testNDJSON),
+		"test.jsonl":   []byte(testNDJSON),
+		"test.parquet": testParquet(t),
+		"test.arrow":   testArrowFile(t),
+		"test.feather": testArrowFile(t),
+		"test.arrows":  testArrowStream(t),
+	}
+	timeRange := backend.TimeRange{From: time.UnixMilli(1500), To: time.UnixMilli(3500)}
+
+	tests := []struct {
+		name    string
+		query   readQueryModel
+		columns []string
+		names   []string
+		err     string
+	}{
+		{
+			name:    "all rows and columns",
+			query:   readQueryModel{},
+			columns: []string{"name", "ts", "value"},
+			names:   []string{"a", "b", "c"},
+		},
+		{
+			name:    "limit",
+			query:   readQueryModel{Limit: 2},
+			columns: []string{"name", "ts", "value"},
+			names:   []string{"a", "b"},
+		},
+		{
+			name:    "projection",
+			query:   readQueryModel{Columns: []string{"name", "value"}},
+			columns: []string{"name", "value"},
+			names:   []string{"a", "b", "c"},
+		},
+		{
+			name:    "time range",
+			query:   readQueryModel{TimeColumn: "ts"},
+			columns: []string{"name", "ts", "value"},
+			names:   []string{"b", "c"},
+		},
+		{
+			name:    "time range and limit",
+			query:   readQueryModel{TimeColumn: "ts", Limit: 1},
+			columns: []string{"name", "ts", "value"},
+			names:   []string{"b"},
+		},
+		{
+			name:    "time column is added to the projection",
+			query:   readQueryModel{Columns: []string{"name"}, TimeColumn: "ts"},
+			columns: []string{"name", "ts"},
+			names:   []string{"b", "c"},
+		},
+		{
+			name:  "missing column",
+			query: readQueryModel{Columns: []string{"other"}},
+			err:   "missing column: other",
+		},
+		{
+			name:  "missing time column",
+			query: readQueryModel{TimeColumn: "other"},
+			err:   "other",
+		},
+	}
+
+	for file, contents := range files {
+		for _, tt := range tests {
+			t.Run(file+"/"+tt.name, func(t *testing.T) {
+				q := tt.query
+				frame, err := readFrame(context.Background(), contents, file, &q, timeRange)
+				if tt.err != "" {
+					require.ErrorContains(t, err, tt.err)
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, file, frame.Name)
+				require.ElementsMatch(t, tt.columns, fieldNames(frame))
+				require.Equal(t, tt.names, stringValues(t, frame, "name"))
+
+				if tt.query.TimeColumn != "" {
+					field, _ := frame.FieldByName(tt.query.TimeColumn)
+					require.Equal(t, data.FieldTypeNullableTime, field.Type())
+				}
+			})
+		}
+	}
+}
+
+func TestReadFrameErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents []byte
+		err      string
+	}{
+		{
+			name:     "unsupported file type",
+			file:     "test.txt",
+			contents: []byte("hello"),
+			err:      "unsupported file type",
+		},
+		{
+			name:     "feather v1",
+			file:     "test.feather",
+			contents: []byte("FEA1\x00\x00\x00\x00FEA1"),
+			err:      "feather v1 files are not supported",
+		},
+		{
+			name:     "json object",
+			file:     "test.json",
+			contents: []byte(`{"ts": 1000}`),
+			err:      "expected an array of objects",
+		},
+		{
+			name:     "ndjson array",
+			file:     "test.ndjson",
+			contents: []byte("{\"ts\": 1000}\n[1, 2]\n"),
+			err:      "line 2: expected an object",
+		},
+		{
+			name:     "invalid parquet",
+			file:     "test.parquet",
+			contents: []byte(testCSV),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readFrame(context.Background(), tt.contents, tt.file, &readQueryModel{}, backend.TimeRange{})
+			require.Error(t, err)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestJSONRowsToFrame(t *testing.T) {
+	frame, err := readJSON([]byte(`[
+		{"n": 1, "b": true, "s": "x", "mixed": 1, "obj": {"a": 1}},
+		{"n": 2.5, "s": null, "mixed": "y", "extra": "z"}
+	]`), "test.json", 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "mixed", "n", "obj", "s", "extra"}, fieldNames(frame))
+
+	types := map[string]data.FieldType{}
+	for _, f := range frame.Fields {
+		types[f.Name] = f.Type()
+	}
+	require.Equal(t, map[string]data.FieldType{
+		"b":     data.FieldTypeNullableBool,
+		"mixed": data.FieldTypeNullableString,
+		"n":     data.FieldTypeNullableFloat64,
+		"obj":   data.FieldTypeNullableString,
+		"s":     data.FieldTypeNullableString,
+		"extra": data.FieldTypeNullableString,
+	}, types)
+	require.Equal(t, []string{"1", "y"}, stringValues(t, frame, "mixed"))
+	require.Equal(t, []string{`{"a": 1}`, ""}, stringValues(t, frame, "obj"))
+}
+
+func TestToTimeField(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		field    *data.Field
+		expected []*time.Time
+		err      bool
+	}{
+		{
+			name:     "epoch milliseconds",
+			field:    data.NewField("t", nil, []*float64{ptr(float64(ts.UnixMilli())), nil}),
+			expected: []*time.Time{ptr(ts), nil},
+		},
+		{
+			name:     "rfc3339 strings",
+			field:    data.NewField("t", nil, []*string{ptr(ts.Format(time.RFC3339)), ptr("yesterday")}),
+			expected: []*time.Time{ptr(ts), nil},
+		},
+		{
+			name:     "times",
+			field:    data.NewField("t", nil, []time.Time{ts}),
+			expected: []*time.Time{ptr(ts)},
+		},
+		{
+			name:  "json",
+			field: data.NewField("t", nil, []json.RawMessage{json.RawMessage(`{}`)}),
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, err := toTimeField(tt.field)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, data.FieldTypeNullableTime, field.Type())
+			require.Equal(t, len(tt.expected), field.Len())
+			for i, expected := range tt.expected {
+				actual := field.At(i).(*time.Time)
+				if expected == nil {
+					require.Nil(t, actual)
+					continue
+				}
+				require.True(t, expected.Equal(*actual), "expected %s, got %s", expected, actual)
+			}
+		})
+	}
+}
+
+func fieldNames(frame *data.Frame) []string {
+	names := make([]string, 0, len(frame.Fields))
+	for _, f := range frame.Fields {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func stringValues(t *testing.T, frame *data.Frame, name string) []string {
+	t.Helper()
+
+	field, idx := frame.FieldByName(name)
+	require.GreaterOrEqual(t, idx, 0, "missing field %s", name)
+	values := make([]string, field.Len())
+	for i := range values {
+		if v, ok := field.ConcreteAt(i); ok {
+			values[i] = fmt.Sprint(v)
+		}
+	}
+	return values
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func testRecord(t *testing.T) arrow.RecordBatch {
+	t.Helper()
+
+	mem := memory.DefaultAllocator
+	ts := array.NewInt64Builder(mem)
+	defer ts.Release()
+	ts.AppendValues([]int64{1000, 2000, 3000}, nil)
+	value := array.NewFloat64Builder(mem)
+	defer value.Release()
+	value.AppendValues([]float64{1, 2, 3}, nil)
+	name := array.NewStringBuilder(mem)
+	defer name.Release()
+	name.AppendValues([]string{"a", "b", "c"}, nil)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "ts", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "value", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+	}, nil)
+	rec := array.NewRecordBatch(schema, []arrow.Array{ts.NewArray(), value.NewArray(), name.NewArray()}, 3)
+	t.Cleanup(rec.Release)
+	return rec
+}
+
+func testParquet(t *testing.T) []byte {
+	t.Helper()
+
+	rec := testRecord(t)
+	var buf bytes.Buffer
+	w, err := pqarrow.NewFileWriter(rec.Schema(), &buf, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps())
+	require.NoError(t, err)
+	require.NoError(t, w.Write(rec))
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func testArrowFile(t *testing.T) []byte {
+	t.Helper()
+
+	rec := testRecord(t)
+	var buf bytes.Buffer
+	w, err := ipc.NewFileWriter(&buf, ipc.WithSchema(rec.Schema()))
+	require.NoError(t, err)
+	require.NoError(t, w.Write(rec))
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func testArrowStream(t *testing.T) []byte {
+	t.Helper()
+
+	rec := testRecord(t)
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(rec.Schema()))
+	require.NoError(t, w.Write(rec))
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,77 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// parseEventsQuery returns the model of an annotation query showing Graphite events, which
+// is an annotation query without a target.
+func parseEventsQuery(query backend.DataQuery) (GraphiteQuery, bool) {
+	model := GraphiteQuery{}
+	if err := json.Unmarshal(query.JSON, &model); err != nil {
+		return model, false
+	}
+	isAnnotation := model.FromAnnotations != nil && *model.FromAnnotations
+	return model, isAnnotation && model.Target == "" && model.TargetFull == ""
+}
+
+// runEventsQuery returns the Graphite events in the time range of the query that have all
+// the tags of the query.
+func (s *Service) runEventsQuery(ctx context.Context, dsInfo *datasourceInfo, query backend.DataQuery, model GraphiteQuery) backend.DataResponse {
+	from, until := epochMStoGraphiteTime(query.TimeRange)
+	queryParams := map[string][]string{
+		"from":  {from},
+		"until": {until},
+	}
+
+	tags := make([]string, 0, len(model.Tags))
+	for _, tag := range model.Tags {
+		tags = append(tags, strings.Fields(tag)...)
+	}
+	if len(tags) > 0 {
+		queryParams["tags"] = []string{strings.Join(tags, " ")}
+	}
+
+	req, err := s.createRequest(ctx, dsInfo, URLParams{
+		SubPath:     "events/get_data",
+		QueryParams: queryParams,
+	})
+	if err != nil {
+		return backend.ErrorResponseWithErrorSource(err)
+	}
+
+	events, _, _, err := doGraphiteRequest[[]GraphiteEventsResponse](ctx, dsInfo, s.logger, req, false)
+	if err != nil {
+		return backend.ErrorResponseWithErrorSource(backend.DownstreamError(err))
+	}
+
+	return backend.DataResponse{
+		Frames: data.Frames{eventsToFrame(*events, query.RefID)},
+	}
+}
+
+// eventsToFrame returns an annotations frame with a row for each event. Tags are separated
+// by commas, the way Grafana splits the tags of annotations.
+func eventsToFrame(events []GraphiteEventsResponse, refId string) *data.Frame {
+	frame := data.NewFrame(refId,
+		data.NewField("time", nil, []time.Time{}),
+		data.NewField("title", nil, []string{}),
+		data.NewField("text", nil, []string{}),
+		data.NewField("tags", nil, []string{}),
+	)
+	frame.RefID = refId
+
+	for _, event := range events {
+		seconds, fraction := math.Modf(event.When)
+		when := time.Unix(int64(seconds), int64(fraction*float64(time.Second))).UTC()
+		frame.AppendRow(when, event.What, event.Data, strings.Join(event.Tags, ","))
+	}
+	return frame
+}
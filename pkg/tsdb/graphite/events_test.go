@@ -0,0 +1,132 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEventsQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		isEvents bool
+	}{
+		{name: "annotation query without target", json: `{"fromAnnotations": true, "tags": ["deploy"]}`, isEvents: true},
+		{name: "annotation query with target", json: `{"fromAnnotations": true, "target": "app.deploys"}`, isEvents: false},
+		{name: "annotation query with full target", json: `{"fromAnnotations": true, "targetFull": "app.deploys"}`, isEvents: false},
+		{name: "metrics query", json: `{"target": "app.deploys"}`, isEvents: false},
+		{name: "invalid query", json: `{"fromAnnotations": "yes"}`, isEvents: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, isEvents := parseEventsQuery(backend.DataQuery{RefID: "A", JSON: []byte(tt.json)})
+			assert.Equal(t, tt.isEvents, isEvents)
+		})
+	}
+}
+
+func TestGraphiteEventTagsUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected GraphiteEventTags
+	}{
+		{name: "array", json: `["deploy", "web"]`, expected: GraphiteEventTags{"deploy", "web"}},
+		{name: "space separated string", json: `"deploy web"`, expected: GraphiteEventTags{"deploy", "web"}},
+		{name: "comma separated string", json: `"deploy,web"`, expected: GraphiteEventTags{"deploy", "web"}},
+		{name: "empty string", json: `""`, expected: GraphiteEventTags{}},
+		{name: "null", json: `null`, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tags GraphiteEventTags
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &tags))
+			assert.Equal(t, tt.expected, tags)
+		})
+	}
+
+	t.Run("invalid tags", func(t *testing.T) {
+		var tags GraphiteEventTags
+		require.Error(t, json.Unmarshal([]byte(`{"tag": "deploy"}`), &tags))
+	})
+}
+
+func TestEventsToFrame(t *testing.T) {
+	events := []GraphiteEventsResponse{
+		{When: 1609459200, What: "Deploy", Data: "v1.2.3", Tags: GraphiteEventTags{"deploy", "web"}},
+		{When: 1609459260.5, What: "Restart", Data: "", Tags: nil},
+	}
+
+	frame := eventsToFrame(events, "A")
+
+	expected := data.NewFrame("A",
+		data.NewField("time", nil, []time.Time{time.Unix(1609459200, 0).UTC(), time.Unix(1609459260, int64(500*time.Millisecond)).UTC()}),
+		data.NewField("title", nil, []string{"Deploy", "Restart"}),
+		data.NewField("text", nil, []string{"v1.2.3", ""}),
+		data.NewField("tags", nil, []string{"deploy,web", ""}),
+	)
+	expected.RefID = "A"
+	assert.Equal(t, expected, frame)
+}
+
+func TestRunEventsQuery(t *testing.T) {
+	service := &Service{
+		logger: backend.Logger,
+	}
+	query := backend.DataQuery{
+		RefID: "A",
+		TimeRange: backend.TimeRange{
+			From: time.Unix(1609459200, 0),
+			To:   time.Unix(1609462800, 0),
+		},
+		JSON: []byte(`{"fromAnnotations": true, "tags": ["deploy", "web prod"]}`),
+	}
+
+	t.Run("queries events in the time range with the tags of the query", func(t *testing.T) {
+		roundTripper := &mockRoundTripper{
+			respBody: []byte(`[{"when": 1609459300, "what": "Deploy", "data": "v1.2.3", "tags": "deploy web prod"}]`),
+			status:   200,
+		}
+		dsInfo := &datasourceInfo{Id: 1, URL: "http://graphite.grafana", HTTPClient: &http.Client{Transport: roundTripper}}
+
+		result, err := service.RunQuery(context.Background(), &backend.QueryDataRequest{Queries: []backend.DataQuery{query}}, dsInfo)
+		require.NoError(t, err)
+
+		require.NotNil(t, roundTripper.lastRequest)
+		assert.Equal(t, "/events/get_data", roundTripper.lastRequest.URL.Path)
+		assert.Equal(t, "1609459200", roundTripper.lastRequest.URL.Query().Get("from"))
+		assert.Equal(t, "1609462800", roundTripper.lastRequest.URL.Query().Get("until"))
+		assert.Equal(t, "deploy web prod", roundTripper.lastRequest.URL.Query().Get("tags"))
+
+		resp := result.Responses["A"]
+		require.NoError(t, resp.Error)
+		require.Len(t, resp.Frames, 1)
+		frame := resp.Frames[0]
+		assert.Equal(t, 1, frame.Rows())
+		assert.Equal(t, time.Unix(1609459300, 0).UTC(), frame.Fields[0].At(0))
+		assert.Equal(t, "Deploy", frame.Fields[1].At(0))
+		assert.Equal(t, "deploy,web,prod", frame.Fields[3].At(0))
+	})
+
+	t.Run("returns a downstream error when Graphite fails", func(t *testing.T) {
+		roundTripper := &mockRoundTripper{respBody: []byte(`internal error`), status: 500}
+		dsInfo := &datasourceInfo{Id: 1, URL: "http://graphite.grafana", HTTPClient: &http.Client{Transport: roundTripper}}
+
+		result, err := service.RunQuery(context.Background(), &backend.QueryDataRequest{Queries: []backend.DataQuery{query}}, dsInfo)
+		require.NoError(t, err)
+
+		resp := result.Responses["A"]
+		require.Error(t, resp.Error)
+		assert.Equal(t, backend.ErrorSourceDownstream, resp.ErrorSource)
+	})
+}
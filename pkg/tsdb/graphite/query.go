@@ -34,6 +34,11 @@ func (s *Service) RunQuery(ctx context.Context, req *backend.QueryDataRequest, d
 	result := backend.NewQueryDataResponse()
 
 	for _, query := range req.Queries {
+		if model, ok := parseEventsQuery(query); ok {
+			result.Responses[query.RefID] = s.runEventsQuery(ctx, dsInfo, query, model)
+			continue
+		}
+
 		graphiteReq, formData, emptyQuery, target, err := s.createGraphiteRequest(ctx, query, dsInfo)
 		if err != nil {
 			result.Responses[query.RefID] = backend.ErrorResponseWithErrorSource(err)
@@ -214,10 +219,19 @@ func (s *Service) toDataFrames(response *http.Response, refId string) (frames da
 			values = append(values, value)
 		}
 
+		// Series returned by seriesByTag are named after their tags, the tags are only
+		// part of the response for Graphite 1.1 and above
 		tags := make(map[string]string)
+		metricName, seriesTags, isTagged := parseTaggedSeries(series.Target)
+		for name, value := range seriesTags {
+			tags[name] = value
+		}
 		for name, value := range series.Tags {
 			if name == "name" {
 				value = series.Target
+				if isTagged {
+					value = metricName
+				}
 			}
 			switch value := value.(type) {
 			case string:
@@ -305,6 +319,26 @@ func parseGraphiteError(status int, body string) (errorMsg string) {
 	return errorMsg
 }
 
+// parseTaggedSeries parses the name of a tagged series, like "cpu.usage;host=web01;dc=eu",
+// returning its metric name and tags. Names of series returned by functions, like
+// "sumSeries(cpu.usage;host=web01)", aren't tagged series.
+func parseTaggedSeries(target string) (string, map[string]string, bool) {
+	parts := strings.Split(target, ";")
+	if len(parts) < 2 || parts[0] == "" || strings.ContainsAny(parts[0], "() ,") {
+		return "", nil, false
+	}
+
+	tags := map[string]string{"name": parts[0]}
+	for _, part := range parts[1:] {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || name == "" || strings.ContainsAny(name, "() ,") {
+			return "", nil, false
+		}
+		tags[name] = value
+	}
+	return parts[0], tags, true
+}
+
 func fixIntervalFormat(target string) string {
 	rMinute := regexp.MustCompile(`'(\d+)m'`)
 	target = rMinute.ReplaceAllStringFunc(target, func(m string) string {
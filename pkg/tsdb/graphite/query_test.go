@@ -294,6 +294,55 @@ func TestConvertResponses(t *testing.T) {
 		}
 	})
 
+	t.Run("Converts tagged series to labels", func(*testing.T) {
+		body := `
+		[
+			{
+				"target": "cpu.usage;host=web01;dc=eu",
+				"tags": { "name": "cpu.usage", "host": "web01", "dc": "eu" },
+				"datapoints": [[50, 1], [null, 2], [100, 3]]
+			},
+			{
+				"target": "cpu.usage;host=web02;dc=us",
+				"datapoints": [[60, 1], [null, 2], [110, 3]]
+			}
+		]`
+		a := 50.0
+		b := 100.0
+		c := 60.0
+		d := 110.0
+		refId := "A"
+		expectedFrameA := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0).UTC(), time.Unix(2, 0).UTC(), time.Unix(3, 0).UTC()}),
+			data.NewField("value", data.Labels{
+				"name": "cpu.usage",
+				"host": "web01",
+				"dc":   "eu",
+			}, []*float64{&a, nil, &b}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "cpu.usage;host=web01;dc=eu"}),
+		).SetMeta(&data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti})
+		expectedFrameA.RefID = refId
+		expectedFrameB := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0).UTC(), time.Unix(2, 0).UTC(), time.Unix(3, 0).UTC()}),
+			data.NewField("value", data.Labels{
+				"name": "cpu.usage",
+				"host": "web02",
+				"dc":   "us",
+			}, []*float64{&c, nil, &d}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "cpu.usage;host=web02;dc=us"}),
+		).SetMeta(&data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti})
+		expectedFrameB.RefID = refId
+		expectedFrames := data.Frames{expectedFrameA, expectedFrameB}
+
+		httpResponse := &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body))}
+		dataFrames, err := service.toDataFrames(httpResponse, refId)
+
+		require.NoError(t, err)
+		if !reflect.DeepEqual(expectedFrames, dataFrames) {
+			expectedFramesJSON, _ := json.Marshal(expectedFrames)
+			dataFramesJSON, _ := json.Marshal(dataFrames)
+			t.Errorf("Data frames should have been equal but was, expected:\n%s\nactual:\n%s", expectedFramesJSON, dataFramesJSON)
+		}
+	})
+
 	t.Run("Uses target as series name for alerts", func(*testing.T) {
 		body := `
 		[
@@ -331,6 +380,41 @@ func TestConvertResponses(t *testing.T) {
 	})
 }
 
+func TestParseTaggedSeries(t *testing.T) {
+	tests := []struct {
+		target       string
+		expectedName string
+		expectedTags map[string]string
+		isTagged     bool
+	}{
+		{
+			target:       "cpu.usage;host=web01;dc=eu",
+			expectedName: "cpu.usage",
+			expectedTags: map[string]string{"name": "cpu.usage", "host": "web01", "dc": "eu"},
+			isTagged:     true,
+		},
+		{
+			target:       "disk.used;mount=/var/lib=data",
+			expectedName: "disk.used",
+			expectedTags: map[string]string{"name": "disk.used", "mount": "/var/lib=data"},
+			isTagged:     true,
+		},
+		{target: "cpu.usage", isTagged: false},
+		{target: "sumSeries(cpu.usage;host=web01)", isTagged: false},
+		{target: "cpu.usage;host", isTagged: false},
+		{target: ";host=web01", isTagged: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			name, tags, isTagged := parseTaggedSeries(tt.target)
+			assert.Equal(t, tt.isTagged, isTagged)
+			assert.Equal(t, tt.expectedName, name)
+			assert.Equal(t, tt.expectedTags, tags)
+		})
+	}
+}
+
 func TestFixIntervalFormat(t *testing.T) {
 	testCases := []struct {
 		name     string
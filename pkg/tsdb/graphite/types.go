@@ -1,6 +1,10 @@
 package graphite
 
-import "io"
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
 
 type TargetResponseDTO struct {
 	Target     string               `json:"target"`
@@ -33,6 +37,8 @@ type GraphiteQuery struct {
 	TargetFull      string `json:"targetFull,omitempty"`
 	FromAnnotations *bool  `json:"fromAnnotations,omitempty"`
 	IsMetricTank    bool   `json:"isMetricTank,omitempty"`
+	// Tags of the Graphite events to show as annotations
+	Tags []string `json:"tags,omitempty"`
 }
 
 type GraphiteEventsRequest struct {
@@ -42,10 +48,36 @@ type GraphiteEventsRequest struct {
 }
 
 type GraphiteEventsResponse struct {
-	When int64    `json:"when"`
-	What string   `json:"what"`
-	Tags []string `json:"tags"`
-	Data string   `json:"data"`
+	When float64           `json:"when"`
+	What string            `json:"what"`
+	Tags GraphiteEventTags `json:"tags"`
+	Data string            `json:"data"`
+}
+
+// GraphiteEventTags are the tags of a Graphite event. Graphite before 1.0 returns them as a
+// single string separated by spaces or commas.
+type GraphiteEventTags []string
+
+func (t *GraphiteEventTags) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = nil
+		return nil
+	}
+
+	var tags string
+	if err := json.Unmarshal(data, &tags); err == nil {
+		*t = strings.FieldsFunc(tags, func(r rune) bool {
+			return r == ' ' || r == ','
+		})
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
 }
 
 type GraphiteMetricsFindRequest struct {
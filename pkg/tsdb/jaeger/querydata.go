@@ -102,10 +102,11 @@ func queryData(ctx context.Context, dsInfo *datasourceInfo, req *backend.QueryDa
 }
 
 func transformDependenciesResponse(dependencies types.DependenciesResponse, refID string) []*data.Frame {
-	// Create nodes frame
+	// Create nodes frame. Jaeger doesn't count errors, so nodes only show the calls they received.
 	nodesFrame := data.NewFrame(refID+"_nodes",
 		data.NewField("id", nil, []string{}),
 		data.NewField("title", nil, []string{}),
+		data.NewField("mainstat", nil, []int64{}).SetConfig(&data.FieldConfig{DisplayName: "Requests"}),
 	)
 	nodesFrame.Meta = &data.FrameMeta{
 		PreferredVisualization: "nodeGraph",
@@ -132,14 +133,16 @@ func transformDependenciesResponse(dependencies types.DependenciesResponse, refI
 		return []*data.Frame{nodesFrame, edgesFrame}
 	}
 
-	// Create a map to store unique service nodes
-	servicesByName := make(map[string]bool)
+	// Create a map to store unique service nodes along with the number of calls they received
+	requestsByService := make(map[string]int64)
 
 	// Process each dependency
 	for _, dependency := range dependencies.Data {
 		// Add services to the map to track unique services
-		servicesByName[dependency.Parent] = true
-		servicesByName[dependency.Child] = true
+		if _, ok := requestsByService[dependency.Parent]; !ok {
+			requestsByService[dependency.Parent] = 0
+		}
+		requestsByService[dependency.Child] += int64(dependency.CallCount)
 
 		// Add edge data
 		edgesFrame.AppendRow(
@@ -151,8 +154,8 @@ func transformDependenciesResponse(dependencies types.DependenciesResponse, refI
 	}
 
 	// Convert map keys to slice and sort them - this is to ensure the returned nodes are in a consistent order
-	services := make([]string, 0, len(servicesByName))
-	for service := range servicesByName {
+	services := make([]string, 0, len(requestsByService))
+	for service := range requestsByService {
 		services = append(services, service)
 	}
 	sort.Strings(services)
@@ -162,6 +165,7 @@ func transformDependenciesResponse(dependencies types.DependenciesResponse, refI
 		nodesFrame.AppendRow(
 			service,
 			service,
+			requestsByService[service],
 		)
 	}
 
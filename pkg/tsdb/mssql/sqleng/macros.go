@@ -8,6 +8,8 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/tsdb/sqlcalendar"
 )
 
 const rsIdentifier = `([_a-zA-Z0-9]+)`
@@ -187,6 +189,27 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 			return tg + " AS [time]", nil
 		}
 		return "", err
+	case "__timeGroupCalendar":
+		if len(args) < 3 {
+			return "", fmt.Errorf("macro %v needs time column, interval and time zone", name)
+		}
+		interval, err := sqlcalendar.Parse(strings.Trim(args[1], `'"`), strings.Trim(args[2], `'"`))
+		if err != nil {
+			return "", err
+		}
+		if len(args) == 4 {
+			err := SetupCalendarFillmode(query, interval, args[3])
+			if err != nil {
+				return "", err
+			}
+		}
+		return timeGroupCalendar(args[0], interval, timeRange), nil
+	case "__timeGroupCalendarAlias":
+		tg, err := m.evaluateMacro(timeRange, query, "__timeGroupCalendar", args)
+		if err == nil {
+			return tg + " AS [time]", nil
+		}
+		return "", err
 	case "__unixEpochFilter":
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
@@ -226,3 +249,26 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		return "", fmt.Errorf("unknown macro %q", name)
 	}
 }
+
+// timeGroupCalendar returns the epoch seconds of the start of the calendar interval column is in.
+// AT TIME ZONE only knows Windows time zone names, so the column is shifted to local time by the
+// UTC offsets of the time range instead, truncated, and shifted back. Day 0 is Monday 1900-01-01.
+func timeGroupCalendar(column string, interval sqlcalendar.Interval, timeRange backend.TimeRange) string {
+	epoch := fmt.Sprintf("DATEDIFF(second, '1970-01-01', %s)", column)
+	local := fmt.Sprintf("DATEADD(second, %s + %s, '1970-01-01')", epoch, interval.UTCOffset(epoch, timeRange, false))
+
+	var start string
+	switch interval.Unit {
+	case sqlcalendar.Week:
+		start = fmt.Sprintf("DATEADD(day, DATEDIFF(day, 0, %s)/7*7, 0)", local)
+	case sqlcalendar.Month:
+		start = fmt.Sprintf("DATEADD(month, DATEDIFF(month, 0, %s)/%d*%d, 0)", local, interval.Count, interval.Count)
+	case sqlcalendar.Year:
+		start = fmt.Sprintf("DATEADD(year, DATEDIFF(year, 0, %s), 0)", local)
+	default:
+		start = fmt.Sprintf("DATEADD(day, DATEDIFF(day, 0, %s), 0)", local)
+	}
+
+	localEpoch := fmt.Sprintf("DATEDIFF(second, '1970-01-01', %s)", start)
+	return fmt.Sprintf("(%s - %s)", localEpoch, interval.UTCOffset(localEpoch, timeRange, true))
+}
@@ -46,6 +46,49 @@ func TestMacroEngine(t *testing.T) {
 			require.Equal(t, "select min(DATEDIFF(second, '1970-01-01', time_column) AS time)", sql)
 		})
 
+		t.Run("interpolate __timeGroupCalendar function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '1d', 'UTC')")
+			require.Nil(t, err)
+			sql2, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendarAlias(time_column, '1d', 'UTC')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY (DATEDIFF(second, '1970-01-01', DATEADD(day, DATEDIFF(day, 0, DATEADD(second, DATEDIFF(second, '1970-01-01', time_column) + 0, '1970-01-01')), 0)) - 0)", sql)
+			require.Equal(t, sql+" AS [time]", sql2)
+		})
+
+		t.Run("interpolate __timeGroupCalendar function with weeks", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '1w', 'Asia/Tokyo')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY (DATEDIFF(second, '1970-01-01', DATEADD(day, DATEDIFF(day, 0, DATEADD(second, DATEDIFF(second, '1970-01-01', time_column) + 32400, '1970-01-01'))/7*7, 0)) - 32400)", sql)
+		})
+
+		t.Run("interpolate __timeGroupCalendar function across a daylight saving time change", func(t *testing.T) {
+			timeRange := backend.TimeRange{
+				From: time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			}
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '3M', 'Europe/Berlin')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY (DATEDIFF(second, '1970-01-01', DATEADD(month, DATEDIFF(month, 0, DATEADD(second, DATEDIFF(second, '1970-01-01', time_column) + (CASE WHEN DATEDIFF(second, '1970-01-01', time_column) >= 1711846800 THEN 7200 ELSE 3600 END), '1970-01-01'))/3*3, 0)) - (CASE WHEN DATEDIFF(second, '1970-01-01', DATEADD(month, DATEDIFF(month, 0, DATEADD(second, DATEDIFF(second, '1970-01-01', time_column) + (CASE WHEN DATEDIFF(second, '1970-01-01', time_column) >= 1711846800 THEN 7200 ELSE 3600 END), '1970-01-01'))/3*3, 0)) >= 1711854000 THEN 7200 ELSE 3600 END))", sql)
+		})
+
+		t.Run("interpolate __timeGroupCalendar function with fill", func(t *testing.T) {
+			query := &backend.DataQuery{JSON: []byte(`{}`)}
+			_, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '1M', 'Europe/Berlin', previous)")
+			require.Nil(t, err)
+
+			require.JSONEq(t, `{"fill": true, "fillInterval": 0, "fillMode": "previous", "fillCalendarInterval": "1M", "fillTimezone": "Europe/Berlin"}`, string(query.JSON))
+		})
+
+		t.Run("interpolate __timeGroupCalendar function with invalid arguments", func(t *testing.T) {
+			_, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '1M')")
+			require.Error(t, err)
+			_, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '1h', 'UTC')")
+			require.Error(t, err)
+		})
+
 		t.Run("interpolate __timeFilter function", func(t *testing.T) {
 			sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column)")
 			require.Nil(t, err)
@@ -0,0 +1,187 @@
+package sqleng
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parameter types that can be declared for a query parameter.
+const (
+	ParameterTypeString  = "string"
+	ParameterTypeInteger = "integer"
+	ParameterTypeNumber  = "number"
+	ParameterTypeBoolean = "boolean"
+	ParameterTypeTime    = "time"
+)
+
+// paramRegExp matches the references to query parameters in a parameterized query.
+var paramRegExp = regexp.MustCompile(`\$__param\(\s*([_a-zA-Z0-9]+)\s*\)`)
+
+// QueryParameter is a value bound to a parameterized query as a driver parameter instead of
+// being interpolated into the SQL text. It is referenced in the query with $__param(name).
+// A value holding an array binds every element, which is what multi-value template variables
+// resolve to.
+type QueryParameter struct {
+	Name  string          `json:"name"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// bindParameters replaces the $__param(name) references in sql with numbered @pN placeholders and
+// returns the arguments to execute the query with, in placeholder order. Array values are expanded
+// to a comma separated list of placeholders, so they are meant to be used in an IN (...) list.
+func bindParameters(sql string, params []QueryParameter) (string, []any, error) {
+	values := make(map[string]any, len(params))
+	for _, p := range params {
+		if p.Name == "" {
+			return "", nil, fmt.Errorf("query parameter name is required")
+		}
+		value, err := decodeParameter(p)
+		if err != nil {
+			return "", nil, err
+		}
+		values[p.Name] = value
+	}
+
+	var args []any
+	var bindErr error
+	sql = paramRegExp.ReplaceAllStringFunc(sql, func(ref string) string {
+		name := paramRegExp.FindStringSubmatch(ref)[1]
+		value, ok := values[name]
+		if !ok {
+			if bindErr == nil {
+				bindErr = fmt.Errorf("query parameter %q is not declared", name)
+			}
+			return ref
+		}
+
+		list, ok := value.([]any)
+		if !ok {
+			args = append(args, value)
+			return fmt.Sprintf("@p%d", len(args))
+		}
+		if len(list) == 0 {
+			if bindErr == nil {
+				bindErr = fmt.Errorf("query parameter %q has no values", name)
+			}
+			return ref
+		}
+		placeholders := make([]string, 0, len(list))
+		for _, v := range list {
+			args = append(args, v)
+			placeholders = append(placeholders, fmt.Sprintf("@p%d", len(args)))
+		}
+		return strings.Join(placeholders, ", ")
+	})
+	if bindErr != nil {
+		return "", nil, bindErr
+	}
+
+	return sql, args, nil
+}
+
+// decodeParameter converts the JSON value of a parameter to its declared type. Template
+// variables resolve to strings, so strings are parsed for non-string types.
+func decodeParameter(p QueryParameter) (any, error) {
+	value := bytes.TrimSpace(p.Value)
+	if len(value) == 0 || bytes.Equal(value, []byte("null")) {
+		return nil, nil
+	}
+
+	if value[0] == '[' {
+		var raw []any
+		if err := json.Unmarshal(value, &raw); err != nil {
+			return nil, fmt.Errorf("query parameter %q: %w", p.Name, err)
+		}
+		list := make([]any, 0, len(raw))
+		for _, v := range raw {
+			converted, err := convertParameterValue(p.Type, v)
+			if err != nil {
+				return nil, fmt.Errorf("query parameter %q: %w", p.Name, err)
+			}
+			list = append(list, converted)
+		}
+		return list, nil
+	}
+
+	var raw any
+	if err := json.Unmarshal(value, &raw); err != nil {
+		return nil, fmt.Errorf("query parameter %q: %w", p.Name, err)
+	}
+	converted, err := convertParameterValue(p.Type, raw)
+	if err != nil {
+		return nil, fmt.Errorf("query parameter %q: %w", p.Name, err)
+	}
+	return converted, nil
+}
+
+func convertParameterValue(typ string, v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch typ {
+	case ParameterTypeString, "":
+		switch v := v.(type) {
+		case string:
+			return v, nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		}
+	case ParameterTypeInteger:
+		switch v := v.(type) {
+		case float64:
+			if v != float64(int64(v)) {
+				return nil, fmt.Errorf("%v is not an integer", v)
+			}
+			return int64(v), nil
+		case string:
+			return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		}
+	case ParameterTypeNumber:
+		switch v := v.(type) {
+		case float64:
+			return v, nil
+		case string:
+			return strconv.ParseFloat(strings.TrimSpace(v), 64)
+		}
+	case ParameterTypeBoolean:
+		switch v := v.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(strings.TrimSpace(v))
+		}
+	case ParameterTypeTime:
+		switch v := v.(type) {
+		case float64:
+			return time.UnixMilli(int64(v)).UTC(), nil
+		case string:
+			return parseParameterTime(strings.TrimSpace(v))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported parameter type %q", typ)
+	}
+
+	return nil, fmt.Errorf("cannot convert %v to %s", v, typ)
+}
+
+// parseParameterTime parses RFC 3339 timestamps and unix timestamps in milliseconds, which is
+// what the time range variables resolve to.
+func parseParameterTime(s string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot parse %q as a time", s)
+	}
+	return t.UTC(), nil
+}
@@ -0,0 +1,77 @@
+package sqleng
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindParameters(t *testing.T) {
+	param := func(name, typ string, value any) QueryParameter {
+		raw, err := json.Marshal(value)
+		require.NoError(t, err)
+		return QueryParameter{Name: name, Type: typ, Value: raw}
+	}
+
+	t.Run("binds typed values in placeholder order", func(t *testing.T) {
+		sql, args, err := bindParameters(
+			"SELECT * FROM t WHERE a = $__param(a) AND b > $__param( b ) AND c = $__param(c) AND a <> $__param(a)",
+			[]QueryParameter{
+				param("a", ParameterTypeString, "it's"),
+				param("b", ParameterTypeInteger, "42"),
+				param("c", ParameterTypeBoolean, true),
+			})
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM t WHERE a = @p1 AND b > @p2 AND c = @p3 AND a <> @p4", sql)
+		require.Equal(t, []any{"it's", int64(42), true, "it's"}, args)
+	})
+
+	t.Run("expands multi-value parameters", func(t *testing.T) {
+		sql, args, err := bindParameters("SELECT * FROM t WHERE host IN ($__param(hosts))", []QueryParameter{
+			param("hosts", ParameterTypeString, []string{"a", "b", "c"}),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM t WHERE host IN (@p1, @p2, @p3)", sql)
+		require.Equal(t, []any{"a", "b", "c"}, args)
+	})
+
+	t.Run("parses numbers and times", func(t *testing.T) {
+		_, args, err := bindParameters("$__param(n) $__param(from) $__param(to)", []QueryParameter{
+			param("n", ParameterTypeNumber, "1.5"),
+			param("from", ParameterTypeTime, "1521062406000"),
+			param("to", ParameterTypeTime, "2018-03-14T21:20:06Z"),
+		})
+		require.NoError(t, err)
+		ts := time.Date(2018, 3, 14, 21, 20, 6, 0, time.UTC)
+		require.Equal(t, []any{1.5, ts, ts}, args)
+	})
+
+	t.Run("binds null values", func(t *testing.T) {
+		_, args, err := bindParameters("$__param(a)", []QueryParameter{{Name: "a", Type: ParameterTypeString}})
+		require.NoError(t, err)
+		require.Equal(t, []any{nil}, args)
+	})
+
+	t.Run("fails on undeclared parameters", func(t *testing.T) {
+		_, _, err := bindParameters("SELECT $__param(missing)", nil)
+		require.ErrorContains(t, err, `"missing" is not declared`)
+	})
+
+	t.Run("fails on empty multi-value parameters", func(t *testing.T) {
+		_, _, err := bindParameters("IN ($__param(a))", []QueryParameter{param("a", ParameterTypeString, []string{})})
+		require.ErrorContains(t, err, "has no values")
+	})
+
+	t.Run("fails on values not matching the declared type", func(t *testing.T) {
+		_, _, err := bindParameters("$__param(a)", []QueryParameter{param("a", ParameterTypeInteger, "1; DROP TABLE t")})
+		require.Error(t, err)
+
+		_, _, err = bindParameters("$__param(a)", []QueryParameter{param("a", ParameterTypeInteger, 1.5)})
+		require.Error(t, err)
+
+		_, _, err = bindParameters("$__param(a)", []QueryParameter{param("a", "uuid", "x")})
+		require.ErrorContains(t, err, "unsupported parameter type")
+	})
+}
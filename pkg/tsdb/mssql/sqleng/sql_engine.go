@@ -27,6 +27,7 @@ import (
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
 	"github.com/grafana/grafana/pkg/tsdb/mssql/kerberos"
 	"github.com/grafana/grafana/pkg/tsdb/mssql/utils"
+	"github.com/grafana/grafana/pkg/tsdb/sqlcalendar"
 )
 
 // MetaKeyExecutedQueryString is the key where the executed query should get stored
@@ -112,6 +113,14 @@ type QueryJson struct {
 	FillMode     string  `json:"fillMode"`
 	FillValue    float64 `json:"fillValue"`
 	Format       string  `json:"format"`
+	// FillCalendarInterval and FillTimezone are set instead of FillInterval by calendar aware
+	// macros, whose buckets vary in length.
+	FillCalendarInterval string `json:"fillCalendarInterval"`
+	FillTimezone         string `json:"fillTimezone"`
+	// Parameterized queries reference Parameters with $__param(name) and have them bound as
+	// driver parameters instead of interpolated into the query.
+	Parameterized bool             `json:"parameterized"`
+	Parameters    []QueryParameter `json:"parameters"`
 }
 
 func (e *DataSourceHandler) TransformQueryError(logger log.Logger, err error) error {
@@ -271,7 +280,8 @@ func (e *DataSourceHandler) QueryData(ctx context.Context, req *backend.QueryDat
 
 		// the fill-params are only stored inside this function, during query-interpolation. we do not support
 		// sending them in "from the outside"
-		if queryjson.Fill || queryjson.FillInterval != 0.0 || queryjson.FillMode != "" || queryjson.FillValue != 0.0 {
+		if queryjson.Fill || queryjson.FillInterval != 0.0 || queryjson.FillMode != "" || queryjson.FillValue != 0.0 ||
+			queryjson.FillCalendarInterval != "" || queryjson.FillTimezone != "" {
 			return nil, fmt.Errorf("query fill-parameters not supported")
 		}
 
@@ -345,6 +355,18 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 	// global substitutions
 	interpolatedQuery := Interpolate(query, timeRange, e.dsInfo.JsonData.TimeInterval, queryJson.RawSql)
 
+	// parameters are bound before the data source specific substitutions, which would reject
+	// $__param as an unknown macro
+	var args []any
+	if queryJson.Parameterized {
+		var err error
+		interpolatedQuery, args, err = bindParameters(interpolatedQuery, queryJson.Parameters)
+		if err != nil {
+			errAppendDebug("binding parameters failed", backend.DownstreamError(err), queryJson.RawSql, backend.ErrorSourceDownstream)
+			return
+		}
+	}
+
 	// data source specific substitutions
 	interpolatedQuery, err := e.macroEngine.Interpolate(&query, timeRange, interpolatedQuery)
 	if err != nil {
@@ -357,7 +379,7 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 		errAppendDebug("retrieving database connection failed", e.TransformQueryError(logger, err), interpolatedQuery, backend.ErrorSourcePlugin)
 		return
 	}
-	rows, err := db.QueryContext(queryContext, interpolatedQuery)
+	rows, err := db.QueryContext(queryContext, interpolatedQuery, args...)
 	if err != nil {
 		errAppendDebug("db query error", e.TransformQueryError(logger, err), interpolatedQuery, backend.ErrorSourceDownstream)
 		return
@@ -472,6 +494,10 @@ func (e *DataSourceHandler) processResponse(qm *dataQueryModel, rows *sql.Rows,
 // of fill points would exceed the row limit the fill is skipped and a warning
 // notice is appended to the frame instead.
 func (e *DataSourceHandler) applyFill(frame *data.Frame, qm *dataQueryModel) *data.Frame {
+	if qm.CalendarInterval != nil {
+		return e.applyCalendarFill(frame, qm)
+	}
+
 	// we align the start-time
 	startUnixTime := qm.TimeRange.From.Unix() / int64(qm.Interval.Seconds()) * int64(qm.Interval.Seconds())
 	alignedTimeRange := backend.TimeRange{
@@ -543,6 +569,13 @@ func (e *DataSourceHandler) newProcessCfg(query backend.DataQuery, queryContext
 	if queryJson.Fill {
 		qm.FillMissing = &data.FillMissing{}
 		qm.Interval = time.Duration(queryJson.FillInterval * float64(time.Second))
+		if queryJson.FillCalendarInterval != "" {
+			calendarInterval, err := sqlcalendar.Parse(queryJson.FillCalendarInterval, queryJson.FillTimezone)
+			if err != nil {
+				return nil, err
+			}
+			qm.CalendarInterval = &calendarInterval
+		}
 		switch strings.ToLower(queryJson.FillMode) {
 		case "null":
 			qm.FillMissing.Mode = data.FillModeNull
@@ -615,6 +648,7 @@ type dataQueryModel struct {
 	TimeRange         backend.TimeRange
 	FillMissing       *data.FillMissing // property not set until after Interpolate()
 	Interval          time.Duration
+	CalendarInterval  *sqlcalendar.Interval // set instead of Interval by calendar aware macros
 	columnNames       []string
 	columnTypes       []*sql.ColumnType
 	timeIndex         int
@@ -704,6 +738,27 @@ func convertSQLValueColumnToFloat(frame *data.Frame, Index int) (*data.Frame, er
 	return frame, nil
 }
 
+// applyCalendarFill is applyFill for calendar intervals, whose buckets vary in length.
+func (e *DataSourceHandler) applyCalendarFill(frame *data.Frame, qm *dataQueryModel) *data.Frame {
+	buckets, ok := qm.CalendarInterval.Buckets(qm.TimeRange, e.rowLimit)
+	if !ok {
+		e.log.Warn("Skipping fill: number of fill points exceeds row limit", "rowLimit", e.rowLimit)
+		frame.AppendNotices(data.Notice{
+			Text:     "Fill operation skipped: time range and interval would require more points than the configured row limit",
+			Severity: data.NoticeSeverityWarning,
+		})
+		return frame
+	}
+
+	resampled, err := sqlcalendar.Resample(frame, qm.FillMissing, buckets)
+	if err != nil {
+		e.log.Error("Failed to resample dataframe", "err", err)
+		frame.AppendNotices(data.Notice{Text: "Failed to resample dataframe", Severity: data.NoticeSeverityWarning})
+		return frame
+	}
+	return resampled
+}
+
 func SetupFillmode(query *backend.DataQuery, interval time.Duration, fillmode string) error {
 	rawQueryProp := make(map[string]any)
 	queryBytes, err := query.JSON.MarshalJSON()
@@ -737,6 +792,14 @@ func SetupFillmode(query *backend.DataQuery, interval time.Duration, fillmode st
 	return nil
 }
 
+// SetupCalendarFillmode is SetupFillmode for calendar intervals.
+func SetupCalendarFillmode(query *backend.DataQuery, interval sqlcalendar.Interval, fillmode string) error {
+	if err := SetupFillmode(query, 0, fillmode); err != nil {
+		return err
+	}
+	return sqlcalendar.SetupFill(query, interval)
+}
+
 type SQLMacroEngineBase struct{}
 
 func NewSQLMacroEngineBase() *SQLMacroEngineBase {
@@ -0,0 +1,25 @@
+package sqleng
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/tsdb/sqlstream"
+)
+
+func (e *DataSourceHandler) SubscribeStream(_ context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	return sqlstream.Subscribe(req)
+}
+
+func (e *DataSourceHandler) PublishStream(_ context.Context, _ *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return &backend.PublishStreamResponse{
+		Status: backend.PublishStreamStatusPermissionDenied,
+	}, nil
+}
+
+// RunStream runs the query of a poll stream every poll interval and sends the rows that are newer
+// than the rows already sent.
+func (e *DataSourceHandler) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	return sqlstream.RunPoll(ctx, e.log.FromContext(ctx), req, e.QueryData, sender)
+}
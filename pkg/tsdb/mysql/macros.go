@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
 	"github.com/grafana/grafana/pkg/tsdb/mysql/sqleng"
+	"github.com/grafana/grafana/pkg/tsdb/sqlcalendar"
 )
 
 const rsIdentifier = `([_a-zA-Z0-9]+)`
@@ -187,6 +188,27 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 			return tg + " AS \"time\"", nil
 		}
 		return "", err
+	case "__timeGroupCalendar":
+		if len(args) < 3 {
+			return "", fmt.Errorf("macro %v needs time column, interval and time zone", name)
+		}
+		interval, err := sqlcalendar.Parse(strings.Trim(args[1], `'"`), strings.Trim(args[2], `'"`))
+		if err != nil {
+			return "", err
+		}
+		if len(args) == 4 {
+			err := sqleng.SetupCalendarFillmode(query, interval, args[3])
+			if err != nil {
+				return "", err
+			}
+		}
+		return timeGroupCalendar(args[0], interval, timeRange), nil
+	case "__timeGroupCalendarAlias":
+		tg, err := m.evaluateMacro(timeRange, query, "__timeGroupCalendar", args)
+		if err == nil {
+			return tg + " AS \"time\"", nil
+		}
+		return "", err
 	case "__unixEpochFilter":
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
@@ -226,3 +248,26 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		return "", fmt.Errorf("unknown macro %v", name)
 	}
 }
+
+// timeGroupCalendar returns the epoch seconds of the start of the calendar interval column is in.
+// MySQL only converts between time zones with the time zone tables loaded, so the column is
+// shifted to local time by the UTC offsets of the time range instead, truncated, and shifted back.
+func timeGroupCalendar(column string, interval sqlcalendar.Interval, timeRange backend.TimeRange) string {
+	epoch := fmt.Sprintf("UNIX_TIMESTAMP(%s)", column)
+	local := fmt.Sprintf("(TIMESTAMP('1970-01-01') + INTERVAL (%s + %s) SECOND)", epoch, interval.UTCOffset(epoch, timeRange, false))
+
+	var start string
+	switch interval.Unit {
+	case sqlcalendar.Week:
+		start = fmt.Sprintf("DATE(%s) - INTERVAL WEEKDAY(%s) DAY", local, local)
+	case sqlcalendar.Month:
+		start = fmt.Sprintf("MAKEDATE(YEAR(%s), 1) + INTERVAL ((MONTH(%s) - 1) DIV %d * %d) MONTH", local, local, interval.Count, interval.Count)
+	case sqlcalendar.Year:
+		start = fmt.Sprintf("MAKEDATE(YEAR(%s), 1)", local)
+	default:
+		start = fmt.Sprintf("DATE(%s)", local)
+	}
+
+	localEpoch := fmt.Sprintf("TIMESTAMPDIFF(SECOND, '1970-01-01', %s)", start)
+	return fmt.Sprintf("(%s - %s)", localEpoch, interval.UTCOffset(localEpoch, timeRange, true))
+}
@@ -57,6 +57,51 @@ func TestMacroEngine(t *testing.T) {
 			require.Equal(t, sql+" AS \"time\"", sql2)
 		})
 
+		t.Run("interpolate __timeGroupCalendar function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '1d', 'UTC')")
+			require.Nil(t, err)
+			sql2, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendarAlias(time_column, '1d', 'UTC')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY (TIMESTAMPDIFF(SECOND, '1970-01-01', DATE((TIMESTAMP('1970-01-01') + INTERVAL (UNIX_TIMESTAMP(time_column) + 0) SECOND))) - 0)", sql)
+			require.Equal(t, sql+" AS \"time\"", sql2)
+		})
+
+		t.Run("interpolate __timeGroupCalendar function with months", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '3M', 'Asia/Tokyo')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY (TIMESTAMPDIFF(SECOND, '1970-01-01', MAKEDATE(YEAR((TIMESTAMP('1970-01-01') + INTERVAL (UNIX_TIMESTAMP(time_column) + 32400) SECOND)), 1) + INTERVAL ((MONTH((TIMESTAMP('1970-01-01') + INTERVAL (UNIX_TIMESTAMP(time_column) + 32400) SECOND)) - 1) DIV 3 * 3) MONTH) - 32400)", sql)
+		})
+
+		t.Run("interpolate __timeGroupCalendar function across a daylight saving time change", func(t *testing.T) {
+			timeRange := backend.TimeRange{
+				From: time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			}
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '1w', 'Europe/Berlin')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY (TIMESTAMPDIFF(SECOND, '1970-01-01', DATE((TIMESTAMP('1970-01-01') + INTERVAL (UNIX_TIMESTAMP(time_column) + (CASE WHEN UNIX_TIMESTAMP(time_column) >= 1711846800 THEN 7200 ELSE 3600 END)) SECOND)) - INTERVAL WEEKDAY((TIMESTAMP('1970-01-01') + INTERVAL (UNIX_TIMESTAMP(time_column) + (CASE WHEN UNIX_TIMESTAMP(time_column) >= 1711846800 THEN 7200 ELSE 3600 END)) SECOND)) DAY) - (CASE WHEN TIMESTAMPDIFF(SECOND, '1970-01-01', DATE((TIMESTAMP('1970-01-01') + INTERVAL (UNIX_TIMESTAMP(time_column) + (CASE WHEN UNIX_TIMESTAMP(time_column) >= 1711846800 THEN 7200 ELSE 3600 END)) SECOND)) - INTERVAL WEEKDAY((TIMESTAMP('1970-01-01') + INTERVAL (UNIX_TIMESTAMP(time_column) + (CASE WHEN UNIX_TIMESTAMP(time_column) >= 1711846800 THEN 7200 ELSE 3600 END)) SECOND)) DAY) >= 1711854000 THEN 7200 ELSE 3600 END))", sql)
+		})
+
+		t.Run("interpolate __timeGroupCalendar function with fill", func(t *testing.T) {
+			query := &backend.DataQuery{JSON: []byte(`{}`)}
+			_, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '1M', 'Europe/Berlin', NULL)")
+			require.Nil(t, err)
+
+			require.JSONEq(t, `{"fill": true, "fillInterval": 0, "fillMode": "null", "fillCalendarInterval": "1M", "fillTimezone": "Europe/Berlin"}`, string(query.JSON))
+		})
+
+		t.Run("interpolate __timeGroupCalendar function with invalid arguments", func(t *testing.T) {
+			_, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '1M')")
+			require.Error(t, err)
+			_, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '5M', 'UTC')")
+			require.Error(t, err)
+			_, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '1M', 'Mars/Olympus')")
+			require.Error(t, err)
+		})
+
 		t.Run("interpolate __timeFilter function", func(t *testing.T) {
 			sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column)")
 			require.Nil(t, err)
@@ -19,6 +19,8 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+
+	"github.com/grafana/grafana/pkg/tsdb/sqlcalendar"
 )
 
 // MetaKeyExecutedQueryString is the key where the executed query should get stored
@@ -97,6 +99,14 @@ type QueryJson struct {
 	FillMode     string  `json:"fillMode"`
 	FillValue    float64 `json:"fillValue"`
 	Format       string  `json:"format"`
+	// FillCalendarInterval and FillTimezone are set instead of FillInterval by calendar aware
+	// macros, whose buckets vary in length.
+	FillCalendarInterval string `json:"fillCalendarInterval"`
+	FillTimezone         string `json:"fillTimezone"`
+	// Parameterized queries reference Parameters with $__param(name) and have them bound as
+	// driver parameters instead of interpolated into the query.
+	Parameterized bool             `json:"parameterized"`
+	Parameters    []QueryParameter `json:"parameters"`
 }
 
 func (e *DataSourceHandler) TransformQueryError(logger log.Logger, err error) error {
@@ -169,7 +179,8 @@ func (e *DataSourceHandler) QueryData(ctx context.Context, req *backend.QueryDat
 
 		// the fill-params are only stored inside this function, during query-interpolation. we do not support
 		// sending them in "from the outside"
-		if queryjson.Fill || queryjson.FillInterval != 0.0 || queryjson.FillMode != "" || queryjson.FillValue != 0.0 {
+		if queryjson.Fill || queryjson.FillInterval != 0.0 || queryjson.FillMode != "" || queryjson.FillValue != 0.0 ||
+			queryjson.FillCalendarInterval != "" || queryjson.FillTimezone != "" {
 			return nil, fmt.Errorf("query fill-parameters not supported")
 		}
 
@@ -243,6 +254,18 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 	// global substitutions
 	interpolatedQuery := Interpolate(query, timeRange, e.dsInfo.JsonData.TimeInterval, queryJson.RawSql)
 
+	// parameters are bound before the data source specific substitutions, which would reject
+	// $__param as an unknown macro
+	var args []any
+	if queryJson.Parameterized {
+		var err error
+		interpolatedQuery, args, err = bindParameters(interpolatedQuery, queryJson.Parameters)
+		if err != nil {
+			errAppendDebug("binding parameters failed", backend.DownstreamError(err), queryJson.RawSql, backend.ErrorSourceDownstream)
+			return
+		}
+	}
+
 	// data source specific substitutions
 	interpolatedQuery, err := e.macroEngine.Interpolate(&query, timeRange, interpolatedQuery)
 	if err != nil {
@@ -250,7 +273,7 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 		return
 	}
 
-	rows, err := e.db.QueryContext(queryContext, interpolatedQuery)
+	rows, err := e.db.QueryContext(queryContext, interpolatedQuery, args...)
 	if err != nil {
 		errAppendDebug("db query error", e.TransformQueryError(logger, err), interpolatedQuery, backend.ErrorSourceDownstream)
 		return
@@ -399,6 +422,13 @@ func (e *DataSourceHandler) newProcessCfg(query backend.DataQuery, queryContext
 	if queryJson.Fill {
 		qm.FillMissing = &data.FillMissing{}
 		qm.Interval = time.Duration(queryJson.FillInterval * float64(time.Second))
+		if queryJson.FillCalendarInterval != "" {
+			calendarInterval, err := sqlcalendar.Parse(queryJson.FillCalendarInterval, queryJson.FillTimezone)
+			if err != nil {
+				return nil, err
+			}
+			qm.CalendarInterval = &calendarInterval
+		}
 		switch strings.ToLower(queryJson.FillMode) {
 		case "null":
 			qm.FillMissing.Mode = data.FillModeNull
@@ -471,6 +501,7 @@ type dataQueryModel struct {
 	TimeRange         backend.TimeRange
 	FillMissing       *data.FillMissing // property not set until after Interpolate()
 	Interval          time.Duration
+	CalendarInterval  *sqlcalendar.Interval // set instead of Interval by calendar aware macros
 	columnNames       []string
 	columnTypes       []*sql.ColumnType
 	timeIndex         int
@@ -564,6 +595,10 @@ func convertSQLValueColumnToFloat(frame *data.Frame, Index int) (*data.Frame, er
 // of fill points would exceed the row limit the fill is skipped and a warning
 // notice is appended to the frame instead.
 func (e *DataSourceHandler) applyFill(frame *data.Frame, qm *dataQueryModel) *data.Frame {
+	if qm.CalendarInterval != nil {
+		return e.applyCalendarFill(frame, qm)
+	}
+
 	startUnixTime := qm.TimeRange.From.Unix() / int64(qm.Interval.Seconds()) * int64(qm.Interval.Seconds())
 	alignedTimeRange := backend.TimeRange{
 		From: time.Unix(startUnixTime, 0),
@@ -592,6 +627,27 @@ func (e *DataSourceHandler) applyFill(frame *data.Frame, qm *dataQueryModel) *da
 	return frame
 }
 
+// applyCalendarFill is applyFill for calendar intervals, whose buckets vary in length.
+func (e *DataSourceHandler) applyCalendarFill(frame *data.Frame, qm *dataQueryModel) *data.Frame {
+	buckets, ok := qm.CalendarInterval.Buckets(qm.TimeRange, e.rowLimit)
+	if !ok {
+		e.log.Warn("Skipping fill: number of fill points exceeds row limit", "rowLimit", e.rowLimit)
+		frame.AppendNotices(data.Notice{
+			Text:     "Fill operation skipped: time range and interval would require more points than the configured row limit",
+			Severity: data.NoticeSeverityWarning,
+		})
+		return frame
+	}
+
+	resampled, err := sqlcalendar.Resample(frame, qm.FillMissing, buckets)
+	if err != nil {
+		e.log.Error("Failed to resample dataframe", "err", err)
+		frame.AppendNotices(data.Notice{Text: "Failed to resample dataframe", Severity: data.NoticeSeverityWarning})
+		return frame
+	}
+	return resampled
+}
+
 func SetupFillmode(query *backend.DataQuery, interval time.Duration, fillmode string) error {
 	rawQueryProp := make(map[string]any)
 	queryBytes, err := query.JSON.MarshalJSON()
@@ -625,6 +681,14 @@ func SetupFillmode(query *backend.DataQuery, interval time.Duration, fillmode st
 	return nil
 }
 
+// SetupCalendarFillmode is SetupFillmode for calendar intervals.
+func SetupCalendarFillmode(query *backend.DataQuery, interval sqlcalendar.Interval, fillmode string) error {
+	if err := SetupFillmode(query, 0, fillmode); err != nil {
+		return err
+	}
+	return sqlcalendar.SetupFill(query, interval)
+}
+
 type SQLMacroEngineBase struct{}
 
 func NewSQLMacroEngineBase() *SQLMacroEngineBase {
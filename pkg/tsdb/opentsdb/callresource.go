@@ -212,6 +212,102 @@ func (s *Service) HandleFiltersQuery(rw http.ResponseWriter, req *http.Request)
 	}
 }
 
+// HandleRollupConfigQuery returns the rollup configuration of OpenTSDB 2.4 and above, read
+// from the tsd.rollups settings of its running configuration.
+func (s *Service) HandleRollupConfigQuery(rw http.ResponseWriter, req *http.Request) {
+	logger := logger.FromContext(req.Context())
+
+	dsInfo, err := s.getDSInfo(req.Context(), backend.PluginConfigFromContext(req.Context()))
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to get datasource info: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to parse datasource URL: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	u.Path = path.Join(u.Path, "api/config")
+	httpReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to create request: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	res, err := dsInfo.HTTPClient.Do(httpReq)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to execute request: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Error("Failed to close response body", "error", err)
+		}
+	}()
+
+	responseBody, err := DecodeResponseBody(res, logger)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to decode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if res.StatusCode/100 != 2 {
+		http.Error(rw, fmt.Sprintf("OpenTSDB config endpoint returned status %d", res.StatusCode), res.StatusCode)
+		return
+	}
+
+	var tsdbConfig map[string]string
+	if err := json.Unmarshal(responseBody, &tsdbConfig); err != nil {
+		http.Error(rw, fmt.Sprintf("failed to unmarshal config response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rollupConfig := ParseRollupConfig(tsdbConfig)
+	rollupResponse, err := json.Marshal(rollupConfig)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to marshal response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	if _, err := rw.Write(rollupResponse); err != nil {
+		logger.Error("Failed to write response", "error", err)
+		return
+	}
+}
+
+// ParseRollupConfig reads the rollup configuration from the OpenTSDB configuration. The
+// intervals are only known when tsd.rollups.config holds the configuration itself rather than
+// the path of a file holding it.
+func ParseRollupConfig(tsdbConfig map[string]string) OpenTsdbRollupConfig {
+	rollupConfig := OpenTsdbRollupConfig{
+		Enabled:     tsdbConfig["tsd.rollups.enable"] == "true",
+		Aggregators: []string{},
+		Intervals:   []OpenTsdbRollupInterval{},
+	}
+
+	var config struct {
+		AggregationIds map[string]int           `json:"aggregationIds"`
+		Intervals      []OpenTsdbRollupInterval `json:"intervals"`
+	}
+	if err := json.Unmarshal([]byte(tsdbConfig["tsd.rollups.config"]), &config); err != nil {
+		return rollupConfig
+	}
+
+	for aggregator := range config.AggregationIds {
+		rollupConfig.Aggregators = append(rollupConfig.Aggregators, aggregator)
+	}
+	sort.Strings(rollupConfig.Aggregators)
+	if config.Intervals != nil {
+		rollupConfig.Intervals = config.Intervals
+	}
+	return rollupConfig
+}
+
 func (s *Service) HandleLookupQuery(rw http.ResponseWriter, req *http.Request) {
 	queryParams := req.URL.Query()
 	typeParam := queryParams.Get("type")
@@ -0,0 +1,263 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// Expressions refer to the queries they combine by their ref IDs.
+var expressionIdentifier = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+func (s *Service) queryExpression(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery, queries []backend.DataQuery) backend.DataResponse {
+	expQuery, err := BuildExpressionQuery(query, queries)
+	if err != nil {
+		return backend.ErrorResponseWithErrorSource(err)
+	}
+
+	httpReq, err := CreateExpressionRequest(ctx, dsInfo, expQuery)
+	if err != nil {
+		return backend.ErrorResponseWithErrorSource(err)
+	}
+
+	httpRes, err := doRequest(dsInfo, httpReq)
+	if err != nil {
+		return backend.ErrorResponseWithErrorSource(err)
+	}
+
+	frames, err := ParseExpressionResponse(logger, httpRes, query.RefID)
+	if err != nil {
+		return backend.ErrorResponseWithErrorSource(backend.DownstreamError(err))
+	}
+	return backend.DataResponse{Frames: frames}
+}
+
+// BuildExpressionQuery builds a request to the expression API for an expression query. The
+// metrics of the expression are the other queries of the request it refers to by their ref IDs,
+// so "A / B * 100" divides the metric of query A by the metric of query B.
+func BuildExpressionQuery(query backend.DataQuery, queries []backend.DataQuery) (OpenTsdbExpressionQuery, error) {
+	var model QueryModel
+	if err := json.Unmarshal(query.JSON, &model); err != nil {
+		return OpenTsdbExpressionQuery{}, backend.PluginError(err)
+	}
+
+	expr := strings.TrimSpace(model.Expression)
+	if expr == "" {
+		return OpenTsdbExpressionQuery{}, backend.DownstreamError(errors.New("expression queries need an expression"))
+	}
+
+	aggregator := model.Aggregator
+	if aggregator == "" {
+		aggregator = "sum"
+	}
+	fillPolicy := expressionFillPolicy(model.DownsampleFillPolicy)
+
+	expQuery := OpenTsdbExpressionQuery{
+		Time: OpenTsdbExpressionTime{
+			Start:      strconv.FormatInt(query.TimeRange.From.UnixMilli(), 10),
+			End:        strconv.FormatInt(query.TimeRange.To.UnixMilli(), 10),
+			Aggregator: aggregator,
+		},
+		Filters:     []OpenTsdbExpressionFilter{},
+		Metrics:     []OpenTsdbExpressionMetric{},
+		Expressions: []OpenTsdbExpression{{ID: query.RefID, Expr: expr}},
+		Outputs:     []OpenTsdbExpressionOutput{{ID: query.RefID, Alias: expr}},
+	}
+	if !model.DisableDownsampling {
+		downsampleAggregator := model.DownsampleAggregator
+		if downsampleAggregator == "" {
+			downsampleAggregator = "avg"
+		}
+		expQuery.Time.Downsampler = &OpenTsdbExpressionDownsampler{
+			Interval:   DownsampleInterval(model, query.Interval),
+			Aggregator: downsampleAggregator,
+			FillPolicy: fillPolicy,
+		}
+	}
+
+	byRefID := make(map[string]backend.DataQuery, len(queries))
+	for _, q := range queries {
+		if q.RefID != query.RefID {
+			byRefID[q.RefID] = q
+		}
+	}
+
+	used := map[string]bool{}
+	for _, id := range expressionIdentifier.FindAllString(expr, -1) {
+		ref, ok := byRefID[id]
+		if !ok || used[id] {
+			continue
+		}
+		used[id] = true
+
+		var refModel QueryModel
+		if err := json.Unmarshal(ref.JSON, &refModel); err != nil {
+			return OpenTsdbExpressionQuery{}, backend.PluginError(err)
+		}
+		if ref.QueryType == queryTypeExpression || ref.QueryType == queryTypeTSUID || refModel.Metric == "" {
+			return OpenTsdbExpressionQuery{}, backend.DownstreamError(fmt.Errorf("query %s used in expression %q must query a metric", id, expr))
+		}
+
+		metric := OpenTsdbExpressionMetric{
+			ID:         id,
+			Metric:     refModel.Metric,
+			Aggregator: refModel.Aggregator,
+			FillPolicy: fillPolicy,
+		}
+
+		tags, err := expressionTagFilters(refModel)
+		if err != nil {
+			return OpenTsdbExpressionQuery{}, backend.DownstreamError(fmt.Errorf("invalid filters in query %s: %w", id, err))
+		}
+		if len(tags) > 0 {
+			metric.Filter = "filter" + id
+			expQuery.Filters = append(expQuery.Filters, OpenTsdbExpressionFilter{ID: metric.Filter, Tags: tags})
+		}
+		expQuery.Metrics = append(expQuery.Metrics, metric)
+	}
+
+	if len(expQuery.Metrics) == 0 {
+		return OpenTsdbExpressionQuery{}, backend.DownstreamError(fmt.Errorf("expression %q doesn't use any query, refer to queries by their ref IDs", expr))
+	}
+	return expQuery, nil
+}
+
+// expressionFillPolicy returns the policy filling missing values of downsampled series. The
+// expression API writes NaN values as-is, which isn't valid JSON, so they're filled with nulls.
+func expressionFillPolicy(policy string) *OpenTsdbFillPolicy {
+	switch policy {
+	case "nan", "null":
+		return &OpenTsdbFillPolicy{Policy: "null"}
+	case "zero":
+		return &OpenTsdbFillPolicy{Policy: "zero"}
+	}
+	return nil
+}
+
+// expressionTagFilters returns the filters of the query, converting tags the way OpenTSDB does.
+func expressionTagFilters(model QueryModel) ([]OpenTsdbTagFilter, error) {
+	if len(model.Filters) > 0 {
+		raw, err := json.Marshal(model.Filters)
+		if err != nil {
+			return nil, err
+		}
+		var filters []OpenTsdbTagFilter
+		if err := json.Unmarshal(raw, &filters); err != nil {
+			return nil, err
+		}
+		return filters, nil
+	}
+
+	tagKeys := make([]string, 0, len(model.Tags))
+	for tagKey := range model.Tags {
+		tagKeys = append(tagKeys, tagKey)
+	}
+	sort.Strings(tagKeys)
+
+	filters := make([]OpenTsdbTagFilter, 0, len(tagKeys))
+	for _, tagKey := range tagKeys {
+		value := fmt.Sprint(model.Tags[tagKey])
+		filterType := "literal_or"
+		if strings.Contains(value, "*") {
+			filterType = "wildcard"
+		}
+		filters = append(filters, OpenTsdbTagFilter{Type: filterType, Tagk: tagKey, Filter: value, GroupBy: true})
+	}
+	return filters, nil
+}
+
+func CreateExpressionRequest(ctx context.Context, dsInfo *datasourceInfo, expQuery OpenTsdbExpressionQuery) (*http.Request, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, backend.DownstreamError(fmt.Errorf("failed to parse OpenTSDB URL %q: %w", dsInfo.URL, err))
+	}
+	u.Path = path.Join(u.Path, "api/query/exp")
+
+	postData, err := json.Marshal(expQuery)
+	if err != nil {
+		return nil, backend.PluginError(fmt.Errorf("failed to marshal OpenTSDB expression request body: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(string(postData)))
+	if err != nil {
+		return nil, backend.DownstreamError(fmt.Errorf("failed to create OpenTSDB request: %w", err))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// ParseExpressionResponse returns a frame for each series of the expression.
+func ParseExpressionResponse(logger log.Logger, res *http.Response, refID string) (data.Frames, error) {
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := DecodeResponseBody(res, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode/100 != 2 {
+		logger.Info("Request failed", "status", res.Status, "body", string(body))
+		return nil, fmt.Errorf("request failed, status: %s", res.Status)
+	}
+
+	var response OpenTsdbExpressionResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		logger.Info("Failed to unmarshal opentsdb expression response", "error", err, "status", res.Status, "body", string(body))
+		return nil, err
+	}
+
+	frames := data.Frames{}
+	for _, output := range response.Outputs {
+		name := output.Alias
+		if name == "" {
+			name = output.ID
+		}
+
+		for _, meta := range output.Meta {
+			// the first column holds the timestamps
+			if meta.Index == 0 {
+				continue
+			}
+
+			frame := CreateDataFrame(OpenTsdbCommon{
+				Metric:        name,
+				Tags:          meta.CommonTags,
+				AggregateTags: meta.AggregatedTags,
+			}, len(output.DataPoints), refID)
+
+			for i, point := range output.DataPoints {
+				var timestamp int64
+				if len(point) > 0 && point[0] != nil {
+					timestamp = int64(*point[0])
+				}
+				value := math.NaN()
+				if meta.Index < len(point) && point[meta.Index] != nil {
+					value = *point[meta.Index]
+				}
+				frame.SetRow(i, time.UnixMilli(timestamp).UTC(), value)
+			}
+
+			frames = append(frames, frame)
+		}
+	}
+	return frames, nil
+}
@@ -59,8 +59,27 @@ type QueryModel struct {
 	CounterMax           string                 `json:"counterMax"`
 	CounterResetValue    string                 `json:"counterResetValue"`
 	ExplicitTags         bool                   `json:"explicitTags"`
+	TSUIDs               string                 `json:"tsuids"`
+	Expression           string                 `json:"expression"`
+	RollupUsage          string                 `json:"rollupUsage"`
+	Percentiles          string                 `json:"percentiles"`
+	ShowHistogramBuckets bool                   `json:"showHistogramBuckets"`
 }
 
+// Query types, queries without one of these types query a metric.
+const (
+	queryTypeTSUID      = "tsuid"
+	queryTypeExpression = "expression"
+)
+
+// How OpenTSDB 2.4 and above use rollup tables to answer a query.
+const (
+	RollupUsageRaw         = "ROLLUP_RAW"
+	RollupUsageNoFallback  = "ROLLUP_NOFALLBACK"
+	RollupUsageFallback    = "ROLLUP_FALLBACK"
+	RollupUsageFallbackRaw = "ROLLUP_FALLBACK_RAW"
+)
+
 func newInstanceSettings(httpClientProvider *httpclient.Provider) datasource.InstanceFactoryFunc {
 	return func(ctx context.Context, settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
 		opts, err := settings.HTTPClientOptions(ctx)
@@ -157,6 +176,7 @@ func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceReq
 	mux.HandleFunc("/api/aggregators", s.HandleAggregatorsQuery)
 	mux.HandleFunc("/api/config/filters", s.HandleFiltersQuery)
 	mux.HandleFunc("/api/search/lookup", s.HandleLookupQuery)
+	mux.HandleFunc("/api/config/rollups", s.HandleRollupConfigQuery)
 
 	handler := httpadapter.New(mux)
 	return handler.CallResource(ctx, req, sender)
@@ -175,9 +195,17 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	}
 
 	for _, query := range req.Queries {
+		if query.QueryType == queryTypeExpression {
+			result.Responses[query.RefID] = s.queryExpression(ctx, logger, dsInfo, query, req.Queries)
+			continue
+		}
+
 		metric, err := BuildMetric(query)
 		if err != nil {
-			result.Responses[query.RefID] = backend.ErrorResponseWithErrorSource(backend.PluginError(err))
+			if !backend.IsDownstreamError(err) {
+				err = backend.PluginError(err)
+			}
+			result.Responses[query.RefID] = backend.ErrorResponseWithErrorSource(err)
 			continue
 		}
 
@@ -195,15 +223,8 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 			continue
 		}
 
-		httpRes, err := dsInfo.HTTPClient.Do(httpReq)
+		httpRes, err := doRequest(dsInfo, httpReq)
 		if err != nil {
-			if backend.IsDownstreamHTTPError(err) {
-				err = backend.DownstreamError(err)
-			}
-			var urlErr *url.Error
-			if errors.As(err, &urlErr) && urlErr.Err != nil && strings.HasPrefix(urlErr.Err.Error(), "unsupported protocol scheme") {
-				err = backend.DownstreamError(err)
-			}
 			result.Responses[query.RefID] = backend.ErrorResponseWithErrorSource(err)
 			continue
 		}
@@ -226,6 +247,23 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	return result, nil
 }
 
+// doRequest sends the request to OpenTSDB, marking errors caused by the network or the
+// data source configuration as downstream errors.
+func doRequest(dsInfo *datasourceInfo, httpReq *http.Request) (*http.Response, error) {
+	httpRes, err := dsInfo.HTTPClient.Do(httpReq)
+	if err != nil {
+		if backend.IsDownstreamHTTPError(err) {
+			err = backend.DownstreamError(err)
+		}
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) && urlErr.Err != nil && strings.HasPrefix(urlErr.Err.Error(), "unsupported protocol scheme") {
+			err = backend.DownstreamError(err)
+		}
+		return nil, err
+	}
+	return httpRes, nil
+}
+
 func (s *Service) getDSInfo(ctx context.Context, pluginCtx backend.PluginContext) (*datasourceInfo, error) {
 	i, err := s.im.Get(ctx, pluginCtx)
 	if err != nil {
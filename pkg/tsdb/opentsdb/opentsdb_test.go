@@ -1,8 +1,11 @@
 package opentsdb
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -232,6 +235,220 @@ func TestBuildMetric(t *testing.T) {
 		require.NoError(t, err)
 		require.Nil(t, metric["explicitTags"], "explicitTags should not be present when false")
 	})
+
+	t.Run("Build metric for TSUIDs", func(t *testing.T) {
+		query := backend.DataQuery{
+			QueryType: queryTypeTSUID,
+			JSON: []byte(`
+					{
+						"metric": "ignored",
+						"tsuids": "000001000001000001, 000001000001000002",
+						"aggregator": "sum",
+						"disableDownsampling": true
+					}`,
+			),
+		}
+
+		metric, err := BuildMetric(query)
+		require.NoError(t, err)
+		require.Equal(t, []string{"000001000001000001", "000001000001000002"}, metric["tsuids"])
+		require.Nil(t, metric["metric"])
+		require.Equal(t, "sum", metric["aggregator"])
+
+		query.JSON = []byte(`{"aggregator": "sum", "tsuids": " "}`)
+		_, err = BuildMetric(query)
+		require.Error(t, err)
+		require.True(t, backend.IsDownstreamError(err))
+	})
+
+	t.Run("Build metric with rollup usage and percentiles", func(t *testing.T) {
+		query := backend.DataQuery{
+			JSON: []byte(`
+					{
+						"metric": "http.latency",
+						"aggregator": "sum",
+						"disableDownsampling": true,
+						"rollupUsage": "ROLLUP_FALLBACK",
+						"percentiles": "50, 99.9",
+						"showHistogramBuckets": true
+					}`,
+			),
+		}
+
+		metric, err := BuildMetric(query)
+		require.NoError(t, err)
+		require.Equal(t, "ROLLUP_FALLBACK", metric["rollupUsage"])
+		require.Equal(t, []float64{50, 99.9}, metric["percentiles"])
+		require.True(t, metric["showHistogramBuckets"].(bool))
+	})
+
+	t.Run("Build metric with invalid rollup usage or percentiles", func(t *testing.T) {
+		for _, model := range []string{
+			`{"metric": "m", "rollupUsage": "ROLLUP_SOMETIMES"}`,
+			`{"metric": "m", "percentiles": "99, fast"}`,
+			`{"metric": "m", "percentiles": "0"}`,
+			`{"metric": "m", "percentiles": "101"}`,
+		} {
+			_, err := BuildMetric(backend.DataQuery{JSON: []byte(model)})
+			require.Error(t, err, model)
+			require.True(t, backend.IsDownstreamError(err), model)
+		}
+	})
+}
+
+func TestBuildExpressionQuery(t *testing.T) {
+	timeRange := backend.TimeRange{From: time.UnixMilli(1700000000000), To: time.UnixMilli(1700003600000)}
+	queries := []backend.DataQuery{
+		{
+			RefID:     "A",
+			TimeRange: timeRange,
+			JSON:      []byte(`{"metric": "sys.cpu.user", "aggregator": "avg", "filters": [{"type": "wildcard", "tagk": "host", "filter": "web*", "groupBy": true}]}`),
+		},
+		{
+			RefID:     "B",
+			TimeRange: timeRange,
+			JSON:      []byte(`{"metric": "sys.cpu.total", "aggregator": "sum", "tags": {"host": "*", "dc": "eu"}}`),
+		},
+		{
+			RefID:     "C",
+			QueryType: queryTypeExpression,
+			TimeRange: timeRange,
+			Interval:  time.Minute,
+			JSON:      []byte(`{"expression": "A / B * 100", "aggregator": "sum", "downsampleAggregator": "max", "downsampleFillPolicy": "nan"}`),
+		},
+	}
+
+	expQuery, err := BuildExpressionQuery(queries[2], queries)
+	require.NoError(t, err)
+
+	fillPolicy := &OpenTsdbFillPolicy{Policy: "null"}
+	expected := OpenTsdbExpressionQuery{
+		Time: OpenTsdbExpressionTime{
+			Start:       "1700000000000",
+			End:         "1700003600000",
+			Aggregator:  "sum",
+			Downsampler: &OpenTsdbExpressionDownsampler{Interval: "1m", Aggregator: "max", FillPolicy: fillPolicy},
+		},
+		Filters: []OpenTsdbExpressionFilter{
+			{ID: "filterA", Tags: []OpenTsdbTagFilter{{Type: "wildcard", Tagk: "host", Filter: "web*", GroupBy: true}}},
+			{ID: "filterB", Tags: []OpenTsdbTagFilter{
+				{Type: "literal_or", Tagk: "dc", Filter: "eu", GroupBy: true},
+				{Type: "wildcard", Tagk: "host", Filter: "*", GroupBy: true},
+			}},
+		},
+		Metrics: []OpenTsdbExpressionMetric{
+			{ID: "A", Metric: "sys.cpu.user", Filter: "filterA", Aggregator: "avg", FillPolicy: fillPolicy},
+			{ID: "B", Metric: "sys.cpu.total", Filter: "filterB", Aggregator: "sum", FillPolicy: fillPolicy},
+		},
+		Expressions: []OpenTsdbExpression{{ID: "C", Expr: "A / B * 100"}},
+		Outputs:     []OpenTsdbExpressionOutput{{ID: "C", Alias: "A / B * 100"}},
+	}
+	require.Equal(t, expected, expQuery)
+
+	t.Run("expressions must use queries of metrics", func(t *testing.T) {
+		for _, tc := range []struct {
+			name       string
+			expression string
+		}{
+			{"no expression", ""},
+			{"unknown query", "D * 2"},
+			{"another expression", "C * 2"},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				query := backend.DataQuery{
+					RefID:     "E",
+					QueryType: queryTypeExpression,
+					JSON:      []byte(`{"expression": "` + tc.expression + `"}`),
+				}
+				_, err := BuildExpressionQuery(query, append(queries, query))
+				require.Error(t, err)
+				require.True(t, backend.IsDownstreamError(err))
+			})
+		}
+	})
+}
+
+func TestParseExpressionResponse(t *testing.T) {
+	response := `
+	{
+		"outputs": [
+			{
+				"id": "C",
+				"alias": "A / B * 100",
+				"dps": [
+					[1700000000000, 10, 20],
+					[1700000060000, null, 25]
+				],
+				"meta": [
+					{"index": 0, "metrics": ["timestamp"]},
+					{"index": 1, "metrics": ["sys.cpu.user", "sys.cpu.total"], "commonTags": {"host": "web01"}, "aggregatedTags": []},
+					{"index": 2, "metrics": ["sys.cpu.user", "sys.cpu.total"], "commonTags": {"host": "web02"}, "aggregatedTags": []}
+				]
+			}
+		]
+	}`
+
+	res := &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(response))}
+	frames, err := ParseExpressionResponse(logger, res, "C")
+	require.NoError(t, err)
+	require.Len(t, frames, 2)
+
+	require.Equal(t, "A / B * 100", frames[0].Name)
+	require.Equal(t, "C", frames[0].RefID)
+	require.Equal(t, data.Labels{"host": "web01"}, frames[0].Fields[1].Labels)
+	require.Equal(t, time.UnixMilli(1700000060000).UTC(), frames[0].Fields[0].At(1))
+	require.Equal(t, 10.0, frames[0].Fields[1].At(0))
+	require.True(t, math.IsNaN(frames[0].Fields[1].At(1).(float64)))
+
+	require.Equal(t, data.Labels{"host": "web02"}, frames[1].Fields[1].Labels)
+	require.Equal(t, 25.0, frames[1].Fields[1].At(1))
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, err = gz.Write([]byte(response))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	res = &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:       io.NopCloser(&gzipped),
+	}
+	frames, err = ParseExpressionResponse(logger, res, "C")
+	require.NoError(t, err)
+	require.Len(t, frames, 2)
+
+	res = &http.Response{StatusCode: 400, Status: "400 Bad Request", Body: io.NopCloser(strings.NewReader(`{"error":{}}`))}
+	_, err = ParseExpressionResponse(logger, res, "C")
+	require.Error(t, err)
+}
+
+func TestParseRollupConfig(t *testing.T) {
+	rollupConfig := ParseRollupConfig(map[string]string{
+		"tsd.rollups.enable": "true",
+		"tsd.rollups.config": `{
+			"aggregationIds": {"sum": 0, "count": 1, "max": 2},
+			"intervals": [
+				{"table": "tsdb", "preAggregationTable": "tsdb-preagg", "interval": "1m", "rowSpan": "1h", "defaultInterval": true},
+				{"table": "tsdb-rollup-1h", "preAggregationTable": "tsdb-rollup-preagg-1h", "interval": "1h", "rowSpan": "1d"}
+			]
+		}`,
+	})
+	require.True(t, rollupConfig.Enabled)
+	require.Equal(t, []string{"count", "max", "sum"}, rollupConfig.Aggregators)
+	require.Equal(t, []OpenTsdbRollupInterval{
+		{Table: "tsdb", PreAggregationTable: "tsdb-preagg", Interval: "1m", RowSpan: "1h", DefaultInterval: true},
+		{Table: "tsdb-rollup-1h", PreAggregationTable: "tsdb-rollup-preagg-1h", Interval: "1h", RowSpan: "1d"},
+	}, rollupConfig.Intervals)
+
+	// the configuration can also be the path of a file
+	rollupConfig = ParseRollupConfig(map[string]string{
+		"tsd.rollups.enable": "true",
+		"tsd.rollups.config": "/etc/opentsdb/rollups.json",
+	})
+	require.True(t, rollupConfig.Enabled)
+	require.Empty(t, rollupConfig.Intervals)
+
+	require.False(t, ParseRollupConfig(map[string]string{}).Enabled)
 }
 
 func TestOpenTsdbExecutor(t *testing.T) {
@@ -28,3 +28,94 @@ type OpenTsdbResponse24 struct {
 	OpenTsdbCommon
 	DataPoints [][]float64 `json:"dps"`
 }
+
+type OpenTsdbTagFilter struct {
+	Type    string `json:"type"`
+	Tagk    string `json:"tagk"`
+	Filter  string `json:"filter"`
+	GroupBy bool   `json:"groupBy"`
+}
+
+type OpenTsdbFillPolicy struct {
+	Policy string `json:"policy"`
+}
+
+// OpenTsdbExpressionQuery is the body of a request to the expression API, /api/query/exp.
+type OpenTsdbExpressionQuery struct {
+	Time        OpenTsdbExpressionTime     `json:"time"`
+	Filters     []OpenTsdbExpressionFilter `json:"filters"`
+	Metrics     []OpenTsdbExpressionMetric `json:"metrics"`
+	Expressions []OpenTsdbExpression       `json:"expressions"`
+	Outputs     []OpenTsdbExpressionOutput `json:"outputs"`
+}
+
+type OpenTsdbExpressionTime struct {
+	Start       string                         `json:"start"`
+	End         string                         `json:"end"`
+	Aggregator  string                         `json:"aggregator"`
+	Downsampler *OpenTsdbExpressionDownsampler `json:"downsampler,omitempty"`
+}
+
+type OpenTsdbExpressionDownsampler struct {
+	Interval   string              `json:"interval"`
+	Aggregator string              `json:"aggregator"`
+	FillPolicy *OpenTsdbFillPolicy `json:"fillPolicy,omitempty"`
+}
+
+type OpenTsdbExpressionFilter struct {
+	ID   string              `json:"id"`
+	Tags []OpenTsdbTagFilter `json:"tags"`
+}
+
+type OpenTsdbExpressionMetric struct {
+	ID         string              `json:"id"`
+	Metric     string              `json:"metric"`
+	Filter     string              `json:"filter,omitempty"`
+	Aggregator string              `json:"aggregator,omitempty"`
+	FillPolicy *OpenTsdbFillPolicy `json:"fillPolicy,omitempty"`
+}
+
+type OpenTsdbExpression struct {
+	ID   string `json:"id"`
+	Expr string `json:"expr"`
+}
+
+type OpenTsdbExpressionOutput struct {
+	ID    string `json:"id"`
+	Alias string `json:"alias"`
+}
+
+type OpenTsdbExpressionResponse struct {
+	Outputs []OpenTsdbExpressionOutputResponse `json:"outputs"`
+}
+
+// OpenTsdbExpressionOutputResponse holds the series of an expression. Each data point starts
+// with its timestamp in milliseconds, followed by the value of each series, as described by Meta.
+type OpenTsdbExpressionOutputResponse struct {
+	ID         string                         `json:"id"`
+	Alias      string                         `json:"alias"`
+	DataPoints [][]*float64                   `json:"dps"`
+	Meta       []OpenTsdbExpressionSeriesMeta `json:"meta"`
+}
+
+type OpenTsdbExpressionSeriesMeta struct {
+	Index          int               `json:"index"`
+	Metrics        []string          `json:"metrics"`
+	CommonTags     map[string]string `json:"commonTags"`
+	AggregatedTags []string          `json:"aggregatedTags"`
+}
+
+// OpenTsdbRollupConfig describes the rollup tables of OpenTSDB 2.4 and above.
+type OpenTsdbRollupConfig struct {
+	Enabled     bool                     `json:"enabled"`
+	Aggregators []string                 `json:"aggregators"`
+	Intervals   []OpenTsdbRollupInterval `json:"intervals"`
+}
+
+type OpenTsdbRollupInterval struct {
+	Table               string `json:"table"`
+	PreAggregationTable string `json:"preAggregationTable"`
+	Interval            string `json:"interval"`
+	RowSpan             string `json:"rowSpan"`
+	DefaultInterval     bool   `json:"defaultInterval"`
+}
@@ -4,6 +4,7 @@ import (
 	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
@@ -52,26 +54,21 @@ func BuildMetric(query backend.DataQuery) (map[string]any, error) {
 		return nil, err
 	}
 
-	// Setting metric and aggregator
-	metric["metric"] = model.Metric
+	// Setting metric, or the time series to query by their UIDs, and aggregator
+	if query.QueryType == queryTypeTSUID {
+		tsuids := splitList(model.TSUIDs)
+		if len(tsuids) == 0 {
+			return nil, backend.DownstreamError(errors.New("TSUID queries need at least one TSUID"))
+		}
+		metric["tsuids"] = tsuids
+	} else {
+		metric["metric"] = model.Metric
+	}
 	metric["aggregator"] = model.Aggregator
 
 	// Setting downsampling options
 	if !model.DisableDownsampling {
-		downsampleInterval := model.DownsampleInterval
-		if downsampleInterval == "" {
-			if ms := query.Interval.Milliseconds(); ms > 0 {
-				downsampleInterval = FormatDownsampleInterval(ms)
-			} else {
-				downsampleInterval = "1m"
-			}
-		} else if strings.Contains(downsampleInterval, ".") && strings.HasSuffix(downsampleInterval, "s") {
-			if val, err := strconv.ParseFloat(strings.TrimSuffix(downsampleInterval, "s"), 64); err == nil {
-				downsampleInterval = strconv.FormatInt(int64(val*1000), 10) + "ms"
-			}
-		}
-
-		downsample := downsampleInterval + "-" + model.DownsampleAggregator
+		downsample := DownsampleInterval(model, query.Interval) + "-" + model.DownsampleAggregator
 		if model.DownsampleFillPolicy != "" && model.DownsampleFillPolicy != "none" {
 			metric["downsample"] = downsample + "-" + model.DownsampleFillPolicy
 		} else {
@@ -126,9 +123,76 @@ func BuildMetric(query backend.DataQuery) (map[string]any, error) {
 		metric["explicitTags"] = true
 	}
 
+	// Setting rollup and histogram options
+	if model.RollupUsage != "" {
+		if !isValidRollupUsage(model.RollupUsage) {
+			return nil, backend.DownstreamError(fmt.Errorf("invalid rollup usage %q", model.RollupUsage))
+		}
+		metric["rollupUsage"] = model.RollupUsage
+	}
+
+	percentiles, err := ParsePercentiles(model.Percentiles)
+	if err != nil {
+		return nil, err
+	}
+	if len(percentiles) > 0 {
+		metric["percentiles"] = percentiles
+		if model.ShowHistogramBuckets {
+			metric["showHistogramBuckets"] = true
+		}
+	}
+
 	return metric, nil
 }
 
+// DownsampleInterval returns the downsample interval of the query, defaulting to the interval
+// Grafana computed for the panel.
+func DownsampleInterval(model QueryModel, interval time.Duration) string {
+	downsampleInterval := model.DownsampleInterval
+	if downsampleInterval == "" {
+		if ms := interval.Milliseconds(); ms > 0 {
+			return FormatDownsampleInterval(ms)
+		}
+		return "1m"
+	}
+
+	if strings.Contains(downsampleInterval, ".") && strings.HasSuffix(downsampleInterval, "s") {
+		if val, err := strconv.ParseFloat(strings.TrimSuffix(downsampleInterval, "s"), 64); err == nil {
+			downsampleInterval = strconv.FormatInt(int64(val*1000), 10) + "ms"
+		}
+	}
+	return downsampleInterval
+}
+
+func isValidRollupUsage(usage string) bool {
+	switch usage {
+	case RollupUsageRaw, RollupUsageNoFallback, RollupUsageFallback, RollupUsageFallbackRaw:
+		return true
+	}
+	return false
+}
+
+// ParsePercentiles parses a comma separated list of percentiles to compute from histogram metrics.
+func ParsePercentiles(value string) ([]float64, error) {
+	items := splitList(value)
+	percentiles := make([]float64, 0, len(items))
+	for _, item := range items {
+		p, err := strconv.ParseFloat(item, 64)
+		if err != nil || p <= 0 || p > 100 {
+			return nil, backend.DownstreamError(fmt.Errorf("invalid percentile %q, percentiles must be numbers greater than 0 and at most 100", item))
+		}
+		percentiles = append(percentiles, p)
+	}
+	return percentiles, nil
+}
+
+// splitList splits a comma or whitespace separated list, dropping empty items.
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
 func CreateRequest(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, data OpenTsdbQuery) (*http.Request, error) {
 	u, err := url.Parse(dsInfo.URL)
 	if err != nil {
@@ -0,0 +1,218 @@
+// Package sqlcalendar groups and fills the time series of the SQL data sources by calendar
+// intervals in a time zone. Only the SQL of the time grouping macros is dialect specific and
+// stays in each data source.
+package sqlcalendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// Unit is the unit of a calendar interval.
+type Unit string
+
+const (
+	Day   Unit = "d"
+	Week  Unit = "w"
+	Month Unit = "M"
+	Year  Unit = "y"
+)
+
+var calendarIntervalRegExp = regexp.MustCompile(`^(\d+)([dwMy])$`)
+
+// Interval is an interval that follows the calendar of a time zone, so that its
+// length varies with daylight saving time, the length of months and leap years.
+type Interval struct {
+	Count    int
+	Unit     Unit
+	Location *time.Location
+}
+
+// Parse parses intervals like 1d, 1w, 3M or 1y in the IANA time zone timezone.
+// Weeks start on Monday and groups of months start in January, so the number of months has to
+// divide a year.
+func Parse(interval string, timezone string) (Interval, error) {
+	match := calendarIntervalRegExp.FindStringSubmatch(strings.TrimSpace(interval))
+	if match == nil {
+		return Interval{}, fmt.Errorf("invalid calendar interval %q, expected a number followed by d, w, M or y", interval)
+	}
+	count, err := strconv.Atoi(match[1])
+	if err != nil {
+		return Interval{}, fmt.Errorf("invalid calendar interval %q: %w", interval, err)
+	}
+	unit := Unit(match[2])
+	switch {
+	case unit == Month && (count <= 0 || 12%count != 0):
+		return Interval{}, fmt.Errorf("invalid calendar interval %q, the number of months has to be 1, 2, 3, 4, 6 or 12", interval)
+	case unit != Month && count != 1:
+		return Interval{}, fmt.Errorf("invalid calendar interval %q, only 1%s is supported", interval, unit)
+	}
+
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return Interval{}, fmt.Errorf("invalid time zone %q: %w", timezone, err)
+	}
+	return Interval{Count: count, Unit: unit, Location: location}, nil
+}
+
+func (c Interval) String() string {
+	return strconv.Itoa(c.Count) + string(c.Unit)
+}
+
+// Truncate returns the start of the interval t is in.
+func (c Interval) Truncate(t time.Time) time.Time {
+	t = t.In(c.Location)
+	year, month, day := t.Date()
+	switch c.Unit {
+	case Week:
+		return time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, c.Location)
+	case Month:
+		return time.Date(year, (month-1)/time.Month(c.Count)*time.Month(c.Count)+1, 1, 0, 0, 0, 0, c.Location)
+	case Year:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, c.Location)
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, c.Location)
+	}
+}
+
+// Next returns the start of the interval following the one starting at t.
+func (c Interval) Next(t time.Time) time.Time {
+	switch c.Unit {
+	case Week:
+		return t.AddDate(0, 0, 7)
+	case Month:
+		return t.AddDate(0, c.Count, 0)
+	case Year:
+		return t.AddDate(1, 0, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// Buckets returns the start of every interval overlapping timeRange, stopping after limit buckets.
+func (c Interval) Buckets(timeRange backend.TimeRange, limit int64) ([]time.Time, bool) {
+	var buckets []time.Time
+	for t := c.Truncate(timeRange.From); t.Before(timeRange.To); t = c.Next(t) {
+		if int64(len(buckets)) >= limit {
+			return buckets, false
+		}
+		buckets = append(buckets, t)
+	}
+	return buckets, true
+}
+
+// UTCOffset returns an SQL expression for the UTC offset in seconds of the time zone at the
+// epoch seconds in epochExpr. With local set, epochExpr holds local time as if it were UTC
+// instead. Only the offset changes around timeRange are taken into account, which keeps the
+// expression short and plain integer arithmetic, so it works the same in every dialect.
+func (c Interval) UTCOffset(epochExpr string, timeRange backend.TimeRange, local bool) string {
+	// buckets may start before the time range, and the offset of their start matters as well
+	from := c.Truncate(timeRange.From).AddDate(0, 0, -1)
+	to := timeRange.To.AddDate(0, 0, 1)
+
+	_, initial := from.In(c.Location).Zone()
+	cases := []string{}
+	for t := from; ; {
+		_, end := t.In(c.Location).ZoneBounds()
+		if end.IsZero() || !end.Before(to) {
+			break
+		}
+		_, offset := end.In(c.Location).Zone()
+		boundary := end.Unix()
+		if local {
+			boundary += int64(offset)
+		}
+		cases = append([]string{fmt.Sprintf("WHEN %s >= %d THEN %d", epochExpr, boundary, offset)}, cases...)
+		t = end
+	}
+
+	if len(cases) == 0 {
+		return strconv.Itoa(initial)
+	}
+	return fmt.Sprintf("(CASE %s ELSE %d END)", strings.Join(cases, " "), initial)
+}
+
+// SetupFill sets the calendar interval to fill on a query whose fill mode is already set up.
+func SetupFill(query *backend.DataQuery, interval Interval) error {
+	rawQueryProp := make(map[string]any)
+	if err := json.Unmarshal(query.JSON, &rawQueryProp); err != nil {
+		return err
+	}
+	rawQueryProp["fillCalendarInterval"] = interval.String()
+	rawQueryProp["fillTimezone"] = interval.Location.String()
+
+	var err error
+	query.JSON, err = json.Marshal(rawQueryProp)
+	return err
+}
+
+// Resample is sqlutil.ResampleWideFrame for calendar intervals: it returns a frame with a row
+// for each bucket, taking the rows of frame that start a bucket and filling the others.
+func Resample(frame *data.Frame, fillMissing *data.FillMissing, buckets []time.Time) (*data.Frame, error) {
+	tsSchema := frame.TimeSeriesSchema()
+	if tsSchema.Type != data.TimeSeriesTypeWide {
+		return nil, fmt.Errorf("can not fill a frame of type %s", tsSchema.Type)
+	}
+	timeIndex := tsSchema.TimeIndex
+
+	rows := make(map[int64]int, frame.Rows())
+	for i := 0; i < frame.Rows(); i++ {
+		t, ok := frame.Fields[timeIndex].ConcreteAt(i)
+		if !ok {
+			continue
+		}
+		rows[t.(time.Time).Unix()] = i
+	}
+
+	resampled := data.NewFrame(frame.Name)
+	resampled.Meta = frame.Meta
+	for _, field := range frame.Fields {
+		newField := data.NewFieldFromFieldType(field.Type(), len(buckets))
+		newField.Name = field.Name
+		newField.Labels = field.Labels
+		newField.Config = field.Config
+		resampled.Fields = append(resampled.Fields, newField)
+	}
+
+	prev := -1
+	for i, bucket := range buckets {
+		row, ok := rows[bucket.Unix()]
+		for j, field := range frame.Fields {
+			newField := resampled.Fields[j]
+			switch {
+			case j == timeIndex:
+				if newField.Nullable() {
+					ts := bucket.UTC()
+					newField.Set(i, &ts)
+				} else {
+					newField.Set(i, bucket.UTC())
+				}
+			case ok:
+				newField.Set(i, field.CopyAt(row))
+			case fillMissing.Mode == data.FillModePrevious && prev != -1:
+				newField.Set(i, field.CopyAt(prev))
+			case fillMissing.Mode == data.FillModeValue && newField.Type() == data.FieldTypeNullableFloat64:
+				value := fillMissing.Value
+				newField.Set(i, &value)
+			case fillMissing.Mode == data.FillModeValue && newField.Type() == data.FieldTypeFloat64:
+				newField.Set(i, fillMissing.Value)
+			}
+		}
+		if ok {
+			prev = row
+		}
+	}
+
+	return resampled, nil
+}
@@ -0,0 +1,139 @@
+package sqlcalendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	interval, err := Parse("3M", "Europe/Berlin")
+	require.NoError(t, err)
+	require.Equal(t, 3, interval.Count)
+	require.Equal(t, Month, interval.Unit)
+	require.Equal(t, "Europe/Berlin", interval.Location.String())
+	require.Equal(t, "3M", interval.String())
+
+	interval, err = Parse("1d", "")
+	require.NoError(t, err)
+	require.Equal(t, time.UTC, interval.Location)
+
+	for _, tc := range []string{"", "1h", "2d", "2w", "5M", "0M", "2y"} {
+		_, err := Parse(tc, "UTC")
+		require.Error(t, err, tc)
+	}
+	_, err = Parse("1d", "Mars/Olympus")
+	require.Error(t, err)
+}
+
+func TestIntervalTruncate(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// 2024-03-31 is a Sunday, clocks in Berlin move forward one hour at 02:00 that day.
+	ts := time.Date(2024, 3, 31, 21, 30, 0, 0, time.UTC)
+
+	tcs := []struct {
+		interval string
+		start    time.Time
+		next     time.Time
+	}{
+		{"1d", time.Date(2024, 3, 31, 0, 0, 0, 0, berlin), time.Date(2024, 4, 1, 0, 0, 0, 0, berlin)},
+		{"1w", time.Date(2024, 3, 25, 0, 0, 0, 0, berlin), time.Date(2024, 4, 1, 0, 0, 0, 0, berlin)},
+		{"1M", time.Date(2024, 3, 1, 0, 0, 0, 0, berlin), time.Date(2024, 4, 1, 0, 0, 0, 0, berlin)},
+		{"6M", time.Date(2024, 1, 1, 0, 0, 0, 0, berlin), time.Date(2024, 7, 1, 0, 0, 0, 0, berlin)},
+		{"1y", time.Date(2024, 1, 1, 0, 0, 0, 0, berlin), time.Date(2025, 1, 1, 0, 0, 0, 0, berlin)},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.interval, func(t *testing.T) {
+			interval, err := Parse(tc.interval, "Europe/Berlin")
+			require.NoError(t, err)
+			start := interval.Truncate(ts)
+			require.True(t, tc.start.Equal(start), start)
+			require.True(t, tc.next.Equal(interval.Next(start)), interval.Next(start))
+		})
+	}
+
+	t.Run("daylight saving time change makes the day 23 hours long", func(t *testing.T) {
+		interval, err := Parse("1d", "Europe/Berlin")
+		require.NoError(t, err)
+		start := interval.Truncate(ts)
+		require.Equal(t, 23*time.Hour, interval.Next(start).Sub(start))
+	})
+}
+
+func TestIntervalUTCOffset(t *testing.T) {
+	timeRange := backend.TimeRange{
+		From: time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	interval, err := Parse("1d", "Europe/Berlin")
+	require.NoError(t, err)
+	require.Equal(t, "(CASE WHEN e >= 1711846800 THEN 7200 ELSE 3600 END)", interval.UTCOffset("e", timeRange, false))
+	require.Equal(t, "(CASE WHEN e >= 1711854000 THEN 7200 ELSE 3600 END)", interval.UTCOffset("e", timeRange, true))
+
+	interval, err = Parse("1d", "Asia/Kolkata")
+	require.NoError(t, err)
+	require.Equal(t, "19800", interval.UTCOffset("e", timeRange, false))
+}
+
+func TestResample(t *testing.T) {
+	interval, err := Parse("1M", "Europe/Berlin")
+	require.NoError(t, err)
+	timeRange := backend.TimeRange{
+		From: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+	buckets, ok := interval.Buckets(timeRange, 100)
+	require.True(t, ok)
+	require.Len(t, buckets, 4)
+
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, interval.Location).UTC()
+	mar := time.Date(2024, 3, 1, 0, 0, 0, 0, interval.Location).UTC()
+	one, three := 1.0, 3.0
+	newFrame := func() *data.Frame {
+		return data.NewFrame("",
+			data.NewField("time", nil, []*time.Time{&jan, &mar}),
+			data.NewField("value", nil, []*float64{&one, &three}),
+		)
+	}
+
+	t.Run("null", func(t *testing.T) {
+		frame, err := Resample(newFrame(), &data.FillMissing{Mode: data.FillModeNull}, buckets)
+		require.NoError(t, err)
+		require.Equal(t, 4, frame.Rows())
+		require.Equal(t, []any{&one, (*float64)(nil), &three, (*float64)(nil)}, []any{frame.Fields[1].At(0), frame.Fields[1].At(1), frame.Fields[1].At(2), frame.Fields[1].At(3)})
+		apr := time.Date(2024, 4, 1, 0, 0, 0, 0, interval.Location).UTC()
+		require.Equal(t, &apr, frame.Fields[0].At(3))
+	})
+
+	t.Run("previous", func(t *testing.T) {
+		frame, err := Resample(newFrame(), &data.FillMissing{Mode: data.FillModePrevious}, buckets)
+		require.NoError(t, err)
+		require.Equal(t, []any{&one, &one, &three, &three}, []any{frame.Fields[1].At(0), frame.Fields[1].At(1), frame.Fields[1].At(2), frame.Fields[1].At(3)})
+	})
+
+	t.Run("value", func(t *testing.T) {
+		frame, err := Resample(newFrame(), &data.FillMissing{Mode: data.FillModeValue, Value: 0}, buckets)
+		require.NoError(t, err)
+		zero := 0.0
+		require.Equal(t, []any{&one, &zero, &three, &zero}, []any{frame.Fields[1].At(0), frame.Fields[1].At(1), frame.Fields[1].At(2), frame.Fields[1].At(3)})
+	})
+
+	t.Run("too many buckets", func(t *testing.T) {
+		_, ok := interval.Buckets(timeRange, 2)
+		require.False(t, ok)
+	})
+}
+
+func TestSetupFill(t *testing.T) {
+	interval, err := Parse("1w", "Europe/Berlin")
+	require.NoError(t, err)
+
+	query := &backend.DataQuery{JSON: []byte(`{"rawSql":"SELECT 1","fill":true,"fillMode":"null"}`)}
+	require.NoError(t, SetupFill(query, interval))
+	require.JSONEq(t, `{"rawSql":"SELECT 1","fill":true,"fillMode":"null","fillCalendarInterval":"1w","fillTimezone":"Europe/Berlin"}`, string(query.JSON))
+}
@@ -0,0 +1,193 @@
+package sqlite
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+	"github.com/grafana/grafana/pkg/tsdb/mysql/sqleng"
+	"github.com/grafana/grafana/pkg/tsdb/sqlcalendar"
+)
+
+const rsIdentifier = `([_a-zA-Z0-9]+)`
+const sExpr = `\$` + rsIdentifier + `\(([^\)]*)\)`
+
+// restrictedRegExp matches statements that would give access to files outside of the configured
+// database file (ATTACH, VACUUM INTO) or load native code (load_extension).
+var restrictedRegExp = regexp.MustCompile(`(?im)(^|[\s;(])(attach|detach|vacuum)([\s;]|$)|(^|[\s,(])load_extension\s*\(`)
+
+type sqliteMacroEngine struct {
+	*sqleng.SQLMacroEngineBase
+	logger    log.Logger
+	userError string
+}
+
+func newSqliteMacroEngine(logger log.Logger, userFacingDefaultError string) sqleng.SQLMacroEngine {
+	return &sqliteMacroEngine{
+		SQLMacroEngineBase: sqleng.NewSQLMacroEngineBase(),
+		logger:             logger,
+		userError:          userFacingDefaultError,
+	}
+}
+
+func (m *sqliteMacroEngine) Interpolate(query *backend.DataQuery, timeRange backend.TimeRange, sql string) (string, error) {
+	if restrictedRegExp.MatchString(sql) {
+		m.logger.Error("ATTACH, DETACH, VACUUM or load_extension() not allowed in query")
+		return "", fmt.Errorf("invalid query - %s", m.userError)
+	}
+
+	// TODO: Handle error
+	rExp, _ := regexp.Compile(sExpr)
+	var macroError error
+
+	sql = m.ReplaceAllStringSubmatchFunc(rExp, sql, func(groups []string) string {
+		args := strings.Split(groups[2], ",")
+		for i, arg := range args {
+			args[i] = strings.Trim(arg, " ")
+		}
+		res, err := m.evaluateMacro(timeRange, query, groups[1], args)
+		if err != nil && macroError == nil {
+			macroError = err
+			return "macro_error()"
+		}
+		return res
+	})
+
+	if macroError != nil {
+		return "", macroError
+	}
+
+	return sql, nil
+}
+
+// unixEpoch returns the expression converting a time column to unix seconds. The auto modifier
+// lets the column hold either text timestamps or unix timestamps.
+func unixEpoch(column string) string {
+	return fmt.Sprintf("unixepoch(%s, 'auto')", column)
+}
+
+func (m *sqliteMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *backend.DataQuery, name string, args []string) (string, error) {
+	switch name {
+	case "__timeEpoch", "__time":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s AS time_sec", unixEpoch(args[0])), nil
+	case "__timeFilter":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s BETWEEN %d AND %d", unixEpoch(args[0]), timeRange.From.UTC().Unix(), timeRange.To.UTC().Unix()), nil
+	case "__timeFrom":
+		return fmt.Sprintf("datetime(%d, 'unixepoch')", timeRange.From.UTC().Unix()), nil
+	case "__timeTo":
+		return fmt.Sprintf("datetime(%d, 'unixepoch')", timeRange.To.UTC().Unix()), nil
+	case "__timeGroup":
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and interval", name)
+		}
+		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'"`))
+		if err != nil {
+			return "", fmt.Errorf("error parsing interval %v", args[1])
+		}
+		if len(args) == 3 {
+			err := sqleng.SetupFillmode(query, interval, args[2])
+			if err != nil {
+				return "", err
+			}
+		}
+		return fmt.Sprintf("%s / %.0f * %.0f", unixEpoch(args[0]), interval.Seconds(), interval.Seconds()), nil
+	case "__timeGroupAlias":
+		tg, err := m.evaluateMacro(timeRange, query, "__timeGroup", args)
+		if err == nil {
+			return tg + " AS \"time\"", nil
+		}
+		return "", err
+	case "__timeGroupCalendar":
+		if len(args) < 3 {
+			return "", fmt.Errorf("macro %v needs time column, interval and time zone", name)
+		}
+		interval, err := sqlcalendar.Parse(strings.Trim(args[1], `'"`), strings.Trim(args[2], `'"`))
+		if err != nil {
+			return "", err
+		}
+		if len(args) == 4 {
+			err := sqleng.SetupCalendarFillmode(query, interval, args[3])
+			if err != nil {
+				return "", err
+			}
+		}
+		return timeGroupCalendar(args[0], interval, timeRange), nil
+	case "__timeGroupCalendarAlias":
+		tg, err := m.evaluateMacro(timeRange, query, "__timeGroupCalendar", args)
+		if err == nil {
+			return tg + " AS \"time\"", nil
+		}
+		return "", err
+	case "__unixEpochFilter":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().Unix(), args[0], timeRange.To.UTC().Unix()), nil
+	case "__unixEpochNanoFilter":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().UnixNano(), args[0], timeRange.To.UTC().UnixNano()), nil
+	case "__unixEpochNanoFrom":
+		return fmt.Sprintf("%d", timeRange.From.UTC().UnixNano()), nil
+	case "__unixEpochNanoTo":
+		return fmt.Sprintf("%d", timeRange.To.UTC().UnixNano()), nil
+	case "__unixEpochGroup":
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
+		}
+		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'`))
+		if err != nil {
+			return "", fmt.Errorf("error parsing interval %v", args[1])
+		}
+		if len(args) == 3 {
+			err := sqleng.SetupFillmode(query, interval, args[2])
+			if err != nil {
+				return "", err
+			}
+		}
+		return fmt.Sprintf("CAST(%s AS INTEGER) / %.0f * %.0f", args[0], interval.Seconds(), interval.Seconds()), nil
+	case "__unixEpochGroupAlias":
+		tg, err := m.evaluateMacro(timeRange, query, "__unixEpochGroup", args)
+		if err == nil {
+			return tg + " AS \"time\"", nil
+		}
+		return "", err
+	default:
+		return "", fmt.Errorf("unknown macro %v", name)
+	}
+}
+
+// timeGroupCalendar returns the epoch seconds of the start of the calendar interval column is in.
+// SQLite has no time zone data, so the column is shifted to local time by the UTC offsets of the
+// time range, truncated with date modifiers, and shifted back.
+func timeGroupCalendar(column string, interval sqlcalendar.Interval, timeRange backend.TimeRange) string {
+	epoch := unixEpoch(column)
+	local := fmt.Sprintf("%s + %s", epoch, interval.UTCOffset(epoch, timeRange, false))
+
+	var start string
+	switch {
+	case interval.Unit == sqlcalendar.Week:
+		start = fmt.Sprintf("unixepoch(%s, 'unixepoch', 'start of day', '-6 days', 'weekday 1')", local)
+	case interval.Unit == sqlcalendar.Month && interval.Count == 1:
+		start = fmt.Sprintf("unixepoch(%s, 'unixepoch', 'start of month')", local)
+	case interval.Unit == sqlcalendar.Month:
+		start = fmt.Sprintf("unixepoch(%s, 'unixepoch', 'start of year', ((CAST(strftime('%%m', %s, 'unixepoch') AS INTEGER) - 1) / %d * %d) || ' months')", local, local, interval.Count, interval.Count)
+	case interval.Unit == sqlcalendar.Year:
+		start = fmt.Sprintf("unixepoch(%s, 'unixepoch', 'start of year')", local)
+	default:
+		start = fmt.Sprintf("unixepoch(%s, 'unixepoch', 'start of day')", local)
+	}
+
+	return fmt.Sprintf("(%s - %s)", start, interval.UTCOffset(start, timeRange, true))
+}
@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroEngine(t *testing.T) {
+	engine := &sqliteMacroEngine{
+		logger:    backend.NewLoggerWith("logger", "test"),
+		userError: "inspect Grafana server log for details",
+	}
+	query := &backend.DataQuery{}
+
+	from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+	to := from.Add(5 * time.Minute)
+	timeRange := backend.TimeRange{From: from, To: to}
+
+	t.Run("interpolate __time function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "select $__time(time_column)")
+		require.NoError(t, err)
+
+		require.Equal(t, "select unixepoch(time_column, 'auto') AS time_sec", sql)
+	})
+
+	t.Run("interpolate __timeGroup function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'5m')")
+		require.NoError(t, err)
+		sql2, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupAlias(time_column,'5m')")
+		require.NoError(t, err)
+
+		require.Equal(t, "GROUP BY unixepoch(time_column, 'auto') / 300 * 300", sql)
+		require.Equal(t, sql+" AS \"time\"", sql2)
+	})
+
+	t.Run("interpolate __timeGroup function with fill mode", func(t *testing.T) {
+		q := &backend.DataQuery{JSON: []byte(`{}`)}
+		_, err := engine.Interpolate(q, timeRange, "GROUP BY $__timeGroup(time_column,'5m', previous)")
+		require.NoError(t, err)
+
+		require.JSONEq(t, `{"fill":true,"fillInterval":300,"fillMode":"previous"}`, string(q.JSON))
+	})
+
+	t.Run("interpolate __timeGroupCalendar function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '1w', 'Asia/Kolkata')")
+		require.NoError(t, err)
+		sql2, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupCalendarAlias(time_column, '1w', 'Asia/Kolkata')")
+		require.NoError(t, err)
+
+		require.Equal(t, "GROUP BY (unixepoch(unixepoch(time_column, 'auto') + 19800, 'unixepoch', 'start of day', '-6 days', 'weekday 1') - 19800)", sql)
+		require.Equal(t, sql+" AS \"time\"", sql2)
+	})
+
+	t.Run("interpolate __timeGroupCalendar function with months and fill mode", func(t *testing.T) {
+		q := &backend.DataQuery{JSON: []byte(`{}`)}
+		sql, err := engine.Interpolate(q, timeRange, "GROUP BY $__timeGroupCalendar(time_column, '6M', 'UTC', NULL)")
+		require.NoError(t, err)
+
+		require.Equal(t, "GROUP BY (unixepoch(unixepoch(time_column, 'auto') + 0, 'unixepoch', 'start of year', ((CAST(strftime('%m', unixepoch(time_column, 'auto') + 0, 'unixepoch') AS INTEGER) - 1) / 6 * 6) || ' months') - 0)", sql)
+		require.JSONEq(t, `{"fill":true,"fillInterval":0,"fillMode":"null","fillCalendarInterval":"6M","fillTimezone":"UTC"}`, string(q.JSON))
+	})
+
+	t.Run("interpolate __timeFilter function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column)")
+		require.NoError(t, err)
+
+		require.Equal(t, fmt.Sprintf("WHERE unixepoch(time_column, 'auto') BETWEEN %d AND %d", from.Unix(), to.Unix()), sql)
+	})
+
+	t.Run("interpolate __timeFrom and __timeTo functions", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "select $__timeFrom(), $__timeTo()")
+		require.NoError(t, err)
+
+		require.Equal(t, fmt.Sprintf("select datetime(%d, 'unixepoch'), datetime(%d, 'unixepoch')", from.Unix(), to.Unix()), sql)
+	})
+
+	t.Run("interpolate __unixEpochGroup function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "SELECT $__unixEpochGroupAlias(time_column,'1m')")
+		require.NoError(t, err)
+
+		require.Equal(t, "SELECT CAST(time_column AS INTEGER) / 60 * 60 AS \"time\"", sql)
+	})
+
+	t.Run("interpolate __unixEpochFilter function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "WHERE $__unixEpochFilter(time_column)")
+		require.NoError(t, err)
+
+		require.Equal(t, fmt.Sprintf("WHERE time_column >= %d AND time_column <= %d", from.Unix(), to.Unix()), sql)
+	})
+
+	t.Run("rejects statements reaching outside of the database file", func(t *testing.T) {
+		for _, sql := range []string{
+			"ATTACH DATABASE '/etc/grafana/grafana.db' AS g",
+			"select 1; attach '/tmp/x.db' as x",
+			"VACUUM INTO '/tmp/copy.db'",
+			"vacuum",
+			"DETACH g",
+			"SELECT load_extension('/tmp/evil.so')",
+		} {
+			_, err := engine.Interpolate(query, timeRange, sql)
+			require.Error(t, err, sql)
+		}
+	})
+
+	t.Run("allows identifiers containing restricted words", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "SELECT attachment, vacuum_count FROM devices")
+		require.NoError(t, err)
+		require.Equal(t, "SELECT attachment, vacuum_count FROM devices", sql)
+	})
+}
@@ -0,0 +1,268 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+	_ "modernc.org/sqlite"
+
+	"github.com/grafana/grafana/pkg/tsdb/mysql/sqleng"
+)
+
+// allowedDirectoriesKey is the Grafana config key holding the comma separated directories
+// database files can be opened from. It is set from [sql_datasources] sqlite_allowed_directories.
+const allowedDirectoriesKey = "GF_SQL_SQLITE_ALLOWED_DIRECTORIES"
+
+// busyTimeout is how long a query waits for a database file locked by a writer.
+const busyTimeout = 5 * time.Second
+
+var (
+	errNoAllowedDirectories = errors.New("no directories are allowed for SQLite data sources, set sqlite_allowed_directories in the [sql_datasources] section of the configuration")
+	errNoDatabasePath       = errors.New("database file path is required")
+	errPathNotAllowed       = errors.New("database file is not inside an allowed directory")
+)
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+func NewInstanceSettings(logger log.Logger) datasource.InstanceFactoryFunc {
+	return func(ctx context.Context, settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+		cfg := backend.GrafanaConfigFromContext(ctx)
+		sqlCfg, err := cfg.SQL()
+		if err != nil {
+			return nil, err
+		}
+		jsonData := sqleng.JsonData{
+			MaxOpenConns:    sqlCfg.DefaultMaxOpenConns,
+			MaxIdleConns:    sqlCfg.DefaultMaxIdleConns,
+			ConnMaxLifetime: sqlCfg.DefaultMaxConnLifetimeSeconds,
+		}
+
+		err = json.Unmarshal(settings.JSONData, &jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("error reading settings: %w", err)
+		}
+
+		database := jsonData.Database
+		if database == "" {
+			database = settings.Database
+		}
+
+		path, err := resolveDatabasePath(splitDirectories(cfg.Get(allowedDirectoriesKey)), database)
+		if err != nil {
+			return nil, err
+		}
+
+		dsInfo := sqleng.DataSourceInfo{
+			JsonData:                jsonData,
+			Database:                path,
+			ID:                      settings.ID,
+			Updated:                 settings.Updated,
+			UID:                     settings.UID,
+			DecryptedSecureJSONData: settings.DecryptedSecureJSONData,
+		}
+
+		config := sqleng.DataPluginConfiguration{
+			DSInfo:            dsInfo,
+			TimeColumnNames:   []string{"time", "time_sec"},
+			MetricColumnTypes: []string{"TEXT", "VARCHAR", "CHAR", "NVARCHAR", "NCHAR", "CLOB"},
+			RowLimit:          sqlCfg.RowLimit,
+		}
+
+		userFacingDefaultError, err := cfg.UserFacingDefaultError()
+		if err != nil {
+			return nil, err
+		}
+
+		db, err := sql.Open("sqlite", connectionString(path))
+		if err != nil {
+			return nil, err
+		}
+
+		db.SetMaxOpenConns(config.DSInfo.JsonData.MaxOpenConns)
+		db.SetMaxIdleConns(config.DSInfo.JsonData.MaxIdleConns)
+		db.SetConnMaxLifetime(time.Duration(config.DSInfo.JsonData.ConnMaxLifetime) * time.Second)
+
+		return sqleng.NewQueryDataHandler(userFacingDefaultError, db, config, &sqliteQueryResultTransformer{}, newSqliteMacroEngine(logger, userFacingDefaultError), logger)
+	}
+}
+
+// connectionString opens the database file read-only. query_only additionally rejects
+// statements that would change the file, such as writes through a shared connection.
+func connectionString(path string) string {
+	q := url.Values{}
+	q.Set("mode", "ro")
+	q.Add("_pragma", "query_only(1)")
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	return "file:" + (&url.URL{Path: path}).EscapedPath() + "?" + q.Encode()
+}
+
+func splitDirectories(value string) []string {
+	var dirs []string
+	for _, dir := range strings.Split(value, ",") {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// resolveDatabasePath returns the real path of the database file after resolving symlinks,
+// as long as it is inside one of the allowed directories. Relative paths are resolved against
+// each allowed directory in turn.
+func resolveDatabasePath(allowedDirs []string, name string) (string, error) {
+	if len(allowedDirs) == 0 {
+		return "", errNoAllowedDirectories
+	}
+	if strings.TrimSpace(name) == "" {
+		return "", errNoDatabasePath
+	}
+
+	candidates := []string{name}
+	if !filepath.IsAbs(name) {
+		candidates = make([]string, 0, len(allowedDirs))
+		for _, dir := range allowedDirs {
+			candidates = append(candidates, filepath.Join(dir, name))
+		}
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		path, err := filepath.EvalSymlinks(candidate)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		path, err = filepath.Abs(path)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, dir := range allowedDirs {
+			if isInside(dir, path) {
+				return path, nil
+			}
+		}
+		lastErr = errPathNotAllowed
+	}
+	if errors.Is(lastErr, errPathNotAllowed) {
+		return "", lastErr
+	}
+	return "", fmt.Errorf("database file %q not found: %w", name, lastErr)
+}
+
+func isInside(dir, path string) bool {
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return false
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+type sqliteQueryResultTransformer struct{}
+
+func (t *sqliteQueryResultTransformer) TransformQueryError(_ log.Logger, err error) error {
+	return err
+}
+
+// GetConverterList returns converters for the column types commonly declared in SQLite tables.
+// SQLite values are dynamically typed, so values are scanned as strings and parsed based on
+// the declared type of their column.
+func (t *sqliteQueryResultTransformer) GetConverterList() []sqlutil.StringConverter {
+	converters := make([]sqlutil.StringConverter, 0)
+	for _, typeName := range []string{"INTEGER", "INT", "BIGINT", "SMALLINT", "TINYINT", "MEDIUMINT", "BOOLEAN"} {
+		converters = append(converters, stringConverter(typeName, data.FieldTypeNullableInt64, parseInt))
+	}
+	for _, typeName := range []string{"REAL", "DOUBLE", "FLOAT", "NUMERIC", "DECIMAL"} {
+		converters = append(converters, stringConverter(typeName, data.FieldTypeNullableFloat64, parseFloat))
+	}
+	for _, typeName := range []string{"DATETIME", "TIMESTAMP", "DATE"} {
+		converters = append(converters, stringConverter(typeName, data.FieldTypeNullableTime, parseTime))
+	}
+	return converters
+}
+
+func stringConverter(typeName string, fieldType data.FieldType, parse func(string) (any, error)) sqlutil.StringConverter {
+	return sqlutil.StringConverter{
+		Name:           "handle " + typeName,
+		InputScanKind:  reflect.Struct,
+		InputTypeName:  typeName,
+		ConversionFunc: func(in *string) (*string, error) { return in, nil },
+		Replacer: &sqlutil.StringFieldReplacer{
+			OutputFieldType: fieldType,
+			ReplaceFunc: func(in *string) (any, error) {
+				if in == nil {
+					return nil, nil
+				}
+				return parse(*in)
+			},
+		},
+	}
+}
+
+func parseInt(s string) (any, error) {
+	switch strings.ToLower(s) {
+	case "true":
+		s = "1"
+	case "false":
+		s = "0"
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func parseFloat(s string) (any, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// parseTime parses the text representations of time SQLite functions produce, and unix
+// timestamps stored in columns declared as a time.
+func parseTime(s string) (any, error) {
+	for _, layout := range timeLayouts {
+		if v, err := time.Parse(layout, s); err == nil {
+			return &v, nil
+		}
+	}
+	if epoch, err := strconv.ParseFloat(s, 64); err == nil {
+		v := time.UnixMilli(int64(epoch * 1000)).UTC()
+		return &v, nil
+	}
+	return nil, fmt.Errorf("unable to parse time %q", s)
+}
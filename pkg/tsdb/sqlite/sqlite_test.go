@@ -0,0 +1,164 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDatabasePath(t *testing.T) {
+	allowed := t.TempDir()
+	other := t.TempDir()
+
+	dbPath := filepath.Join(allowed, "devices.db")
+	require.NoError(t, os.WriteFile(dbPath, nil, 0600))
+	outsidePath := filepath.Join(other, "secret.db")
+	require.NoError(t, os.WriteFile(outsidePath, nil, 0600))
+	require.NoError(t, os.Symlink(outsidePath, filepath.Join(allowed, "link.db")))
+
+	expected, err := filepath.EvalSymlinks(dbPath)
+	require.NoError(t, err)
+
+	t.Run("absolute path inside an allowed directory", func(t *testing.T) {
+		path, err := resolveDatabasePath([]string{allowed}, dbPath)
+		require.NoError(t, err)
+		require.Equal(t, expected, path)
+	})
+
+	t.Run("relative path is resolved against the allowed directories", func(t *testing.T) {
+		path, err := resolveDatabasePath([]string{other, allowed}, "devices.db")
+		require.NoError(t, err)
+		require.Equal(t, expected, path)
+	})
+
+	t.Run("path outside of the allowed directories", func(t *testing.T) {
+		_, err := resolveDatabasePath([]string{allowed}, outsidePath)
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("relative path escaping the allowed directory", func(t *testing.T) {
+		_, err := resolveDatabasePath([]string{allowed}, filepath.Join("..", filepath.Base(other), "secret.db"))
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("symlink pointing outside of the allowed directories", func(t *testing.T) {
+		_, err := resolveDatabasePath([]string{allowed}, "link.db")
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := resolveDatabasePath([]string{allowed}, "missing.db")
+		require.Error(t, err)
+	})
+
+	t.Run("no allowed directories", func(t *testing.T) {
+		_, err := resolveDatabasePath(nil, dbPath)
+		require.ErrorIs(t, err, errNoAllowedDirectories)
+	})
+}
+
+func TestSQLite(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "metrics.db")
+
+	db, err := sql.Open("sqlite", dbPath)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE metrics (ts DATETIME, host TEXT, value REAL)`)
+	require.NoError(t, err)
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		_, err = db.Exec(`INSERT INTO metrics (ts, host, value) VALUES (?, ?, ?)`,
+			start.Add(time.Duration(i)*30*time.Second).Format("2006-01-02 15:04:05"), "a", float64(i))
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+
+	logger := backend.NewLoggerWith("logger", "sqlite.test")
+	exe := &Service{
+		im:     datasource.NewInstanceManager(NewInstanceSettings(logger)),
+		logger: logger,
+	}
+
+	ctx := backend.WithGrafanaConfig(context.Background(), backend.NewGrafanaCfg(map[string]string{
+		backend.SQLMaxOpenConnsDefault:           "2",
+		backend.SQLMaxIdleConnsDefault:           "2",
+		backend.SQLMaxConnLifetimeSecondsDefault: "14400",
+		backend.SQLRowLimit:                      "1000",
+		backend.UserFacingDefaultError:           "",
+		allowedDirectoriesKey:                    dir,
+	}))
+
+	jsonData, err := json.Marshal(map[string]any{"database": "metrics.db"})
+	require.NoError(t, err)
+	pluginCtx := backend.PluginContext{
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+			UID:      "sqlite-test",
+			Type:     "sqlite",
+			JSONData: jsonData,
+		},
+	}
+
+	runQuery := func(t *testing.T, model map[string]any) backend.DataResponse {
+		t.Helper()
+		raw, err := json.Marshal(model)
+		require.NoError(t, err)
+		resp, err := exe.QueryData(ctx, &backend.QueryDataRequest{
+			PluginContext: pluginCtx,
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				JSON:      raw,
+				TimeRange: backend.TimeRange{From: start, To: start.Add(2 * time.Minute)},
+			}},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	t.Run("time series grouped by interval", func(t *testing.T) {
+		res := runQuery(t, map[string]any{
+			"format": "time_series",
+			"rawSql": "SELECT $__timeGroupAlias(ts, '1m'), avg(value) AS value FROM metrics WHERE $__timeFilter(ts) GROUP BY 1 ORDER BY 1",
+		})
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+
+		frame := res.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, data.TimeSeriesTimeFieldName, frame.Fields[0].Name)
+		first, ok := frame.Fields[0].ConcreteAt(0)
+		require.True(t, ok)
+		require.Equal(t, start, first.(time.Time).UTC())
+		value, err := frame.Fields[1].FloatAt(0)
+		require.NoError(t, err)
+		require.Equal(t, 0.5, value)
+	})
+
+	t.Run("table with declared column types", func(t *testing.T) {
+		res := runQuery(t, map[string]any{
+			"format": "table",
+			"rawSql": "SELECT ts, host, value FROM metrics ORDER BY ts LIMIT 1",
+		})
+		require.NoError(t, res.Error)
+
+		frame := res.Frames[0]
+		require.Equal(t, data.FieldTypeNullableTime, frame.Fields[0].Type())
+		require.Equal(t, data.FieldTypeNullableFloat64, frame.Fields[2].Type())
+	})
+
+	t.Run("database is opened read-only", func(t *testing.T) {
+		res := runQuery(t, map[string]any{
+			"format": "table",
+			"rawSql": "DELETE FROM metrics",
+		})
+		require.Error(t, res.Error)
+	})
+}
@@ -0,0 +1,267 @@
+// Package sqlstream implements the poll streams shared by the SQL data sources. A poll stream runs a
+// regular query periodically and sends the rows that are newer than the rows already sent.
+package sqlstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// PathPoll is the prefix of the stream paths running a query periodically.
+const PathPoll = "poll/"
+
+const (
+	minPollInterval     = time.Second
+	defaultPollInterval = 10 * time.Second
+	defaultPollLookback = time.Hour
+)
+
+// QueryDataFunc runs the queries of a request, it is the QueryData method of the data source handler.
+type QueryDataFunc func(context.Context, *backend.QueryDataRequest) (*backend.QueryDataResponse, error)
+
+// CheckAccess returns an error unless the subscription is made on behalf of a user for a data source
+// instance. Grafana checks the user can query the data source before passing the subscription on.
+func CheckAccess(pCtx backend.PluginContext) error {
+	if pCtx.DataSourceInstanceSettings == nil {
+		return errors.New("stream subscriptions need a data source")
+	}
+	if pCtx.User == nil {
+		return errors.New("stream subscriptions need a user")
+	}
+	return nil
+}
+
+// Subscribe checks the access and the query of a poll stream subscription.
+func Subscribe(req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	if err := CheckAccess(req.PluginContext); err != nil {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusPermissionDenied,
+		}, err
+	}
+
+	if !strings.HasPrefix(req.Path, PathPoll) {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusNotFound,
+		}, fmt.Errorf("unsupported stream path %q", req.Path)
+	}
+	if err := ValidatePollQuery(req.Data); err != nil {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusNotFound,
+		}, err
+	}
+
+	return &backend.SubscribeStreamResponse{
+		Status: backend.SubscribeStreamStatusOK,
+	}, nil
+}
+
+// ValidatePollQuery returns an error when the query of a poll stream can not be run.
+func ValidatePollQuery(raw json.RawMessage) error {
+	var query pollQuery
+	if err := json.Unmarshal(raw, &query); err != nil {
+		return fmt.Errorf("error unmarshal query json: %w", err)
+	}
+	if query.RawSql == "" {
+		return errors.New("missing rawSql in poll query")
+	}
+	return nil
+}
+
+// pollQuery is the query model of a poll stream. It is a regular query along with how often to run it.
+type pollQuery struct {
+	RawSql string `json:"rawSql"`
+	// PollInterval is the number of seconds between two runs of the query
+	PollInterval float64 `json:"pollInterval"`
+	// TimeRange is the time range of the panel, in epoch milliseconds. The first run of the
+	// query covers it, later runs start at the most recent row sent.
+	TimeRange struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"timeRange"`
+}
+
+func (q pollQuery) interval() time.Duration {
+	interval := time.Duration(q.PollInterval * float64(time.Second))
+	if interval <= 0 {
+		return defaultPollInterval
+	}
+	return max(interval, minPollInterval)
+}
+
+func (q pollQuery) from(now time.Time) time.Time {
+	if ms, err := strconv.ParseInt(q.TimeRange.From, 10, 64); err == nil {
+		return time.UnixMilli(ms)
+	}
+	return now.Add(-defaultPollLookback)
+}
+
+// RunPoll runs the query of a poll stream every poll interval and sends the rows that are newer
+// than the rows already sent. The query needs a time column to tell new rows apart.
+func RunPoll(ctx context.Context, logger log.Logger, req *backend.RunStreamRequest, queryData QueryDataFunc, sender *backend.StreamSender) error {
+	var query pollQuery
+	if err := json.Unmarshal(req.Data, &query); err != nil {
+		return fmt.Errorf("error unmarshal query json: %w", err)
+	}
+
+	poller := &queryPoller{
+		queryData: queryData,
+		pCtx:      req.PluginContext,
+		query:     req.Data,
+		from:      query.from(time.Now()),
+	}
+
+	ticker := time.NewTicker(query.interval())
+	defer ticker.Stop()
+
+	for {
+		frame, err := poller.poll(ctx, time.Now())
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			logger.Warn("Failed to poll query", "path", req.Path, "error", err)
+		} else if frame != nil {
+			if err := poller.send(sender, frame); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Debug("Stop polling (context canceled)", "path", req.Path)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// queryPoller keeps track of the most recent row sent by a poll stream.
+type queryPoller struct {
+	queryData QueryDataFunc
+	pCtx      backend.PluginContext
+	query     json.RawMessage
+	// from is the start of the time range of the next run. Rows before it have been sent already,
+	// unless nothing has been sent yet.
+	from time.Time
+	// sentAtFrom holds the keys of the rows sent with the timestamp from. Rows inserted later with
+	// the same timestamp are still sent.
+	sentAtFrom map[string]bool
+	sent       bool
+	prev       data.FrameJSONCache
+}
+
+// poll runs the query and returns a frame holding the new rows, or nil when there are none.
+func (p *queryPoller) poll(ctx context.Context, now time.Time) (*data.Frame, error) {
+	resp, err := p.queryData(ctx, &backend.QueryDataRequest{
+		PluginContext: p.pCtx,
+		Queries: []backend.DataQuery{{
+			RefID:     "A",
+			JSON:      p.query,
+			TimeRange: backend.TimeRange{From: p.from, To: now},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := resp.Responses["A"]
+	if !ok {
+		return nil, nil
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if len(res.Frames) == 0 || res.Frames[0].Rows() == 0 {
+		return nil, nil
+	}
+
+	return p.newRows(res.Frames[0])
+}
+
+// newRows returns a frame holding the rows of frame that have not been sent yet, or nil when there are none.
+// Rows are identified by their timestamp and values, so rows sharing the timestamp of the most recent rows
+// sent are only skipped when they have been sent before.
+func (p *queryPoller) newRows(frame *data.Frame) (*data.Frame, error) {
+	timeIndex := -1
+	for i, field := range frame.Fields {
+		if t := field.Type(); t == data.FieldTypeTime || t == data.FieldTypeNullableTime {
+			timeIndex = i
+			break
+		}
+	}
+	if timeIndex == -1 {
+		return nil, errors.New("poll queries need a time column to find new rows")
+	}
+
+	newRows := frame.EmptyCopy()
+	latest := p.from
+	latestKeys := p.sentAtFrom
+	if latestKeys == nil {
+		latestKeys = map[string]bool{}
+	}
+	for i := 0; i < frame.Rows(); i++ {
+		t, ok := frame.Fields[timeIndex].ConcreteAt(i)
+		if !ok {
+			continue
+		}
+		ts := t.(time.Time)
+		key := rowKey(frame, i)
+		if p.sent && (ts.Before(p.from) || (ts.Equal(p.from) && p.sentAtFrom[key])) {
+			continue
+		}
+		newRows.AppendRow(frame.RowCopy(i)...)
+		switch {
+		case ts.After(latest):
+			latest = ts
+			latestKeys = map[string]bool{key: true}
+		case ts.Equal(latest):
+			latestKeys[key] = true
+		}
+	}
+
+	p.from = latest
+	p.sentAtFrom = latestKeys
+	p.sent = true
+	if newRows.Rows() == 0 {
+		return nil, nil
+	}
+	return newRows, nil
+}
+
+// rowKey returns the values of a row joined in a string.
+func rowKey(frame *data.Frame, i int) string {
+	var sb strings.Builder
+	for _, field := range frame.Fields {
+		if v, ok := field.ConcreteAt(i); ok {
+			fmt.Fprintf(&sb, "%v", v)
+		} else {
+			sb.WriteString("null")
+		}
+		sb.WriteByte(0)
+	}
+	return sb.String()
+}
+
+// send sends the full frame when its schema changed, and the data only otherwise.
+func (p *queryPoller) send(sender *backend.StreamSender, frame *data.Frame) error {
+	next, err := data.FrameToJSONCache(frame)
+	if err != nil {
+		return err
+	}
+	if next.SameSchema(&p.prev) {
+		err = sender.SendBytes(next.Bytes(data.IncludeDataOnly))
+	} else {
+		err = sender.SendFrame(frame, data.IncludeAll)
+	}
+	p.prev = next
+	return err
+}
@@ -0,0 +1,279 @@
+package sqlstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribe(t *testing.T) {
+	pCtx := backend.PluginContext{
+		User:                       &backend.User{Login: "viewer", Role: "Viewer"},
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "sql"},
+	}
+
+	tests := []struct {
+		name   string
+		pCtx   backend.PluginContext
+		path   string
+		data   string
+		status backend.SubscribeStreamStatus
+	}{
+		{
+			name:   "poll query",
+			pCtx:   pCtx,
+			path:   "poll/abc",
+			data:   `{"rawSql": "SELECT 1"}`,
+			status: backend.SubscribeStreamStatusOK,
+		},
+		{
+			name:   "poll query without sql",
+			pCtx:   pCtx,
+			path:   "poll/abc",
+			data:   `{}`,
+			status: backend.SubscribeStreamStatusNotFound,
+		},
+		{
+			name:   "invalid query",
+			pCtx:   pCtx,
+			path:   "poll/abc",
+			data:   `{`,
+			status: backend.SubscribeStreamStatusNotFound,
+		},
+		{
+			name:   "unsupported path",
+			pCtx:   pCtx,
+			path:   "other/abc",
+			data:   `{"rawSql": "SELECT 1"}`,
+			status: backend.SubscribeStreamStatusNotFound,
+		},
+		{
+			name:   "without user",
+			pCtx:   backend.PluginContext{DataSourceInstanceSettings: pCtx.DataSourceInstanceSettings},
+			path:   "poll/abc",
+			data:   `{"rawSql": "SELECT 1"}`,
+			status: backend.SubscribeStreamStatusPermissionDenied,
+		},
+		{
+			name:   "without data source",
+			pCtx:   backend.PluginContext{User: pCtx.User},
+			path:   "poll/abc",
+			data:   `{"rawSql": "SELECT 1"}`,
+			status: backend.SubscribeStreamStatusPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsp, err := Subscribe(&backend.SubscribeStreamRequest{PluginContext: tt.pCtx, Path: tt.path, Data: []byte(tt.data)})
+			require.Equal(t, tt.status, rsp.Status)
+			if tt.status == backend.SubscribeStreamStatusOK {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestQueryPoller(t *testing.T) {
+	var rows []pollRow
+	var ranges []backend.TimeRange
+	queryData := func(_ context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+		ranges = append(ranges, req.Queries[0].TimeRange)
+		resp := backend.NewQueryDataResponse()
+		resp.Responses["A"] = backend.DataResponse{Frames: data.Frames{pollFrame(rows)}}
+		return resp, nil
+	}
+	at := func(sec int64) *time.Time {
+		ts := time.Unix(sec, 0)
+		return &ts
+	}
+
+	poller := &queryPoller{queryData: queryData, from: time.Unix(0, 0)}
+	ctx := context.Background()
+
+	rows = []pollRow{{at(1700000000), "a"}, {at(1700000060), "b"}}
+	frame, err := poller.poll(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, frameHosts(frame))
+
+	frame, err = poller.poll(ctx, time.Now())
+	require.NoError(t, err)
+	require.Nil(t, frame)
+	require.True(t, time.Unix(1700000060, 0).Equal(ranges[1].From))
+
+	rows = append(rows, pollRow{at(1700000120), "c"})
+	frame, err = poller.poll(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, []string{"c"}, frameHosts(frame))
+
+	t.Run("query errors", func(t *testing.T) {
+		poller := &queryPoller{
+			queryData: func(context.Context, *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				resp := backend.NewQueryDataResponse()
+				resp.Responses["A"] = backend.ErrDataResponse(backend.StatusBadRequest, "syntax error")
+				return resp, nil
+			},
+			from: time.Unix(0, 0),
+		}
+
+		_, err := poller.poll(ctx, time.Now())
+		require.Error(t, err)
+	})
+
+	t.Run("handler errors", func(t *testing.T) {
+		poller := &queryPoller{
+			queryData: func(context.Context, *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				return nil, errors.New("connection refused")
+			},
+			from: time.Unix(0, 0),
+		}
+
+		_, err := poller.poll(ctx, time.Now())
+		require.Error(t, err)
+	})
+}
+
+func TestPollQueryInterval(t *testing.T) {
+	require.Equal(t, defaultPollInterval, pollQuery{}.interval())
+	require.Equal(t, minPollInterval, pollQuery{PollInterval: 0.1}.interval())
+	require.Equal(t, 30*time.Second, pollQuery{PollInterval: 30}.interval())
+}
+
+func TestQueryPollerNewRows(t *testing.T) {
+	t0 := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		ts := t0.Add(time.Duration(minutes) * time.Minute)
+		return &ts
+	}
+
+	tests := []struct {
+		name  string
+		polls [][]pollRow
+		sent  [][]string
+	}{
+		{
+			name:  "first poll sends all rows",
+			polls: [][]pollRow{{{at(-1), "a"}, {at(0), "b"}}},
+			sent:  [][]string{{"a", "b"}},
+		},
+		{
+			name:  "rows already sent are skipped",
+			polls: [][]pollRow{{{at(1), "a"}, {at(2), "b"}}, {{at(1), "a"}, {at(2), "b"}}},
+			sent:  [][]string{{"a", "b"}, nil},
+		},
+		{
+			name: "new rows with the timestamp of the latest row sent are sent",
+			polls: [][]pollRow{
+				{{at(1), "a"}},
+				{{at(1), "a"}, {at(1), "b"}},
+				{{at(1), "a"}, {at(1), "b"}, {at(2), "c"}},
+			},
+			sent: [][]string{{"a"}, {"b"}, {"c"}},
+		},
+		{
+			name:  "rows older than the latest row sent are skipped",
+			polls: [][]pollRow{{{at(2), "a"}}, {{at(1), "b"}, {at(2), "a"}, {at(3), "c"}}},
+			sent:  [][]string{{"a"}, {"c"}},
+		},
+		{
+			name:  "rows without time are skipped",
+			polls: [][]pollRow{{{nil, "a"}, {at(1), "b"}}},
+			sent:  [][]string{{"b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poller := &queryPoller{from: t0}
+			for i, rows := range tt.polls {
+				frame, err := poller.newRows(pollFrame(rows))
+				require.NoError(t, err)
+				require.Equal(t, tt.sent[i], frameHosts(frame), "poll %d", i)
+			}
+		})
+	}
+
+	t.Run("frames without a time column", func(t *testing.T) {
+		poller := &queryPoller{from: t0}
+		_, err := poller.newRows(data.NewFrame("A", data.NewField("value", nil, []float64{1})))
+		require.Error(t, err)
+	})
+}
+
+func TestQueryPollerSend(t *testing.T) {
+	packets := &fakePacketSender{}
+	sender := backend.NewStreamSender(packets)
+	poller := &queryPoller{}
+	ts := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	frame := pollFrame([]pollRow{{&ts, "a"}})
+	require.NoError(t, poller.send(sender, frame))
+	require.NoError(t, poller.send(sender, frame))
+	other := data.NewFrame("A", data.NewField("time", nil, []*time.Time{&ts}), data.NewField("value", nil, []float64{1}))
+	require.NoError(t, poller.send(sender, other))
+
+	require.Len(t, packets.packets, 3)
+	for i, withSchema := range []bool{true, false, true} {
+		var msg map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(packets.packets[i].Data, &msg))
+		_, ok := msg["schema"]
+		require.Equal(t, withSchema, ok, "packet %d", i)
+		require.Contains(t, msg, "data")
+	}
+}
+
+func TestPollQueryFrom(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	q := pollQuery{}
+	q.TimeRange.From = "1709283600000"
+	require.True(t, time.UnixMilli(1709283600000).Equal(q.from(now)))
+
+	q.TimeRange.From = "now-1h"
+	require.Equal(t, now.Add(-defaultPollLookback), q.from(now))
+}
+
+type pollRow struct {
+	time *time.Time
+	host string
+}
+
+func pollFrame(rows []pollRow) *data.Frame {
+	times := make([]*time.Time, len(rows))
+	hosts := make([]*string, len(rows))
+	for i, row := range rows {
+		host := row.host
+		times[i] = row.time
+		hosts[i] = &host
+	}
+	return data.NewFrame("A", data.NewField("time", nil, times), data.NewField("host", nil, hosts))
+}
+
+func frameHosts(frame *data.Frame) []string {
+	if frame == nil {
+		return nil
+	}
+	var hosts []string
+	for i := 0; i < frame.Rows(); i++ {
+		host, _ := frame.Fields[1].ConcreteAt(i)
+		hosts = append(hosts, host.(string))
+	}
+	return hosts
+}
+
+type fakePacketSender struct {
+	packets []*backend.StreamPacket
+}
+
+func (s *fakePacketSender) Send(packet *backend.StreamPacket) error {
+	s.packets = append(s.packets, packet)
+	return nil
+}
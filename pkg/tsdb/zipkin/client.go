@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
@@ -113,6 +115,90 @@ func (z *ZipkinClient) Traces(serviceName string, spanName string) ([][]model.Sp
 	return traces, err
 }
 
+// TracesSearchParams filters the traces returned by SearchTraces. Zero values are left out of the request.
+type TracesSearchParams struct {
+	ServiceName string
+	SpanName    string
+	// AnnotationQuery matches span annotations and tags, like "error and http.method=GET"
+	AnnotationQuery string
+	MinDuration     time.Duration
+	MaxDuration     time.Duration
+	// End and Lookback select the traces that started within Lookback before End
+	End      time.Time
+	Lookback time.Duration
+	Limit    int
+}
+
+func (p TracesSearchParams) queryParams() map[string]string {
+	params := map[string]string{}
+	if p.ServiceName != "" {
+		params["serviceName"] = p.ServiceName
+	}
+	if p.SpanName != "" {
+		params["spanName"] = p.SpanName
+	}
+	if p.AnnotationQuery != "" {
+		params["annotationQuery"] = p.AnnotationQuery
+	}
+	if p.MinDuration > 0 {
+		params["minDuration"] = strconv.FormatInt(p.MinDuration.Microseconds(), 10)
+	}
+	if p.MaxDuration > 0 {
+		params["maxDuration"] = strconv.FormatInt(p.MaxDuration.Microseconds(), 10)
+	}
+	if !p.End.IsZero() {
+		params["endTs"] = strconv.FormatInt(p.End.UnixMilli(), 10)
+	}
+	if p.Lookback > 0 {
+		params["lookback"] = strconv.FormatInt(p.Lookback.Milliseconds(), 10)
+	}
+	if p.Limit > 0 {
+		params["limit"] = strconv.Itoa(p.Limit)
+	}
+	return params
+}
+
+// SearchTraces returns list of traces matching the given search parameters
+// https://zipkin.io/zipkin-api/#/default/get_traces
+func (z *ZipkinClient) SearchTraces(params TracesSearchParams) ([][]model.SpanModel, error) {
+	traces := [][]model.SpanModel{}
+	if params.MinDuration > 0 && params.MaxDuration > 0 && params.MinDuration > params.MaxDuration {
+		return traces, backend.DownstreamError(errors.New("minDuration is greater than maxDuration"))
+	}
+
+	tracesUrl, err := createZipkinURL(z.url, "/api/v2/traces", params.queryParams())
+	if err != nil {
+		return traces, backend.DownstreamError(fmt.Errorf("failed to compose url: %w", err))
+	}
+
+	res, err := z.httpClient.Get(tracesUrl)
+	if err != nil {
+		if backend.IsDownstreamHTTPError(err) {
+			return traces, backend.DownstreamError(err)
+		}
+		return traces, err
+	}
+
+	defer func() {
+		if err = res.Body.Close(); err != nil {
+			z.logger.Error("Failed to close response body", "error", err)
+		}
+	}()
+
+	if res.StatusCode/100 != 2 {
+		err := fmt.Errorf("request failed: %s", res.Status)
+		if backend.ErrorSourceFromHTTPStatus(res.StatusCode) == backend.ErrorSourceDownstream {
+			return traces, backend.DownstreamError(err)
+		}
+		return traces, err
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&traces); err != nil {
+		return traces, err
+	}
+	return traces, err
+}
+
 // Trace returns trace for the given traceId
 // https://zipkin.io/zipkin-api/#/default/get_trace__traceId_
 func (z *ZipkinClient) Trace(traceId string) ([]model.SpanModel, error) {
@@ -156,6 +242,55 @@ func (z *ZipkinClient) Trace(traceId string) ([]model.SpanModel, error) {
 	return trace, err
 }
 
+// DependencyLink is the number of calls from one service to another
+type DependencyLink struct {
+	Parent     string `json:"parent"`
+	Child      string `json:"child"`
+	CallCount  int64  `json:"callCount"`
+	ErrorCount int64  `json:"errorCount"`
+}
+
+// Dependencies returns the calls between services within lookback before end
+// https://zipkin.io/zipkin-api/#/default/get_dependencies
+func (z *ZipkinClient) Dependencies(end time.Time, lookback time.Duration) ([]DependencyLink, error) {
+	dependencies := []DependencyLink{}
+	params := map[string]string{"endTs": strconv.FormatInt(end.UnixMilli(), 10)}
+	if lookback > 0 {
+		params["lookback"] = strconv.FormatInt(lookback.Milliseconds(), 10)
+	}
+	dependenciesUrl, err := createZipkinURL(z.url, "/api/v2/dependencies", params)
+	if err != nil {
+		return dependencies, backend.DownstreamError(fmt.Errorf("failed to compose url: %w", err))
+	}
+
+	res, err := z.httpClient.Get(dependenciesUrl)
+	if err != nil {
+		if backend.IsDownstreamHTTPError(err) {
+			return dependencies, backend.DownstreamError(err)
+		}
+		return dependencies, err
+	}
+
+	defer func() {
+		if err = res.Body.Close(); err != nil {
+			z.logger.Error("Failed to close response body", "error", err)
+		}
+	}()
+
+	if res.StatusCode/100 != 2 {
+		err := fmt.Errorf("request failed: %s", res.Status)
+		if backend.ErrorSourceFromHTTPStatus(res.StatusCode) == backend.ErrorSourceDownstream {
+			return dependencies, backend.DownstreamError(err)
+		}
+		return dependencies, err
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&dependencies); err != nil {
+		return dependencies, err
+	}
+	return dependencies, err
+}
+
 func createZipkinURL(baseURL string, path string, params map[string]string) (string, error) {
 	// Parse the base URL
 	finalUrl, err := url.Parse(baseURL)
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
@@ -237,6 +238,90 @@ func TestZipkinClient_Traces(t *testing.T) {
 	}
 }
 
+func TestZipkinClient_SearchTraces(t *testing.T) {
+	t.Run("sends the search parameters", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v2/traces", r.URL.Path)
+			assert.Equal(t, url.Values{
+				"serviceName":     {"frontend"},
+				"annotationQuery": {"error and http.method=GET"},
+				"minDuration":     {"100000"},
+				"maxDuration":     {"2000000"},
+				"endTs":           {"1700000000000"},
+				"lookback":        {"3600000"},
+				"limit":           {"5"},
+			}, r.URL.Query())
+			_, _ = w.Write([]byte(`[[{"traceId":"00000000000004d2","id":"0000000000000001","name":"get"}]]`))
+		}))
+		defer server.Close()
+
+		client, _ := New(server.URL, server.Client(), log.New())
+		traces, err := client.SearchTraces(TracesSearchParams{
+			ServiceName:     "frontend",
+			AnnotationQuery: "error and http.method=GET",
+			MinDuration:     100 * time.Millisecond,
+			MaxDuration:     2 * time.Second,
+			End:             time.UnixMilli(1700000000000),
+			Lookback:        time.Hour,
+			Limit:           5,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, traces, 1)
+		assert.Equal(t, "get", traces[0][0].Name)
+	})
+
+	t.Run("non-200 response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		client, _ := New(server.URL, server.Client(), log.New())
+		traces, err := client.SearchTraces(TracesSearchParams{})
+		assert.Error(t, err)
+		assert.True(t, backend.IsDownstreamError(err))
+		assert.Empty(t, traces)
+	})
+
+	t.Run("min duration greater than max duration", func(t *testing.T) {
+		client, _ := New("http://localhost:9411", http.DefaultClient, log.New())
+		_, err := client.SearchTraces(TracesSearchParams{MinDuration: time.Second, MaxDuration: time.Millisecond})
+		assert.Error(t, err)
+	})
+}
+
+func TestZipkinClient_Dependencies(t *testing.T) {
+	t.Run("sends the time range", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v2/dependencies", r.URL.Path)
+			assert.Equal(t, url.Values{
+				"endTs":    {"1700000000000"},
+				"lookback": {"3600000"},
+			}, r.URL.Query())
+			_, _ = w.Write([]byte(`[{"parent":"frontend","child":"backend","callCount":10,"errorCount":2}]`))
+		}))
+		defer server.Close()
+
+		client, _ := New(server.URL, server.Client(), log.New())
+		links, err := client.Dependencies(time.UnixMilli(1700000000000), time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, []DependencyLink{{Parent: "frontend", Child: "backend", CallCount: 10, ErrorCount: 2}}, links)
+	})
+
+	t.Run("non-200 response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		client, _ := New(server.URL, server.Client(), log.New())
+		links, err := client.Dependencies(time.UnixMilli(1700000000000), time.Hour)
+		assert.Error(t, err)
+		assert.True(t, backend.IsDownstreamError(err))
+		assert.Empty(t, links)
+	})
+}
+
 func TestZipkinClient_Trace(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -0,0 +1,150 @@
+package zipkin
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/openzipkin/zipkin-go/model"
+)
+
+// Status of a span in a trace comparison, relative to the first trace.
+const (
+	spanStatusMatched = "matched"
+	spanStatusRemoved = "removed"
+	spanStatusAdded   = "added"
+)
+
+func compareQuery(dsInfo *datasourceInfo, query zipkinQuery, q backend.DataQuery) backend.DataResponse {
+	traceA, traceB := strings.TrimSpace(query.Query), strings.TrimSpace(query.CompareTraceID)
+	if traceA == "" || traceB == "" {
+		return errorResponse(backend.DownstreamError(errors.New("compare queries need the IDs of the two traces to compare")))
+	}
+
+	spansA, err := dsInfo.ZipkinClient.Trace(traceA)
+	if err != nil {
+		return errorResponse(err)
+	}
+	spansB, err := dsInfo.ZipkinClient.Trace(traceB)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return backend.DataResponse{
+		Frames: []*data.Frame{compareTraces(spansA, spansB, q.RefID)},
+	}
+}
+
+// compareTraces aligns the spans of two traces and returns a table with a row for each span,
+// holding its duration in both traces and how much it changed. Spans are aligned by the
+// services and names of the spans on the path from the root to them, so repeated spans are
+// matched in the order they started.
+func compareTraces(traceA []model.SpanModel, traceB []model.SpanModel, refId string) *data.Frame {
+	frame := data.NewFrame(refId,
+		data.NewField("serviceName", nil, []string{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Service"}),
+		data.NewField("operationName", nil, []string{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Name"}),
+		data.NewField("depth", nil, []int64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Depth"}),
+		data.NewField("durationA", nil, []*float64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Duration A", Unit: "ms"}),
+		data.NewField("durationB", nil, []*float64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Duration B", Unit: "ms"}),
+		data.NewField("delta", nil, []*float64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Delta", Unit: "ms"}),
+		data.NewField("deltaPercent", nil, []*float64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Delta %", Unit: "percent"}),
+		data.NewField("status", nil, []string{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Status"}),
+	)
+	frame.Meta = &data.FrameMeta{
+		PreferredVisualization: data.VisTypeTable,
+	}
+
+	alignedA := alignSpans(traceA)
+	alignedB := alignSpans(traceB)
+	byKeyB := make(map[string]alignedSpan, len(alignedB))
+	for _, s := range alignedB {
+		byKeyB[s.key] = s
+	}
+
+	matched := map[string]bool{}
+	for _, a := range alignedA {
+		durationA := spanDurationMs(a.span)
+		b, ok := byKeyB[a.key]
+		if !ok {
+			frame.AppendRow(getServiceName(a.span), a.span.Name, int64(a.depth), &durationA, nil, nil, nil, spanStatusRemoved)
+			continue
+		}
+		matched[a.key] = true
+
+		durationB := spanDurationMs(b.span)
+		delta := durationB - durationA
+		var deltaPercent *float64
+		if durationA != 0 {
+			p := delta / durationA * 100
+			deltaPercent = &p
+		}
+		frame.AppendRow(getServiceName(a.span), a.span.Name, int64(a.depth), &durationA, &durationB, &delta, deltaPercent, spanStatusMatched)
+	}
+
+	for _, b := range alignedB {
+		if matched[b.key] {
+			continue
+		}
+		durationB := spanDurationMs(b.span)
+		frame.AppendRow(getServiceName(b.span), b.span.Name, int64(b.depth), nil, &durationB, nil, nil, spanStatusAdded)
+	}
+
+	return frame
+}
+
+type alignedSpan struct {
+	key   string
+	depth int
+	span  model.SpanModel
+}
+
+// alignSpans returns the spans of trace in the order they started, each with a key identifying
+// it across traces.
+func alignSpans(trace []model.SpanModel) []alignedSpan {
+	byID := make(map[model.ID]model.SpanModel, len(trace))
+	for _, span := range trace {
+		byID[span.ID] = span
+	}
+
+	spans := make([]model.SpanModel, len(trace))
+	copy(spans, trace)
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].Timestamp.Before(spans[j].Timestamp)
+	})
+
+	occurrences := map[string]int{}
+	aligned := make([]alignedSpan, 0, len(spans))
+	for _, span := range spans {
+		path := []string{spanPathElement(span)}
+		parentID := span.ParentID
+		// a trace with a cycle can't have a path longer than its number of spans
+		for parentID != nil && len(path) <= len(trace) {
+			parent, ok := byID[*parentID]
+			if !ok {
+				break
+			}
+			path = append(path, spanPathElement(parent))
+			parentID = parent.ParentID
+		}
+
+		key := strings.Join(path, " < ")
+		aligned = append(aligned, alignedSpan{
+			key:   fmt.Sprintf("%s #%d", key, occurrences[key]),
+			depth: len(path) - 1,
+			span:  span,
+		})
+		occurrences[key]++
+	}
+	return aligned
+}
+
+func spanPathElement(span model.SpanModel) string {
+	return getServiceName(span) + ":" + span.Name
+}
+
+func spanDurationMs(span model.SpanModel) float64 {
+	return float64(span.Duration.Microseconds()) / 1000
+}
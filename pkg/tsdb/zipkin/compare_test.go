@@ -0,0 +1,61 @@
+package zipkin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareTraces(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	span := func(id model.ID, parent *model.ID, service string, name string, offset time.Duration, duration time.Duration) model.SpanModel {
+		return model.SpanModel{
+			SpanContext:   model.SpanContext{TraceID: model.TraceID{Low: 1}, ID: id, ParentID: parent},
+			Name:          name,
+			Timestamp:     start.Add(offset),
+			Duration:      duration,
+			LocalEndpoint: &model.Endpoint{ServiceName: service},
+		}
+	}
+	rootA, rootB := model.ID(1), model.ID(10)
+
+	traceA := []model.SpanModel{
+		span(rootA, nil, "frontend", "get /api", 0, 100*time.Millisecond),
+		span(2, &rootA, "db", "query", 10*time.Millisecond, 20*time.Millisecond),
+		span(3, &rootA, "db", "query", 40*time.Millisecond, 30*time.Millisecond),
+		span(4, &rootA, "cache", "get", 5*time.Millisecond, time.Millisecond),
+	}
+	// span IDs differ between traces, and spans aren't in the order they started
+	traceB := []model.SpanModel{
+		span(12, &rootB, "db", "query", 60*time.Millisecond, 45*time.Millisecond),
+		span(rootB, nil, "frontend", "get /api", 0, 150*time.Millisecond),
+		span(11, &rootB, "db", "query", 10*time.Millisecond, 20*time.Millisecond),
+		span(13, &rootB, "auth", "check", 2*time.Millisecond, 5*time.Millisecond),
+	}
+
+	frame := compareTraces(traceA, traceB, "A")
+	require.Equal(t, 5, frame.Rows())
+
+	f := func(v float64) *float64 { return &v }
+	rows := [][]any{
+		{"frontend", "get /api", int64(0), f(100), f(150), f(50), f(50), spanStatusMatched},
+		{"cache", "get", int64(1), f(1), (*float64)(nil), (*float64)(nil), (*float64)(nil), spanStatusRemoved},
+		{"db", "query", int64(1), f(20), f(20), f(0), f(0), spanStatusMatched},
+		{"db", "query", int64(1), f(30), f(45), f(15), f(50), spanStatusMatched},
+		{"auth", "check", int64(1), (*float64)(nil), f(5), (*float64)(nil), (*float64)(nil), spanStatusAdded},
+	}
+	for i, row := range rows {
+		require.Equal(t, row, frame.RowCopy(i), "row %d", i)
+	}
+}
+
+func TestAlignSpansWithParentCycle(t *testing.T) {
+	a, b := model.ID(1), model.ID(2)
+	aligned := alignSpans([]model.SpanModel{
+		{SpanContext: model.SpanContext{ID: a, ParentID: &b}, Name: "a"},
+		{SpanContext: model.SpanContext{ID: b, ParentID: &a}, Name: "b"},
+	})
+	require.Len(t, aligned, 2)
+}
@@ -0,0 +1,104 @@
+package zipkin
+
+import (
+	"sort"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+func dependencyGraphQuery(dsInfo *datasourceInfo, q backend.DataQuery) backend.DataResponse {
+	links, err := dsInfo.ZipkinClient.Dependencies(q.TimeRange.To, q.TimeRange.To.Sub(q.TimeRange.From))
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return backend.DataResponse{
+		Frames: transformDependencies(links, q.RefID),
+	}
+}
+
+type serviceCalls struct {
+	calls  int64
+	errors int64
+}
+
+// transformDependencies returns the nodes and edges frames of a node graph. Each service is a
+// node showing the requests it received and how many of them failed, each link is an edge
+// showing the calls between two services.
+func transformDependencies(links []DependencyLink, refId string) []*data.Frame {
+	nodes := data.NewFrame(refId+"_nodes",
+		data.NewField("id", nil, []string{}),
+		data.NewField("title", nil, []string{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Service"}),
+		data.NewField("mainstat", nil, []int64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Requests"}),
+		data.NewField("secondarystat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Error rate", Unit: "percent"}),
+		data.NewField("arc__success", nil, []float64{}).SetConfig(&data.FieldConfig{
+			DisplayNameFromDS: "Success",
+			Color:             map[string]interface{}{"mode": "fixed", "fixedColor": "green"},
+		}),
+		data.NewField("arc__errors", nil, []float64{}).SetConfig(&data.FieldConfig{
+			DisplayNameFromDS: "Errors",
+			Color:             map[string]interface{}{"mode": "fixed", "fixedColor": "red"},
+		}),
+	)
+	nodes.Meta = &data.FrameMeta{
+		PreferredVisualization: "nodeGraph",
+	}
+
+	edges := data.NewFrame(refId+"_edges",
+		data.NewField("id", nil, []string{}),
+		data.NewField("source", nil, []string{}),
+		data.NewField("target", nil, []string{}),
+		data.NewField("mainstat", nil, []int64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Calls"}),
+		data.NewField("secondarystat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Error rate", Unit: "percent"}),
+	)
+	edges.Meta = &data.FrameMeta{
+		PreferredVisualization: "nodeGraph",
+	}
+
+	services := map[string]*serviceCalls{}
+	addService := func(name string) *serviceCalls {
+		s, ok := services[name]
+		if !ok {
+			s = &serviceCalls{}
+			services[name] = s
+		}
+		return s
+	}
+
+	for _, link := range links {
+		addService(link.Parent)
+		child := addService(link.Child)
+		child.calls += link.CallCount
+		child.errors += link.ErrorCount
+
+		edges.AppendRow(link.Parent+"--"+link.Child, link.Parent, link.Child, link.CallCount, errorRate(link.CallCount, link.ErrorCount))
+	}
+
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := services[name]
+		// services nobody calls, like the frontend, have no requests to fail
+		success, failed := 1.0, 0.0
+		if s.calls > 0 {
+			success = float64(s.calls-s.errors) / float64(s.calls)
+			failed = float64(s.errors) / float64(s.calls)
+		}
+		nodes.AppendRow(name, name, s.calls, errorRate(s.calls, s.errors), success, failed)
+	}
+
+	return []*data.Frame{nodes, edges}
+}
+
+// errorRate returns the percentage of calls that failed.
+func errorRate(calls int64, errors int64) float64 {
+	if calls == 0 {
+		return 0
+	}
+	return float64(errors) / float64(calls) * 100
+}
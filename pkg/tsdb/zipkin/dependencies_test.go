@@ -0,0 +1,37 @@
+package zipkin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformDependencies(t *testing.T) {
+	frames := transformDependencies([]DependencyLink{
+		{Parent: "frontend", Child: "backend", CallCount: 8, ErrorCount: 2},
+		{Parent: "scheduler", Child: "backend", CallCount: 2},
+		{Parent: "backend", Child: "db", CallCount: 20, ErrorCount: 1},
+	}, "A")
+	require.Len(t, frames, 2)
+	nodes, edges := frames[0], frames[1]
+
+	require.Equal(t, "A_nodes", nodes.Name)
+	require.Equal(t, 4, nodes.Rows())
+	require.Equal(t, []any{"backend", "backend", int64(10), 20.0, 0.8, 0.2}, nodes.RowCopy(0))
+	require.Equal(t, []any{"db", "db", int64(20), 5.0, 0.95, 0.05}, nodes.RowCopy(1))
+	require.Equal(t, []any{"frontend", "frontend", int64(0), 0.0, 1.0, 0.0}, nodes.RowCopy(2))
+	require.Equal(t, "scheduler", nodes.Fields[0].At(3))
+
+	require.Equal(t, "A_edges", edges.Name)
+	require.Equal(t, 3, edges.Rows())
+	require.Equal(t, []any{"frontend--backend", "frontend", "backend", int64(8), 25.0}, edges.RowCopy(0))
+	require.Equal(t, []any{"scheduler--backend", "scheduler", "backend", int64(2), 0.0}, edges.RowCopy(1))
+}
+
+func TestTransformDependenciesEmpty(t *testing.T) {
+	frames := transformDependencies(nil, "A")
+	require.Len(t, frames, 2)
+	require.Equal(t, 0, frames[0].Rows())
+	require.Equal(t, 0, frames[1].Rows())
+	require.Equal(t, "nodeGraph", string(frames[0].Meta.PreferredVisualization))
+}
@@ -17,14 +17,7 @@ func queryData(ctx context.Context, dsInfo *datasourceInfo, req *backend.QueryDa
 	for _, q := range req.Queries {
 		query, err := loadQuery(q)
 		if err != nil {
-			es := backend.ErrorSourcePlugin
-			if backend.IsDownstreamError(err) {
-				es = backend.ErrorSourceDownstream
-			}
-			response.Responses[q.RefID] = backend.DataResponse{
-				Error:       err,
-				ErrorSource: es,
-			}
+			response.Responses[q.RefID] = errorResponse(err)
 			continue
 		}
 
@@ -35,17 +28,16 @@ func queryData(ctx context.Context, dsInfo *datasourceInfo, req *backend.QueryDa
 				Error:       fmt.Errorf("unsupported query type %s. only available in frontend mode", query.QueryType),
 				ErrorSource: backend.ErrorSourcePlugin,
 			}
+		case zipkinQueryTypeSearch:
+			response.Responses[q.RefID] = searchQuery(dsInfo, query, q, req.PluginContext)
+		case zipkinQueryTypeCompare:
+			response.Responses[q.RefID] = compareQuery(dsInfo, query, q)
+		case zipkinQueryTypeDependencyGraph:
+			response.Responses[q.RefID] = dependencyGraphQuery(dsInfo, q)
 		default:
 			traces, err := dsInfo.ZipkinClient.Trace(query.Query)
 			if err != nil {
-				es := backend.ErrorSourcePlugin
-				if backend.IsDownstreamError(err) {
-					es = backend.ErrorSourceDownstream
-				}
-				response.Responses[q.RefID] = backend.DataResponse{
-					Error:       err,
-					ErrorSource: es,
-				}
+				response.Responses[q.RefID] = errorResponse(err)
 				continue
 			}
 
@@ -58,16 +50,41 @@ func queryData(ctx context.Context, dsInfo *datasourceInfo, req *backend.QueryDa
 	return response, nil
 }
 
+func errorResponse(err error) backend.DataResponse {
+	es := backend.ErrorSourcePlugin
+	if backend.IsDownstreamError(err) {
+		es = backend.ErrorSourceDownstream
+	}
+	return backend.DataResponse{
+		Error:       err,
+		ErrorSource: es,
+	}
+}
+
 type zipkinQueryType string
 
 const (
-	zipkinQueryTypeTraceId zipkinQueryType = "traceID"
-	zipkinQueryTypeUpload  zipkinQueryType = "upload"
+	zipkinQueryTypeTraceId         zipkinQueryType = "traceID"
+	zipkinQueryTypeUpload          zipkinQueryType = "upload"
+	zipkinQueryTypeSearch          zipkinQueryType = "search"
+	zipkinQueryTypeCompare         zipkinQueryType = "compare"
+	zipkinQueryTypeDependencyGraph zipkinQueryType = "dependencyGraph"
 )
 
 type zipkinQuery struct {
 	Query     string          `json:"query,omitempty"`
 	QueryType zipkinQueryType `json:"queryType,omitempty"`
+
+	// Search query fields. Durations are Go durations like "100ms".
+	ServiceName     string `json:"serviceName,omitempty"`
+	SpanName        string `json:"spanName,omitempty"`
+	AnnotationQuery string `json:"annotationQuery,omitempty"`
+	MinDuration     string `json:"minDuration,omitempty"`
+	MaxDuration     string `json:"maxDuration,omitempty"`
+	Limit           int    `json:"limit,omitempty"`
+
+	// CompareTraceID is the trace compared against the trace in Query by compare queries.
+	CompareTraceID string `json:"compareTraceID,omitempty"`
 }
 
 func loadQuery(backendQuery backend.DataQuery) (zipkinQuery, error) {
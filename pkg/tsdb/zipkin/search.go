@@ -0,0 +1,153 @@
+package zipkin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/openzipkin/zipkin-go/model"
+)
+
+const defaultSearchLimit = 20
+
+func searchQuery(dsInfo *datasourceInfo, query zipkinQuery, q backend.DataQuery, pCtx backend.PluginContext) backend.DataResponse {
+	params, err := searchParams(query, q.TimeRange)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	traces, err := dsInfo.ZipkinClient.SearchTraces(params)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return backend.DataResponse{
+		Frames: []*data.Frame{transformSearchResponse(pCtx, traces, q.RefID)},
+	}
+}
+
+func searchParams(query zipkinQuery, timeRange backend.TimeRange) (TracesSearchParams, error) {
+	params := TracesSearchParams{
+		ServiceName:     strings.TrimSpace(query.ServiceName),
+		SpanName:        strings.TrimSpace(query.SpanName),
+		AnnotationQuery: strings.TrimSpace(query.AnnotationQuery),
+		End:             timeRange.To,
+		Lookback:        timeRange.To.Sub(timeRange.From),
+		Limit:           query.Limit,
+	}
+	if params.Limit <= 0 {
+		params.Limit = defaultSearchLimit
+	}
+
+	var err error
+	if params.MinDuration, err = parseSearchDuration("minDuration", query.MinDuration); err != nil {
+		return params, err
+	}
+	if params.MaxDuration, err = parseSearchDuration("maxDuration", query.MaxDuration); err != nil {
+		return params, err
+	}
+	return params, nil
+}
+
+func parseSearchDuration(name string, value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, backend.DownstreamError(fmt.Errorf("invalid %s %q: %w", name, value, err))
+	}
+	return d, nil
+}
+
+// transformSearchResponse returns a table with a row for each trace, linking to the trace by its ID.
+func transformSearchResponse(pCtx backend.PluginContext, traces [][]model.SpanModel, refId string) *data.Frame {
+	traceIDConfig := &data.FieldConfig{DisplayNameFromDS: "Trace ID"}
+	if settings := pCtx.DataSourceInstanceSettings; settings != nil {
+		traceIDConfig.Links = []data.DataLink{
+			{
+				Title: "Trace: ${__value.raw}",
+				URL:   "",
+				Internal: &data.InternalDataLink{
+					DatasourceUID:  settings.UID,
+					DatasourceName: settings.Name,
+					Query: map[string]interface{}{
+						"query":     "${__value.raw}",
+						"queryType": zipkinQueryTypeTraceId,
+					},
+				},
+			},
+		}
+	}
+
+	frame := data.NewFrame(refId,
+		data.NewField("traceID", nil, []string{}).SetConfig(traceIDConfig),
+		data.NewField("startTime", nil, []time.Time{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Start time"}),
+		data.NewField("traceService", nil, []string{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Service"}),
+		data.NewField("traceName", nil, []string{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Name"}),
+		data.NewField("spans", nil, []int64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Spans"}),
+		data.NewField("traceDuration", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Duration", Unit: "ms"}),
+	)
+	frame.Meta = &data.FrameMeta{
+		PreferredVisualization: data.VisTypeTable,
+		UniqueRowIDFields:      []int{0},
+	}
+
+	summaries := make([]traceSummary, 0, len(traces))
+	for _, trace := range traces {
+		if len(trace) == 0 {
+			continue
+		}
+		summaries = append(summaries, summarizeTrace(trace))
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].start.After(summaries[j].start)
+	})
+
+	for _, s := range summaries {
+		frame.AppendRow(s.traceID, s.start, s.service, s.name, int64(s.spans), float64(s.duration.Microseconds())/1000)
+	}
+	return frame
+}
+
+type traceSummary struct {
+	traceID  string
+	start    time.Time
+	service  string
+	name     string
+	spans    int
+	duration time.Duration
+}
+
+// summarizeTrace describes a trace by its root span, or by its earliest span when the root
+// span is missing. The duration covers all spans.
+func summarizeTrace(trace []model.SpanModel) traceSummary {
+	root := trace[0]
+	start := root.Timestamp
+	end := root.Timestamp.Add(root.Duration)
+	for _, span := range trace {
+		isRoot, rootIsRoot := span.ParentID == nil, root.ParentID == nil
+		if (isRoot && !rootIsRoot) || (isRoot == rootIsRoot && span.Timestamp.Before(root.Timestamp)) {
+			root = span
+		}
+		if span.Timestamp.Before(start) {
+			start = span.Timestamp
+		}
+		if spanEnd := span.Timestamp.Add(span.Duration); spanEnd.After(end) {
+			end = spanEnd
+		}
+	}
+
+	return traceSummary{
+		traceID:  root.TraceID.String(),
+		start:    start,
+		service:  getServiceName(root),
+		name:     root.Name,
+		spans:    len(trace),
+		duration: end.Sub(start),
+	}
+}
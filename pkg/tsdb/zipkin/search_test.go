@@ -0,0 +1,77 @@
+package zipkin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchParams(t *testing.T) {
+	timeRange := backend.TimeRange{
+		From: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	params, err := searchParams(zipkinQuery{
+		ServiceName:     " frontend ",
+		AnnotationQuery: "error",
+		MinDuration:     "250ms",
+		MaxDuration:     "1.5s",
+	}, timeRange)
+	require.NoError(t, err)
+	require.Equal(t, TracesSearchParams{
+		ServiceName:     "frontend",
+		AnnotationQuery: "error",
+		MinDuration:     250 * time.Millisecond,
+		MaxDuration:     1500 * time.Millisecond,
+		End:             timeRange.To,
+		Lookback:        time.Hour,
+		Limit:           defaultSearchLimit,
+	}, params)
+
+	_, err = searchParams(zipkinQuery{MinDuration: "fast"}, timeRange)
+	require.Error(t, err)
+	require.True(t, backend.IsDownstreamError(err))
+}
+
+func TestTransformSearchResponse(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	rootID := model.ID(1)
+	older := []model.SpanModel{
+		{
+			SpanContext:   model.SpanContext{TraceID: model.TraceID{Low: 1}, ID: 2, ParentID: &rootID},
+			Name:          "query",
+			Timestamp:     start.Add(5 * time.Millisecond),
+			Duration:      50 * time.Millisecond,
+			LocalEndpoint: &model.Endpoint{ServiceName: "db"},
+		},
+		{
+			SpanContext:   model.SpanContext{TraceID: model.TraceID{Low: 1}, ID: rootID},
+			Name:          "get /api",
+			Timestamp:     start,
+			Duration:      40 * time.Millisecond,
+			LocalEndpoint: &model.Endpoint{ServiceName: "frontend"},
+		},
+	}
+	newer := []model.SpanModel{
+		{
+			SpanContext:   model.SpanContext{TraceID: model.TraceID{Low: 2}, ID: 3},
+			Name:          "post /api",
+			Timestamp:     start.Add(time.Minute),
+			Duration:      10 * time.Millisecond,
+			LocalEndpoint: &model.Endpoint{ServiceName: "frontend"},
+		},
+	}
+
+	pCtx := backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "zipkin-uid", Name: "Zipkin"}}
+	frame := transformSearchResponse(pCtx, [][]model.SpanModel{older, newer, {}}, "A")
+
+	require.Equal(t, "A", frame.Name)
+	require.Equal(t, 2, frame.Rows())
+	require.Equal(t, "0000000000000002", frame.Fields[0].At(0))
+	require.Equal(t, []any{"0000000000000001", start, "frontend", "get /api", int64(2), 55.0}, frame.RowCopy(1))
+	require.Equal(t, "zipkin-uid", frame.Fields[0].Config.Links[0].Internal.DatasourceUID)
+}
@@ -34,11 +34,17 @@ function ShareSnapshotRenderer({ model }: SceneComponentProps<ShareSnapshot>) {
   const { snapshotName, snapshotSharingOptions, selectedExpireOption, panelRef, onDismiss, dashboardRef } =
     model.useState();
 
+  const [isExternal, setIsExternal] = useState(false);
+
   const [snapshotResult, createSnapshot] = useAsyncFn(async (external = false) => {
     const response = await model.onSnapshotCreate(external);
+    setIsExternal(external);
     setStep(2);
     return response;
   });
+  const [refreshSnapshotResult, refreshSnapshot] = useAsyncFn(async (key: string) => {
+    return model.onSnapshotRefresh(key);
+  });
   const [deleteSnapshotResult, deleteSnapshot] = useAsyncFn(async (url: string) => {
     const response = await model.onSnapshotDelete(url);
     setStep(1);
@@ -105,6 +111,8 @@ function ShareSnapshotRenderer({ model }: SceneComponentProps<ShareSnapshot>) {
               snapshotResult.value && (
                 <UpsertSnapshotActions
                   url={snapshotResult.value!.url}
+                  onRefreshClick={isExternal ? undefined : () => refreshSnapshot(snapshotResult.value!.key)}
+                  isRefreshing={refreshSnapshotResult.loading}
                   onDeleteClick={() => setShowDeleteConfirmation(true)}
                   onNewSnapshotClick={reset}
                 />
@@ -154,10 +162,14 @@ const CreateSnapshotActions = ({
 
 const UpsertSnapshotActions = ({
   url,
+  onRefreshClick,
+  isRefreshing,
   onDeleteClick,
   onNewSnapshotClick,
 }: {
   url: string;
+  onRefreshClick?: () => void;
+  isRefreshing: boolean;
   onDeleteClick: () => void;
   onNewSnapshotClick: () => void;
 }) => {
@@ -178,6 +190,18 @@ const UpsertSnapshotActions = ({
       >
         <Trans i18nKey="snapshot.share.copy-link-button">Copy link</Trans>
       </ClipboardButton>
+      {onRefreshClick && (
+        <Button
+          icon={isRefreshing ? 'spinner' : 'sync'}
+          variant="secondary"
+          fill="outline"
+          onClick={onRefreshClick}
+          disabled={isRefreshing}
+          data-testid={selectors.refreshSnapshotButton}
+        >
+          <Trans i18nKey="snapshot.share.refresh-button">Refresh snapshot</Trans>
+        </Button>
+      )}
       <Button
         icon="trash-alt"
         variant="destructive"
@@ -0,0 +1,105 @@
+import { getDefaultTimeRange, LoadingState } from '@grafana/data';
+import { getPanelPlugin } from '@grafana/data/test';
+import { setPluginImportUtils } from '@grafana/runtime';
+import { SceneQueryRunner, SceneTimeRange, VizPanel } from '@grafana/scenes';
+
+import { DashboardScene } from '../scene/DashboardScene';
+import { DefaultGridLayoutManager } from '../scene/layout-default/DefaultGridLayoutManager';
+import { activateFullSceneTree } from '../utils/test-utils';
+
+import { refreshQueries, ShareSnapshotTab } from './ShareSnapshotTab';
+
+const refreshMock = jest.fn().mockResolvedValue({ key: 'snap-1', version: 2, url: 'http://localhost/snap-1' });
+
+jest.mock('app/features/dashboard/services/SnapshotSrv', () => ({
+  ...jest.requireActual('app/features/dashboard/services/SnapshotSrv'),
+  getDashboardSnapshotSrv: () => ({
+    getSharingOptions: jest.fn().mockResolvedValue({ externalEnabled: false, snapshotEnabled: true }),
+    refresh: refreshMock,
+  }),
+}));
+
+jest.mock('app/store/store', () => ({
+  dispatch: jest.fn(),
+}));
+
+setPluginImportUtils({
+  importPanelPlugin: () => Promise.resolve(getPanelPlugin({})),
+  getPanelPluginFromCache: () => undefined,
+});
+
+describe('ShareSnapshotTab', () => {
+  let runQueries: jest.SpyInstance;
+
+  beforeEach(() => {
+    refreshMock.mockClear();
+    runQueries = jest.spyOn(SceneQueryRunner.prototype, 'runQueries').mockImplementation(() => {});
+  });
+
+  afterEach(() => {
+    runQueries.mockRestore();
+  });
+
+  describe('refreshQueries', () => {
+    it('resolves once every active query runner has new results', async () => {
+      const { scene, runner } = buildScenario();
+
+      let refreshed = false;
+      const done = refreshQueries(scene).then(() => {
+        refreshed = true;
+      });
+
+      runner.setState({ data: { state: LoadingState.Loading, series: [], timeRange: getDefaultTimeRange() } });
+      await Promise.resolve();
+      expect(refreshed).toBe(false);
+
+      runner.setState({ data: { state: LoadingState.Done, series: [], timeRange: getDefaultTimeRange() } });
+      await done;
+      expect(refreshed).toBe(true);
+    });
+
+    it('resolves when there is no active query runner', async () => {
+      const { scene } = buildScenario({ withRunner: false });
+
+      await expect(refreshQueries(scene)).resolves.toBeUndefined();
+    });
+  });
+
+  describe('onSnapshotRefresh', () => {
+    it('sends a snapshot built from the new results', async () => {
+      const { tab, runner } = buildScenario();
+
+      const result = tab.onSnapshotRefresh('snap-1');
+      runner.setState({ data: { state: LoadingState.Loading, series: [], timeRange: getDefaultTimeRange() } });
+      runner.setState({ data: { state: LoadingState.Done, series: [], timeRange: getDefaultTimeRange() } });
+
+      expect(await result).toEqual({ key: 'snap-1', version: 2, url: 'http://localhost/snap-1' });
+      expect(refreshMock).toHaveBeenCalledTimes(1);
+      expect(refreshMock).toHaveBeenCalledWith('snap-1', {
+        dashboard: expect.objectContaining({ title: 'hello', panels: expect.any(Array) }),
+      });
+    });
+  });
+});
+
+function buildScenario({ withRunner = true }: { withRunner?: boolean } = {}) {
+  const runner = new SceneQueryRunner({ queries: [{ refId: 'A' }] });
+  const panel = new VizPanel({
+    title: 'Panel A',
+    pluginId: 'table',
+    key: 'panel-12',
+    $data: withRunner ? runner : undefined,
+  });
+  const scene = new DashboardScene({
+    title: 'hello',
+    uid: 'dash-1',
+    $timeRange: new SceneTimeRange({}),
+    body: DefaultGridLayoutManager.fromVizPanels([panel]),
+  });
+  const tab = new ShareSnapshotTab({ dashboardRef: scene.getRef() });
+  scene.setState({ overlay: tab });
+
+  activateFullSceneTree(scene);
+
+  return { scene, tab, runner };
+}
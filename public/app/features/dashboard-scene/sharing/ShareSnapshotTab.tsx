@@ -1,13 +1,16 @@
+import { useState } from 'react';
 import useAsyncFn from 'react-use/lib/useAsyncFn';
 
-import { type SelectableValue } from '@grafana/data';
+import { LoadingState, type SelectableValue } from '@grafana/data';
 import { selectors as e2eSelectors } from '@grafana/e2e-selectors';
 import { Trans, t } from '@grafana/i18n';
 import {
   type SceneComponentProps,
   sceneGraph,
+  type SceneObject,
   SceneObjectBase,
   type SceneObjectRef,
+  SceneQueryRunner,
   type VizPanel,
 } from '@grafana/scenes';
 import { type Dashboard } from '@grafana/schema';
@@ -184,6 +187,21 @@ export class ShareSnapshotTab extends SceneObjectBase<ShareSnapshotTabState> imp
     }
   };
 
+  /**
+   * Re-runs the panel queries and replaces the dashboard of an existing local snapshot
+   * with one built from the new results. The snapshot keeps its key and expiry date.
+   */
+  public onSnapshotRefresh = async (key: string) => {
+    await refreshQueries(this.state.panelRef?.resolve() ?? this.state.dashboardRef.resolve());
+    const snapshot = this.prepareSnapshot();
+
+    const response = await getDashboardSnapshotSrv().refresh(key, { dashboard: snapshot });
+    dispatch(
+      notifyApp(createSuccessNotification(t('snapshot.share.success-refresh', 'Your snapshot has been refreshed')))
+    );
+    return response;
+  };
+
   public onSnapshotDelete = async (key: string) => {
     const response = await getDashboardSnapshotSrv().deleteSnapshot(key);
     dispatch(
@@ -193,10 +211,39 @@ export class ShareSnapshotTab extends SceneObjectBase<ShareSnapshotTabState> imp
   };
 }
 
+/**
+ * Refreshes the time range and resolves once every active query runner below the scene has new results.
+ * Runners that are not active (collapsed rows, panels outside the viewport) keep their data, the same as
+ * when the snapshot is created.
+ */
+export async function refreshQueries(scene: SceneObject) {
+  const runners = sceneGraph.findAllObjects(
+    scene,
+    (o) => o instanceof SceneQueryRunner && o.isActive
+  ) as SceneQueryRunner[];
+
+  const done = runners.map(
+    (runner) =>
+      new Promise<void>((resolve) => {
+        const sub = runner.subscribeToState((state, prev) => {
+          if (prev.data?.state === LoadingState.Loading && state.data?.state !== LoadingState.Loading) {
+            sub.unsubscribe();
+            resolve();
+          }
+        });
+      })
+  );
+
+  sceneGraph.getTimeRange(scene).onRefresh();
+  await Promise.all(done);
+}
+
 function ShareSnapshotTabRenderer({ model }: SceneComponentProps<ShareSnapshotTab>) {
   const { snapshotName, selectedExpireOption, modalRef, snapshotSharingOptions } = model.useState();
 
+  const [external, setExternal] = useState(false);
   const [snapshotResult, createSnapshot] = useAsyncFn(async (external = false) => {
+    setExternal(external);
     return model.onSnapshotCreate(external);
   });
 
@@ -204,6 +251,10 @@ function ShareSnapshotTabRenderer({ model }: SceneComponentProps<ShareSnapshotTa
     return await getDashboardSnapshotSrv().deleteSnapshot(key);
   });
 
+  const [refreshSnapshotResult, refreshSnapshot] = useAsyncFn(async (key: string) => {
+    return model.onSnapshotRefresh(key);
+  });
+
   // If snapshot has been deleted - show message and allow to close modal
   if (deleteSnapshotResult.value) {
     return (
@@ -303,6 +354,23 @@ function ShareSnapshotTabRenderer({ model }: SceneComponentProps<ShareSnapshotTa
             />
           </Field>
 
+          {!external && (
+            <div style={{ alignSelf: 'flex-end', padding: '5px' }}>
+              <Button
+                fill="outline"
+                size="md"
+                variant="secondary"
+                icon="sync"
+                disabled={refreshSnapshotResult.loading}
+                onClick={() => {
+                  refreshSnapshot(snapshotResult.value!.key);
+                }}
+              >
+                <Trans i18nKey="share-modal.snapshot.refresh-button">Refresh snapshot</Trans>
+              </Button>
+            </div>
+          )}
+
           <div style={{ alignSelf: 'flex-end', padding: '5px' }}>
             <Trans i18nKey="share-modal.snapshot.mistake-message">Did you make a mistake? </Trans>&nbsp;
             <Button
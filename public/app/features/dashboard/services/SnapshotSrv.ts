@@ -35,8 +35,19 @@ export interface SnapshotCreateResponse {
   deleteUrl: string;
 }
 
+export interface SnapshotRefreshCommand {
+  dashboard: object;
+}
+
+export interface SnapshotRefreshResponse {
+  key: string;
+  version: number;
+  url: string;
+}
+
 export interface DashboardSnapshotSrv {
   create: (cmd: SnapshotCreateCommand) => Promise<SnapshotCreateResponse>;
+  refresh: (key: string, cmd: SnapshotRefreshCommand) => Promise<SnapshotRefreshResponse>;
   getSnapshots: () => Promise<Snapshot[]>;
   getSharingOptions: () => Promise<SnapshotSharingOptions>;
   deleteSnapshot: (key: string) => Promise<void>;
@@ -45,6 +56,8 @@ export interface DashboardSnapshotSrv {
 
 const legacyDashboardSnapshotSrv: DashboardSnapshotSrv = {
   create: (cmd: SnapshotCreateCommand) => getBackendSrv().post<SnapshotCreateResponse>('/api/snapshots', cmd),
+  refresh: (key: string, cmd: SnapshotRefreshCommand) =>
+    getBackendSrv().post<SnapshotRefreshResponse>('/api/snapshots/' + key + '/refresh', cmd),
   getSnapshots: () => getBackendSrv().get<Snapshot[]>('/api/dashboard/snapshots'),
   getSharingOptions: () => getBackendSrv().get<SnapshotSharingOptions>('/api/snapshot/shared-options'),
   deleteSnapshot: (key: string) => getBackendSrv().delete('/api/snapshots/' + key),
@@ -102,6 +115,11 @@ class K8sAPI implements DashboardSnapshotSrv {
     return getBackendSrv().post<SnapshotCreateResponse>(this.url + '/create', cmd);
   }
 
+  // The snapshots API has no refresh subresource yet, so the legacy endpoint is used
+  async refresh(key: string, cmd: SnapshotRefreshCommand) {
+    return legacyDashboardSnapshotSrv.refresh(key, cmd);
+  }
+
   async getSnapshots(): Promise<Snapshot[]> {
     const result = await getBackendSrv().get<DashboardSnapshotList>(this.url);
     return result.items.map((r) => {
@@ -18,6 +18,11 @@ export enum GrafanaQueryType {
   RandomWalk = 'randomWalk',
   List = 'list',
   Read = 'read',
+  GrafanaAnnotations = 'grafanaAnnotations',
+  AlertStateHistory = 'alertStateHistory',
+  AlertInstances = 'alertInstances',
+  DashboardSearch = 'dashboardSearch',
+  UsageStats = 'usageStats',
 }
 
 export interface GrafanaQuery extends DataQuery {
@@ -26,6 +31,22 @@ export interface GrafanaQuery extends DataQuery {
   filter?: LiveDataFilter;
   buffer?: number;
   path?: string; // for list and read
+  columns?: string[]; // for read
+  limit?: number; // for read
+  timeColumn?: string; // for read
+  format?: 'table' | 'timeseries'; // for grafanaAnnotations
+  tags?: string[]; // for grafanaAnnotations and dashboardSearch
+  matchAny?: boolean; // for grafanaAnnotations
+  annotationType?: 'annotation' | 'alert'; // for grafanaAnnotations
+  dashboardUID?: string; // for grafanaAnnotations and alertStateHistory
+  ruleUID?: string; // for alertStateHistory and alertInstances
+  previous?: string; // for alertStateHistory
+  current?: string; // for alertStateHistory
+  matchers?: string; // for alertStateHistory
+  state?: string; // for alertInstances
+  title?: string; // for dashboardSearch
+  folderUIDs?: string[]; // for dashboardSearch
+  searchType?: string; // for dashboardSearch
   search?: SearchQuery;
   searchNext?: SearchQuery;
   snapshot?: DataFrameJSON[];
@@ -1,6 +1,6 @@
 import { isArray } from 'lodash';
 import moment from 'moment';
-import { type Observable, of } from 'rxjs';
+import { lastValueFrom, type Observable, of } from 'rxjs';
 
 import {
   type AbstractLabelMatcher,
@@ -19,6 +19,7 @@ import {
 import {
   type BackendSrvRequest,
   config,
+  DataSourceWithBackend,
   type FetchResponse,
   getTemplateSrv,
   type TemplateSrv,
@@ -542,6 +543,35 @@ describe('graphiteDatasource', () => {
       expect(results).toEqual([]);
       expect(console.error).toHaveBeenCalledWith(expect.stringMatching(/Unable to get annotations/));
     });
+
+    it('should query events on the backend', async () => {
+      const querySpy = jest
+        .spyOn(DataSourceWithBackend.prototype, 'query')
+        .mockReturnValue(of({ data: [{ refId: 'Anno', fields: [], length: 0 }] }));
+
+      const request = {
+        ...options,
+        targets: options.targets as GraphiteQuery[],
+      } as unknown as DataQueryRequest<GraphiteQuery>;
+      const response = await lastValueFrom(ctx.ds.query(request));
+
+      expect(querySpy).toHaveBeenCalledWith(
+        expect.objectContaining({ targets: [expect.objectContaining({ refId: 'Anno', tags: ['tag1'] })] })
+      );
+      expect(response.data[0].refId).toBe('Anno');
+      querySpy.mockRestore();
+    });
+
+    it('should interpolate the tags of events queries', () => {
+      ctx.templateSrv.replace = jest.fn((s?: string) => (s ?? '').replace('$env', 'prod'));
+
+      const query = ctx.ds.applyTemplateVariables(
+        { refId: 'Anno', fromAnnotations: true, tags: ['deploy', '$env'] },
+        {}
+      );
+
+      expect(query.tags).toEqual(['deploy', 'prod']);
+    });
   });
 
   describe('when fetching Graphite function descriptions', () => {
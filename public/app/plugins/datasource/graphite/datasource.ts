@@ -215,6 +215,7 @@ export class GraphiteDatasource
       ...target,
       target: this.templateSrv.replace(target.target ?? '', scopedVars),
       targetFull: this.templateSrv.replace(target.targetFull ?? '', scopedVars),
+      tags: target.tags?.map((tag) => this.templateSrv.replace(tag, scopedVars)),
     };
   }
 
@@ -330,6 +331,12 @@ export class GraphiteDatasource
       const streams: Array<Observable<DataQueryResponse>> = [];
 
       for (const target of options.targets) {
+        // Graphite events are queried by the backend, which returns them as annotation frames
+        if (config.featureToggles.graphiteBackendMode && !target.target) {
+          streams.push(super.query({ ...options, targets: [target] }));
+          continue;
+        }
+
         streams.push(
           new Observable((subscriber) => {
             this.annotationEvents(options.range, target)
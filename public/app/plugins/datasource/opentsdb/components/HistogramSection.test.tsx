@@ -0,0 +1,51 @@
+import { render, screen } from '@testing-library/react';
+
+import { type OpenTsdbQuery } from '../types';
+
+import { HistogramSection, type HistogramSectionProps, testIds } from './HistogramSection';
+
+const onRunQuery = jest.fn();
+const onChange = jest.fn();
+
+const setup = (query: Partial<OpenTsdbQuery>, rollupsEnabled = false) => {
+  const props: HistogramSectionProps = {
+    query: { refId: 'A', metric: 'http.latency', ...query },
+    onChange,
+    onRunQuery,
+    rollupsEnabled,
+  };
+
+  return render(<HistogramSection {...props} />);
+};
+
+describe('HistogramSection', () => {
+  beforeEach(() => {
+    jest.clearAllMocks();
+  });
+
+  it('should render the percentiles input', () => {
+    setup({ percentiles: '50, 99' });
+    expect(screen.getByTestId(testIds.percentiles)).toHaveValue('50, 99');
+  });
+
+  it('should only render the show buckets switch with percentiles', () => {
+    setup({});
+    expect(screen.queryByTestId(testIds.showHistogramBuckets)).not.toBeInTheDocument();
+  });
+
+  it('should only render the rollup usage when rollups are enabled', () => {
+    const { rerender } = setup({});
+    expect(screen.queryByText('Rollups')).not.toBeInTheDocument();
+
+    rerender(
+      <HistogramSection
+        query={{ refId: 'A', rollupUsage: 'ROLLUP_FALLBACK' }}
+        onChange={onChange}
+        onRunQuery={onRunQuery}
+        rollupsEnabled={true}
+      />
+    );
+    expect(screen.getByText('Rollups')).toBeInTheDocument();
+    expect(screen.getByText('Fallback')).toBeInTheDocument();
+  });
+});
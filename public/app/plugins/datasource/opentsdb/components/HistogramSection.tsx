@@ -0,0 +1,92 @@
+import { type SelectableValue } from '@grafana/data';
+import { InlineFormLabel, InlineLabel, InlineSwitch, Input, Select, Stack } from '@grafana/ui';
+
+import { type OpenTsdbQuery, type OpenTsdbRollupUsage } from '../types';
+
+export interface HistogramSectionProps {
+  query: OpenTsdbQuery;
+  onChange: (query: OpenTsdbQuery) => void;
+  onRunQuery: () => void;
+  rollupsEnabled: boolean;
+}
+
+const rollupUsageOptions: Array<SelectableValue<OpenTsdbRollupUsage>> = [
+  { value: 'ROLLUP_FALLBACK', label: 'Fallback', description: 'Use the best rollup table, falling back to others' },
+  {
+    value: 'ROLLUP_FALLBACK_RAW',
+    label: 'Fallback to raw',
+    description: 'Use the best rollup table, falling back to raw data',
+  },
+  { value: 'ROLLUP_NOFALLBACK', label: 'No fallback', description: 'Only use the best rollup table' },
+  { value: 'ROLLUP_RAW', label: 'Raw', description: 'Ignore rollup tables' },
+];
+
+export function HistogramSection({ query, onChange, onRunQuery, rollupsEnabled }: HistogramSectionProps) {
+  return (
+    <Stack gap={0.5} alignItems="flex-start" data-testid={testIds.section}>
+      {rollupsEnabled && (
+        <Stack gap={0} alignItems="flex-start">
+          <InlineFormLabel
+            width={8}
+            className="query-keyword"
+            tooltip="How OpenTSDB uses rollup tables to answer queries with a down sample interval"
+          >
+            Rollups
+          </InlineFormLabel>
+          <Select
+            inputId="opentsdb-rollup-usage-select"
+            width={25}
+            isClearable
+            placeholder="OpenTSDB default"
+            value={query.rollupUsage ?? null}
+            options={rollupUsageOptions}
+            onChange={(option) => {
+              onChange({ ...query, rollupUsage: option?.value });
+              onRunQuery();
+            }}
+          />
+        </Stack>
+      )}
+      <Stack gap={0}>
+        <InlineFormLabel
+          width={rollupsEnabled ? 'auto' : 8}
+          className="query-keyword"
+          tooltip="Comma separated percentiles to compute from histogram metrics"
+        >
+          Percentiles
+        </InlineFormLabel>
+        <Input
+          data-testid={testIds.percentiles}
+          placeholder="50, 95, 99"
+          value={query.percentiles ?? ''}
+          onChange={(e) => onChange({ ...query, percentiles: e.currentTarget.value })}
+          onBlur={() => onRunQuery()}
+        />
+      </Stack>
+      {query.percentiles && (
+        <>
+          <InlineFormLabel className="query-keyword" width={'auto'}>
+            Show buckets
+          </InlineFormLabel>
+          <InlineSwitch
+            data-testid={testIds.showHistogramBuckets}
+            value={query.showHistogramBuckets ?? false}
+            onChange={() => {
+              onChange({ ...query, showHistogramBuckets: !query.showHistogramBuckets });
+              onRunQuery();
+            }}
+          />
+        </>
+      )}
+      <Stack gap={0} grow={1}>
+        <InlineLabel> </InlineLabel>
+      </Stack>
+    </Stack>
+  );
+}
+
+export const testIds = {
+  section: 'opentsdb-histogram',
+  percentiles: 'opentsdb-percentiles',
+  showHistogramBuckets: 'opentsdb-show-histogram-buckets',
+};
@@ -2,6 +2,7 @@ import { css } from '@emotion/css';
 import { useEffect, useState } from 'react';
 
 import { type GrafanaTheme2, type QueryEditorProps, textUtil } from '@grafana/data';
+import { config } from '@grafana/runtime';
 import { useStyles2, Stack } from '@grafana/ui';
 
 import type OpenTsDatasource from '../datasource';
@@ -9,7 +10,9 @@ import { type OpenTsdbOptions, type OpenTsdbQuery } from '../types';
 
 import { DownSample } from './DownSample';
 import { FilterSection } from './FilterSection';
+import { HistogramSection } from './HistogramSection';
 import { MetricSection } from './MetricSection';
+import { QueryTypeSection } from './QueryTypeSection';
 import { RateSection } from './RateSection';
 import { TagSection } from './TagSection';
 
@@ -48,7 +51,12 @@ export function OpenTsdbQueryEditor({
     'regexp',
   ]);
 
+  const [rollupsEnabled, setRollupsEnabled] = useState(false);
+
   const tsdbVersion: number = datasource.tsdbVersion;
+  // TSUID and expression queries, rollups and histograms are only supported by the backend
+  const backendQueries = !!config.featureToggles.opentsdbBackendMigration;
+  const queryType = (backendQueries && query.queryType) || 'metric';
 
   if (!query.aggregator) {
     query.aggregator = 'sum';
@@ -78,6 +86,12 @@ export function OpenTsdbQueryEditor({
     });
   }, [datasource]);
 
+  useEffect(() => {
+    if (backendQueries && tsdbVersion >= 4) {
+      datasource.getRollupConfig().then((rollupConfig) => setRollupsEnabled(rollupConfig.enabled));
+    }
+  }, [datasource, backendQueries, tsdbVersion]);
+
   async function suggestMetrics(value: string): Promise<Array<{ value: string; description: string }>> {
     return datasource.metricFindQuery(`metrics(${value})`).then(getTextValues);
   }
@@ -113,13 +127,18 @@ export function OpenTsdbQueryEditor({
   return (
     <div className={styles.container} data-testid={testIds.editor}>
       <Stack gap={0.5} direction="column" grow={1}>
-        <MetricSection
-          query={query}
-          onChange={onChange}
-          onRunQuery={onRunQuery}
-          suggestMetrics={suggestMetrics}
-          aggregators={aggregators}
-        />
+        {backendQueries && tsdbVersion >= 3 && (
+          <QueryTypeSection query={query} onChange={onChange} onRunQuery={onRunQuery} aggregators={aggregators} />
+        )}
+        {queryType === 'metric' && (
+          <MetricSection
+            query={query}
+            onChange={onChange}
+            onRunQuery={onRunQuery}
+            suggestMetrics={suggestMetrics}
+            aggregators={aggregators}
+          />
+        )}
         <DownSample
           query={query}
           onChange={onChange}
@@ -128,7 +147,7 @@ export function OpenTsdbQueryEditor({
           fillPolicies={fillPolicies}
           tsdbVersion={tsdbVersion}
         />
-        {tsdbVersion >= 2 && (
+        {tsdbVersion >= 2 && queryType === 'metric' && (
           <FilterSection
             query={query}
             onChange={onChange}
@@ -138,15 +157,22 @@ export function OpenTsdbQueryEditor({
             suggestTagKeys={suggestTagKeys}
           />
         )}
-        <TagSection
-          query={query}
-          onChange={onChange}
-          onRunQuery={onRunQuery}
-          suggestTagValues={suggestTagValues}
-          suggestTagKeys={suggestTagKeys}
-          tsdbVersion={tsdbVersion}
-        />
-        <RateSection query={query} onChange={onChange} onRunQuery={onRunQuery} tsdbVersion={tsdbVersion} />
+        {queryType === 'metric' && (
+          <TagSection
+            query={query}
+            onChange={onChange}
+            onRunQuery={onRunQuery}
+            suggestTagValues={suggestTagValues}
+            suggestTagKeys={suggestTagKeys}
+            tsdbVersion={tsdbVersion}
+          />
+        )}
+        {queryType !== 'expression' && (
+          <RateSection query={query} onChange={onChange} onRunQuery={onRunQuery} tsdbVersion={tsdbVersion} />
+        )}
+        {backendQueries && tsdbVersion >= 4 && queryType !== 'expression' && (
+          <HistogramSection query={query} onChange={onChange} onRunQuery={onRunQuery} rollupsEnabled={rollupsEnabled} />
+        )}
       </Stack>
     </div>
   );
@@ -0,0 +1,50 @@
+import { fireEvent, render, screen } from '@testing-library/react';
+
+import { type OpenTsdbQuery } from '../types';
+
+import { QueryTypeSection, type QueryTypeSectionProps, testIds } from './QueryTypeSection';
+
+const onRunQuery = jest.fn();
+const onChange = jest.fn();
+
+const setup = (query: Partial<OpenTsdbQuery>) => {
+  const props: QueryTypeSectionProps = {
+    query: { refId: 'A', ...query },
+    onChange,
+    onRunQuery,
+    aggregators: ['avg', 'sum'],
+  };
+
+  return render(<QueryTypeSection {...props} />);
+};
+
+describe('QueryTypeSection', () => {
+  beforeEach(() => {
+    jest.clearAllMocks();
+  });
+
+  it('should only render the query types for metric queries', () => {
+    setup({});
+    expect(screen.getByTestId(testIds.section)).toBeInTheDocument();
+    expect(screen.queryByTestId(testIds.tsuids)).not.toBeInTheDocument();
+    expect(screen.queryByTestId(testIds.expression)).not.toBeInTheDocument();
+  });
+
+  it('should change the query type', () => {
+    setup({});
+    fireEvent.click(screen.getByLabelText('Expression'));
+    expect(onChange).toHaveBeenCalledWith({ refId: 'A', queryType: 'expression' });
+    expect(onRunQuery).toHaveBeenCalled();
+  });
+
+  it('should render the TSUIDs input for TSUID queries', () => {
+    setup({ queryType: 'tsuid', tsuids: '000001000001000001' });
+    expect(screen.getByTestId(testIds.tsuids)).toHaveValue('000001000001000001');
+  });
+
+  it('should render the expression input for expression queries', () => {
+    setup({ queryType: 'expression' });
+    fireEvent.change(screen.getByTestId(testIds.expression), { target: { value: 'A / B' } });
+    expect(onChange).toHaveBeenCalledWith({ refId: 'A', queryType: 'expression', expression: 'A / B' });
+  });
+});
@@ -0,0 +1,105 @@
+import { type SelectableValue, toOption } from '@grafana/data';
+import { InlineFormLabel, InlineLabel, Input, RadioButtonGroup, Select, Stack } from '@grafana/ui';
+
+import { type OpenTsdbQuery, type OpenTsdbQueryType } from '../types';
+
+export interface QueryTypeSectionProps {
+  query: OpenTsdbQuery;
+  onChange: (query: OpenTsdbQuery) => void;
+  onRunQuery: () => void;
+  aggregators: string[];
+}
+
+const queryTypeOptions: Array<SelectableValue<OpenTsdbQueryType>> = [
+  { value: 'metric', label: 'Metric' },
+  { value: 'tsuid', label: 'TSUID' },
+  { value: 'expression', label: 'Expression' },
+];
+
+export function QueryTypeSection({ query, onChange, onRunQuery, aggregators }: QueryTypeSectionProps) {
+  const aggregatorOptions = aggregators.map((value: string) => toOption(value));
+  const queryType = query.queryType ?? 'metric';
+
+  return (
+    <Stack gap={0.5} alignItems="flex-start" data-testid={testIds.section}>
+      <Stack gap={0}>
+        <InlineFormLabel width={8} className="query-keyword">
+          Query type
+        </InlineFormLabel>
+        <RadioButtonGroup<OpenTsdbQueryType>
+          options={queryTypeOptions}
+          value={queryType}
+          onChange={(value) => {
+            onChange({ ...query, queryType: value });
+            onRunQuery();
+          }}
+        />
+      </Stack>
+      {queryType === 'tsuid' && (
+        <Stack gap={0}>
+          <InlineFormLabel width={'auto'} className="query-keyword" tooltip="Comma separated UIDs of the time series">
+            TSUIDs
+          </InlineFormLabel>
+          <Input
+            width={40}
+            data-testid={testIds.tsuids}
+            placeholder="000001000001000001, 000001000001000002"
+            value={query.tsuids ?? ''}
+            onChange={(e) => onChange({ ...query, tsuids: e.currentTarget.value })}
+            onBlur={() => onRunQuery()}
+          />
+        </Stack>
+      )}
+      {queryType === 'expression' && (
+        <Stack gap={0}>
+          <InlineFormLabel
+            width={'auto'}
+            className="query-keyword"
+            tooltip={
+              <div>
+                Combine the metrics of other queries by their letters, for example <code>A / B * 100</code>
+              </div>
+            }
+          >
+            Expression
+          </InlineFormLabel>
+          <Input
+            width={40}
+            data-testid={testIds.expression}
+            placeholder="A / B * 100"
+            value={query.expression ?? ''}
+            onChange={(e) => onChange({ ...query, expression: e.currentTarget.value })}
+            onBlur={() => onRunQuery()}
+          />
+        </Stack>
+      )}
+      {queryType !== 'metric' && (
+        <Stack gap={0} alignItems="flex-start">
+          <InlineFormLabel width={'auto'} className="query-keyword">
+            Aggregator
+          </InlineFormLabel>
+          <Select
+            inputId="opentsdb-query-type-aggregator-select"
+            value={query.aggregator ? toOption(query.aggregator) : undefined}
+            options={aggregatorOptions}
+            onChange={({ value }) => {
+              if (value) {
+                onChange({ ...query, aggregator: value });
+                onRunQuery();
+              }
+            }}
+          />
+        </Stack>
+      )}
+      <Stack gap={0} grow={1}>
+        <InlineLabel> </InlineLabel>
+      </Stack>
+    </Stack>
+  );
+}
+
+export const testIds = {
+  section: 'opentsdb-query-type',
+  tsuids: 'opentsdb-tsuids',
+  expression: 'opentsdb-expression',
+};
@@ -37,7 +37,7 @@ import {
 
 import { AnnotationEditor } from './components/AnnotationEditor';
 import { prepareAnnotation } from './migrations';
-import { type OpenTsdbFilter, type OpenTsdbOptions, type OpenTsdbQuery } from './types';
+import { type OpenTsdbFilter, type OpenTsdbOptions, type OpenTsdbQuery, type OpenTsdbRollupConfig } from './types';
 
 export default class OpenTsDatasource extends DataSourceWithBackend<OpenTsdbQuery, OpenTsdbOptions> {
   type: 'opentsdb';
@@ -52,6 +52,7 @@ export default class OpenTsDatasource extends DataSourceWithBackend<OpenTsdbQuer
 
   aggregatorsPromise: Promise<string[]> | null;
   filterTypesPromise: Promise<string[]> | null;
+  rollupConfigPromise: Promise<OpenTsdbRollupConfig> | null;
 
   constructor(
     instanceSettings: any,
@@ -71,6 +72,7 @@ export default class OpenTsDatasource extends DataSourceWithBackend<OpenTsdbQuer
 
     this.aggregatorsPromise = null;
     this.filterTypesPromise = null;
+    this.rollupConfigPromise = null;
     this.annotations = {
       QueryEditor: AnnotationEditor,
       prepareAnnotation,
@@ -100,7 +102,9 @@ export default class OpenTsDatasource extends DataSourceWithBackend<OpenTsdbQuer
     }
 
     if (config.featureToggles.opentsdbBackendMigration) {
-      const hasValidTargets = options.targets.some((target) => target.metric && !target.hide);
+      const hasValidTargets = options.targets.some(
+        (target) => (target.metric || target.tsuids || target.expression) && !target.hide
+      );
       if (!hasValidTargets) {
         return of({ data: [] });
       }
@@ -542,6 +546,20 @@ export default class OpenTsDatasource extends DataSourceWithBackend<OpenTsdbQuer
     return this.filterTypesPromise;
   }
 
+  getRollupConfig(): Promise<OpenTsdbRollupConfig> {
+    if (this.rollupConfigPromise) {
+      return this.rollupConfigPromise;
+    }
+
+    // rollups are only queried by the backend
+    if (!config.featureToggles.opentsdbBackendMigration || this.tsdbVersion < 4) {
+      return Promise.resolve({ enabled: false, aggregators: [], intervals: [] });
+    }
+
+    this.rollupConfigPromise = this.getResource('api/config/rollups');
+    return this.rollupConfigPromise;
+  }
+
   transformMetricData(
     md: { dps: any },
     groupByTags: Record<string, boolean>,
@@ -735,6 +753,18 @@ export default class OpenTsDatasource extends DataSourceWithBackend<OpenTsdbQuer
       query.counterResetValue = this.templateSrv.replace(target.counterResetValue, scopedVars);
     }
 
+    if (target.tsuids) {
+      query.tsuids = this.templateSrv.replace(target.tsuids, scopedVars, 'csv');
+    }
+
+    if (target.expression) {
+      query.expression = this.templateSrv.replace(target.expression, scopedVars);
+    }
+
+    if (target.percentiles) {
+      query.percentiles = this.templateSrv.replace(target.percentiles, scopedVars, 'csv');
+    }
+
     return query;
   }
 
@@ -1,6 +1,8 @@
 import { type DataQuery, type DataSourceJsonData } from '@grafana/data';
 
 export interface OpenTsdbQuery extends DataQuery {
+  queryType?: OpenTsdbQueryType;
+
   // migrating to react
   // metrics section
   metric?: string;
@@ -31,6 +33,33 @@ export interface OpenTsdbQuery extends DataQuery {
   counterMax?: string;
   counterResetValue?: string;
   explicitTags?: boolean;
+
+  // TSUID queries, comma separated
+  tsuids?: string;
+
+  // expression queries, combining other queries by their ref IDs
+  expression?: string;
+
+  // rollups and histograms
+  rollupUsage?: OpenTsdbRollupUsage;
+  percentiles?: string;
+  showHistogramBuckets?: boolean;
+}
+
+export type OpenTsdbQueryType = 'metric' | 'tsuid' | 'expression';
+
+export type OpenTsdbRollupUsage = 'ROLLUP_RAW' | 'ROLLUP_NOFALLBACK' | 'ROLLUP_FALLBACK' | 'ROLLUP_FALLBACK_RAW';
+
+export interface OpenTsdbRollupConfig {
+  enabled: boolean;
+  aggregators: string[];
+  intervals: Array<{
+    table: string;
+    preAggregationTable: string;
+    interval: string;
+    rowSpan: string;
+    defaultInterval: boolean;
+  }>;
 }
 
 export interface OpenTsdbOptions extends DataSourceJsonData {
@@ -0,0 +1,46 @@
+import { type DataSourcePluginOptionsEditorProps, onUpdateDatasourceJsonDataOption } from '@grafana/data';
+import { ConfigSection, DataSourceDescription } from '@grafana/plugin-ui';
+import { ConnectionLimits, Divider } from '@grafana/sql';
+import { Field, Input } from '@grafana/ui';
+
+import { type SQLiteOptions } from '../types';
+
+const WIDTH_LONG = 40;
+
+export const ConfigurationEditor = (props: DataSourcePluginOptionsEditorProps<SQLiteOptions>) => {
+  const { options, onOptionsChange } = props;
+
+  return (
+    <>
+      <DataSourceDescription
+        dataSourceName="SQLite"
+        docsLink="https://grafana.com/docs/grafana/latest/datasources/sqlite/"
+        hasRequiredFields={true}
+      />
+
+      <Divider />
+
+      <ConfigSection title="Database file">
+        <Field
+          label="Path"
+          required
+          description="Path to the database file. Relative paths are resolved against the directories listed in sqlite_allowed_directories in the Grafana server configuration."
+        >
+          <Input
+            width={WIDTH_LONG}
+            name="database"
+            value={options.jsonData.database || ''}
+            placeholder="devices.db"
+            onChange={onUpdateDatasourceJsonDataOption(props, 'database')}
+          />
+        </Field>
+      </ConfigSection>
+
+      <Divider />
+
+      <ConfigSection title="Additional settings" isCollapsible isInitiallyOpen={false}>
+        <ConnectionLimits options={options} onOptionsChange={onOptionsChange} />
+      </ConfigSection>
+    </>
+  );
+};
@@ -0,0 +1,112 @@
+import { v4 as uuidv4 } from 'uuid';
+
+import { type DataSourceInstanceSettings, type TimeRange } from '@grafana/data';
+import { type LanguageDefinition } from '@grafana/plugin-ui';
+import {
+  COMMON_FNS,
+  type DB,
+  type FuncParameter,
+  MACRO_FUNCTIONS,
+  type RAQBFieldTypes,
+  type SQLQuery,
+  SQLVariableSupport,
+  SqlDatasource,
+  formatSQL,
+} from '@grafana/sql';
+
+import { buildColumnQuery, buildTableQuery, quoteIdentifierIfNecessary, quoteLiteral, toRawSql } from './sqlUtil';
+import { type SQLiteOptions } from './types';
+
+// A SQLite data source reads a single database file, so there is only the main schema to choose from.
+const mainDataset = 'main';
+
+export class SQLiteDatasource extends SqlDatasource {
+  sqlLanguageDefinition: LanguageDefinition | undefined;
+
+  constructor(instanceSettings: DataSourceInstanceSettings<SQLiteOptions>) {
+    super(instanceSettings);
+    this.variables = new SQLVariableSupport(this);
+  }
+
+  getQueryModel() {
+    return { quoteLiteral };
+  }
+
+  getSqlLanguageDefinition(): LanguageDefinition {
+    if (this.sqlLanguageDefinition !== undefined) {
+      return this.sqlLanguageDefinition;
+    }
+
+    this.sqlLanguageDefinition = {
+      id: 'sqlite',
+      formatter: formatSQL,
+    };
+
+    return this.sqlLanguageDefinition;
+  }
+
+  async fetchTables(): Promise<string[]> {
+    const tables = await this.runSql<string[]>(buildTableQuery(), { refId: 'tables' });
+    return tables.map((t) => quoteIdentifierIfNecessary(t[0]));
+  }
+
+  async fetchFields(query: Partial<SQLQuery>) {
+    if (!query.table) {
+      return [];
+    }
+    const frame = await this.runSql<string[]>(buildColumnQuery(query.table), { refId: `fields-${uuidv4()}` });
+    return frame.map((f) => ({
+      name: f[0],
+      text: f[0],
+      value: quoteIdentifierIfNecessary(f[0]),
+      type: f[1],
+      label: f[0],
+      raqbFieldType: fieldType(f[1]),
+    }));
+  }
+
+  getFunctions = (): ReturnType<DB['functions']> => {
+    const columnParam: FuncParameter = {
+      name: 'Column',
+      required: true,
+      options: (query) => this.fetchFields(query),
+    };
+
+    return [...MACRO_FUNCTIONS(columnParam), ...COMMON_FNS.map((fn) => ({ ...fn, parameters: [columnParam] }))];
+  };
+
+  getDB(): DB {
+    if (this.db !== undefined) {
+      return this.db;
+    }
+
+    return {
+      datasets: () => Promise.resolve([mainDataset]),
+      tables: () => this.fetchTables(),
+      fields: (query: SQLQuery) => this.fetchFields(query),
+      validateQuery: (query: SQLQuery, _range?: TimeRange) =>
+        Promise.resolve({ query, error: '', isError: false, isValid: true }),
+      toRawSql,
+      functions: () => this.getFunctions(),
+      getEditorLanguageDefinition: () => this.getSqlLanguageDefinition(),
+    };
+  }
+}
+
+// fieldType maps a declared column type to a query builder type using SQLite's type affinity rules.
+function fieldType(declared = ''): RAQBFieldTypes {
+  const type = declared.toUpperCase();
+  if (type.includes('DATETIME') || type.includes('TIMESTAMP')) {
+    return 'datetime';
+  }
+  if (type === 'DATE') {
+    return 'date';
+  }
+  if (type.includes('BOOL')) {
+    return 'boolean';
+  }
+  if (type.includes('INT') || type.includes('REAL') || type.includes('FLOA') || type.includes('DOUB')) {
+    return 'number';
+  }
+  return 'text';
+}
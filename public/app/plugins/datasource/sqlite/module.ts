@@ -0,0 +1,10 @@
+import { DataSourcePlugin } from '@grafana/data';
+import { type SQLQuery, SqlQueryEditorLazy } from '@grafana/sql';
+
+import { SQLiteDatasource } from './datasource';
+import { ConfigurationEditor } from './configuration/ConfigurationEditor';
+import { type SQLiteOptions } from './types';
+
+export const plugin = new DataSourcePlugin<SQLiteDatasource, SQLQuery, SQLiteOptions>(SQLiteDatasource)
+  .setQueryEditor(SqlQueryEditorLazy)
+  .setConfigEditor(ConfigurationEditor);
@@ -0,0 +1,58 @@
+import { isEmpty } from 'lodash';
+
+import { type SQLQuery, createSelectClause, haveColumns } from '@grafana/sql';
+
+export function toRawSql({ sql, table }: SQLQuery): string {
+  let rawQuery = '';
+
+  // Return early with empty string if there is no sql column
+  if (!sql || !haveColumns(sql.columns)) {
+    return rawQuery;
+  }
+
+  rawQuery += createSelectClause(sql.columns);
+
+  if (table) {
+    rawQuery += `FROM ${table} `;
+  }
+
+  if (sql.whereString) {
+    rawQuery += `WHERE ${sql.whereString} `;
+  }
+
+  if (sql.groupBy?.[0]?.property.name) {
+    const groupBy = sql.groupBy.map((g) => g.property.name).filter((g) => !isEmpty(g));
+    rawQuery += `GROUP BY ${groupBy.join(', ')} `;
+  }
+
+  if (sql.orderBy?.property.name) {
+    rawQuery += `ORDER BY ${sql.orderBy.property.name} `;
+  }
+
+  if (sql.orderBy?.property.name && sql.orderByDirection) {
+    rawQuery += `${sql.orderByDirection} `;
+  }
+
+  if (sql.limit !== undefined && sql.limit >= 0) {
+    rawQuery += `LIMIT ${sql.limit} `;
+  }
+  return rawQuery;
+}
+
+// Puts double quotes around the identifier if it is necessary.
+export function quoteIdentifierIfNecessary(value: string) {
+  return /^[a-zA-Z_][a-zA-Z0-9_]*$/.test(value) ? value : `"${value.replaceAll('"', '""')}"`;
+}
+
+export function quoteLiteral(value: string) {
+  return "'" + value.replaceAll("'", "''") + "'";
+}
+
+export function buildTableQuery() {
+  return `SELECT name FROM sqlite_master WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name`;
+}
+
+export function buildColumnQuery(table: string) {
+  const unquoted = table.startsWith('"') && table.endsWith('"') ? table.slice(1, -1).replaceAll('""', '"') : table;
+  return `SELECT name, type FROM pragma_table_info(${quoteLiteral(unquoted)}) ORDER BY cid`;
+}
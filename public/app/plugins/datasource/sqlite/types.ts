@@ -0,0 +1,5 @@
+import { type SQLOptions, type SQLQuery } from '@grafana/sql';
+
+export interface SQLiteOptions extends SQLOptions {}
+
+export interface SQLiteQuery extends SQLQuery {}
@@ -23,6 +23,41 @@ describe('QueryField', () => {
     expect(await screen.findByText(/1234/i)).toBeInTheDocument();
     expect(await screen.findByText(/Traces/i)).toBeInTheDocument();
   });
+
+  it('renders the search fields', async () => {
+    const ds = {} as ZipkinDatasource;
+
+    render(
+      <ZipkinQueryField
+        history={[]}
+        datasource={ds}
+        query={{ query: '', queryType: 'search', annotationQuery: 'error' } as ZipkinQuery}
+        onRunQuery={() => {}}
+        onChange={() => {}}
+      />
+    );
+
+    expect(await screen.findByDisplayValue('error')).toBeInTheDocument();
+    expect(screen.getByText('Min duration')).toBeInTheDocument();
+    expect(screen.queryByText('Traces')).not.toBeInTheDocument();
+  });
+
+  it('renders the trace IDs to compare', async () => {
+    const ds = {} as ZipkinDatasource;
+
+    render(
+      <ZipkinQueryField
+        history={[]}
+        datasource={ds}
+        query={{ query: 'abc', compareTraceID: 'def', queryType: 'compare' } as ZipkinQuery}
+        onRunQuery={() => {}}
+        onChange={() => {}}
+      />
+    );
+
+    expect(await screen.findByDisplayValue('abc')).toBeInTheDocument();
+    expect(screen.getByDisplayValue('def')).toBeInTheDocument();
+  });
 });
 
 describe('useServices', () => {
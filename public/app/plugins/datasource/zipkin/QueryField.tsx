@@ -1,6 +1,6 @@
 import { css } from '@emotion/css';
 import { fromPairs } from 'lodash';
-import { useCallback, useEffect, useMemo, useState } from 'react';
+import { type FocusEvent, useCallback, useEffect, useMemo, useState } from 'react';
 import { useAsyncFn, useMount, useMountedState } from 'react-use';
 import { type AsyncState } from 'react-use/lib/useAsyncFn';
 
@@ -12,6 +12,7 @@ import {
   FileDropzone,
   InlineField,
   InlineFieldRow,
+  Input,
   RadioButtonGroup,
   useTheme2,
   QueryField,
@@ -92,7 +93,12 @@ export const ZipkinQueryField = ({ query, onChange, onRunQuery, datasource }: Pr
         <InlineField label="Query type" grow={true}>
           <Stack gap={1} alignItems="center" justifyContent="space-between">
             <RadioButtonGroup<ZipkinQueryType>
-              options={[{ value: 'traceID', label: 'TraceID' }]}
+              options={[
+                { value: 'traceID', label: 'TraceID' },
+                { value: 'search', label: 'Search' },
+                { value: 'compare', label: 'Compare' },
+                { value: 'dependencyGraph', label: 'Dependency graph' },
+              ]}
               value={query.queryType || 'traceID'}
               onChange={(v) =>
                 onChange({
@@ -136,11 +142,114 @@ export const ZipkinQueryField = ({ query, onChange, onRunQuery, datasource }: Pr
           </div>
         </InlineFieldRow>
       )}
+      {query.queryType === 'search' && <SearchFields query={query} onChange={onChange} onRunQuery={onRunQuery} />}
+      {query.queryType === 'compare' && <CompareFields query={query} onChange={onChange} onRunQuery={onRunQuery} />}
       {alertText && <TemporaryAlert text={alertText} severity={'error'} />}
     </>
   );
 };
 
+type FieldsProps = Pick<Props, 'query' | 'onChange' | 'onRunQuery'>;
+
+const SearchFields = ({ query, onChange, onRunQuery }: FieldsProps) => {
+  const onBlur = (key: 'serviceName' | 'spanName' | 'annotationQuery' | 'minDuration' | 'maxDuration') => {
+    return (e: FocusEvent<HTMLInputElement>) => {
+      onChange({ ...query, [key]: e.currentTarget.value.trim() || undefined });
+      onRunQuery();
+    };
+  };
+
+  return (
+    <>
+      <InlineFieldRow>
+        <InlineField label="Service" labelWidth={14}>
+          <Input
+            defaultValue={query.serviceName}
+            placeholder="all services"
+            onBlur={onBlur('serviceName')}
+            width={25}
+          />
+        </InlineField>
+        <InlineField label="Span name" labelWidth={14}>
+          <Input defaultValue={query.spanName} placeholder="all spans" onBlur={onBlur('spanName')} width={25} />
+        </InlineField>
+      </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField
+          label="Tags"
+          labelWidth={14}
+          grow
+          tooltip="Zipkin annotation query, for example: error and http.method=GET"
+        >
+          <Input
+            defaultValue={query.annotationQuery}
+            placeholder="http.path=/api and error"
+            onBlur={onBlur('annotationQuery')}
+          />
+        </InlineField>
+      </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField label="Min duration" labelWidth={14}>
+          <Input
+            defaultValue={query.minDuration}
+            placeholder="e.g. 1.2s, 100ms"
+            onBlur={onBlur('minDuration')}
+            width={25}
+          />
+        </InlineField>
+        <InlineField label="Max duration" labelWidth={14}>
+          <Input
+            defaultValue={query.maxDuration}
+            placeholder="e.g. 1.2s, 100ms"
+            onBlur={onBlur('maxDuration')}
+            width={25}
+          />
+        </InlineField>
+        <InlineField label="Limit" labelWidth={14} tooltip="Maximum number of traces to return">
+          <Input
+            type="number"
+            defaultValue={query.limit}
+            placeholder="20"
+            onBlur={(e) => {
+              const limit = parseInt(e.currentTarget.value, 10);
+              onChange({ ...query, limit: Number.isNaN(limit) ? undefined : limit });
+              onRunQuery();
+            }}
+            width={10}
+          />
+        </InlineField>
+      </InlineFieldRow>
+    </>
+  );
+};
+
+const CompareFields = ({ query, onChange, onRunQuery }: FieldsProps) => {
+  return (
+    <InlineFieldRow>
+      <InlineField label="Trace A" labelWidth={14} grow>
+        <Input
+          defaultValue={query.query}
+          placeholder="Trace ID"
+          onBlur={(e) => {
+            onChange({ ...query, query: e.currentTarget.value.trim() });
+            onRunQuery();
+          }}
+        />
+      </InlineField>
+      <InlineField label="Trace B" labelWidth={14} grow>
+        <Input
+          defaultValue={query.compareTraceID}
+          placeholder="Trace ID"
+          onBlur={(e) => {
+            onChange({ ...query, compareTraceID: e.currentTarget.value.trim() });
+            onRunQuery();
+          }}
+        />
+      </InlineField>
+    </InlineFieldRow>
+  );
+};
+
 // Exported for tests
 export function useServices(
   datasource: ZipkinDatasource,
@@ -49,6 +49,45 @@ describe('ZipkinDatasource', () => {
       });
     });
 
+    it('runs search queries without a trace ID', async () => {
+      const fetch = jest.fn().mockReturnValue(of({ data: { results: { A: { frames: [] } } } }));
+      setBackendSrv({ ...origBackendSrv, fetch });
+
+      await lastValueFrom(
+        ds.query({
+          targets: [{ refId: 'A', query: '', queryType: 'search', serviceName: 'frontend' }],
+        } as unknown as DataQueryRequest<ZipkinQuery>)
+      );
+      expect(fetch).toHaveBeenCalledTimes(1);
+      expect(fetch.mock.calls[0][0].data.queries[0]).toMatchObject({ queryType: 'search', serviceName: 'frontend' });
+    });
+
+    it('runs dependency graph queries without a trace ID', async () => {
+      const fetch = jest.fn().mockReturnValue(of({ data: { results: { A: { frames: [] } } } }));
+      setBackendSrv({ ...origBackendSrv, fetch });
+
+      await lastValueFrom(
+        ds.query({
+          targets: [{ refId: 'A', query: '', queryType: 'dependencyGraph' }],
+        } as unknown as DataQueryRequest<ZipkinQuery>)
+      );
+      expect(fetch).toHaveBeenCalledTimes(1);
+      expect(fetch.mock.calls[0][0].data.queries[0]).toMatchObject({ queryType: 'dependencyGraph' });
+    });
+
+    it('does not run compare queries without both trace IDs', async () => {
+      const fetch = jest.fn();
+      setBackendSrv({ ...origBackendSrv, fetch });
+
+      const response = await lastValueFrom(
+        ds.query({
+          targets: [{ refId: 'A', query: 'abc', queryType: 'compare' }],
+        } as unknown as DataQueryRequest<ZipkinQuery>)
+      );
+      expect(fetch).not.toHaveBeenCalled();
+      expect(response.data).toEqual([]);
+    });
+
     it('should handle json file upload', async () => {
       ds.uploadedJson = JSON.stringify(mockJson);
       const response = await lastValueFrom(
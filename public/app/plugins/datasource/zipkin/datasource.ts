@@ -49,6 +49,14 @@ export class ZipkinDatasource extends DataSourceWithBackend<ZipkinQuery, ZipkinJ
       }
     }
 
+    if (target.queryType === 'search' || target.queryType === 'dependencyGraph') {
+      return super.query(options);
+    }
+
+    if (target.queryType === 'compare') {
+      return target.query && target.compareTraceID ? super.query(options) : of({ data: [] });
+    }
+
     if (target.query) {
       return super.query(options).pipe(
         map((response) => {
@@ -71,6 +79,15 @@ export class ZipkinDatasource extends DataSourceWithBackend<ZipkinQuery, ZipkinJ
   }
 
   getQueryDisplayText(query: ZipkinQuery): string {
+    if (query.queryType === 'search') {
+      return [query.serviceName, query.spanName, query.annotationQuery].filter(Boolean).join(' ');
+    }
+    if (query.queryType === 'compare') {
+      return `${query.query} vs ${query.compareTraceID ?? ''}`;
+    }
+    if (query.queryType === 'dependencyGraph') {
+      return 'Dependency graph';
+    }
     return query.query;
   }
 
@@ -94,6 +111,10 @@ export class ZipkinDatasource extends DataSourceWithBackend<ZipkinQuery, ZipkinJ
     return {
       ...expandedQuery,
       query: this.templateSrv.replace(query.query ?? '', scopedVars),
+      ...(query.serviceName && { serviceName: this.templateSrv.replace(query.serviceName, scopedVars) }),
+      ...(query.spanName && { spanName: this.templateSrv.replace(query.spanName, scopedVars) }),
+      ...(query.annotationQuery && { annotationQuery: this.templateSrv.replace(query.annotationQuery, scopedVars) }),
+      ...(query.compareTraceID && { compareTraceID: this.templateSrv.replace(query.compareTraceID, scopedVars) }),
     };
   }
 }
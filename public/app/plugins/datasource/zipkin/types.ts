@@ -26,9 +26,20 @@ export type ZipkinAnnotation = {
   timestamp: number;
   value: string;
 };
-export type ZipkinQueryType = 'traceID' | 'upload';
+export type ZipkinQueryType = 'traceID' | 'search' | 'compare' | 'dependencyGraph' | 'upload';
 
 export interface ZipkinQuery extends DataQuery {
   query: string;
   queryType?: ZipkinQueryType;
+
+  // Search queries
+  serviceName?: string;
+  spanName?: string;
+  annotationQuery?: string;
+  minDuration?: string;
+  maxDuration?: string;
+  limit?: number;
+
+  // Compare queries, comparing the trace in query with this one
+  compareTraceID?: string;
 }